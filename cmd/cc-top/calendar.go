@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/ics"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+// calendarAlertLength is how long an alert is shown on the calendar, so it
+// is visible on a day view.
+const calendarAlertLength = 15 * time.Minute
+
+// runCalendar implements `cc-top calendar`: it writes fired alerts and daily
+// cost summaries to an iCalendar file, to import or subscribe to.
+func runCalendar(args []string) int {
+	fs := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top calendar [--since <date>] [--until <date>] [--severity critical|warning|all] [--no-daily] [--out <file>] [--db <path>]\n\n")
+		fs.PrintDefaults()
+	}
+	sinceFlag := fs.String("since", "", "First day to include (YYYY-MM-DD, local; default 30 days ago)")
+	untilFlag := fs.String("until", "", "Last day to include (YYYY-MM-DD, local; default today)")
+	severityFlag := fs.String("severity", alerts.SeverityCritical, "Lowest alert severity to include: critical, warning or all")
+	noDailyFlag := fs.Bool("no-daily", false, "Leave out the daily cost summaries")
+	outFlag := fs.String("out", "cc-top.ics", `File to write ("-" for stdout)`)
+	dbFlag := fs.String("db", "", "Database to read (default: storage.db_path from config)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	today := time.Now()
+	y, m, d := today.Date()
+	until := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	since := until.AddDate(0, 0, -30)
+	for _, f := range []struct {
+		value string
+		day   *time.Time
+	}{{*sinceFlag, &since}, {*untilFlag, &until}} {
+		if f.value == "" {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", f.value, time.Local)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: invalid date %q (want YYYY-MM-DD)\n", f.value)
+			return 2
+		}
+		*f.day = day
+	}
+	severities := map[string][]string{
+		alerts.SeverityCritical: {alerts.SeverityCritical},
+		alerts.SeverityWarning:  {alerts.SeverityCritical, alerts.SeverityWarning},
+		"all":                   {alerts.SeverityCritical, alerts.SeverityWarning, alerts.SeverityInfo},
+	}
+	include, ok := severities[*severityFlag]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "cc-top: invalid severity %q (want critical, warning or all)\n", *severityFlag)
+		return 2
+	}
+
+	dbPath, err := localDBPath(*dbFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+	history, err := storage.ReadAlertHistory(dbPath, since, until.AddDate(0, 0, 1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: calendar: %v\n", err)
+		return 1
+	}
+	var days []storage.DailyStatsRow
+	if !*noDailyFlag {
+		if days, err = storage.ReadDailyStats(dbPath, since.Format("2006-01-02"), until.Format("2006-01-02")); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: calendar: %v\n", err)
+			return 1
+		}
+	}
+
+	var events []ics.Event
+	nAlerts := 0
+	for _, r := range history {
+		firedAt, err := time.Parse(time.RFC3339, r.FiredAt)
+		if err != nil || !slices.Contains(include, r.Severity) {
+			continue
+		}
+		events = append(events, alertCalendarEvent(r, firedAt))
+		nAlerts++
+	}
+	for _, r := range days {
+		if e, ok := dailyCalendarEvent(r); ok {
+			events = append(events, e)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ics.Write(&buf, "cc-top", events, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: calendar: %v\n", err)
+		return 1
+	}
+	if *outFlag == "-" {
+		_, _ = os.Stdout.Write(buf.Bytes())
+		return 0
+	}
+	if err := os.WriteFile(*outFlag, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: calendar: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %d alerts and %d daily summaries from %s to %s to %s.\n",
+		nAlerts, len(events)-nAlerts, since.Format("2006-01-02"), until.Format("2006-01-02"), *outFlag)
+	return 0
+}
+
+// alertCalendarEvent is a fired alert as a short event at its firing time.
+func alertCalendarEvent(r storage.AlertHistoryRow, firedAt time.Time) ics.Event {
+	desc := []string{r.Message}
+	if r.SessionID != "" {
+		desc = append(desc, "Session: "+r.SessionID)
+	}
+	if r.Note != "" {
+		desc = append(desc, "Note: "+r.Note)
+	}
+	return ics.Event{
+		UID:         fmt.Sprintf("alert-%d@cc-top", r.ID),
+		Summary:     fmt.Sprintf("cc-top %s: %s", r.Severity, r.Rule),
+		Description: strings.Join(desc, "\n"),
+		Start:       firedAt,
+		End:         firedAt.Add(calendarAlertLength),
+	}
+}
+
+// dailyCalendarEvent is a day's cost summary as an all-day event. Days
+// without sessions are left out.
+func dailyCalendarEvent(r storage.DailyStatsRow) (ics.Event, bool) {
+	day, err := time.ParseInLocation("2006-01-02", r.Date, time.Local)
+	if err != nil || (r.SessionCount == 0 && r.TotalCost == 0) {
+		return ics.Event{}, false
+	}
+	return ics.Event{
+		UID:     "day-" + r.Date + "@cc-top",
+		Summary: fmt.Sprintf("cc-top $%.2f, %d sessions", r.TotalCost, r.SessionCount),
+		Description: fmt.Sprintf("Tokens: %d in, %d out, %d cache read\nAPI requests: %d (%d errors)\nLines: +%d -%d, %d commits",
+			r.TokenInput, r.TokenOutput, r.TokenCacheRead, r.APIRequests, r.APIErrors, r.LinesAdded, r.LinesRemoved, r.Commits),
+		Start:  day,
+		AllDay: true,
+	}, true
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/scanner"
+	"github.com/nixlim/cc-top/internal/settings"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+const (
+	// clockSkewTolerance is how far this machine's clock may drift from the
+	// reference, or telemetry timestamps run ahead of it, before doctor
+	// reports it.
+	clockSkewTolerance = 30 * time.Second
+
+	// clockReferenceURL is asked for its Date header to measure the local
+	// clock. Claude Code talks to it anyway.
+	clockReferenceURL = "https://api.anthropic.com/"
+
+	// doctorProbeTimeout bounds each network probe.
+	doctorProbeTimeout = 3 * time.Second
+)
+
+// doctorReport prints check results and remembers whether any failed.
+type doctorReport struct {
+	failed bool
+}
+
+func (r *doctorReport) section(name string) { fmt.Printf("\n%s\n", name) }
+
+func (r *doctorReport) ok(format string, a ...any) {
+	fmt.Printf("  ok    %s\n", fmt.Sprintf(format, a...))
+}
+
+func (r *doctorReport) warn(msg, fix string) { r.print("WARN", msg, fix) }
+
+func (r *doctorReport) fail(msg, fix string) {
+	r.failed = true
+	r.print("FAIL", msg, fix)
+}
+
+func (r *doctorReport) print(tag, msg, fix string) {
+	fmt.Printf("  %-5s %s\n", tag, msg)
+	if fix != "" {
+		fmt.Printf("        fix: %s\n", fix)
+	}
+}
+
+// runDoctor implements `cc-top doctor`: it checks the things that make
+// telemetry silently not arrive — the receiver ports, Claude Code's
+// settings, the database, process inspection and the clock — and prints a
+// fix for each problem. It exits 1 if any check failed.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top doctor [--settings <path>] [--db <path>] [--offline]\n\n")
+		fs.PrintDefaults()
+	}
+	settingsFlag := fs.String("settings", "", "Claude Code settings file (default: ~/.claude/settings.json)")
+	dbFlag := fs.String("db", "", "Database to inspect (default: storage.db_path from config)")
+	offlineFlag := fs.Bool("offline", false, "Skip the clock check against "+clockReferenceURL)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	loadResult, err := config.Load()
+	if err != nil {
+		fmt.Printf("FAIL  config: %v\n", err)
+		fmt.Printf("      fix: correct %s\n", config.DefaultPath())
+		return 1
+	}
+	cfg := loadResult.Config
+	r := &doctorReport{}
+
+	r.section("Config")
+	r.ok("loaded %s", config.DefaultPath())
+	for _, w := range loadResult.Warnings {
+		r.warn(w, "")
+	}
+
+	r.section("Receiver")
+	doctorPorts(r, cfg.Receiver)
+
+	r.section("Claude Code settings")
+	settingsPath := pathutil.ExpandHome(*settingsFlag)
+	if settingsPath == "" {
+		settingsPath = settings.DefaultPath()
+	}
+	doctorSettings(r, settingsPath, cfg.Receiver.GRPCPort)
+
+	r.section("Database")
+	dbPath := pathutil.ExpandHome(*dbFlag)
+	if dbPath == "" {
+		dbPath = pathutil.ExpandHome(cfg.Storage.DBPath)
+	}
+	latest := doctorDatabase(r, dbPath)
+
+	r.section("Process scanner")
+	doctorScanner(r, cfg)
+
+	r.section("Clock")
+	doctorClock(r, latest, *offlineFlag)
+
+	fmt.Println()
+	if r.failed {
+		fmt.Println("Some checks failed; apply the fixes above and run cc-top doctor again.")
+		return 1
+	}
+	fmt.Println("No problems found. To test ingest end to end, run cc-top send-test.")
+	return 0
+}
+
+// doctorPorts checks that cc-top, and not another collector, listens on
+// the receiver ports.
+func doctorPorts(r *doctorReport, rc config.ReceiverConfig) {
+	httpOwner := portOwner(rc, rc.HTTPPort, true)
+	grpcOwner := portOwner(rc, rc.GRPCPort, false)
+	if grpcOwner == "in use" {
+		// gRPC can't be told apart cheaply. cc-top listens on both ports,
+		// so trust the HTTP port's answer.
+		switch httpOwner {
+		case "cc-top":
+			grpcOwner = "cc-top"
+		case "free", "another program":
+			grpcOwner = "another program"
+		}
+	}
+
+	for _, p := range []struct {
+		name, owner string
+		port        int
+		key         string
+	}{
+		{"gRPC", grpcOwner, rc.GRPCPort, "grpc_port"},
+		{"HTTP", httpOwner, rc.HTTPPort, "http_port"},
+	} {
+		addr := net.JoinHostPort(receiverHost(rc.Bind), strconv.Itoa(p.port))
+		switch p.owner {
+		case "cc-top":
+			r.ok("%s %s: cc-top is listening", p.name, addr)
+		case "free":
+			r.warn(fmt.Sprintf("%s %s: nothing is listening, so telemetry sent now is lost", p.name, addr),
+				"start cc-top (or cc-top -headless) before Claude Code sessions")
+		case "in use":
+			r.ok("%s %s: in use, presumably by cc-top", p.name, addr)
+		case "another program":
+			r.fail(fmt.Sprintf("%s %s: in use by another program, perhaps another OTLP collector", p.name, addr),
+				fmt.Sprintf("stop it, or set [receiver] %s to a free port and run cc-top -setup", p.key))
+		default:
+			r.fail(fmt.Sprintf("%s %s: %s", p.name, addr, p.owner),
+				fmt.Sprintf("check [receiver] bind and %s", p.key))
+		}
+	}
+}
+
+// portOwner reports who holds port: "free", "cc-top", "another program",
+// "in use" when that can't be told, or why the port can't be bound. Only
+// cc-top's HTTP receiver serves /v1/annotations, which identifies it; the
+// probe carries the auth token, as the receiver rejects requests without
+// it. With [receiver.tls] the probe uses https without verifying the
+// certificate, and with a client CA it can't get in, so it reports "in use".
+func portOwner(rc config.ReceiverConfig, port int, isHTTP bool) string {
+	bind, token := rc.Bind, rc.AuthToken
+	lis, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(port)))
+	if err == nil {
+		_ = lis.Close()
+		return "free"
+	}
+	if !isAddrInUse(err) {
+		return err.Error()
+	}
+	if !isHTTP || rc.TLS.ClientCAFile != "" {
+		return "in use"
+	}
+
+	client := &http.Client{Timeout: doctorProbeTimeout}
+	scheme := "http://"
+	if rc.TLS.Enabled() {
+		scheme = "https://"
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	req, err := http.NewRequest(http.MethodGet, scheme+net.JoinHostPort(receiverHost(bind), strconv.Itoa(port))+"/v1/annotations", nil)
+	if err != nil {
+		return err.Error()
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "another program"
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		return "cc-top"
+	}
+	return "another program"
+}
+
+// isAddrInUse reports whether err is a listen error for a taken address.
+func isAddrInUse(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if errors.As(opErr.Err, &sysErr) {
+		// EADDRINUSE, or WSAEADDRINUSE on Windows.
+		return sysErr.Syscall == "bind"
+	}
+	return false
+}
+
+// doctorSettings checks the OTel variables in Claude Code's settings.
+func doctorSettings(r *doctorReport, path string, grpcPort int) {
+	issues, err := settings.CheckOTelEnv(path, grpcPort)
+	if err != nil {
+		r.fail(err.Error(), "fix the file by hand, then run cc-top -setup")
+		return
+	}
+	if len(issues) == 0 {
+		r.ok("%s sets every telemetry variable", path)
+		return
+	}
+	for _, is := range issues {
+		if is.Missing {
+			r.fail(fmt.Sprintf("%s is not set (want %s)", is.Key, is.Want), "")
+		} else {
+			r.fail(fmt.Sprintf("%s is %q (want %q)", is.Key, is.Got, is.Want), "")
+		}
+	}
+	fmt.Printf("        fix: run cc-top -setup to update %s, then restart Claude Code sessions\n", path)
+}
+
+// doctorDatabase checks the database and returns its newest telemetry
+// timestamp, zero if unknown.
+func doctorDatabase(r *doctorReport, dbPath string) time.Time {
+	if dbPath == "" {
+		r.ok("persistence is disabled (storage.db_path is empty); nothing to check")
+		return time.Time{}
+	}
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) {
+		r.warn(dbPath+" does not exist yet", "start cc-top once; it creates the database")
+		return time.Time{}
+	}
+	h, err := storage.InspectDB(dbPath)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s: %v", dbPath, err), "check the file's permissions, or move it aside to start a fresh database")
+		return time.Time{}
+	}
+
+	r.ok("%s, %s", dbPath, formatBytes(h.SizeBytes))
+	switch {
+	case h.SchemaVersion > storage.SchemaVersion:
+		r.fail(fmt.Sprintf("schema v%d is newer than this cc-top supports (v%d)", h.SchemaVersion, storage.SchemaVersion),
+			"upgrade cc-top, or point storage.db_path at another file")
+	case h.SchemaVersion < storage.SchemaVersion:
+		r.warn(fmt.Sprintf("schema v%d; cc-top migrates it to v%d on next start", h.SchemaVersion, storage.SchemaVersion), "")
+	default:
+		r.ok("schema v%d", h.SchemaVersion)
+	}
+	if h.Integrity != "ok" {
+		r.fail("integrity check: "+h.Integrity, "stop cc-top and restore the database from a backup, or move it aside")
+	} else {
+		r.ok("integrity check passed")
+	}
+	if h.LatestTelemetry.IsZero() {
+		r.warn("no telemetry stored yet", "run cc-top send-test to test ingest")
+	} else {
+		r.ok("newest telemetry at %s", h.LatestTelemetry.Local().Format("2006-01-02 15:04:05"))
+	}
+	return h.LatestTelemetry
+}
+
+// doctorScanner checks that processes can be listed and that the Claude
+// Code processes it finds have telemetry on.
+func doctorScanner(r *doctorReport, cfg config.Config) {
+	s := scanner.NewDefaultScanner(cfg.Scanner.IntervalSeconds)
+	pids, err := s.API().ListAllPIDs()
+	switch {
+	case err != nil:
+		r.fail("listing processes: "+err.Error(), "run cc-top as the user that runs Claude Code")
+	case len(pids) == 0:
+		r.warn(fmt.Sprintf("process inspection is not supported on %s; only pgrep finds Claude Code", runtime.GOOS),
+			"rely on settings.json for telemetry; the dashboard can't check individual sessions")
+	default:
+		r.ok("%d processes visible", len(pids))
+	}
+
+	procs := s.Scan()
+	if len(procs) == 0 {
+		r.ok("no Claude Code processes running")
+		return
+	}
+	for _, p := range procs {
+		if !p.EnvReadable {
+			r.warn(fmt.Sprintf("PID %d: environment unreadable, telemetry status unknown", p.PID),
+				"run cc-top as the same user as Claude Code")
+			continue
+		}
+		st := scanner.ClassifyTelemetry(p, cfg.Receiver.GRPCPort, false)
+		switch st.Status {
+		case scanner.TelemetryOff, scanner.TelemetryConsoleOnly, scanner.TelemetryWrongPort:
+			r.fail(fmt.Sprintf("PID %d (%s): telemetry %s", p.PID, p.CWD, st.Label),
+				"run cc-top -setup if settings.json is wrong, then restart this session")
+		default:
+			r.ok("PID %d (%s): telemetry configured", p.PID, p.CWD)
+		}
+	}
+}
+
+// doctorClock checks this machine's clock against clockReferenceURL and
+// that stored telemetry isn't timestamped in the future, which happens
+// when Claude Code runs on a machine whose clock is ahead.
+func doctorClock(r *doctorReport, latest time.Time, offline bool) {
+	if ahead := time.Until(latest); !latest.IsZero() && ahead > clockSkewTolerance {
+		r.fail(fmt.Sprintf("newest telemetry is %s in the future", ahead.Round(time.Second)),
+			"sync the clock (NTP) of the machine running Claude Code, or of this one")
+	}
+	if offline {
+		r.ok("reference check skipped (--offline)")
+		return
+	}
+	skew, err := clockSkew(clockReferenceURL)
+	if err != nil {
+		r.warn("could not reach "+clockReferenceURL+": "+err.Error(), "")
+		return
+	}
+	if skew > clockSkewTolerance || skew < -clockSkewTolerance {
+		r.fail(fmt.Sprintf("this clock is %s off", skew.Round(time.Second)),
+			"enable time sync (NTP); skew shifts burn rates and daily totals")
+		return
+	}
+	r.ok("within %s of %s", clockSkewTolerance, clockReferenceURL)
+}
+
+// clockSkew returns how far the local clock is ahead of url's Date header.
+func clockSkew(url string) (time.Duration, error) {
+	client := &http.Client{Timeout: doctorProbeTimeout}
+	start := time.Now()
+	resp, err := client.Head(url)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	remote, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("no usable Date header")
+	}
+	local := start.Add(time.Since(start) / 2)
+	return local.Sub(remote), nil
+}
+
+// formatBytes formats n as a human-readable size, e.g. "12.3 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
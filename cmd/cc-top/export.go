@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/gitlog"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+// runExport implements `cc-top export`: it writes history tables from the
+// database to JSON or CSV files for offline analysis.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top export [--format json|csv] [--out <dir>] [--tables <list>] [--since <date>] [--until <date>] [--session <id,...>] [--commits] [--db <path>]\n\n")
+		fs.PrintDefaults()
+	}
+	formatFlag := fs.String("format", "json", "Output format: json or csv")
+	outFlag := fs.String("out", ".", "Directory to write <table>.<format> files to")
+	tablesFlag := fs.String("tables", strings.Join(storage.ExportTables, ","), "Comma-separated tables to export")
+	sinceFlag := fs.String("since", "", "Only export data from this date on (YYYY-MM-DD, UTC)")
+	untilFlag := fs.String("until", "", "Only export data up to and including this date (YYYY-MM-DD, UTC)")
+	sessionFlag := fs.String("session", "", "Comma-separated session IDs to export")
+	commitsFlag := fs.Bool("commits", false, "Also write commits.<format>: the git commits of each session with its cost split between them (runs git log)")
+	dbFlag := fs.String("db", "", "Database to export (default: storage.db_path from config)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	for _, d := range []string{*sinceFlag, *untilFlag} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: invalid date %q (want YYYY-MM-DD)\n", d)
+			return 2
+		}
+	}
+
+	dbPath, err := localDBPath(*dbFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+
+	opts := storage.ExportOptions{
+		Format:     *formatFlag,
+		Dir:        *outFlag,
+		Tables:     splitList(*tablesFlag),
+		Since:      *sinceFlag,
+		Until:      *untilFlag,
+		SessionIDs: splitList(*sessionFlag),
+	}
+	counts, err := storage.ExportDB(dbPath, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: export: %v\n", err)
+		return 1
+	}
+
+	tables := opts.Tables
+	if len(tables) == 0 {
+		tables = storage.ExportTables
+	}
+	fmt.Printf("Exported %s to %s:\n", dbPath, *outFlag)
+	for _, t := range tables {
+		fmt.Printf("  %-13s %d rows -> %s.%s\n", t+":", counts[t], t, opts.Format)
+	}
+	if *commitsFlag {
+		n, err := storage.ExportCommits(dbPath, opts, gitlog.ExecRunner)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: export commits: %v\n", err)
+			return 1
+		}
+		fmt.Printf("  %-13s %d rows -> commits.%s\n", "commits:", n, opts.Format)
+	}
+	return 0
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/control"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// headlessStatusInterval is how often a headless instance logs its status.
+const headlessStatusInterval = time.Minute
+
+// defaultControlSocket returns the control socket path used when -socket is
+// not given.
+func defaultControlSocket() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "cc-top.sock")
+	}
+	return filepath.Join(home, ".local", "share", "cc-top", "cc-top.sock")
+}
+
+// daemonStatus is the reply to the "status" control command and the content
+// of the periodic status log line.
+type daemonStatus struct {
+	Uptime        string  `json:"uptime"`
+	Sessions      int     `json:"sessions"`
+	ActiveSession int     `json:"active_sessions"`
+	TotalCost     float64 `json:"total_cost"`
+	HourlyRate    float64 `json:"hourly_rate"`
+	Alerts        int     `json:"alerts"`
+	Persistent    bool    `json:"persistent"`
+	DroppedWrites int64   `json:"dropped_writes"`
+	GRPCPort      int     `json:"grpc_port"`
+	HTTPPort      int     `json:"http_port"`
+}
+
+// daemonSession is one entry of the "sessions" control command reply.
+type daemonSession struct {
+	SessionID   string    `json:"session_id"`
+	PID         int       `json:"pid,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	CWD         string    `json:"cwd,omitempty"`
+	Status      string    `json:"status"`
+	TotalCost   float64   `json:"total_cost"`
+	TotalTokens int64     `json:"total_tokens"`
+	LastEventAt time.Time `json:"last_event_at"`
+}
+
+// daemon holds what a headless instance reports over logs and the control
+// socket.
+type daemon struct {
+	cfg        config.Config
+	store      state.Store
+	brCalc     *burnrate.Calculator
+	engine     *alerts.Engine
+	slaTimers  *alerts.SLATimers
+	persistent bool
+	started    time.Time
+}
+
+func (d *daemon) status() daemonStatus {
+	sessions := d.store.ListSessions()
+	active := 0
+	for i := range sessions {
+		if sessions[i].Status() == state.StatusActive {
+			active++
+		}
+	}
+	br := d.brCalc.Compute(d.store)
+	return daemonStatus{
+		Uptime:        time.Since(d.started).Round(time.Second).String(),
+		Sessions:      len(sessions),
+		ActiveSession: active,
+		TotalCost:     d.store.GetAggregatedCost(),
+		HourlyRate:    br.HourlyRate,
+		Alerts:        len(d.engine.Alerts()),
+		Persistent:    d.persistent,
+		DroppedWrites: d.store.DroppedWrites(),
+		GRPCPort:      d.cfg.Receiver.GRPCPort,
+		HTTPPort:      d.cfg.Receiver.HTTPPort,
+	}
+}
+
+func (d *daemon) sessions() []daemonSession {
+	sessions := d.store.ListSessions()
+	result := make([]daemonSession, len(sessions))
+	for i := range sessions {
+		s := &sessions[i]
+		result[i] = daemonSession{
+			SessionID:   s.SessionID,
+			PID:         s.PID,
+			Model:       s.Model,
+			CWD:         s.CWD,
+			Status:      string(s.Status()),
+			TotalCost:   s.TotalCost,
+			TotalTokens: s.TotalTokens,
+			LastEventAt: s.LastEventAt,
+		}
+	}
+	return result
+}
+
+// setSLA handles "sla <session-id> <duration>", setting the session's
+// expected duration. A duration of 0 clears it.
+func (d *daemon) setSLA(args []string) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("usage: sla <session-id> <duration>")
+	}
+	if d.store.GetSession(args[0]) == nil {
+		return nil, fmt.Errorf("unknown session %q", args[0])
+	}
+	var dur time.Duration
+	if args[1] != "0" {
+		var err error
+		if dur, err = time.ParseDuration(args[1]); err != nil || dur <= 0 {
+			return nil, fmt.Errorf("invalid duration %q", args[1])
+		}
+	}
+	d.slaTimers.SetExpectedDuration(args[0], dur)
+	return fmt.Sprintf("expected duration of %s set to %s", args[0], dur), nil
+}
+
+// logStatus writes a one-line status summary to the log.
+func (d *daemon) logStatus() {
+	st := d.status()
+	log.Printf("status: %d session(s), %d active, $%.2f total, $%.2f/hr, %d alert(s), %d dropped write(s)",
+		st.Sessions, st.ActiveSession, st.TotalCost, st.HourlyRate, st.Alerts, st.DroppedWrites)
+}
+
+// logNotifier logs every alert before passing it on to the next notifier.
+type logNotifier struct {
+	next alerts.Notifier
+}
+
+func (n logNotifier) Notify(a alerts.Alert) {
+	session := a.SessionID
+	if session == "" {
+		session = "global"
+	}
+	log.Printf("alert: [%s] %s (%s): %s", a.Severity, a.Rule, session, a.Message)
+	if n.next != nil {
+		n.next.Notify(a)
+	}
+}
+
+// runHeadless serves the control socket and logs status until a signal
+// arrives or a client sends "stop", then runs shutdown.
+func runHeadless(ctx context.Context, d *daemon, socketPath string, sigCh <-chan os.Signal, shutdown func()) error {
+	stopCh := make(chan struct{}, 1)
+
+	srv := control.NewServer(socketPath)
+	srv.Handle("status", func([]string) (any, error) { return d.status(), nil })
+	srv.Handle("sessions", func([]string) (any, error) { return d.sessions(), nil })
+	srv.Handle("alerts", func([]string) (any, error) { return d.engine.Alerts(), nil })
+	srv.Handle("sla", d.setSLA)
+	srv.Handle("stop", func([]string) (any, error) {
+		select {
+		case stopCh <- struct{}{}:
+		default:
+		}
+		return "stopping", nil
+	})
+
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o755); err != nil {
+		return fmt.Errorf("creating control socket directory: %w", err)
+	}
+	if err := srv.Start(ctx); err != nil {
+		return err
+	}
+	log.Printf("headless: receivers on gRPC :%d and HTTP :%d, control socket %s",
+		d.cfg.Receiver.GRPCPort, d.cfg.Receiver.HTTPPort, socketPath)
+
+	ticker := time.NewTicker(headlessStatusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.logStatus()
+		case sig := <-sigCh:
+			log.Printf("headless: received %s, shutting down", sig)
+			srv.Stop()
+			shutdown()
+			return nil
+		case <-stopCh:
+			log.Printf("headless: stop requested over control socket, shutting down")
+			srv.Stop()
+			shutdown()
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			shutdown()
+			return nil
+		}
+	}
+}
+
+// runControl sends command to the headless instance listening on
+// socketPath and prints its reply. It returns the process exit code.
+func runControl(socketPath, command string) int {
+	reply, err := control.Send(socketPath, command)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+	if !reply.OK {
+		fmt.Fprintf(os.Stderr, "cc-top: %s\n", reply.Error)
+		return 1
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, reply.Result, "", "  "); err != nil {
+		out.Reset()
+		out.Write(reply.Result)
+	}
+	fmt.Println(out.String())
+	return 0
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/receiver"
+	"github.com/nixlim/cc-top/internal/state"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+// runImport implements `cc-top import`: it merges other cc-top databases,
+// e.g. copied from a laptop, into the local one, and ingests the metrics and
+// events of `-debug` telemetry logs (.jsonl).
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top import [--db <path>] [--dry-run] <other.db|debug.jsonl>...\n\n")
+		fs.PrintDefaults()
+	}
+	dbFlag := fs.String("db", "", "Local database to import into (default: storage.db_path from config)")
+	dryRunFlag := fs.Bool("dry-run", false, "Report what would be imported without writing")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	dbPath, err := localDBPath(*dbFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+
+	for _, other := range fs.Args() {
+		if strings.HasSuffix(other, ".jsonl") {
+			if err := importLog(dbPath, pathutil.ExpandHome(other), *dryRunFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "cc-top: import %s: %v\n", other, err)
+				return 1
+			}
+			continue
+		}
+		result, err := storage.ImportDB(dbPath, pathutil.ExpandHome(other), *dryRunFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: import %s: %v\n", other, err)
+			return 1
+		}
+		if *dryRunFlag {
+			fmt.Print("Dry run, nothing written. ")
+		}
+		printMergeResult(other, dbPath, result)
+	}
+	return 0
+}
+
+// importLog ingests the metrics and events of a debug log into the database
+// at dbPath with storage.BulkIngest. It refuses when config marks the
+// database read-only, since another instance owns the writes.
+func importLog(dbPath, logPath string, dryRun bool) error {
+	bulk, metrics, events, err := readLogRecords(logPath)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		loadResult, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("config error: %w", err)
+		}
+		cfg := loadResult.Config
+		if cfg.Storage.ReadOnly {
+			return fmt.Errorf("storage.read_only is set; import on the instance that writes %s", dbPath)
+		}
+		store, err := storage.NewSQLiteStore(dbPath, cfg.Storage.RetentionDays, cfg.Storage.SummaryRetentionDays,
+			storage.WithTuning(storage.TuningFromConfig(cfg.Storage)))
+		if err != nil {
+			return err
+		}
+		ingestErr := store.BulkIngest(bulk)
+		if err := store.Close(); err != nil && ingestErr == nil {
+			ingestErr = err
+		}
+		if ingestErr != nil {
+			return ingestErr
+		}
+	} else {
+		fmt.Print("Dry run, nothing written. ")
+	}
+
+	fmt.Printf("Ingested %s into %s:\n", logPath, dbPath)
+	fmt.Printf("  metrics:             %d\n", metrics)
+	fmt.Printf("  events:              %d\n", events)
+	return nil
+}
+
+// replayLog loads the metrics and events of a debug log into a starting
+// instance for --replay. With persistence they go through
+// storage.BulkIngest, which also updates the in-memory state; without it
+// they are added to store one by one.
+func replayLog(store state.Store, sqliteStore *storage.SQLiteStore, logPath string) (metrics, events int, err error) {
+	records, metrics, events, err := readLogRecords(logPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	if sqliteStore != nil {
+		return metrics, events, sqliteStore.BulkIngest(records)
+	}
+	for _, r := range records {
+		if r.Metric != nil {
+			store.AddMetric(r.SessionID, *r.Metric)
+		} else {
+			store.AddEvent(r.SessionID, *r.Event)
+		}
+	}
+	return metrics, events, nil
+}
+
+// readLogRecords reads the metrics and events of a debug log as bulk
+// records, counting each kind.
+func readLogRecords(logPath string) (records []storage.BulkRecord, metrics, events int, err error) {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	logRecords, err := receiver.ReadLog(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	records = make([]storage.BulkRecord, len(logRecords))
+	for i, r := range logRecords {
+		records[i] = storage.BulkRecord{SessionID: r.SessionID, Metric: r.Metric, Event: r.Event}
+		if r.Metric != nil {
+			metrics++
+		} else {
+			events++
+		}
+	}
+	return records, metrics, events, nil
+}
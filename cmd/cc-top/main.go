@@ -7,36 +7,107 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/budget"
 	"github.com/nixlim/cc-top/internal/burnrate"
 	"github.com/nixlim/cc-top/internal/config"
 	"github.com/nixlim/cc-top/internal/correlator"
 	"github.com/nixlim/cc-top/internal/events"
+	"github.com/nixlim/cc-top/internal/gitlog"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/plan"
+	"github.com/nixlim/cc-top/internal/pricing"
+	"github.com/nixlim/cc-top/internal/promexport"
 	"github.com/nixlim/cc-top/internal/receiver"
+	"github.com/nixlim/cc-top/internal/records"
 	"github.com/nixlim/cc-top/internal/scanner"
 	"github.com/nixlim/cc-top/internal/state"
 	"github.com/nixlim/cc-top/internal/stats"
 	"github.com/nixlim/cc-top/internal/storage"
+	"github.com/nixlim/cc-top/internal/team"
 	"github.com/nixlim/cc-top/internal/tui"
+	"github.com/nixlim/cc-top/internal/web"
 )
 
 func main() {
+	var serveMode, webMode bool
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "sync":
+			os.Exit(runSync(os.Args[2:]))
+		case "import":
+			os.Exit(runImport(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "send-test":
+			os.Exit(runSendTest(os.Args[2:]))
+		case "doctor":
+			os.Exit(runDoctor(os.Args[2:]))
+		case "silence":
+			os.Exit(runSilence(os.Args[2:]))
+		case "report":
+			os.Exit(runReport(os.Args[2:]))
+		case "calendar":
+			os.Exit(runCalendar(os.Args[2:]))
+		case "serve":
+			// serve is the usual dashboard (or -headless daemon) plus the
+			// aggregation API, so the remaining arguments are the usual flags.
+			serveMode = true
+			os.Args = append(os.Args[:1:1], os.Args[2:]...)
+		case "web":
+			// Likewise web adds the read-only web dashboard.
+			webMode = true
+			os.Args = append(os.Args[:1:1], os.Args[2:]...)
+		}
+	}
+
 	setupFlag := flag.Bool("setup", false, "Configure Claude Code telemetry settings and exit")
 	debugFlag := flag.String("debug", "", "Write OTEL debug log (JSONL) to the specified file path")
+	replayFlag := flag.String("replay", "", "Load the metrics and events of a -debug log (JSONL) before starting")
+	noScannerFlag := flag.Bool("no-scanner", false, "Run without process inspection (no PID/terminal info, no correlation, no kill switch)")
+	profileTUIFlag := flag.String("profile-tui", "", "Write a CPU profile to the specified file and log slow TUI renders to <file>.log")
+	headlessFlag := flag.Bool("headless", false, "Run receivers, scanner, alerts and storage without the TUI; log to stderr and serve the control socket")
+	socketFlag := flag.String("socket", defaultControlSocket(), "Control socket path used by -headless and -control")
+	controlFlag := flag.String("control", "", "Send a command (status, sessions, alerts, sla <session> <duration>, stop, help) to a headless instance and exit")
+	var webPortFlag *int
+	var webBindFlag *string
+	var webTokenFlag *string
+	if webMode {
+		webPortFlag = flag.Int("port", 8080, "Port of the web dashboard")
+		webBindFlag = flag.String("bind", "127.0.0.1", "Address the web dashboard listens on; 0.0.0.0 serves the LAN and needs --token")
+		webTokenFlag = flag.String("token", "", "Bearer token the web dashboard API requires; open the page as /?token=<token>")
+	}
 	flag.Parse()
 
+	if webMode && *webTokenFlag == "" && !isLoopbackHost(*webBindFlag) {
+		fmt.Fprintf(os.Stderr, "cc-top: the web dashboard has no authentication without --token; set one to bind %s\n", *webBindFlag)
+		os.Exit(2)
+	}
+
 	if *setupFlag {
 		RunSetup()
 		return
 	}
 
+	if *controlFlag != "" {
+		os.Exit(runControl(*socketFlag, *controlFlag))
+	}
+
 	loadResult, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "cc-top: config error: %v\n", err)
@@ -59,10 +130,18 @@ func main() {
 		sqliteStore = storeIface.(*storage.SQLiteStore)
 	}
 
-	proc := scanner.NewDefaultScanner(cfg.Scanner.IntervalSeconds)
-
-	portMapper := correlator.NewScannerPortMapper(proc.API())
-	corr := correlator.NewCorrelator(portMapper, cfg.Receiver.GRPCPort)
+	// With --no-scanner no process inspection happens at all: the scanner and
+	// the port correlator (which resolves ports via the process API) are not
+	// created, and the receiver runs without a port mapper.
+	var proc *scanner.Scanner
+	var corr *correlator.Correlator
+	var recvPortMapper receiver.PortMapper
+	if !*noScannerFlag {
+		proc = scanner.NewDefaultScanner(cfg.Scanner.IntervalSeconds)
+		portMapper := correlator.NewScannerPortMapper(proc.API())
+		corr = correlator.NewCorrelator(portMapper, cfg.Receiver.GRPCPort)
+		recvPortMapper = &portMapperAdapter{corr: corr}
+	}
 
 	var recvOpts []receiver.ReceiverOption
 	if *debugFlag != "" {
@@ -75,7 +154,33 @@ func main() {
 		recvOpts = append(recvOpts, receiver.WithLogger(receiver.NewFileLogger(debugFile)))
 	}
 
-	recv := receiver.New(cfg.Receiver, store, &portMapperAdapter{corr: corr}, recvOpts...)
+	if adm := cfg.Receiver.Admission; len(adm.Allow) > 0 || len(adm.Deny) > 0 {
+		var cwdOf receiver.CWDFunc
+		if proc != nil {
+			cwdOf = func(sourcePort int) string { return sourcePortDir(sourcePort, proc) }
+		}
+		recvOpts = append(recvOpts, receiver.WithAdmission(receiver.NewAdmission(adm, cwdOf)))
+	}
+
+	redactor, err := receiver.NewRedactor(cfg.Receiver.Redaction)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		os.Exit(1)
+	}
+	if redactor != nil {
+		recvOpts = append(recvOpts, receiver.WithRedactor(redactor))
+	}
+
+	forwarder, err := receiver.NewForwarder(cfg.Receiver.Forward)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		os.Exit(1)
+	}
+	if forwarder != nil {
+		recvOpts = append(recvOpts, receiver.WithForwarder(forwarder))
+	}
+
+	recv := receiver.New(cfg.Receiver, store, recvPortMapper, recvOpts...)
 
 	eventBuf := events.NewRingBuffer(cfg.Display.EventBufferSize)
 
@@ -83,29 +188,157 @@ func main() {
 		fe := events.FormatEvent(sessionID, e)
 		eventBuf.Add(fe)
 	})
+	if *replayFlag != "" {
+		metrics, evts, err := replayLog(store, sqliteStore, pathutil.ExpandHome(*replayFlag))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: replay %s: %v\n", *replayFlag, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "cc-top: replayed %d metrics and %d events from %s\n", metrics, evts, *replayFlag)
+	}
+	var eventProvider tui.EventProvider = &eventAdapter{buf: eventBuf}
+	if cfg.Display.EventBufferEviction == "spill" && sqliteStore != nil && !*headlessFlag {
+		eventProvider = events.NewScrollback(eventBuf, sqliteStore)
+	}
 
+	var brOpts []burnrate.CalculatorOption
+	if sqliteStore != nil {
+		brOpts = append(brOpts, burnrate.WithHistory(sqliteStore))
+	}
 	brCalc := burnrate.NewCalculator(burnrate.Thresholds{
 		GreenBelow:  cfg.Display.CostColorGreenBelow,
 		YellowBelow: cfg.Display.CostColorYellowBelow,
-	})
+	}, brOpts...)
 
-	notifier := alerts.NewOSAScriptNotifier(cfg.Alerts.Notifications.SystemNotify)
+	projectOf := func(s state.SessionData) string { return sessionDir(s, proc) }
+	systemNotifier := alerts.NewSystemNotifier(cfg.Alerts.Notifications.Backend, cfg.Alerts.Notifications.SystemNotify)
+	notifier := systemNotifier
+	if notif := cfg.Alerts.Notifications; len(notif.Routes) > 0 || len(notif.Channels) > 0 {
+		channels := map[string]alerts.Notifier{config.SystemChannel: systemNotifier}
+		for name, ch := range notif.Channels {
+			channels[name] = alerts.NewWebhookNotifier(ch.Type, ch.URL)
+		}
+		notifier = alerts.NewRouter(notif.Routes, channels, store, projectOf)
+	}
+	if target := cfg.Alerts.Notifications.LogTarget; target != "" {
+		if sn, err := alerts.NewSyslogNotifier(target, notifier); err != nil {
+			log.Printf("WARNING: alerts will not be logged to %s: %v", target, err)
+		} else {
+			notifier = sn
+		}
+	}
+	if *headlessFlag {
+		notifier = logNotifier{next: notifier}
+	}
+	notifyCounter := &countingNotifier{next: notifier}
+	notifier = notifyCounter
 	var alertOpts []alerts.EngineOption
 	alertOpts = append(alertOpts, alerts.WithNotifier(notifier))
+	alertOpts = append(alertOpts, alerts.WithProjectFunc(projectOf))
 	if sqliteStore != nil {
 		alertOpts = append(alertOpts, alerts.WithPersister(sqliteStore))
+		alertOpts = append(alertOpts, alerts.WithAckStore(sqliteStore))
+		alertOpts = append(alertOpts, alerts.WithSilenceStore(sqliteStore))
+		if cfg.Alerts.CostSurgeAuto || cfg.Alerts.RunawayTokenVelocityAuto {
+			sqliteStore.EnableAutoThresholds(cfg.Alerts.AutoThresholdPercentile)
+			alertOpts = append(alertOpts, alerts.WithThresholdSource(sqliteStore))
+		}
+		sqliteStore.EnableSpendBaseline()
+		alertOpts = append(alertOpts, alerts.WithBaselineSource(sqliteStore))
+	}
+	var budgetOpts []budget.Option
+	if sqliteStore != nil {
+		budgetOpts = append(budgetOpts, budget.WithCostHistory(sqliteStore))
 	}
+	budgetTracker := budget.NewTracker(cfg.Budget, store, budgetOpts...)
+	if budgetTracker.Enabled() {
+		alertOpts = append(alertOpts, alerts.WithBudgetSource(budgetTracker))
+	}
+	recordOpts := []records.Option{records.WithRateSource(&burnRateAdapter{calc: brCalc, store: store})}
+	if sqliteStore != nil {
+		recordOpts = append(recordOpts, records.WithStore(sqliteStore))
+	}
+	recordTracker := records.NewTracker(store, recordOpts...)
+	alertOpts = append(alertOpts, alerts.WithRecordSource(recordTracker))
+	slaTimers := alerts.NewSLATimers()
+	alertOpts = append(alertOpts, alerts.WithSLATimers(slaTimers))
 	alertEngine := alerts.NewEngine(store, cfg, brCalc, alertOpts...)
 
-	statsCalc := stats.NewCalculator(cfg.Pricing)
+	// With [pricing_sync] the fetched prices take precedence over the
+	// configured ones.
+	pricingSync := pricing.NewSyncer(cfg.PricingSync)
+	newStatsCalc := func(cfg config.Config) *stats.Calculator {
+		cfg = pricingSync.Apply(cfg)
+		return stats.NewCalculator(cfg.Pricing,
+			stats.WithLatencyAverage(cfg.Display.LatencyAverage, cfg.Display.LatencyTrimPercent),
+			stats.WithTierPricing(cfg.PricingTiers),
+			stats.WithMinVersion(cfg.Display.MinClaudeCodeVersion),
+			stats.WithContextLimits(cfg.Models, cfg.Alerts.ContextPressurePercent),
+			stats.WithProjectFunc(func(s state.SessionData) string {
+				return gitlog.ProjectDir(sessionDir(s, proc), cfg.Projects.SubprojectDepth, cfg.Projects.Workspaces)
+			}))
+	}
+	// Reloading the config or a pricing update swaps in a calculator with
+	// the new pricing.
+	var statsCalc atomic.Pointer[stats.Calculator]
+	statsCalc.Store(newStatsCalc(cfg))
+	var statsMu sync.Mutex
+	statsCfg := cfg
+	rebuildStatsCalc := func(next *config.Config) {
+		statsMu.Lock()
+		defer statsMu.Unlock()
+		if next != nil {
+			statsCfg = *next
+		}
+		statsCalc.Store(newStatsCalc(statsCfg))
+	}
+
+	var metricsSrv *promexport.Server
+	if cfg.Receiver.MetricsPort != 0 {
+		metricsSrv = promexport.NewServer(
+			net.JoinHostPort(cfg.Receiver.Bind, strconv.Itoa(cfg.Receiver.MetricsPort)),
+			promexport.New(store,
+				promexport.WithBurnRate(func() burnrate.BurnRate { return brCalc.Compute(store) }),
+				promexport.WithAlerts(alertEngine.Alerts)))
+	}
+
+	var teamSrv *team.Server
+	if serveMode {
+		teamSrv = team.NewServer(cfg.Serve, store)
+	}
+	var webSrv *web.Server
+	if webMode {
+		webOpts := []web.Option{
+			web.WithBurnRate(&burnRateAdapter{calc: brCalc, store: store}),
+			web.WithAlerts(&alertAdapter{engine: alertEngine}),
+		}
+		if sqliteStore != nil {
+			webOpts = append(webOpts, web.WithHistory(&historyAdapter{store: sqliteStore}))
+		}
+		if *webTokenFlag != "" {
+			webOpts = append(webOpts, web.WithToken(*webTokenFlag))
+		}
+		webSrv = web.NewServer(net.JoinHostPort(*webBindFlag, strconv.Itoa(*webPortFlag)), web.New(store, webOpts...))
+	}
 
 	shutdownMgr := tui.NewShutdownManager()
 	shutdownMgr.StopReceiver = func(ctx context.Context) error {
 		recv.Stop()
+		if metricsSrv != nil {
+			metricsSrv.Stop()
+		}
+		if teamSrv != nil {
+			teamSrv.Stop()
+		}
+		if webSrv != nil {
+			webSrv.Stop()
+		}
 		return nil
 	}
-	shutdownMgr.StopScanner = func() {
-		proc.Stop()
+	if proc != nil {
+		shutdownMgr.StopScanner = func() {
+			proc.Stop()
+		}
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -114,21 +347,80 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	log.SetOutput(io.Discard)
+	if *headlessFlag {
+		log.SetOutput(os.Stderr)
+	} else {
+		log.SetOutput(io.Discard)
+	}
 
 	if err := recv.Start(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "cc-top: failed to start receivers: %v\n", err)
 		os.Exit(1)
 	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: failed to start metrics endpoint: %v\n", err)
+			recv.Stop()
+			os.Exit(1)
+		}
+	}
 
-	proc.Scan()
-	proc.StartPeriodicScan()
+	if teamSrv != nil {
+		if err := teamSrv.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+			recv.Stop()
+			os.Exit(1)
+		}
+	}
+	if webSrv != nil {
+		if err := webSrv.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: failed to start web dashboard: %v\n", err)
+			recv.Stop()
+			os.Exit(1)
+		}
+	}
+	if pusher := team.NewPusher(cfg.Push, store); pusher != nil {
+		pusher.Start(ctx)
+	}
+
+	if proc != nil {
+		proc.Scan()
+		proc.StartPeriodicScan()
+		syncSessionPIDs(ctx, store, proc, corr, time.Duration(cfg.Scanner.IntervalSeconds)*time.Second)
+		syncSessionGit(ctx, store, proc, time.Duration(cfg.Scanner.IntervalSeconds)*time.Second)
+	}
 
 	alertEngine.Start(ctx)
 
+	watcher := config.NewWatcher(config.DefaultPath(), loadResult)
+	watcher.Subscribe(func(r config.Reload) {
+		if r.Err != nil {
+			return
+		}
+		for _, w := range r.Warnings {
+			log.Printf("WARNING: config: %s", w)
+		}
+		if len(r.Restart) > 0 {
+			log.Printf("WARNING: config changes to %s take effect after a restart", strings.Join(r.Restart, ", "))
+		}
+		alertEngine.Reload(r.Config)
+		brCalc.SetThresholds(burnrate.Thresholds{
+			GreenBelow:  r.Config.Display.CostColorGreenBelow,
+			YellowBelow: r.Config.Display.CostColorYellowBelow,
+		})
+		rebuildStatsCalc(&r.Config)
+		log.Printf("config reloaded from %s", config.DefaultPath())
+	})
+	watcher.Start(ctx)
+
+	if pricingSync != nil {
+		pricingSync.Subscribe(func(*pricing.Manifest) { rebuildStatsCalc(nil) })
+		pricingSync.Start(ctx)
+	}
+
 	if sqliteStore != nil {
 		sqliteStore.SetStatsSnapshotFunc(func() stats.DashboardStats {
-			return statsCalc.Compute(store.ListSessions())
+			return statsCalc.Load().Compute(store.Snapshot().Sessions)
 		})
 		sqliteStore.SetBurnRateSnapshotFunc(func() burnrate.BurnRate {
 			return brCalc.Compute(store)
@@ -136,23 +428,109 @@ func main() {
 		sqliteStore.StartBurnRateSnapshots()
 	}
 
+	if *headlessFlag {
+		d := &daemon{
+			cfg:        cfg,
+			store:      store,
+			brCalc:     brCalc,
+			engine:     alertEngine,
+			slaTimers:  slaTimers,
+			persistent: isPersistent,
+			started:    time.Now(),
+		}
+		err := runHeadless(ctx, d, *socketFlag, sigCh, func() {
+			alertEngine.Stop()
+			_ = shutdownMgr.Shutdown()
+			_ = store.Close()
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+			alertEngine.Stop()
+			_ = shutdownMgr.Shutdown()
+			_ = store.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
 	modelOpts := []tui.ModelOption{
 		tui.WithStateProvider(store),
-		tui.WithScannerProvider(&scannerAdapter{scanner: proc, cfg: cfg, store: store}),
 		tui.WithBurnRateProvider(&burnRateAdapter{calc: brCalc, store: store}),
-		tui.WithEventProvider(&eventAdapter{buf: eventBuf}),
+		tui.WithEventProvider(eventProvider),
 		tui.WithAlertProvider(&alertAdapter{engine: alertEngine}),
-		tui.WithStatsProvider(&statsAdapter{calc: statsCalc, store: store}),
+		tui.WithNotificationMuter(alertEngine),
+		tui.WithStatsProvider(&statsAdapter{calc: &statsCalc, store: store}),
+		tui.WithSLAProvider(slaTimers),
+		tui.WithRecordsProvider(recordTracker),
 		tui.WithStartView(tui.ViewStartup),
 		tui.WithPersistenceFlag(isPersistent),
+		tui.WithScannerDisabled(*noScannerFlag),
+		tui.WithTerminalOutput(os.Stdout),
+		tui.WithNotifyStatus(func() string {
+			notif := cfg.Alerts.Notifications
+			switch {
+			case !notif.SystemNotify && len(notif.Channels) == 0 && notif.LogTarget == "":
+				return "off"
+			case notifyCounter.sent.Load() == 0:
+				return "untested"
+			}
+			return "ok"
+		}),
 		tui.WithOnShutdown(func() {
 			alertEngine.Stop()
 			_ = shutdownMgr.Shutdown()
 			_ = store.Close()
 		}),
 	}
+	if budgetTracker.Enabled() {
+		modelOpts = append(modelOpts, tui.WithBudgetProvider(budgetTracker))
+	}
+	if planTracker := plan.NewTracker(cfg.Plan, store); planTracker.Enabled() {
+		modelOpts = append(modelOpts, tui.WithPlanProvider(planTracker))
+	}
+	if proc != nil {
+		modelOpts = append(modelOpts, tui.WithScannerProvider(&scannerAdapter{scanner: proc, cfg: cfg, store: store}))
+	}
 	if sqliteStore != nil {
-		modelOpts = append(modelOpts, tui.WithHistoryProvider(&historyAdapter{store: sqliteStore}))
+		modelOpts = append(modelOpts,
+			tui.WithHistoryProvider(&historyAdapter{store: sqliteStore}),
+			tui.WithReplaySource(&historyAdapter{store: sqliteStore}),
+			tui.WithEventHistory(&historyAdapter{store: sqliteStore}),
+			tui.WithWriterStats(sqliteStore),
+			tui.WithThresholdTuner(sqliteStore),
+			tui.WithSessionTagger(sqliteStore),
+		)
+	}
+	if cfg.Scanner.GitCommits {
+		commitTracker := gitlog.NewTracker(func(s state.SessionData) string {
+			return sessionDir(s, proc)
+		})
+		commitTracker.Start(ctx, store, gitCommitInterval)
+		modelOpts = append(modelOpts, tui.WithCommitProvider(commitTracker))
+	}
+
+	if *profileTUIFlag != "" {
+		stop, watchdog, err := startTUIProfile(*profileTUIFlag, time.Duration(cfg.Display.SlowRenderMS)*time.Millisecond)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+			os.Exit(1)
+		}
+		modelOpts = append(modelOpts, tui.WithRenderWatchdog(watchdog))
+		defer func() {
+			stop()
+			fmt.Fprintf(os.Stderr, "cc-top: CPU profile written to %s; %d slow render(s) logged to %s.log\n",
+				*profileTUIFlag, watchdog.SlowCount(), *profileTUIFlag)
+		}()
+	}
+
+	uiStatePath := defaultUIStatePath()
+	modelOpts = append(modelOpts, tui.WithScreenDumpDir(filepath.Dir(uiStatePath)))
+	if cfg.Display.RememberState {
+		uiState, err := tui.LoadUIState(uiStatePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: ignoring UI state: %v\n", err)
+		}
+		modelOpts = append(modelOpts, tui.WithUIState(uiState))
 	}
 
 	model := tui.NewModel(cfg, modelOpts...)
@@ -160,6 +538,10 @@ func main() {
 	p := tea.NewProgram(model,
 		tea.WithAltScreen(),
 	)
+	watcher.Subscribe(func(r config.Reload) { p.Send(tui.ConfigReloadedMsg(r)) })
+	if bell, ok := systemNotifier.(*alerts.BellNotifier); ok {
+		bell.OnNotify(func(a alerts.Alert) { p.Send(tui.AlertFlashMsg(a)) })
+	}
 
 	go func() {
 		select {
@@ -173,10 +555,55 @@ func main() {
 		}
 	}()
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
 		os.Exit(1)
 	}
+	if m, ok := final.(tui.Model); ok {
+		m.ClearTerminalStatus(os.Stdout)
+	}
+	if m, ok := final.(tui.Model); ok && cfg.Display.RememberState {
+		if err := tui.SaveUIState(uiStatePath, m.UIState()); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: saving UI state: %v\n", err)
+		}
+	}
+}
+
+// defaultUIStatePath returns where the TUI remembers its state between runs.
+func defaultUIStatePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "cc-top-ui-state.json")
+	}
+	return filepath.Join(home, ".local", "share", "cc-top", "ui-state.json")
+}
+
+// startTUIProfile starts CPU profiling to path and opens path+".log" for the
+// render watchdog. The returned stop function ends profiling and closes both
+// files.
+func startTUIProfile(path string, threshold time.Duration) (stop func(), wd *tui.RenderWatchdog, err error) {
+	profFile, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating TUI profile %q: %w", path, err)
+	}
+	logFile, err := os.Create(path + ".log")
+	if err != nil {
+		_ = profFile.Close()
+		return nil, nil, fmt.Errorf("creating render log %q: %w", path+".log", err)
+	}
+	if err := pprof.StartCPUProfile(profFile); err != nil {
+		_ = profFile.Close()
+		_ = logFile.Close()
+		return nil, nil, fmt.Errorf("starting CPU profile: %w", err)
+	}
+
+	stop = func() {
+		pprof.StopCPUProfile()
+		_ = profFile.Close()
+		_ = logFile.Close()
+	}
+	return stop, tui.NewRenderWatchdog(threshold, logFile), nil
 }
 
 type portMapperAdapter struct {
@@ -200,14 +627,16 @@ func (a *scannerAdapter) Processes() []scanner.ProcessInfo {
 func (a *scannerAdapter) GetTelemetryStatus(p scanner.ProcessInfo) scanner.StatusInfo {
 	hasData := false
 	if a.store != nil {
-		for _, s := range a.store.ListSessions() {
-			if s.PID == p.PID {
+		sessions := a.store.Snapshot().Sessions
+		for i := range sessions {
+			if sessions[i].PID == p.PID {
 				hasData = true
 				break
 			}
 		}
 	}
-	return scanner.ClassifyTelemetry(p, a.cfg.Receiver.GRPCPort, hasData)
+	port := scanner.ReceiverPort(p, a.cfg.Receiver.GRPCPort, a.cfg.Receiver.HTTPPort)
+	return scanner.ClassifyTelemetry(p, port, hasData)
 }
 
 func (a *scannerAdapter) Rescan() {
@@ -220,7 +649,11 @@ type burnRateAdapter struct {
 }
 
 func (a *burnRateAdapter) Get(sessionID string) burnrate.BurnRate {
-	return a.calc.Compute(a.store)
+	s := a.store.Snapshot().Session(sessionID)
+	if s == nil {
+		return burnrate.BurnRate{}
+	}
+	return a.calc.ComputeSession(*s, time.Now())
 }
 
 func (a *burnRateAdapter) GetGlobal() burnrate.BurnRate {
@@ -247,6 +680,18 @@ func (a *eventAdapter) RecentForSession(sessionID string, limit int) []events.Fo
 	return all[len(all)-limit:]
 }
 
+// countingNotifier counts the alerts passed on to next, for the health
+// summary in the TUI header.
+type countingNotifier struct {
+	next alerts.Notifier
+	sent atomic.Int64
+}
+
+func (n *countingNotifier) Notify(a alerts.Alert) {
+	n.sent.Add(1)
+	n.next.Notify(a)
+}
+
 type alertAdapter struct {
 	engine *alerts.Engine
 }
@@ -255,6 +700,18 @@ func (a *alertAdapter) Active() []alerts.Alert {
 	return a.engine.Alerts()
 }
 
+func (a *alertAdapter) Acknowledge(alert alerts.Alert) {
+	a.engine.Acknowledge(alert)
+}
+
+func (a *alertAdapter) Snooze(alert alerts.Alert, d time.Duration) {
+	a.engine.Snooze(alert, d)
+}
+
+func (a *alertAdapter) Silences() []alerts.Silence {
+	return a.engine.Silences()
+}
+
 func (a *alertAdapter) ActiveForSession(sessionID string) []alerts.Alert {
 	all := a.engine.Alerts()
 	var result []alerts.Alert
@@ -267,28 +724,50 @@ func (a *alertAdapter) ActiveForSession(sessionID string) []alerts.Alert {
 }
 
 type statsAdapter struct {
-	calc  *stats.Calculator
+	calc  *atomic.Pointer[stats.Calculator]
 	store state.Store
 }
 
 func (a *statsAdapter) Get(sessionID string) stats.DashboardStats {
-	s := a.store.GetSession(sessionID)
+	s := a.store.Snapshot().Session(sessionID)
 	if s == nil {
 		return stats.DashboardStats{}
 	}
-	return a.calc.Compute([]state.SessionData{*s})
+	return a.calc.Load().Compute([]state.SessionData{*s})
 }
 
 func (a *statsAdapter) GetGlobal() stats.DashboardStats {
-	return a.calc.Compute(a.store.ListSessions())
+	return a.calc.Load().Compute(a.store.Snapshot().Sessions)
 }
 
 type historyAdapter struct {
 	store *storage.SQLiteStore
 }
 
+func (a *historyAdapter) SessionEvents(sessionID string) []state.Event {
+	return a.store.QuerySessionEvents(sessionID)
+}
+
+func (a *historyAdapter) QueryEvents(from, to time.Time, sessionID, name string, limit int) []events.FormattedEvent {
+	rows := a.store.QueryEvents(from, to, sessionID, name, limit)
+	result := make([]events.FormattedEvent, len(rows))
+	for i, r := range rows {
+		result[i] = events.FormatEvent(r.SessionID, r.Event)
+	}
+	return result
+}
+
+func (a *historyAdapter) EventFacets(from, to time.Time) (sessions, names []string) {
+	return a.store.QueryEventFacets(from, to)
+}
+
 func (a *historyAdapter) QueryDailyStats(days int) []tui.DailyStatsRow {
-	rows := a.store.QueryDailyStats(days)
+	return toTUIDailyStats(a.store.QueryDailyStats(days))
+}
+
+// toTUIDailyStats converts stored daily stats rows, decoding their JSON
+// breakdowns.
+func toTUIDailyStats(rows []storage.DailyStatsRow) []tui.DailyStatsRow {
 	result := make([]tui.DailyStatsRow, len(rows))
 	for i, r := range rows {
 		result[i] = tui.DailyStatsRow{
@@ -334,6 +813,15 @@ func (a *historyAdapter) QueryDailyStats(days int) []tui.DailyStatsRow {
 		if r.MCPToolUsage != "" {
 			_ = json.Unmarshal([]byte(r.MCPToolUsage), &result[i].MCPToolUsage)
 		}
+		if r.AccountBreakdown != "" {
+			_ = json.Unmarshal([]byte(r.AccountBreakdown), &result[i].AccountBreakdown)
+		}
+		if r.ProjectBreakdown != "" {
+			_ = json.Unmarshal([]byte(r.ProjectBreakdown), &result[i].ProjectBreakdown)
+		}
+		if r.ContextUsage != "" {
+			_ = json.Unmarshal([]byte(r.ContextUsage), &result[i].ContextUsage)
+		}
 	}
 	return result
 }
@@ -355,6 +843,19 @@ func (a *historyAdapter) QueryBurnRateDailySummary(days int) []tui.BurnRateDaily
 	return result
 }
 
+func (a *historyAdapter) QueryBurnRateHourly(days int) map[string][24]float64 {
+	result := make(map[string][24]float64)
+	for _, r := range a.store.QueryBurnRateHourly(days) {
+		if r.Hour < 0 || r.Hour > 23 {
+			continue
+		}
+		hours := result[r.Date]
+		hours[r.Hour] = r.AvgHourlyRate
+		result[r.Date] = hours
+	}
+	return result
+}
+
 func (a *historyAdapter) QueryBurnRateSnapshots(date string) []tui.BurnRateSnapshotRow {
 	rows := a.store.QueryBurnRateSnapshotsForDate(date)
 	result := make([]tui.BurnRateSnapshotRow, len(rows))
@@ -376,6 +877,19 @@ func (a *historyAdapter) QueryBurnRateSnapshots(date string) []tui.BurnRateSnaps
 	return result
 }
 
+func (a *historyAdapter) QueryProjectionAccuracy(days int) []tui.ProjectionAccuracyRow {
+	rows := a.store.QueryProjectionAccuracy(days)
+	result := make([]tui.ProjectionAccuracyRow, len(rows))
+	for i, r := range rows {
+		result[i] = tui.ProjectionAccuracyRow{
+			Date:          r.Date,
+			ProjectedCost: r.ProjectedCost,
+			ActualCost:    r.ActualCost,
+		}
+	}
+	return result
+}
+
 func (a *historyAdapter) QueryAlertHistory(days int, ruleFilter string) []tui.AlertHistoryRow {
 	rows := a.store.QueryAlertHistory(days, ruleFilter)
 	result := make([]tui.AlertHistoryRow, len(rows))
@@ -387,7 +901,164 @@ func (a *historyAdapter) QueryAlertHistory(days int, ruleFilter string) []tui.Al
 			Message:   r.Message,
 			SessionID: r.SessionID,
 			FiredAt:   firedAt,
+			Note:      r.Note,
 		}
 	}
 	return result
 }
+
+func (a *historyAdapter) SetAlertNote(rule, sessionID string, firedAt time.Time, note string) {
+	a.store.SetAlertNote(rule, sessionID, firedAt, note)
+}
+
+// gitCommitInterval is how often session directories are re-scanned for new
+// commits when scanner.git_commits is enabled.
+const gitCommitInterval = time.Minute
+
+// sessionDir resolves the working directory of a session: its recorded CWD
+// if any, otherwise the CWD of the scanned process with the session's PID.
+// proc is nil with --no-scanner.
+// isLoopbackHost reports whether host, a bind address, only accepts
+// connections from this machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func sessionDir(s state.SessionData, proc *scanner.Scanner) string {
+	if s.CWD != "" {
+		return gitlog.ExpandHome(s.CWD)
+	}
+	if proc == nil || s.PID == 0 {
+		return ""
+	}
+	for _, p := range proc.GetProcesses() {
+		if p.PID == s.PID && p.CWD != "" {
+			return gitlog.ExpandHome(p.CWD)
+		}
+	}
+	return ""
+}
+
+// syncSessionGit copies the repository and branch of each live session's
+// process onto the session every interval until ctx is cancelled. Sessions
+// without a scanned process fall back to their recorded CWD.
+func syncSessionGit(ctx context.Context, store state.Store, proc *scanner.Scanner, interval time.Duration) {
+	update := func() {
+		byPID := make(map[int]scanner.ProcessInfo)
+		for _, p := range proc.GetProcesses() {
+			byPID[p.PID] = p
+		}
+		for _, s := range store.Snapshot().Sessions {
+			if s.Exited {
+				continue
+			}
+			var repo, branch string
+			if p, ok := byPID[s.PID]; ok && s.PID != 0 {
+				repo, branch = p.Repo, p.Branch
+			} else if s.CWD != "" {
+				repo, branch = gitlog.Head(gitlog.ExpandHome(s.CWD))
+			} else {
+				continue
+			}
+			if repo != s.Repo || branch != s.Branch {
+				store.UpdateGit(s.SessionID, repo, branch)
+			}
+		}
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			update()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// syncSessionPIDs correlates scanned processes with sessions every interval
+// until ctx is cancelled. Each session is attributed to its process, and
+// sessions that more than one live process exports under (e.g. a forked
+// subprocess that inherited the environment) are flagged and logged
+// instead of being merged silently.
+func syncSessionPIDs(ctx context.Context, store state.Store, proc *scanner.Scanner, corr *correlator.Correlator, interval time.Duration) {
+	known := make(map[int]bool)
+	update := func() {
+		var active []int
+		for _, p := range proc.GetProcesses() {
+			if p.Exited {
+				if known[p.PID] {
+					corr.RemovePID(p.PID)
+					delete(known, p.PID)
+				}
+				continue
+			}
+			active = append(active, p.PID)
+			if !known[p.PID] {
+				known[p.PID] = true
+				corr.RecordPID(p.PID)
+			}
+		}
+		corr.Correlate(active)
+
+		duplicates := make(map[string][]int)
+		for _, d := range corr.Duplicates() {
+			duplicates[d.SessionID] = d.Others
+		}
+		for _, s := range store.Snapshot().Sessions {
+			if pid := corr.GetPIDForSession(s.SessionID); pid != 0 && pid != s.PID {
+				store.UpdatePID(s.SessionID, pid)
+			}
+			others := duplicates[s.SessionID]
+			if slices.Equal(others, s.DuplicatePIDs) {
+				continue
+			}
+			if len(others) > 0 {
+				log.Printf("WARNING: session %s is also exported by PIDs %v; its data stays attributed to PID %d. A subprocess probably inherited the session's environment.",
+					s.SessionID, others, corr.GetPIDForSession(s.SessionID))
+			}
+			store.SetDuplicatePIDs(s.SessionID, others)
+		}
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			update()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sourcePortDir resolves the working directory of the scanned process that
+// owns the local end of a connection from sourcePort, for cwd: admission
+// matchers.
+func sourcePortDir(sourcePort int, proc *scanner.Scanner) string {
+	api := proc.API()
+	for _, p := range proc.GetProcesses() {
+		if p.CWD == "" {
+			continue
+		}
+		ports, err := api.GetOpenPorts(p.PID)
+		if err != nil {
+			continue
+		}
+		for _, pair := range ports {
+			if pair[0] == sourcePort {
+				return gitlog.ExpandHome(p.CWD)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+	"github.com/nixlim/cc-top/internal/storage"
+	"github.com/nixlim/cc-top/internal/tui"
+)
+
+// runReport implements `cc-top report`: it summarizes a day, week or month
+// of history into a Markdown, HTML or JSON file to share.
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top report [--period day|week|month] [--date <date>] [--format md|html|json] [--out <file>] [--db <path>]\n\n")
+		fs.PrintDefaults()
+	}
+	periodFlag := fs.String("period", "week", "Period to report on: day, week (Monday to Sunday) or month")
+	dateFlag := fs.String("date", "", "A date in the period (YYYY-MM-DD, local; default today)")
+	formatFlag := fs.String("format", "md", "Output format: md, html or json")
+	outFlag := fs.String("out", "", `File to write (default cc-top-report-<first date>.<format>; "-" for stdout)`)
+	dbFlag := fs.String("db", "", "Database to report on (default: storage.db_path from config)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	day := time.Now()
+	if *dateFlag != "" {
+		d, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: invalid date %q (want YYYY-MM-DD)\n", *dateFlag)
+			return 2
+		}
+		day = d
+	}
+	var start, end time.Time
+	switch *periodFlag {
+	case "day":
+		y, m, d := day.Date()
+		start = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		end = start
+	case "week":
+		start = budget.WeekStart(day)
+		end = start.AddDate(0, 0, 6)
+	case "month":
+		start = budget.MonthStart(day)
+		end = start.AddDate(0, 1, -1)
+	default:
+		fmt.Fprintf(os.Stderr, "cc-top: invalid period %q (want day, week or month)\n", *periodFlag)
+		return 2
+	}
+	switch *formatFlag {
+	case "md", "html", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "cc-top: invalid format %q (want md, html or json)\n", *formatFlag)
+		return 2
+	}
+
+	dbPath, err := localDBPath(*dbFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+	from, to := start.Format("2006-01-02"), end.Format("2006-01-02")
+	rows, err := storage.ReadDailyStats(dbPath, from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: report: %v\n", err)
+		return 1
+	}
+	report := tui.BuildReport(*periodFlag, from, to, toTUIDailyStats(rows), time.Now())
+
+	var out []byte
+	switch *formatFlag {
+	case "md":
+		out = []byte(report.Markdown())
+	case "html":
+		html, err := report.HTML()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: report: %v\n", err)
+			return 1
+		}
+		out = []byte(html)
+	case "json":
+		if out, err = json.MarshalIndent(report, "", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: report: %v\n", err)
+			return 1
+		}
+		out = append(out, '\n')
+	}
+
+	path := *outFlag
+	if path == "-" {
+		_, _ = os.Stdout.Write(out)
+		return 0
+	}
+	if path == "" {
+		path = fmt.Sprintf("cc-top-report-%s.%s", from, *formatFlag)
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: report: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote the report for %s to %s.\n", report.Title(), path)
+	return 0
+}
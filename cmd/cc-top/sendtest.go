@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/receiver"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+// sendTestPoll is how often send-test checks the database for the batch.
+const sendTestPoll = 250 * time.Millisecond
+
+// runSendTest implements `cc-top send-test`: it sends a synthetic OTLP
+// batch to the configured receiver, like Claude Code would, and follows it
+// into the running instance's database to check the ingest path end to end.
+func runSendTest(args []string) int {
+	fs := flag.NewFlagSet("send-test", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top send-test [--protocol grpc|http] [--endpoint <url>] [--ca <file>] [--cert <file> --key <file>] [--db <path>] [--wait <duration>]\n\n")
+		fs.PrintDefaults()
+	}
+	protocolFlag := fs.String("protocol", "grpc", "OTLP protocol: grpc or http (http/protobuf)")
+	endpointFlag := fs.String("endpoint", "", "Receiver base URL (default: from [receiver] in config, e.g. http://127.0.0.1:4317)")
+	caFlag := fs.String("ca", "", "CA file to verify an https receiver with (default: [receiver.tls] cert_file, for a self-signed certificate)")
+	certFlag := fs.String("cert", "", "Client certificate, for a receiver that requires one (mTLS)")
+	keyFlag := fs.String("key", "", "Key of the --cert client certificate")
+	dbFlag := fs.String("db", "", "Database the running cc-top writes (default: storage.db_path from config)")
+	waitFlag := fs.Duration("wait", 10*time.Second, "How long to wait for the batch to reach the database")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *protocolFlag != "grpc" && *protocolFlag != "http" {
+		fmt.Fprintf(os.Stderr, "cc-top: unknown protocol %q (want grpc or http)\n", *protocolFlag)
+		return 2
+	}
+	if (*certFlag == "") != (*keyFlag == "") {
+		fmt.Fprintln(os.Stderr, "cc-top: --cert and --key must be given together")
+		return 2
+	}
+
+	loadResult, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: config error: %v\n", err)
+		return 1
+	}
+	cfg := loadResult.Config
+
+	endpoint := *endpointFlag
+	if endpoint == "" {
+		port := cfg.Receiver.GRPCPort
+		if *protocolFlag == "http" {
+			port = cfg.Receiver.HTTPPort
+		}
+		scheme := "http://"
+		if cfg.Receiver.TLS.Enabled() {
+			scheme = "https://"
+		}
+		endpoint = scheme + net.JoinHostPort(receiverHost(cfg.Receiver.Bind), strconv.Itoa(port))
+	}
+	protocol := *protocolFlag
+	if protocol == "http" {
+		protocol = "http/protobuf"
+	}
+	fwd := config.ForwardConfig{Endpoint: endpoint, Protocol: protocol, TimeoutSeconds: 5}
+	fwd.TLS = config.ForwardTLSConfig{
+		CAFile:   pathutil.ExpandHome(*caFlag),
+		CertFile: pathutil.ExpandHome(*certFlag),
+		KeyFile:  pathutil.ExpandHome(*keyFlag),
+	}
+	if fwd.TLS.CAFile == "" {
+		fwd.TLS.CAFile = pathutil.ExpandHome(cfg.Receiver.TLS.CertFile)
+	}
+	if cfg.Receiver.AuthToken != "" {
+		fwd.Headers = map[string]string{"Authorization": "Bearer " + cfg.Receiver.AuthToken}
+	}
+	client, err := receiver.NewForwarder(fwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 2
+	}
+	defer client.Stop()
+
+	now := time.Now()
+	sessionID := "send-test-" + now.Format("20060102-150405.000")
+	metrics, logs := receiver.TestPayload(sessionID, now)
+
+	fmt.Printf("Sending test telemetry for session %s to %s (%s)\n", sessionID, endpoint, *protocolFlag)
+	for _, send := range []struct {
+		what string
+		fn   func() error
+	}{
+		{"metrics", func() error { return client.SendMetrics(metrics) }},
+		{"events", func() error { return client.SendLogs(logs) }},
+	} {
+		start := time.Now()
+		if err := send.fn(); err != nil {
+			fmt.Printf("  %-8s FAILED: %v\n", send.what+":", err)
+			fmt.Println("Is cc-top running, and listening on this port? Check [receiver] in the config.")
+			return 1
+		}
+		fmt.Printf("  %-8s accepted in %s\n", send.what+":", time.Since(start).Round(time.Millisecond))
+	}
+
+	dbPath := pathutil.ExpandHome(*dbFlag)
+	if dbPath == "" {
+		dbPath = pathutil.ExpandHome(cfg.Storage.DBPath)
+	}
+	if dbPath == "" {
+		fmt.Printf("Persistence is disabled, so storage can't be checked; look for session %s in the dashboard.\n", sessionID)
+		return 0
+	}
+
+	fmt.Printf("Waiting up to %s for the batch to reach %s\n", *waitFlag, dbPath)
+	start := time.Now()
+	var rows storage.SessionRows
+	for {
+		rows, err = storage.CountSessionRows(dbPath, sessionID)
+		if err != nil {
+			fmt.Printf("  FAILED: %v\n", err)
+			return 1
+		}
+		if (rows.Metrics > 0 && rows.Events > 0) || time.Since(start) >= *waitFlag {
+			break
+		}
+		time.Sleep(sendTestPoll)
+	}
+	listed := "not listed yet"
+	if rows.Session {
+		listed = "listed"
+	}
+	fmt.Printf("  session %s, %d metric row(s), %d event row(s) after %s\n",
+		listed, rows.Metrics, rows.Events, time.Since(start).Round(100*time.Millisecond))
+
+	if rows.Metrics == 0 || rows.Events == 0 {
+		fmt.Println("The receiver accepted the batch but it was not stored. Possible causes:")
+		fmt.Println("  - [receiver.admission] rules dropped the session")
+		fmt.Println("  - another program is listening on the receiver port")
+		fmt.Println("  - the running cc-top writes a different database (pass --db)")
+		return 1
+	}
+	fmt.Println("OK: telemetry is received and stored.")
+	return 0
+}
+
+// receiverHost returns the address to reach a receiver bound to bind.
+func receiverHost(bind string) string {
+	switch bind {
+	case "", "0.0.0.0", "::":
+		return "127.0.0.1"
+	}
+	return bind
+}
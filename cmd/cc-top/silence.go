@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+// runSilence implements `cc-top silence`: it keeps an alert rule from
+// firing for a while, e.g. before a planned expensive run. Silences are
+// stored in the database, so a running cc-top picks them up and they
+// survive restarts.
+func runSilence(args []string) int {
+	fs := flag.NewFlagSet("silence", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top silence --rule <name> --for <duration> [--reason <text>] [--db <path>]\n")
+		fmt.Fprintf(fs.Output(), "       cc-top silence --list [--db <path>]\n")
+		fmt.Fprintf(fs.Output(), "       cc-top silence --end <id> [--db <path>]\n\n")
+		fs.PrintDefaults()
+	}
+	ruleFlag := fs.String("rule", "", `Rule to silence, or "*" for every rule`)
+	forFlag := fs.Duration("for", 0, "How long the silence lasts, e.g. 2h or 30m")
+	reasonFlag := fs.String("reason", "", "Why, shown in the Alerts panel")
+	listFlag := fs.Bool("list", false, "List the silences in effect")
+	endFlag := fs.Int64("end", 0, "End the silence with this ID")
+	dbFlag := fs.String("db", "", "Database (default: storage.db_path from config)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	dbPath, err := localDBPath(*dbFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+	now := time.Now()
+
+	switch {
+	case *listFlag:
+		silences, err := storage.ListSilences(dbPath, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: silence: %v\n", err)
+			return 1
+		}
+		if len(silences) == 0 {
+			fmt.Println("No silences in effect.")
+			return 0
+		}
+		fmt.Printf("%-5s %-20s %-17s %s\n", "ID", "RULE", "UNTIL", "REASON")
+		for _, s := range silences {
+			fmt.Printf("%-5d %-20s %-17s %s\n", s.ID, s.Rule, s.Until.Local().Format("2006-01-02 15:04"), s.Reason)
+		}
+		return 0
+
+	case *endFlag != 0:
+		ended, err := storage.EndSilence(dbPath, *endFlag, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: silence: %v\n", err)
+			return 1
+		}
+		if !ended {
+			fmt.Fprintf(os.Stderr, "cc-top: no silence %d in effect\n", *endFlag)
+			return 1
+		}
+		fmt.Printf("Ended silence %d.\n", *endFlag)
+		return 0
+	}
+
+	if *ruleFlag == "" || *forFlag <= 0 {
+		fs.Usage()
+		return 2
+	}
+	if known := silenceRuleNames(); *ruleFlag != "*" && !slices.Contains(known, *ruleFlag) {
+		fmt.Fprintf(os.Stderr, "cc-top: unknown rule %q (want one of %s, or *)\n", *ruleFlag, strings.Join(known, ", "))
+		return 2
+	}
+
+	s := alerts.Silence{Rule: *ruleFlag, Reason: *reasonFlag, CreatedAt: now, Until: now.Add(*forFlag)}
+	id, err := storage.AddSilence(dbPath, s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: silence: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Silenced %s until %s (id %d; end early with cc-top silence --end %d).\n",
+		s.Rule, s.Until.Local().Format("2006-01-02 15:04"), id, id)
+	return 0
+}
+
+// silenceRuleNames returns the rules that can be silenced: the built-in
+// ones, plus the custom and composite rules of the config if it loads.
+func silenceRuleNames() []string {
+	if result, err := config.Load(); err == nil {
+		return result.Config.Alerts.RuleNames()
+	}
+	return slices.Clone(config.BuiltinRuleNames)
+}
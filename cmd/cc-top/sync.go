@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/storage"
+)
+
+// peerDownloadTimeout bounds fetching a peer database over HTTP.
+const peerDownloadTimeout = 5 * time.Minute
+
+// runSync implements `cc-top sync`: it merges the history of a peer cc-top
+// database into the local one.
+func runSync(args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cc-top sync --peer <path|url> [--db <path>] [--two-way]\n\n")
+		fs.PrintDefaults()
+	}
+	peerFlag := fs.String("peer", "", "Peer database to merge: a file path or an http(s) URL serving the file")
+	dbFlag := fs.String("db", "", "Local database (default: storage.db_path from config)")
+	twoWayFlag := fs.Bool("two-way", false, "Also merge the local database into the peer (file paths only)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *peerFlag == "" {
+		fs.Usage()
+		return 2
+	}
+
+	dbPath, err := localDBPath(*dbFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+		return 1
+	}
+
+	peerPath := *peerFlag
+	remote := strings.HasPrefix(peerPath, "http://") || strings.HasPrefix(peerPath, "https://")
+	if remote {
+		if *twoWayFlag {
+			fmt.Fprintln(os.Stderr, "cc-top: --two-way needs a peer file path, not a URL")
+			return 2
+		}
+		tmp, err := downloadPeer(peerPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: %v\n", err)
+			return 1
+		}
+		defer os.Remove(tmp)
+		peerPath = tmp
+	} else {
+		peerPath = pathutil.ExpandHome(peerPath)
+	}
+
+	result, err := storage.MergeDB(dbPath, peerPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cc-top: sync: %v\n", err)
+		return 1
+	}
+	printMergeResult(*peerFlag, dbPath, result)
+
+	if *twoWayFlag {
+		result, err := storage.MergeDB(peerPath, dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cc-top: sync: %v\n", err)
+			return 1
+		}
+		printMergeResult(dbPath, peerPath, result)
+	}
+	return 0
+}
+
+// localDBPath returns the database path given by a --db flag, falling back
+// to storage.db_path from the config.
+func localDBPath(override string) (string, error) {
+	dbPath := override
+	if dbPath == "" {
+		loadResult, err := config.Load()
+		if err != nil {
+			return "", fmt.Errorf("config error: %w", err)
+		}
+		dbPath = loadResult.Config.Storage.DBPath
+		if dbPath == "" {
+			return "", fmt.Errorf("persistence is disabled (storage.db_path is empty); pass --db")
+		}
+	}
+	return pathutil.ExpandHome(dbPath), nil
+}
+
+func printMergeResult(from, into string, r storage.MergeResult) {
+	fmt.Printf("Merged %s into %s:\n", from, into)
+	fmt.Printf("  sessions:            %d\n", r.Sessions)
+	fmt.Printf("  metrics:             %d\n", r.Metrics)
+	fmt.Printf("  events:              %d\n", r.Events)
+	fmt.Printf("  daily summaries:     %d\n", r.DailySummaries)
+	fmt.Printf("  daily stats:         %d\n", r.DailyStats)
+	fmt.Printf("  burn rate snapshots: %d\n", r.BurnRateSnapshots)
+	fmt.Printf("  alerts:              %d\n", r.Alerts)
+	fmt.Printf("  session tags:        %d\n", r.SessionTags)
+	if len(r.SkippedDays) > 0 {
+		fmt.Printf("  kept local daily stats for %d day(s) recorded on both: %s\n",
+			len(r.SkippedDays), strings.Join(r.SkippedDays, ", "))
+	}
+}
+
+// downloadPeer fetches a peer database served over HTTP into a temporary
+// file and returns its path.
+func downloadPeer(url string) (string, error) {
+	client := &http.Client{Timeout: peerDownloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("fetching peer database: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching peer database: %s", resp.Status)
+	}
+
+	f, err := os.CreateTemp("", "cc-top-peer-*.db")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("fetching peer database: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("writing peer database: %w", err)
+	}
+	return f.Name(), nil
+}
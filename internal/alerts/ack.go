@@ -0,0 +1,78 @@
+package alerts
+
+import "time"
+
+// AckStore persists alert acknowledgments and snoozes so they survive a
+// restart. Keys are rule:session alert keys; a zero until means
+// acknowledged until the rule stops triggering.
+type AckStore interface {
+	LoadAlertAcks() map[string]time.Time
+	SaveAlertAck(key string, until time.Time)
+	DeleteAlertAck(key string)
+}
+
+// WithAckStore sets the store that acknowledgments and snoozes are loaded
+// from and saved to.
+func WithAckStore(s AckStore) EngineOption {
+	return func(e *Engine) {
+		e.ackStore = s
+	}
+}
+
+// Acknowledge marks the alert's rule and session silenced in the fired
+// alerts and keeps it from firing again until the rule stops triggering
+// for that session.
+func (e *Engine) Acknowledge(a Alert) {
+	e.silence(a.alertKey(), time.Time{})
+}
+
+// Snooze marks the alert's rule and session silenced in the fired alerts
+// and keeps it from firing again for d.
+func (e *Engine) Snooze(a Alert, d time.Duration) {
+	e.silence(a.alertKey(), e.clock.Now().Add(d))
+}
+
+func (e *Engine) silence(key string, until time.Time) {
+	e.mu.Lock()
+	e.acks[key] = until
+	for i := range e.alerts {
+		if e.alerts[i].alertKey() == key {
+			e.alerts[i].Silenced = true
+		}
+	}
+	e.mu.Unlock()
+
+	if e.ackStore != nil {
+		e.ackStore.SaveAlertAck(key, until)
+	}
+}
+
+// silenced reports whether alerts with key are acknowledged or snoozed at
+// now.
+func (e *Engine) silenced(key string, now time.Time) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	until, ok := e.acks[key]
+	return ok && (until.IsZero() || now.Before(until))
+}
+
+// expireAcks drops snoozes that have run out and acknowledgments whose rule
+// no longer triggers, given the alert keys triggered at now.
+func (e *Engine) expireAcks(triggered map[string]bool, now time.Time) {
+	var expired []string
+	e.mu.Lock()
+	for key, until := range e.acks {
+		if (until.IsZero() && !triggered[key]) || (!until.IsZero() && !now.Before(until)) {
+			delete(e.acks, key)
+			expired = append(expired, key)
+		}
+	}
+	e.mu.Unlock()
+
+	if e.ackStore != nil {
+		for _, key := range expired {
+			e.ackStore.DeleteAlertAck(key)
+		}
+	}
+}
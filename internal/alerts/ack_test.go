@@ -0,0 +1,252 @@
+package alerts
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// toggleRule fires one SessionCost alert for sess-1 while on is set.
+type toggleRule struct{ on bool }
+
+func (r *toggleRule) Evaluate(_ state.Store, now time.Time) []Alert {
+	if !r.on {
+		return nil
+	}
+	return []Alert{{Rule: RuleSessionCost, Severity: SeverityWarning, SessionID: "sess-1", FiredAt: now}}
+}
+
+type memAckStore struct {
+	mu   sync.Mutex
+	acks map[string]time.Time
+}
+
+func (s *memAckStore) LoadAlertAcks() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]time.Time, len(s.acks))
+	for k, v := range s.acks {
+		out[k] = v
+	}
+	return out
+}
+
+func (s *memAckStore) SaveAlertAck(key string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acks[key] = until
+}
+
+func (s *memAckStore) DeleteAlertAck(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.acks, key)
+}
+
+func newToggleEngine(t *testing.T, acks *memAckStore) (*Engine, *toggleRule) {
+	t.Helper()
+	engine := NewEngine(state.NewMemoryStore(), defaultTestConfig(), newTestCalculator(),
+		WithDedupTTL(time.Nanosecond), WithAckStore(acks))
+	rule := &toggleRule{on: true}
+	engine.rules = []Rule{rule}
+	return engine, rule
+}
+
+// unsilenced returns the fired alerts that are not acknowledged, snoozed
+// or silenced: those shown in the alert bar.
+func unsilenced(e *Engine) []Alert {
+	var result []Alert
+	for _, a := range e.Alerts() {
+		if !a.Silenced {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func TestEngine_AcknowledgeUntilRuleClears(t *testing.T) {
+	acks := &memAckStore{acks: map[string]time.Time{}}
+	engine, rule := newToggleEngine(t, acks)
+	now := time.Now()
+
+	engine.EvaluateAt(now)
+	if len(engine.Alerts()) != 1 {
+		t.Fatalf("expected one alert, got %d", len(engine.Alerts()))
+	}
+
+	engine.Acknowledge(engine.Alerts()[0])
+	if got := engine.Alerts(); len(got) != 1 || !got[0].Silenced {
+		t.Fatalf("acknowledged alert should stay fired and be marked silenced, got %+v", got)
+	}
+	if _, ok := acks.LoadAlertAcks()["SessionCost:sess-1"]; !ok {
+		t.Fatal("acknowledgment should be persisted")
+	}
+
+	engine.EvaluateAt(now.Add(time.Minute))
+	if len(engine.Alerts()) != 1 {
+		t.Fatal("acknowledged alert should not re-fire while the rule still triggers")
+	}
+
+	// A restarted engine picks up the acknowledgment.
+	restarted, _ := newToggleEngine(t, acks)
+	restarted.EvaluateAt(now.Add(2 * time.Minute))
+	if len(restarted.Alerts()) != 0 {
+		t.Fatal("persisted acknowledgment should hold after a restart")
+	}
+
+	rule.on = false
+	engine.EvaluateAt(now.Add(3 * time.Minute))
+	rule.on = true
+	engine.EvaluateAt(now.Add(4 * time.Minute))
+	if len(unsilenced(engine)) != 1 {
+		t.Errorf("alert should fire again once the rule has cleared, got %d", len(unsilenced(engine)))
+	}
+	if _, ok := acks.LoadAlertAcks()["SessionCost:sess-1"]; ok {
+		t.Error("cleared acknowledgment should be deleted from the store")
+	}
+}
+
+func TestEngine_SnoozeExpires(t *testing.T) {
+	acks := &memAckStore{acks: map[string]time.Time{}}
+	engine, _ := newToggleEngine(t, acks)
+	now := time.Now()
+
+	engine.EvaluateAt(now)
+	engine.Snooze(engine.Alerts()[0], 30*time.Minute)
+	if len(unsilenced(engine)) != 0 || len(engine.Alerts()) != 1 {
+		t.Fatal("snoozed alert should stay fired and be marked silenced")
+	}
+
+	engine.EvaluateAt(now.Add(10 * time.Minute))
+	if len(engine.Alerts()) != 1 {
+		t.Fatal("snoozed alert should not fire during the snooze")
+	}
+
+	engine.EvaluateAt(now.Add(31 * time.Minute))
+	if len(unsilenced(engine)) != 1 {
+		t.Errorf("alert should fire again after the snooze, got %d", len(unsilenced(engine)))
+	}
+}
+
+func TestEngine_Escalation(t *testing.T) {
+	cfg := defaultTestConfig()
+	cfg.Alerts.Escalation = map[string]config.EscalationPolicy{
+		RuleSessionCost: {AfterMinutes: 10, Severity: SeverityCritical, Channels: []string{"pager"}},
+	}
+	notifier := newTestNotifier()
+	engine := NewEngine(state.NewMemoryStore(), cfg, newTestCalculator(), WithNotifier(notifier))
+	rule := &toggleRule{on: true}
+	engine.rules = []Rule{rule}
+	now := time.Now()
+
+	escalations := func() []Alert {
+		notifier.mu.Lock()
+		defer notifier.mu.Unlock()
+		var out []Alert
+		for _, a := range notifier.alerts {
+			if len(a.Channels) > 0 {
+				out = append(out, a)
+			}
+		}
+		return out
+	}
+
+	for m := 0; m < 10; m++ {
+		engine.EvaluateAt(now.Add(time.Duration(m) * time.Minute))
+	}
+	if n := len(escalations()); n != 0 {
+		t.Fatalf("escalated after 9 minutes: %d", n)
+	}
+	engine.EvaluateAt(now.Add(10 * time.Minute))
+	engine.EvaluateAt(now.Add(11 * time.Minute))
+	esc := escalations()
+	if len(esc) != 1 {
+		t.Fatalf("expected one escalation after 10 minutes, got %d", len(esc))
+	}
+	if esc[0].Severity != SeverityCritical || esc[0].Channels[0] != "pager" || !strings.HasPrefix(esc[0].Message, "Escalated after 10 min: ") {
+		t.Errorf("unexpected escalation %+v", esc[0])
+	}
+
+	// Once the rule clears, the alert has to stay active for the full delay
+	// again.
+	rule.on = false
+	engine.EvaluateAt(now.Add(12 * time.Minute))
+	rule.on = true
+	engine.EvaluateAt(now.Add(13 * time.Minute))
+	engine.EvaluateAt(now.Add(22 * time.Minute))
+	if n := len(escalations()); n != 1 {
+		t.Errorf("escalated again before the delay: %d escalations", n)
+	}
+	engine.EvaluateAt(now.Add(23 * time.Minute))
+	if n := len(escalations()); n != 2 {
+		t.Errorf("expected a second escalation, got %d", n)
+	}
+
+	// Acknowledged alerts don't escalate.
+	engine.Acknowledge(Alert{Rule: RuleSessionCost, SessionID: "sess-1"})
+	engine.EvaluateAt(now.Add(40 * time.Minute))
+	if n := len(escalations()); n != 2 {
+		t.Errorf("acknowledged alert escalated: %d escalations", n)
+	}
+}
+
+// memSilenceStore serves fixed silences.
+type memSilenceStore struct {
+	silences []Silence
+	loads    int
+}
+
+func (s *memSilenceStore) LoadAlertSilences(now time.Time) []Silence {
+	s.loads++
+	var result []Silence
+	for _, sl := range s.silences {
+		if now.Before(sl.Until) {
+			result = append(result, sl)
+		}
+	}
+	return result
+}
+
+func TestEngine_Silences(t *testing.T) {
+	now := time.Now()
+	silences := &memSilenceStore{}
+	notifier := newTestNotifier()
+	engine := NewEngine(state.NewMemoryStore(), defaultTestConfig(), newTestCalculator(),
+		WithNotifier(notifier), WithSilenceStore(silences), WithDedupTTL(time.Nanosecond))
+	engine.rules = []Rule{&toggleRule{on: true}}
+
+	engine.EvaluateAt(now)
+	if len(engine.Alerts()) != 1 {
+		t.Fatalf("expected the alert to fire, got %d", len(engine.Alerts()))
+	}
+
+	// A silence created meanwhile (by cc-top silence) is read within the
+	// reload interval and clears the active alert.
+	silences.silences = []Silence{{ID: 1, Rule: RuleSessionCost, Reason: "load test", Until: now.Add(time.Hour)}}
+	engine.EvaluateAt(now.Add(time.Second))
+	if silences.loads != 1 {
+		t.Errorf("silences reloaded before the interval: %d loads", silences.loads)
+	}
+	engine.EvaluateAt(now.Add(silenceReloadInterval))
+	if len(unsilenced(engine)) != 0 || len(engine.Alerts()) == 0 {
+		t.Errorf("silenced rule's alerts should stay fired and be marked silenced, got %+v", engine.Alerts())
+	}
+	n := notifier.count()
+	engine.EvaluateAt(now.Add(silenceReloadInterval + time.Second))
+	if notifier.count() != n {
+		t.Error("silenced rule notified")
+	}
+	if got := engine.Silences(); len(got) != 1 || got[0].Reason != "load test" {
+		t.Errorf("Silences() = %+v", got)
+	}
+
+	// Once the silence ends the rule fires again.
+	engine.EvaluateAt(now.Add(time.Hour + silenceReloadInterval))
+	if len(unsilenced(engine)) != 1 || len(engine.Silences()) != 0 {
+		t.Errorf("after the silence: %d alerts, %d silences", len(unsilenced(engine)), len(engine.Silences()))
+	}
+}
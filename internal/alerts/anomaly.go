@@ -0,0 +1,52 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// anomalousSpendRule fires when the current hourly burn rate is more than
+// the configured number of standard deviations above the rolling baseline of
+// recent hourly spend. Unlike CostSurge it adapts to how much is normally
+// spent, so it needs no fixed threshold.
+type anomalousSpendRule struct {
+	stddevs    float64
+	calculator *burnrate.Calculator
+	baseline   BaselineSource
+}
+
+func newAnomalousSpendRule(cfg config.AlertsConfig, calculator *burnrate.Calculator, baseline BaselineSource) *anomalousSpendRule {
+	return &anomalousSpendRule{
+		stddevs:    cfg.AnomalousSpendStdDevs,
+		calculator: calculator,
+		baseline:   baseline,
+	}
+}
+
+func (r *anomalousSpendRule) Evaluate(store state.Store, now time.Time) []Alert {
+	if r.baseline == nil {
+		return nil
+	}
+	b, ok := r.baseline.SpendBaseline()
+	// With no spread every change would be infinitely anomalous.
+	if !ok || b.StdDevPerHour <= 0 {
+		return nil
+	}
+
+	br := r.calculator.ComputeWithTime(store, now)
+	deviation := (br.HourlyRate - b.MeanPerHour) / b.StdDevPerHour
+	if deviation <= r.stddevs {
+		return nil
+	}
+	return []Alert{{
+		Rule:     RuleAnomalousSpend,
+		Severity: SeverityWarning,
+		Message: fmt.Sprintf("Anomalous spend: $%.2f/hr is %.1f standard deviations above the baseline of $%.2f/hr (±$%.2f, %d active hours)",
+			br.HourlyRate, deviation, b.MeanPerHour, b.StdDevPerHour, b.Hours),
+		FiredAt: now,
+	}}
+}
@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// BudgetSource supplies the current weekly/monthly budget statuses.
+type BudgetSource interface {
+	Status(now time.Time) []budget.Status
+}
+
+// budgetThresholdRule fires once per budget period for each configured
+// percentage crossed. When several are crossed at once, as after a restart,
+// only the highest is reported. All periods crossing a threshold in the same
+// evaluation share one alert, since global alerts are deduplicated by rule.
+type budgetThresholdRule struct {
+	source      BudgetSource
+	percentages []float64
+	fired       map[string]bool // period + start date + percentage
+}
+
+func newBudgetThresholdRule(source BudgetSource, percentages []float64) *budgetThresholdRule {
+	pcts := slices.Clone(percentages)
+	slices.Sort(pcts)
+	return &budgetThresholdRule{source: source, percentages: pcts, fired: make(map[string]bool)}
+}
+
+func (r *budgetThresholdRule) inheritState(prev Rule) {
+	if p, ok := prev.(*budgetThresholdRule); ok {
+		r.fired = p.fired
+	}
+}
+
+func (r *budgetThresholdRule) Evaluate(_ state.Store, now time.Time) []Alert {
+	if r.source == nil || len(r.percentages) == 0 {
+		return nil
+	}
+
+	var parts []string
+	severity := SeverityWarning
+	for _, s := range r.source.Status(now) {
+		used := s.Percent()
+		crossed := -1
+		for i, p := range r.percentages {
+			if used >= p {
+				crossed = i
+			}
+		}
+		if crossed < 0 {
+			continue
+		}
+		key := func(p float64) string {
+			return fmt.Sprintf("%s:%s:%g", s.Period, s.Start.Format("2006-01-02"), p)
+		}
+		if r.fired[key(r.percentages[crossed])] {
+			continue
+		}
+		for _, p := range r.percentages[:crossed+1] {
+			r.fired[key(p)] = true
+		}
+
+		p := r.percentages[crossed]
+		if p >= 100 {
+			severity = SeverityCritical
+		}
+		parts = append(parts, fmt.Sprintf("%s%s budget %g%% used: $%.2f of $%.2f",
+			strings.ToUpper(s.Period[:1]), s.Period[1:], p, s.Spent, s.Limit))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return []Alert{{
+		Rule:     RuleBudgetThreshold,
+		Severity: severity,
+		Message:  strings.Join(parts, "; "),
+		FiredAt:  now,
+	}}
+}
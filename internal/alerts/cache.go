@@ -0,0 +1,118 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// cacheTTL is the lifetime of an idle prompt cache entry. A rewrite after a
+// longer pause is ordinary expiry, not a prompt change.
+const cacheTTL = 5 * time.Minute
+
+// cacheInvalidationRule fires when a session's prompt cache keeps being
+// rewritten: an API request writes more cache than it reads right after a
+// request to the same model that read from the cache. Each rewrite is
+// attributed to its likely cause, a changed prompt prefix when a user prompt
+// came in between, or a system prompt or tool definition change mid-turn.
+type cacheInvalidationRule struct {
+	count  int
+	window time.Duration
+}
+
+func newCacheInvalidationRule(cfg config.AlertsConfig) *cacheInvalidationRule {
+	return &cacheInvalidationRule{
+		count:  cfg.CacheInvalidationCount,
+		window: time.Duration(cfg.CacheInvalidationWindowMinutes) * time.Minute,
+	}
+}
+
+func (r *cacheInvalidationRule) Evaluate(store state.Store, now time.Time) []Alert {
+	cutoff := now.Add(-r.window)
+	var alerts []Alert
+
+	for _, session := range store.ListSessions() {
+		var afterPrompt, midTurn int
+		var rewritten int64
+
+		type lastRequest struct {
+			at   time.Time
+			warm bool // read from the cache
+		}
+		last := make(map[string]lastRequest) // model -> previous request
+		var lastPrompt time.Time
+
+		for _, evt := range session.Events {
+			switch evt.Name {
+			case "claude_code.user_prompt":
+				lastPrompt = evt.Timestamp
+				continue
+			case "claude_code.api_request":
+			default:
+				continue
+			}
+
+			model := evt.Attributes["model"]
+			read := intAttr(evt.Attributes, "cache_read_tokens")
+			created := intAttr(evt.Attributes, "cache_creation_tokens")
+			prev, seen := last[model]
+			last[model] = lastRequest{at: evt.Timestamp, warm: read > 0}
+
+			if !seen || !prev.warm || created <= read || evt.Timestamp.Sub(prev.at) > cacheTTL {
+				continue
+			}
+			if evt.Timestamp.Before(cutoff) {
+				continue
+			}
+			rewritten += created
+			if lastPrompt.After(prev.at) {
+				afterPrompt++
+			} else {
+				midTurn++
+			}
+		}
+
+		total := afterPrompt + midTurn
+		if total < r.count {
+			continue
+		}
+		var causes []string
+		if afterPrompt > 0 {
+			causes = append(causes, fmt.Sprintf("%d after a user prompt changed the prompt prefix", afterPrompt))
+		}
+		if midTurn > 0 {
+			causes = append(causes, fmt.Sprintf("%d mid-turn from a system prompt or tool change", midTurn))
+		}
+		alerts = append(alerts, Alert{
+			Rule:      RuleCacheInvalidation,
+			Severity:  SeverityInfo,
+			SessionID: session.SessionID,
+			Message: fmt.Sprintf("Cache invalidated %d times in the last %s (%s); %d tokens re-written to the cache",
+				total, formatWindow(r.window), strings.Join(causes, ", "), rewritten),
+			FiredAt: now,
+		})
+	}
+
+	return alerts
+}
+
+// intAttr parses a numeric event attribute, returning 0 when it is absent.
+func intAttr(attrs map[string]string, name string) int64 {
+	n, _ := strconv.ParseInt(attrs[name], 10, 64)
+	return n
+}
+
+// formatWindow renders a rule window as "hour" or "N minutes".
+func formatWindow(d time.Duration) string {
+	if d == time.Hour {
+		return "hour"
+	}
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%d minutes", int(d/time.Minute))
+}
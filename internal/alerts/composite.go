@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// compositeRule evaluates a [[alerts.composite]] rule. It does not look at
+// the store: it combines the alerts the other rules raised in the same
+// evaluation, and fires for a session when every rule in All and at least
+// one rule in Any hold for it. A global alert holds for every session.
+type compositeRule struct {
+	cfg config.CompositeRuleConfig
+}
+
+func newCompositeRule(cfg config.CompositeRuleConfig) *compositeRule {
+	return &compositeRule{cfg: cfg}
+}
+
+// evaluate returns the composite's alerts, given the alerts raised this
+// evaluation keyed by rule name.
+func (r *compositeRule) evaluate(fired map[string][]Alert, now time.Time) []Alert {
+	sessions := make(map[string]bool)
+	for _, member := range slices.Concat(r.cfg.All, r.cfg.Any) {
+		for _, a := range fired[member] {
+			sessions[a.SessionID] = true
+		}
+	}
+	ids := make([]string, 0, len(sessions))
+	for id := range sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var alerts []Alert
+	for _, id := range ids {
+		var matched []Alert
+		perSession := false
+		holds := func(member string) bool {
+			for _, a := range fired[member] {
+				if a.SessionID == id || a.SessionID == "" {
+					matched = append(matched, a)
+					perSession = perSession || a.SessionID != ""
+					return true
+				}
+			}
+			return false
+		}
+
+		ok := true
+		for _, member := range r.cfg.All {
+			if !holds(member) {
+				ok = false
+				break
+			}
+		}
+		if ok && len(r.cfg.Any) > 0 {
+			ok = false
+			for _, member := range r.cfg.Any {
+				// Keep going to list every member that holds.
+				if holds(member) {
+					ok = true
+				}
+			}
+		}
+		// A session made up only of global alerts is the global alert.
+		if !ok || (id != "" && !perSession) {
+			continue
+		}
+
+		msg := r.cfg.Message
+		if msg == "" {
+			msg = r.cfg.Name
+		}
+		parts := make([]string, len(matched))
+		for i, a := range matched {
+			parts[i] = a.Message
+		}
+		alerts = append(alerts, Alert{
+			Rule:      r.cfg.Name,
+			Severity:  r.cfg.Severity,
+			SessionID: id,
+			Message:   fmt.Sprintf("%s: %s", msg, strings.Join(parts, "; ")),
+			FiredAt:   now,
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,198 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// customRule evaluates a user-defined [[alerts.custom]] rule: it aggregates
+// the matching metric samples or events over a window, per session or across
+// all sessions, and fires when the comparison with the threshold holds.
+type customRule struct {
+	cfg    config.CustomRuleConfig
+	window time.Duration // 0 means the whole session
+}
+
+func newCustomRule(cfg config.CustomRuleConfig) *customRule {
+	return &customRule{
+		cfg:    cfg,
+		window: time.Duration(cfg.WindowMinutes) * time.Minute,
+	}
+}
+
+func (r *customRule) Evaluate(store state.Store, now time.Time) []Alert {
+	sessions := store.ListSessions()
+
+	if r.cfg.Scope == "global" {
+		var agg aggregate
+		for i := range sessions {
+			r.collect(&agg, &sessions[i], now)
+		}
+		if a, ok := r.check(agg, "", now); ok {
+			return []Alert{a}
+		}
+		return nil
+	}
+
+	var alerts []Alert
+	for i := range sessions {
+		if sessions[i].Exited {
+			continue
+		}
+		var agg aggregate
+		r.collect(&agg, &sessions[i], now)
+		if a, ok := r.check(agg, sessions[i].SessionID, now); ok {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
+
+// aggregate accumulates the values a custom rule compares.
+type aggregate struct {
+	n             int
+	sum, min, max float64
+}
+
+func (a *aggregate) add(v float64) {
+	if a.n == 0 || v < a.min {
+		a.min = v
+	}
+	if a.n == 0 || v > a.max {
+		a.max = v
+	}
+	a.n++
+	a.sum += v
+}
+
+// inWindow reports whether ts falls inside the rule's window ending at now.
+func (r *customRule) inWindow(ts, now time.Time) bool {
+	return r.window == 0 || !ts.Before(now.Add(-r.window))
+}
+
+// matches reports whether attrs satisfy every where filter.
+func (r *customRule) matches(attrs map[string]string) bool {
+	for k, v := range r.cfg.Where {
+		if attrs[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// collect adds the session's matching data points in the window to agg.
+func (r *customRule) collect(agg *aggregate, s *state.SessionData, now time.Time) {
+	if r.cfg.Event != "" {
+		for _, e := range s.Events {
+			if e.Name != r.cfg.Event || !r.inWindow(e.Timestamp, now) || !r.matches(e.Attributes) {
+				continue
+			}
+			if r.cfg.Aggregation == "count" {
+				agg.add(1)
+				continue
+			}
+			if v, err := strconv.ParseFloat(e.Attributes[r.cfg.Field], 64); err == nil {
+				agg.add(v)
+			}
+		}
+		return
+	}
+
+	// Metrics are cumulative counters per attribute series; increase replays
+	// them the same way the state store does, including counter resets.
+	prev := make(map[string]float64)
+	for _, m := range s.Metrics {
+		if m.Name != r.cfg.Metric || !r.matches(m.Attributes) {
+			continue
+		}
+		value := m.Value
+		if r.cfg.Aggregation == "increase" {
+			key := state.MetricKey(m.Name, m.Attributes)
+			last, ok := prev[key]
+			prev[key] = m.Value
+			if ok && m.Value >= last {
+				value = m.Value - last
+			}
+		}
+		if r.inWindow(m.Timestamp, now) {
+			agg.add(value)
+		}
+	}
+}
+
+// check compares the aggregated value with the threshold and builds the
+// alert when it holds.
+func (r *customRule) check(agg aggregate, sessionID string, now time.Time) (Alert, bool) {
+	var value float64
+	switch r.cfg.Aggregation {
+	case "count":
+		value = float64(agg.n)
+	case "sum", "increase":
+		value = agg.sum
+	case "avg", "min", "max":
+		if agg.n == 0 {
+			return Alert{}, false
+		}
+		switch r.cfg.Aggregation {
+		case "avg":
+			value = agg.sum / float64(agg.n)
+		case "min":
+			value = agg.min
+		default:
+			value = agg.max
+		}
+	}
+	if !compare(value, r.cfg.Op, r.cfg.Threshold) {
+		return Alert{}, false
+	}
+
+	msg := r.cfg.Message
+	if msg == "" {
+		msg = r.cfg.Name
+	}
+	return Alert{
+		Rule:      r.cfg.Name,
+		Severity:  r.cfg.Severity,
+		SessionID: sessionID,
+		Message:   fmt.Sprintf("%s: %s = %g (%s %g)", msg, r.describe(), value, r.cfg.Op, r.cfg.Threshold),
+		FiredAt:   now,
+	}, true
+}
+
+// describe renders the aggregation, e.g. "count(claude_code.api_error) over 5m".
+func (r *customRule) describe() string {
+	source := r.cfg.Metric
+	if r.cfg.Event != "" {
+		source = r.cfg.Event
+		if r.cfg.Field != "" && r.cfg.Aggregation != "count" {
+			source += "." + r.cfg.Field
+		}
+	}
+	d := r.cfg.Aggregation + "(" + source + ")"
+	if r.window > 0 {
+		d += fmt.Sprintf(" over %dm", r.cfg.WindowMinutes)
+	}
+	return d
+}
+
+func compare(v float64, op string, threshold float64) bool {
+	switch op {
+	case ">":
+		return v > threshold
+	case ">=":
+		return v >= threshold
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	case "==":
+		return v == threshold
+	case "!=":
+		return v != threshold
+	}
+	return false
+}
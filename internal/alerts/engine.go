@@ -5,10 +5,15 @@ package alerts
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/clock"
 	"github.com/nixlim/cc-top/internal/config"
 	"github.com/nixlim/cc-top/internal/state"
 )
@@ -18,16 +23,57 @@ import (
 // sends macOS notifications via the configured Notifier.
 type Engine struct {
 	store      state.Store
+	calculator *burnrate.Calculator
+
+	// ruleMu guards the rules, composites, muted, suppress and quiet, which
+	// Reload replaces while evaluation runs.
+	ruleMu     sync.RWMutex
 	rules      []Rule
-	notifier   Notifier
-	persister  AlertPersister
-	interval   time.Duration
-	dedupTTL   time.Duration
+	composites []*compositeRule
+	muted      map[string]bool // rules that only feed composites
+	suppress   suppressor
+	escalation map[string]config.EscalationPolicy
+	quiet      quietHours
+
+	// evalMu serializes evaluations with Reload handing the state of the
+	// old rules to the new ones.
+	evalMu sync.Mutex
+
+	// muteNotify holds back every notification (SetNotificationsMuted).
+	muteNotify atomic.Bool
+
+	notifier     Notifier
+	persister    AlertPersister
+	ackStore     AckStore
+	silenceStore SilenceStore
+	thresholds   ThresholdSource
+	baseline     BaselineSource
+	slaTimers    *SLATimers
+	budgets      BudgetSource
+	records      RecordSource
+	interval     time.Duration
+	dedupTTL     time.Duration
+	clock        clock.Clock
 
 	mu         sync.RWMutex
 	alerts     []Alert
 	lastFired  map[string]time.Time // alertKey -> last fire time for dedup
 
+	// acks maps an alertKey to its snooze end; zero means acknowledged.
+	// Guarded by mu.
+	acks map[string]time.Time
+
+	// activeSince maps the alertKey of each alert raised in the last
+	// evaluation to when it started being raised; escalated holds those
+	// already escalated. Guarded by mu.
+	activeSince map[string]time.Time
+	escalated   map[string]bool
+
+	// silences are those read from silenceStore at silencesLoaded.
+	// Guarded by mu.
+	silences       []Silence
+	silencesLoaded time.Time
+
 	cancel     context.CancelFunc
 	done       chan struct{}
 }
@@ -64,35 +110,174 @@ func WithPersister(p AlertPersister) EngineOption {
 	}
 }
 
-// NewEngine creates a new alert engine with all built-in rules configured
-// from the provided config. The calculator is used for cost/token rate rules.
+// WithThresholdSource sets the source of history-derived thresholds used by
+// the CostSurge and RunawayTokens rules when their auto mode is enabled.
+func WithThresholdSource(src ThresholdSource) EngineOption {
+	return func(e *Engine) {
+		e.thresholds = src
+	}
+}
+
+// WithBaselineSource sets the rolling spend baseline used by the
+// AnomalousSpend rule. Without it the rule never fires.
+func WithBaselineSource(src BaselineSource) EngineOption {
+	return func(e *Engine) {
+		e.baseline = src
+	}
+}
+
+// WithProjectFunc sets how session project directories are resolved for
+// project suppressions (default: the session's CWD).
+func WithProjectFunc(fn ProjectFunc) EngineOption {
+	return func(e *Engine) {
+		e.suppress.project = fn
+	}
+}
+
+// WithSLATimers sets the runtime expected durations used by the SLAOverrun
+// rule in addition to the cc_top.expected_duration resource attribute.
+func WithSLATimers(t *SLATimers) EngineOption {
+	return func(e *Engine) {
+		e.slaTimers = t
+	}
+}
+
+// WithBudgetSource sets the budget statuses checked by the BudgetThreshold
+// rule. Without it the rule never fires.
+func WithBudgetSource(src BudgetSource) EngineOption {
+	return func(e *Engine) {
+		e.budgets = src
+	}
+}
+
+// WithRecordSource sets the all-time records checked by the RecordBroken
+// rule. Without it the rule never fires.
+func WithRecordSource(src RecordSource) EngineOption {
+	return func(e *Engine) {
+		e.records = src
+	}
+}
+
+// WithClock sets the clock that times evaluations and snoozes. The ticker
+// driving Start stays on the wall clock; tests advance a clock.Fake and
+// call EvaluateNow instead.
+func WithClock(c clock.Clock) EngineOption {
+	return func(e *Engine) {
+		e.clock = c
+	}
+}
+
+// NewEngine creates a new alert engine with all built-in rules and any
+// [[alerts.custom]] rules configured from the provided config. The calculator is used for cost/token rate rules.
 func NewEngine(store state.Store, cfg config.Config, calculator *burnrate.Calculator, opts ...EngineOption) *Engine {
 	e := &Engine{
-		store:     store,
-		interval:  1 * time.Second,
-		dedupTTL:  60 * time.Second,
-		lastFired: make(map[string]time.Time),
-		done:      make(chan struct{}),
+		store:      store,
+		calculator: calculator,
+		interval:   1 * time.Second,
+		dedupTTL:   60 * time.Second,
+		clock:      clock.Real{},
+		lastFired:  make(map[string]time.Time),
+		acks:       make(map[string]time.Time),
+		done:       make(chan struct{}),
+
+		activeSince: make(map[string]time.Time),
+		escalated:   make(map[string]bool),
 	}
 
 	for _, opt := range opts {
 		opt(e)
 	}
+	if e.ackStore != nil {
+		for key, until := range e.ackStore.LoadAlertAcks() {
+			e.acks[key] = until
+		}
+	}
+
+	e.applyConfig(cfg)
+	return e
+}
 
+// Reload replaces the rules with ones built from cfg, so changed thresholds,
+// custom and composite rules, suppressions and quiet hours apply from the next
+// evaluation. Fired, acknowledged and deduplicated alerts are kept, and so is
+// the state of stateful rules, such as the budget thresholds and SLA timers
+// already alerted on.
+func (e *Engine) Reload(cfg config.Config) {
+	e.applyConfig(cfg)
+}
+
+// applyConfig builds the built-in, custom and composite rules from cfg.
+func (e *Engine) applyConfig(cfg config.Config) {
+	calculator := e.calculator
 	normalizer := defaultNormalizer{}
 
-	e.rules = []Rule{
-		newCostSurgeRule(cfg.Alerts, calculator),
-		newRunawayTokensRule(cfg.Alerts, calculator),
+	costSurge := newCostSurgeRule(cfg.Alerts, calculator)
+	if cfg.Alerts.CostSurgeAuto {
+		costSurge.auto = e.thresholds
+	}
+	runaway := newRunawayTokensRule(cfg.Alerts, calculator)
+	if cfg.Alerts.RunawayTokenVelocityAuto {
+		runaway.auto = e.thresholds
+	}
+
+	rules := []Rule{
+		costSurge,
+		runaway,
 		newLoopDetectorRule(cfg.Alerts, normalizer),
 		newErrorStormRule(cfg.Alerts),
 		newStaleSessionRule(cfg.Alerts),
 		newContextPressureRule(cfg.Alerts, cfg.Models),
 		newHighRejectionRule(cfg.Alerts),
 		newSessionCostRule(cfg.Alerts),
+		newSLAOverrunRule(cfg.Alerts, e.slaTimers),
+		newBudgetThresholdRule(e.budgets, cfg.Budget.AlertPercentages),
+		newCacheInvalidationRule(cfg.Alerts),
+		newToolFailureRule(cfg.Alerts),
+		newRequestBudgetRule(cfg.Alerts),
+		newAnomalousSpendRule(cfg.Alerts, calculator, e.baseline),
+		newRecordBrokenRule(e.records),
+	}
+	for _, c := range cfg.Alerts.Custom {
+		rules = append(rules, newCustomRule(c))
+	}
+	var composites []*compositeRule
+	muted := make(map[string]bool)
+	for _, c := range cfg.Alerts.Composite {
+		composites = append(composites, newCompositeRule(c))
+		if c.SuppressMembers {
+			for _, member := range slices.Concat(c.All, c.Any) {
+				muted[member] = true
+			}
+		}
 	}
 
-	return e
+	// Rules that remember what they fired take over the state of the rules
+	// they replace, so a reload does not fire their alerts again.
+	e.evalMu.Lock()
+	defer e.evalMu.Unlock()
+	for _, r := range rules {
+		if s, ok := r.(statefulRule); ok {
+			for _, prev := range e.rules {
+				s.inheritState(prev)
+			}
+		}
+	}
+
+	e.ruleMu.Lock()
+	defer e.ruleMu.Unlock()
+	e.rules, e.composites, e.muted = rules, composites, muted
+	e.suppress.byRule = cfg.Alerts.Suppressions
+	e.escalation = cfg.Alerts.Escalation
+	e.quiet = newQuietHours(cfg.Alerts.QuietHours)
+}
+
+// statefulRule is a rule that keeps state between evaluations, such as the
+// alerts it has already fired.
+type statefulRule interface {
+	Rule
+	// inheritState takes over the state of prev if it is the same kind of
+	// rule, and ignores it otherwise.
+	inheritState(prev Rule)
 }
 
 // Start begins periodic evaluation of alert rules. It runs until Stop is called
@@ -110,7 +295,7 @@ func (e *Engine) Start(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				e.evaluate(time.Now())
+				e.evaluate(e.clock.Now())
 			}
 		}
 	}()
@@ -126,26 +311,58 @@ func (e *Engine) Stop() {
 
 // evaluate runs all rules and processes any triggered alerts.
 func (e *Engine) evaluate(now time.Time) {
+	e.evalMu.Lock()
+	defer e.evalMu.Unlock()
+
 	var newAlerts []Alert
+	triggeredKeys := make(map[string]bool)
+
+	e.ruleMu.RLock()
+	rules, composites, muted, suppress, escalation := e.rules, e.composites, e.muted, e.suppress, e.escalation
+	e.ruleMu.RUnlock()
+	e.loadSilences(now)
+
+	// Composites combine what the other rules raised in this evaluation.
+	var triggered []Alert
+	fired := make(map[string][]Alert)
+	for _, rule := range rules {
+		for _, alert := range rule.Evaluate(e.store, now) {
+			fired[alert.Rule] = append(fired[alert.Rule], alert)
+			triggered = append(triggered, alert)
+		}
+	}
+	for _, c := range composites {
+		triggered = append(triggered, c.evaluate(fired, now)...)
+	}
 
-	for _, rule := range e.rules {
-		triggered := rule.Evaluate(e.store, now)
-		for _, alert := range triggered {
-			if e.isDuplicate(alert) {
-				continue
-			}
-			e.recordFired(alert)
-			newAlerts = append(newAlerts, alert)
+	active := make(map[string]Alert)
+	for _, alert := range triggered {
+		if muted[alert.Rule] {
+			continue
+		}
+		triggeredKeys[alert.alertKey()] = true
+		if suppress.suppressed(e.store, alert) || e.silenced(alert.alertKey(), now) || e.ruleSilenced(alert.Rule, now) {
+			continue
+		}
+		active[alert.alertKey()] = alert
+		if e.isDuplicate(alert) {
+			continue
 		}
+		e.recordFired(alert)
+		newAlerts = append(newAlerts, alert)
 	}
+	e.expireAcks(triggeredKeys, now)
+	newAlerts = append(newAlerts, e.escalate(active, escalation, now)...)
 
 	if len(newAlerts) > 0 {
 		e.mu.Lock()
 		e.alerts = append(e.alerts, newAlerts...)
 		e.mu.Unlock()
 
+		// Quiet hours and do-not-disturb hold back notifications only.
+		held, _ := e.notificationsHeld(now)
 		for _, alert := range newAlerts {
-			if e.notifier != nil {
+			if e.notifier != nil && !held {
 				e.notifier.Notify(alert)
 			}
 			if e.persister != nil {
@@ -155,10 +372,51 @@ func (e *Engine) evaluate(now time.Time) {
 	}
 }
 
-// EvaluateNow runs a single evaluation cycle immediately. This is primarily
-// useful for testing without waiting for the ticker.
+// escalate tracks how long each alert has been active, raised in every
+// evaluation without being suppressed or acknowledged, and returns an
+// escalated copy of those whose rule's policy delay has passed. An alert
+// escalates once until it stops being raised.
+func (e *Engine) escalate(active map[string]Alert, policies map[string]config.EscalationPolicy, now time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key := range e.activeSince {
+		if _, ok := active[key]; !ok {
+			delete(e.activeSince, key)
+			delete(e.escalated, key)
+		}
+	}
+
+	var escalated []Alert
+	for key, alert := range active {
+		since, ok := e.activeSince[key]
+		if !ok {
+			since = now
+			e.activeSince[key] = now
+		}
+		p, ok := policies[alert.Rule]
+		if !ok || e.escalated[key] || now.Sub(since) < time.Duration(p.AfterMinutes)*time.Minute {
+			continue
+		}
+		e.escalated[key] = true
+
+		alert.FiredAt = now
+		alert.Message = fmt.Sprintf("Escalated after %d min: %s", p.AfterMinutes, alert.Message)
+		if p.Severity != "" {
+			alert.Severity = p.Severity
+		}
+		alert.Channels = p.Channels
+		escalated = append(escalated, alert)
+	}
+	slices.SortFunc(escalated, func(a, b Alert) int { return strings.Compare(a.alertKey(), b.alertKey()) })
+	return escalated
+}
+
+// EvaluateNow runs a single evaluation cycle immediately, at the engine
+// clock's time. This is primarily useful for testing without waiting for
+// the ticker.
 func (e *Engine) EvaluateNow() {
-	e.evaluate(time.Now())
+	e.evaluate(e.clock.Now())
 }
 
 // EvaluateAt runs a single evaluation cycle at the specified time.
@@ -3,12 +3,16 @@ package alerts
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/nixlim/cc-top/internal/budget"
 	"github.com/nixlim/cc-top/internal/burnrate"
 	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/records"
 	"github.com/nixlim/cc-top/internal/state"
 )
 
@@ -92,6 +96,81 @@ func TestAlertCostSurge_Fires(t *testing.T) {
 	}
 }
 
+// fakeThresholdSource returns fixed auto thresholds.
+type fakeThresholdSource struct {
+	t  AutoThresholds
+	ok bool
+}
+
+func (f fakeThresholdSource) AutoThresholds() (AutoThresholds, bool) {
+	return f.t, f.ok
+}
+
+func TestAlertCostSurge_AutoThreshold(t *testing.T) {
+	cfg := defaultTestConfig()
+	cfg.Alerts.CostSurgeAuto = true
+
+	// $10 in 5 minutes = $120/hr.
+	seed := func(store *state.MemoryStore, calc *burnrate.Calculator, base time.Time) {
+		store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 0.0, Timestamp: base})
+		_ = calc.ComputeWithTime(store, base)
+		store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 10.00, Timestamp: base.Add(5 * time.Minute)})
+		_ = calc.ComputeWithTime(store, base.Add(5*time.Minute))
+	}
+
+	tests := []struct {
+		name     string
+		src      fakeThresholdSource
+		wantFire bool
+		wantAuto bool
+	}{
+		{"history above rate", fakeThresholdSource{t: AutoThresholds{CostPerHour: 200}, ok: true}, false, false},
+		{"history below rate", fakeThresholdSource{t: AutoThresholds{CostPerHour: 50}, ok: true}, true, true},
+		{"insufficient history falls back", fakeThresholdSource{t: AutoThresholds{CostPerHour: 200}, ok: false}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := state.NewMemoryStore()
+			calc := newTestCalculator()
+			engine := NewEngine(store, cfg, calc, WithThresholdSource(tt.src))
+			rule := engine.rules[0].(*costSurgeRule)
+
+			base := time.Now().Add(-6 * time.Minute)
+			seed(store, calc, base)
+
+			alerts := rule.Evaluate(store, base.Add(5*time.Minute))
+			if (len(alerts) > 0) != tt.wantFire {
+				t.Fatalf("fired = %v, want %v", len(alerts) > 0, tt.wantFire)
+			}
+			if tt.wantFire {
+				gotAuto := strings.HasSuffix(alerts[0].Message, "(auto)")
+				if gotAuto != tt.wantAuto {
+					t.Errorf("message %q: auto suffix = %v, want %v", alerts[0].Message, gotAuto, tt.wantAuto)
+				}
+			}
+		})
+	}
+}
+
+func TestAlertRunawayTokens_AutoDisabledIgnoresSource(t *testing.T) {
+	cfg := defaultTestConfig()
+	src := fakeThresholdSource{t: AutoThresholds{TokensPerMinute: 1}, ok: true}
+	engine := NewEngine(state.NewMemoryStore(), cfg, newTestCalculator(), WithThresholdSource(src))
+
+	rule := engine.rules[1].(*runawayTokensRule)
+	if got, auto := rule.currentThreshold(); auto || got != float64(cfg.Alerts.RunawayTokenVelocity) {
+		t.Errorf("currentThreshold() = (%v, %v), want configured %d", got, auto, cfg.Alerts.RunawayTokenVelocity)
+	}
+
+	cfg.Alerts.RunawayTokenVelocityAuto = true
+	engine = NewEngine(state.NewMemoryStore(), cfg, newTestCalculator(), WithThresholdSource(src))
+	rule = engine.rules[1].(*runawayTokensRule)
+	if got, auto := rule.currentThreshold(); !auto || got != 1 {
+		t.Errorf("currentThreshold() = (%v, %v), want (1, true)", got, auto)
+	}
+}
+
 func TestAlertCostSurge_BelowThreshold(t *testing.T) {
 	store := state.NewMemoryStore()
 	cfg := defaultTestConfig()
@@ -360,6 +439,90 @@ func TestAlertErrorStorm_Fires(t *testing.T) {
 	}
 }
 
+func TestAlertToolFailures(t *testing.T) {
+	store := state.NewMemoryStore()
+	cfg := defaultTestConfig() // more than 5 failures in 10 minutes
+	rule := newToolFailureRule(cfg.Alerts)
+	now := time.Now()
+
+	addResult := func(sessionID, tool, success string, ago time.Duration) {
+		store.AddEvent(sessionID, state.Event{
+			Name:       "claude_code.tool_result",
+			Attributes: map[string]string{"tool_name": tool, "success": success},
+			Timestamp:  now.Add(-ago),
+		})
+	}
+	for i := range 6 {
+		addResult("sess-1", "Bash", "false", time.Duration(i)*time.Minute)
+		addResult("sess-1", "Read", "true", time.Duration(i)*time.Minute)
+	}
+	// Five failures is not more than the threshold, and old failures don't count.
+	for i := range 5 {
+		addResult("sess-2", "Bash", "false", time.Duration(i)*time.Minute)
+		addResult("sess-2", "Bash", "false", time.Duration(20+i)*time.Minute)
+	}
+
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %+v", alerts)
+	}
+	a := alerts[0]
+	if a.Rule != RuleToolFailures || a.SessionID != "sess-1" || a.Severity != SeverityWarning {
+		t.Errorf("unexpected alert %+v", a)
+	}
+	if want := "Tool failures: Bash failed 6 times in the last 10 minutes (threshold 5)"; a.Message != want {
+		t.Errorf("message = %q, want %q", a.Message, want)
+	}
+
+	for range 7 {
+		addResult("sess-1", "Edit", "false", time.Minute)
+	}
+	alerts = rule.Evaluate(store, now)
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "Edit failed 7 times, Bash failed 6 times") {
+		t.Errorf("expected both tools in one alert, worst first, got %+v", alerts)
+	}
+}
+
+func TestAlertRequestBudget(t *testing.T) {
+	store := state.NewMemoryStore()
+	cfg := defaultTestConfig()
+	cfg.Alerts.APIRequestBudgetPerHour = 20
+	rule := newRequestBudgetRule(cfg.Alerts)
+	now := time.Now()
+
+	addRequests := func(sessionID string, n int, ago time.Duration) {
+		for range n {
+			store.AddEvent(sessionID, state.Event{
+				Name:       "claude_code.api_request",
+				Attributes: map[string]string{"cost_usd": "0.001"},
+				Timestamp:  now.Add(-ago),
+			})
+		}
+	}
+	// 21 cheap requests in the hour exceed the budget; for sess-2, only 20
+	// are within the hour.
+	addRequests("sess-1", 21, 30*time.Minute)
+	addRequests("sess-2", 20, 30*time.Minute)
+	addRequests("sess-2", 20, 2*time.Hour)
+
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %+v", alerts)
+	}
+	a := alerts[0]
+	if a.Rule != RuleRequestBudget || a.SessionID != "sess-1" || a.Severity != SeverityWarning {
+		t.Errorf("unexpected alert %+v", a)
+	}
+	if want := "API request budget: 21 requests in the last hour costing $0.02 (budget 20)"; a.Message != want {
+		t.Errorf("message = %q, want %q", a.Message, want)
+	}
+
+	cfg.Alerts.APIRequestBudgetPerHour = 0
+	if alerts := newRequestBudgetRule(cfg.Alerts).Evaluate(store, now); len(alerts) != 0 {
+		t.Errorf("a budget of 0 should disable the rule, got %+v", alerts)
+	}
+}
+
 func TestAlertStaleSession_Fires(t *testing.T) {
 	store := state.NewMemoryStore()
 	cfg := defaultTestConfig()
@@ -445,6 +608,42 @@ func TestAlertContextPressure_UnknownModel(t *testing.T) {
 	}
 }
 
+func TestAlertContextPressure_CountsCachedContext(t *testing.T) {
+	store := state.NewMemoryStore()
+	cfg := defaultTestConfig()
+
+	rule := newContextPressureRule(cfg.Alerts, cfg.Models)
+
+	now := time.Now()
+	request := func(input, cacheRead string, at time.Time) {
+		store.AddEvent("sess-1", state.Event{
+			Name: "claude_code.api_request",
+			Attributes: map[string]string{
+				"model":             "claude-sonnet-4-5-20250929",
+				"input_tokens":      input,
+				"cache_read_tokens": cacheRead,
+			},
+			Timestamp: at,
+		})
+	}
+
+	// Few fresh input tokens, but 85% of the window read from the cache.
+	request("500", "169500", now.Add(-time.Minute))
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected one ContextPressure alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "85%") {
+		t.Errorf("message should report 85%%: %q", alerts[0].Message)
+	}
+
+	// A compaction brings the context back under the threshold.
+	request("2000", "20000", now)
+	if alerts := rule.Evaluate(store, now); len(alerts) != 0 {
+		t.Errorf("expected no alert after compaction, got %d", len(alerts))
+	}
+}
+
 func TestAlertHighRejection_Fires(t *testing.T) {
 	store := state.NewMemoryStore()
 	cfg := defaultTestConfig()
@@ -538,29 +737,7 @@ func TestAlertEngine_WithStateStore(t *testing.T) {
 	}
 }
 
-func TestAlertNotification_OSAScript(t *testing.T) {
-	// Test the notifier interface and AppleScript string escaping.
-	// We don't actually run osascript in tests to avoid UI popups.
-	notifier := NewOSAScriptNotifier(false) // disabled = no-op
-
-	alert := Alert{
-		Rule:      RuleCostSurge,
-		Severity:  SeverityCritical,
-		Message:   `Cost surge: $5.00/hr exceeds threshold $2.00/hr with "special" chars`,
-		SessionID: "sess-notification-test-1234567890",
-		FiredAt:   time.Now(),
-	}
-
-	// Should not panic even with special characters.
-	notifier.Notify(alert)
-
-	// Test escaping function.
-	escaped := escapeAppleScript(`He said "hello" and \n stuff`)
-	expected := `He said \"hello\" and \\n stuff`
-	if escaped != expected {
-		t.Errorf("escapeAppleScript: expected %q, got %q", expected, escaped)
-	}
-
+func TestTruncateSessionID(t *testing.T) {
 	// Test session ID truncation.
 	truncated := truncateSessionID("sess-1234567890abcdef")
 	if len(truncated) > 16 {
@@ -571,18 +748,6 @@ func TestAlertNotification_OSAScript(t *testing.T) {
 	if short != "sess-123" {
 		t.Errorf("short session ID should not be truncated, got %q", short)
 	}
-
-	// Test with enabled notifier (will attempt osascript but that's fine in CI).
-	enabledNotifier := NewOSAScriptNotifier(true)
-	if enabledNotifier.enabled != true {
-		t.Error("expected notifier to be enabled")
-	}
-
-	// Verify the constructor works correctly.
-	disabledNotifier := NewOSAScriptNotifier(false)
-	if disabledNotifier.enabled != false {
-		t.Error("expected notifier to be disabled")
-	}
 }
 
 func TestAlertEngine_DedupPreventsRenotify(t *testing.T) {
@@ -972,3 +1137,559 @@ func TestAlertHighRejection_WindowRespected(t *testing.T) {
 		t.Errorf("expected no alert when rejections are outside window, got %d", len(alerts))
 	}
 }
+
+func TestEngine_Suppressions(t *testing.T) {
+	store := state.NewMemoryStore()
+	cfg := defaultTestConfig()
+	cfg.Alerts.Suppressions = map[string][]string{
+		RuleSessionCost: {"tag:experiment", "project:sandbox/", "env:dev"},
+	}
+	now := time.Now()
+
+	for _, id := range []string{"sess-tagged", "sess-sandbox", "sess-dev", "sess-prod"} {
+		store.AddMetric(id, state.Metric{Name: "claude_code.cost.usage", Value: 6.50, Timestamp: now})
+	}
+	store.UpdateMetadata("sess-tagged", state.SessionMetadata{Tags: []string{"demo", "experiment"}})
+	store.UpdateMetadata("sess-dev", state.SessionMetadata{Environment: "dev"})
+	store.UpdateMetadata("sess-prod", state.SessionMetadata{Environment: "prod"})
+
+	dirs := map[string]string{"sess-sandbox": "/home/dev/work/sandbox/tool", "sess-prod": "/home/dev/work/api"}
+	notifier := newTestNotifier()
+	engine := NewEngine(store, cfg, newTestCalculator(),
+		WithNotifier(notifier),
+		WithProjectFunc(func(s state.SessionData) string { return dirs[s.SessionID] }))
+	engine.EvaluateAt(now)
+
+	var fired []string
+	for _, a := range engine.Alerts() {
+		if a.Rule == RuleSessionCost {
+			fired = append(fired, a.SessionID)
+		}
+	}
+	if len(fired) != 1 || fired[0] != "sess-prod" {
+		t.Errorf("expected only sess-prod to fire SessionCost, got %v", fired)
+	}
+	if notifier.count() != len(engine.Alerts()) {
+		t.Errorf("suppressed alerts should not be notified: %d notifications, %d alerts", notifier.count(), len(engine.Alerts()))
+	}
+}
+
+func TestAlertSLAOverrun(t *testing.T) {
+	store := state.NewMemoryStore()
+	cfg := defaultTestConfig()
+	cfg.Alerts.SLAOverrunFactor = 1.5
+
+	start := time.Now()
+	store.AddMetric("sess-sla", state.Metric{Name: "claude_code.session.count", Value: 1, Timestamp: start})
+	store.UpdateMetadata("sess-sla", state.SessionMetadata{ExpectedDuration: 40 * time.Minute})
+	store.AddMetric("sess-none", state.Metric{Name: "claude_code.session.count", Value: 1, Timestamp: start})
+
+	timers := NewSLATimers()
+	rule := newSLAOverrunRule(cfg.Alerts, timers)
+
+	if alerts := rule.Evaluate(store, start.Add(55*time.Minute)); len(alerts) != 0 {
+		t.Fatalf("within 1.5x of the expected duration, got %+v", alerts)
+	}
+	alerts := rule.Evaluate(store, start.Add(61*time.Minute))
+	if len(alerts) != 1 || alerts[0].Rule != RuleSLAOverrun || alerts[0].SessionID != "sess-sla" {
+		t.Fatalf("expected one SLAOverrun alert for sess-sla, got %+v", alerts)
+	}
+	if !strings.Contains(alerts[0].Message, "expected 40m0s") {
+		t.Errorf("message should name the expected duration: %q", alerts[0].Message)
+	}
+	if alerts := rule.Evaluate(store, start.Add(90*time.Minute)); len(alerts) != 0 {
+		t.Errorf("overrun should alert once per expected duration, got %+v", alerts)
+	}
+
+	// A runtime timer overrides the attribute and re-arms the alert; a
+	// non-positive one clears it.
+	timers.SetExpectedDuration("sess-sla", time.Hour)
+	if alerts := rule.Evaluate(store, start.Add(89*time.Minute)); len(alerts) != 0 {
+		t.Errorf("new expected duration not yet overrun, got %+v", alerts)
+	}
+	if alerts := rule.Evaluate(store, start.Add(91*time.Minute)); len(alerts) != 1 {
+		t.Errorf("expected re-armed alert after 1.5h, got %+v", alerts)
+	}
+	timers.SetExpectedDuration("sess-none", 10*time.Minute)
+	timers.SetExpectedDuration("sess-none", 0)
+	if d := timers.ExpectedDuration(*store.GetSession("sess-none")); d != 0 {
+		t.Errorf("cleared timer should report 0, got %v", d)
+	}
+}
+
+func TestAlertCustomRule_EventCount(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	rule := newCustomRule(config.CustomRuleConfig{
+		Name:          "BashFailures",
+		Event:         "claude_code.tool_result",
+		Where:         map[string]string{"tool_name": "Bash", "success": "false"},
+		Aggregation:   "count",
+		WindowMinutes: 10,
+		Op:            ">=",
+		Threshold:     3,
+		Severity:      SeverityWarning,
+	})
+
+	failure := func(sessionID string, age time.Duration, tool string) {
+		store.AddEvent(sessionID, state.Event{
+			Name:       "claude_code.tool_result",
+			Attributes: map[string]string{"tool_name": tool, "success": "false"},
+			Timestamp:  now.Add(-age),
+		})
+	}
+	// sess-1: three Bash failures in the window.
+	failure("sess-1", time.Minute, "Bash")
+	failure("sess-1", 2*time.Minute, "Bash")
+	failure("sess-1", 3*time.Minute, "Bash")
+	// sess-2: only two in the window; older and non-Bash failures don't count.
+	failure("sess-2", time.Minute, "Bash")
+	failure("sess-2", 2*time.Minute, "Bash")
+	failure("sess-2", 30*time.Minute, "Bash")
+	failure("sess-2", time.Minute, "Edit")
+
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d: %+v", len(alerts), alerts)
+	}
+	a := alerts[0]
+	if a.Rule != "BashFailures" || a.SessionID != "sess-1" || a.Severity != SeverityWarning {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+	if want := "BashFailures: count(claude_code.tool_result) over 10m = 3 (>= 3)"; a.Message != want {
+		t.Errorf("message: got %q, want %q", a.Message, want)
+	}
+}
+
+func TestAlertCustomRule_MetricIncreaseGlobal(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	rule := newCustomRule(config.CustomRuleConfig{
+		Name:          "TeamSpend",
+		Metric:        "claude_code.cost.usage",
+		Aggregation:   "increase",
+		WindowMinutes: 60,
+		Op:            ">",
+		Threshold:     5,
+		Severity:      SeverityCritical,
+		Scope:         "global",
+		Message:       "Team spend over budget",
+	})
+
+	cost := func(sessionID string, value float64, age time.Duration) {
+		store.AddMetric(sessionID, state.Metric{Name: "claude_code.cost.usage", Value: value, Timestamp: now.Add(-age)})
+	}
+	// sess-1 spent $10 before the window and $3 inside it.
+	cost("sess-1", 10, 2*time.Hour)
+	cost("sess-1", 13, 10*time.Minute)
+	if alerts := rule.Evaluate(store, now); len(alerts) != 0 {
+		t.Fatalf("$3 in the window should not fire, got %+v", alerts)
+	}
+
+	// sess-2 adds $2.50 inside the window: $5.50 across sessions.
+	cost("sess-2", 2.5, 5*time.Minute)
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 global alert, got %d", len(alerts))
+	}
+	if alerts[0].SessionID != "" || !strings.HasPrefix(alerts[0].Message, "Team spend over budget: increase(claude_code.cost.usage) over 60m = 5.5") {
+		t.Errorf("unexpected alert: %+v", alerts[0])
+	}
+}
+
+func TestAlertCustomRule_EventFieldAverage(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	rule := newCustomRule(config.CustomRuleConfig{
+		Name:        "SlowAPI",
+		Event:       "claude_code.api_request",
+		Aggregation: "avg",
+		Field:       "duration_ms",
+		Op:          ">",
+		Threshold:   10000,
+		Severity:    SeverityWarning,
+	})
+
+	if alerts := rule.Evaluate(store, now); len(alerts) != 0 {
+		t.Fatal("avg over no data should not fire")
+	}
+	for _, d := range []string{"8000", "16000", "not-a-number"} {
+		store.AddEvent("sess-1", state.Event{
+			Name:       "claude_code.api_request",
+			Attributes: map[string]string{"duration_ms": d},
+			Timestamp:  now,
+		})
+	}
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "avg(claude_code.api_request.duration_ms) = 12000") {
+		t.Errorf("expected SlowAPI alert with avg 12000, got %+v", alerts)
+	}
+}
+
+func TestAlertEngine_CustomRulesRegistered(t *testing.T) {
+	store := state.NewMemoryStore()
+	cfg := defaultTestConfig()
+	cfg.Alerts.Custom = []config.CustomRuleConfig{{
+		Name: "AnyError", Event: "claude_code.api_error", Aggregation: "count",
+		Op: ">", Threshold: 0, Severity: SeverityCritical,
+	}}
+	store.AddEvent("sess-1", state.Event{Name: "claude_code.api_error", Timestamp: time.Now()})
+
+	engine := NewEngine(store, cfg, newTestCalculator())
+	engine.EvaluateNow()
+
+	var found bool
+	for _, a := range engine.Alerts() {
+		found = found || a.Rule == "AnyError"
+	}
+	if !found {
+		t.Error("custom rule did not fire through the engine")
+	}
+}
+
+// fakeBudgets returns fixed budget statuses.
+type fakeBudgets struct {
+	statuses []budget.Status
+}
+
+func (f *fakeBudgets) Status(time.Time) []budget.Status { return f.statuses }
+
+func TestEngine_BudgetThreshold(t *testing.T) {
+	monthStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	weekStart := time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)
+	src := &fakeBudgets{statuses: []budget.Status{
+		{Period: budget.Weekly, Limit: 100, Spent: 85, Start: weekStart},
+		{Period: budget.Monthly, Limit: 500, Spent: 100, Start: monthStart},
+	}}
+	rule := newBudgetThresholdRule(src, []float64{80, 50, 100})
+	now := time.Date(2026, 3, 12, 12, 0, 0, 0, time.Local)
+
+	alerts := rule.Evaluate(nil, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected one alert, got %d", len(alerts))
+	}
+	a := alerts[0]
+	if a.Rule != RuleBudgetThreshold || a.Severity != SeverityWarning || a.SessionID != "" {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+	if !strings.Contains(a.Message, "Weekly budget 80% used: $85.00 of $100.00") || strings.Contains(a.Message, "50%") {
+		t.Errorf("only the highest crossed threshold should be reported: %q", a.Message)
+	}
+
+	if again := rule.Evaluate(nil, now.Add(time.Minute)); len(again) != 0 {
+		t.Errorf("a crossed threshold should fire once per period, got %+v", again)
+	}
+
+	src.statuses[0].Spent = 120
+	src.statuses[1].Spent = 260
+	alerts = rule.Evaluate(nil, now.Add(2*time.Minute))
+	if len(alerts) != 1 || alerts[0].Severity != SeverityCritical {
+		t.Fatalf("expected a critical alert for the exhausted weekly budget, got %+v", alerts)
+	}
+	if !strings.Contains(alerts[0].Message, "Weekly budget 100% used") || !strings.Contains(alerts[0].Message, "Monthly budget 50% used") {
+		t.Errorf("both periods should be reported: %q", alerts[0].Message)
+	}
+
+	// A new week starts over.
+	src.statuses[0] = budget.Status{Period: budget.Weekly, Limit: 100, Spent: 60, Start: weekStart.AddDate(0, 0, 7)}
+	alerts = rule.Evaluate(nil, now.AddDate(0, 0, 7))
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "Weekly budget 50% used") {
+		t.Errorf("expected the 50%% threshold to fire again in a new week, got %+v", alerts)
+	}
+}
+
+func TestEngine_ReloadKeepsFiredBudgetThresholds(t *testing.T) {
+	src := &fakeBudgets{statuses: []budget.Status{
+		{Period: budget.Weekly, Limit: 100, Spent: 85, Start: time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)},
+	}}
+	notifier := newTestNotifier()
+	cfg := defaultTestConfig()
+	engine := NewEngine(state.NewMemoryStore(), cfg, newTestCalculator(),
+		WithBudgetSource(src), WithNotifier(notifier), WithDedupTTL(time.Minute))
+
+	now := time.Date(2026, 3, 12, 12, 0, 0, 0, time.Local)
+	engine.EvaluateAt(now)
+	if notifier.count() != 1 {
+		t.Fatalf("expected the 80%% threshold to notify once, got %d", notifier.count())
+	}
+
+	// A config save rebuilds the rules; once the dedup window has passed,
+	// the crossed threshold must not fire again.
+	engine.Reload(cfg)
+	engine.EvaluateAt(now.Add(2 * time.Minute))
+	if notifier.count() != 1 {
+		t.Errorf("reload fired the budget threshold again: %+v", notifier.alerts)
+	}
+}
+
+// fakeRecords returns fixed record breaks once.
+type fakeRecords struct {
+	breaks []records.Break
+}
+
+func (f *fakeRecords) Breaks(time.Time) []records.Break {
+	b := f.breaks
+	f.breaks = nil
+	return b
+}
+
+func TestEngine_RecordBroken(t *testing.T) {
+	src := &fakeRecords{breaks: []records.Break{
+		{
+			Record:   records.Record{Name: records.CostliestDay, Value: 42.1, Detail: "2026-03-12"},
+			Previous: records.Record{Name: records.CostliestDay, Value: 30, Detail: "2026-03-01"},
+		},
+		{
+			Record:   records.Record{Name: records.HourlyRate, Value: 12.3},
+			Previous: records.Record{Name: records.HourlyRate, Value: 9},
+		},
+		{
+			Record:   records.Record{Name: records.LongestSession, Value: 3 * 3600, SessionID: "sess-1"},
+			Previous: records.Record{Name: records.LongestSession, Value: 2 * 3600, SessionID: "sess-0"},
+		},
+	}}
+	rule := newRecordBrokenRule(src)
+	now := time.Date(2026, 3, 12, 12, 0, 0, 0, time.Local)
+
+	alerts := rule.Evaluate(nil, now)
+	if len(alerts) != 2 {
+		t.Fatalf("expected a session and a global alert, got %+v", alerts)
+	}
+	session, global := alerts[0], alerts[1]
+	if session.Rule != RuleRecordBroken || session.Severity != SeverityInfo || session.SessionID != "sess-1" ||
+		session.Message != "New record: longest session 3h0m (previous 2h0m)" {
+		t.Errorf("longest session alert: %+v", session)
+	}
+	if global.Severity != SeverityWarning || global.SessionID != "" ||
+		!strings.Contains(global.Message, "most expensive day $42.10 on 2026-03-12 (previous $30.00 on 2026-03-01)") ||
+		!strings.Contains(global.Message, "highest hourly rate $12.30/hr (previous $9.00/hr)") {
+		t.Errorf("global alert: %+v", global)
+	}
+
+	if again := rule.Evaluate(nil, now.Add(time.Minute)); len(again) != 0 {
+		t.Errorf("no new breaks, got %+v", again)
+	}
+}
+
+func TestAlertCacheInvalidation(t *testing.T) {
+	store := state.NewMemoryStore()
+	rule := newCacheInvalidationRule(defaultTestConfig().Alerts)
+	now := time.Now()
+	at := func(min float64) time.Time { return now.Add(time.Duration(min * float64(time.Minute))) }
+	request := func(sessionID string, ts time.Time, read, created string) {
+		store.AddEvent(sessionID, state.Event{
+			Name:      "claude_code.api_request",
+			Timestamp: ts,
+			Attributes: map[string]string{
+				"model":                 "claude-sonnet-4-5",
+				"cache_read_tokens":     read,
+				"cache_creation_tokens": created,
+			},
+		})
+	}
+	prompt := func(sessionID string, ts time.Time) {
+		store.AddEvent(sessionID, state.Event{Name: "claude_code.user_prompt", Timestamp: ts})
+	}
+
+	// Warm cache, then rewrites: two after a prompt, one mid-turn.
+	request("churn", at(-30), "0", "20000")
+	request("churn", at(-29), "20000", "500")
+	prompt("churn", at(-28))
+	request("churn", at(-27.5), "0", "21000")
+	request("churn", at(-27), "21000", "300")
+	prompt("churn", at(-26))
+	request("churn", at(-25.5), "1000", "22000")
+	request("churn", at(-25), "23000", "200")
+	request("churn", at(-24), "0", "23500")
+
+	// Rewrites after the cache expired are not invalidations.
+	request("idle", at(-50), "0", "20000")
+	request("idle", at(-49), "20000", "100")
+	request("idle", at(-40), "0", "20000")
+	request("idle", at(-39), "20000", "100")
+	request("idle", at(-30), "0", "20000")
+	request("idle", at(-29), "20000", "100")
+	request("idle", at(-20), "0", "20000")
+
+	alerts := rule.Evaluate(store, now)
+	if len(alerts) != 1 {
+		t.Fatalf("expected one CacheInvalidation alert, got %+v", alerts)
+	}
+	a := alerts[0]
+	if a.Rule != RuleCacheInvalidation || a.Severity != SeverityInfo || a.SessionID != "churn" {
+		t.Errorf("unexpected alert %+v", a)
+	}
+	for _, want := range []string{"invalidated 3 times in the last hour", "2 after a user prompt", "1 mid-turn", "66500 tokens"} {
+		if !strings.Contains(a.Message, want) {
+			t.Errorf("message %q should contain %q", a.Message, want)
+		}
+	}
+
+	// Outside the window the rewrites no longer count.
+	if alerts := rule.Evaluate(store, now.Add(time.Hour)); len(alerts) != 0 {
+		t.Errorf("expected no alert an hour later, got %+v", alerts)
+	}
+}
+
+// fakeBaselineSource returns a fixed spend baseline.
+type fakeBaselineSource struct {
+	b  SpendBaseline
+	ok bool
+}
+
+func (f fakeBaselineSource) SpendBaseline() (SpendBaseline, bool) {
+	return f.b, f.ok
+}
+
+func TestAlertAnomalousSpend(t *testing.T) {
+	cfg := defaultTestConfig() // 3 standard deviations
+
+	tests := []struct {
+		name     string
+		src      BaselineSource
+		wantFire bool
+	}{
+		{"no baseline source", nil, false},
+		{"insufficient history", fakeBaselineSource{b: SpendBaseline{MeanPerHour: 10, StdDevPerHour: 5}, ok: false}, false},
+		{"within normal spread", fakeBaselineSource{b: SpendBaseline{MeanPerHour: 100, StdDevPerHour: 10}, ok: true}, false},
+		{"far above baseline", fakeBaselineSource{b: SpendBaseline{MeanPerHour: 10, StdDevPerHour: 5, Hours: 48}, ok: true}, true},
+		{"no spread", fakeBaselineSource{b: SpendBaseline{MeanPerHour: 10}, ok: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := state.NewMemoryStore()
+			calc := newTestCalculator()
+			rule := newAnomalousSpendRule(cfg.Alerts, calc, tt.src)
+
+			// $10 in 5 minutes = $120/hr.
+			base := time.Now().Add(-6 * time.Minute)
+			store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 0.0, Timestamp: base})
+			_ = calc.ComputeWithTime(store, base)
+			store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 10.00, Timestamp: base.Add(5 * time.Minute)})
+			_ = calc.ComputeWithTime(store, base.Add(5*time.Minute))
+
+			alerts := rule.Evaluate(store, base.Add(5*time.Minute))
+			if (len(alerts) > 0) != tt.wantFire {
+				t.Fatalf("fired = %v, want %v (%+v)", len(alerts) > 0, tt.wantFire, alerts)
+			}
+			if tt.wantFire {
+				a := alerts[0]
+				if a.Rule != RuleAnomalousSpend || a.Severity != SeverityWarning {
+					t.Errorf("unexpected alert %+v", a)
+				}
+				if !strings.Contains(a.Message, "22.0 standard deviations") {
+					t.Errorf("message %q should report the deviation", a.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestCompositeRule_Evaluate(t *testing.T) {
+	now := time.Now()
+	fired := map[string][]Alert{
+		RuleCostSurge:    {{Rule: RuleCostSurge, Message: "Cost surge"}},
+		RuleErrorStorm:   {{Rule: RuleErrorStorm, SessionID: "sess-2", Message: "Error storm"}},
+		RuleLoopDetector: {{Rule: RuleLoopDetector, SessionID: "sess-3", Message: "Loop"}},
+	}
+
+	tests := []struct {
+		name     string
+		all, any []string
+		want     []string // session IDs
+	}{
+		{"and with a global member", []string{RuleCostSurge, RuleErrorStorm}, nil, []string{"sess-2"}},
+		{"and across sessions", []string{RuleErrorStorm, RuleLoopDetector}, nil, nil},
+		{"and of globals only", []string{RuleCostSurge}, nil, []string{""}},
+		{"or", nil, []string{RuleErrorStorm, RuleLoopDetector}, []string{"sess-2", "sess-3"}},
+		{"and with or", []string{RuleCostSurge}, []string{RuleErrorStorm, RuleStaleSession}, []string{"sess-2"}},
+		{"nothing fired", []string{RuleStaleSession}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := newCompositeRule(config.CompositeRuleConfig{
+				Name: "Combo", All: tt.all, Any: tt.any, Severity: SeverityCritical,
+			})
+			var got []string
+			for _, a := range rule.evaluate(fired, now) {
+				got = append(got, a.SessionID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("fired for %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_CompositeRule(t *testing.T) {
+	cfg := defaultTestConfig()
+	cfg.Alerts.Composite = []config.CompositeRuleConfig{{
+		Name:            "RetryStorm",
+		All:             []string{RuleCostSurge, RuleErrorStorm},
+		Severity:        SeverityCritical,
+		Message:         "Likely retry storm",
+		SuppressMembers: true,
+	}}
+	cfg.Alerts.SessionCostThreshold = 100
+	store := state.NewMemoryStore()
+	calc := newTestCalculator()
+	engine := NewEngine(store, cfg, calc)
+
+	// $10 in 5 minutes = $120/hr, over the $100/hr CostSurge threshold.
+	base := time.Now().Add(-6 * time.Minute)
+	store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 0, Timestamp: base})
+	_ = calc.ComputeWithTime(store, base)
+	now := base.Add(5 * time.Minute)
+	store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 10, Timestamp: now})
+	_ = calc.ComputeWithTime(store, now)
+
+	engine.EvaluateAt(now)
+	if got := engine.Alerts(); len(got) != 0 {
+		t.Fatalf("a cost surge alone should neither fire the composite nor alert on its own, got %+v", got)
+	}
+
+	for i := 0; i < 11; i++ {
+		store.AddEvent("sess-2", state.Event{
+			Name:      "claude_code.api_error",
+			Timestamp: now.Add(-time.Duration(50-i*4) * time.Second),
+		})
+	}
+	engine.EvaluateAt(now)
+	got := engine.Alerts()
+	if len(got) != 1 {
+		t.Fatalf("want only the composite alert, got %+v", got)
+	}
+	a := got[0]
+	if a.Rule != "RetryStorm" || a.SessionID != "sess-2" || a.Severity != SeverityCritical {
+		t.Errorf("unexpected alert %+v", a)
+	}
+	if !strings.HasPrefix(a.Message, "Likely retry storm: Cost surge") || !strings.Contains(a.Message, "API errors") {
+		t.Errorf("message should list both conditions, got %q", a.Message)
+	}
+}
+
+func TestEngine_Reload(t *testing.T) {
+	cfg := defaultTestConfig()
+	cfg.Alerts.SessionCostThreshold = 100
+	store := state.NewMemoryStore()
+	engine := NewEngine(store, cfg, newTestCalculator())
+
+	now := time.Now()
+	store.AddMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 10, Timestamp: now})
+	hasSessionCost := func() bool {
+		return slices.ContainsFunc(engine.Alerts(), func(a Alert) bool { return a.Rule == RuleSessionCost })
+	}
+
+	engine.EvaluateAt(now)
+	if hasSessionCost() {
+		t.Fatal("$10 session should be under the $100 threshold")
+	}
+
+	cfg.Alerts.SessionCostThreshold = 5
+	engine.Reload(cfg)
+	engine.EvaluateAt(now.Add(time.Second))
+	if !hasSessionCost() {
+		t.Error("lowering the threshold by reload should fire SessionCost")
+	}
+}
@@ -0,0 +1,82 @@
+package alerts
+
+import (
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// truncateSessionID shortens a session ID for display in notifications.
+func truncateSessionID(id string) string {
+	if len(id) <= 12 {
+		return id
+	}
+	return id[:12] + "..."
+}
+
+// System notification backends, set by [alerts.notifications] backend.
+const (
+	BackendAuto      = "auto"      // the platform's notifier, else the bell
+	BackendOSAScript = "osascript" // macOS notifications
+	BackendDBus      = "dbus"      // freedesktop notifications via notify-send
+	BackendBell      = "bell"      // terminal bell and TUI header flash
+)
+
+// NewSystemNotifier returns the notifier for desktop notifications. Where
+// the backend is not available, such as osascript on Linux or a desktop
+// without notify-send, it falls back to the terminal bell.
+func NewSystemNotifier(backend string, enabled bool) Notifier {
+	if !enabled {
+		return nopNotifier{}
+	}
+	if backend != BackendBell {
+		if n := platformNotifier(backend); n != nil {
+			return n
+		}
+		if backend != BackendAuto {
+			log.Printf("WARNING: notification backend %q is not available; using the terminal bell", backend)
+		}
+	}
+	return NewBellNotifier(os.Stderr)
+}
+
+// nopNotifier drops every notification.
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(Alert) {}
+
+// BellNotifier rings the terminal bell for each alert. It is the fallback
+// wherever no desktop notifier is available; the TUI flashes its header
+// through OnNotify.
+type BellNotifier struct {
+	out io.Writer
+
+	mu    sync.Mutex
+	flash func(Alert)
+}
+
+// NewBellNotifier creates a notifier that writes the bell character to out,
+// normally the terminal.
+func NewBellNotifier(out io.Writer) *BellNotifier {
+	return &BellNotifier{out: out}
+}
+
+// OnNotify sets a function called with every alert after the bell rings.
+func (n *BellNotifier) OnNotify(fn func(Alert)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.flash = fn
+}
+
+// Notify rings the bell.
+func (n *BellNotifier) Notify(alert Alert) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := io.WriteString(n.out, "\a"); err != nil {
+		log.Printf("WARNING: ringing terminal bell: %v", err)
+	}
+	if n.flash != nil {
+		n.flash(alert)
+	}
+}
@@ -18,6 +18,16 @@ type OSAScriptNotifier struct {
 	enabled bool
 }
 
+// platformNotifier returns the notifier of backend on macOS, or nil when it
+// is not available here.
+func platformNotifier(backend string) Notifier {
+	switch backend {
+	case BackendAuto, BackendOSAScript:
+		return NewOSAScriptNotifier(true)
+	}
+	return nil
+}
+
 // NewOSAScriptNotifier creates a new macOS notification sender.
 // If enabled is false, notifications are silently dropped.
 func NewOSAScriptNotifier(enabled bool) *OSAScriptNotifier {
@@ -75,11 +85,3 @@ func escapeAppleScript(s string) string {
 	s = strings.ReplaceAll(s, `"`, `\"`)
 	return s
 }
-
-// truncateSessionID shortens a session ID for display in notifications.
-func truncateSessionID(id string) string {
-	if len(id) <= 12 {
-		return id
-	}
-	return id[:12] + "..."
-}
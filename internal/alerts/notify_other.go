@@ -0,0 +1,57 @@
+//go:build !darwin && !windows
+
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+)
+
+// NotifySendNotifier sends freedesktop desktop notifications over DBus
+// through notify-send, as shipped with libnotify. Like OSAScriptNotifier on
+// macOS, notifications are sent from a goroutine.
+type NotifySendNotifier struct {
+	path string // notify-send executable
+}
+
+// platformNotifier returns the notifier of backend on Linux and the BSDs,
+// or nil when it is not available here. auto and dbus need notify-send.
+func platformNotifier(backend string) Notifier {
+	switch backend {
+	case BackendAuto, BackendDBus:
+		if path, err := exec.LookPath("notify-send"); err == nil {
+			return &NotifySendNotifier{path: path}
+		}
+	}
+	return nil
+}
+
+// Notify shows a desktop notification for the given alert. The call
+// returns immediately; errors are logged but do not affect the alert engine.
+func (n *NotifySendNotifier) Notify(alert Alert) {
+	args := notifySendArgs(alert)
+	go func() {
+		if err := exec.Command(n.path, args...).Run(); err != nil {
+			log.Printf("WARNING: failed to send desktop notification: %v", err)
+		}
+	}()
+}
+
+// notifySendArgs returns the notify-send arguments for alert. Critical
+// alerts stay on screen until dismissed; info alerts are low urgency.
+func notifySendArgs(alert Alert) []string {
+	urgency := "normal"
+	switch alert.Severity {
+	case SeverityCritical:
+		urgency = "critical"
+	case SeverityInfo:
+		urgency = "low"
+	}
+	body := alert.Message
+	if alert.SessionID != "" {
+		body = fmt.Sprintf("Session: %s\n%s", truncateSessionID(alert.SessionID), body)
+	}
+	// "--" keeps a message starting with "-" from being read as an option.
+	return []string{"--app-name=cc-top", "--urgency=" + urgency, "--", "cc-top: " + alert.Rule, body}
+}
@@ -0,0 +1,26 @@
+//go:build !darwin && !windows
+
+package alerts
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNotifySendArgs(t *testing.T) {
+	args := notifySendArgs(Alert{
+		Rule:      RuleErrorStorm,
+		Severity:  SeverityCritical,
+		Message:   "-5 errors in 1 minute",
+		SessionID: "sess-notification-test-1234567890",
+	})
+	want := []string{"--app-name=cc-top", "--urgency=critical", "--", "cc-top: ErrorStorm",
+		"Session: sess-notific...\n-5 errors in 1 minute"}
+	if !slices.Equal(args, want) {
+		t.Errorf("notifySendArgs = %q, want %q", args, want)
+	}
+
+	if args := notifySendArgs(Alert{Rule: RuleCacheInvalidation, Severity: SeverityInfo}); args[1] != "--urgency=low" {
+		t.Errorf("info alerts should be low urgency, got %q", args[1])
+	}
+}
@@ -0,0 +1,41 @@
+package alerts
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBellNotifier(t *testing.T) {
+	var out bytes.Buffer
+	n := NewBellNotifier(&out)
+	var flashed []Alert
+	n.OnNotify(func(a Alert) { flashed = append(flashed, a) })
+
+	n.Notify(Alert{Rule: RuleErrorStorm, Severity: SeverityCritical})
+	n.Notify(Alert{Rule: RuleCostSurge, Severity: SeverityWarning})
+	if out.String() != "\a\a" {
+		t.Errorf("expected two bells, got %q", out.String())
+	}
+	if len(flashed) != 2 || flashed[1].Rule != RuleCostSurge {
+		t.Errorf("OnNotify should see every alert, got %+v", flashed)
+	}
+}
+
+func TestNewSystemNotifier_Backends(t *testing.T) {
+	if _, ok := NewSystemNotifier(BackendAuto, false).(nopNotifier); !ok {
+		t.Error("disabled system notifications should be dropped")
+	}
+	if _, ok := NewSystemNotifier(BackendBell, true).(*BellNotifier); !ok {
+		t.Error("the bell backend should ring the bell")
+	}
+	// No platform offers every backend; the unavailable ones fall back.
+	var bells int
+	for _, b := range []string{BackendOSAScript, BackendDBus} {
+		if _, ok := NewSystemNotifier(b, true).(*BellNotifier); ok {
+			bells++
+		}
+	}
+	if bells == 0 {
+		t.Error("an unavailable backend should fall back to the bell")
+	}
+}
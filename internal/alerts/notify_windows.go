@@ -0,0 +1,98 @@
+//go:build windows
+
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// toastAppID is the AppUserModelID toasts are shown under. Windows drops
+// toasts from unregistered IDs, so cc-top borrows PowerShell's, which is
+// registered on every install.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// ToastNotifier sends Windows toast notifications through PowerShell and
+// the WinRT ToastNotificationManager. Like OSAScriptNotifier on macOS,
+// notifications are sent from a goroutine so the alert engine never waits
+// on PowerShell starting up.
+type ToastNotifier struct {
+	// enabled controls whether notifications are actually sent.
+	// When false, Notify is a no-op.
+	enabled bool
+}
+
+// platformNotifier returns the notifier of backend on Windows, or nil when
+// it is not available here. auto shows toast notifications.
+func platformNotifier(backend string) Notifier {
+	if backend == BackendAuto {
+		return NewToastNotifier(true)
+	}
+	return nil
+}
+
+// NewToastNotifier creates a new Windows toast notification sender.
+// If enabled is false, notifications are silently dropped.
+func NewToastNotifier(enabled bool) *ToastNotifier {
+	return &ToastNotifier{enabled: enabled}
+}
+
+// Notify shows a toast for the given alert. The call returns immediately;
+// errors are logged but do not affect the alert engine.
+func (n *ToastNotifier) Notify(alert Alert) {
+	if !n.enabled {
+		return
+	}
+
+	title := fmt.Sprintf("cc-top: %s", alert.Rule)
+	message := alert.Message
+	if alert.SessionID != "" {
+		message = fmt.Sprintf("Session: %s\n%s", truncateSessionID(alert.SessionID), message)
+	}
+
+	go func() {
+		if err := sendToast(title, message); err != nil {
+			log.Printf("WARNING: failed to send Windows notification: %v", err)
+		}
+	}()
+}
+
+// sendToast runs a hidden PowerShell that shows a two-line toast.
+func sendToast(title, message string) error {
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", toastScript(title, message))
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// toastScript builds the PowerShell script for a ToastText02 toast, a bold
+// title line over a wrapped message.
+func toastScript(title, message string) string {
+	return fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+$null = [Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime]
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$null = $text.Item(0).AppendChild($xml.CreateTextNode('%s'))
+$null = $text.Item(1).AppendChild($xml.CreateTextNode('%s'))
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('%s').Show($toast)`,
+		escapePowerShell(title), escapePowerShell(message), escapePowerShell(toastAppID))
+}
+
+// escapePowerShell escapes s for a single-quoted PowerShell string, where
+// the only special character is the quote itself. PowerShell also treats
+// the typographic quotes as quotes, so they are doubled too.
+func escapePowerShell(s string) string {
+	return strings.NewReplacer(
+		`'`, `''`,
+		"‘", "‘‘",
+		"’", "’’",
+		"‚", "‚‚",
+		"‛", "‛‛",
+	).Replace(s)
+}
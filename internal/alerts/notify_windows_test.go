@@ -0,0 +1,34 @@
+//go:build windows
+
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToastNotifier(t *testing.T) {
+	// We don't actually run PowerShell in tests to avoid UI popups.
+	notifier := NewToastNotifier(false)
+	notifier.Notify(Alert{
+		Rule:      RuleCostSurge,
+		Severity:  SeverityCritical,
+		Message:   `Cost surge with 'quoted' chars`,
+		SessionID: "sess-notification-test-1234567890",
+		FiredAt:   time.Now(),
+	})
+
+	if got, want := escapePowerShell(`it's ‘odd’`), `it''s ‘‘odd’’`; got != want {
+		t.Errorf("escapePowerShell: expected %q, got %q", want, got)
+	}
+
+	script := toastScript("cc-top: CostSurge", `rate's $5.00/hr; Remove-Item x`)
+	if !strings.Contains(script, `CreateTextNode('rate''s $5.00/hr; Remove-Item x')`) {
+		t.Errorf("message not passed as a single-quoted literal:\n%s", script)
+	}
+
+	if _, ok := NewSystemNotifier(BackendAuto, true).(*ToastNotifier); !ok {
+		t.Error("expected the system notifier to be a ToastNotifier on Windows")
+	}
+}
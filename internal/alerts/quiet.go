@@ -0,0 +1,63 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// Reasons NotificationsHeld gives for holding back notifications.
+const (
+	HeldMuted      = "muted"
+	HeldQuietHours = "quiet hours"
+)
+
+// quietHours is the [alerts.quiet_hours] schedule.
+type quietHours struct {
+	window   *config.DailyWindow // nil: weekends only, if set
+	weekends bool
+}
+
+func newQuietHours(cfg config.QuietHoursConfig) quietHours {
+	q := quietHours{weekends: cfg.Weekends}
+	if cfg.Window != "" {
+		// The config was validated, so a bad window can only mean none.
+		if w, err := config.ParseDailyWindow(cfg.Window); err == nil {
+			q.window = &w
+		}
+	}
+	return q
+}
+
+// covers reports whether t, in its own location, falls in quiet hours.
+func (q quietHours) covers(t time.Time) bool {
+	if q.weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return true
+	}
+	return q.window != nil && q.window.Contains(t)
+}
+
+// SetNotificationsMuted turns do-not-disturb on or off: while on, alerts
+// fire and are recorded but no notification is sent.
+func (e *Engine) SetNotificationsMuted(muted bool) {
+	e.muteNotify.Store(muted)
+}
+
+// NotificationsHeld reports whether notifications are held back now and
+// why: HeldMuted or HeldQuietHours.
+func (e *Engine) NotificationsHeld() (held bool, reason string) {
+	return e.notificationsHeld(e.clock.Now())
+}
+
+func (e *Engine) notificationsHeld(now time.Time) (bool, string) {
+	if e.muteNotify.Load() {
+		return true, HeldMuted
+	}
+	e.ruleMu.RLock()
+	quiet := e.quiet
+	e.ruleMu.RUnlock()
+	if quiet.covers(now.Local()) {
+		return true, HeldQuietHours
+	}
+	return false, ""
+}
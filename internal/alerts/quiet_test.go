@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestQuietHours_Covers(t *testing.T) {
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 3, day, hour, min, 0, 0, time.UTC) } // March 2 is a Monday
+	tests := []struct {
+		name string
+		cfg  config.QuietHoursConfig
+		t    time.Time
+		want bool
+	}{
+		{"disabled", config.QuietHoursConfig{}, at(2, 23, 0), false},
+		{"inside same-day window", config.QuietHoursConfig{Window: "12:00-13:30"}, at(2, 13, 29), true},
+		{"end is exclusive", config.QuietHoursConfig{Window: "12:00-13:30"}, at(2, 13, 30), false},
+		{"late evening of wrapping window", config.QuietHoursConfig{Window: "22:00-08:00"}, at(2, 22, 0), true},
+		{"early morning of wrapping window", config.QuietHoursConfig{Window: "22:00-08:00"}, at(3, 7, 59), true},
+		{"daytime outside wrapping window", config.QuietHoursConfig{Window: "22:00-08:00"}, at(3, 8, 0), false},
+		{"weekend", config.QuietHoursConfig{Weekends: true}, at(7, 12, 0), true},
+		{"weekday with weekends only", config.QuietHoursConfig{Weekends: true}, at(6, 12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := newQuietHours(tt.cfg).covers(tt.t); got != tt.want {
+			t.Errorf("%s: covers(%v) = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_QuietHoursHoldNotifications(t *testing.T) {
+	engine, clk, _, notifier := newSimEngine(t)
+	persister := &testPersister{}
+	engine.persister = persister
+	engine.rules = []Rule{&toggleRule{on: true}}
+	engine.dedupTTL = time.Nanosecond
+
+	local := clk.Now().Local()
+	cfg := defaultTestConfig()
+	cfg.Alerts.QuietHours.Window = fmt.Sprintf("%02d:00-%02d:00", local.Hour(), (local.Hour()+1)%24)
+	engine.ruleMu.Lock()
+	engine.quiet = newQuietHours(cfg.Alerts.QuietHours)
+	engine.ruleMu.Unlock()
+
+	engine.EvaluateNow()
+	if held, reason := engine.NotificationsHeld(); !held || reason != HeldQuietHours {
+		t.Errorf("NotificationsHeld() = %v, %q, want quiet hours", held, reason)
+	}
+	if notifier.count() != 0 || persister.count() != 1 || len(engine.Alerts()) != 1 {
+		t.Fatalf("during quiet hours: %d notified, %d persisted, %d active; want 0, 1, 1",
+			notifier.count(), persister.count(), len(engine.Alerts()))
+	}
+
+	clk.Advance(time.Hour)
+	engine.EvaluateNow()
+	if notifier.count() != 1 {
+		t.Errorf("after quiet hours the alert should be notified, got %d", notifier.count())
+	}
+
+	engine.SetNotificationsMuted(true)
+	clk.Advance(time.Minute)
+	engine.EvaluateNow()
+	if held, reason := engine.NotificationsHeld(); !held || reason != HeldMuted {
+		t.Errorf("NotificationsHeld() = %v, %q, want muted", held, reason)
+	}
+	if notifier.count() != 1 || persister.count() != 3 {
+		t.Errorf("while muted: %d notified, %d persisted; want 1, 3", notifier.count(), persister.count())
+	}
+
+	engine.SetNotificationsMuted(false)
+	if held, _ := engine.NotificationsHeld(); held {
+		t.Error("unmuting outside quiet hours should release notifications")
+	}
+}
@@ -0,0 +1,77 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/records"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// RecordSource reports the all-time records beaten since the previous call.
+type RecordSource interface {
+	Breaks(now time.Time) []records.Break
+}
+
+// recordBrokenRule reports beaten all-time records: an info celebration
+// for the longest session, a warning for the cost records. The longest
+// session and biggest request alert on the session that set them; the day
+// and hourly rate records share one global alert.
+type recordBrokenRule struct {
+	source RecordSource
+}
+
+func newRecordBrokenRule(source RecordSource) *recordBrokenRule {
+	return &recordBrokenRule{source: source}
+}
+
+func (r *recordBrokenRule) Evaluate(_ state.Store, now time.Time) []Alert {
+	if r.source == nil {
+		return nil
+	}
+
+	var result []Alert
+	var global []string
+	for _, b := range r.source.Breaks(now) {
+		msg := fmt.Sprintf("New record: %s %s (previous %s)",
+			strings.ToLower(b.Record.Label()), describeRecord(b.Record), describeRecord(b.Previous))
+		if b.Record.SessionID == "" {
+			global = append(global, msg)
+			continue
+		}
+		severity := SeverityWarning
+		if b.Record.Name == records.LongestSession {
+			severity = SeverityInfo
+		}
+		result = append(result, Alert{
+			Rule:      RuleRecordBroken,
+			Severity:  severity,
+			Message:   msg,
+			SessionID: b.Record.SessionID,
+			FiredAt:   now,
+		})
+	}
+	if len(global) > 0 {
+		result = append(result, Alert{
+			Rule:     RuleRecordBroken,
+			Severity: SeverityWarning,
+			Message:  strings.Join(global, "; "),
+			FiredAt:  now,
+		})
+	}
+	return result
+}
+
+// describeRecord formats a record's value with its day or model.
+func describeRecord(r records.Record) string {
+	switch r.Name {
+	case records.CostliestDay:
+		return r.FormatValue() + " on " + r.Detail
+	case records.BiggestRequest:
+		if r.Detail != "" {
+			return r.FormatValue() + " on " + r.Detail
+		}
+	}
+	return r.FormatValue()
+}
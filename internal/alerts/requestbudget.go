@@ -0,0 +1,61 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// requestBudgetWindow is the rolling window API requests are counted over.
+const requestBudgetWindow = time.Hour
+
+// requestBudgetRule fires when a session makes more API requests in an hour
+// than its budget, however cheap they are. It catches retry loops and heavy
+// use of a plan whose premium requests are capped, which cost-based rules
+// miss when each request costs fractions of a cent. A budget of 0 disables
+// it.
+type requestBudgetRule struct {
+	perHour int
+}
+
+func newRequestBudgetRule(cfg config.AlertsConfig) *requestBudgetRule {
+	return &requestBudgetRule{perHour: cfg.APIRequestBudgetPerHour}
+}
+
+func (r *requestBudgetRule) Evaluate(store state.Store, now time.Time) []Alert {
+	if r.perHour == 0 {
+		return nil
+	}
+	cutoff := now.Add(-requestBudgetWindow)
+	var alerts []Alert
+
+	for _, session := range store.ListSessions() {
+		var requests int
+		var cost float64
+		for _, evt := range session.Events {
+			if evt.Name != "claude_code.api_request" || evt.Timestamp.Before(cutoff) {
+				continue
+			}
+			requests++
+			if v, err := strconv.ParseFloat(evt.Attributes["cost_usd"], 64); err == nil {
+				cost += v
+			}
+		}
+		if requests <= r.perHour {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Rule:      RuleRequestBudget,
+			Severity:  SeverityWarning,
+			SessionID: session.SessionID,
+			Message: fmt.Sprintf("API request budget: %d requests in the last %s costing $%.2f (budget %d)",
+				requests, formatWindow(requestBudgetWindow), cost, r.perHour),
+			FiredAt: now,
+		})
+	}
+
+	return alerts
+}
@@ -0,0 +1,79 @@
+package alerts
+
+import (
+	"strings"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Router is a Notifier that sends each alert to the channels of the first
+// [[alerts.notifications.routes]] entry it matches. Alerts matching no route
+// go to the "system" channel.
+type Router struct {
+	routes   []config.NotificationRoute
+	channels map[string]Notifier
+	store    state.Store
+	project  ProjectFunc
+}
+
+// NewRouter creates a Router. channels maps channel names, including
+// "system", to their notifiers; project resolves session project
+// directories for "project:" matchers and may be nil to use the session CWD.
+func NewRouter(routes []config.NotificationRoute, channels map[string]Notifier, store state.Store, project ProjectFunc) *Router {
+	return &Router{routes: routes, channels: channels, store: store, project: project}
+}
+
+// Notify forwards alert to the channels of its route.
+func (r *Router) Notify(alert Alert) {
+	for _, name := range r.route(alert) {
+		if n := r.channels[name]; n != nil {
+			n.Notify(alert)
+		}
+	}
+}
+
+// route returns the channel names alert is sent to: its own Channels when
+// an escalation set them, else those of its route.
+func (r *Router) route(alert Alert) []string {
+	if len(alert.Channels) > 0 {
+		return alert.Channels
+	}
+
+	var session *state.SessionData
+	var dir string
+	if alert.SessionID != "" && r.store != nil {
+		if session = r.store.GetSession(alert.SessionID); session != nil {
+			dir = session.CWD
+			if r.project != nil {
+				dir = r.project(*session)
+			}
+		}
+	}
+
+	for _, route := range r.routes {
+		if len(route.Match) == 0 {
+			return route.Channels
+		}
+		for _, m := range route.Match {
+			kind, value, _ := strings.Cut(m, ":")
+			switch kind {
+			case "rule":
+				if alert.Rule == value {
+					return route.Channels
+				}
+			case "severity":
+				if alert.Severity == value {
+					return route.Channels
+				}
+			default:
+				// Global alerts have no session, so they never match
+				// session matchers such as tag: or env:.
+				if session != nil && matchSession(session, dir, m) {
+					return route.Channels
+				}
+			}
+		}
+	}
+	return []string{config.SystemChannel}
+}
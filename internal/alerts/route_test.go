@@ -0,0 +1,99 @@
+package alerts
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestRouter_RoutesBySession(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	for _, id := range []string{"sess-work", "sess-home", "sess-demo"} {
+		store.AddMetric(id, state.Metric{Name: "claude_code.cost.usage", Value: 1, Timestamp: now})
+	}
+	store.UpdateMetadata("sess-demo", state.SessionMetadata{Tags: []string{"demo"}})
+	dirs := map[string]string{"sess-work": "/home/dev/work/api", "sess-home": "/home/dev/hobby"}
+
+	system, slack, pager := newTestNotifier(), newTestNotifier(), newTestNotifier()
+	router := NewRouter([]config.NotificationRoute{
+		{Match: []string{"severity:critical"}, Channels: []string{"pager"}},
+		{Match: []string{"project:/home/dev/work/", "tag:demo"}, Channels: []string{"slack"}},
+	}, map[string]Notifier{"system": system, "slack": slack, "pager": pager},
+		store, func(s state.SessionData) string { return dirs[s.SessionID] })
+
+	router.Notify(Alert{Rule: RuleSessionCost, Severity: SeverityWarning, SessionID: "sess-work"})
+	router.Notify(Alert{Rule: RuleSessionCost, Severity: SeverityWarning, SessionID: "sess-demo"})
+	router.Notify(Alert{Rule: RuleSessionCost, Severity: SeverityWarning, SessionID: "sess-home"})
+	router.Notify(Alert{Rule: RuleCostSurge, Severity: SeverityWarning})
+	router.Notify(Alert{Rule: RuleErrorStorm, Severity: SeverityCritical, SessionID: "sess-work"})
+
+	if slack.count() != 2 {
+		t.Errorf("slack: got %d notifications, want 2 (work project and demo tag)", slack.count())
+	}
+	if system.count() != 2 {
+		t.Errorf("system: got %d notifications, want 2 (unrouted session and global alert)", system.count())
+	}
+	if pager.count() != 1 || pager.last().Rule != RuleErrorStorm {
+		t.Errorf("pager: got %d notifications, want the critical ErrorStorm", pager.count())
+	}
+}
+
+func TestRouter_AlertChannels(t *testing.T) {
+	system, pager := newTestNotifier(), newTestNotifier()
+	router := NewRouter(nil, map[string]Notifier{"system": system, "pager": pager}, state.NewMemoryStore(), nil)
+
+	router.Notify(Alert{Rule: RuleErrorStorm, Channels: []string{"pager"}})
+	if system.count() != 0 || pager.count() != 1 {
+		t.Errorf("got system=%d pager=%d; an alert's channels should replace its route", system.count(), pager.count())
+	}
+}
+
+func TestRouter_CatchAllRoute(t *testing.T) {
+	system, hook := newTestNotifier(), newTestNotifier()
+	router := NewRouter([]config.NotificationRoute{
+		{Match: []string{"rule:CostSurge"}, Channels: []string{"system", "hook"}},
+		{Channels: []string{"hook"}},
+	}, map[string]Notifier{"system": system, "hook": hook}, state.NewMemoryStore(), nil)
+
+	router.Notify(Alert{Rule: RuleCostSurge})
+	router.Notify(Alert{Rule: RuleStaleSession, SessionID: "unknown"})
+
+	if system.count() != 1 || hook.count() != 2 {
+		t.Errorf("got system=%d hook=%d, want 1 and 2", system.count(), hook.count())
+	}
+}
+
+func TestWebhookNotifier_Payloads(t *testing.T) {
+	bodies := make(chan map[string]any, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("invalid JSON body %q: %v", data, err)
+		}
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	alert := Alert{Rule: RuleCostSurge, Severity: SeverityCritical, Message: "cost surging", SessionID: "sess-1234567890abcdef"}
+
+	NewWebhookNotifier("webhook", srv.URL).Notify(alert)
+	body := <-bodies
+	if body["rule"] != RuleCostSurge || body["message"] != "cost surging" || body["session_id"] != alert.SessionID {
+		t.Errorf("webhook payload: got %v", body)
+	}
+
+	NewWebhookNotifier("slack", srv.URL).Notify(alert)
+	body = <-bodies
+	text, _ := body["text"].(string)
+	if text == "" || len(body) != 1 {
+		t.Errorf("slack payload should only carry text, got %v", body)
+	}
+}
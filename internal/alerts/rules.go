@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strconv"
 	"sync"
 	"time"
 
@@ -23,6 +22,7 @@ type Rule interface {
 type costSurgeRule struct {
 	threshold  float64
 	calculator *burnrate.Calculator
+	auto       ThresholdSource // nil unless cost_surge_auto is set
 }
 
 func newCostSurgeRule(cfg config.AlertsConfig, calculator *burnrate.Calculator) *costSurgeRule {
@@ -32,13 +32,26 @@ func newCostSurgeRule(cfg config.AlertsConfig, calculator *burnrate.Calculator)
 	}
 }
 
+// currentThreshold returns the history-derived threshold in auto mode once
+// available, otherwise the configured one. The second result reports
+// whether the auto value is in use.
+func (r *costSurgeRule) currentThreshold() (float64, bool) {
+	if r.auto != nil {
+		if t, ok := r.auto.AutoThresholds(); ok && t.CostPerHour > 0 {
+			return t.CostPerHour, true
+		}
+	}
+	return r.threshold, false
+}
+
 func (r *costSurgeRule) Evaluate(store state.Store, now time.Time) []Alert {
 	br := r.calculator.ComputeWithTime(store, now)
-	if br.HourlyRate >= r.threshold {
+	threshold, auto := r.currentThreshold()
+	if br.HourlyRate >= threshold {
 		return []Alert{{
 			Rule:     RuleCostSurge,
 			Severity: SeverityCritical,
-			Message:  fmt.Sprintf("Cost surge: $%.2f/hr exceeds threshold $%.2f/hr", br.HourlyRate, r.threshold),
+			Message:  fmt.Sprintf("Cost surge: $%.2f/hr exceeds threshold $%.2f/hr%s", br.HourlyRate, threshold, autoSuffix(auto)),
 			FiredAt:  now,
 		}}
 	}
@@ -51,6 +64,7 @@ type runawayTokensRule struct {
 	sustainedMinutes  int
 	calculator        *burnrate.Calculator
 	exceededSince     map[string]time.Time
+	auto              ThresholdSource // nil unless runaway_token_velocity_auto is set
 }
 
 func newRunawayTokensRule(cfg config.AlertsConfig, calculator *burnrate.Calculator) *runawayTokensRule {
@@ -62,10 +76,27 @@ func newRunawayTokensRule(cfg config.AlertsConfig, calculator *burnrate.Calculat
 	}
 }
 
+// currentThreshold mirrors costSurgeRule.currentThreshold for token velocity.
+func (r *runawayTokensRule) currentThreshold() (float64, bool) {
+	if r.auto != nil {
+		if t, ok := r.auto.AutoThresholds(); ok && t.TokensPerMinute > 0 {
+			return t.TokensPerMinute, true
+		}
+	}
+	return r.velocityThreshold, false
+}
+
+func (r *runawayTokensRule) inheritState(prev Rule) {
+	if p, ok := prev.(*runawayTokensRule); ok {
+		r.exceededSince = p.exceededSince
+	}
+}
+
 func (r *runawayTokensRule) Evaluate(store state.Store, now time.Time) []Alert {
 	br := r.calculator.ComputeWithTime(store, now)
+	threshold, auto := r.currentThreshold()
 	key := "" // global key
-	if br.TokenVelocity >= r.velocityThreshold {
+	if br.TokenVelocity >= threshold {
 		if _, ok := r.exceededSince[key]; !ok {
 			r.exceededSince[key] = now
 		}
@@ -73,7 +104,7 @@ func (r *runawayTokensRule) Evaluate(store state.Store, now time.Time) []Alert {
 			return []Alert{{
 				Rule:     RuleRunawayTokens,
 				Severity: SeverityWarning,
-				Message:  fmt.Sprintf("Runaway tokens: %.0f tokens/min exceeds threshold %.0f for %d+ min", br.TokenVelocity, r.velocityThreshold, r.sustainedMinutes),
+				Message:  fmt.Sprintf("Runaway tokens: %.0f tokens/min exceeds threshold %.0f%s for %d+ min", br.TokenVelocity, threshold, autoSuffix(auto), r.sustainedMinutes),
 				FiredAt:  now,
 			}}
 		}
@@ -83,6 +114,14 @@ func (r *runawayTokensRule) Evaluate(store state.Store, now time.Time) []Alert {
 	return nil
 }
 
+// autoSuffix marks alert messages whose threshold came from usage history.
+func autoSuffix(auto bool) string {
+	if auto {
+		return " (auto)"
+	}
+	return ""
+}
+
 // loopDetectorRule fires when the same command hash fails repeatedly within a time window.
 type loopDetectorRule struct {
 	threshold  int
@@ -105,6 +144,12 @@ func newLoopDetectorRule(cfg config.AlertsConfig, normalizer CommandNormalizer)
 	}
 }
 
+func (r *loopDetectorRule) inheritState(prev Rule) {
+	if p, ok := prev.(*loopDetectorRule); ok {
+		r.failures, r.lastProcessed = p.failures, p.lastProcessed
+	}
+}
+
 func (r *loopDetectorRule) Evaluate(store state.Store, now time.Time) []Alert {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -284,14 +329,15 @@ func (r *staleSessionRule) Evaluate(store state.Store, now time.Time) []Alert {
 	return alerts
 }
 
-// contextPressureRule fires when input_tokens approach the model's context limit.
+// contextPressureRule fires when a session's context, as tracked by
+// SessionData.ContextUsage, approaches the model's context limit.
 type contextPressureRule struct {
 	pressurePercent int
 	modelLimits     map[string]int
 
 	// Track models we have already warned about (one-time warning).
-	mu             sync.Mutex
-	warnedModels   map[string]bool
+	mu           sync.Mutex
+	warnedModels map[string]bool
 }
 
 func newContextPressureRule(cfg config.AlertsConfig, modelLimits map[string]int) *contextPressureRule {
@@ -302,6 +348,12 @@ func newContextPressureRule(cfg config.AlertsConfig, modelLimits map[string]int)
 	}
 }
 
+func (r *contextPressureRule) inheritState(prev Rule) {
+	if p, ok := prev.(*contextPressureRule); ok {
+		r.warnedModels = p.warnedModels
+	}
+}
+
 func (r *contextPressureRule) Evaluate(store state.Store, now time.Time) []Alert {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -309,46 +361,27 @@ func (r *contextPressureRule) Evaluate(store state.Store, now time.Time) []Alert
 	var alerts []Alert
 
 	for _, session := range store.ListSessions() {
-		for _, evt := range session.Events {
-			if evt.Name != "claude_code.api_request" {
-				continue
-			}
-
-			model := evt.Attributes["model"]
-			if model == "" {
-				continue
-			}
-
-			limit, ok := r.modelLimits[model]
-			if !ok {
+		for model := range session.ContextTokens {
+			if _, ok := r.modelLimits[model]; !ok && !r.warnedModels[model] {
 				// Model not in limit map: log one-time warning, no alert.
-				if !r.warnedModels[model] {
-					log.Printf("WARNING: model %q not in context limit map, skipping context pressure check", model)
-					r.warnedModels[model] = true
-				}
-				continue
-			}
-
-			inputTokensStr := evt.Attributes["input_tokens"]
-			if inputTokensStr == "" {
-				continue
-			}
-			inputTokens, err := strconv.ParseInt(inputTokensStr, 10, 64)
-			if err != nil {
-				continue
+				log.Printf("WARNING: model %q not in context limit map, skipping context pressure check", model)
+				r.warnedModels[model] = true
 			}
+		}
 
-			threshold := float64(limit) * float64(r.pressurePercent) / 100.0
-			if float64(inputTokens) > threshold {
-				pct := float64(inputTokens) / float64(limit) * 100.0
-				alerts = append(alerts, Alert{
-					Rule:      RuleContextPressure,
-					Severity:  SeverityWarning,
-					SessionID: session.SessionID,
-					Message:   fmt.Sprintf("Context pressure: %d input tokens (%.0f%% of %d limit for %s)", inputTokens, pct, limit, model),
-					FiredAt:   now,
-				})
-			}
+		model, tokens, limit := session.ContextUsage(r.modelLimits)
+		if limit == 0 {
+			continue
+		}
+		pct := session.ContextUsagePercent(r.modelLimits)
+		if pct > float64(r.pressurePercent) {
+			alerts = append(alerts, Alert{
+				Rule:      RuleContextPressure,
+				Severity:  SeverityWarning,
+				SessionID: session.SessionID,
+				Message:   fmt.Sprintf("Context pressure: %d context tokens (%.0f%% of %d limit for %s)", tokens, pct, limit, model),
+				FiredAt:   now,
+			})
 		}
 	}
 
@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"slices"
+	"time"
+)
+
+// silenceReloadInterval is how often the engine rereads silences, which
+// cc-top silence writes from another process.
+const silenceReloadInterval = 10 * time.Second
+
+// Silence keeps every alert of a rule ("*" for all rules) from firing until
+// it ends. Silences are created with cc-top silence, e.g. before a planned
+// expensive run.
+type Silence struct {
+	ID        int64
+	Rule      string
+	Reason    string
+	CreatedAt time.Time
+	Until     time.Time
+}
+
+// Covers reports whether the silence applies to rule at now.
+func (s Silence) Covers(rule string, now time.Time) bool {
+	return (s.Rule == "*" || s.Rule == rule) && now.Before(s.Until)
+}
+
+// SilenceStore loads the silences that haven't ended at now.
+type SilenceStore interface {
+	LoadAlertSilences(now time.Time) []Silence
+}
+
+// WithSilenceStore sets the store silences are read from.
+func WithSilenceStore(s SilenceStore) EngineOption {
+	return func(e *Engine) {
+		e.silenceStore = s
+	}
+}
+
+// Silences returns the silences in effect, soonest ending first.
+func (e *Engine) Silences() []Silence {
+	now := e.clock.Now()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var result []Silence
+	for _, s := range e.silences {
+		if now.Before(s.Until) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// loadSilences rereads the silences at most every silenceReloadInterval and
+// marks the fired alerts they cover silenced.
+func (e *Engine) loadSilences(now time.Time) {
+	if e.silenceStore == nil {
+		return
+	}
+	e.mu.RLock()
+	fresh := !e.silencesLoaded.IsZero() && now.Sub(e.silencesLoaded) < silenceReloadInterval && !now.Before(e.silencesLoaded)
+	e.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	silences := e.silenceStore.LoadAlertSilences(now)
+	slices.SortFunc(silences, func(a, b Silence) int { return a.Until.Compare(b.Until) })
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.silences = silences
+	e.silencesLoaded = now
+	for i := range e.alerts {
+		if silencesCover(silences, e.alerts[i].Rule, now) {
+			e.alerts[i].Silenced = true
+		}
+	}
+}
+
+// ruleSilenced reports whether a silence covers rule at now.
+func (e *Engine) ruleSilenced(rule string, now time.Time) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return silencesCover(e.silences, rule, now)
+}
+
+func silencesCover(silences []Silence, rule string, now time.Time) bool {
+	for _, s := range silences {
+		if s.Covers(rule, now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/clock"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// newSimEngine returns an engine whose calculator and evaluations follow a
+// fake clock, so tests can step through minutes of telemetry.
+func newSimEngine(t *testing.T) (*Engine, *clock.Fake, *state.MemoryStore, *testNotifier) {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	store := state.NewMemoryStore()
+	calc := burnrate.NewCalculator(burnrate.DefaultThresholds(), burnrate.WithClock(clk))
+	notifier := newTestNotifier()
+	engine := NewEngine(store, defaultTestConfig(), calc, WithNotifier(notifier), WithClock(clk))
+	return engine, clk, store, notifier
+}
+
+func TestSimulation_RunawayTokensFiresAfterSustainedMinutes(t *testing.T) {
+	engine, clk, store, notifier := newSimEngine(t)
+	cfg := defaultTestConfig()
+	perTick := float64(cfg.Alerts.RunawayTokenVelocity) * 2 / 6 // twice the threshold, 10s ticks
+
+	var tokens float64
+	step := func() {
+		store.AddMetric("sess-1", state.Metric{
+			Name:       "claude_code.token.usage",
+			Value:      tokens,
+			Attributes: map[string]string{"type": "input"},
+			Timestamp:  clk.Now(),
+		})
+		engine.EvaluateNow()
+	}
+
+	step()
+	start := clk.Now()
+	var firedAt time.Time
+	for range 30 {
+		clk.Advance(10 * time.Second)
+		tokens += perTick
+		step()
+		if a := notifier.last(); a != nil && a.Rule == RuleRunawayTokens {
+			firedAt = a.FiredAt
+			break
+		}
+	}
+
+	// Velocity is measurable from the second sample, and must then stay
+	// above the threshold for the sustained period.
+	sustained := time.Duration(cfg.Alerts.RunawayTokenSustainedMinutes) * time.Minute
+	if want := start.Add(10*time.Second + sustained); !firedAt.Equal(want) {
+		t.Errorf("RunawayTokens fired at %v, want %v", firedAt, want)
+	}
+}
+
+func TestSimulation_SnoozeFollowsEngineClock(t *testing.T) {
+	engine, clk, _, _ := newSimEngine(t)
+	rule := &toggleRule{on: true}
+	engine.rules = []Rule{rule}
+	engine.dedupTTL = time.Nanosecond
+
+	engine.EvaluateNow()
+	engine.Snooze(engine.Alerts()[0], 30*time.Minute)
+
+	clk.Advance(29 * time.Minute)
+	engine.EvaluateNow()
+	if len(unsilenced(engine)) != 0 {
+		t.Fatal("snoozed alert should not fire during the snooze")
+	}
+
+	clk.Advance(2 * time.Minute)
+	engine.EvaluateNow()
+	if len(unsilenced(engine)) != 1 {
+		t.Errorf("alert should fire again after the snooze, got %d", len(unsilenced(engine)))
+	}
+}
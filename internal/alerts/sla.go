@@ -0,0 +1,101 @@
+package alerts
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// SLATimers holds expected session durations set at runtime, e.g. from the
+// TUI. They take precedence over the cc_top.expected_duration resource
+// attribute. It is safe for concurrent use.
+type SLATimers struct {
+	mu       sync.RWMutex
+	expected map[string]time.Duration
+}
+
+// NewSLATimers creates an empty set of SLA timers.
+func NewSLATimers() *SLATimers {
+	return &SLATimers{expected: make(map[string]time.Duration)}
+}
+
+// SetExpectedDuration sets the expected duration of a session. A
+// non-positive d removes the timer, including one from the resource
+// attribute.
+func (t *SLATimers) SetExpectedDuration(sessionID string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expected[sessionID] = max(d, 0)
+}
+
+// ExpectedDuration returns the expected duration of s, or zero if it has no
+// SLA timer. A nil SLATimers only reports the resource attribute.
+func (t *SLATimers) ExpectedDuration(s state.SessionData) time.Duration {
+	if t != nil {
+		t.mu.RLock()
+		d, ok := t.expected[s.SessionID]
+		t.mu.RUnlock()
+		if ok {
+			return d
+		}
+	}
+	return s.Metadata.ExpectedDuration
+}
+
+// slaOverrunRule fires once when a session with an expected duration has
+// been running for longer than factor times that duration.
+type slaOverrunRule struct {
+	factor float64
+	timers *SLATimers
+
+	mu    sync.Mutex
+	fired map[string]time.Duration // session ID -> expected duration alerted on
+}
+
+func newSLAOverrunRule(cfg config.AlertsConfig, timers *SLATimers) *slaOverrunRule {
+	return &slaOverrunRule{
+		factor: cfg.SLAOverrunFactor,
+		timers: timers,
+		fired:  make(map[string]time.Duration),
+	}
+}
+
+func (r *slaOverrunRule) inheritState(prev Rule) {
+	if p, ok := prev.(*slaOverrunRule); ok {
+		r.fired = p.fired
+	}
+}
+
+func (r *slaOverrunRule) Evaluate(store state.Store, now time.Time) []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var alerts []Alert
+	for _, session := range store.ListSessions() {
+		expected := r.timers.ExpectedDuration(session)
+		if session.Exited || expected <= 0 || session.StartedAt.IsZero() {
+			continue
+		}
+		// Changing the expected duration re-arms the alert.
+		if r.fired[session.SessionID] == expected {
+			continue
+		}
+		elapsed := now.Sub(session.StartedAt)
+		if elapsed <= time.Duration(float64(expected)*r.factor) {
+			continue
+		}
+		r.fired[session.SessionID] = expected
+		alerts = append(alerts, Alert{
+			Rule:      RuleSLAOverrun,
+			Severity:  SeverityWarning,
+			SessionID: session.SessionID,
+			Message: fmt.Sprintf("SLA overrun: running %s, expected %s (%.1fx)",
+				elapsed.Round(time.Minute), expected, elapsed.Seconds()/expected.Seconds()),
+			FiredAt: now,
+		})
+	}
+	return alerts
+}
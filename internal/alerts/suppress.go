@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// suppressAllRules is the suppression key that applies to every rule.
+const suppressAllRules = "*"
+
+// ProjectFunc resolves the project directory of a session for project
+// suppressions. The default uses the session's recorded CWD.
+type ProjectFunc func(s state.SessionData) string
+
+// suppressor drops session alerts matching the [alerts.suppressions]
+// config before they are recorded or notified.
+type suppressor struct {
+	byRule  map[string][]string // rule name or "*" -> "tag:x" / "project:y"
+	project ProjectFunc
+}
+
+// suppressed reports whether alert should be dropped. Global alerts are never
+// suppressed since they do not belong to a single session.
+func (sp *suppressor) suppressed(store state.Store, alert Alert) bool {
+	if len(sp.byRule) == 0 || alert.SessionID == "" {
+		return false
+	}
+	matchers := append(sp.byRule[alert.Rule], sp.byRule[suppressAllRules]...)
+	if len(matchers) == 0 {
+		return false
+	}
+	s := store.GetSession(alert.SessionID)
+	if s == nil {
+		return false
+	}
+
+	dir := s.CWD
+	if sp.project != nil {
+		dir = sp.project(*s)
+	}
+	for _, m := range matchers {
+		if matchSession(s, dir, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSession reports whether the session with project directory dir
+// satisfies a "tag:<name>", "project:<path>", "env:<environment>" or
+// "host:<name>" matcher.
+func matchSession(s *state.SessionData, dir, matcher string) bool {
+	kind, value, _ := strings.Cut(matcher, ":")
+	switch kind {
+	case "tag":
+		return slices.Contains(s.Metadata.Tags, value)
+	case "project":
+		return pathutil.MatchDir(dir, value)
+	case "env":
+		return s.Metadata.Environment == value
+	case "host":
+		return s.Metadata.HostName == value
+	}
+	return false
+}
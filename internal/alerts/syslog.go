@@ -0,0 +1,141 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// journaldSocket is the systemd journal's native protocol socket.
+const journaldSocket = "/run/systemd/journal/socket"
+
+// Syslog priorities (RFC 5424 severities) used for journal entries.
+const (
+	priorityCrit    = 2
+	priorityWarning = 4
+	priorityInfo    = 6
+)
+
+// syslogWriter is the part of *syslog.Writer the notifier uses; log/syslog
+// does not build on Windows.
+type syslogWriter interface {
+	Crit(m string) error
+	Warning(m string) error
+	Info(m string) error
+}
+
+// SyslogNotifier writes every alert to syslog or the systemd journal, with
+// the priority taken from the severity (critical -> crit, otherwise
+// warning), before passing it on to the next notifier. Syslog lines are
+// logfmt key=value pairs; journal entries carry the same data as
+// CC_TOP_* fields.
+type SyslogNotifier struct {
+	next    Notifier
+	syslog  syslogWriter
+	journal net.Conn
+}
+
+// NewSyslogNotifier connects to the log target, "syslog" or "journald".
+// next may be nil.
+func NewSyslogNotifier(target string, next Notifier) (*SyslogNotifier, error) {
+	switch target {
+	case "syslog":
+		w, err := dialSyslog()
+		if err != nil {
+			return nil, fmt.Errorf("connecting to syslog: %w", err)
+		}
+		return &SyslogNotifier{next: next, syslog: w}, nil
+	case "journald":
+		return newJournaldNotifier(journaldSocket, next)
+	}
+	return nil, fmt.Errorf("unknown log target %q", target)
+}
+
+func newJournaldNotifier(socket string, next Notifier) (*SyslogNotifier, error) {
+	conn, err := net.Dial("unixgram", socket)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to journald: %w", err)
+	}
+	return &SyslogNotifier{next: next, journal: conn}, nil
+}
+
+// Notify logs alert in the background and forwards it to next.
+func (n *SyslogNotifier) Notify(alert Alert) {
+	go func() {
+		if err := n.write(alert); err != nil {
+			log.Printf("WARNING: failed to log alert: %v", err)
+		}
+	}()
+	if n.next != nil {
+		n.next.Notify(alert)
+	}
+}
+
+func (n *SyslogNotifier) write(alert Alert) error {
+	if n.journal != nil {
+		_, err := n.journal.Write(journalEntry(alert))
+		return err
+	}
+	line := syslogLine(alert)
+	switch alert.Severity {
+	case SeverityCritical:
+		return n.syslog.Crit(line)
+	case SeverityInfo:
+		return n.syslog.Info(line)
+	}
+	return n.syslog.Warning(line)
+}
+
+// syslogLine formats alert as logfmt, e.g.
+// `alert rule=ErrorStorm severity=critical session=abcd1234 msg="..."`.
+func syslogLine(alert Alert) string {
+	session := alert.SessionID
+	if session == "" {
+		session = "global"
+	}
+	return fmt.Sprintf("alert rule=%s severity=%s session=%s msg=%s",
+		alert.Rule, alert.Severity, session, strconv.Quote(alert.Message))
+}
+
+// syslogPriority maps a severity to a syslog priority number.
+func syslogPriority(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return priorityCrit
+	case SeverityInfo:
+		return priorityInfo
+	}
+	return priorityWarning
+}
+
+// journalEntry encodes alert in the journal native protocol: one
+// KEY=value line per field, or KEY, a little-endian length and the raw
+// value for values containing newlines.
+func journalEntry(alert Alert) []byte {
+	fields := [][2]string{
+		{"MESSAGE", fmt.Sprintf("%s: %s", alert.Rule, alert.Message)},
+		{"PRIORITY", strconv.Itoa(syslogPriority(alert.Severity))},
+		{"SYSLOG_IDENTIFIER", "cc-top"},
+		{"CC_TOP_RULE", alert.Rule},
+		{"CC_TOP_SEVERITY", alert.Severity},
+	}
+	if alert.SessionID != "" {
+		fields = append(fields, [2]string{"CC_TOP_SESSION_ID", alert.SessionID})
+	}
+
+	var buf bytes.Buffer
+	for _, f := range fields {
+		if !strings.Contains(f[1], "\n") {
+			buf.WriteString(f[0] + "=" + f[1] + "\n")
+			continue
+		}
+		buf.WriteString(f[0] + "\n")
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(len(f[1])))
+		buf.WriteString(f[1] + "\n")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSyslogLine(t *testing.T) {
+	got := syslogLine(Alert{Rule: RuleErrorStorm, Severity: SeverityCritical, Message: `5 "errors"`})
+	want := `alert rule=ErrorStorm severity=critical session=global msg="5 \"errors\""`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestJournaldNotifier(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cctop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	next := newTestNotifier()
+	n, err := newJournaldNotifier(socket, next)
+	if err != nil {
+		t.Fatalf("newJournaldNotifier: %v", err)
+	}
+	n.Notify(Alert{Rule: RuleSessionCost, Severity: SeverityWarning, SessionID: "sess-1", Message: "line one\nline two"})
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	size, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("reading journal entry: %v", err)
+	}
+	entry := buf[:size]
+	for _, want := range []string{"PRIORITY=4\n", "SYSLOG_IDENTIFIER=cc-top\n", "CC_TOP_RULE=SessionCost\n", "CC_TOP_SESSION_ID=sess-1\n"} {
+		if !bytes.Contains(entry, []byte(want)) {
+			t.Errorf("entry missing %q:\n%q", want, entry)
+		}
+	}
+	msg := "SessionCost: line one\nline two"
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(msg)))
+	if !bytes.Contains(entry, append(append([]byte("MESSAGE\n"), length[:]...), msg+"\n"...)) {
+		t.Errorf("multi-line MESSAGE should use the binary field format:\n%q", entry)
+	}
+	if next.count() != 1 {
+		t.Errorf("alert should be passed on to the next notifier, got %d", next.count())
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package alerts
+
+import "log/syslog"
+
+// dialSyslog connects to the local syslog daemon.
+func dialSyslog() (syslogWriter, error) {
+	return syslog.New(syslog.LOG_USER|syslog.LOG_WARNING, "cc-top")
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package alerts
+
+import "errors"
+
+// dialSyslog fails: Windows has no syslog daemon.
+func dialSyslog() (syslogWriter, error) {
+	return nil, errors.New("syslog is not available on Windows")
+}
@@ -0,0 +1,74 @@
+package alerts
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// toolFailureRule fires when one tool keeps failing in a session, which
+// usually means a broken environment the agent keeps retrying, e.g. Bash
+// against a missing binary. Unlike LoopDetector it counts every failure of
+// the tool, not repeats of one command.
+type toolFailureRule struct {
+	count  int
+	window time.Duration
+}
+
+func newToolFailureRule(cfg config.AlertsConfig) *toolFailureRule {
+	return &toolFailureRule{
+		count:  cfg.ToolFailureCount,
+		window: time.Duration(cfg.ToolFailureWindowMinutes) * time.Minute,
+	}
+}
+
+func (r *toolFailureRule) Evaluate(store state.Store, now time.Time) []Alert {
+	cutoff := now.Add(-r.window)
+	var alerts []Alert
+
+	for _, session := range store.ListSessions() {
+		failures := make(map[string]int)
+		for _, evt := range session.Events {
+			if evt.Name != "claude_code.tool_result" || evt.Timestamp.Before(cutoff) {
+				continue
+			}
+			if tool := evt.Attributes["tool_name"]; tool != "" && evt.Attributes["success"] == "false" {
+				failures[tool]++
+			}
+		}
+
+		var tools []string
+		for tool, n := range failures {
+			if n > r.count {
+				tools = append(tools, tool)
+			}
+		}
+		if len(tools) == 0 {
+			continue
+		}
+		// One alert per session, since alerts are deduplicated by rule and
+		// session; it names every failing tool, worst first.
+		slices.SortFunc(tools, func(a, b string) int {
+			return cmp.Or(cmp.Compare(failures[b], failures[a]), cmp.Compare(a, b))
+		})
+		parts := make([]string, len(tools))
+		for i, tool := range tools {
+			parts[i] = fmt.Sprintf("%s failed %d times", tool, failures[tool])
+		}
+		alerts = append(alerts, Alert{
+			Rule:      RuleToolFailures,
+			Severity:  SeverityWarning,
+			SessionID: session.SessionID,
+			Message: fmt.Sprintf("Tool failures: %s in the last %s (threshold %d)",
+				strings.Join(parts, ", "), formatWindow(r.window), r.count),
+			FiredAt: now,
+		})
+	}
+
+	return alerts
+}
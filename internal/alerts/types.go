@@ -12,10 +12,19 @@ const (
 	RuleContextPressure = "ContextPressure"
 	RuleHighRejection   = "HighRejection"
 	RuleSessionCost     = "SessionCost"
+	RuleSLAOverrun      = "SLAOverrun"
+	RuleBudgetThreshold = "BudgetThreshold"
+
+	RuleCacheInvalidation = "CacheInvalidation"
+	RuleAnomalousSpend    = "AnomalousSpend"
+	RuleRecordBroken      = "RecordBroken"
+	RuleToolFailures      = "ToolFailures"
+	RuleRequestBudget     = "RequestBudget"
 )
 
 // Alert severity constants.
 const (
+	SeverityInfo     = "info"
 	SeverityWarning  = "warning"
 	SeverityCritical = "critical"
 )
@@ -23,10 +32,18 @@ const (
 // Alert represents a triggered alert from the alert engine.
 type Alert struct {
 	Rule      string // CostSurge, RunawayTokens, LoopDetector, etc.
-	Severity  string // warning, critical
+	Severity  string // info, warning, critical
 	Message   string
 	SessionID string // empty for global alerts
 	FiredAt   time.Time
+
+	// Channels, when set, replace the notification route's channels; an
+	// escalation policy sets them.
+	Channels []string
+
+	// Silenced is set once the alert is acknowledged, snoozed or covered by
+	// a silence. It stays in the history but leaves the alert bar.
+	Silenced bool
 }
 
 // alertKey returns a deduplication key for this alert, combining the rule name
@@ -55,6 +72,37 @@ type AlertPersister interface {
 	PersistAlert(alert Alert)
 }
 
+// AutoThresholds holds alert thresholds derived from the user's own usage
+// history, used by rules running in auto mode.
+type AutoThresholds struct {
+	CostPerHour     float64 // percentile of non-idle hourly cost rates
+	TokensPerMinute float64 // percentile of non-idle token velocities
+	Samples         int
+	ComputedAt      time.Time
+}
+
+// ThresholdSource supplies history-derived thresholds. ok is false until
+// enough history has been collected, in which case rules keep their
+// configured static thresholds.
+type ThresholdSource interface {
+	AutoThresholds() (t AutoThresholds, ok bool)
+}
+
+// SpendBaseline describes normal spend: the mean and standard deviation of
+// the average $/hr over recent active hours.
+type SpendBaseline struct {
+	MeanPerHour   float64
+	StdDevPerHour float64
+	Hours         int // active hours the baseline was computed from
+	ComputedAt    time.Time
+}
+
+// BaselineSource supplies the rolling spend baseline used by the
+// AnomalousSpend rule. ok is false until enough history has been collected.
+type BaselineSource interface {
+	SpendBaseline() (b SpendBaseline, ok bool)
+}
+
 // Notifier sends alert notifications via platform-specific mechanisms.
 type Notifier interface {
 	// Notify sends an alert notification. Implementations must be non-blocking.
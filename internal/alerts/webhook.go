@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds a single webhook delivery.
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts alerts to an HTTP endpoint. Slack-style endpoints
+// receive a {"text": ...} message; plain webhooks receive the alert as JSON.
+// Deliveries run in a background goroutine so the alert engine never blocks.
+type WebhookNotifier struct {
+	url    string
+	slack  bool
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to url. kind is "slack" or
+// "webhook".
+func NewWebhookNotifier(kind, url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		slack:  kind == "slack",
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// webhookPayload is the JSON body posted to plain webhooks.
+type webhookPayload struct {
+	Rule      string    `json:"rule"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+	SessionID string    `json:"session_id,omitempty"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// Notify posts alert in the background. Errors are logged.
+func (n *WebhookNotifier) Notify(alert Alert) {
+	body, err := n.payload(alert)
+	if err != nil {
+		log.Printf("WARNING: failed to encode webhook notification: %v", err)
+		return
+	}
+	go func() {
+		if err := n.post(body); err != nil {
+			log.Printf("WARNING: failed to send webhook notification: %v", err)
+		}
+	}()
+}
+
+func (n *WebhookNotifier) payload(alert Alert) ([]byte, error) {
+	if n.slack {
+		text := fmt.Sprintf("*cc-top: %s* (%s)\n%s", alert.Rule, alert.Severity, alert.Message)
+		if alert.SessionID != "" {
+			text += "\nSession: " + truncateSessionID(alert.SessionID)
+		}
+		return json.Marshal(map[string]string{"text": text})
+	}
+	return json.Marshal(webhookPayload{
+		Rule:      alert.Rule,
+		Severity:  alert.Severity,
+		Message:   alert.Message,
+		SessionID: alert.SessionID,
+		FiredAt:   alert.FiredAt,
+	})
+}
+
+func (n *WebhookNotifier) post(body []byte) error {
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", n.url, resp.Status)
+	}
+	return nil
+}
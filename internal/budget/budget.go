@@ -0,0 +1,195 @@
+// Package budget tracks spending against the weekly and monthly budgets
+// configured in [budget].
+package budget
+
+import (
+	"log"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Budget period names.
+const (
+	Weekly  = "weekly"
+	Monthly = "monthly"
+)
+
+// refreshInterval bounds how often spending is recomputed; it reads the
+// daily history, which is too expensive for every render.
+const refreshInterval = 30 * time.Second
+
+// Status is the spending of one budget period.
+type Status struct {
+	Period string // Weekly or Monthly
+	Limit  float64
+	Spent  float64
+	Start  time.Time // start of the current period, local midnight
+	End    time.Time // start of the next period
+}
+
+// Percent returns the share of the budget spent, in percent.
+func (s Status) Percent() float64 {
+	if s.Limit <= 0 {
+		return 0
+	}
+	return s.Spent / s.Limit * 100
+}
+
+// Remaining returns the unspent budget, never negative.
+func (s Status) Remaining() float64 {
+	return max(0, s.Limit-s.Spent)
+}
+
+// SessionDay is the highest cumulative cost a session reported on one day.
+type SessionDay struct {
+	SessionID string
+	Date      string // YYYY-MM-DD
+	Cost      float64
+}
+
+// CostHistory supplies per-session cost history, since session costs are
+// cumulative and only their day-over-day differences are the day's spend.
+type CostHistory interface {
+	// SessionCosts returns the highest cumulative cost of each session on
+	// each day from start on, dated in start's location and ordered by
+	// session and date, and the highest cost each session reported before
+	// start.
+	SessionCosts(start time.Time) (days []SessionDay, before map[string]float64, err error)
+}
+
+// Option configures a Tracker.
+type Option func(*Tracker)
+
+// WithCostHistory makes spending come from h instead of the in-memory
+// sessions.
+func WithCostHistory(h CostHistory) Option {
+	return func(t *Tracker) { t.history = h }
+}
+
+// Tracker computes budget statuses. Spending comes from the cost history
+// when one is available and from the in-memory sessions otherwise.
+type Tracker struct {
+	cfg     config.BudgetConfig
+	store   state.Store
+	history CostHistory
+
+	mu         sync.Mutex
+	cached     []Status
+	computedAt time.Time
+}
+
+// NewTracker creates a tracker for the configured budgets.
+func NewTracker(cfg config.BudgetConfig, store state.Store, opts ...Option) *Tracker {
+	t := &Tracker{cfg: cfg, store: store}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Enabled reports whether any budget is configured.
+func (t *Tracker) Enabled() bool {
+	return t.cfg.WeeklyUSD > 0 || t.cfg.MonthlyUSD > 0
+}
+
+// Status returns the current status of each configured budget, weekly first.
+func (t *Tracker) Status(now time.Time) []Status {
+	if !t.Enabled() {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fresh := now.Sub(t.computedAt) < refreshInterval && now.After(t.computedAt)
+	if fresh && len(t.cached) > 0 && now.Before(t.cached[0].End) {
+		return t.cached
+	}
+
+	var statuses []Status
+	if t.cfg.WeeklyUSD > 0 {
+		start := WeekStart(now)
+		statuses = append(statuses, Status{Period: Weekly, Limit: t.cfg.WeeklyUSD, Start: start, End: start.AddDate(0, 0, 7)})
+	}
+	if t.cfg.MonthlyUSD > 0 {
+		start := MonthStart(now)
+		statuses = append(statuses, Status{Period: Monthly, Limit: t.cfg.MonthlyUSD, Start: start, End: start.AddDate(0, 1, 0)})
+	}
+
+	earliest := statuses[0].Start
+	for _, s := range statuses {
+		if s.Start.Before(earliest) {
+			earliest = s.Start
+		}
+	}
+	daily := t.dailyCosts(earliest)
+	for i := range statuses {
+		from := statuses[i].Start.Format("2006-01-02")
+		for date, cost := range daily {
+			if date >= from {
+				statuses[i].Spent += cost
+			}
+		}
+	}
+
+	t.cached = statuses
+	t.computedAt = now
+	return statuses
+}
+
+// dailyCosts returns the cost per local date since start.
+func (t *Tracker) dailyCosts(start time.Time) map[string]float64 {
+	costs := make(map[string]float64)
+	if t.history != nil {
+		days, before, err := t.history.SessionCosts(start)
+		if err != nil {
+			log.Printf("WARNING: reading cost history for budgets: %v", err)
+		} else if len(days) > 0 {
+			// Each day adds what the session spent since the last cost it
+			// reported before; a lower cost means its counter restarted.
+			last := maps.Clone(before)
+			if last == nil {
+				last = make(map[string]float64)
+			}
+			for _, d := range days {
+				spent := d.Cost - last[d.SessionID]
+				if spent < 0 {
+					spent = d.Cost
+				}
+				costs[d.Date] += spent
+				last[d.SessionID] = d.Cost
+			}
+			return costs
+		}
+	}
+
+	// No history (persistence disabled or nothing flushed yet): attribute
+	// each session's cost to the day it was last active.
+	for _, s := range t.store.ListSessions() {
+		last := s.LastEventAt
+		if last.IsZero() {
+			last = s.StartedAt
+		}
+		if last.Before(start) {
+			continue
+		}
+		costs[last.Format("2006-01-02")] += s.TotalCost
+	}
+	return costs
+}
+
+// WeekStart returns local midnight of the Monday starting t's week.
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// MonthStart returns local midnight of the first day of t's month.
+func MonthStart(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
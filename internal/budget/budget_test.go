@@ -0,0 +1,91 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// fakeHistory serves fixed per-session costs.
+type fakeHistory struct {
+	days   []SessionDay
+	before map[string]float64
+}
+
+func (f *fakeHistory) SessionCosts(time.Time) ([]SessionDay, map[string]float64, error) {
+	return f.days, f.before, nil
+}
+
+func TestPeriodStarts(t *testing.T) {
+	// Thursday 2026-03-12.
+	now := time.Date(2026, 3, 12, 15, 4, 5, 0, time.Local)
+	if got, want := WeekStart(now), time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("WeekStart: got %v, want %v", got, want)
+	}
+	sunday := time.Date(2026, 3, 15, 23, 0, 0, 0, time.Local)
+	if got, want := WeekStart(sunday), time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("WeekStart on Sunday: got %v, want %v", got, want)
+	}
+	if got, want := MonthStart(now), time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("MonthStart: got %v, want %v", got, want)
+	}
+}
+
+func TestTracker_FromCostHistory(t *testing.T) {
+	history := &fakeHistory{
+		days: []SessionDay{
+			{SessionID: "a", Date: "2026-03-02", Cost: 104},
+			{SessionID: "a", Date: "2026-03-09", Cost: 110},
+			{SessionID: "b", Date: "2026-03-08", Cost: 20}, // last week
+			{SessionID: "b", Date: "2026-03-12", Cost: 30},
+			{SessionID: "c", Date: "2026-03-10", Cost: 8},
+			{SessionID: "c", Date: "2026-03-11", Cost: 3}, // restarted counter
+		},
+		// a spent $99 last month.
+		before: map[string]float64{"a": 99},
+	}
+	tr := NewTracker(config.BudgetConfig{WeeklyUSD: 50, MonthlyUSD: 100}, state.NewMemoryStore(), WithCostHistory(history))
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.Local)
+
+	got := tr.Status(now)
+	if len(got) != 2 || got[0].Period != Weekly || got[1].Period != Monthly {
+		t.Fatalf("expected weekly and monthly statuses, got %+v", got)
+	}
+	// a $6, b $10 and c $8 + $3 this week.
+	if got[0].Spent != 27 || got[0].Percent() != 54 || got[0].Remaining() != 23 {
+		t.Errorf("weekly: spent=%v percent=%v remaining=%v, want 27, 54, 23", got[0].Spent, got[0].Percent(), got[0].Remaining())
+	}
+	if got[1].Spent != 52 {
+		t.Errorf("monthly spent: got %v, want 52", got[1].Spent)
+	}
+	if !got[1].End.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("monthly end: got %v", got[1].End)
+	}
+
+	// Cached until the refresh interval passes.
+	history.days[3].Cost = 130
+	if s := tr.Status(now.Add(time.Second)); s[0].Spent != 27 {
+		t.Errorf("status should be cached, got spent %v", s[0].Spent)
+	}
+	if s := tr.Status(now.Add(refreshInterval)); s[0].Spent != 127 || s[0].Remaining() != 0 {
+		t.Errorf("status should be refreshed: spent=%v remaining=%v", s[0].Spent, s[0].Remaining())
+	}
+}
+
+func TestTracker_FromSessionsWithoutHistory(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	store.AddMetric("recent", state.Metric{Name: "claude_code.cost.usage", Value: 4, Timestamp: now})
+
+	tr := NewTracker(config.BudgetConfig{MonthlyUSD: 10}, store)
+	got := tr.Status(now)
+	if len(got) != 1 || got[0].Period != Monthly || got[0].Spent != 4 {
+		t.Errorf("expected $4 of the monthly budget from live sessions, got %+v", got)
+	}
+
+	if NewTracker(config.BudgetConfig{}, store).Status(now) != nil {
+		t.Error("no budgets configured should give no statuses")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/nixlim/cc-top/internal/clock"
 	"github.com/nixlim/cc-top/internal/state"
 )
 
@@ -27,88 +28,42 @@ type Calculator struct {
 	prevCost    float64
 	prevTokens  int64
 	initialized bool
+
+	clock clock.Clock
+
+	// history feeds the forecast; it is re-read at the start of each hour.
+	history     HistorySource
+	hist        SpendHistory
+	histOK      bool
+	histFetched time.Time
+}
+
+// CalculatorOption configures a Calculator.
+type CalculatorOption func(*Calculator)
+
+// WithClock sets the clock Compute reads; tests use a clock.Fake to
+// simulate hours of telemetry.
+func WithClock(c clock.Clock) CalculatorOption {
+	return func(calc *Calculator) { calc.clock = c }
 }
 
 // NewCalculator creates a new Calculator with the given color thresholds.
-func NewCalculator(thresholds Thresholds) *Calculator {
-	return &Calculator{
+func NewCalculator(thresholds Thresholds, opts ...CalculatorOption) *Calculator {
+	c := &Calculator{
 		thresholds: thresholds,
+		clock:      clock.Real{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Compute calculates the current burn rate from the state store data.
 // It should be called periodically (e.g., every 500ms) to update the
 // rolling window with fresh data.
 func (c *Calculator) Compute(store state.Store) BurnRate {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	now := time.Now()
-	totalCost := store.GetAggregatedCost()
-
-	// Calculate total tokens across all sessions.
-	sessions := store.ListSessions()
-	var totalTokens int64
-	for _, s := range sessions {
-		totalTokens += s.TotalTokens
-	}
-
-	// Record samples for rate calculation.
-	if !c.initialized {
-		c.prevCost = totalCost
-		c.prevTokens = totalTokens
-		c.initialized = true
-		c.costSamples = append(c.costSamples, costSample{cost: totalCost, at: now})
-		c.tokenSamples = append(c.tokenSamples, tokenSample{tokens: totalTokens, at: now})
-		return BurnRate{
-			TotalCost:     totalCost,
-			HourlyRate:    0,
-			Trend:         TrendFlat,
-			TokenVelocity: 0,
-			PerModel:      computePerModel(sessions, totalCost, 0),
-		}
-	}
-
-	// Handle counter resets: if the total went down, treat the previous as 0.
-	costDelta := totalCost - c.prevCost
-	if costDelta < 0 {
-		costDelta = totalCost
-	}
-
-	tokenDelta := totalTokens - c.prevTokens
-	if tokenDelta < 0 {
-		tokenDelta = totalTokens
-	}
-
-	c.prevCost = totalCost
-	c.prevTokens = totalTokens
-
-	c.costSamples = append(c.costSamples, costSample{cost: totalCost, at: now})
-	c.tokenSamples = append(c.tokenSamples, tokenSample{tokens: totalTokens, at: now})
-
-	// Prune samples older than 2 * windowDuration (need two windows for trend).
-	cutoff := now.Add(-2 * windowDuration)
-	c.costSamples = pruneCostSamples(c.costSamples, cutoff)
-	c.tokenSamples = pruneTokenSamples(c.tokenSamples, cutoff)
-
-	// Compute hourly rate from the current 5-minute window.
-	hourlyRate := c.computeHourlyRate(now)
-
-	// Compute trend by comparing current vs previous window.
-	trend := c.computeTrend(now)
-
-	// Compute token velocity (tokens/minute).
-	tokenVelocity := c.computeTokenVelocity(now)
-
-	return BurnRate{
-		TotalCost:         totalCost,
-		HourlyRate:        hourlyRate,
-		Trend:             trend,
-		TokenVelocity:     tokenVelocity,
-		PerModel:          computePerModel(sessions, totalCost, hourlyRate),
-		DailyProjection:   hourlyRate * 24,
-		MonthlyProjection: hourlyRate * 720,
-	}
+	return c.ComputeWithTime(store, c.clock.Now())
 }
 
 // computeHourlyRate calculates the cost rate extrapolated to an hourly rate
@@ -284,6 +239,14 @@ func (c *Calculator) computeTokenVelocity(now time.Time) float64 {
 	return float64(tokenDiff) / minutes
 }
 
+// SetThresholds replaces the color thresholds, e.g. after a config reload.
+func (c *Calculator) SetThresholds(thresholds Thresholds) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.thresholds = thresholds
+}
+
 // ColorForRate returns the display color for the given hourly rate
 // based on the calculator's configured thresholds.
 func (c *Calculator) ColorForRate(hourlyRate float64) RateColor {
@@ -361,20 +324,45 @@ func computePerModel(sessions []state.SessionData, totalCost, hourlyRate float64
 	return result
 }
 
-// ComputeWithTime is like Compute but uses a specific timestamp instead of
-// time.Now(). This is primarily useful for testing deterministic behavior.
+// computeTodayByHour replays each session's cumulative cost counters and
+// attributes every increase to the local hour of the metric's timestamp,
+// keeping only increases from now's calendar day. Counter resets are
+// handled the same way as in the state store. Because recovered sessions
+// carry their metrics, the buckets survive a restart when persistence is on.
+func computeTodayByHour(sessions []state.SessionData, now time.Time) [24]float64 {
+	var buckets [24]float64
+	y, mo, d := now.Date()
+	for i := range sessions {
+		for _, inc := range sessionIncrements(sessions[i], "claude_code.cost.usage") {
+			ts := inc.at.In(now.Location())
+			if ty, tm, td := ts.Date(); ty != y || tm != mo || td != d {
+				continue
+			}
+			buckets[ts.Hour()] += inc.delta
+		}
+	}
+	return buckets
+}
+
+// ComputeWithTime is like Compute but takes the current time, for callers
+// that evaluate several things at one instant.
 func (c *Calculator) ComputeWithTime(store state.Store, now time.Time) BurnRate {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	totalCost := store.GetAggregatedCost()
+	snap := store.Snapshot()
+	totalCost := snap.TotalCost
 
-	sessions := store.ListSessions()
+	// Calculate total tokens across all sessions.
+	sessions := snap.Sessions
 	var totalTokens int64
 	for _, s := range sessions {
 		totalTokens += s.TotalTokens
 	}
 
+	todayByHour := computeTodayByHour(sessions, now)
+
+	// Record samples for rate calculation.
 	if !c.initialized {
 		c.prevCost = totalCost
 		c.prevTokens = totalTokens
@@ -387,13 +375,17 @@ func (c *Calculator) ComputeWithTime(store state.Store, now time.Time) BurnRate
 			Trend:         TrendFlat,
 			TokenVelocity: 0,
 			PerModel:      computePerModel(sessions, totalCost, 0),
+			TodayByHour:   todayByHour,
+			Forecast:      c.forecast(todayByHour, now),
 		}
 	}
 
+	// Handle counter resets: if the total went down, treat the previous as 0.
 	costDelta := totalCost - c.prevCost
 	if costDelta < 0 {
 		costDelta = totalCost
 	}
+
 	tokenDelta := totalTokens - c.prevTokens
 	if tokenDelta < 0 {
 		tokenDelta = totalTokens
@@ -405,12 +397,18 @@ func (c *Calculator) ComputeWithTime(store state.Store, now time.Time) BurnRate
 	c.costSamples = append(c.costSamples, costSample{cost: totalCost, at: now})
 	c.tokenSamples = append(c.tokenSamples, tokenSample{tokens: totalTokens, at: now})
 
+	// Prune samples older than 2 * windowDuration (need two windows for trend).
 	cutoff := now.Add(-2 * windowDuration)
 	c.costSamples = pruneCostSamples(c.costSamples, cutoff)
 	c.tokenSamples = pruneTokenSamples(c.tokenSamples, cutoff)
 
+	// Compute hourly rate from the current 5-minute window.
 	hourlyRate := c.computeHourlyRate(now)
+
+	// Compute trend by comparing current vs previous window.
 	trend := c.computeTrend(now)
+
+	// Compute token velocity (tokens/minute).
 	tokenVelocity := c.computeTokenVelocity(now)
 
 	return BurnRate{
@@ -421,5 +419,131 @@ func (c *Calculator) ComputeWithTime(store state.Store, now time.Time) BurnRate
 		PerModel:          computePerModel(sessions, totalCost, hourlyRate),
 		DailyProjection:   hourlyRate * 24,
 		MonthlyProjection: hourlyRate * 720,
+		TodayByHour:       todayByHour,
+		Forecast:          c.forecast(todayByHour, now),
+	}
+}
+
+// forecast fits the spend history to today's spend, re-reading the history
+// when the hour has changed since it was last read.
+func (c *Calculator) forecast(todayByHour [24]float64, now time.Time) Forecast {
+	if c.history == nil {
+		return Forecast{}
+	}
+	if hour := now.Truncate(time.Hour); !hour.Equal(c.histFetched) {
+		c.hist, c.histOK = c.history.SpendHistory(now)
+		c.histFetched = hour
+	}
+	if !c.histOK {
+		return Forecast{}
+	}
+	return forecast(c.hist, todayByHour, now)
+}
+
+// increment is one increase of a cumulative counter, attributed to the
+// timestamp of the metric that reported it.
+type increment struct {
+	delta float64
+	model string
+	at    time.Time
+}
+
+// sessionIncrements replays a session's cumulative counters with the given
+// metric name and returns every increase, handling counter resets the same
+// way as the state store.
+func sessionIncrements(s state.SessionData, name string) []increment {
+	var incs []increment
+	prev := make(map[string]float64)
+	for _, m := range s.Metrics {
+		if m.Name != name {
+			continue
+		}
+		key := state.MetricKey(m.Name, m.Attributes)
+		last, ok := prev[key]
+		prev[key] = m.Value
+		delta := m.Value
+		if ok && m.Value >= last {
+			delta = m.Value - last
+		}
+		if delta <= 0 || m.Timestamp.IsZero() {
+			continue
+		}
+		incs = append(incs, increment{delta: delta, model: m.Attributes["model"], at: m.Timestamp})
+	}
+	return incs
+}
+
+// sumBetween totals the increments in (from, to].
+func sumBetween(incs []increment, from, to time.Time) float64 {
+	var sum float64
+	for _, inc := range incs {
+		if inc.at.After(from) && !inc.at.After(to) {
+			sum += inc.delta
+		}
+	}
+	return sum
+}
+
+// ComputeSession calculates the burn rate of a single session from its own
+// metrics. Unlike Compute it keeps no samples between calls: the rolling
+// windows are rebuilt from the session's metric timestamps, so it can be
+// called for any session at any time without disturbing the global rate.
+func (c *Calculator) ComputeSession(s state.SessionData, now time.Time) BurnRate {
+	costIncs := sessionIncrements(s, "claude_code.cost.usage")
+	tokenIncs := sessionIncrements(s, "claude_code.token.usage")
+
+	// A session younger than the window is rated over its lifetime so far,
+	// but never over less than a minute.
+	window := windowDuration
+	if !s.StartedAt.IsZero() {
+		window = min(window, max(now.Sub(s.StartedAt), time.Minute))
+	}
+	windowStart := now.Add(-window)
+
+	hourlyRate := sumBetween(costIncs, windowStart, now) / window.Hours()
+	tokenVelocity := sumBetween(tokenIncs, windowStart, now) / window.Minutes()
+
+	trend := TrendFlat
+	prevRate := sumBetween(costIncs, now.Add(-2*windowDuration), now.Add(-windowDuration)) / windowDuration.Hours()
+	currentRate := sumBetween(costIncs, now.Add(-windowDuration), now) / windowDuration.Hours()
+	if diff := currentRate - prevRate; diff > 0.001 {
+		trend = TrendUp
+	} else if diff < -0.001 {
+		trend = TrendDown
+	}
+
+	modelCosts := make(map[string]float64)
+	modelWindow := make(map[string]float64)
+	for _, inc := range costIncs {
+		model := inc.model
+		if model == "" {
+			model = "unknown"
+		}
+		modelCosts[model] += inc.delta
+		if inc.at.After(windowStart) && !inc.at.After(now) {
+			modelWindow[model] += inc.delta
+		}
+	}
+	perModel := make([]ModelBurnRate, 0, len(modelCosts))
+	for model, cost := range modelCosts {
+		perModel = append(perModel, ModelBurnRate{
+			Model:      model,
+			HourlyRate: modelWindow[model] / window.Hours(),
+			TotalCost:  cost,
+		})
+	}
+	sort.Slice(perModel, func(i, j int) bool {
+		return perModel[i].TotalCost > perModel[j].TotalCost
+	})
+
+	return BurnRate{
+		TotalCost:         s.TotalCost,
+		HourlyRate:        hourlyRate,
+		Trend:             trend,
+		TokenVelocity:     tokenVelocity,
+		PerModel:          perModel,
+		DailyProjection:   hourlyRate * 24,
+		MonthlyProjection: hourlyRate * 720,
+		TodayByHour:       computeTodayByHour([]state.SessionData{s}, now),
 	}
 }
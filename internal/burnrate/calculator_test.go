@@ -405,3 +405,116 @@ func TestBurnRate_ProjectionsFromHourlyRate(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeTodayByHour(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 10, 0, 0, time.Local) }
+	cost := func(v float64, ts time.Time, model string) state.Metric {
+		return state.Metric{Name: "claude_code.cost.usage", Value: v, Timestamp: ts, Attributes: map[string]string{"model": model}}
+	}
+
+	sessions := []state.SessionData{
+		{
+			SessionID: "s1",
+			Metrics: []state.Metric{
+				cost(1.0, at(9, 23), "opus"),     // yesterday: excluded
+				cost(1.5, at(10, 9), "opus"),     // +0.5 at 09
+				cost(3.5, at(10, 9), "opus"),     // +2.0 at 09
+				cost(0.25, at(10, 11), "sonnet"), // separate counter: +0.25 at 11
+				cost(0.5, at(10, 14), "opus"),    // counter reset: +0.5 at 14
+			},
+		},
+		{
+			SessionID: "s2",
+			Metrics: []state.Metric{
+				cost(2.0, at(10, 14), "opus"),
+				{Name: "claude_code.token.usage", Value: 999, Timestamp: at(10, 14)},
+			},
+		},
+	}
+
+	got := computeTodayByHour(sessions, now)
+
+	want := map[int]float64{9: 2.5, 11: 0.25, 14: 2.5}
+	for h := range 24 {
+		if math.Abs(got[h]-want[h]) > 1e-9 {
+			t.Errorf("hour %02d = %v, want %v", h, got[h], want[h])
+		}
+	}
+}
+
+func TestBurnRate_ComputeSession(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	start := now.Add(-20 * time.Minute)
+
+	store.AddMetric("sess-1", state.Metric{Name: "claude_code.session.count", Value: 1, Timestamp: start})
+	// Previous window (5-10 min ago): $0.10. Current window: $0.50 over two models.
+	addCostMetric(store, "sess-1", 0.10, now.Add(-8*time.Minute))
+	addCostMetric(store, "sess-1", 0.40, now.Add(-2*time.Minute))
+	store.AddMetric("sess-1", state.Metric{
+		Name:       "claude_code.cost.usage",
+		Value:      0.20,
+		Attributes: map[string]string{"model": "claude-haiku-4-5"},
+		Timestamp:  now.Add(-time.Minute),
+	})
+	addTokenMetric(store, "sess-1", 1000, now.Add(-9*time.Minute))
+	addTokenMetric(store, "sess-1", 6000, now.Add(-time.Minute))
+
+	// Another session's spend must not leak into sess-1's rate.
+	addCostMetric(store, "sess-2", 5.00, now.Add(-time.Minute))
+
+	calc := NewCalculator(DefaultThresholds())
+	br := calc.ComputeSession(*store.GetSession("sess-1"), now)
+
+	if math.Abs(br.TotalCost-0.60) > 0.001 {
+		t.Errorf("TotalCost: want 0.60, got %.4f", br.TotalCost)
+	}
+	// $0.50 in 5 minutes = $6.00/hr.
+	if math.Abs(br.HourlyRate-6.0) > 0.001 {
+		t.Errorf("HourlyRate: want 6.00, got %.4f", br.HourlyRate)
+	}
+	if br.Trend != TrendUp {
+		t.Errorf("Trend: want up, got %s", br.Trend)
+	}
+	// 5000 tokens in 5 minutes.
+	if math.Abs(br.TokenVelocity-1000) > 0.001 {
+		t.Errorf("TokenVelocity: want 1000, got %.4f", br.TokenVelocity)
+	}
+	if math.Abs(br.DailyProjection-144) > 0.01 || math.Abs(br.MonthlyProjection-4320) > 0.1 {
+		t.Errorf("projections: got $%.2f/day $%.2f/mon", br.DailyProjection, br.MonthlyProjection)
+	}
+	if len(br.PerModel) != 2 || br.PerModel[0].Model != "claude-sonnet-4-5-20250929" {
+		t.Fatalf("PerModel: got %+v", br.PerModel)
+	}
+	if math.Abs(br.PerModel[1].HourlyRate-2.4) > 0.001 {
+		t.Errorf("haiku hourly rate: want 2.40, got %.4f", br.PerModel[1].HourlyRate)
+	}
+
+	// ComputeSession keeps no state, so the global rate starts fresh.
+	if global := calc.Compute(store); global.HourlyRate != 0 {
+		t.Errorf("global rate after ComputeSession: want 0 on first sample, got %.4f", global.HourlyRate)
+	}
+}
+
+func TestBurnRate_ComputeSessionYoung(t *testing.T) {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	store.AddMetric("sess-1", state.Metric{Name: "claude_code.session.count", Value: 1, Timestamp: now.Add(-2 * time.Minute)})
+	addCostMetric(store, "sess-1", 0.10, now.Add(-time.Minute))
+
+	br := NewCalculator(DefaultThresholds()).ComputeSession(*store.GetSession("sess-1"), now)
+	// $0.10 over a 2-minute-old session = $3.00/hr.
+	if math.Abs(br.HourlyRate-3.0) > 0.001 {
+		t.Errorf("HourlyRate: want 3.00, got %.4f", br.HourlyRate)
+	}
+}
+
+func TestBurnRate_SetThresholds(t *testing.T) {
+	calc := NewCalculator(DefaultThresholds())
+	calc.SetThresholds(Thresholds{GreenBelow: 1.00, YellowBelow: 5.00})
+
+	if got := calc.ColorForRate(3.00); got != ColorYellow {
+		t.Errorf("ColorForRate(3.00) after SetThresholds: expected %v, got %v", ColorYellow, got)
+	}
+}
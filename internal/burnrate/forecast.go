@@ -0,0 +1,146 @@
+package burnrate
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// forecastAlpha is the EWMA weight of the most recent day when fitting
+	// the spend of each hour of the day, so last week's quiet Monday fades
+	// within a few days.
+	forecastAlpha = 0.3
+
+	// forecastZ widens the forecast to an 80% band around the likely value.
+	forecastZ = 1.28
+
+	// forecastMinHours is one day of history; with less the Burn Rate panel
+	// falls back to extrapolating the current hourly rate.
+	forecastMinHours = 24
+)
+
+// HourSpend is the spend in one completed hour.
+type HourSpend struct {
+	Hour time.Time // start of the hour
+	Cost float64
+}
+
+// SpendHistory is the past spend a forecast is fitted to.
+type SpendHistory struct {
+	// Hours holds the completed hours of the trailing window that have burn
+	// rate snapshots, oldest first. Hours cc-top was not running are absent.
+	Hours []HourSpend
+	// MonthBeforeToday is the spend in the current month before today.
+	MonthBeforeToday float64
+}
+
+// HistorySource supplies spend history for forecasts. It is queried once per
+// hour, so an implementation may read it from storage.
+type HistorySource interface {
+	SpendHistory(now time.Time) (h SpendHistory, ok bool)
+}
+
+// WithHistory makes Compute forecast end-of-day and end-of-month spend from
+// the history h supplies.
+func WithHistory(h HistorySource) CalculatorOption {
+	return func(calc *Calculator) { calc.history = h }
+}
+
+// Range is a forecast value with its low and high bounds.
+type Range struct {
+	Low, Likely, High float64
+}
+
+// Forecast predicts where spend will end up today and this month.
+type Forecast struct {
+	EndOfDay   Range
+	EndOfMonth Range
+	Hours      int // hours of history the forecast was fitted to
+}
+
+// Valid reports whether there was enough history to forecast.
+func (f Forecast) Valid() bool {
+	return f.Hours >= forecastMinHours
+}
+
+// ewma is an exponentially weighted mean and variance.
+type ewma struct {
+	mean, variance float64
+	n              int
+}
+
+func (e *ewma) add(x float64) {
+	if e.n == 0 {
+		e.mean = x
+		e.n = 1
+		return
+	}
+	diff := x - e.mean
+	incr := forecastAlpha * diff
+	e.mean += incr
+	e.variance = (1 - forecastAlpha) * (e.variance + diff*incr)
+	e.n++
+}
+
+// forecast fits an EWMA of the spend in each local hour of the day, so that
+// nights and working hours are each compared with their own past, and sums
+// the fitted hours still to come onto what was spent so far. Hours are
+// treated as independent when adding up variances. An hour of the day with
+// no history uses the EWMA across all hours.
+func forecast(h SpendHistory, todayByHour [24]float64, now time.Time) Forecast {
+	var slots [24]ewma
+	var all ewma
+	for _, hs := range h.Hours {
+		slots[hs.Hour.In(now.Location()).Hour()].add(hs.Cost)
+		all.add(hs.Cost)
+	}
+	hourFit := func(hour int) ewma {
+		if slots[hour].n == 0 {
+			return all
+		}
+		return slots[hour]
+	}
+
+	var spentToday float64
+	for _, c := range todayByHour {
+		spentToday += c
+	}
+
+	// The rest of the current hour, then every later hour of today.
+	hourStart := now.Truncate(time.Hour)
+	rest := 1 - now.Sub(hourStart).Hours()
+	cur := hourFit(now.Hour())
+	restOfDay, restOfDayVar := rest*cur.mean, rest*rest*cur.variance
+	for hour := now.Hour() + 1; hour < 24; hour++ {
+		fit := hourFit(hour)
+		restOfDay += fit.mean
+		restOfDayVar += fit.variance
+	}
+
+	var day, dayVar float64
+	for hour := range 24 {
+		fit := hourFit(hour)
+		day += fit.mean
+		dayVar += fit.variance
+	}
+	y, m, d := now.Date()
+	daysLeft := float64(time.Date(y, m+1, 0, 0, 0, 0, 0, now.Location()).Day() - d)
+
+	return Forecast{
+		EndOfDay:   forecastRange(spentToday, restOfDay, restOfDayVar),
+		EndOfMonth: forecastRange(h.MonthBeforeToday+spentToday, restOfDay+daysLeft*day, restOfDayVar+daysLeft*dayVar),
+		Hours:      len(h.Hours),
+	}
+}
+
+// forecastRange is spent plus the expected spend still to come, with a band
+// that never dips below what has already been spent.
+func forecastRange(spent, expected, variance float64) Range {
+	likely := spent + expected
+	spread := forecastZ * math.Sqrt(variance)
+	return Range{
+		Low:    max(spent, likely-spread),
+		Likely: likely,
+		High:   likely + spread,
+	}
+}
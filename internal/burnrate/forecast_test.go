@@ -0,0 +1,113 @@
+package burnrate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// hoursOf returns history covering the days before now, with cost(hour of
+// the day, day index) spent in each hour.
+func hoursOf(now time.Time, days int, cost func(hour, day int) float64) []HourSpend {
+	start := time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, now.Location())
+	var hours []HourSpend
+	for at := start; at.Before(now.Truncate(time.Hour)); at = at.Add(time.Hour) {
+		hours = append(hours, HourSpend{Hour: at, Cost: cost(at.Hour(), int(at.Sub(start).Hours())/24)})
+	}
+	return hours
+}
+
+func TestForecast_SteadySpend(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+	var today [24]float64
+	for h := range 12 {
+		today[h] = 1
+	}
+	f := forecast(SpendHistory{
+		Hours:            hoursOf(now, 2, func(int, int) float64 { return 1 }),
+		MonthBeforeToday: 100,
+	}, today, now)
+
+	if !f.Valid() {
+		t.Fatalf("two days of history should be enough, got %d hours", f.Hours)
+	}
+	// $12 so far, half of this hour and 11 more hours at $1.
+	if want := (Range{Low: 23.5, Likely: 23.5, High: 23.5}); f.EndOfDay != want {
+		t.Errorf("EndOfDay = %+v, want %+v", f.EndOfDay, want)
+	}
+	// Plus 15 more days at $24.
+	if want := (Range{Low: 483.5, Likely: 483.5, High: 483.5}); f.EndOfMonth != want {
+		t.Errorf("EndOfMonth = %+v, want %+v", f.EndOfMonth, want)
+	}
+}
+
+func TestForecast_WorkingHours(t *testing.T) {
+	// $2-$4 an hour from 9 to 17, nothing at night.
+	work := func(hour, day int) float64 {
+		if hour < 9 || hour >= 17 {
+			return 0
+		}
+		return 2 + float64((hour+day)%3)
+	}
+	hist := SpendHistory{Hours: hoursOf(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 7, work)}
+
+	var today [24]float64
+	for h := 9; h < 17; h++ {
+		today[h] = 3
+	}
+	evening := forecast(hist, today, time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC))
+	if evening.EndOfDay.Likely != 24 || evening.EndOfDay.High != 24 {
+		t.Errorf("no spend is expected overnight, got %+v", evening.EndOfDay)
+	}
+
+	var morning [24]float64
+	f := forecast(hist, morning, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC))
+	r := f.EndOfDay
+	if r.Likely < 20 || r.Likely > 28 {
+		t.Errorf("Likely = %v, want about a working day's $24", r.Likely)
+	}
+	if !(r.Low < r.Likely && r.Likely < r.High) || r.Low < 0 {
+		t.Errorf("EndOfDay = %+v, want a band around the likely value", r)
+	}
+	if m := f.EndOfMonth; m.High-m.Likely <= r.High-r.Likely {
+		t.Errorf("the month's band %+v should be wider than the day's %+v", m, r)
+	}
+}
+
+type fakeHistory struct {
+	h     SpendHistory
+	calls int
+}
+
+func (f *fakeHistory) SpendHistory(time.Time) (SpendHistory, bool) {
+	f.calls++
+	return f.h, true
+}
+
+func TestBurnRate_ForecastFromHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	src := &fakeHistory{h: SpendHistory{Hours: hoursOf(now, 1, func(int, int) float64 { return 1 })[:10]}}
+	calc := NewCalculator(DefaultThresholds(), WithHistory(src))
+	store := state.NewMemoryStore()
+	addCostMetric(store, "sess-001", 5, now)
+
+	if br := calc.ComputeWithTime(store, now); br.Forecast.Valid() {
+		t.Errorf("10 hours of history should not be enough, got %+v", br.Forecast)
+	}
+
+	src.h.Hours = hoursOf(now, 2, func(int, int) float64 { return 1 })
+	calc.ComputeWithTime(store, now.Add(10*time.Minute))
+	if src.calls != 1 {
+		t.Errorf("history read %d times within an hour, want 1", src.calls)
+	}
+
+	br := calc.ComputeWithTime(store, now.Add(time.Hour))
+	if src.calls != 2 {
+		t.Errorf("history should be re-read each hour, read %d times", src.calls)
+	}
+	if !br.Forecast.Valid() || math.Abs(br.Forecast.EndOfDay.Likely-(5+11)) > 1e-9 {
+		t.Errorf("Forecast = %+v, want $5 spent plus $11 to come", br.Forecast)
+	}
+}
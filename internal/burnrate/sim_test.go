@@ -0,0 +1,127 @@
+package burnrate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/clock"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// simTick is how often a simulation reports telemetry and computes the burn
+// rate, standing in for the exporter interval and the TUI refresh.
+const simTick = 10 * time.Second
+
+// simulation drives a calculator through hours of telemetry on a fake
+// clock, so rates, projections and trends can be checked exactly.
+type simulation struct {
+	clock  *clock.Fake
+	store  *state.MemoryStore
+	calc   *Calculator
+	cost   float64
+	tokens float64
+}
+
+func newSimulation() *simulation {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	sim := &simulation{
+		clock: clk,
+		store: state.NewMemoryStore(),
+		calc:  NewCalculator(DefaultThresholds(), WithClock(clk)),
+	}
+	sim.report()
+	return sim
+}
+
+// report sends the cumulative counters at the current time and computes
+// the burn rate, like one refresh of the dashboard.
+func (s *simulation) report() BurnRate {
+	now := s.clock.Now()
+	addCostMetric(s.store, "sess-1", s.cost, now)
+	addTokenMetric(s.store, "sess-1", s.tokens, now)
+	return s.calc.Compute(s.store)
+}
+
+// run spends at costPerHour and tokensPerMin for d and returns the burn
+// rate computed at the end.
+func (s *simulation) run(d time.Duration, costPerHour, tokensPerMin float64) BurnRate {
+	var br BurnRate
+	for elapsed := time.Duration(0); elapsed < d; elapsed += simTick {
+		s.clock.Advance(simTick)
+		s.cost += costPerHour * simTick.Hours()
+		s.tokens += tokensPerMin * simTick.Minutes()
+		br = s.report()
+	}
+	return br
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestSimulation_SteadySpendProjections(t *testing.T) {
+	sim := newSimulation()
+
+	br := sim.run(3*time.Hour, 6, 1200)
+
+	if !approxEqual(br.TotalCost, 18) {
+		t.Errorf("TotalCost = %f, want 18", br.TotalCost)
+	}
+	if !approxEqual(br.HourlyRate, 6) {
+		t.Errorf("HourlyRate = %f, want 6", br.HourlyRate)
+	}
+	if !approxEqual(br.DailyProjection, 144) || !approxEqual(br.MonthlyProjection, 4320) {
+		t.Errorf("projections = %f/day, %f/month, want 144 and 4320", br.DailyProjection, br.MonthlyProjection)
+	}
+	if !approxEqual(br.TokenVelocity, 1200) {
+		t.Errorf("TokenVelocity = %f, want 1200", br.TokenVelocity)
+	}
+	if br.Trend != TrendFlat {
+		t.Errorf("Trend = %v, want flat for steady spend", br.Trend)
+	}
+}
+
+func TestSimulation_TrendFollowsRateChanges(t *testing.T) {
+	sim := newSimulation()
+	sim.run(time.Hour, 6, 1000)
+
+	// One window at double the rate: the hourly rate only reflects the
+	// current window and the trend compares it with the one before.
+	br := sim.run(windowDuration, 12, 1000)
+	if !approxEqual(br.HourlyRate, 12) || !approxEqual(br.DailyProjection, 288) {
+		t.Errorf("after the surge HourlyRate = %f, DailyProjection = %f, want 12 and 288", br.HourlyRate, br.DailyProjection)
+	}
+	if br.Trend != TrendUp {
+		t.Errorf("Trend = %v, want up after the rate doubled", br.Trend)
+	}
+
+	br = sim.run(windowDuration, 3, 1000)
+	if !approxEqual(br.HourlyRate, 3) {
+		t.Errorf("after the slowdown HourlyRate = %f, want 3", br.HourlyRate)
+	}
+	if br.Trend != TrendDown {
+		t.Errorf("Trend = %v, want down after the rate dropped", br.Trend)
+	}
+
+	br = sim.run(windowDuration, 3, 1000)
+	if br.Trend != TrendFlat {
+		t.Errorf("Trend = %v, want flat once both windows match", br.Trend)
+	}
+}
+
+func TestSimulation_IdleSpendDecaysToZero(t *testing.T) {
+	sim := newSimulation()
+	sim.run(2*time.Hour, 6, 1000)
+
+	br := sim.run(windowDuration, 0, 0)
+	if br.HourlyRate != 0 || br.TokenVelocity != 0 {
+		t.Errorf("a window without spend should read zero, got $%f/hr and %f tokens/min", br.HourlyRate, br.TokenVelocity)
+	}
+	if br.Trend != TrendDown {
+		t.Errorf("Trend = %v, want down when spending stops", br.Trend)
+	}
+	if !approxEqual(br.TotalCost, 12) {
+		t.Errorf("TotalCost = %f, want 12", br.TotalCost)
+	}
+}
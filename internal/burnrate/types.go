@@ -16,8 +16,10 @@ type BurnRate struct {
 	Trend             TrendDirection
 	TokenVelocity     float64 // tokens per minute
 	PerModel          []ModelBurnRate
-	DailyProjection   float64 // HourlyRate * 24
-	MonthlyProjection float64 // HourlyRate * 720
+	DailyProjection   float64     // HourlyRate * 24
+	MonthlyProjection float64     // HourlyRate * 720
+	TodayByHour       [24]float64 // cost accrued in each local hour of today
+	Forecast          Forecast    // fitted to spend history; global rate only
 }
 
 // TrendDirection indicates rate change direction.
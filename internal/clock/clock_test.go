@@ -0,0 +1,21 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	if !f.Now().Equal(start) {
+		t.Fatalf("Now = %v, want %v", f.Now(), start)
+	}
+	if got := f.Advance(90 * time.Minute); !got.Equal(start.Add(90*time.Minute)) || !f.Now().Equal(got) {
+		t.Errorf("Advance = %v, Now = %v, want %v", got, f.Now(), start.Add(90*time.Minute))
+	}
+	f.Set(start)
+	if !f.Now().Equal(start) {
+		t.Errorf("after Set, Now = %v, want %v", f.Now(), start)
+	}
+}
@@ -4,9 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -17,18 +25,101 @@ type Config struct {
 	Alerts   AlertsConfig
 	Display  DisplayConfig
 	Storage  StorageConfig
+	Budget   BudgetConfig
+	Plan     PlanConfig
+	Projects ProjectsConfig
 	Models   map[string]int
 	Pricing  map[string][4]float64
+	// PricingTiers holds prices for non-standard service tiers, keyed by
+	// tier name ("batch", "priority") and then model.
+	PricingTiers map[string]map[string][4]float64
+	// PricingSync keeps Pricing and PricingTiers up to date from a
+	// published pricing manifest.
+	PricingSync PricingSyncConfig
+	// Serve and Push connect cc-top instances into a team-wide view.
+	Serve ServeConfig
+	Push  PushConfig
 }
 
+// StandardTier is the service tier priced by Config.Pricing.
+const StandardTier = "standard"
+
 type ReceiverConfig struct {
 	GRPCPort int    `toml:"grpc_port"`
 	HTTPPort int    `toml:"http_port"`
 	Bind     string `toml:"bind"`
+	// MetricsPort serves a Prometheus /metrics endpoint when non-zero.
+	MetricsPort int `toml:"metrics_port"`
+	// AuthToken, when set, is the bearer token every OTLP request must
+	// carry in its Authorization header.
+	AuthToken string            `toml:"auth_token"`
+	TLS       ReceiverTLSConfig `toml:"tls"`
+	Admission AdmissionConfig   `toml:"admission"`
+	Forward   ForwardConfig     `toml:"forward"`
+	Redaction RedactionConfig   `toml:"redaction"`
+}
+
+// ReceiverTLSConfig serves the gRPC and HTTP receivers over TLS when
+// CertFile and KeyFile are set. ClientCAFile additionally requires every
+// client to present a certificate signed by one of its CAs (mTLS).
+type ReceiverTLSConfig struct {
+	CertFile     string `toml:"cert_file"`
+	KeyFile      string `toml:"key_file"`
+	ClientCAFile string `toml:"client_ca_file"`
+}
+
+// Enabled reports whether the receivers serve TLS.
+func (t ReceiverTLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// ForwardConfig re-exports every received OTLP payload to an upstream
+// collector. An empty Endpoint disables forwarding.
+type ForwardConfig struct {
+	// Endpoint is the collector's base URL, e.g. "https://otel.example.com:4318";
+	// the scheme selects plain text (http) or TLS (https).
+	Endpoint string `toml:"endpoint"`
+	// Protocol is "http/protobuf" (POST to /v1/metrics and /v1/logs) or "grpc".
+	Protocol       string            `toml:"protocol"`
+	Headers        map[string]string `toml:"headers"`
+	TimeoutSeconds int               `toml:"timeout_seconds"`
+	TLS            ForwardTLSConfig  `toml:"tls"`
+}
+
+// ForwardTLSConfig configures TLS for an https forward endpoint. CertFile
+// and KeyFile together enable a client certificate (mTLS).
+type ForwardTLSConfig struct {
+	CAFile             string `toml:"ca_file"`
+	CertFile           string `toml:"cert_file"`
+	KeyFile            string `toml:"key_file"`
+	InsecureSkipVerify bool   `toml:"insecure_skip_verify"`
+}
+
+// AdmissionConfig limits which sessions' telemetry is stored. Matchers have
+// the form "org:<id>", "user:<account uuid>" or "cwd:<path>".
+type AdmissionConfig struct {
+	Allow []string `toml:"allow"`
+	Deny  []string `toml:"deny"`
+}
+
+// RedactionConfig masks sensitive substrings of telemetry attribute values
+// before they are stored or shown.
+type RedactionConfig struct {
+	Enabled bool `toml:"enabled"`
+	// APIKeys and Emails turn on the built-in patterns for API keys and
+	// bearer tokens, and for email addresses.
+	APIKeys bool `toml:"api_keys"`
+	Emails  bool `toml:"emails"`
+	// SensitiveDirs redacts paths in or under these directories, e.g. "~/.ssh".
+	SensitiveDirs []string `toml:"sensitive_dirs"`
+	// Patterns are extra regular expressions (RE2 syntax) to redact.
+	Patterns    []string `toml:"patterns"`
+	Replacement string   `toml:"replacement"`
 }
 
 type ScannerConfig struct {
-	IntervalSeconds int `toml:"interval_seconds"`
+	IntervalSeconds int  `toml:"interval_seconds"`
+	GitCommits      bool `toml:"git_commits"`
 }
 
 type AlertsConfig struct {
@@ -43,32 +134,375 @@ type AlertsConfig struct {
 	ContextPressurePercent       int                `toml:"context_pressure_percent"`
 	HighRejectionPercent         int                `toml:"high_rejection_percent"`
 	HighRejectionWindowMinutes   int                `toml:"high_rejection_window_minutes"`
+	CostSurgeAuto                bool               `toml:"cost_surge_auto"`
+	RunawayTokenVelocityAuto     bool               `toml:"runaway_token_velocity_auto"`
+	AutoThresholdPercentile      float64            `toml:"auto_threshold_percentile"`
+	SLAOverrunFactor             float64            `toml:"sla_overrun_factor"`
+	SnoozeMinutes                int                `toml:"snooze_minutes"`
 	Notifications                NotificationConfig `toml:"notifications"`
+
+	// CacheInvalidation fires after this many prompt cache rewrites in a
+	// session within the window.
+	CacheInvalidationCount         int `toml:"cache_invalidation_count"`
+	CacheInvalidationWindowMinutes int `toml:"cache_invalidation_window_minutes"`
+
+	// AnomalousSpendStdDevs is how many standard deviations above the rolling
+	// 7-day baseline of hourly spend the burn rate must be to alert.
+	AnomalousSpendStdDevs float64 `toml:"anomalous_spend_stddevs"`
+
+	// ToolFailures fires when one tool fails more than this many times in a
+	// session within the window.
+	ToolFailureCount         int `toml:"tool_failure_count"`
+	ToolFailureWindowMinutes int `toml:"tool_failure_window_minutes"`
+
+	// RequestBudget fires when a session makes more than this many API
+	// requests within an hour, whatever they cost. 0 disables it.
+	APIRequestBudgetPerHour int `toml:"api_request_budget_per_hour"`
+
+	// Suppressions maps a rule name (or "*" for every rule) to matchers of the
+	// form "tag:<tag>", "project:<path>", "env:<environment>" or "host:<name>".
+	// Matching session alerts are dropped.
+	Suppressions map[string][]string `toml:"suppressions"`
+	// Custom holds user-defined rules from [[alerts.custom]] tables.
+	Custom []CustomRuleConfig `toml:"custom"`
+	// Composite holds rules combining other rules from [[alerts.composite]].
+	Composite []CompositeRuleConfig `toml:"composite"`
+	// Escalation maps a rule name to what happens when one of its alerts
+	// stays active, from [alerts.escalation.<rule>] tables.
+	Escalation map[string]EscalationPolicy `toml:"escalation"`
+	// QuietHours holds back notifications on a schedule.
+	QuietHours QuietHoursConfig `toml:"quiet_hours"`
+}
+
+// QuietHoursConfig is a do-not-disturb schedule: alerts still fire, show in
+// the TUI and are recorded to history, but no notification is sent.
+type QuietHoursConfig struct {
+	// Window is a daily local time range "HH:MM-HH:MM", which may wrap past
+	// midnight, e.g. "22:00-08:00". Empty disables it.
+	Window string `toml:"window"`
+	// Weekends makes all of Saturday and Sunday quiet.
+	Weekends bool `toml:"weekends"`
+}
+
+// DailyWindow is a daily local time range, such as quiet hours or the
+// storage maintenance window. Start and End are offsets from midnight; a
+// window whose End is before its Start wraps past midnight.
+type DailyWindow struct {
+	Start, End time.Duration
+}
+
+// Contains reports whether t, in its own location, falls in the window. A
+// window that wraps past midnight contains the late evening and the early
+// morning of every day.
+func (w DailyWindow) Contains(t time.Time) bool {
+	y, mo, d := t.Date()
+	offset := t.Sub(time.Date(y, mo, d, 0, 0, 0, 0, t.Location()))
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// ParseDailyWindow parses a daily window "HH:MM-HH:MM", such as quiet hours
+// or the storage maintenance window.
+func ParseDailyWindow(s string) (DailyWindow, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return DailyWindow{}, fmt.Errorf("want HH:MM-HH:MM, got %q", s)
+	}
+	parse := func(hm string) (time.Duration, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(hm))
+		if err != nil {
+			return 0, fmt.Errorf("want HH:MM-HH:MM, got %q", s)
+		}
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+	var w DailyWindow
+	var err error
+	if w.Start, err = parse(from); err != nil {
+		return DailyWindow{}, err
+	}
+	if w.End, err = parse(to); err != nil {
+		return DailyWindow{}, err
+	}
+	if w.Start == w.End {
+		return DailyWindow{}, fmt.Errorf("start and end of %q are the same", s)
+	}
+	return w, nil
+}
+
+// EscalationPolicy escalates an alert that is still being raised
+// AfterMinutes after it first fired, once: it is notified again, through
+// Channels instead of its route when set, and with Severity when set.
+type EscalationPolicy struct {
+	AfterMinutes int      `toml:"after_minutes"`
+	Channels     []string `toml:"channels"`
+	Severity     string   `toml:"severity"`
+}
+
+// CustomRuleConfig defines an alert rule over metrics or events. Exactly one
+// of Metric and Event selects the data; Where filters it by attribute.
+type CustomRuleConfig struct {
+	Name          string            `toml:"name"`
+	Metric        string            `toml:"metric"`
+	Event         string            `toml:"event"`
+	Where         map[string]string `toml:"where"`
+	Aggregation   string            `toml:"aggregation"` // count, sum, avg, min, max, increase
+	Field         string            `toml:"field"`       // numeric event attribute for sum/avg/min/max
+	WindowMinutes int               `toml:"window_minutes"`
+	Op            string            `toml:"op"`
+	Threshold     float64           `toml:"threshold"`
+	Severity      string            `toml:"severity"`
+	Scope         string            `toml:"scope"` // session (default) or global
+	Message       string            `toml:"message"`
+}
+
+// CompositeRuleConfig fires when the conditions of other built-in or custom
+// rules hold in the same evaluation for the same session. A global alert
+// (such as CostSurge) holds for every session.
+type CompositeRuleConfig struct {
+	Name     string   `toml:"name"`
+	All      []string `toml:"all"` // every rule must hold (AND)
+	Any      []string `toml:"any"` // at least one rule must hold (OR)
+	Severity string   `toml:"severity"`
+	Message  string   `toml:"message"`
+	// SuppressMembers stops the member rules from alerting on their own;
+	// they are then only evaluated as conditions of the composite.
+	SuppressMembers bool `toml:"suppress_members"`
+}
+
+// BuiltinRuleNames lists the rule names of the built-in alert rules, which
+// custom rules may not reuse.
+var BuiltinRuleNames = []string{
+	"CostSurge", "RunawayTokens", "LoopDetector", "ErrorStorm", "StaleSession",
+	"ContextPressure", "HighRejection", "SessionCost", "SLAOverrun",
+	"BudgetThreshold", "CacheInvalidation", "AnomalousSpend", "RecordBroken",
+	"ToolFailures", "RequestBudget",
 }
 
 type NotificationConfig struct {
 	SystemNotify bool `toml:"system_notify"`
+	// Backend picks the system notifier: "auto", "osascript", "dbus" or
+	// "bell".
+	Backend string `toml:"backend"`
+	// Channels are named notification targets besides the built-in
+	// "system" channel, referenced by Routes.
+	Channels map[string]ChannelConfig `toml:"channels"`
+	// Routes pick the channels for an alert; the first route whose matchers
+	// match wins. Alerts matching no route go to the "system" channel.
+	Routes []NotificationRoute `toml:"routes"`
+	// LogTarget also writes every alert to "syslog" or "journald"; empty
+	// disables it.
+	LogTarget string `toml:"log_target"`
+}
+
+// ChannelConfig is a notification target: "slack" posts to a Slack
+// incoming webhook, "webhook" posts the alert as JSON.
+type ChannelConfig struct {
+	Type string `toml:"type"`
+	URL  string `toml:"url"`
+}
+
+// NotificationRoute sends alerts matching any of Match (all alerts when
+// empty) to Channels. Matchers have the form "tag:<name>", "project:<path>",
+// "env:<environment>", "host:<name>", "rule:<name>" or "severity:<level>".
+type NotificationRoute struct {
+	Match    []string `toml:"match"`
+	Channels []string `toml:"channels"`
 }
 
+// SystemChannel is the channel name of system notifications.
+const SystemChannel = "system"
+
 type DisplayConfig struct {
 	EventBufferSize      int     `toml:"event_buffer_size"`
 	RefreshRateMS        int     `toml:"refresh_rate_ms"`
 	CostColorGreenBelow  float64 `toml:"cost_color_green_below"`
 	CostColorYellowBelow float64 `toml:"cost_color_yellow_below"`
+	LatencyAverage       string  `toml:"latency_average"`
+	LatencyTrimPercent   float64 `toml:"latency_trim_percent"`
+	SlowRenderMS         int     `toml:"slow_render_ms"`
+	TimeFormat           string  `toml:"time_format"`
+	// EventBufferEviction is what happens to events the buffer evicts:
+	// "drop", or "spill" to store them so the Events panel can scroll back
+	// past the buffer. Spilling needs persistence.
+	EventBufferEviction string `toml:"event_buffer_eviction"`
+	// RememberState restores the last view, filters and selected session
+	// from the UI state file on startup.
+	RememberState bool `toml:"remember_state"`
+	// StartView is the view shown on launch: "startup" (the process list),
+	// "dashboard", or "auto" to leave the process list once a session
+	// sends telemetry.
+	StartView string `toml:"start_view"`
+	// MinClaudeCodeVersion is the oldest Claude Code release considered
+	// current, e.g. "2.0.14"; sessions reporting an older service.version are
+	// flagged in the Stats view. Empty disables the check.
+	MinClaudeCodeVersion string `toml:"min_claude_code_version"`
+	// TerminalTitle shows global spend and hourly rate in the terminal
+	// window title.
+	TerminalTitle bool `toml:"terminal_title"`
+	// TaskbarProgress reports budget utilization as OSC 9;4 progress, shown
+	// in the taskbar or tab by terminals that support it.
+	TaskbarProgress bool `toml:"taskbar_progress"`
+	// Theme is the [display.theme] section.
+	Theme ThemeConfig `toml:"theme"`
+	// Badges pins KPIs to the dashboard header, mapping each KPI name to
+	// its target.
+	Badges map[string]float64 `toml:"badges"`
+}
+
+// BadgeKPIs lists the KPIs [display.badges] may pin, in header order.
+// cost_today and burn_rate are in USD, error_rate and cache_efficiency in
+// percent.
+var BadgeKPIs = []string{"cost_today", "burn_rate", "error_rate", "cache_efficiency"}
+
+// maxBadges is how many KPIs fit in the dashboard header.
+const maxBadges = 3
+
+// ThemeConfig selects the TUI color scheme. Colors overrides individual
+// roles of the named theme with "#rrggbb" hex or 0-255 ANSI color values.
+type ThemeConfig struct {
+	Name   string            `toml:"name"`
+	Colors map[string]string `toml:"colors"`
+}
+
+// ThemeNames lists the built-in TUI themes.
+var ThemeNames = []string{"dark", "light", "solarized", "high-contrast"}
+
+// ThemeColorRoles lists the color roles a theme defines, which
+// [display.theme.colors] may override.
+var ThemeColorRoles = []string{
+	"accent", "header_fg", "header_bg", "border", "focus", "dim", "muted",
+	"good", "warning", "critical", "prompt", "tool", "api", "decision",
+	"annotation", "alert_badge",
+}
+
+// BudgetConfig caps spending per calendar week (starting Monday) and month,
+// in local time. A zero budget is disabled.
+type BudgetConfig struct {
+	WeeklyUSD  float64 `toml:"weekly_usd"`
+	MonthlyUSD float64 `toml:"monthly_usd"`
+	// AlertPercentages are the shares of a budget, in percent, at which
+	// the BudgetThreshold alert fires.
+	AlertPercentages []float64 `toml:"alert_percentages"`
+}
+
+// PlanConfig describes the quotas of a Claude subscription plan (Pro, Max,
+// Team), so usage is shown as plan utilization rather than pay-as-you-go
+// dollars. Quotas count every token Claude Code reports, cache reads
+// included; a zero quota is disabled.
+type PlanConfig struct {
+	Name string `toml:"name"`
+	// WindowTokens is the quota of each rolling usage window, which starts
+	// at the first request after the previous one ended and lasts
+	// WindowHours.
+	WindowTokens int64 `toml:"window_tokens"`
+	WindowHours  int   `toml:"window_hours"`
+	// WeeklyTokens is the quota of each calendar week, starting Monday.
+	WeeklyTokens int64 `toml:"weekly_tokens"`
+}
+
+// Enabled reports whether any plan quota is configured.
+func (p PlanConfig) Enabled() bool {
+	return p.WindowTokens > 0 || p.WeeklyTokens > 0
+}
+
+// ProjectsConfig splits repositories into subprojects, so cost in a
+// monorepo can be attributed to its packages. Workspaces are globs relative
+// to the repository root, like "packages/*"; a session in or below a
+// matching directory belongs to that directory. Otherwise SubprojectDepth
+// path components below the root name the subproject; 0 groups by
+// repository.
+type ProjectsConfig struct {
+	SubprojectDepth int      `toml:"subproject_depth"`
+	Workspaces      []string `toml:"workspaces"`
+}
+
+// PricingSyncConfig configures fetching model prices from a published
+// manifest. An empty URL disables it.
+type PricingSyncConfig struct {
+	URL string `toml:"url"`
+	// RefreshHours is how old the cached manifest may get before it is
+	// fetched again.
+	RefreshHours int `toml:"refresh_hours"`
+	// CachePath keeps the last manifest fetched, for offline starts.
+	CachePath string `toml:"cache_path"`
+}
+
+// ServeConfig configures the aggregation API of `cc-top serve`, which
+// accepts session summaries pushed by other cc-top instances.
+type ServeConfig struct {
+	// Listen is the host:port the API listens on.
+	Listen string `toml:"listen"`
+	// Token is the shared secret pushing instances must send as a bearer
+	// token. serve refuses to start without one.
+	Token string `toml:"token"`
+}
+
+// PushConfig configures pushing session summaries to a `cc-top serve`
+// instance. An empty URL disables it.
+type PushConfig struct {
+	URL             string `toml:"url"`
+	Token           string `toml:"token"`
+	IntervalSeconds int    `toml:"interval_seconds"`
+	// Host and User label this instance's sessions in the team view; they
+	// default to the hostname and the login name.
+	Host string `toml:"host"`
+	User string `toml:"user"`
 }
 
 type StorageConfig struct {
-	DBPath               string `toml:"db_path"`
-	RetentionDays        int    `toml:"retention_days"`
-	SummaryRetentionDays int    `toml:"summary_retention_days"`
+	DBPath string `toml:"db_path"`
+	// RetentionDays keeps raw metrics, events and burn rate snapshots; older
+	// raw data is downsampled into daily summaries. The legacy key
+	// retention_days is still accepted.
+	RetentionDays int `toml:"retention_days_raw"`
+	// SummaryRetentionDays keeps daily summaries, daily stats and alert
+	// history. The legacy key summary_retention_days is still accepted.
+	SummaryRetentionDays int `toml:"retention_days_daily"`
+	// MaxDBSizeMB caps the database size; 0 disables the cap.
+	MaxDBSizeMB int `toml:"max_db_size_mb"`
+	// MaintenanceWindow limits pruning, the size cap and VACUUM to a daily
+	// local time range "HH:MM-HH:MM", e.g. "03:00-04:00". Empty allows any
+	// time.
+	MaintenanceWindow string `toml:"maintenance_window"`
+	// MaintenanceBusyWritesPerMinute defers that maintenance while more
+	// writes than this per minute arrive, i.e. while an agent is running.
+	// 0 never defers.
+	MaintenanceBusyWritesPerMinute int `toml:"maintenance_busy_writes_per_minute"`
+	// ReadOnly opens the database as a follower of another cc-top instance
+	// that writes it: history is read from it, but nothing is written.
+	ReadOnly bool `toml:"read_only"`
+
+	// SQLite and write batching tuning. JournalMode and Synchronous are
+	// the SQLite pragmas; writes are queued in a ChannelSize buffer (and
+	// dropped when it is full) and committed BatchSize at a time, or every
+	// FlushIntervalMs.
+	JournalMode     string `toml:"journal_mode"`
+	Synchronous     string `toml:"synchronous"`
+	BusyTimeoutMs   int    `toml:"busy_timeout_ms"`
+	BatchSize       int    `toml:"batch_size"`
+	FlushIntervalMs int    `toml:"flush_interval_ms"`
+	ChannelSize     int    `toml:"channel_size"`
 }
 
+// JournalModes and SynchronousModes are the accepted storage journal_mode
+// and synchronous values.
+var (
+	JournalModes     = []string{"wal", "delete", "truncate", "persist"}
+	SynchronousModes = []string{"off", "normal", "full", "extra"}
+)
+
 type LoadResult struct {
 	Config   Config
 	Warnings []string
+	// Files lists the config file and every file it includes, found or
+	// not, so a Watcher can reload when any of them appears or changes.
+	Files []string
 }
 
-func defaultConfigPath() string {
+// DefaultPath returns ~/.config/cc-top/config.toml, the file Load reads.
+func DefaultPath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return ""
@@ -77,9 +511,12 @@ func defaultConfigPath() string {
 }
 
 func Load() (*LoadResult, error) {
-	return LoadFrom(defaultConfigPath())
+	return LoadFrom(DefaultPath())
 }
 
+// LoadFrom loads the config file at path on top of the defaults. Files listed
+// in its include array are merged before it, in order, so the including file
+// always wins. A missing config file is not an error.
 func LoadFrom(path string) (*LoadResult, error) {
 	cfg := DefaultConfig()
 	result := &LoadResult{Config: cfg}
@@ -92,38 +529,122 @@ func LoadFrom(path string) (*LoadResult, error) {
 		return nil, fmt.Errorf("reading config file: %w", err)
 	}
 
-	var raw map[string]any
-	if _, err := toml.Decode(string(data), &raw); err != nil {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	result.Files = []string{abs}
+	if err := mergeLayer(result, string(data), filepath.Dir(abs), []string{abs}); err != nil {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
 
-	knownTopLevel := map[string]bool{
-		"receiver": true,
-		"scanner":  true,
-		"alerts":   true,
-		"display":  true,
-		"storage":  true,
-		"models":   true,
+	if err := validate(&result.Config); err != nil {
+		return nil, err
 	}
+
+	return result, nil
+}
+
+// knownTopLevel lists the top-level keys accepted in a config file.
+var knownTopLevel = map[string]bool{
+	"include":  true,
+	"receiver": true,
+	"scanner":  true,
+	"alerts":   true,
+	"display":  true,
+	"storage":  true,
+	"budget":   true,
+	"models":   true,
+}
+
+// mergeLayer decodes one TOML document and merges it into result. Files
+// named in its include array are merged first so that keys set in data
+// override them. Relative includes resolve against baseDir; stack holds the
+// files currently being loaded and is used to detect include cycles.
+func mergeLayer(result *LoadResult, data, baseDir string, stack []string) error {
+	cfg, warnings := &result.Config, &result.Warnings
+	var raw map[string]any
+	if _, err := toml.Decode(data, &raw); err != nil {
+		return err
+	}
+
 	for key := range raw {
 		if !knownTopLevel[key] {
-			result.Warnings = append(result.Warnings, fmt.Sprintf("unknown config key: %q", key))
+			*warnings = append(*warnings, fmt.Sprintf("unknown config key: %q", key))
+		}
+	}
+
+	includes, err := includePaths(raw)
+	if err != nil {
+		return err
+	}
+	for _, inc := range includes {
+		path := resolveIncludePath(inc, baseDir)
+		for _, p := range stack {
+			if p == path {
+				return fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), path)
+			}
+		}
+
+		result.Files = append(result.Files, path)
+		incData, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				*warnings = append(*warnings, fmt.Sprintf("included config file not found: %q", path))
+				continue
+			}
+			return fmt.Errorf("include %q: %w", path, err)
+		}
+
+		next := append(stack[:len(stack):len(stack)], path)
+		if err := mergeLayer(result, string(incData), filepath.Dir(path), next); err != nil {
+			return fmt.Errorf("include %q: %w", path, err)
 		}
 	}
 
 	var tf tomlFile
-	if _, err := toml.Decode(string(data), &tf); err != nil {
-		return nil, fmt.Errorf("parsing config file: %w", err)
+	if _, err := toml.Decode(data, &tf); err != nil {
+		return err
 	}
 
-	mergeFromRaw(&result.Config, &tf, raw)
-	mergeModelsFromRaw(&result.Config, raw)
+	mergeFromRaw(cfg, &tf, raw)
+	mergeModelsFromRaw(cfg, raw)
+	return nil
+}
 
-	if err := validate(&result.Config); err != nil {
-		return nil, err
+// includePaths returns the entries of the top-level include array.
+func includePaths(raw map[string]any) ([]string, error) {
+	v, ok := raw["include"]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("include must be an array of file paths")
 	}
+	paths := make([]string, 0, len(list))
+	for _, item := range list {
+		p, ok := item.(string)
+		if !ok || p == "" {
+			return nil, fmt.Errorf("include entries must be non-empty strings, got %v", item)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
 
-	return result, nil
+// resolveIncludePath expands a leading "~/" and makes relative paths
+// relative to baseDir.
+func resolveIncludePath(path, baseDir string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return filepath.Clean(path)
 }
 
 type tomlFile struct {
@@ -132,15 +653,22 @@ type tomlFile struct {
 	Alerts   *AlertsConfig   `toml:"alerts"`
 	Display  *DisplayConfig  `toml:"display"`
 	Storage  *StorageConfig  `toml:"storage"`
+	Budget   *BudgetConfig   `toml:"budget"`
+	Plan     *PlanConfig     `toml:"plan"`
+	Projects *ProjectsConfig `toml:"projects"`
 	Models   *tomlModels     `toml:"models"`
+
+	PricingSync *PricingSyncConfig `toml:"pricing_sync"`
+	Serve       *ServeConfig       `toml:"serve"`
+	Push        *PushConfig        `toml:"push"`
 }
 
 type tomlModels struct {
-	Pricing map[string]tomlPricing `toml:"pricing"`
+	// Values are price arrays or, for service tiers, tables of them; both
+	// are parsed from the raw document by mergeModelsFromRaw.
+	Pricing map[string]any `toml:"pricing"`
 }
 
-type tomlPricing [4]float64
-
 func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 	if tf.Receiver != nil {
 		if section, ok := rawSection(raw, "receiver"); ok {
@@ -153,6 +681,32 @@ func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 			if _, exists := section["bind"]; exists {
 				cfg.Receiver.Bind = tf.Receiver.Bind
 			}
+			if _, exists := section["metrics_port"]; exists {
+				cfg.Receiver.MetricsPort = tf.Receiver.MetricsPort
+			}
+			if _, exists := section["auth_token"]; exists {
+				cfg.Receiver.AuthToken = tf.Receiver.AuthToken
+			}
+			if tls, ok := rawSection(section, "tls"); ok {
+				if _, exists := tls["cert_file"]; exists {
+					cfg.Receiver.TLS.CertFile = tf.Receiver.TLS.CertFile
+				}
+				if _, exists := tls["key_file"]; exists {
+					cfg.Receiver.TLS.KeyFile = tf.Receiver.TLS.KeyFile
+				}
+				if _, exists := tls["client_ca_file"]; exists {
+					cfg.Receiver.TLS.ClientCAFile = tf.Receiver.TLS.ClientCAFile
+				}
+			}
+			if _, exists := section["admission"]; exists {
+				cfg.Receiver.Admission = tf.Receiver.Admission
+			}
+			if fwd, ok := rawSection(section, "forward"); ok {
+				mergeForward(&cfg.Receiver.Forward, &tf.Receiver.Forward, fwd)
+			}
+			if red, ok := rawSection(section, "redaction"); ok {
+				mergeRedaction(&cfg.Receiver.Redaction, &tf.Receiver.Redaction, red)
+			}
 		}
 	}
 	if tf.Scanner != nil {
@@ -160,6 +714,9 @@ func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 			if _, exists := section["interval_seconds"]; exists {
 				cfg.Scanner.IntervalSeconds = tf.Scanner.IntervalSeconds
 			}
+			if _, exists := section["git_commits"]; exists {
+				cfg.Scanner.GitCommits = tf.Scanner.GitCommits
+			}
 		}
 	}
 	if tf.Alerts != nil {
@@ -197,8 +754,75 @@ func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 			if _, exists := section["high_rejection_window_minutes"]; exists {
 				cfg.Alerts.HighRejectionWindowMinutes = tf.Alerts.HighRejectionWindowMinutes
 			}
-			if _, exists := section["notifications"]; exists {
-				cfg.Alerts.Notifications = tf.Alerts.Notifications
+			if _, exists := section["cache_invalidation_count"]; exists {
+				cfg.Alerts.CacheInvalidationCount = tf.Alerts.CacheInvalidationCount
+			}
+			if _, exists := section["cache_invalidation_window_minutes"]; exists {
+				cfg.Alerts.CacheInvalidationWindowMinutes = tf.Alerts.CacheInvalidationWindowMinutes
+			}
+			if _, exists := section["tool_failure_count"]; exists {
+				cfg.Alerts.ToolFailureCount = tf.Alerts.ToolFailureCount
+			}
+			if _, exists := section["tool_failure_window_minutes"]; exists {
+				cfg.Alerts.ToolFailureWindowMinutes = tf.Alerts.ToolFailureWindowMinutes
+			}
+			if _, exists := section["api_request_budget_per_hour"]; exists {
+				cfg.Alerts.APIRequestBudgetPerHour = tf.Alerts.APIRequestBudgetPerHour
+			}
+			if _, exists := section["anomalous_spend_stddevs"]; exists {
+				cfg.Alerts.AnomalousSpendStdDevs = tf.Alerts.AnomalousSpendStdDevs
+			}
+			if _, exists := section["cost_surge_auto"]; exists {
+				cfg.Alerts.CostSurgeAuto = tf.Alerts.CostSurgeAuto
+			}
+			if _, exists := section["runaway_token_velocity_auto"]; exists {
+				cfg.Alerts.RunawayTokenVelocityAuto = tf.Alerts.RunawayTokenVelocityAuto
+			}
+			if _, exists := section["auto_threshold_percentile"]; exists {
+				cfg.Alerts.AutoThresholdPercentile = tf.Alerts.AutoThresholdPercentile
+			}
+			if _, exists := section["sla_overrun_factor"]; exists {
+				cfg.Alerts.SLAOverrunFactor = tf.Alerts.SLAOverrunFactor
+			}
+			if _, exists := section["snooze_minutes"]; exists {
+				cfg.Alerts.SnoozeMinutes = tf.Alerts.SnoozeMinutes
+			}
+			if notif, ok := rawSection(section, "notifications"); ok {
+				if _, exists := notif["system_notify"]; exists {
+					cfg.Alerts.Notifications.SystemNotify = tf.Alerts.Notifications.SystemNotify
+				}
+				if _, exists := notif["backend"]; exists {
+					cfg.Alerts.Notifications.Backend = tf.Alerts.Notifications.Backend
+				}
+				if _, exists := notif["channels"]; exists {
+					cfg.Alerts.Notifications.Channels = tf.Alerts.Notifications.Channels
+				}
+				if _, exists := notif["routes"]; exists {
+					cfg.Alerts.Notifications.Routes = tf.Alerts.Notifications.Routes
+				}
+				if _, exists := notif["log_target"]; exists {
+					cfg.Alerts.Notifications.LogTarget = tf.Alerts.Notifications.LogTarget
+				}
+			}
+			if _, exists := section["suppressions"]; exists {
+				cfg.Alerts.Suppressions = tf.Alerts.Suppressions
+			}
+			if _, exists := section["custom"]; exists {
+				cfg.Alerts.Custom = tf.Alerts.Custom
+			}
+			if _, exists := section["composite"]; exists {
+				cfg.Alerts.Composite = tf.Alerts.Composite
+			}
+			if _, exists := section["escalation"]; exists {
+				cfg.Alerts.Escalation = tf.Alerts.Escalation
+			}
+			if quiet, ok := rawSection(section, "quiet_hours"); ok {
+				if _, exists := quiet["window"]; exists {
+					cfg.Alerts.QuietHours.Window = tf.Alerts.QuietHours.Window
+				}
+				if _, exists := quiet["weekends"]; exists {
+					cfg.Alerts.QuietHours.Weekends = tf.Alerts.QuietHours.Weekends
+				}
 			}
 		}
 	}
@@ -207,6 +831,9 @@ func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 			if _, exists := section["event_buffer_size"]; exists {
 				cfg.Display.EventBufferSize = tf.Display.EventBufferSize
 			}
+			if _, exists := section["event_buffer_eviction"]; exists {
+				cfg.Display.EventBufferEviction = tf.Display.EventBufferEviction
+			}
 			if _, exists := section["refresh_rate_ms"]; exists {
 				cfg.Display.RefreshRateMS = tf.Display.RefreshRateMS
 			}
@@ -216,6 +843,44 @@ func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 			if _, exists := section["cost_color_yellow_below"]; exists {
 				cfg.Display.CostColorYellowBelow = tf.Display.CostColorYellowBelow
 			}
+			if _, exists := section["latency_average"]; exists {
+				cfg.Display.LatencyAverage = tf.Display.LatencyAverage
+			}
+			if _, exists := section["latency_trim_percent"]; exists {
+				cfg.Display.LatencyTrimPercent = tf.Display.LatencyTrimPercent
+			}
+			if _, exists := section["slow_render_ms"]; exists {
+				cfg.Display.SlowRenderMS = tf.Display.SlowRenderMS
+			}
+			if _, exists := section["time_format"]; exists {
+				cfg.Display.TimeFormat = tf.Display.TimeFormat
+			}
+			if _, exists := section["remember_state"]; exists {
+				cfg.Display.RememberState = tf.Display.RememberState
+			}
+			if _, exists := section["start_view"]; exists {
+				cfg.Display.StartView = tf.Display.StartView
+			}
+			if _, exists := section["min_claude_code_version"]; exists {
+				cfg.Display.MinClaudeCodeVersion = tf.Display.MinClaudeCodeVersion
+			}
+			if _, exists := section["terminal_title"]; exists {
+				cfg.Display.TerminalTitle = tf.Display.TerminalTitle
+			}
+			if _, exists := section["taskbar_progress"]; exists {
+				cfg.Display.TaskbarProgress = tf.Display.TaskbarProgress
+			}
+			if theme, ok := rawSection(section, "theme"); ok {
+				if _, exists := theme["name"]; exists {
+					cfg.Display.Theme.Name = tf.Display.Theme.Name
+				}
+				if _, exists := theme["colors"]; exists {
+					cfg.Display.Theme.Colors = tf.Display.Theme.Colors
+				}
+			}
+			if _, exists := section["badges"]; exists {
+				cfg.Display.Badges = tf.Display.Badges
+			}
 		}
 	}
 	if tf.Storage != nil {
@@ -223,16 +888,185 @@ func mergeFromRaw(cfg *Config, tf *tomlFile, raw map[string]any) {
 			if _, exists := section["db_path"]; exists {
 				cfg.Storage.DBPath = tf.Storage.DBPath
 			}
-			if _, exists := section["retention_days"]; exists {
+			// The legacy names apply first so the new ones win when both
+			// are set.
+			if v, ok := section["retention_days"].(int64); ok {
+				cfg.Storage.RetentionDays = int(v)
+			}
+			if v, ok := section["summary_retention_days"].(int64); ok {
+				cfg.Storage.SummaryRetentionDays = int(v)
+			}
+			if _, exists := section["retention_days_raw"]; exists {
 				cfg.Storage.RetentionDays = tf.Storage.RetentionDays
 			}
-			if _, exists := section["summary_retention_days"]; exists {
+			if _, exists := section["retention_days_daily"]; exists {
 				cfg.Storage.SummaryRetentionDays = tf.Storage.SummaryRetentionDays
 			}
+			if _, exists := section["max_db_size_mb"]; exists {
+				cfg.Storage.MaxDBSizeMB = tf.Storage.MaxDBSizeMB
+			}
+			if _, exists := section["maintenance_window"]; exists {
+				cfg.Storage.MaintenanceWindow = strings.TrimSpace(tf.Storage.MaintenanceWindow)
+			}
+			if _, exists := section["maintenance_busy_writes_per_minute"]; exists {
+				cfg.Storage.MaintenanceBusyWritesPerMinute = tf.Storage.MaintenanceBusyWritesPerMinute
+			}
+			if _, exists := section["read_only"]; exists {
+				cfg.Storage.ReadOnly = tf.Storage.ReadOnly
+			}
+			if _, exists := section["journal_mode"]; exists {
+				cfg.Storage.JournalMode = strings.ToLower(tf.Storage.JournalMode)
+			}
+			if _, exists := section["synchronous"]; exists {
+				cfg.Storage.Synchronous = strings.ToLower(tf.Storage.Synchronous)
+			}
+			if _, exists := section["busy_timeout_ms"]; exists {
+				cfg.Storage.BusyTimeoutMs = tf.Storage.BusyTimeoutMs
+			}
+			if _, exists := section["batch_size"]; exists {
+				cfg.Storage.BatchSize = tf.Storage.BatchSize
+			}
+			if _, exists := section["flush_interval_ms"]; exists {
+				cfg.Storage.FlushIntervalMs = tf.Storage.FlushIntervalMs
+			}
+			if _, exists := section["channel_size"]; exists {
+				cfg.Storage.ChannelSize = tf.Storage.ChannelSize
+			}
+		}
+	}
+	if tf.Budget != nil {
+		if section, ok := rawSection(raw, "budget"); ok {
+			if _, exists := section["weekly_usd"]; exists {
+				cfg.Budget.WeeklyUSD = tf.Budget.WeeklyUSD
+			}
+			if _, exists := section["monthly_usd"]; exists {
+				cfg.Budget.MonthlyUSD = tf.Budget.MonthlyUSD
+			}
+			if _, exists := section["alert_percentages"]; exists {
+				cfg.Budget.AlertPercentages = tf.Budget.AlertPercentages
+			}
+		}
+	}
+	if tf.Plan != nil {
+		if section, ok := rawSection(raw, "plan"); ok {
+			if _, exists := section["name"]; exists {
+				cfg.Plan.Name = tf.Plan.Name
+			}
+			if _, exists := section["window_tokens"]; exists {
+				cfg.Plan.WindowTokens = tf.Plan.WindowTokens
+			}
+			if _, exists := section["window_hours"]; exists {
+				cfg.Plan.WindowHours = tf.Plan.WindowHours
+			}
+			if _, exists := section["weekly_tokens"]; exists {
+				cfg.Plan.WeeklyTokens = tf.Plan.WeeklyTokens
+			}
+		}
+	}
+	if tf.Projects != nil {
+		if section, ok := rawSection(raw, "projects"); ok {
+			if _, exists := section["subproject_depth"]; exists {
+				cfg.Projects.SubprojectDepth = tf.Projects.SubprojectDepth
+			}
+			if _, exists := section["workspaces"]; exists {
+				cfg.Projects.Workspaces = tf.Projects.Workspaces
+			}
+		}
+	}
+	if tf.PricingSync != nil {
+		if section, ok := rawSection(raw, "pricing_sync"); ok {
+			if _, exists := section["url"]; exists {
+				cfg.PricingSync.URL = tf.PricingSync.URL
+			}
+			if _, exists := section["refresh_hours"]; exists {
+				cfg.PricingSync.RefreshHours = tf.PricingSync.RefreshHours
+			}
+			if _, exists := section["cache_path"]; exists {
+				cfg.PricingSync.CachePath = tf.PricingSync.CachePath
+			}
+		}
+	}
+	if tf.Serve != nil {
+		if section, ok := rawSection(raw, "serve"); ok {
+			if _, exists := section["listen"]; exists {
+				cfg.Serve.Listen = tf.Serve.Listen
+			}
+			if _, exists := section["token"]; exists {
+				cfg.Serve.Token = tf.Serve.Token
+			}
+		}
+	}
+	if tf.Push != nil {
+		if section, ok := rawSection(raw, "push"); ok {
+			if _, exists := section["url"]; exists {
+				cfg.Push.URL = tf.Push.URL
+			}
+			if _, exists := section["token"]; exists {
+				cfg.Push.Token = tf.Push.Token
+			}
+			if _, exists := section["interval_seconds"]; exists {
+				cfg.Push.IntervalSeconds = tf.Push.IntervalSeconds
+			}
+			if _, exists := section["host"]; exists {
+				cfg.Push.Host = tf.Push.Host
+			}
+			if _, exists := section["user"]; exists {
+				cfg.Push.User = tf.Push.User
+			}
+		}
+	}
+}
+
+func mergeForward(dst, src *ForwardConfig, section map[string]any) {
+	if _, exists := section["endpoint"]; exists {
+		dst.Endpoint = src.Endpoint
+	}
+	if _, exists := section["protocol"]; exists {
+		dst.Protocol = src.Protocol
+	}
+	if _, exists := section["headers"]; exists {
+		dst.Headers = src.Headers
+	}
+	if _, exists := section["timeout_seconds"]; exists {
+		dst.TimeoutSeconds = src.TimeoutSeconds
+	}
+	if tls, ok := rawSection(section, "tls"); ok {
+		if _, exists := tls["ca_file"]; exists {
+			dst.TLS.CAFile = src.TLS.CAFile
+		}
+		if _, exists := tls["cert_file"]; exists {
+			dst.TLS.CertFile = src.TLS.CertFile
+		}
+		if _, exists := tls["key_file"]; exists {
+			dst.TLS.KeyFile = src.TLS.KeyFile
+		}
+		if _, exists := tls["insecure_skip_verify"]; exists {
+			dst.TLS.InsecureSkipVerify = src.TLS.InsecureSkipVerify
 		}
 	}
 }
 
+func mergeRedaction(dst, src *RedactionConfig, section map[string]any) {
+	if _, exists := section["enabled"]; exists {
+		dst.Enabled = src.Enabled
+	}
+	if _, exists := section["api_keys"]; exists {
+		dst.APIKeys = src.APIKeys
+	}
+	if _, exists := section["emails"]; exists {
+		dst.Emails = src.Emails
+	}
+	if _, exists := section["sensitive_dirs"]; exists {
+		dst.SensitiveDirs = src.SensitiveDirs
+	}
+	if _, exists := section["patterns"]; exists {
+		dst.Patterns = src.Patterns
+	}
+	if _, exists := section["replacement"]; exists {
+		dst.Replacement = src.Replacement
+	}
+}
+
 func rawSection(raw map[string]any, key string) (map[string]any, bool) {
 	v, ok := raw[key]
 	if !ok {
@@ -262,23 +1096,12 @@ func mergeModelsFromRaw(cfg *Config, raw map[string]any) {
 				cfg.Pricing = make(map[string][4]float64)
 			}
 			for model, priceVal := range pricingMap {
-				priceSlice, ok := priceVal.([]any)
-				if !ok || len(priceSlice) != 4 {
+				// A nested table is a service tier: [models.pricing.batch].
+				if tierMap, ok := priceVal.(map[string]any); ok {
+					mergeTierPricing(cfg, model, tierMap)
 					continue
 				}
-				var prices [4]float64
-				valid := true
-				for i, v := range priceSlice {
-					switch n := v.(type) {
-					case float64:
-						prices[i] = n
-					case int64:
-						prices[i] = float64(n)
-					default:
-						valid = false
-					}
-				}
-				if valid {
+				if prices, ok := parsePrices(priceVal); ok {
 					cfg.Pricing[model] = prices
 				}
 			}
@@ -294,6 +1117,50 @@ func mergeModelsFromRaw(cfg *Config, raw map[string]any) {
 	}
 }
 
+// mergeTierPricing merges the per-model prices of one service tier. The
+// "standard" tier is an alias for the top-level pricing table.
+func mergeTierPricing(cfg *Config, tier string, tierMap map[string]any) {
+	dst := cfg.Pricing
+	if tier != StandardTier {
+		if cfg.PricingTiers == nil {
+			cfg.PricingTiers = make(map[string]map[string][4]float64)
+		}
+		if cfg.PricingTiers[tier] == nil {
+			cfg.PricingTiers[tier] = make(map[string][4]float64)
+		}
+		dst = cfg.PricingTiers[tier]
+	}
+	for model, priceVal := range tierMap {
+		if prices, ok := parsePrices(priceVal); ok {
+			dst[model] = prices
+		}
+	}
+}
+
+// parsePrices converts a TOML [input, output, cacheRead, cacheCreation]
+// array into prices, rejecting arrays of the wrong length or type.
+func parsePrices(v any) ([4]float64, bool) {
+	var prices [4]float64
+	priceSlice, ok := v.([]any)
+	if !ok || len(priceSlice) != 4 {
+		return prices, false
+	}
+	for i, v := range priceSlice {
+		switch n := v.(type) {
+		case float64:
+			prices[i] = n
+		case int64:
+			prices[i] = float64(n)
+		default:
+			return prices, false
+		}
+	}
+	return prices, true
+}
+
+// LoadFromString loads config from an in-memory TOML document on top of the
+// defaults. Relative include paths are resolved against the working
+// directory; see LoadFrom for the layering rules.
 func LoadFromString(data string) (*LoadResult, error) {
 	cfg := DefaultConfig()
 	result := &LoadResult{Config: cfg}
@@ -302,33 +1169,14 @@ func LoadFromString(data string) (*LoadResult, error) {
 		return result, nil
 	}
 
-	var raw map[string]any
-	if _, err := toml.Decode(data, &raw); err != nil {
-		return nil, fmt.Errorf("parsing config: %w", err)
-	}
-
-	knownTopLevel := map[string]bool{
-		"receiver": true,
-		"scanner":  true,
-		"alerts":   true,
-		"display":  true,
-		"storage":  true,
-		"models":   true,
-	}
-	for key := range raw {
-		if !knownTopLevel[key] {
-			result.Warnings = append(result.Warnings, fmt.Sprintf("unknown config key: %q", key))
-		}
+	wd, err := os.Getwd()
+	if err != nil {
+		wd = "."
 	}
-
-	var tf tomlFile
-	if _, err := toml.Decode(data, &tf); err != nil {
+	if err := mergeLayer(result, data, wd, nil); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
-	mergeFromRaw(&result.Config, &tf, raw)
-	mergeModelsFromRaw(&result.Config, raw)
-
 	if err := validate(&result.Config); err != nil {
 		return nil, err
 	}
@@ -345,6 +1193,48 @@ func validate(cfg *Config) error {
 	if cfg.Receiver.HTTPPort < 1 || cfg.Receiver.HTTPPort > 65535 {
 		errs = append(errs, fmt.Sprintf("http_port must be 1-65535, got %d", cfg.Receiver.HTTPPort))
 	}
+	if strings.ContainsAny(cfg.Receiver.AuthToken, " \t\r\n") {
+		errs = append(errs, "auth_token must not contain whitespace")
+	}
+	if cfg.Receiver.MetricsPort < 0 || cfg.Receiver.MetricsPort > 65535 {
+		errs = append(errs, fmt.Sprintf("metrics_port must be 0 (disabled) or 1-65535, got %d", cfg.Receiver.MetricsPort))
+	} else if cfg.Receiver.MetricsPort != 0 &&
+		(cfg.Receiver.MetricsPort == cfg.Receiver.GRPCPort || cfg.Receiver.MetricsPort == cfg.Receiver.HTTPPort) {
+		errs = append(errs, fmt.Sprintf("metrics_port %d must differ from grpc_port and http_port", cfg.Receiver.MetricsPort))
+	}
+
+	for _, list := range []struct {
+		name     string
+		matchers []string
+	}{{"allow", cfg.Receiver.Admission.Allow}, {"deny", cfg.Receiver.Admission.Deny}} {
+		for _, m := range list.matchers {
+			kind, value, _ := strings.Cut(m, ":")
+			if (kind != "org" && kind != "user" && kind != "cwd") || strings.TrimSpace(value) == "" {
+				errs = append(errs, fmt.Sprintf("admission.%s: matcher must be org:<id>, user:<uuid> or cwd:<path>, got %q", list.name, m))
+			}
+		}
+	}
+
+	errs = append(errs, validateForward(cfg.Receiver.Forward)...)
+	if t := cfg.Receiver.TLS; (t.CertFile == "") != (t.KeyFile == "") {
+		errs = append(errs, "receiver.tls.cert_file and receiver.tls.key_file must be set together")
+	} else if t.ClientCAFile != "" && t.CertFile == "" {
+		errs = append(errs, "receiver.tls.client_ca_file needs cert_file and key_file")
+	}
+
+	for _, p := range cfg.Receiver.Redaction.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Sprintf("redaction.patterns: invalid pattern %q: %v", p, err))
+		}
+	}
+	for _, d := range cfg.Receiver.Redaction.SensitiveDirs {
+		if strings.TrimSpace(d) == "" {
+			errs = append(errs, "redaction.sensitive_dirs: directory must not be empty")
+		}
+	}
+	if cfg.Receiver.Redaction.Replacement == "" {
+		errs = append(errs, "redaction.replacement must not be empty")
+	}
 
 	if cfg.Scanner.IntervalSeconds < 1 {
 		errs = append(errs, fmt.Sprintf("scanner interval_seconds must be positive, got %d", cfg.Scanner.IntervalSeconds))
@@ -382,6 +1272,56 @@ func validate(cfg *Config) error {
 	if cfg.Alerts.HighRejectionWindowMinutes < 1 {
 		errs = append(errs, fmt.Sprintf("high_rejection_window_minutes must be positive, got %d", cfg.Alerts.HighRejectionWindowMinutes))
 	}
+	if cfg.Alerts.CacheInvalidationCount < 1 {
+		errs = append(errs, fmt.Sprintf("cache_invalidation_count must be positive, got %d", cfg.Alerts.CacheInvalidationCount))
+	}
+	if cfg.Alerts.CacheInvalidationWindowMinutes < 1 {
+		errs = append(errs, fmt.Sprintf("cache_invalidation_window_minutes must be positive, got %d", cfg.Alerts.CacheInvalidationWindowMinutes))
+	}
+	if cfg.Alerts.ToolFailureCount < 1 {
+		errs = append(errs, fmt.Sprintf("tool_failure_count must be positive, got %d", cfg.Alerts.ToolFailureCount))
+	}
+	if cfg.Alerts.ToolFailureWindowMinutes < 1 {
+		errs = append(errs, fmt.Sprintf("tool_failure_window_minutes must be positive, got %d", cfg.Alerts.ToolFailureWindowMinutes))
+	}
+	if cfg.Alerts.APIRequestBudgetPerHour < 0 {
+		errs = append(errs, fmt.Sprintf("api_request_budget_per_hour must not be negative, got %d", cfg.Alerts.APIRequestBudgetPerHour))
+	}
+	if cfg.Alerts.AnomalousSpendStdDevs <= 0 {
+		errs = append(errs, fmt.Sprintf("anomalous_spend_stddevs must be positive, got %g", cfg.Alerts.AnomalousSpendStdDevs))
+	}
+	if cfg.Alerts.SLAOverrunFactor < 1 {
+		errs = append(errs, fmt.Sprintf("sla_overrun_factor must be at least 1, got %g", cfg.Alerts.SLAOverrunFactor))
+	}
+	if cfg.Alerts.SnoozeMinutes < 1 {
+		errs = append(errs, fmt.Sprintf("snooze_minutes must be positive, got %d", cfg.Alerts.SnoozeMinutes))
+	}
+	if cfg.Alerts.AutoThresholdPercentile < 50 || cfg.Alerts.AutoThresholdPercentile > 100 {
+		errs = append(errs, fmt.Sprintf("auto_threshold_percentile must be 50-100, got %f", cfg.Alerts.AutoThresholdPercentile))
+	}
+	rules := make([]string, 0, len(cfg.Alerts.Suppressions))
+	for rule := range cfg.Alerts.Suppressions {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		for _, m := range cfg.Alerts.Suppressions[rule] {
+			kind, value, _ := strings.Cut(m, ":")
+			if !isSessionMatcher(kind) || strings.TrimSpace(value) == "" {
+				errs = append(errs, fmt.Sprintf("suppressions.%s: matcher must be tag:<name>, project:<path>, env:<environment> or host:<name>, got %q", rule, m))
+			}
+		}
+	}
+
+	errs = append(errs, validateCustomRules(cfg.Alerts.Custom)...)
+	errs = append(errs, validateCompositeRules(cfg.Alerts.Composite, cfg.Alerts.Custom)...)
+	errs = append(errs, validateNotifications(cfg.Alerts.Notifications)...)
+	errs = append(errs, validateEscalation(cfg.Alerts)...)
+	if w := cfg.Alerts.QuietHours.Window; w != "" {
+		if _, err := ParseDailyWindow(w); err != nil {
+			errs = append(errs, fmt.Sprintf("quiet_hours.window: %v", err))
+		}
+	}
 
 	if cfg.Display.EventBufferSize < 1 {
 		errs = append(errs, fmt.Sprintf("event_buffer_size must be positive, got %d", cfg.Display.EventBufferSize))
@@ -396,6 +1336,37 @@ func validate(cfg *Config) error {
 	if cfg.Display.CostColorYellowBelow <= 0 {
 		errs = append(errs, fmt.Sprintf("cost_color_yellow_below must be positive, got %f", cfg.Display.CostColorYellowBelow))
 	}
+	switch cfg.Display.LatencyAverage {
+	case "mean", "trimmed", "winsorized":
+	default:
+		errs = append(errs, fmt.Sprintf("latency_average must be mean, trimmed or winsorized, got %q", cfg.Display.LatencyAverage))
+	}
+	if cfg.Display.LatencyTrimPercent < 0 || cfg.Display.LatencyTrimPercent >= 50 {
+		errs = append(errs, fmt.Sprintf("latency_trim_percent must be 0-50 (exclusive), got %f", cfg.Display.LatencyTrimPercent))
+	}
+	if cfg.Display.SlowRenderMS < 1 {
+		errs = append(errs, fmt.Sprintf("slow_render_ms must be positive, got %d", cfg.Display.SlowRenderMS))
+	}
+	switch cfg.Display.TimeFormat {
+	case "24h", "24h_seconds", "12h", "12h_seconds":
+	default:
+		errs = append(errs, fmt.Sprintf("time_format must be 24h, 24h_seconds, 12h or 12h_seconds, got %q", cfg.Display.TimeFormat))
+	}
+	switch cfg.Display.EventBufferEviction {
+	case "drop", "spill":
+	default:
+		errs = append(errs, fmt.Sprintf("event_buffer_eviction must be drop or spill, got %q", cfg.Display.EventBufferEviction))
+	}
+	switch cfg.Display.StartView {
+	case "startup", "dashboard", "auto":
+	default:
+		errs = append(errs, fmt.Sprintf("start_view must be startup, dashboard or auto, got %q", cfg.Display.StartView))
+	}
+	if v := cfg.Display.MinClaudeCodeVersion; v != "" && !isDottedVersion(v) {
+		errs = append(errs, fmt.Sprintf("min_claude_code_version must be a dotted version like 2.0.14, got %q", v))
+	}
+	errs = append(errs, validateTheme(cfg.Display.Theme)...)
+	errs = append(errs, validateBadges(cfg.Display.Badges)...)
 
 	for model, limit := range cfg.Models {
 		if limit < 1 {
@@ -404,10 +1375,82 @@ func validate(cfg *Config) error {
 	}
 
 	if cfg.Storage.RetentionDays <= 0 {
-		errs = append(errs, fmt.Sprintf("storage retention_days must be positive, got %d", cfg.Storage.RetentionDays))
+		errs = append(errs, fmt.Sprintf("storage retention_days_raw must be positive, got %d", cfg.Storage.RetentionDays))
 	}
 	if cfg.Storage.SummaryRetentionDays <= 0 {
-		errs = append(errs, fmt.Sprintf("storage summary_retention_days must be positive, got %d", cfg.Storage.SummaryRetentionDays))
+		errs = append(errs, fmt.Sprintf("storage retention_days_daily must be positive, got %d", cfg.Storage.SummaryRetentionDays))
+	}
+	if cfg.Storage.MaxDBSizeMB < 0 {
+		errs = append(errs, fmt.Sprintf("storage max_db_size_mb must not be negative, got %d", cfg.Storage.MaxDBSizeMB))
+	}
+	if w := cfg.Storage.MaintenanceWindow; w != "" {
+		if _, err := ParseDailyWindow(w); err != nil {
+			errs = append(errs, fmt.Sprintf("storage maintenance_window: %v", err))
+		}
+	}
+	if cfg.Storage.MaintenanceBusyWritesPerMinute < 0 {
+		errs = append(errs, fmt.Sprintf("storage maintenance_busy_writes_per_minute must not be negative, got %d", cfg.Storage.MaintenanceBusyWritesPerMinute))
+	}
+	if !slices.Contains(JournalModes, cfg.Storage.JournalMode) {
+		errs = append(errs, fmt.Sprintf("storage journal_mode must be one of %s, got %q", strings.Join(JournalModes, ", "), cfg.Storage.JournalMode))
+	}
+	if !slices.Contains(SynchronousModes, cfg.Storage.Synchronous) {
+		errs = append(errs, fmt.Sprintf("storage synchronous must be one of %s, got %q", strings.Join(SynchronousModes, ", "), cfg.Storage.Synchronous))
+	}
+	if cfg.Storage.BusyTimeoutMs < 0 {
+		errs = append(errs, fmt.Sprintf("storage busy_timeout_ms must not be negative, got %d", cfg.Storage.BusyTimeoutMs))
+	}
+	if cfg.Storage.BatchSize < 1 {
+		errs = append(errs, fmt.Sprintf("storage batch_size must be positive, got %d", cfg.Storage.BatchSize))
+	}
+	if cfg.Storage.FlushIntervalMs < 1 {
+		errs = append(errs, fmt.Sprintf("storage flush_interval_ms must be positive, got %d", cfg.Storage.FlushIntervalMs))
+	}
+	if cfg.Storage.ChannelSize < 1 {
+		errs = append(errs, fmt.Sprintf("storage channel_size must be positive, got %d", cfg.Storage.ChannelSize))
+	}
+	if cfg.Budget.WeeklyUSD < 0 {
+		errs = append(errs, fmt.Sprintf("budget weekly_usd must not be negative, got %g", cfg.Budget.WeeklyUSD))
+	}
+	if cfg.Budget.MonthlyUSD < 0 {
+		errs = append(errs, fmt.Sprintf("budget monthly_usd must not be negative, got %g", cfg.Budget.MonthlyUSD))
+	}
+	if cfg.Plan.WindowTokens < 0 {
+		errs = append(errs, fmt.Sprintf("plan window_tokens must not be negative, got %d", cfg.Plan.WindowTokens))
+	}
+	if cfg.Plan.WeeklyTokens < 0 {
+		errs = append(errs, fmt.Sprintf("plan weekly_tokens must not be negative, got %d", cfg.Plan.WeeklyTokens))
+	}
+	if cfg.Plan.WindowHours < 1 || cfg.Plan.WindowHours > 24 {
+		errs = append(errs, fmt.Sprintf("plan window_hours must be between 1 and 24, got %d", cfg.Plan.WindowHours))
+	}
+	if cfg.Projects.SubprojectDepth < 0 {
+		errs = append(errs, fmt.Sprintf("projects subproject_depth must not be negative, got %d", cfg.Projects.SubprojectDepth))
+	}
+	for _, w := range cfg.Projects.Workspaces {
+		if _, err := path.Match(w, ""); err != nil || w == "" || path.IsAbs(w) || slices.Contains(strings.Split(w, "/"), "..") {
+			errs = append(errs, fmt.Sprintf("projects workspaces: %q is not a glob relative to the repository root", w))
+		}
+	}
+	for _, p := range cfg.Budget.AlertPercentages {
+		if p <= 0 {
+			errs = append(errs, fmt.Sprintf("budget alert_percentages must be positive, got %g", p))
+		}
+	}
+	if u := cfg.PricingSync.URL; u != "" && !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		errs = append(errs, fmt.Sprintf("pricing_sync url must be an http:// or https:// URL, got %q", u))
+	}
+	if cfg.PricingSync.RefreshHours <= 0 {
+		errs = append(errs, fmt.Sprintf("pricing_sync refresh_hours must be positive, got %d", cfg.PricingSync.RefreshHours))
+	}
+	if _, port, err := net.SplitHostPort(cfg.Serve.Listen); err != nil || port == "" {
+		errs = append(errs, fmt.Sprintf("serve listen must be host:port, got %q", cfg.Serve.Listen))
+	}
+	if u := cfg.Push.URL; u != "" && !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		errs = append(errs, fmt.Sprintf("push url must be an http:// or https:// URL, got %q", u))
+	}
+	if cfg.Push.IntervalSeconds <= 0 {
+		errs = append(errs, fmt.Sprintf("push interval_seconds must be positive, got %d", cfg.Push.IntervalSeconds))
 	}
 
 	if len(errs) > 0 {
@@ -415,3 +1458,290 @@ func validate(cfg *Config) error {
 	}
 	return nil
 }
+
+// validateCustomRules checks [[alerts.custom]] definitions.
+func validateCustomRules(rules []CustomRuleConfig) []string {
+	var errs []string
+	seen := make(map[string]bool)
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: name is required", name))
+		} else if seen[r.Name] || slices.Contains(BuiltinRuleNames, r.Name) {
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: name must be unique and not a built-in rule", name))
+		}
+		seen[r.Name] = true
+
+		if (r.Metric == "") == (r.Event == "") {
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: set exactly one of metric or event", name))
+		}
+		switch r.Aggregation {
+		case "count":
+		case "sum", "avg", "min", "max":
+			if r.Event != "" && r.Field == "" {
+				errs = append(errs, fmt.Sprintf("alerts.custom %s: aggregation %q over events needs a field", name, r.Aggregation))
+			}
+		case "increase":
+			if r.Metric == "" {
+				errs = append(errs, fmt.Sprintf("alerts.custom %s: aggregation \"increase\" needs a metric", name))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: aggregation must be count, sum, avg, min, max or increase, got %q", name, r.Aggregation))
+		}
+		if r.WindowMinutes < 0 {
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: window_minutes must be 0 (whole session) or positive, got %d", name, r.WindowMinutes))
+		}
+		switch r.Op {
+		case ">", ">=", "<", "<=", "==", "!=":
+		default:
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: op must be one of > >= < <= == !=, got %q", name, r.Op))
+		}
+		switch r.Severity {
+		case "warning", "critical":
+		default:
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: severity must be warning or critical, got %q", name, r.Severity))
+		}
+		switch r.Scope {
+		case "", "session", "global":
+		default:
+			errs = append(errs, fmt.Sprintf("alerts.custom %s: scope must be session or global, got %q", name, r.Scope))
+		}
+	}
+	return errs
+}
+
+// validateCompositeRules checks [[alerts.composite]] definitions. Members
+// must be built-in or custom rules; composites cannot nest.
+func validateCompositeRules(rules []CompositeRuleConfig, custom []CustomRuleConfig) []string {
+	known := slices.Clone(BuiltinRuleNames)
+	for _, c := range custom {
+		known = append(known, c.Name)
+	}
+
+	var errs []string
+	seen := make(map[string]bool)
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+			errs = append(errs, fmt.Sprintf("alerts.composite %s: name is required", name))
+		} else if seen[r.Name] || slices.Contains(known, r.Name) {
+			errs = append(errs, fmt.Sprintf("alerts.composite %s: name must be unique and not a built-in or custom rule", name))
+		}
+		seen[r.Name] = true
+
+		if len(r.All)+len(r.Any) == 0 {
+			errs = append(errs, fmt.Sprintf("alerts.composite %s: set all, any or both", name))
+		}
+		for _, member := range slices.Concat(r.All, r.Any) {
+			if !slices.Contains(known, member) {
+				errs = append(errs, fmt.Sprintf("alerts.composite %s: unknown rule %q (want a built-in or custom rule)", name, member))
+			}
+		}
+		switch r.Severity {
+		case "warning", "critical":
+		default:
+			errs = append(errs, fmt.Sprintf("alerts.composite %s: severity must be warning or critical, got %q", name, r.Severity))
+		}
+	}
+	return errs
+}
+
+// validateEscalation checks that escalation policies name a known rule and
+// notification channel.
+// RuleNames returns the names of the built-in, custom and composite rules.
+func (a AlertsConfig) RuleNames() []string {
+	names := slices.Clone(BuiltinRuleNames)
+	for _, c := range a.Custom {
+		names = append(names, c.Name)
+	}
+	for _, c := range a.Composite {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func validateEscalation(a AlertsConfig) []string {
+	known := a.RuleNames()
+
+	rules := make([]string, 0, len(a.Escalation))
+	for rule := range a.Escalation {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	var errs []string
+	for _, rule := range rules {
+		p := a.Escalation[rule]
+		if !slices.Contains(known, rule) {
+			errs = append(errs, fmt.Sprintf("alerts.escalation.%s: unknown rule (want a built-in, custom or composite rule)", rule))
+		}
+		if p.AfterMinutes < 1 {
+			errs = append(errs, fmt.Sprintf("alerts.escalation.%s: after_minutes must be positive, got %d", rule, p.AfterMinutes))
+		}
+		switch p.Severity {
+		case "", "info", "warning", "critical":
+		default:
+			errs = append(errs, fmt.Sprintf("alerts.escalation.%s: severity must be info, warning or critical, got %q", rule, p.Severity))
+		}
+		for _, ch := range p.Channels {
+			if _, ok := a.Notifications.Channels[ch]; !ok && ch != SystemChannel {
+				errs = append(errs, fmt.Sprintf("alerts.escalation.%s: unknown channel %q", rule, ch))
+			}
+		}
+	}
+	return errs
+}
+
+// validateTheme checks the theme name and color overrides.
+func validateTheme(t ThemeConfig) []string {
+	var errs []string
+	if !slices.Contains(ThemeNames, t.Name) {
+		errs = append(errs, fmt.Sprintf("theme.name must be one of %s, got %q", strings.Join(ThemeNames, ", "), t.Name))
+	}
+	roles := make([]string, 0, len(t.Colors))
+	for role := range t.Colors {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		if !slices.Contains(ThemeColorRoles, role) {
+			errs = append(errs, fmt.Sprintf("theme.colors: unknown role %q (want one of %s)", role, strings.Join(ThemeColorRoles, ", ")))
+			continue
+		}
+		if !validColor(t.Colors[role]) {
+			errs = append(errs, fmt.Sprintf("theme.colors.%s must be a #rrggbb hex color or an ANSI color number 0-255, got %q", role, t.Colors[role]))
+		}
+	}
+	return errs
+}
+
+func validateBadges(badges map[string]float64) []string {
+	var errs []string
+	if len(badges) > maxBadges {
+		errs = append(errs, fmt.Sprintf("display.badges: at most %d KPIs fit in the header, got %d", maxBadges, len(badges)))
+	}
+	names := make([]string, 0, len(badges))
+	for name := range badges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		target := badges[name]
+		switch {
+		case !slices.Contains(BadgeKPIs, name):
+			errs = append(errs, fmt.Sprintf("display.badges: unknown KPI %q (want one of %s)", name, strings.Join(BadgeKPIs, ", ")))
+		case target <= 0:
+			errs = append(errs, fmt.Sprintf("display.badges.%s must be > 0, got %g", name, target))
+		case (name == "error_rate" || name == "cache_efficiency") && target > 100:
+			errs = append(errs, fmt.Sprintf("display.badges.%s is a percentage and must be <= 100, got %g", name, target))
+		}
+	}
+	return errs
+}
+
+// validColor reports whether c is a "#rrggbb" hex color or an ANSI color
+// number from 0 to 255.
+func validColor(c string) bool {
+	if hex, ok := strings.CutPrefix(c, "#"); ok {
+		if len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// validateForward checks the [receiver.forward] section.
+func validateForward(f ForwardConfig) []string {
+	var errs []string
+	if f.Endpoint != "" {
+		u, err := url.Parse(f.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("forward.endpoint must be an http:// or https:// URL, got %q", f.Endpoint))
+		}
+	}
+	if f.Protocol != "http/protobuf" && f.Protocol != "grpc" {
+		errs = append(errs, fmt.Sprintf("forward.protocol must be http/protobuf or grpc, got %q", f.Protocol))
+	}
+	if f.TimeoutSeconds < 1 {
+		errs = append(errs, fmt.Sprintf("forward.timeout_seconds must be positive, got %d", f.TimeoutSeconds))
+	}
+	if (f.TLS.CertFile == "") != (f.TLS.KeyFile == "") {
+		errs = append(errs, "forward.tls.cert_file and forward.tls.key_file must be set together")
+	}
+	return errs
+}
+
+// validateNotifications checks notification channels and routes.
+// isSessionMatcher reports whether kind is a matcher kind that selects
+// sessions, as used by suppressions and notification routes.
+func isSessionMatcher(kind string) bool {
+	switch kind {
+	case "tag", "project", "env", "host":
+		return true
+	}
+	return false
+}
+
+func validateNotifications(n NotificationConfig) []string {
+	var errs []string
+	switch n.Backend {
+	case "auto", "osascript", "dbus", "bell":
+	default:
+		errs = append(errs, fmt.Sprintf("notifications.backend must be auto, osascript, dbus or bell, got %q", n.Backend))
+	}
+	switch n.LogTarget {
+	case "", "syslog", "journald":
+	default:
+		errs = append(errs, fmt.Sprintf("notifications.log_target must be syslog or journald, got %q", n.LogTarget))
+	}
+	names := make([]string, 0, len(n.Channels))
+	for name := range n.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ch := n.Channels[name]
+		if name == SystemChannel {
+			errs = append(errs, fmt.Sprintf("notifications.channels.%s: %q is reserved for system notifications", name, SystemChannel))
+		}
+		if ch.Type != "slack" && ch.Type != "webhook" {
+			errs = append(errs, fmt.Sprintf("notifications.channels.%s: type must be slack or webhook, got %q", name, ch.Type))
+		}
+		if !strings.HasPrefix(ch.URL, "http://") && !strings.HasPrefix(ch.URL, "https://") {
+			errs = append(errs, fmt.Sprintf("notifications.channels.%s: url must be an http(s) URL, got %q", name, ch.URL))
+		}
+	}
+	for i, r := range n.Routes {
+		if len(r.Channels) == 0 {
+			errs = append(errs, fmt.Sprintf("notifications.routes #%d: channels must not be empty", i+1))
+		}
+		for _, ch := range r.Channels {
+			if _, ok := n.Channels[ch]; !ok && ch != SystemChannel {
+				errs = append(errs, fmt.Sprintf("notifications.routes #%d: unknown channel %q", i+1, ch))
+			}
+		}
+		for _, m := range r.Match {
+			kind, value, _ := strings.Cut(m, ":")
+			if (isSessionMatcher(kind) || kind == "rule" || kind == "severity") && strings.TrimSpace(value) != "" {
+				continue
+			}
+			errs = append(errs, fmt.Sprintf("notifications.routes #%d: matcher must be tag:, project:, env:, host:, rule: or severity:<value>, got %q", i+1, m))
+		}
+	}
+	return errs
+}
+
+// isDottedVersion reports whether v is a release number such as "2.0.14".
+func isDottedVersion(v string) bool {
+	for _, part := range strings.Split(v, ".") {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestConfigParser_Defaults(t *testing.T) {
@@ -59,6 +61,9 @@ func TestConfigParser_Defaults(t *testing.T) {
 	if cfg.Alerts.HighRejectionWindowMinutes != 5 {
 		t.Errorf("default high_rejection_window_minutes: want 5, got %d", cfg.Alerts.HighRejectionWindowMinutes)
 	}
+	if cfg.Alerts.SLAOverrunFactor != 1.5 {
+		t.Errorf("default sla_overrun_factor: want 1.5, got %g", cfg.Alerts.SLAOverrunFactor)
+	}
 	if !cfg.Alerts.Notifications.SystemNotify {
 		t.Error("default system_notify: want true, got false")
 	}
@@ -96,6 +101,8 @@ func TestConfigParser_CustomPorts(t *testing.T) {
 grpc_port = 5317
 http_port = 5318
 bind = "0.0.0.0"
+metrics_port = 9464
+auth_token = "s3cret"
 `
 	result, err := LoadFromString(tomlData)
 	if err != nil {
@@ -112,6 +119,15 @@ bind = "0.0.0.0"
 	if cfg.Receiver.Bind != "0.0.0.0" {
 		t.Errorf("bind: want 0.0.0.0, got %s", cfg.Receiver.Bind)
 	}
+	if cfg.Receiver.MetricsPort != 9464 {
+		t.Errorf("metrics_port: want 9464, got %d", cfg.Receiver.MetricsPort)
+	}
+	if cfg.Receiver.AuthToken != "s3cret" {
+		t.Errorf("auth_token: want s3cret, got %q", cfg.Receiver.AuthToken)
+	}
+	if _, err := LoadFromString("[receiver]\nauth_token = \"two words\"\n"); err == nil {
+		t.Error("an auth_token with whitespace should be rejected")
+	}
 
 	if cfg.Scanner.IntervalSeconds != 5 {
 		t.Errorf("default interval_seconds should be preserved: want 5, got %d", cfg.Scanner.IntervalSeconds)
@@ -121,6 +137,24 @@ bind = "0.0.0.0"
 	}
 }
 
+func TestConfigParser_AlertSuppressions(t *testing.T) {
+	result, err := LoadFromString(`
+[alerts.suppressions]
+SessionCost = ["tag:experiment"]
+ErrorStorm = ["project:sandbox/", "tag:demo"]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := result.Config.Alerts.Suppressions
+	if len(got["SessionCost"]) != 1 || got["SessionCost"][0] != "tag:experiment" {
+		t.Errorf("SessionCost suppressions: got %v", got["SessionCost"])
+	}
+	if len(got["ErrorStorm"]) != 2 {
+		t.Errorf("ErrorStorm suppressions: want 2, got %v", got["ErrorStorm"])
+	}
+}
+
 func TestConfigParser_PartialConfig(t *testing.T) {
 	tomlData := `
 [scanner]
@@ -159,6 +193,98 @@ error_storm_count = 20
 	if cfg.Display.EventBufferSize != 1000 {
 		t.Errorf("event_buffer_size default: want 1000, got %d", cfg.Display.EventBufferSize)
 	}
+	if !cfg.Display.RememberState {
+		t.Error("remember_state default: want true")
+	}
+
+	result, err = LoadFromString("[display]\nremember_state = false\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Config.Display.RememberState {
+		t.Error("remember_state = false was not applied")
+	}
+	if cfg.Display.StartView != "startup" {
+		t.Errorf("start_view default: want startup, got %q", cfg.Display.StartView)
+	}
+
+	if cfg.Display.EventBufferEviction != "drop" {
+		t.Errorf("event_buffer_eviction default: want drop, got %q", cfg.Display.EventBufferEviction)
+	}
+	result, err = LoadFromString("[display]\nevent_buffer_eviction = \"spill\"\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Config.Display.EventBufferEviction != "spill" {
+		t.Errorf("event_buffer_eviction: want spill, got %q", result.Config.Display.EventBufferEviction)
+	}
+
+	result, err = LoadFromString("[display]\nstart_view = \"auto\"\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Config.Display.StartView != "auto" {
+		t.Errorf("start_view: want auto, got %q", result.Config.Display.StartView)
+	}
+
+	if cfg.Display.MinClaudeCodeVersion != "" {
+		t.Errorf("min_claude_code_version default: want empty, got %q", cfg.Display.MinClaudeCodeVersion)
+	}
+	result, err = LoadFromString("[display]\nmin_claude_code_version = \"2.0.14\"\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Config.Display.MinClaudeCodeVersion != "2.0.14" {
+		t.Errorf("min_claude_code_version: want 2.0.14, got %q", result.Config.Display.MinClaudeCodeVersion)
+	}
+}
+
+func TestConfigParser_Theme(t *testing.T) {
+	if got := DefaultConfig().Display.Theme.Name; got != "dark" {
+		t.Errorf("theme default: want dark, got %q", got)
+	}
+
+	result, err := LoadFromString(`[display]
+time_format = "24h"
+
+[display.theme]
+name = "solarized"
+
+[display.theme.colors]
+accent = "#FF8800"
+dim = "244"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	theme := result.Config.Display.Theme
+	if theme.Name != "solarized" {
+		t.Errorf("theme name: want solarized, got %q", theme.Name)
+	}
+	if theme.Colors["accent"] != "#FF8800" || theme.Colors["dim"] != "244" {
+		t.Errorf("theme colors not parsed: %v", theme.Colors)
+	}
+	if result.Config.Display.TimeFormat != "24h" {
+		t.Error("other display keys should still apply alongside the theme")
+	}
+}
+
+func TestConfigParser_Badges(t *testing.T) {
+	if got := DefaultConfig().Display.Badges; len(got) != 0 {
+		t.Errorf("no badges by default, got %v", got)
+	}
+
+	result, err := LoadFromString(`[display.badges]
+cost_today = 25.0
+cache_efficiency = 60
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	badges := result.Config.Display.Badges
+	if len(badges) != 2 || badges["cost_today"] != 25 || badges["cache_efficiency"] != 60 {
+		t.Errorf("badges not parsed: %v", badges)
+	}
 }
 
 func TestConfigParser_InvalidValue(t *testing.T) {
@@ -180,6 +306,198 @@ grpc_port = 70000`,
 			name: "zero http_port",
 			toml: `[receiver]
 http_port = 0`,
+		},
+		{
+			name: "custom rule with metric and event",
+			toml: `[[alerts.custom]]
+name = "Both"
+metric = "claude_code.cost.usage"
+event = "claude_code.api_request"
+aggregation = "count"
+op = ">"
+threshold = 1
+severity = "warning"`,
+		},
+		{
+			name: "custom rule reusing a built-in name",
+			toml: `[[alerts.custom]]
+name = "ErrorStorm"
+event = "claude_code.api_error"
+aggregation = "count"
+op = ">"
+threshold = 1
+severity = "warning"`,
+		},
+		{
+			name: "custom rule summing events without field",
+			toml: `[[alerts.custom]]
+name = "SlowTools"
+event = "claude_code.tool_result"
+aggregation = "sum"
+op = ">"
+threshold = 1
+severity = "warning"`,
+		},
+		{
+			name: "custom rule with bad operator",
+			toml: `[[alerts.custom]]
+name = "BadOp"
+metric = "claude_code.cost.usage"
+aggregation = "increase"
+op = "=>"
+threshold = 1
+severity = "critical"`,
+		},
+		{
+			name: "composite rule with unknown member",
+			toml: `[[alerts.composite]]
+name = "RetryStorm"
+all = ["CostSurge", "ErrorStrom"]
+severity = "critical"`,
+		},
+		{
+			name: "composite rule without members",
+			toml: `[[alerts.composite]]
+name = "Empty"
+severity = "warning"`,
+		},
+		{
+			name: "composite rule reusing a built-in name",
+			toml: `[[alerts.composite]]
+name = "CostSurge"
+all = ["ErrorStorm", "LoopDetector"]
+severity = "warning"`,
+		},
+		{
+			name: "negative weekly budget",
+			toml: `[budget]
+weekly_usd = -10`,
+		},
+		{
+			name: "zero budget alert percentage",
+			toml: `[budget]
+alert_percentages = [0, 80]`,
+		},
+		{
+			name: "negative plan window tokens",
+			toml: `[plan]
+window_tokens = -1`,
+		},
+		{
+			name: "plan window longer than a day",
+			toml: `[plan]
+window_hours = 48`,
+		},
+		{
+			name: "projects workspace outside the repository",
+			toml: `[projects]
+workspaces = ["../other/*"]`,
+		},
+		{
+			name: "projects malformed workspace glob",
+			toml: `[projects]
+workspaces = ["packages/[a"]`,
+		},
+		{
+			name: "pricing sync url without scheme",
+			toml: `[pricing_sync]
+url = "example.com/pricing.json"`,
+		},
+		{
+			name: "zero pricing sync refresh",
+			toml: `[pricing_sync]
+refresh_hours = 0`,
+		},
+		{
+			name: "serve listen without port",
+			toml: `[serve]
+listen = "0.0.0.0"`,
+		},
+		{
+			name: "push url without scheme",
+			toml: `[push]
+url = "team-box:4320"`,
+		},
+		{
+			name: "zero push interval",
+			toml: `[push]
+interval_seconds = 0`,
+		},
+		{
+			name: "unknown badge KPI",
+			toml: `[display.badges]
+cost_week = 100.0`,
+		},
+		{
+			name: "too many badges",
+			toml: `[display.badges]
+cost_today = 20.0
+burn_rate = 5.0
+error_rate = 5.0
+cache_efficiency = 60.0`,
+		},
+		{
+			name: "badge percentage above 100",
+			toml: `[display.badges]
+cache_efficiency = 160.0`,
+		},
+		{
+			name: "unknown notification backend",
+			toml: `[alerts.notifications]
+backend = "growl"`,
+		},
+		{
+			name: "unknown notification log target",
+			toml: `[alerts.notifications]
+log_target = "eventlog"`,
+		},
+		{
+			name: "notification route to unknown channel",
+			toml: `[[alerts.notifications.routes]]
+match = ["project:~/work/"]
+channels = ["work-slack"]`,
+		},
+		{
+			name: "notification channel with bad type",
+			toml: `[alerts.notifications.channels.pager]
+type = "sms"
+url = "https://example.com/hook"`,
+		},
+		{
+			name: "notification route with bad matcher",
+			toml: `[[alerts.notifications.routes]]
+match = ["cwd:~/work"]
+channels = ["system"]`,
+		},
+		{
+			name: "admission matcher without kind",
+			toml: `[receiver.admission]
+allow = ["my-org"]`,
+		},
+		{
+			name: "admission matcher with empty value",
+			toml: `[receiver.admission]
+deny = ["cwd:"]`,
+		},
+		{
+			name: "invalid redaction pattern",
+			toml: `[receiver.redaction]
+patterns = ["token-("]`,
+		},
+		{
+			name: "empty redaction replacement",
+			toml: `[receiver.redaction]
+replacement = ""`,
+		},
+		{
+			name: "negative metrics_port",
+			toml: `[receiver]
+metrics_port = -1`,
+		},
+		{
+			name: "metrics_port clashes with http_port",
+			toml: `[receiver]
+metrics_port = 4318`,
 		},
 		{
 			name: "negative scanner interval",
@@ -190,6 +508,106 @@ interval_seconds = -5`,
 			name: "zero event_buffer_size",
 			toml: `[display]
 event_buffer_size = 0`,
+		},
+		{
+			name: "unknown latency_average",
+			toml: `[display]
+latency_average = "median"`,
+		},
+		{
+			name: "latency_trim_percent at 50",
+			toml: `[display]
+latency_trim_percent = 50`,
+		},
+		{
+			name: "zero slow_render_ms",
+			toml: `[display]
+slow_render_ms = 0`,
+		},
+		{
+			name: "unknown theme",
+			toml: `[display.theme]
+name = "neon"`,
+		},
+		{
+			name: "unknown theme color role",
+			toml: `[display.theme.colors]
+background = "#000000"`,
+		},
+		{
+			name: "invalid theme color",
+			toml: `[display.theme.colors]
+accent = "blue"`,
+		},
+		{
+			name: "theme color out of range",
+			toml: `[display.theme.colors]
+accent = "256"`,
+		},
+		{
+			name: "zero snooze_minutes",
+			toml: `[alerts]
+snooze_minutes = 0`,
+		},
+		{
+			name: "unknown event_buffer_eviction",
+			toml: `[display]
+event_buffer_eviction = "disk"`,
+		},
+		{
+			name: "unknown start_view",
+			toml: `[display]
+start_view = "stats"`,
+		},
+		{
+			name: "malformed min_claude_code_version",
+			toml: `[display]
+min_claude_code_version = "v2.0"`,
+		},
+		{
+			name: "forward endpoint without scheme",
+			toml: `[receiver.forward]
+endpoint = "otel.example.com:4317"`,
+		},
+		{
+			name: "unknown forward protocol",
+			toml: `[receiver.forward]
+protocol = "http/json"`,
+		},
+		{
+			name: "forward client cert without key",
+			toml: `[receiver.forward.tls]
+cert_file = "client.pem"`,
+		},
+		{
+			name: "receiver tls key without cert",
+			toml: `[receiver.tls]
+key_file = "server-key.pem"`,
+		},
+		{
+			name: "receiver client ca without server cert",
+			toml: `[receiver.tls]
+client_ca_file = "clients.pem"`,
+		},
+		{
+			name: "unknown time_format",
+			toml: `[display]
+time_format = "military"`,
+		},
+		{
+			name: "unknown suppression matcher",
+			toml: `[alerts.suppressions]
+SessionCost = ["label:experiment"]`,
+		},
+		{
+			name: "empty suppression project",
+			toml: `[alerts.suppressions]
+ErrorStorm = ["project:"]`,
+		},
+		{
+			name: "empty suppression env",
+			toml: `[alerts.suppressions]
+ErrorStorm = ["env:"]`,
 		},
 		{
 			name: "negative context_pressure_percent",
@@ -201,6 +619,28 @@ context_pressure_percent = -10`,
 			toml: `[alerts]
 context_pressure_percent = 101`,
 		},
+		{
+			name: "escalation for unknown rule",
+			toml: `[alerts.escalation.NoSuchRule]
+after_minutes = 10`,
+		},
+		{
+			name: "escalation after_minutes zero",
+			toml: `[alerts.escalation.ErrorStorm]
+after_minutes = 0`,
+		},
+		{
+			name: "escalation with invalid severity",
+			toml: `[alerts.escalation.ErrorStorm]
+after_minutes = 10
+severity = "urgent"`,
+		},
+		{
+			name: "escalation to unknown channel",
+			toml: `[alerts.escalation.ErrorStorm]
+after_minutes = 10
+channels = ["oncall"]`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -301,6 +741,46 @@ func TestConfigParser_ModelContextLimits(t *testing.T) {
 	}
 }
 
+func TestConfigParser_PricingTiers(t *testing.T) {
+	tomlData := `
+[models.pricing]
+"my-custom-model" = [1.00, 5.00, 0.10, 1.25]
+
+[models.pricing.batch]
+"my-custom-model" = [0.50, 2.50, 0.05, 0.625]
+
+[models.pricing.priority]
+"my-custom-model" = [1.50, 7.50, 0.15, 1.875]
+
+[models.pricing.standard]
+"other-model" = [2.00, 10.00, 0.20, 2.50]
+`
+	result, err := LoadFromString(tomlData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := result.Config
+
+	if got := cfg.Pricing["my-custom-model"]; got[0] != 1.00 {
+		t.Errorf("standard pricing: got %v", got)
+	}
+	if got := cfg.Pricing["other-model"]; got[1] != 10.00 {
+		t.Errorf("[models.pricing.standard] should merge into standard pricing, got %v", got)
+	}
+	if got := cfg.PricingTiers["batch"]["my-custom-model"]; got[0] != 0.50 || got[3] != 0.625 {
+		t.Errorf("batch pricing: got %v", got)
+	}
+	if got := cfg.PricingTiers["priority"]["my-custom-model"]; got[1] != 7.50 {
+		t.Errorf("priority pricing: got %v", got)
+	}
+	if _, ok := cfg.PricingTiers["batch"]["claude-opus-4-6"]; !ok {
+		t.Error("default batch pricing should be preserved")
+	}
+	if _, ok := cfg.Pricing["batch"]; ok {
+		t.Error("tier tables must not be treated as model prices")
+	}
+}
+
 func TestConfigParser_FileLoad(t *testing.T) {
 	dir := t.TempDir()
 	configPath := filepath.Join(dir, "config.toml")
@@ -409,6 +889,116 @@ high_rejection_window_minutes = 10
 	}
 }
 
+func TestConfigParser_CacheInvalidation(t *testing.T) {
+	result, err := LoadFromString("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := result.Config.Alerts; a.CacheInvalidationCount != 3 || a.CacheInvalidationWindowMinutes != 60 {
+		t.Errorf("defaults: want 3 in 60 minutes, got %d in %d", a.CacheInvalidationCount, a.CacheInvalidationWindowMinutes)
+	}
+
+	result, err = LoadFromString(`
+[alerts]
+cache_invalidation_count = 5
+cache_invalidation_window_minutes = 30
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := result.Config.Alerts; a.CacheInvalidationCount != 5 || a.CacheInvalidationWindowMinutes != 30 {
+		t.Errorf("want 5 in 30 minutes, got %d in %d", a.CacheInvalidationCount, a.CacheInvalidationWindowMinutes)
+	}
+}
+
+func TestConfigParser_ToolFailures(t *testing.T) {
+	result, err := LoadFromString("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := result.Config.Alerts; a.ToolFailureCount != 5 || a.ToolFailureWindowMinutes != 10 {
+		t.Errorf("defaults: want 5 in 10 minutes, got %d in %d", a.ToolFailureCount, a.ToolFailureWindowMinutes)
+	}
+
+	result, err = LoadFromString(`
+[alerts]
+tool_failure_count = 3
+tool_failure_window_minutes = 15
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := result.Config.Alerts; a.ToolFailureCount != 3 || a.ToolFailureWindowMinutes != 15 {
+		t.Errorf("want 3 in 15 minutes, got %d in %d", a.ToolFailureCount, a.ToolFailureWindowMinutes)
+	}
+}
+
+func TestConfigParser_Escalation(t *testing.T) {
+	result, err := LoadFromString(`
+[alerts.notifications.channels.oncall]
+type = "webhook"
+url = "https://example.com/hook"
+
+[alerts.escalation.ErrorStorm]
+after_minutes = 10
+channels = ["oncall", "system"]
+severity = "critical"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, ok := result.Config.Alerts.Escalation["ErrorStorm"]
+	if !ok {
+		t.Fatal("ErrorStorm escalation policy missing")
+	}
+	if p.AfterMinutes != 10 || p.Severity != "critical" || len(p.Channels) != 2 || p.Channels[0] != "oncall" {
+		t.Errorf("unexpected policy %+v", p)
+	}
+}
+
+func TestConfigParser_RequestBudget(t *testing.T) {
+	result, err := LoadFromString("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Config.Alerts.APIRequestBudgetPerHour; got != 600 {
+		t.Errorf("default api_request_budget_per_hour = %d, want 600", got)
+	}
+
+	result, err = LoadFromString(`
+[alerts]
+api_request_budget_per_hour = 150
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Config.Alerts.APIRequestBudgetPerHour; got != 150 {
+		t.Errorf("api_request_budget_per_hour = %d, want 150", got)
+	}
+
+	if _, err := LoadFromString("[alerts]\napi_request_budget_per_hour = 0\n"); err != nil {
+		t.Errorf("0 should be accepted to disable the rule: %v", err)
+	}
+}
+
+func TestConfigParser_AnomalousSpend(t *testing.T) {
+	result, err := LoadFromString("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Config.Alerts.AnomalousSpendStdDevs; got != 3 {
+		t.Errorf("default anomalous_spend_stddevs: want 3, got %g", got)
+	}
+
+	result, err = LoadFromString("[alerts]\nanomalous_spend_stddevs = 2.5\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Config.Alerts.AnomalousSpendStdDevs; got != 2.5 {
+		t.Errorf("anomalous_spend_stddevs: want 2.5, got %g", got)
+	}
+}
+
 func TestConfigParser_HighRejectionInvalid(t *testing.T) {
 	tests := []struct {
 		name string
@@ -429,6 +1019,51 @@ high_rejection_percent = 101`,
 			toml: `[alerts]
 high_rejection_window_minutes = 0`,
 		},
+		{
+			name: "cache_invalidation_count zero",
+			toml: `[alerts]
+cache_invalidation_count = 0`,
+		},
+		{
+			name: "cache_invalidation_window_minutes zero",
+			toml: `[alerts]
+cache_invalidation_window_minutes = 0`,
+		},
+		{
+			name: "tool_failure_count zero",
+			toml: `[alerts]
+tool_failure_count = 0`,
+		},
+		{
+			name: "tool_failure_window_minutes negative",
+			toml: `[alerts]
+tool_failure_window_minutes = -1`,
+		},
+		{
+			name: "api_request_budget_per_hour negative",
+			toml: `[alerts]
+api_request_budget_per_hour = -1`,
+		},
+		{
+			name: "anomalous_spend_stddevs zero",
+			toml: `[alerts]
+anomalous_spend_stddevs = 0`,
+		},
+		{
+			name: "sla_overrun_factor below 1",
+			toml: `[alerts]
+sla_overrun_factor = 0.5`,
+		},
+		{
+			name: "auto_threshold_percentile below 50",
+			toml: `[alerts]
+auto_threshold_percentile = 20`,
+		},
+		{
+			name: "auto_threshold_percentile over 100",
+			toml: `[alerts]
+auto_threshold_percentile = 101`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -483,6 +1118,91 @@ summary_retention_days = 180
 	}
 }
 
+func TestStorageConfig_RetentionKeys(t *testing.T) {
+	tomlData := `
+[storage]
+retention_days = 14
+retention_days_raw = 3
+retention_days_daily = 30
+max_db_size_mb = 512
+`
+	result, err := LoadFromString(tomlData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	st := result.Config.Storage
+	if st.RetentionDays != 3 {
+		t.Errorf("retention_days_raw should win over retention_days: got %d", st.RetentionDays)
+	}
+	if st.SummaryRetentionDays != 30 {
+		t.Errorf("retention_days_daily: want 30, got %d", st.SummaryRetentionDays)
+	}
+	if st.MaxDBSizeMB != 512 {
+		t.Errorf("max_db_size_mb: want 512, got %d", st.MaxDBSizeMB)
+	}
+	if DefaultConfig().Storage.MaxDBSizeMB != 0 {
+		t.Error("max_db_size_mb should be off by default")
+	}
+}
+
+func TestStorageConfig_Tuning(t *testing.T) {
+	result, err := LoadFromString(`
+[storage]
+journal_mode = "WAL"
+synchronous = "normal"
+busy_timeout_ms = 10000
+batch_size = 200
+flush_interval_ms = 250
+channel_size = 10000
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	st := result.Config.Storage
+	if st.JournalMode != "wal" || st.Synchronous != "normal" || st.BusyTimeoutMs != 10000 ||
+		st.BatchSize != 200 || st.FlushIntervalMs != 250 || st.ChannelSize != 10000 {
+		t.Errorf("unexpected storage tuning %+v", st)
+	}
+
+	for _, toml := range []string{
+		"[storage]\njournal_mode = \"memory\"",
+		"[storage]\nsynchronous = \"sometimes\"",
+		"[storage]\nbusy_timeout_ms = -1",
+		"[storage]\nbatch_size = 0",
+		"[storage]\nflush_interval_ms = 0",
+		"[storage]\nchannel_size = 0",
+	} {
+		if _, err := LoadFromString(toml); err == nil {
+			t.Errorf("expected a validation error for %q", toml)
+		}
+	}
+}
+
+func TestStorageConfig_MaintenanceAndReadOnly(t *testing.T) {
+	def := DefaultConfig().Storage
+	if def.MaintenanceWindow != "" || def.MaintenanceBusyWritesPerMinute != 60 {
+		t.Errorf("unexpected maintenance defaults %q, %d", def.MaintenanceWindow, def.MaintenanceBusyWritesPerMinute)
+	}
+
+	result, err := LoadFromString("[storage]\nmaintenance_window = \"03:00-04:00\"\nmaintenance_busy_writes_per_minute = 0\nread_only = true\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st := result.Config.Storage; st.MaintenanceWindow != "03:00-04:00" || st.MaintenanceBusyWritesPerMinute != 0 || !st.ReadOnly {
+		t.Errorf("unexpected maintenance settings %q, %d", st.MaintenanceWindow, st.MaintenanceBusyWritesPerMinute)
+	}
+
+	for _, toml := range []string{
+		"[storage]\nmaintenance_window = \"3am\"",
+		"[storage]\nmaintenance_window = \"03:00-03:00\"",
+		"[storage]\nmaintenance_busy_writes_per_minute = -1",
+	} {
+		if _, err := LoadFromString(toml); err == nil {
+			t.Errorf("expected a validation error for %q", toml)
+		}
+	}
+}
+
 func TestStorageConfig_ValidationRejectsZeroRetention(t *testing.T) {
 	tests := []struct {
 		name string
@@ -508,6 +1228,21 @@ retention_days = -1`,
 			toml: `[storage]
 summary_retention_days = -5`,
 		},
+		{
+			name: "retention_days_raw zero",
+			toml: `[storage]
+retention_days_raw = 0`,
+		},
+		{
+			name: "retention_days_daily negative",
+			toml: `[storage]
+retention_days_daily = -1`,
+		},
+		{
+			name: "max_db_size_mb negative",
+			toml: `[storage]
+max_db_size_mb = -1`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -557,3 +1292,419 @@ unknown_field = "value"
 		t.Errorf("retention_days: want 7, got %d", result.Config.Storage.RetentionDays)
 	}
 }
+
+func TestConfigParser_IncludeLayering(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "shared")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(sub, "base.toml"): `
+include = ["nested.toml"]
+
+[receiver]
+grpc_port = 5000
+
+[alerts]
+session_cost_threshold = 20.0
+`,
+		// Relative to base.toml's directory.
+		filepath.Join(sub, "nested.toml"): `
+[display]
+event_buffer_size = 1500
+
+[models]
+team-model = 50000
+`,
+		filepath.Join(dir, "team.toml"): `
+[receiver]
+grpc_port = 6000
+http_port = 6001
+`,
+		filepath.Join(dir, "config.toml"): `
+include = ["shared/base.toml", "./team.toml", "missing.toml"]
+
+[receiver]
+http_port = 7001
+`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", path, err)
+		}
+	}
+
+	result, err := LoadFrom(filepath.Join(dir, "config.toml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := result.Config
+
+	if cfg.Receiver.GRPCPort != 6000 {
+		t.Errorf("grpc_port: later include should win, got %d", cfg.Receiver.GRPCPort)
+	}
+	if cfg.Receiver.HTTPPort != 7001 {
+		t.Errorf("http_port: including file should win, got %d", cfg.Receiver.HTTPPort)
+	}
+	if cfg.Alerts.SessionCostThreshold != 20.0 {
+		t.Errorf("session_cost_threshold from include: want 20, got %f", cfg.Alerts.SessionCostThreshold)
+	}
+	if cfg.Display.EventBufferSize != 1500 {
+		t.Errorf("event_buffer_size from nested include: want 1500, got %d", cfg.Display.EventBufferSize)
+	}
+	if cfg.Models["team-model"] != 50000 {
+		t.Errorf("models from nested include: want 50000, got %d", cfg.Models["team-model"])
+	}
+	if cfg.Display.RefreshRateMS != 500 {
+		t.Errorf("refresh_rate_ms should keep default, got %d", cfg.Display.RefreshRateMS)
+	}
+
+	var missingWarned bool
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "missing.toml") {
+			missingWarned = true
+		}
+		if strings.Contains(w, "include") && !strings.Contains(w, "missing.toml") {
+			t.Errorf("include key should not be reported as unknown: %s", w)
+		}
+	}
+	if !missingWarned {
+		t.Errorf("expected warning for missing include, got %v", result.Warnings)
+	}
+}
+
+func TestConfigParser_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.toml")
+	b := filepath.Join(dir, "b.toml")
+	if err := os.WriteFile(a, []byte(`include = ["b.toml"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`include = ["a.toml"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFrom(a)
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Fatalf("expected include cycle error, got %v", err)
+	}
+}
+
+func TestConfigParser_IncludeInvalidType(t *testing.T) {
+	if _, err := LoadFromString(`include = "team.toml"`); err == nil {
+		t.Fatal("expected error for non-array include")
+	}
+}
+
+func TestConfigParser_Admission(t *testing.T) {
+	result, err := LoadFromString(`
+[receiver]
+grpc_port = 5317
+
+[receiver.admission]
+allow = ["org:acme", "cwd:~/work/"]
+deny = ["cwd:~/work/client-x"]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	adm := result.Config.Receiver.Admission
+	if len(adm.Allow) != 2 || adm.Allow[0] != "org:acme" || len(adm.Deny) != 1 {
+		t.Errorf("admission not parsed: %+v", adm)
+	}
+	if result.Config.Receiver.GRPCPort != 5317 {
+		t.Errorf("grpc_port: want 5317, got %d", result.Config.Receiver.GRPCPort)
+	}
+}
+
+func TestConfigParser_Redaction(t *testing.T) {
+	if red := DefaultConfig().Receiver.Redaction; red.Enabled || !red.APIKeys || !red.Emails || red.Replacement != "[REDACTED]" {
+		t.Errorf("redaction defaults: %+v", red)
+	}
+
+	result, err := LoadFromString(`
+[receiver.redaction]
+enabled = true
+emails = false
+patterns = ['ACME-\d{6}']
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	red := result.Config.Receiver.Redaction
+	if !red.Enabled || red.Emails || !red.APIKeys || len(red.Patterns) != 1 || len(red.SensitiveDirs) == 0 {
+		t.Errorf("redaction not merged over the defaults: %+v", red)
+	}
+}
+
+func TestConfigParser_Forward(t *testing.T) {
+	if fwd := DefaultConfig().Receiver.Forward; fwd.Endpoint != "" || fwd.Protocol != "http/protobuf" || fwd.TimeoutSeconds != 10 {
+		t.Errorf("forward defaults: %+v", fwd)
+	}
+
+	result, err := LoadFromString(`
+[receiver.forward]
+endpoint = "https://otel.example.com:4318"
+headers = { authorization = "Bearer abc" }
+
+[receiver.forward.tls]
+ca_file = "/etc/ssl/corp-ca.pem"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fwd := result.Config.Receiver.Forward
+	if fwd.Endpoint != "https://otel.example.com:4318" || fwd.Headers["authorization"] != "Bearer abc" {
+		t.Errorf("forward not parsed: %+v", fwd)
+	}
+	if fwd.TLS.CAFile != "/etc/ssl/corp-ca.pem" {
+		t.Errorf("forward.tls.ca_file: got %q", fwd.TLS.CAFile)
+	}
+	if fwd.Protocol != "http/protobuf" || fwd.TimeoutSeconds != 10 {
+		t.Errorf("unset forward keys should keep their defaults: %+v", fwd)
+	}
+}
+
+func TestConfigParser_ReceiverTLS(t *testing.T) {
+	if DefaultConfig().Receiver.TLS.Enabled() {
+		t.Error("receiver TLS should be off by default")
+	}
+
+	result, err := LoadFromString(`
+[receiver.tls]
+cert_file = "/etc/cc-top/server.pem"
+key_file = "/etc/cc-top/server-key.pem"
+client_ca_file = "/etc/cc-top/clients.pem"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ReceiverTLSConfig{
+		CertFile:     "/etc/cc-top/server.pem",
+		KeyFile:      "/etc/cc-top/server-key.pem",
+		ClientCAFile: "/etc/cc-top/clients.pem",
+	}
+	if got := result.Config.Receiver.TLS; got != want || !got.Enabled() {
+		t.Errorf("receiver.tls = %+v, want %+v", got, want)
+	}
+}
+
+func TestConfigParser_CustomRules(t *testing.T) {
+	result, err := LoadFromString(`
+[[alerts.custom]]
+name = "BashFailures"
+event = "claude_code.tool_result"
+where = { tool_name = "Bash", success = "false" }
+aggregation = "count"
+window_minutes = 10
+op = ">="
+threshold = 5
+severity = "warning"
+
+[[alerts.custom]]
+name = "TeamSpend"
+metric = "claude_code.cost.usage"
+aggregation = "increase"
+window_minutes = 60
+op = ">"
+threshold = 20
+severity = "critical"
+scope = "global"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := result.Config.Alerts.Custom
+	if len(rules) != 2 {
+		t.Fatalf("want 2 custom rules, got %d", len(rules))
+	}
+	if rules[0].Where["tool_name"] != "Bash" || rules[0].WindowMinutes != 10 || rules[0].Op != ">=" {
+		t.Errorf("first rule not parsed: %+v", rules[0])
+	}
+	if rules[1].Scope != "global" || rules[1].Threshold != 20 {
+		t.Errorf("second rule not parsed: %+v", rules[1])
+	}
+}
+
+func TestConfigParser_CompositeRules(t *testing.T) {
+	result, err := LoadFromString(`
+[[alerts.custom]]
+name = "BashFailures"
+event = "claude_code.tool_result"
+aggregation = "count"
+op = ">="
+threshold = 5
+severity = "warning"
+
+[[alerts.composite]]
+name = "RetryStorm"
+all = ["CostSurge", "ErrorStorm"]
+severity = "critical"
+message = "Likely retry storm"
+suppress_members = true
+
+[[alerts.composite]]
+name = "Flailing"
+any = ["LoopDetector", "BashFailures"]
+severity = "warning"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := result.Config.Alerts.Composite
+	if len(rules) != 2 {
+		t.Fatalf("want 2 composite rules, got %d", len(rules))
+	}
+	if len(rules[0].All) != 2 || rules[0].All[1] != "ErrorStorm" || !rules[0].SuppressMembers || rules[0].Message != "Likely retry storm" {
+		t.Errorf("first rule not parsed: %+v", rules[0])
+	}
+	if len(rules[1].Any) != 2 || rules[1].Any[1] != "BashFailures" || rules[1].SuppressMembers {
+		t.Errorf("second rule not parsed: %+v", rules[1])
+	}
+}
+
+func TestConfigParser_NotificationRoutes(t *testing.T) {
+	result, err := LoadFromString(`
+[alerts.notifications.channels.work-slack]
+type = "slack"
+url = "https://hooks.slack.com/services/T000/B000/XXXX"
+
+[[alerts.notifications.routes]]
+match = ["project:~/work/"]
+channels = ["work-slack", "system"]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := result.Config.Alerts.Notifications
+	if !n.SystemNotify {
+		t.Error("system_notify should keep its default when only routes are set")
+	}
+	if n.Channels["work-slack"].Type != "slack" {
+		t.Errorf("channel not parsed: %+v", n.Channels)
+	}
+	if len(n.Routes) != 1 || len(n.Routes[0].Channels) != 2 || n.Routes[0].Match[0] != "project:~/work/" {
+		t.Errorf("routes not parsed: %+v", n.Routes)
+	}
+
+	result, err = LoadFromString("[alerts.notifications]\nlog_target = \"journald\"\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Config.Alerts.Notifications.LogTarget; got != "journald" {
+		t.Errorf("log_target: got %q, want journald", got)
+	}
+	if got := result.Config.Alerts.Notifications.Backend; got != "auto" {
+		t.Errorf("backend default: got %q, want auto", got)
+	}
+
+	result, err = LoadFromString("[alerts.notifications]\nbackend = \"bell\"\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Config.Alerts.Notifications.Backend; got != "bell" {
+		t.Errorf("backend: got %q, want bell", got)
+	}
+}
+
+func TestConfigParser_Plan(t *testing.T) {
+	result, err := LoadFromString(`
+[plan]
+name = "Max"
+window_tokens = 2000000
+weekly_tokens = 40000000
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := result.Config.Plan
+	if p.Name != "Max" || p.WindowTokens != 2_000_000 || p.WeeklyTokens != 40_000_000 || p.WindowHours != 5 {
+		t.Errorf("plan: got %+v", p)
+	}
+	if !p.Enabled() || DefaultConfig().Plan.Enabled() {
+		t.Error("only a plan with quotas should be enabled")
+	}
+}
+
+func TestConfigParser_Projects(t *testing.T) {
+	result, err := LoadFromString(`
+[projects]
+workspaces = ["packages/*", "services/*/api"]
+subproject_depth = 1
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := result.Config.Projects
+	if p.SubprojectDepth != 1 || len(p.Workspaces) != 2 || p.Workspaces[1] != "services/*/api" {
+		t.Errorf("projects: got %+v", p)
+	}
+}
+
+func TestConfigParser_Budget(t *testing.T) {
+	result, err := LoadFromString(`
+[budget]
+monthly_usd = 500
+alert_percentages = [75, 100]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := result.Config.Budget
+	if b.WeeklyUSD != 0 || b.MonthlyUSD != 500 {
+		t.Errorf("budgets: got weekly=%g monthly=%g, want 0 and 500", b.WeeklyUSD, b.MonthlyUSD)
+	}
+	if len(b.AlertPercentages) != 2 || b.AlertPercentages[0] != 75 {
+		t.Errorf("alert_percentages: got %v", b.AlertPercentages)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+}
+
+func TestQuietHoursConfig(t *testing.T) {
+	result, err := LoadFromString("[alerts.quiet_hours]\nwindow = \"22:00-08:00\"\nweekends = true\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q := result.Config.Alerts.QuietHours; q.Window != "22:00-08:00" || !q.Weekends {
+		t.Errorf("quiet_hours = %+v", q)
+	}
+	w, err := ParseDailyWindow("22:00-08:00")
+	if err != nil || w != (DailyWindow{Start: 22 * time.Hour, End: 8 * time.Hour}) {
+		t.Errorf("ParseDailyWindow = %+v, %v", w, err)
+	}
+
+	for _, bad := range []string{"22:00", "25:00-08:00", "08:00-08:00"} {
+		if _, err := LoadFromString("[alerts.quiet_hours]\nwindow = \"" + bad + "\"\n"); err == nil {
+			t.Errorf("window %q should be rejected", bad)
+		}
+	}
+}
+
+func TestDailyWindow_Contains(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 10, 16, h, m, 0, 0, time.UTC) }
+	day := DailyWindow{Start: 9 * time.Hour, End: 17 * time.Hour}
+	night := DailyWindow{Start: 22 * time.Hour, End: 8 * time.Hour}
+
+	for _, tt := range []struct {
+		w    DailyWindow
+		t    time.Time
+		want bool
+	}{
+		{day, at(9, 0), true},
+		{day, at(16, 59), true},
+		{day, at(17, 0), false},
+		{day, at(3, 0), false},
+		{night, at(23, 30), true},
+		{night, at(7, 59), true},
+		{night, at(8, 0), false},
+		{night, at(12, 0), false},
+	} {
+		if got := tt.w.Contains(tt.t); got != tt.want {
+			t.Errorf("%+v.Contains(%s) = %v, want %v", tt.w, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
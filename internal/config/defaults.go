@@ -6,6 +6,16 @@ func DefaultConfig() Config {
 			GRPCPort: 4317,
 			HTTPPort: 4318,
 			Bind:     "127.0.0.1",
+			Forward: ForwardConfig{
+				Protocol:       "http/protobuf",
+				TimeoutSeconds: 10,
+			},
+			Redaction: RedactionConfig{
+				APIKeys:       true,
+				Emails:        true,
+				SensitiveDirs: []string{"~/.ssh", "~/.aws", "~/.gnupg", "~/.kube"},
+				Replacement:   "[REDACTED]",
+			},
 		},
 		Scanner: ScannerConfig{
 			IntervalSeconds: 5,
@@ -22,20 +32,64 @@ func DefaultConfig() Config {
 			ContextPressurePercent:       80,
 			HighRejectionPercent:         50,
 			HighRejectionWindowMinutes:   5,
+			AutoThresholdPercentile:      95,
+			SLAOverrunFactor:             1.5,
+			SnoozeMinutes:                60,
+
+			CacheInvalidationCount:         3,
+			CacheInvalidationWindowMinutes: 60,
+			AnomalousSpendStdDevs:          3,
+			ToolFailureCount:               5,
+			ToolFailureWindowMinutes:       10,
+			APIRequestBudgetPerHour:        600,
+
 			Notifications: NotificationConfig{
 				SystemNotify: true,
+				Backend:      "auto",
 			},
 		},
 		Display: DisplayConfig{
 			EventBufferSize:      1000,
+			EventBufferEviction:  "drop",
 			RefreshRateMS:        500,
 			CostColorGreenBelow:  0.50,
 			CostColorYellowBelow: 2.00,
+			LatencyAverage:       "mean",
+			LatencyTrimPercent:   5,
+			SlowRenderMS:         50,
+			TimeFormat:           "24h_seconds",
+			RememberState:        true,
+			StartView:            "startup",
+			TerminalTitle:        true,
+			Theme:                ThemeConfig{Name: "dark"},
 		},
 		Storage: StorageConfig{
-			DBPath:               "~/.local/share/cc-top/cc-top.db",
-			RetentionDays:        7,
-			SummaryRetentionDays: 90,
+			DBPath:                         "~/.local/share/cc-top/cc-top.db",
+			RetentionDays:                  7,
+			SummaryRetentionDays:           90,
+			MaintenanceBusyWritesPerMinute: 60,
+			JournalMode:                    "wal",
+			Synchronous:                    "full",
+			BusyTimeoutMs:                  5000,
+			BatchSize:                      50,
+			FlushIntervalMs:                100,
+			ChannelSize:                    1000,
+		},
+		Budget: BudgetConfig{
+			AlertPercentages: []float64{50, 80, 100},
+		},
+		Plan: PlanConfig{
+			WindowHours: 5,
+		},
+		PricingSync: PricingSyncConfig{
+			RefreshHours: 24,
+			CachePath:    "~/.cache/cc-top/pricing.json",
+		},
+		Serve: ServeConfig{
+			Listen: "0.0.0.0:4320",
+		},
+		Push: PushConfig{
+			IntervalSeconds: 30,
 		},
 		Models: defaultModelContextLimits(),
 		Pricing: map[string][4]float64{
@@ -43,6 +97,14 @@ func DefaultConfig() Config {
 			"claude-opus-4-6":            {5.00, 25.00, 0.50, 6.25},
 			"claude-haiku-4-5-20251001":  {1.00, 5.00, 0.10, 1.25},
 		},
+		PricingTiers: map[string]map[string][4]float64{
+			// Message Batches are billed at 50% of standard prices.
+			"batch": {
+				"claude-sonnet-4-5-20250929": {1.50, 7.50, 0.15, 1.875},
+				"claude-opus-4-6":            {2.50, 12.50, 0.25, 3.125},
+				"claude-haiku-4-5-20251001":  {0.50, 2.50, 0.05, 0.625},
+			},
+		},
 	}
 }
 
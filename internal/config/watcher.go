@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"log"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Reload is published by a Watcher when the config file changes.
+type Reload struct {
+	// Config is the reloaded config, or the config still in effect when
+	// Err is set.
+	Config   Config
+	Warnings []string
+	// Restart lists changed settings that only take effect after a
+	// restart; the rest of the new config is applied.
+	Restart []string
+	// Err is set when the changed file failed to load or validate.
+	Err error
+}
+
+// fileStamp identifies one version of a file; a missing file is the zero
+// stamp.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls a config file and the files it includes, reloads the
+// config when any of them is written, created or removed, and publishes
+// the result to its subscribers. Polling keeps it dependency-free and
+// also catches editors that replace the file instead of writing it.
+type Watcher struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.Mutex
+	current Config
+	stamps  map[string]fileStamp
+	subs    []func(Reload)
+}
+
+// WatcherOption configures a Watcher.
+type WatcherOption func(*Watcher)
+
+// WithPollInterval sets how often the watched files are checked. The
+// default is 2 seconds.
+func WithPollInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) { w.interval = d }
+}
+
+// NewWatcher watches path, which was loaded into loaded. Reloads are
+// compared against loaded.Config.
+func NewWatcher(path string, loaded *LoadResult, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		path:     path,
+		interval: 2 * time.Second,
+		current:  loaded.Config,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.stamps = stampFiles(w.files(loaded))
+	return w
+}
+
+// Subscribe registers fn to be called, from the watcher's goroutine, with
+// every reload.
+func (w *Watcher) Subscribe(fn func(Reload)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Start polls the watched files until ctx is cancelled.
+func (w *Watcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+}
+
+// check reloads the config if a watched file changed and reports whether
+// it published a Reload.
+func (w *Watcher) check() bool {
+	w.mu.Lock()
+	if reflect.DeepEqual(stampFiles(slices.Collect(maps.Keys(w.stamps))), w.stamps) {
+		w.mu.Unlock()
+		return false
+	}
+
+	loaded, err := LoadFrom(w.path)
+	var r Reload
+	if err != nil {
+		log.Printf("WARNING: config reload failed, keeping the previous config: %v", err)
+		// Wait for the next change instead of retrying the broken file.
+		w.stamps = stampFiles(slices.Collect(maps.Keys(w.stamps)))
+		r = Reload{Config: w.current, Err: err}
+	} else {
+		r = Reload{
+			Config:   loaded.Config,
+			Warnings: loaded.Warnings,
+			Restart:  restartRequired(w.current, loaded.Config),
+		}
+		w.current = loaded.Config
+		w.stamps = stampFiles(w.files(loaded))
+	}
+	subs := w.subs
+	w.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(r)
+	}
+	return true
+}
+
+// files returns the files to watch for a load of w.path.
+func (w *Watcher) files(loaded *LoadResult) []string {
+	if len(loaded.Files) == 0 {
+		// The config file did not exist; watch for it to be created.
+		return []string{w.path}
+	}
+	return loaded.Files
+}
+
+func stampFiles(paths []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, p := range paths {
+		var s fileStamp
+		if fi, err := os.Stat(p); err == nil {
+			s = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+		stamps[p] = s
+	}
+	return stamps
+}
+
+// restartRequired lists the settings that differ between prev and next but
+// are only read at startup: listeners, the scanner, storage, budgets,
+// plan quotas, pricing sync, serve and push, notification channels, the event buffer and auto thresholds.
+func restartRequired(prev, next Config) []string {
+	var changed []string
+	check := func(name string, a, b any) {
+		if !reflect.DeepEqual(a, b) {
+			changed = append(changed, name)
+		}
+	}
+	check("receiver", prev.Receiver, next.Receiver)
+	check("scanner", prev.Scanner, next.Scanner)
+	check("storage", prev.Storage, next.Storage)
+	check("budget", prev.Budget, next.Budget)
+	check("plan", prev.Plan, next.Plan)
+	check("pricing_sync", prev.PricingSync, next.PricingSync)
+	check("serve", prev.Serve, next.Serve)
+	check("push", prev.Push, next.Push)
+	check("alerts.notifications", prev.Alerts.Notifications, next.Alerts.Notifications)
+	check("alerts auto thresholds",
+		[]any{prev.Alerts.CostSurgeAuto, prev.Alerts.RunawayTokenVelocityAuto, prev.Alerts.AutoThresholdPercentile},
+		[]any{next.Alerts.CostSurgeAuto, next.Alerts.RunawayTokenVelocityAuto, next.Alerts.AutoThresholdPercentile})
+	check("display.event_buffer_size", prev.Display.EventBufferSize, next.Display.EventBufferSize)
+	check("display.event_buffer_eviction", prev.Display.EventBufferEviction, next.Display.EventBufferEviction)
+	return changed
+}
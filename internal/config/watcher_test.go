@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeConfig writes data to path and moves its mtime forward, so the
+// watcher sees a change even within the filesystem's timestamp resolution.
+func writeConfig(t *testing.T, path, data string, at time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	shared := filepath.Join(dir, "shared.toml")
+	base := time.Now().Add(-time.Hour)
+	writeConfig(t, path, "include = [\"shared.toml\"]\n[alerts]\nsession_cost_threshold = 5.0\n", base)
+	writeConfig(t, shared, "[display]\ncost_color_green_below = 1.0\n", base)
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if want := []string{path, shared}; !slices.Equal(loaded.Files, want) {
+		t.Fatalf("Files = %v, want %v", loaded.Files, want)
+	}
+
+	w := NewWatcher(path, loaded)
+	var got []Reload
+	w.Subscribe(func(r Reload) { got = append(got, r) })
+
+	if w.check() {
+		t.Fatal("unchanged files should not reload")
+	}
+
+	writeConfig(t, path, "include = [\"shared.toml\"]\n[alerts]\nsession_cost_threshold = 9.0\n[receiver]\ngrpc_port = 5317\n", base.Add(time.Minute))
+	if !w.check() || len(got) != 1 {
+		t.Fatalf("changed config should publish one reload, got %d", len(got))
+	}
+	if r := got[0]; r.Err != nil || r.Config.Alerts.SessionCostThreshold != 9 {
+		t.Errorf("reload = %+v, want session_cost_threshold 9", r)
+	}
+	if !slices.Equal(got[0].Restart, []string{"receiver"}) {
+		t.Errorf("Restart = %v, want [receiver]", got[0].Restart)
+	}
+
+	// Included files are watched too.
+	writeConfig(t, shared, "[display]\ncost_color_green_below = 2.0\n", base.Add(2*time.Minute))
+	if !w.check() || got[1].Config.Display.CostColorGreenBelow != 2 {
+		t.Fatalf("changed include should reload, got %+v", got)
+	}
+	if len(got[1].Restart) != 0 {
+		t.Errorf("Restart = %v, want none", got[1].Restart)
+	}
+
+	// A broken file is reported once and the previous config kept.
+	writeConfig(t, path, "[alerts]\nsession_cost_threshold = -1.0\n", base.Add(3*time.Minute))
+	if !w.check() || got[2].Err == nil {
+		t.Fatalf("invalid config should publish an error, got %+v", got)
+	}
+	if got[2].Config.Alerts.SessionCostThreshold != 9 {
+		t.Errorf("failed reload should carry the config in effect, got %v", got[2].Config.Alerts.SessionCostThreshold)
+	}
+	if w.check() {
+		t.Error("a broken file should not be reloaded again until it changes")
+	}
+}
+
+func TestWatcher_FileCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+
+	w := NewWatcher(path, loaded)
+	var got []Reload
+	w.Subscribe(func(r Reload) { got = append(got, r) })
+
+	writeConfig(t, path, "[display]\nrefresh_rate_ms = 250\n", time.Now())
+	if !w.check() || len(got) != 1 || got[0].Config.Display.RefreshRateMS != 250 {
+		t.Fatalf("creating the config file should reload it, got %+v", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Package control serves a local Unix socket for querying and stopping a
+// headless cc-top. A client connects, writes one command line (a name
+// followed by optional space-separated arguments), and reads back a single
+// JSON reply before the connection is closed.
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler answers one command. The result is encoded as JSON.
+type Handler func(args []string) (any, error)
+
+// Reply is the JSON document written back for every command.
+type Reply struct {
+	OK     bool            `json:"ok"`
+	Error  string          `json:"error,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// Server accepts control connections on a Unix socket.
+type Server struct {
+	path     string
+	handlers map[string]Handler
+
+	ln net.Listener
+	wg sync.WaitGroup
+}
+
+// NewServer creates a server that will listen on the socket at path. A
+// "help" command listing the registered commands is always available.
+func NewServer(path string) *Server {
+	s := &Server{path: path, handlers: make(map[string]Handler)}
+	s.handlers["help"] = func([]string) (any, error) {
+		names := make([]string, 0, len(s.handlers))
+		for name := range s.handlers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	return s
+}
+
+// Handle registers h for the named command. It must be called before Start.
+// Handlers must not call Stop, which waits for them to return.
+func (s *Server) Handle(name string, h Handler) {
+	s.handlers[name] = h
+}
+
+// Path returns the socket path.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Start removes a stale socket left by a previous run, listens on path
+// (readable by the owner only), and serves connections until ctx is
+// cancelled or Stop is called. It fails if another instance is already
+// answering on the socket.
+func (s *Server) Start(ctx context.Context) error {
+	if conn, err := net.DialTimeout("unix", s.path, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("control socket %s is in use by another cc-top", s.path)
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing stale control socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("listening on control socket: %w", err)
+	}
+	if err := os.Chmod(s.path, 0600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("securing control socket: %w", err)
+	}
+	s.ln = ln
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		s.Stop()
+	}()
+	return nil
+}
+
+// Stop closes the listener, waits for in-flight commands, and removes the
+// socket file. It is safe to call more than once.
+func (s *Server) Stop() {
+	if s.ln == nil {
+		return
+	}
+	if err := s.ln.Close(); err == nil {
+		s.wg.Wait()
+		_ = os.Remove(s.path)
+	}
+}
+
+// serve reads one command from conn and writes its reply.
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(s.dispatch(line))
+}
+
+// dispatch runs the handler named by the first field of line.
+func (s *Server) dispatch(line string) Reply {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Reply{Error: "empty command"}
+	}
+	h, ok := s.handlers[fields[0]]
+	if !ok {
+		return Reply{Error: fmt.Sprintf("unknown command %q (try help)", fields[0])}
+	}
+	result, err := h(fields[1:])
+	if err != nil {
+		return Reply{Error: err.Error()}
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return Reply{Error: fmt.Sprintf("encoding result: %v", err)}
+	}
+	return Reply{OK: true, Result: raw}
+}
+
+// Send connects to the socket at path, issues command, and returns the
+// reply.
+func Send(path, command string) (Reply, error) {
+	var reply Reply
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return reply, fmt.Errorf("connecting to control socket: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return reply, fmt.Errorf("sending command: %w", err)
+	}
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return reply, fmt.Errorf("reading reply: %w", err)
+	}
+	return reply, nil
+}
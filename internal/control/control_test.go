@@ -0,0 +1,93 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func startTestServer(t *testing.T) *Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cc-top.sock")
+	s := NewServer(path)
+	s.Handle("echo", func(args []string) (any, error) {
+		return map[string]any{"args": args}, nil
+	})
+	s.Handle("fail", func([]string) (any, error) {
+		return nil, errors.New("boom")
+	})
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestServer_Commands(t *testing.T) {
+	s := startTestServer(t)
+
+	reply, err := Send(s.Path(), "echo a b")
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if !reply.OK {
+		t.Fatalf("expected ok reply, got %+v", reply)
+	}
+	var got struct{ Args []string }
+	if err := json.Unmarshal(reply.Result, &got); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if strings.Join(got.Args, ",") != "a,b" {
+		t.Errorf("args: got %v", got.Args)
+	}
+
+	reply, _ = Send(s.Path(), "fail")
+	if reply.OK || reply.Error != "boom" {
+		t.Errorf("handler error should be reported, got %+v", reply)
+	}
+
+	reply, _ = Send(s.Path(), "nope")
+	if reply.OK || !strings.Contains(reply.Error, "unknown command") {
+		t.Errorf("unknown command should be rejected, got %+v", reply)
+	}
+
+	reply, _ = Send(s.Path(), "help")
+	if !reply.OK || !strings.Contains(string(reply.Result), `"echo"`) {
+		t.Errorf("help should list commands, got %+v", reply)
+	}
+}
+
+func TestServer_SocketLifecycle(t *testing.T) {
+	s := startTestServer(t)
+
+	info, err := os.Stat(s.Path())
+	if err != nil {
+		t.Fatalf("socket should exist: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode: got %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := NewServer(s.Path()).Start(context.Background()); err == nil {
+		t.Error("second server on a live socket should fail")
+	}
+
+	s.Stop()
+	if _, err := os.Stat(s.Path()); !os.IsNotExist(err) {
+		t.Errorf("socket should be removed on Stop, stat err = %v", err)
+	}
+
+	// A stale socket file from a crashed run is replaced.
+	if err := os.WriteFile(s.Path(), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	next := NewServer(s.Path())
+	if err := next.Start(context.Background()); err != nil {
+		t.Fatalf("Start over stale socket: %v", err)
+	}
+	next.Stop()
+}
@@ -10,9 +10,16 @@
 // Fallback: Timing heuristic. When a new PID appears in the process scanner
 // and a new session.id starts sending within 10 seconds, they are assumed
 // to match.
+//
+// Duplicate exporters: when a second live PID is fingerprinted to a session
+// that is already correlated (e.g. a forked subprocess inherited the
+// session's environment), the session keeps its first PID and the conflict
+// is reported by Duplicates rather than silently merged.
 package correlator
 
 import (
+	"slices"
+	"sort"
 	"sync"
 	"time"
 )
@@ -56,6 +63,20 @@ type Correlator struct {
 	// newSessions tracks recently seen session IDs with their first-seen
 	// timestamp, used for the timing heuristic fallback.
 	newSessions map[string]time.Time
+
+	// duplicates maps a session ID to the other PIDs found exporting under
+	// it while its correlated PID was still running.
+	duplicates map[string][]int
+}
+
+// Duplicate is a session ID that more than one process exports under.
+type Duplicate struct {
+	SessionID string
+	// PID is the process the session is attributed to: the first one
+	// correlated to it.
+	PID int
+	// Others are the other processes sending data with the same session ID.
+	Others []int
 }
 
 // NewCorrelator creates a Correlator with the given port mapper and
@@ -69,6 +90,7 @@ func NewCorrelator(portMapper PortMapper, receiverPort int) *Correlator {
 		sessionToPID:  make(map[string]int),
 		newPIDs:       make(map[int]time.Time),
 		newSessions:   make(map[string]time.Time),
+		duplicates:    make(map[string][]int),
 	}
 }
 
@@ -119,6 +141,21 @@ func (c *Correlator) Correlate(activePIDs []int) {
 
 	now := time.Now()
 
+	// Forget duplicate exporters that have exited. When the attributed
+	// process exits first, the session passes to the oldest remaining one.
+	for sid, others := range c.duplicates {
+		live := slices.DeleteFunc(others, func(pid int) bool { return !slices.Contains(activePIDs, pid) })
+		if owner := c.sessionToPID[sid]; !slices.Contains(activePIDs, owner) && len(live) > 0 {
+			c.sessionToPID[sid] = live[0]
+			live = live[1:]
+		}
+		if len(live) == 0 {
+			delete(c.duplicates, sid)
+		} else {
+			c.duplicates[sid] = live
+		}
+	}
+
 	// Phase 1: Port fingerprinting.
 	for _, pid := range activePIDs {
 		if _, already := c.pidToSession[pid]; already {
@@ -140,6 +177,16 @@ func (c *Correlator) Correlate(activePIDs []int) {
 			// port matches our receiver port.
 			if remotePort == c.receiverPort {
 				if sessionID, ok := c.portToSession[localPort]; ok {
+					if owner, taken := c.sessionToPID[sessionID]; taken && owner != pid && slices.Contains(activePIDs, owner) {
+						// Another live process already exports this session:
+						// keep the data attributed to it and flag the conflict.
+						c.pidToSession[pid] = sessionID
+						if !slices.Contains(c.duplicates[sessionID], pid) {
+							c.duplicates[sessionID] = append(c.duplicates[sessionID], pid)
+						}
+						delete(c.newPIDs, pid)
+						break
+					}
 					c.pidToSession[pid] = sessionID
 					c.sessionToPID[sessionID] = pid
 					delete(c.newPIDs, pid)
@@ -219,3 +266,22 @@ func (c *Correlator) GetPIDForSession(sessionID string) int {
 	defer c.mu.RUnlock()
 	return c.sessionToPID[sessionID]
 }
+
+// Duplicates returns the sessions exported by more than one process, ordered
+// by session ID. A duplicate is dropped once its extra processes exit, see
+// Correlate.
+func (c *Correlator) Duplicates() []Duplicate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]Duplicate, 0, len(c.duplicates))
+	for sid, others := range c.duplicates {
+		result = append(result, Duplicate{
+			SessionID: sid,
+			PID:       c.sessionToPID[sid],
+			Others:    slices.Sorted(slices.Values(others)),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SessionID < result[j].SessionID })
+	return result
+}
@@ -232,3 +232,75 @@ func TestCorrelator_ConcurrentAccess(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestCorrelator_DuplicateExporter(t *testing.T) {
+	pm := newMockPortMapper()
+	c := NewCorrelator(pm, 4317)
+
+	// PID 100 exports session "sess-dup"; its forked child 200 inherited
+	// the environment and exports under the same session ID.
+	pm.SetPorts(100, [][2]int{{51000, 4317}})
+	pm.SetPorts(200, [][2]int{{52000, 4317}})
+	c.RecordConnection(51000, "sess-dup")
+	c.Correlate([]int{100})
+	c.RecordConnection(52000, "sess-dup")
+	c.Correlate([]int{100, 200})
+
+	if pid := c.GetPIDForSession("sess-dup"); pid != 100 {
+		t.Errorf("GetPIDForSession = %d, want the first exporter 100", pid)
+	}
+	dups := c.Duplicates()
+	if len(dups) != 1 || dups[0].SessionID != "sess-dup" || dups[0].PID != 100 ||
+		len(dups[0].Others) != 1 || dups[0].Others[0] != 200 {
+		t.Fatalf("Duplicates() = %+v, want sess-dup owned by 100 with 200", dups)
+	}
+
+	// Once the child exits the conflict is gone.
+	c.Correlate([]int{100})
+	if dups := c.Duplicates(); len(dups) != 0 {
+		t.Errorf("Duplicates() after the child exited = %+v, want none", dups)
+	}
+}
+
+func TestCorrelator_DuplicateExporterOwnerExits(t *testing.T) {
+	pm := newMockPortMapper()
+	c := NewCorrelator(pm, 4317)
+
+	pm.SetPorts(100, [][2]int{{51000, 4317}})
+	pm.SetPorts(200, [][2]int{{52000, 4317}})
+	c.RecordConnection(51000, "sess-dup")
+	c.Correlate([]int{100})
+	c.RecordConnection(52000, "sess-dup")
+	c.Correlate([]int{100, 200})
+
+	// The original exits: the session passes to the remaining exporter.
+	c.Correlate([]int{200})
+	if pid := c.GetPIDForSession("sess-dup"); pid != 200 {
+		t.Errorf("GetPIDForSession = %d, want 200 after 100 exited", pid)
+	}
+	if dups := c.Duplicates(); len(dups) != 0 {
+		t.Errorf("Duplicates() = %+v, want none", dups)
+	}
+}
+
+func TestCorrelator_SessionHandoffIsNotDuplicate(t *testing.T) {
+	pm := newMockPortMapper()
+	c := NewCorrelator(pm, 4317)
+
+	// A resumed session in a new process after the old one exited is not a
+	// conflict.
+	pm.SetPorts(100, [][2]int{{51000, 4317}})
+	c.RecordConnection(51000, "sess-resume")
+	c.Correlate([]int{100})
+
+	pm.SetPorts(300, [][2]int{{53000, 4317}})
+	c.RecordConnection(53000, "sess-resume")
+	c.Correlate([]int{300})
+
+	if pid := c.GetPIDForSession("sess-resume"); pid != 300 {
+		t.Errorf("GetPIDForSession = %d, want 300", pid)
+	}
+	if dups := c.Duplicates(); len(dups) != 0 {
+		t.Errorf("Duplicates() = %+v, want none", dups)
+	}
+}
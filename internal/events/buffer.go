@@ -11,6 +11,8 @@ type RingBuffer struct {
 	cap   int
 	head  int // index of the oldest element
 	count int // number of elements currently stored
+
+	onEvict func(FormattedEvent)
 }
 
 // NewRingBuffer creates a new RingBuffer with the given capacity.
@@ -25,22 +27,40 @@ func NewRingBuffer(capacity int) *RingBuffer {
 	}
 }
 
+// OnEvict registers fn to receive each event the buffer evicts, e.g. to
+// spill it to storage. fn is called outside the buffer's lock.
+func (rb *RingBuffer) OnEvict(fn func(FormattedEvent)) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.onEvict = fn
+}
+
 // Add inserts an event into the buffer. If the buffer is full, the oldest
-// event is overwritten.
+// event is overwritten and passed to the OnEvict callback.
 func (rb *RingBuffer) Add(e FormattedEvent) {
 	rb.mu.Lock()
-	defer rb.mu.Unlock()
 
 	// Calculate write position.
 	writePos := (rb.head + rb.count) % rb.cap
+	var evicted *FormattedEvent
 	if rb.count == rb.cap {
 		// Buffer is full; overwrite oldest and advance head.
+		if rb.onEvict != nil {
+			old := rb.items[rb.head]
+			evicted = &old
+		}
 		rb.items[rb.head] = e
 		rb.head = (rb.head + 1) % rb.cap
 	} else {
 		rb.items[writePos] = e
 		rb.count++
 	}
+	onEvict := rb.onEvict
+	rb.mu.Unlock()
+
+	if evicted != nil {
+		onEvict(*evicted)
+	}
 }
 
 // ListAll returns all events in chronological order (oldest first).
@@ -268,3 +268,19 @@ func TestEventBuffer_ZeroCapacity(t *testing.T) {
 		t.Errorf("expected len=1, got %d", buf.Len())
 	}
 }
+
+func TestEventBuffer_OnEvict(t *testing.T) {
+	buf := NewRingBuffer(2)
+	var evicted []string
+	buf.OnEvict(func(e FormattedEvent) { evicted = append(evicted, e.Formatted) })
+
+	for i := 1; i <= 5; i++ {
+		buf.Add(makeEvent("s1", "api_request", fmt.Sprintf("event-%d", i)))
+	}
+	if want := []string{"event-1", "event-2", "event-3"}; fmt.Sprint(evicted) != fmt.Sprint(want) {
+		t.Errorf("evicted = %v, want %v", evicted, want)
+	}
+	if all := buf.ListAll(); len(all) != 2 || all[0].Formatted != "event-4" {
+		t.Errorf("buffer = %+v, want event-4, event-5", all)
+	}
+}
@@ -48,12 +48,20 @@ func FormatEvent(sessionID string, e state.Event) FormattedEvent {
 		fe.Formatted = formatAPIRequest(shortSession, e)
 		trueVal := true
 		fe.Success = &trueVal
+		// Each request reports its own cost, which is everything the session
+		// spent since its previous request.
+		if cost, err := strconv.ParseFloat(attrStr(e, "cost_usd"), 64); err == nil {
+			fe.DeltaCost = &cost
+		}
 	case "claude_code.api_error":
 		fe.Formatted = formatAPIError(shortSession, e)
 		falseVal := false
 		fe.Success = &falseVal
 	case "claude_code.tool_decision":
 		fe.Formatted = formatToolDecision(shortSession, e, &fe)
+	case state.AnnotationEventName:
+		fe.EventType = "annotation"
+		fe.Formatted = formatAnnotation(shortSession, e)
 	default:
 		fe.Formatted = fmt.Sprintf("[%s] %s", shortSession, e.Name)
 	}
@@ -72,6 +80,15 @@ func formatUserPrompt(session string, e state.Event) string {
 	return fmt.Sprintf("[%s] Prompt (%s chars)", session, length)
 }
 
+// formatAnnotation formats: [session] Note: text (source)
+func formatAnnotation(session string, e state.Event) string {
+	text := truncatePrompt(attrStr(e, "text"), 120)
+	if source := attrStr(e, "source"); source != "" {
+		return fmt.Sprintf("[%s] Note: %s (%s)", session, text, source)
+	}
+	return fmt.Sprintf("[%s] Note: %s", session, text)
+}
+
 // formatToolResult formats tool results with success/failure indicators.
 func formatToolResult(session string, e state.Event, fe *FormattedEvent) string {
 	toolName := attrStr(e, "tool_name")
@@ -121,6 +121,9 @@ func TestEventFormat_APIRequest(t *testing.T) {
 	if fe.EventType != "api_request" {
 		t.Errorf("expected EventType='api_request', got %q", fe.EventType)
 	}
+	if fe.DeltaCost == nil || *fe.DeltaCost != 0.03 {
+		t.Errorf("expected DeltaCost=0.03, got %v", fe.DeltaCost)
+	}
 }
 
 func TestEventFormat_APIError(t *testing.T) {
@@ -143,6 +146,9 @@ func TestEventFormat_APIError(t *testing.T) {
 	if fe.Success == nil || *fe.Success {
 		t.Error("expected Success=false for api_error")
 	}
+	if fe.DeltaCost != nil {
+		t.Errorf("only api_request events carry a cost, got %v", *fe.DeltaCost)
+	}
 }
 
 func TestEventFormat_ToolDecision(t *testing.T) {
@@ -361,3 +367,24 @@ func TestFormatCost(t *testing.T) {
 		})
 	}
 }
+
+func TestEventFormat_Annotation(t *testing.T) {
+	e := state.Event{
+		Name: state.AnnotationEventName,
+		Attributes: map[string]string{
+			"text":   "tests passing",
+			"source": "PostToolUse",
+		},
+		Timestamp: time.Now(),
+	}
+
+	fe := FormatEvent("sess-abc", e)
+
+	expected := "[sess-abc] Note: tests passing (PostToolUse)"
+	if fe.Formatted != expected {
+		t.Errorf("expected %q, got %q", expected, fe.Formatted)
+	}
+	if fe.EventType != "annotation" {
+		t.Errorf("expected EventType='annotation', got %q", fe.EventType)
+	}
+}
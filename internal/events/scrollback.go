@@ -0,0 +1,78 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// spillCacheTTL is how long Scrollback reuses a query of the spill store.
+// The Events panel asks on every refresh; evictions only append behind
+// the buffer, so a slightly stale tail of the scrollback is fine.
+const spillCacheTTL = 2 * time.Second
+
+// SpillStore keeps the events a RingBuffer evicts.
+type SpillStore interface {
+	SpillEvent(e FormattedEvent)
+	// QuerySpilledEvents returns the newest limit spilled events, of
+	// sessionID or of all sessions when it is empty, oldest first.
+	QuerySpilledEvents(sessionID string, limit int) []FormattedEvent
+}
+
+// Scrollback serves the recent events of a RingBuffer and, behind them, the
+// events it evicted to a SpillStore, so listings can reach past the
+// buffer's capacity. It is safe for concurrent use.
+type Scrollback struct {
+	buf   *RingBuffer
+	store SpillStore
+
+	mu    sync.Mutex
+	cache map[string]spillQuery // by session ID; "" is all sessions
+}
+
+type spillQuery struct {
+	at     time.Time
+	limit  int
+	events []FormattedEvent
+}
+
+// NewScrollback makes buf spill the events it evicts to store.
+func NewScrollback(buf *RingBuffer, store SpillStore) *Scrollback {
+	buf.OnEvict(store.SpillEvent)
+	return &Scrollback{buf: buf, store: store, cache: make(map[string]spillQuery)}
+}
+
+// Recent returns up to limit of the newest events, oldest first.
+func (s *Scrollback) Recent(limit int) []FormattedEvent {
+	return s.recent("", s.buf.ListAll(), limit)
+}
+
+// RecentForSession returns up to limit of the newest events of sessionID,
+// oldest first.
+func (s *Scrollback) RecentForSession(sessionID string, limit int) []FormattedEvent {
+	return s.recent(sessionID, s.buf.ListBySession(sessionID), limit)
+}
+
+func (s *Scrollback) recent(sessionID string, buffered []FormattedEvent, limit int) []FormattedEvent {
+	if len(buffered) >= limit {
+		return buffered[len(buffered)-limit:]
+	}
+	spilled := s.spilled(sessionID, limit-len(buffered), time.Now())
+	result := make([]FormattedEvent, 0, len(spilled)+len(buffered))
+	return append(append(result, spilled...), buffered...)
+}
+
+// spilled returns up to n of the newest spilled events of sessionID.
+func (s *Scrollback) spilled(sessionID string, n int, now time.Time) []FormattedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.cache[sessionID]
+	if !ok || now.Sub(q.at) >= spillCacheTTL || q.limit < n {
+		q = spillQuery{at: now, limit: n, events: s.store.QuerySpilledEvents(sessionID, n)}
+		s.cache[sessionID] = q
+	}
+	if len(q.events) > n {
+		return q.events[len(q.events)-n:]
+	}
+	return q.events
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+)
+
+// memorySpill is an in-memory SpillStore.
+type memorySpill struct {
+	events  []FormattedEvent
+	queries int
+}
+
+func (m *memorySpill) SpillEvent(e FormattedEvent) { m.events = append(m.events, e) }
+
+func (m *memorySpill) QuerySpilledEvents(sessionID string, limit int) []FormattedEvent {
+	m.queries++
+	var result []FormattedEvent
+	for _, e := range m.events {
+		if sessionID == "" || e.SessionID == sessionID {
+			result = append(result, e)
+		}
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+func TestScrollback(t *testing.T) {
+	buf := NewRingBuffer(3)
+	spill := &memorySpill{}
+	sb := NewScrollback(buf, spill)
+
+	for i := 1; i <= 6; i++ {
+		session := "s1"
+		if i%2 == 0 {
+			session = "s2"
+		}
+		buf.Add(makeEvent(session, "api_request", fmt.Sprintf("event-%d", i)))
+	}
+	if len(spill.events) != 3 {
+		t.Fatalf("spilled %d events, want 3", len(spill.events))
+	}
+
+	formatted := func(evts []FormattedEvent) string {
+		var s []string
+		for _, e := range evts {
+			s = append(s, e.Formatted)
+		}
+		return fmt.Sprint(s)
+	}
+	if got := formatted(sb.Recent(2)); got != "[event-5 event-6]" {
+		t.Errorf("Recent(2) = %s, want only buffered events", got)
+	}
+	if got := formatted(sb.Recent(5)); got != "[event-2 event-3 event-4 event-5 event-6]" {
+		t.Errorf("Recent(5) = %s", got)
+	}
+	if got := formatted(sb.RecentForSession("s1", 10)); got != "[event-1 event-3 event-5]" {
+		t.Errorf("RecentForSession(s1) = %s", got)
+	}
+
+	// Within the cache TTL, a smaller limit reuses the last query.
+	queries := spill.queries
+	if got := formatted(sb.Recent(4)); got != "[event-3 event-4 event-5 event-6]" {
+		t.Errorf("Recent(4) = %s", got)
+	}
+	if spill.queries != queries {
+		t.Errorf("Recent(4) queried the store again")
+	}
+}
@@ -5,9 +5,10 @@ import "time"
 // FormattedEvent holds a display-ready event with metadata.
 type FormattedEvent struct {
 	SessionID     string
-	EventType     string            // user_prompt, tool_result, api_request, api_error, tool_decision
+	EventType     string            // user_prompt, tool_result, api_request, api_error, tool_decision, annotation
 	Formatted     string            // display-ready string
 	Timestamp     time.Time
 	Success       *bool             // nil if not applicable
+	DeltaCost     *float64          // api_request only: what it added to the session's spend since its previous api_request
 	RawAttributes map[string]string // deep copy of original event attributes
 }
@@ -0,0 +1,275 @@
+// Package gitlog links git commits to Claude Code sessions by running
+// `git log` in each session's working directory over the session's active
+// window. It is opt-in (scanner.git_commits) because it shells out to git.
+package gitlog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Commit is a single commit made during a session.
+type Commit struct {
+	Hash    string
+	Subject string
+	Time    time.Time
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 8 {
+		return c.Hash[:8]
+	}
+	return c.Hash
+}
+
+// Runner executes git with args in dir and returns its stdout. Tests
+// substitute a fake.
+type Runner func(ctx context.Context, dir string, args ...string) ([]byte, error)
+
+// ExecRunner runs the git binary found on PATH.
+func ExecRunner(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	return cmd.Output()
+}
+
+// fieldSep separates fields in the git log output format.
+const fieldSep = "\x1f"
+
+// Log returns commits in dir with a committer date in [since, until],
+// newest first.
+func Log(ctx context.Context, run Runner, dir string, since, until time.Time) ([]Commit, error) {
+	out, err := run(ctx, dir, "log",
+		"--since="+since.UTC().Format(time.RFC3339),
+		"--until="+until.UTC().Format(time.RFC3339),
+		"--format=%H"+fieldSep+"%ct"+fieldSep+"%s")
+	if err != nil {
+		return nil, fmt.Errorf("git log in %s: %w", dir, err)
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, fieldSep, 3)
+		if len(parts) != 3 {
+			continue
+		}
+		secs, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		ts := time.Unix(secs, 0)
+		// git's --since/--until are approximate; enforce the window exactly.
+		if ts.Before(since.Truncate(time.Second)) || ts.After(until) {
+			continue
+		}
+		commits = append(commits, Commit{Hash: parts[0], Subject: parts[2], Time: ts})
+	}
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Time.After(commits[j].Time) })
+	return commits, nil
+}
+
+// DirFunc resolves the directory to run git in for a session, or "" if
+// unknown.
+type DirFunc func(s state.SessionData) string
+
+// Tracker periodically refreshes the commits for each session and caches
+// them for the TUI. Sessions sharing a directory over overlapping windows
+// each see the same commits. All methods are safe for concurrent use.
+type Tracker struct {
+	run     Runner
+	dirFor  DirFunc
+	timeout time.Duration
+
+	mu      sync.RWMutex
+	commits map[string][]Commit // session ID -> commits, newest first
+	final   map[string]bool     // exited sessions whose window is closed
+}
+
+// Option configures a Tracker.
+type Option func(*Tracker)
+
+// WithRunner replaces the git runner (default ExecRunner).
+func WithRunner(run Runner) Option {
+	return func(t *Tracker) { t.run = run }
+}
+
+// NewTracker creates a Tracker that resolves session directories with dirFor.
+func NewTracker(dirFor DirFunc, opts ...Option) *Tracker {
+	t := &Tracker{
+		run:     ExecRunner,
+		dirFor:  dirFor,
+		timeout: 5 * time.Second,
+		commits: make(map[string][]Commit),
+		final:   make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Refresh runs git log for every session with a known directory. A session's
+// window runs from its start to its last event (or now while still active).
+// Exited sessions are looked up once more and then left alone.
+func (t *Tracker) Refresh(ctx context.Context, sessions []state.SessionData, now time.Time) {
+	for _, s := range sessions {
+		t.mu.RLock()
+		done := t.final[s.SessionID]
+		t.mu.RUnlock()
+		if done || s.StartedAt.IsZero() {
+			continue
+		}
+		dir := t.dirFor(s)
+		if dir == "" {
+			continue
+		}
+
+		until := now
+		if s.Exited && !s.LastEventAt.IsZero() {
+			until = s.LastEventAt
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, t.timeout)
+		commits, err := Log(runCtx, t.run, dir, s.StartedAt, until)
+		cancel()
+		if err != nil {
+			// Not a git repository, or git missing: nothing to link.
+			continue
+		}
+
+		t.mu.Lock()
+		t.commits[s.SessionID] = commits
+		if s.Exited {
+			t.final[s.SessionID] = true
+		}
+		t.mu.Unlock()
+	}
+}
+
+// Start refreshes from store every interval until ctx is cancelled.
+func (t *Tracker) Start(ctx context.Context, store state.Store, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		t.Refresh(ctx, store.ListSessions(), time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.Refresh(ctx, store.ListSessions(), time.Now())
+			}
+		}
+	}()
+}
+
+// Commits returns the commits linked to sessionID, newest first.
+func (t *Tracker) Commits(sessionID string) []Commit {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]Commit(nil), t.commits[sessionID]...)
+}
+
+// ExpandHome turns a leading "~" (as shown by the scanner) back into the
+// user's home directory.
+func ExpandHome(path string) string {
+	return pathutil.ExpandHome(path)
+}
+
+// RepoRoot returns the nearest directory at or above dir that contains a
+// .git entry (a directory, or a file for worktrees and submodules), or ""
+// when dir is not inside a repository. It only looks at the filesystem.
+func RepoRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir = filepath.Clean(dir)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Head returns the name of the repository dir is in and its checked-out
+// branch, read from .git/HEAD without running git. A detached HEAD gives
+// the abbreviated commit hash. Both are empty outside a repository. For a
+// linked worktree the repository is named after the main checkout.
+func Head(dir string) (repo, branch string) {
+	root := RepoRoot(dir)
+	if root == "" {
+		return "", ""
+	}
+	repo = filepath.Base(root)
+	gitDir := filepath.Join(root, ".git")
+	if data, err := os.ReadFile(gitDir); err == nil {
+		// A worktree or submodule: ".git" is a file pointing at the git dir.
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+		if !ok {
+			return repo, ""
+		}
+		gitDir = strings.TrimSpace(target)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(root, gitDir)
+		}
+		if main, _, ok := strings.Cut(filepath.ToSlash(gitDir), "/.git/worktrees/"); ok {
+			repo = filepath.Base(filepath.FromSlash(main))
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return repo, ""
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		return repo, strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/")
+	}
+	return repo, Commit{Hash: head}.ShortHash()
+}
+
+// ProjectDir returns the project dir belongs to: the subproject of its
+// repository given by the workspace globs (relative to the repository root,
+// e.g. "packages/*") or else the first depth path components below the
+// root, the repository root when neither applies, or dir itself outside a
+// repository.
+func ProjectDir(dir string, depth int, workspaces []string) string {
+	root := RepoRoot(dir)
+	if root == "" {
+		return dir
+	}
+	rel, err := filepath.Rel(root, filepath.Clean(dir))
+	if err != nil || rel == "." {
+		return root
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, w := range workspaces {
+		n := strings.Count(w, "/") + 1
+		if n > len(parts) {
+			continue
+		}
+		if ok, _ := path.Match(w, strings.Join(parts[:n], "/")); ok {
+			return filepath.Join(root, filepath.Join(parts[:n]...))
+		}
+	}
+	if depth > 0 {
+		return filepath.Join(root, filepath.Join(parts[:min(depth, len(parts))]...))
+	}
+	return root
+}
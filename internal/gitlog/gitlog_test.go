@@ -0,0 +1,197 @@
+package gitlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func logLine(hash string, ts time.Time, subject string) string {
+	return fmt.Sprintf("%s\x1f%d\x1f%s", hash, ts.Unix(), subject)
+}
+
+func TestLog_ParsesAndFiltersWindow(t *testing.T) {
+	since := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	until := since.Add(time.Hour)
+
+	var gotDir string
+	var gotArgs []string
+	run := func(_ context.Context, dir string, args ...string) ([]byte, error) {
+		gotDir, gotArgs = dir, args
+		return []byte(strings.Join([]string{
+			logLine("aaaaaaaaaaaa", since.Add(10*time.Minute), "first"),
+			logLine("bbbbbbbbbbbb", since.Add(50*time.Minute), "second: with\x1fseparator"),
+			logLine("cccccccccccc", since.Add(-time.Minute), "before window"),
+			"garbage",
+		}, "\n") + "\n"), nil
+	}
+
+	commits, err := Log(context.Background(), run, "/repo", since, until)
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if gotDir != "/repo" || gotArgs[0] != "log" {
+		t.Errorf("unexpected invocation: dir=%q args=%v", gotDir, gotArgs)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits in window, got %d: %+v", len(commits), commits)
+	}
+	if commits[0].Hash != "bbbbbbbbbbbb" || commits[1].Hash != "aaaaaaaaaaaa" {
+		t.Errorf("expected newest first, got %s, %s", commits[0].Hash, commits[1].Hash)
+	}
+	if commits[0].Subject != "second: with\x1fseparator" {
+		t.Errorf("subject not preserved: %q", commits[0].Subject)
+	}
+	if commits[1].ShortHash() != "aaaaaaaa" {
+		t.Errorf("ShortHash: got %q", commits[1].ShortHash())
+	}
+}
+
+func TestTracker_RefreshLinksCommitsToSessions(t *testing.T) {
+	start := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	now := start.Add(2 * time.Hour)
+
+	calls := map[string]int{}
+	run := func(_ context.Context, dir string, _ ...string) ([]byte, error) {
+		calls[dir]++
+		switch dir {
+		case "/repo":
+			return []byte(logLine("abc123def456", start.Add(30*time.Minute), "fix parser") + "\n"), nil
+		default:
+			return nil, errors.New("not a git repository")
+		}
+	}
+	dirs := map[string]string{"live": "/repo", "done": "/repo", "plain": "/tmp", "nodir": ""}
+	tr := NewTracker(func(s state.SessionData) string { return dirs[s.SessionID] }, WithRunner(run))
+
+	sessions := []state.SessionData{
+		{SessionID: "live", StartedAt: start},
+		{SessionID: "done", StartedAt: start, Exited: true, LastEventAt: start.Add(time.Hour)},
+		{SessionID: "plain", StartedAt: start},
+		{SessionID: "nodir", StartedAt: start},
+	}
+	tr.Refresh(context.Background(), sessions, now)
+	tr.Refresh(context.Background(), sessions, now)
+
+	for _, id := range []string{"live", "done"} {
+		got := tr.Commits(id)
+		if len(got) != 1 || got[0].Subject != "fix parser" {
+			t.Errorf("session %s: expected linked commit, got %+v", id, got)
+		}
+	}
+	if got := tr.Commits("plain"); len(got) != 0 {
+		t.Errorf("non-repo session should have no commits, got %+v", got)
+	}
+	// Two live refreshes plus one final lookup for the exited session.
+	if calls["/repo"] != 3 {
+		t.Errorf("expected 3 git runs in /repo, got %d", calls["/repo"])
+	}
+	if _, ok := calls[""]; ok {
+		t.Error("git should not run for sessions without a directory")
+	}
+}
+
+func TestRepoRoot(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	sub := filepath.Join(repo, "cmd", "tool")
+	plain := filepath.Join(tmp, "plain")
+	for _, d := range []string{filepath.Join(repo, ".git"), sub, plain} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A worktree marks its root with a .git file.
+	worktree := filepath.Join(tmp, "wt")
+	if err := os.MkdirAll(worktree, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+repo+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for dir, want := range map[string]string{
+		repo:     repo,
+		sub:      repo,
+		worktree: worktree,
+		plain:    "",
+		"":       "",
+	} {
+		if got := RepoRoot(dir); got != want {
+			t.Errorf("RepoRoot(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestHead(t *testing.T) {
+	tmp := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := filepath.Join(tmp, "cc-top")
+	write(filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/feature/graphs\n")
+	detached := filepath.Join(tmp, "detached")
+	write(filepath.Join(detached, ".git", "HEAD"), "0123456789abcdef0123456789abcdef01234567\n")
+	// A linked worktree of repo, checked out on its own branch.
+	worktree := filepath.Join(tmp, "wt")
+	write(filepath.Join(repo, ".git", "worktrees", "wt", "HEAD"), "ref: refs/heads/fix\n")
+	write(filepath.Join(worktree, ".git"), "gitdir: "+filepath.Join(repo, ".git", "worktrees", "wt")+"\n")
+
+	tests := []struct{ dir, repo, branch string }{
+		{filepath.Join(repo, "internal"), "cc-top", "feature/graphs"},
+		{detached, "detached", "01234567"},
+		{worktree, "cc-top", "fix"},
+		{tmp, "", ""},
+	}
+	for _, tt := range tests {
+		if repo, branch := Head(tt.dir); repo != tt.repo || branch != tt.branch {
+			t.Errorf("Head(%q) = %q, %q, want %q, %q", tt.dir, repo, branch, tt.repo, tt.branch)
+		}
+	}
+}
+
+func TestProjectDir(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	pkg := filepath.Join(repo, "packages", "web", "src")
+	svc := filepath.Join(repo, "services", "billing", "api", "handlers")
+	for _, d := range []string{filepath.Join(repo, ".git"), pkg, svc} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	workspaces := []string{"packages/*", "services/*/api"}
+
+	for _, tt := range []struct {
+		dir        string
+		depth      int
+		workspaces []string
+		want       string
+	}{
+		{pkg, 0, nil, repo},
+		{pkg, 0, workspaces, filepath.Join(repo, "packages", "web")},
+		{svc, 0, workspaces, filepath.Join(repo, "services", "billing", "api")},
+		{svc, 1, nil, filepath.Join(repo, "services")},
+		{filepath.Join(repo, "packages"), 2, nil, filepath.Join(repo, "packages")},
+		{filepath.Join(repo, "services", "billing"), 1, workspaces, filepath.Join(repo, "services")},
+		{repo, 2, workspaces, repo},
+		{tmp, 2, workspaces, tmp},
+	} {
+		if got := ProjectDir(tt.dir, tt.depth, tt.workspaces); got != tt.want {
+			t.Errorf("ProjectDir(%q, %d, %v) = %q, want %q", tt.dir, tt.depth, tt.workspaces, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package ics writes iCalendar (RFC 5545) files, so cc-top history can be
+// imported into or subscribed to from a calendar.
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest content line before it must be folded.
+const maxLineOctets = 75
+
+// Event is a calendar event. All-day events use the dates of Start and End;
+// End is exclusive, and a zero End means one day for all-day events and a
+// point in time otherwise.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start, End  time.Time
+	AllDay      bool
+}
+
+// Write writes events as a calendar named name. now stamps every event.
+func Write(w io.Writer, name string, events []Event, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) { writeFolded(bw, s) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//cc-top//cc-top//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escape(name))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escape(e.UID))
+		line("DTSTAMP:" + stamp)
+		if e.AllDay {
+			end := e.End
+			if end.IsZero() {
+				end = e.Start.AddDate(0, 0, 1)
+			}
+			line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + end.Format("20060102"))
+		} else {
+			line("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+			if !e.End.IsZero() {
+				line("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+			}
+		}
+		line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escape(e.Description))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line ending in CRLF, folding it into
+// continuation lines of at most maxLineOctets without splitting a UTF-8
+// character.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	_, _ = w.WriteString(s + "\r\n")
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
@@ -0,0 +1,61 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWrite(t *testing.T) {
+	day := time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local)
+	fired := time.Date(2026, 3, 16, 14, 5, 0, 0, time.UTC)
+	events := []Event{
+		{UID: "day-2026-03-16@cc-top", Summary: "cc-top: $12.50", AllDay: true, Start: day},
+		{UID: "alert-7@cc-top", Summary: "CostSurge", Description: "rate $4.20/hr, above $2.00\nsession s1; note", Start: fired, End: fired.Add(15 * time.Minute)},
+	}
+	var sb strings.Builder
+	if err := Write(&sb, "cc-top", events, fired); err != nil {
+		t.Fatal(err)
+	}
+	got := sb.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"DTSTART;VALUE=DATE:20260316\r\nDTEND;VALUE=DATE:20260317\r\n",
+		"DTSTART:20260316T140500Z\r\nDTEND:20260316T142000Z\r\n",
+		`DESCRIPTION:rate $4.20/hr\, above $2.00\nsession s1\; note`,
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar should contain %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("got %d events, want 2", n)
+	}
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	var sb strings.Builder
+	long := strings.Repeat("é", 100)
+	if err := Write(&sb, "cc-top", []Event{{UID: "x", Summary: long, Start: time.Now()}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var unfolded strings.Builder
+	for _, l := range strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+		if !utf8.ValidString(l) {
+			t.Errorf("folding split a character: %q", l)
+		}
+		if strings.HasPrefix(l, " ") {
+			unfolded.WriteString(l[1:])
+		} else {
+			unfolded.WriteString("\n" + l)
+		}
+	}
+	if !strings.Contains(unfolded.String(), "SUMMARY:"+long) {
+		t.Error("unfolding should restore the summary")
+	}
+}
@@ -0,0 +1,38 @@
+// Package pathutil matches session working directories against the
+// directory patterns used in config (suppressions, admission rules).
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandHome replaces a leading "~" with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// MatchDir reports whether dir is the pattern directory or lies beneath it.
+// Absolute (or ~) patterns match as path prefixes; relative patterns such as
+// "sandbox/" match any directory with that path segment sequence.
+func MatchDir(dir, pattern string) bool {
+	if dir == "" {
+		return false
+	}
+	dir = filepath.Clean(ExpandHome(dir))
+	pattern = filepath.Clean(ExpandHome(pattern))
+	if filepath.IsAbs(pattern) {
+		return dir == pattern || strings.HasPrefix(dir, pattern+string(filepath.Separator))
+	}
+	sep := string(filepath.Separator)
+	wrapped := sep + pattern + sep
+	return strings.Contains(dir+sep, wrapped) || strings.HasPrefix(dir+sep, pattern+sep)
+}
@@ -0,0 +1,41 @@
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchDir(t *testing.T) {
+	tests := []struct {
+		dir, pattern string
+		want         bool
+	}{
+		{"/home/dev/sandbox", "sandbox/", true},
+		{"/home/dev/sandbox/app", "sandbox", true},
+		{"/home/dev/sandboxes/app", "sandbox/", false},
+		{"/home/dev/play/sandbox-x", "play/sandbox", false},
+		{"/home/dev/play/sandbox", "play/sandbox", true},
+		{"/srv/repo/sub", "/srv/repo", true},
+		{"/srv/repository", "/srv/repo", false},
+		{"", "sandbox", false},
+	}
+	for _, tt := range tests {
+		if got := MatchDir(tt.dir, tt.pattern); got != tt.want {
+			t.Errorf("MatchDir(%q, %q) = %v, want %v", tt.dir, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	if got := ExpandHome("~/work"); got != filepath.Join(home, "work") {
+		t.Errorf("ExpandHome(~/work) = %q", got)
+	}
+	if got := ExpandHome("/abs/~/x"); got != "/abs/~/x" {
+		t.Errorf("paths without a leading ~ should be unchanged, got %q", got)
+	}
+}
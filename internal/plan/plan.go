@@ -0,0 +1,197 @@
+// Package plan tracks token usage against the subscription plan quotas
+// configured in [plan]: a rolling usage window matching Anthropic's rate
+// limit windows, and a weekly quota.
+package plan
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Quota period names.
+const (
+	Window = "window"
+	Weekly = "weekly"
+)
+
+// refreshInterval bounds how often usage is recomputed; it walks every
+// session's events and reads the daily history.
+const refreshInterval = 30 * time.Second
+
+// requestTokenAttrs are the api_request attributes counted against the
+// quotas, the same token types as the token usage totals.
+var requestTokenAttrs = [4]string{"input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens"}
+
+// Usage is the token usage of one quota period.
+type Usage struct {
+	Period string // Window or Weekly
+	Limit  int64
+	Used   int64
+	Start  time.Time // zero for a window when no window is active
+	End    time.Time // when the quota resets
+}
+
+// Active reports whether the period has started; a usage window only
+// starts with the first request after the previous one ended.
+func (u Usage) Active() bool {
+	return !u.Start.IsZero()
+}
+
+// Percent returns the share of the quota used, in percent.
+func (u Usage) Percent() float64 {
+	if u.Limit <= 0 {
+		return 0
+	}
+	return float64(u.Used) / float64(u.Limit) * 100
+}
+
+// Remaining returns the unused quota, never negative.
+func (u Usage) Remaining() int64 {
+	return max(0, u.Limit-u.Used)
+}
+
+// Tracker computes plan utilization from the state store. Window usage
+// comes from the api_request events of the in-memory sessions; weekly usage
+// from the persisted daily summaries when history is available.
+type Tracker struct {
+	cfg   config.PlanConfig
+	store state.Store
+
+	mu         sync.Mutex
+	cached     []Usage
+	computedAt time.Time
+}
+
+// NewTracker creates a tracker for the configured plan.
+func NewTracker(cfg config.PlanConfig, store state.Store) *Tracker {
+	return &Tracker{cfg: cfg, store: store}
+}
+
+// Enabled reports whether any plan quota is configured.
+func (t *Tracker) Enabled() bool {
+	return t.cfg.Enabled()
+}
+
+// Name returns the configured plan name, e.g. "Max".
+func (t *Tracker) Name() string {
+	return t.cfg.Name
+}
+
+// Usage returns the current usage of each configured quota, window first.
+func (t *Tracker) Usage(now time.Time) []Usage {
+	if !t.Enabled() {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.computedAt) < refreshInterval && now.After(t.computedAt) && !t.expired(now) {
+		return t.cached
+	}
+
+	var usage []Usage
+	if t.cfg.WindowTokens > 0 {
+		u := t.windowUsage(now)
+		u.Limit = t.cfg.WindowTokens
+		usage = append(usage, u)
+	}
+	if t.cfg.WeeklyTokens > 0 {
+		start := budget.WeekStart(now)
+		usage = append(usage, Usage{
+			Period: Weekly, Limit: t.cfg.WeeklyTokens, Used: t.weeklyTokens(start, now),
+			Start: start, End: start.AddDate(0, 0, 7),
+		})
+	}
+
+	t.cached = usage
+	t.computedAt = now
+	return usage
+}
+
+// expired reports whether a cached period has reset since it was computed.
+func (t *Tracker) expired(now time.Time) bool {
+	for _, u := range t.cached {
+		if u.Active() && !now.Before(u.End) {
+			return true
+		}
+	}
+	return false
+}
+
+type request struct {
+	at     time.Time
+	tokens int64
+}
+
+// windowUsage finds the usage window in effect at now. A window opens at
+// the hour of the first request after the previous window closed and lasts
+// WindowHours; no window is active when the last one has closed.
+func (t *Tracker) windowUsage(now time.Time) Usage {
+	length := time.Duration(t.cfg.WindowHours) * time.Hour
+	var requests []request
+	for _, s := range t.store.ListSessions() {
+		for _, e := range s.Events {
+			if e.Name != "claude_code.api_request" || e.Timestamp.After(now) {
+				continue
+			}
+			var tokens int64
+			for _, attr := range requestTokenAttrs {
+				if n, err := strconv.ParseInt(e.Attributes[attr], 10, 64); err == nil {
+					tokens += n
+				}
+			}
+			requests = append(requests, request{at: e.Timestamp, tokens: tokens})
+		}
+	}
+	slices.SortFunc(requests, func(a, b request) int { return a.at.Compare(b.at) })
+
+	u := Usage{Period: Window}
+	for _, r := range requests {
+		if u.Start.IsZero() || !r.at.Before(u.End) {
+			y, m, d := r.at.Date()
+			u.Start = time.Date(y, m, d, r.at.Hour(), 0, 0, 0, r.at.Location())
+			u.End = u.Start.Add(length)
+			u.Used = 0
+		}
+		u.Used += r.tokens
+	}
+	if !now.Before(u.End) {
+		return Usage{Period: Window}
+	}
+	return u
+}
+
+// weeklyTokens returns the tokens used since start.
+func (t *Tracker) weeklyTokens(start, now time.Time) int64 {
+	days := int(now.Sub(start).Hours()/24) + 1
+	from := start.Format("2006-01-02")
+	if summaries := t.store.QueryDailySummaries(days); len(summaries) > 0 {
+		var total int64
+		for _, ds := range summaries {
+			if ds.Date >= from {
+				total += ds.TotalTokens
+			}
+		}
+		return total
+	}
+
+	// No history: count the sessions active since start.
+	var total int64
+	for _, s := range t.store.ListSessions() {
+		last := s.LastEventAt
+		if last.IsZero() {
+			last = s.StartedAt
+		}
+		if last.Before(start) {
+			continue
+		}
+		total += s.TotalTokens
+	}
+	return total
+}
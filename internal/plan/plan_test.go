@@ -0,0 +1,89 @@
+package plan
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// summaryStore serves fixed daily summaries on top of a memory store.
+type summaryStore struct {
+	*state.MemoryStore
+	summaries []state.DailySummary
+}
+
+func (s *summaryStore) QueryDailySummaries(int) []state.DailySummary { return s.summaries }
+
+func addRequest(store state.Store, sessionID string, at time.Time, input, cacheRead int) {
+	store.AddEvent(sessionID, state.Event{
+		Name: "claude_code.api_request",
+		Attributes: map[string]string{
+			"input_tokens":      strconv.Itoa(input),
+			"cache_read_tokens": strconv.Itoa(cacheRead),
+		},
+		Timestamp: at,
+	})
+}
+
+func TestTracker_Window(t *testing.T) {
+	store := state.NewMemoryStore()
+	day := time.Date(2026, 3, 12, 0, 0, 0, 0, time.Local)
+	// A window 08:00-13:00, then one opened by the 14:20 request.
+	addRequest(store, "s1", day.Add(8*time.Hour+30*time.Minute), 1000, 0)
+	addRequest(store, "s1", day.Add(12*time.Hour+50*time.Minute), 500, 0)
+	addRequest(store, "s2", day.Add(14*time.Hour+20*time.Minute), 200, 800)
+	addRequest(store, "s1", day.Add(16*time.Hour), 1000, 0)
+
+	tr := NewTracker(config.PlanConfig{WindowTokens: 10_000, WindowHours: 5}, store)
+	got := tr.Usage(day.Add(16*time.Hour + 30*time.Minute))
+	if len(got) != 1 || got[0].Period != Window {
+		t.Fatalf("expected window usage, got %+v", got)
+	}
+	u := got[0]
+	if !u.Start.Equal(day.Add(14*time.Hour)) || !u.End.Equal(day.Add(19*time.Hour)) {
+		t.Errorf("window: got %v-%v, want 14:00-19:00", u.Start, u.End)
+	}
+	if u.Used != 2000 || u.Percent() != 20 || u.Remaining() != 8000 {
+		t.Errorf("used=%d percent=%v remaining=%d, want 2000, 20, 8000", u.Used, u.Percent(), u.Remaining())
+	}
+
+	// After the window closes no window is active until the next request.
+	tr = NewTracker(config.PlanConfig{WindowTokens: 10_000, WindowHours: 5}, store)
+	if u := tr.Usage(day.Add(19 * time.Hour))[0]; u.Active() || u.Used != 0 {
+		t.Errorf("expected no active window, got %+v", u)
+	}
+}
+
+func TestTracker_Weekly(t *testing.T) {
+	store := &summaryStore{
+		MemoryStore: state.NewMemoryStore(),
+		summaries: []state.DailySummary{
+			{Date: "2026-03-12", TotalTokens: 300_000},
+			{Date: "2026-03-09", TotalTokens: 200_000},
+			{Date: "2026-03-08", TotalTokens: 900_000}, // last week
+		},
+	}
+	tr := NewTracker(config.PlanConfig{WeeklyTokens: 1_000_000, WindowHours: 5}, store)
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.Local)
+
+	got := tr.Usage(now)
+	if len(got) != 1 || got[0].Period != Weekly {
+		t.Fatalf("expected weekly usage, got %+v", got)
+	}
+	if got[0].Used != 500_000 || got[0].Percent() != 50 {
+		t.Errorf("weekly: used=%d percent=%v, want 500000, 50", got[0].Used, got[0].Percent())
+	}
+	if !got[0].End.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("weekly end: got %v", got[0].End)
+	}
+}
+
+func TestTracker_Disabled(t *testing.T) {
+	tr := NewTracker(config.PlanConfig{WindowHours: 5}, state.NewMemoryStore())
+	if tr.Enabled() || tr.Usage(time.Now()) != nil {
+		t.Error("a plan without quotas should be disabled")
+	}
+}
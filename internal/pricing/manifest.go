@@ -0,0 +1,113 @@
+// Package pricing keeps model prices up to date from a published pricing
+// manifest. The last manifest fetched is cached on disk, so cc-top starts
+// with the last known prices when offline and falls back to the prices in
+// the config without one.
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// Prices are the prices of one model, in USD per million tokens.
+type Prices struct {
+	Input         float64 `json:"input"`
+	Output        float64 `json:"output"`
+	CacheRead     float64 `json:"cache_read"`
+	CacheCreation float64 `json:"cache_creation"`
+}
+
+// array returns p in the [input, output, cacheRead, cacheCreation] order
+// of config.Config.Pricing.
+func (p Prices) array() [4]float64 {
+	return [4]float64{p.Input, p.Output, p.CacheRead, p.CacheCreation}
+}
+
+// Manifest is a published price list:
+//
+//	{
+//	  "updated": "2026-10-01",
+//	  "models": {"claude-opus-4-6": {"input": 5, "output": 25, "cache_read": 0.5, "cache_creation": 6.25}},
+//	  "tiers": {"batch": {"claude-opus-4-6": {"input": 2.5, ...}}}
+//	}
+type Manifest struct {
+	// Updated is when the publisher last changed the prices.
+	Updated string            `json:"updated"`
+	Models  map[string]Prices `json:"models"`
+	// Tiers holds the prices of non-standard service tiers, keyed by tier
+	// and then model.
+	Tiers map[string]map[string]Prices `json:"tiers,omitempty"`
+}
+
+// Parse decodes and checks a manifest. A manifest without models, or with
+// a negative or non-finite price, is rejected rather than applied.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decoding pricing manifest: %w", err)
+	}
+	if len(m.Models) == 0 {
+		return nil, errors.New("pricing manifest lists no models")
+	}
+	check := func(where string, prices map[string]Prices) error {
+		for model, p := range prices {
+			for _, v := range p.array() {
+				if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+					return fmt.Errorf("pricing manifest: invalid %sprice %v for %s", where, v, model)
+				}
+			}
+		}
+		return nil
+	}
+	if err := check("", m.Models); err != nil {
+		return nil, err
+	}
+	for tier, prices := range m.Tiers {
+		if err := check(tier+" ", prices); err != nil {
+			return nil, err
+		}
+	}
+	return &m, nil
+}
+
+// Apply returns cfg with the manifest's prices in place of the configured
+// ones. Models and tiers the manifest does not list keep their configured
+// prices; cfg itself is not modified.
+func (m *Manifest) Apply(cfg config.Config) config.Config {
+	if m == nil {
+		return cfg
+	}
+	cfg.Pricing = maps.Clone(cfg.Pricing)
+	if cfg.Pricing == nil {
+		cfg.Pricing = make(map[string][4]float64)
+	}
+	for model, p := range m.Models {
+		cfg.Pricing[model] = p.array()
+	}
+
+	tiers := make(map[string]map[string][4]float64, len(cfg.PricingTiers))
+	for tier, prices := range cfg.PricingTiers {
+		tiers[tier] = maps.Clone(prices)
+	}
+	for tier, prices := range m.Tiers {
+		if tier == config.StandardTier {
+			for model, p := range prices {
+				cfg.Pricing[model] = p.array()
+			}
+			continue
+		}
+		if tiers[tier] == nil {
+			tiers[tier] = make(map[string][4]float64)
+		}
+		for model, p := range prices {
+			tiers[tier][model] = p.array()
+		}
+	}
+	cfg.PricingTiers = tiers
+	return cfg
+}
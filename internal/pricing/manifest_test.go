@@ -0,0 +1,62 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+const testManifest = `{
+  "updated": "2026-10-01",
+  "models": {
+    "claude-opus-4-6": {"input": 4, "output": 20, "cache_read": 0.4, "cache_creation": 5},
+    "claude-opus-5": {"input": 6, "output": 30, "cache_read": 0.6, "cache_creation": 7.5}
+  },
+  "tiers": {
+    "batch": {"claude-opus-5": {"input": 3, "output": 15, "cache_read": 0.3, "cache_creation": 3.75}}
+  }
+}`
+
+func TestParse_Rejects(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":       `<html>`,
+		"no models":      `{"updated": "2026-10-01", "models": {}}`,
+		"negative price": `{"models": {"m": {"input": -1, "output": 1}}}`,
+		"negative tier":  `{"models": {"m": {"input": 1}}, "tiers": {"batch": {"m": {"output": -2}}}}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestManifest_Apply(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	got := m.Apply(cfg)
+
+	if p := got.Pricing["claude-opus-4-6"]; p != [4]float64{4, 20, 0.4, 5} {
+		t.Errorf("listed model should take the manifest price, got %v", p)
+	}
+	if p := got.Pricing["claude-opus-5"]; p != [4]float64{6, 30, 0.6, 7.5} {
+		t.Errorf("new model should be added, got %v", p)
+	}
+	if got.Pricing["claude-haiku-4-5-20251001"] != cfg.Pricing["claude-haiku-4-5-20251001"] {
+		t.Error("unlisted model should keep the configured price")
+	}
+	if p := got.PricingTiers["batch"]["claude-opus-5"]; p != [4]float64{3, 15, 0.3, 3.75} {
+		t.Errorf("batch tier price = %v", p)
+	}
+	if got.PricingTiers["batch"]["claude-opus-4-6"] != cfg.PricingTiers["batch"]["claude-opus-4-6"] {
+		t.Error("unlisted batch price should be kept")
+	}
+	if cfg.Pricing["claude-opus-4-6"] != config.DefaultConfig().Pricing["claude-opus-4-6"] {
+		t.Error("Apply must not modify the config passed in")
+	}
+	if _, ok := cfg.PricingTiers["batch"]["claude-opus-5"]; ok {
+		t.Error("Apply must not modify the tiers of the config passed in")
+	}
+}
@@ -0,0 +1,217 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+)
+
+const (
+	// fetchTimeout bounds a single manifest download.
+	fetchTimeout = 30 * time.Second
+	// maxManifestSize caps the manifest body.
+	maxManifestSize = 1 << 20
+	// retryInterval is how soon a failed fetch is retried, unless the
+	// refresh interval is shorter.
+	retryInterval = time.Hour
+)
+
+// Syncer fetches the pricing manifest configured in [pricing_sync] and
+// keeps the last good one. Until the first successful fetch it serves the
+// cached manifest, if any.
+type Syncer struct {
+	url       string
+	cachePath string
+	refresh   time.Duration
+	client    *http.Client
+
+	mu        sync.Mutex
+	current   *Manifest
+	fetchedAt time.Time
+	subs      []func(*Manifest)
+}
+
+// Option configures a Syncer.
+type Option func(*Syncer)
+
+// WithHTTPClient sets the client used to fetch the manifest.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Syncer) { s.client = c }
+}
+
+// NewSyncer returns a Syncer for cfg, loaded with the cached manifest. It
+// returns nil when cfg has no URL; a nil Syncer applies no prices.
+func NewSyncer(cfg config.PricingSyncConfig, opts ...Option) *Syncer {
+	if cfg.URL == "" {
+		return nil
+	}
+	s := &Syncer{
+		url:       cfg.URL,
+		cachePath: pathutil.ExpandHome(cfg.CachePath),
+		refresh:   time.Duration(cfg.RefreshHours) * time.Hour,
+		client:    &http.Client{Timeout: fetchTimeout},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.loadCache()
+	return s
+}
+
+// Manifest returns the manifest in effect, or nil if none was fetched or
+// cached yet.
+func (s *Syncer) Manifest() *Manifest {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// Apply returns cfg with the prices of the manifest in effect.
+func (s *Syncer) Apply(cfg config.Config) config.Config {
+	return s.Manifest().Apply(cfg)
+}
+
+// Subscribe registers fn to be called, from the syncer's goroutine, when a
+// fetch brings different prices.
+func (s *Syncer) Subscribe(fn func(*Manifest)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, fn)
+}
+
+// Start fetches the manifest whenever the one in effect is older than the
+// refresh interval, until ctx is cancelled.
+func (s *Syncer) Start(ctx context.Context) {
+	go func() {
+		for {
+			s.mu.Lock()
+			wait := time.Until(s.fetchedAt.Add(s.refresh))
+			s.mu.Unlock()
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+
+			if err := s.Sync(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("WARNING: pricing sync failed, keeping the current prices: %v", err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(min(s.refresh, retryInterval)):
+				}
+			}
+		}
+	}()
+}
+
+// Sync fetches the manifest, caches it and, when its prices changed,
+// publishes it to the subscribers.
+func (s *Syncer) Sync(ctx context.Context) error {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	if err := s.writeCache(data); err != nil {
+		log.Printf("WARNING: caching pricing manifest: %v", err)
+	}
+
+	s.mu.Lock()
+	changed := !reflect.DeepEqual(s.current, m)
+	s.current = m
+	s.fetchedAt = time.Now()
+	subs := s.subs
+	s.mu.Unlock()
+
+	if changed {
+		log.Printf("pricing manifest updated from %s (prices of %s, %d models)", s.url, m.Updated, len(m.Models))
+		for _, fn := range subs {
+			fn(m)
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", s.url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", s.url, err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("pricing manifest at %s is larger than %d bytes", s.url, maxManifestSize)
+	}
+	return data, nil
+}
+
+// loadCache serves the cached manifest; its modification time is when it
+// was fetched.
+func (s *Syncer) loadCache() {
+	if s.cachePath == "" {
+		return
+	}
+	data, err := os.ReadFile(s.cachePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("WARNING: reading cached pricing manifest: %v", err)
+		}
+		return
+	}
+	m, err := Parse(data)
+	if err != nil {
+		log.Printf("WARNING: ignoring cached pricing manifest %s: %v", s.cachePath, err)
+		return
+	}
+	s.current = m
+	if fi, err := os.Stat(s.cachePath); err == nil {
+		s.fetchedAt = fi.ModTime()
+	}
+}
+
+// writeCache replaces the cached manifest atomically.
+func (s *Syncer) writeCache(data []byte) error {
+	if s.cachePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.cachePath), 0o755); err != nil {
+		return err
+	}
+	tmp := s.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.cachePath)
+}
@@ -0,0 +1,88 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestSyncer_FetchCacheAndOffline(t *testing.T) {
+	body := testManifest
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "pricing.json")
+	cfg := config.PricingSyncConfig{URL: srv.URL, RefreshHours: 24, CachePath: cachePath}
+
+	s := NewSyncer(cfg)
+	if s.Manifest() != nil {
+		t.Fatal("no manifest should be in effect before the first fetch")
+	}
+	if got := s.Apply(config.DefaultConfig()); got.Pricing["claude-opus-4-6"] != config.DefaultConfig().Pricing["claude-opus-4-6"] {
+		t.Error("without a manifest the configured prices apply")
+	}
+
+	var published int
+	s.Subscribe(func(*Manifest) { published++ })
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if published != 1 {
+		t.Errorf("unchanged prices should be published once, got %d", published)
+	}
+	if got := s.Apply(config.DefaultConfig()).Pricing["claude-opus-4-6"]; got != [4]float64{4, 20, 0.4, 5} {
+		t.Errorf("fetched price not applied: %v", got)
+	}
+
+	// A broken response keeps the prices in effect.
+	status, body = http.StatusOK, `{"models": {}}`
+	if err := s.Sync(context.Background()); err == nil {
+		t.Error("an invalid manifest should be an error")
+	}
+	if s.Manifest() == nil || s.Manifest().Updated != "2026-10-01" {
+		t.Error("an invalid manifest should not replace the current one")
+	}
+
+	// Offline, a new syncer starts from the cache, which is not stale yet.
+	status = http.StatusServiceUnavailable
+	s2 := NewSyncer(cfg)
+	if m := s2.Manifest(); m == nil || m.Updated != "2026-10-01" {
+		t.Fatalf("cached manifest not loaded: %+v", m)
+	}
+	if wait := time.Until(s2.fetchedAt.Add(s2.refresh)); wait < 23*time.Hour {
+		t.Errorf("a fresh cache should not be refetched yet, next fetch in %v", wait)
+	}
+	if err := s2.Sync(context.Background()); err == nil {
+		t.Error("a failed fetch should be an error")
+	}
+	if s2.Manifest() == nil {
+		t.Error("a failed fetch should keep the cached manifest")
+	}
+	if _, err := os.Stat(cachePath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("the temporary cache file should be renamed into place")
+	}
+}
+
+func TestNewSyncer_Disabled(t *testing.T) {
+	s := NewSyncer(config.DefaultConfig().PricingSync)
+	if s != nil {
+		t.Fatal("pricing sync should be off by default")
+	}
+	cfg := config.DefaultConfig()
+	if got := s.Apply(cfg); got.Pricing["claude-opus-4-6"] != cfg.Pricing["claude-opus-4-6"] {
+		t.Error("a nil syncer should leave the prices alone")
+	}
+}
@@ -0,0 +1,93 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestToOSSignal(t *testing.T) {
+	tests := []struct {
+		sig  SignalType
+		want os.Signal
+	}{
+		{SignalStop, syscall.SIGSTOP},
+		{SignalKill, syscall.SIGKILL},
+		{SignalContinue, syscall.SIGCONT},
+		{SignalTerminate, syscall.SIGTERM},
+	}
+
+	for _, tt := range tests {
+		got := toOSSignal(tt.sig)
+		if got != tt.want {
+			t.Errorf("toOSSignal(%d) = %v, want %v", tt.sig, got, tt.want)
+		}
+	}
+}
+
+func TestToOSSignal_Unknown(t *testing.T) {
+	got := toOSSignal(SignalType(99))
+	if got != nil {
+		t.Errorf("toOSSignal(99) = %v, want nil", got)
+	}
+}
+
+func TestSendSignal_RealProcess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping real process test in short mode")
+	}
+
+	// Start a sleep process that we can safely signal.
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start sleep process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	pid := cmd.Process.Pid
+
+	// Give the process a moment to start.
+	time.Sleep(100 * time.Millisecond)
+
+	// Verify the process exists.
+	err := CheckProcess(pid)
+	if err != nil {
+		t.Fatalf("sleep process should exist, got: %v", err)
+	}
+
+	// Send SIGSTOP.
+	err = SendSignal(pid, SignalStop)
+	if err != nil {
+		t.Errorf("SendSignal(SIGSTOP) failed: %v", err)
+	}
+
+	// Send SIGCONT to resume.
+	err = SendSignal(pid, SignalContinue)
+	if err != nil {
+		t.Errorf("SendSignal(SIGCONT) failed: %v", err)
+	}
+
+	// Send SIGTERM.
+	err = SendSignal(pid, SignalTerminate)
+	if err != nil {
+		t.Errorf("SendSignal(SIGTERM) failed: %v", err)
+	}
+
+	// Wait for process to exit.
+	_ = cmd.Wait()
+
+	// Now the process should not exist.
+	time.Sleep(100 * time.Millisecond)
+	err = CheckProcess(pid)
+	if err == nil {
+		// May still exist briefly; try SIGKILL.
+		_ = SendSignal(pid, SignalKill)
+	}
+}
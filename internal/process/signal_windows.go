@@ -0,0 +1,106 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// processSuspendResume is the access right NtSuspendProcess and
+// NtResumeProcess need; x/sys/windows does not define it.
+const processSuspendResume = 0x0800
+
+// stillActive is the exit code GetExitCodeProcess reports for a running
+// process.
+const stillActive = 259
+
+var (
+	ntdll            = windows.NewLazySystemDLL("ntdll.dll")
+	ntSuspendProcess = ntdll.NewProc("NtSuspendProcess")
+	ntResumeProcess  = ntdll.NewProc("NtResumeProcess")
+)
+
+// SendSignal applies the signal to the process with the given PID. Windows
+// has no signals or process groups: SignalStop and SignalContinue suspend
+// and resume every thread of the process, and SignalKill and
+// SignalTerminate both terminate it. Child processes are not affected.
+// It returns errNoSuchProcess if the process has already exited.
+func SendSignal(pid int, sig SignalType) error {
+	if pid <= 0 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+
+	var access uint32
+	switch sig {
+	case SignalStop, SignalContinue:
+		access = processSuspendResume
+	case SignalKill, SignalTerminate:
+		access = windows.PROCESS_TERMINATE
+	default:
+		return fmt.Errorf("unknown signal type: %d", sig)
+	}
+
+	h, err := windows.OpenProcess(access, false, uint32(pid))
+	if err != nil {
+		if isProcessGone(err) {
+			return errNoSuchProcess
+		}
+		return fmt.Errorf("opening PID %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(h)
+
+	switch sig {
+	case SignalStop:
+		err = ntStatus(ntSuspendProcess.Call(uintptr(h)))
+	case SignalContinue:
+		err = ntStatus(ntResumeProcess.Call(uintptr(h)))
+	default:
+		err = windows.TerminateProcess(h, 1)
+	}
+	if err != nil {
+		return fmt.Errorf("signalling PID %d: %w", pid, err)
+	}
+	return nil
+}
+
+// ntStatus turns the result of an ntdll call into an error.
+func ntStatus(r1, _ uintptr, _ error) error {
+	if status := windows.NTStatus(r1); status != windows.STATUS_SUCCESS {
+		return status
+	}
+	return nil
+}
+
+// isProcessGone returns true if OpenProcess failed because there is no
+// process with that PID.
+func isProcessGone(err error) bool {
+	return errors.Is(err, windows.ERROR_INVALID_PARAMETER)
+}
+
+// CheckProcess checks if a process with the given PID exists and is alive.
+// Returns nil if the process exists, errNoSuchProcess if it doesn't.
+func CheckProcess(pid int) error {
+	if pid <= 0 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but belongs to someone else.
+		if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+			return nil
+		}
+		return errNoSuchProcess
+	}
+	defer windows.CloseHandle(h)
+
+	// A handle can outlive the process, so check it is still running.
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil || code != stillActive {
+		return errNoSuchProcess
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// Package promexport publishes cc-top's session state on an HTTP /metrics
+// endpoint in the Prometheus text exposition format, so it can be scraped
+// into an existing Prometheus/Grafana stack.
+package promexport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Exporter renders the state store, burn rate and alerts as Prometheus
+// metrics.
+type Exporter struct {
+	store    state.Store
+	burnRate func() burnrate.BurnRate
+	alerts   func() []alerts.Alert
+}
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithBurnRate sets the source of the global burn rate metrics.
+func WithBurnRate(fn func() burnrate.BurnRate) Option {
+	return func(e *Exporter) {
+		e.burnRate = fn
+	}
+}
+
+// WithAlerts sets the source of the alert count metrics.
+func WithAlerts(fn func() []alerts.Alert) Option {
+	return func(e *Exporter) {
+		e.alerts = fn
+	}
+}
+
+// New creates an Exporter reading sessions from store.
+func New(store state.Store, opts ...Option) *Exporter {
+	e := &Exporter{store: store}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ServeHTTP writes the current metrics.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := e.Write(w); err != nil {
+		log.Printf("WARNING: writing Prometheus metrics: %v", err)
+	}
+}
+
+// Write renders all metrics to w.
+func (e *Exporter) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	p := &printer{w: bw}
+
+	sessions := e.store.ListSessions()
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].SessionID < sessions[j].SessionID })
+
+	var sessionAlerts map[string]int
+	var alertList []alerts.Alert
+	if e.alerts != nil {
+		alertList = e.alerts()
+		sessionAlerts = make(map[string]int)
+		for _, a := range alertList {
+			if a.SessionID != "" {
+				sessionAlerts[a.SessionID]++
+			}
+		}
+	}
+
+	byStatus := map[state.SessionStatus]int{}
+	for i := range sessions {
+		byStatus[sessions[i].Status()]++
+	}
+	p.header("cctop_sessions", "gauge", "Sessions known to cc-top by status.")
+	for _, st := range []state.SessionStatus{state.StatusActive, state.StatusIdle, state.StatusDone, state.StatusExited} {
+		p.sample("cctop_sessions", labels{{"status", string(st)}}, float64(byStatus[st]))
+	}
+
+	p.header("cctop_session_cost_usd", "gauge", "Session cost in USD.")
+	for i := range sessions {
+		p.sample("cctop_session_cost_usd", sessionLabels(&sessions[i]), sessions[i].TotalCost)
+	}
+	p.header("cctop_session_tokens", "gauge", "Session token usage by kind.")
+	for i := range sessions {
+		s := &sessions[i]
+		for _, kind := range []struct {
+			name  string
+			value int64
+		}{
+			{"total", s.TotalTokens},
+			{"cache_read", s.CacheReadTokens},
+			{"cache_creation", s.CacheCreationTokens},
+		} {
+			p.sample("cctop_session_tokens", append(sessionLabels(s), label{"kind", kind.name}), float64(kind.value))
+		}
+	}
+
+	p.header("cctop_session_api_requests", "gauge", "API requests recorded for the session.")
+	requests := make([]int, len(sessions))
+	errs := make([]int, len(sessions))
+	for i := range sessions {
+		requests[i], errs[i] = apiCounts(&sessions[i])
+		p.sample("cctop_session_api_requests", sessionLabels(&sessions[i]), float64(requests[i]))
+	}
+	p.header("cctop_session_api_errors", "gauge", "API errors recorded for the session.")
+	for i := range sessions {
+		p.sample("cctop_session_api_errors", sessionLabels(&sessions[i]), float64(errs[i]))
+	}
+	p.header("cctop_session_error_rate", "gauge", "Ratio of API errors to API requests for the session.")
+	for i := range sessions {
+		rate := 0.0
+		if requests[i] > 0 {
+			rate = float64(errs[i]) / float64(requests[i])
+		}
+		p.sample("cctop_session_error_rate", sessionLabels(&sessions[i]), rate)
+	}
+
+	if e.alerts != nil {
+		p.header("cctop_session_alerts", "gauge", "Alerts raised for the session.")
+		for i := range sessions {
+			p.sample("cctop_session_alerts", sessionLabels(&sessions[i]), float64(sessionAlerts[sessions[i].SessionID]))
+		}
+
+		type ruleKey struct{ rule, severity string }
+		byRule := map[ruleKey]int{}
+		for _, a := range alertList {
+			byRule[ruleKey{a.Rule, a.Severity}]++
+		}
+		keys := make([]ruleKey, 0, len(byRule))
+		for k := range byRule {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].rule != keys[j].rule {
+				return keys[i].rule < keys[j].rule
+			}
+			return keys[i].severity < keys[j].severity
+		})
+		p.header("cctop_alerts", "gauge", "Alerts raised by rule and severity.")
+		for _, k := range keys {
+			p.sample("cctop_alerts", labels{{"rule", k.rule}, {"severity", k.severity}}, float64(byRule[k]))
+		}
+	}
+
+	p.header("cctop_cost_usd", "gauge", "Total cost across all sessions in USD.")
+	p.sample("cctop_cost_usd", nil, e.store.GetAggregatedCost())
+
+	if e.burnRate != nil {
+		br := e.burnRate()
+		p.header("cctop_burn_rate_usd_per_hour", "gauge", "Global burn rate in USD per hour.")
+		p.sample("cctop_burn_rate_usd_per_hour", nil, br.HourlyRate)
+		p.header("cctop_burn_rate_trend", "gauge", "Burn rate trend: 1 rising, -1 falling, 0 flat.")
+		p.sample("cctop_burn_rate_trend", nil, trendValue(br.Trend))
+		p.header("cctop_token_velocity_tokens_per_minute", "gauge", "Global token velocity in tokens per minute.")
+		p.sample("cctop_token_velocity_tokens_per_minute", nil, br.TokenVelocity)
+	}
+
+	if p.err != nil {
+		return p.err
+	}
+	return bw.Flush()
+}
+
+// apiCounts counts api_request and api_error events for a session.
+func apiCounts(s *state.SessionData) (requests, errors int) {
+	for _, e := range s.Events {
+		switch e.Name {
+		case "claude_code.api_request":
+			requests++
+		case "claude_code.api_error":
+			errors++
+		}
+	}
+	return requests, errors
+}
+
+func trendValue(t burnrate.TrendDirection) float64 {
+	switch t {
+	case burnrate.TrendUp:
+		return 1
+	case burnrate.TrendDown:
+		return -1
+	}
+	return 0
+}
+
+type label struct{ name, value string }
+
+type labels []label
+
+func sessionLabels(s *state.SessionData) labels {
+	return labels{{"session_id", s.SessionID}, {"model", s.Model}}
+}
+
+// printer writes exposition lines, remembering the first write error.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) header(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *printer) sample(name string, ls labels, v float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(ls) > 0 {
+		b.WriteByte('{')
+		for i, l := range ls {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l.name)
+			b.WriteString(`="`)
+			b.WriteString(escapeLabel(l.value))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	p.printf("%s %s\n", b.String(), strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// Server serves an Exporter on /metrics.
+type Server struct {
+	addr     string
+	exporter *Exporter
+	server   *http.Server
+}
+
+// NewServer creates a server that will listen on addr (host:port).
+func NewServer(addr string, e *Exporter) *Server {
+	return &Server{addr: addr, exporter: e}
+}
+
+// Start listens on the configured address and serves /metrics in the
+// background. It returns an error if the address is unavailable.
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("metrics endpoint %s: %w", s.addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.exporter)
+	s.server = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Prometheus metrics listening on http://%s/metrics", s.addr)
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Printf("metrics server stopped: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		s.Stop()
+	}()
+	return nil
+}
+
+// Stop shuts the server down, waiting up to 5 seconds for in-flight scrapes.
+func (s *Server) Stop() {
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.server.Shutdown(ctx); err != nil {
+			log.Printf("metrics server forced shutdown: %v", err)
+		}
+	}
+}
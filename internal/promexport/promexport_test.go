@@ -0,0 +1,141 @@
+package promexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func newTestStore() *state.MemoryStore {
+	store := state.NewMemoryStore()
+	now := time.Now()
+	store.AddMetric("sess-1", state.Metric{
+		Name: "claude_code.cost.usage", Value: 1.25,
+		Attributes: map[string]string{"model": "claude-sonnet-4-5"}, Timestamp: now,
+	})
+	store.AddMetric("sess-1", state.Metric{
+		Name: "claude_code.token.usage", Value: 3000,
+		Attributes: map[string]string{"type": "input"}, Timestamp: now,
+	})
+	for range 3 {
+		store.AddEvent("sess-1", state.Event{Name: "claude_code.api_request", Timestamp: now})
+	}
+	store.AddEvent("sess-1", state.Event{Name: "claude_code.api_error", Timestamp: now})
+	store.AddEvent("sess-\"2\"", state.Event{Name: "claude_code.user_prompt", Timestamp: now})
+	return store
+}
+
+func TestExporter_Write(t *testing.T) {
+	exp := New(newTestStore(),
+		WithBurnRate(func() burnrate.BurnRate {
+			return burnrate.BurnRate{HourlyRate: 2.5, Trend: burnrate.TrendUp, TokenVelocity: 1200}
+		}),
+		WithAlerts(func() []alerts.Alert {
+			return []alerts.Alert{
+				{Rule: "CostSurge", Severity: "critical"},
+				{Rule: "ErrorStorm", Severity: "warning", SessionID: "sess-1"},
+				{Rule: "ErrorStorm", Severity: "warning", SessionID: "sess-1"},
+			}
+		}),
+	)
+
+	var b strings.Builder
+	if err := exp.Write(&b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE cctop_session_cost_usd gauge",
+		`cctop_session_cost_usd{session_id="sess-1",model="claude-sonnet-4-5"} 1.25`,
+		`cctop_session_tokens{session_id="sess-1",model="claude-sonnet-4-5",kind="total"} 3000`,
+		`cctop_session_api_requests{session_id="sess-1",model="claude-sonnet-4-5"} 3`,
+		`cctop_session_api_errors{session_id="sess-1",model="claude-sonnet-4-5"} 1`,
+		`cctop_session_error_rate{session_id="sess-1",model="claude-sonnet-4-5"} 0.3333333333333333`,
+		`cctop_session_alerts{session_id="sess-1",model="claude-sonnet-4-5"} 2`,
+		`cctop_session_alerts{session_id="sess-\"2\"",model=""} 0`,
+		`cctop_alerts{rule="CostSurge",severity="critical"} 1`,
+		`cctop_alerts{rule="ErrorStorm",severity="warning"} 2`,
+		`cctop_sessions{status="active"} 2`,
+		"cctop_cost_usd 1.25",
+		"cctop_burn_rate_usd_per_hour 2.5",
+		"cctop_burn_rate_trend 1",
+		"cctop_token_velocity_tokens_per_minute 1200",
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestExporter_OptionalSources(t *testing.T) {
+	var b strings.Builder
+	if err := New(newTestStore()).Write(&b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := b.String()
+	if strings.Contains(out, "cctop_burn_rate_usd_per_hour") || strings.Contains(out, "cctop_alerts") {
+		t.Errorf("burn rate and alert metrics need their sources:\n%s", out)
+	}
+	if !strings.Contains(out, "cctop_session_cost_usd") {
+		t.Error("session metrics should always be exported")
+	}
+}
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	exp := New(newTestStore())
+
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET: want 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	rec = httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: want 405, got %d", rec.Code)
+	}
+}
+
+func TestServer_StartStop(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	srv := NewServer(addr, New(newTestStore()))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", addr))
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "cctop_session_cost_usd") {
+		t.Errorf("scrape returned unexpected body:\n%s", body)
+	}
+
+	if err := NewServer(addr, New(newTestStore())).Start(context.Background()); err == nil {
+		t.Error("second server on the same address should fail to start")
+	}
+}
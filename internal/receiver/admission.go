@@ -0,0 +1,239 @@
+package receiver
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// CWDFunc resolves the working directory of the process that owns an
+// inbound connection's source port. It returns "" if the process is unknown.
+type CWDFunc func(sourcePort int) string
+
+// Admission decides which sessions' telemetry is stored, based on the
+// [receiver.admission] allow and deny matchers. A session is dropped if it
+// matches any deny matcher, or if an allow list is configured and it matches
+// none of it. Decisions are cached per session once every matcher can be
+// evaluated; until then data is dropped if it still needs to match the allow
+// list and admitted otherwise. It is safe for concurrent use.
+type Admission struct {
+	allow []matcher
+	deny  []matcher
+	cwdOf CWDFunc
+
+	mu       sync.Mutex
+	decided  map[string]bool // session ID -> admitted
+	sessions map[string]*admissionInfo
+}
+
+type matcher struct {
+	kind, value string
+}
+
+// admissionInfo is what is known so far about an undecided session.
+type admissionInfo struct {
+	org, user, cwd string
+	port           int // last source port seen
+	resolvedPort   int // source port the CWD was last resolved for
+}
+
+type matchResult int
+
+const (
+	noMatch matchResult = iota
+	matched
+	unknown
+)
+
+// NewAdmission creates an Admission from cfg. cwdOf may be nil, in which case
+// cwd: matchers never match.
+func NewAdmission(cfg config.AdmissionConfig, cwdOf CWDFunc) *Admission {
+	return &Admission{
+		allow:    parseMatchers(cfg.Allow),
+		deny:     parseMatchers(cfg.Deny),
+		cwdOf:    cwdOf,
+		decided:  make(map[string]bool),
+		sessions: make(map[string]*admissionInfo),
+	}
+}
+
+func parseMatchers(specs []string) []matcher {
+	ms := make([]matcher, 0, len(specs))
+	for _, s := range specs {
+		kind, value, _ := strings.Cut(s, ":")
+		ms = append(ms, matcher{kind: kind, value: strings.TrimSpace(value)})
+	}
+	return ms
+}
+
+// RecordSourcePort remembers the latest source port of a session so its
+// working directory can be resolved for cwd: matchers.
+func (a *Admission) RecordSourcePort(sourcePort int, sessionID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.decided[sessionID]; ok {
+		return
+	}
+	a.info(sessionID).port = sourcePort
+}
+
+// Admit reports whether data for sessionID carrying attrs should be stored.
+// Data without a session ID is judged on its own attributes.
+func (a *Admission) Admit(sessionID string, attrs map[string]string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if sessionID == "" {
+		admitted, _ := a.evaluate(&admissionInfo{
+			org:  attrs["organization.id"],
+			user: attrs["user.account_uuid"],
+		}, false)
+		return admitted
+	}
+	if admitted, ok := a.decided[sessionID]; ok {
+		return admitted
+	}
+
+	info := a.info(sessionID)
+	if v := attrs["organization.id"]; v != "" {
+		info.org = v
+	}
+	if v := attrs["user.account_uuid"]; v != "" {
+		info.user = v
+	}
+	admitted, final := a.evaluate(info, true)
+	if final {
+		a.decided[sessionID] = admitted
+		delete(a.sessions, sessionID)
+	}
+	return admitted
+}
+
+func (a *Admission) info(sessionID string) *admissionInfo {
+	info, ok := a.sessions[sessionID]
+	if !ok {
+		info = &admissionInfo{}
+		a.sessions[sessionID] = info
+	}
+	return info
+}
+
+// evaluate applies the matchers to info. final is false while an unknown
+// attribute could still change the outcome.
+func (a *Admission) evaluate(info *admissionInfo, inSession bool) (admitted, final bool) {
+	deny := a.matchAny(a.deny, info, inSession)
+	if deny == matched {
+		return false, true
+	}
+	allow := matched
+	if len(a.allow) > 0 {
+		allow = a.matchAny(a.allow, info, inSession)
+	}
+	switch {
+	case allow == noMatch:
+		return false, true
+	case allow == unknown:
+		return false, false
+	case deny == unknown:
+		return true, false
+	}
+	return true, true
+}
+
+func (a *Admission) matchAny(ms []matcher, info *admissionInfo, inSession bool) matchResult {
+	result := noMatch
+	for _, m := range ms {
+		switch a.match(m, info, inSession) {
+		case matched:
+			return matched
+		case unknown:
+			result = unknown
+		}
+	}
+	return result
+}
+
+func (a *Admission) match(m matcher, info *admissionInfo, inSession bool) matchResult {
+	var have string
+	switch m.kind {
+	case "org":
+		have = info.org
+	case "user":
+		have = info.user
+	case "cwd":
+		if a.cwdOf == nil || !inSession {
+			return noMatch
+		}
+		if info.cwd == "" && info.port > 0 && info.port != info.resolvedPort {
+			info.cwd = a.cwdOf(info.port)
+			info.resolvedPort = info.port
+		}
+		if info.cwd == "" {
+			return unknown
+		}
+		if pathutil.MatchDir(info.cwd, m.value) {
+			return matched
+		}
+		return noMatch
+	default:
+		return noMatch
+	}
+	if have == "" {
+		if inSession {
+			return unknown
+		}
+		return noMatch
+	}
+	if have == m.value {
+		return matched
+	}
+	return noMatch
+}
+
+// admittingStore drops metrics, events, spans and metadata for sessions the
+// Admission rejects before they reach the wrapped store.
+type admittingStore struct {
+	state.Store
+	admission *Admission
+}
+
+func (s admittingStore) AddMetric(sessionID string, m state.Metric) {
+	if s.admission.Admit(sessionID, m.Attributes) {
+		s.Store.AddMetric(sessionID, m)
+	}
+}
+
+func (s admittingStore) AddEvent(sessionID string, e state.Event) {
+	if s.admission.Admit(sessionID, e.Attributes) {
+		s.Store.AddEvent(sessionID, e)
+	}
+}
+
+func (s admittingStore) AddSpan(sessionID string, sp state.Span) {
+	if s.admission.Admit(sessionID, sp.Attributes) {
+		s.Store.AddSpan(sessionID, sp)
+	}
+}
+
+func (s admittingStore) UpdateMetadata(sessionID string, meta state.SessionMetadata) {
+	if s.admission.Admit(sessionID, nil) {
+		s.Store.UpdateMetadata(sessionID, meta)
+	}
+}
+
+// admissionPortMapper feeds source ports to the Admission before passing
+// them on to the correlation PortMapper, if any.
+type admissionPortMapper struct {
+	admission *Admission
+	next      PortMapper
+}
+
+func (p admissionPortMapper) RecordSourcePort(sourcePort int, sessionID string) {
+	p.admission.RecordSourcePort(sourcePort, sessionID)
+	if p.next != nil {
+		p.next.RecordSourcePort(sourcePort, sessionID)
+	}
+}
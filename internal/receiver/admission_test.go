@@ -0,0 +1,119 @@
+package receiver
+
+import (
+	"testing"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestAdmission_OrgAndUser(t *testing.T) {
+	a := NewAdmission(config.AdmissionConfig{
+		Allow: []string{"org:acme"},
+		Deny:  []string{"user:intern"},
+	}, nil)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		attrs     map[string]string
+		want      bool
+	}{
+		{"allowed org", "s1", map[string]string{"organization.id": "acme", "user.account_uuid": "me"}, true},
+		{"other org", "s2", map[string]string{"organization.id": "other", "user.account_uuid": "me"}, false},
+		{"denied user in allowed org", "s3", map[string]string{"organization.id": "acme", "user.account_uuid": "intern"}, false},
+		{"org not yet known", "s4", map[string]string{}, false},
+		{"sessionless allowed org", "", map[string]string{"organization.id": "acme"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Admit(tt.sessionID, tt.attrs); got != tt.want {
+				t.Errorf("Admit(%q) = %v, want %v", tt.sessionID, got, tt.want)
+			}
+		})
+	}
+
+	// Decisions stick once made, even for data without the attributes.
+	if !a.Admit("s1", nil) {
+		t.Error("s1 should stay admitted")
+	}
+	if a.Admit("s2", map[string]string{"organization.id": "acme"}) {
+		t.Error("s2 should stay denied")
+	}
+	// An undecided session is admitted once its org arrives.
+	if !a.Admit("s4", map[string]string{"organization.id": "acme", "user.account_uuid": "me"}) {
+		t.Error("s4 should be admitted once its org is known")
+	}
+}
+
+func TestAdmission_DenyPending(t *testing.T) {
+	a := NewAdmission(config.AdmissionConfig{Deny: []string{"org:shared"}}, nil)
+
+	// Without an allow list, data is admitted until a deny matcher applies.
+	if !a.Admit("s1", nil) {
+		t.Error("undecided session should be admitted without an allow list")
+	}
+	if a.Admit("s1", map[string]string{"organization.id": "shared"}) {
+		t.Error("session should be dropped once its org matches the deny list")
+	}
+	if a.Admit("s1", nil) {
+		t.Error("denied session should stay denied")
+	}
+}
+
+func TestAdmission_CWD(t *testing.T) {
+	dirs := map[int]string{50001: "/home/me/work/api", 50002: "/home/me/scratch"}
+	var lookups int
+	a := NewAdmission(config.AdmissionConfig{Allow: []string{"cwd:/home/me/work"}}, func(port int) string {
+		lookups++
+		return dirs[port]
+	})
+
+	a.RecordSourcePort(50001, "in-work")
+	if !a.Admit("in-work", nil) {
+		t.Error("session under /home/me/work should be admitted")
+	}
+	a.RecordSourcePort(50002, "scratch")
+	if a.Admit("scratch", nil) {
+		t.Error("session outside /home/me/work should be dropped")
+	}
+
+	// An unresolvable port is looked up once and the data dropped until the
+	// session connects from a port that resolves.
+	a.RecordSourcePort(50003, "late")
+	a.Admit("late", nil)
+	a.Admit("late", nil)
+	if lookups != 3 {
+		t.Errorf("lookups = %d, want 3", lookups)
+	}
+	dirs[50004] = "/home/me/work/web"
+	a.RecordSourcePort(50004, "late")
+	if !a.Admit("late", nil) {
+		t.Error("session should be admitted once its CWD resolves")
+	}
+}
+
+func TestAdmittingStore(t *testing.T) {
+	store := state.NewMemoryStore()
+	a := NewAdmission(config.AdmissionConfig{Allow: []string{"org:acme"}}, nil)
+	s := admittingStore{Store: store, admission: a}
+
+	s.AddMetric("mine", state.Metric{Name: "claude_code.cost.usage", Value: 1.5,
+		Attributes: map[string]string{"organization.id": "acme"}})
+	s.UpdateMetadata("mine", state.SessionMetadata{ServiceVersion: "1.0.0"})
+	s.AddMetric("theirs", state.Metric{Name: "claude_code.cost.usage", Value: 9,
+		Attributes: map[string]string{"organization.id": "other"}})
+	s.AddEvent("theirs", state.Event{Name: "claude_code.api_request",
+		Attributes: map[string]string{"organization.id": "other"}})
+	s.UpdateMetadata("theirs", state.SessionMetadata{ServiceVersion: "1.0.0"})
+
+	if store.GetSession("mine") == nil {
+		t.Fatal("admitted session missing from store")
+	}
+	if store.GetSession("theirs") != nil {
+		t.Error("denied session reached the store")
+	}
+	if got := store.GetAggregatedCost(); got != 1.5 {
+		t.Errorf("aggregated cost = %v, want 1.5", got)
+	}
+}
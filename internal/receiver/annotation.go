@@ -0,0 +1,108 @@
+package receiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// maxAnnotationBody caps the size of an annotation request body.
+const maxAnnotationBody = 64 << 10
+
+// maxAnnotationText is the longest annotation text kept, in bytes; longer
+// text is truncated at a character boundary.
+const maxAnnotationText = 500
+
+// annotationRequest is the JSON body accepted on /v1/annotations. The field
+// names match the payload Claude Code passes to hooks on stdin, so a hook can
+// forward its input with an added "text" field.
+type annotationRequest struct {
+	SessionID string `json:"session_id"`
+	Text      string `json:"text"`
+	Source    string `json:"hook_event_name"`
+	Timestamp string `json:"timestamp"` // optional, RFC 3339
+}
+
+// handleAnnotations accepts free-text annotations posted by Claude Code hooks
+// and records them as cc_top.annotation events on the named session.
+func (r *HTTPReceiver) handleAnnotations(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxAnnotationBody+1))
+	if err != nil {
+		logReceiveError("HTTP", "reading annotation body", err)
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	defer req.Body.Close()
+	if len(body) > maxAnnotationBody {
+		http.Error(w, "annotation too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	e, sessionID, err := parseAnnotation(body, time.Now())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid annotation: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	r.store.AddEvent(sessionID, e)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{}"))
+}
+
+// parseAnnotation decodes an annotation request into a session event.
+// now is used when the request carries no timestamp.
+func parseAnnotation(body []byte, now time.Time) (state.Event, string, error) {
+	var ar annotationRequest
+	if err := json.Unmarshal(body, &ar); err != nil {
+		return state.Event{}, "", fmt.Errorf("JSON decode: %w", err)
+	}
+
+	ar.SessionID = strings.TrimSpace(ar.SessionID)
+	ar.Text = strings.TrimSpace(ar.Text)
+	if ar.SessionID == "" {
+		return state.Event{}, "", fmt.Errorf("session_id is required")
+	}
+	if ar.Text == "" {
+		return state.Event{}, "", fmt.Errorf("text is required")
+	}
+	if len(ar.Text) > maxAnnotationText {
+		cut := maxAnnotationText
+		for cut > 0 && !utf8.RuneStart(ar.Text[cut]) {
+			cut--
+		}
+		ar.Text = ar.Text[:cut]
+	}
+
+	ts := now
+	if ar.Timestamp != "" {
+		parsed, err := time.Parse(time.RFC3339, ar.Timestamp)
+		if err != nil {
+			return state.Event{}, "", fmt.Errorf("timestamp: %w", err)
+		}
+		ts = parsed
+	}
+
+	attrs := map[string]string{"text": ar.Text}
+	if ar.Source != "" {
+		attrs["source"] = ar.Source
+	}
+
+	return state.Event{
+		Name:       state.AnnotationEventName,
+		Timestamp:  ts,
+		Attributes: attrs,
+	}, ar.SessionID, nil
+}
@@ -0,0 +1,112 @@
+package receiver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestHTTPAnnotations_RecordsEvent(t *testing.T) {
+	store := state.NewMemoryStore()
+	r := startTestHTTP(t, store, nil)
+	defer r.Stop()
+
+	url := fmt.Sprintf("http://%s/v1/annotations", r.Addr().String())
+	body := `{"session_id":"sess-hook","hook_event_name":"Stop","text":"tests passing","cwd":"/repo"}`
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("HTTP POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	s := store.GetSession("sess-hook")
+	if s == nil || len(s.Events) != 1 {
+		t.Fatalf("expected one event on sess-hook, got %+v", s)
+	}
+	e := s.Events[0]
+	if e.Name != state.AnnotationEventName {
+		t.Errorf("event name = %q, want %q", e.Name, state.AnnotationEventName)
+	}
+	if e.Attributes["text"] != "tests passing" || e.Attributes["source"] != "Stop" {
+		t.Errorf("unexpected attributes: %v", e.Attributes)
+	}
+}
+
+func TestHTTPAnnotations_Rejects(t *testing.T) {
+	store := state.NewMemoryStore()
+	r := startTestHTTP(t, store, nil)
+	defer r.Stop()
+	url := fmt.Sprintf("http://%s/v1/annotations", r.Addr().String())
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing session", `{"text":"hi"}`, http.StatusBadRequest},
+		{"missing text", `{"session_id":"s"}`, http.StatusBadRequest},
+		{"bad json", `{`, http.StatusBadRequest},
+		{"bad timestamp", `{"session_id":"s","text":"hi","timestamp":"yesterday"}`, http.StatusBadRequest},
+		{"too large", `{"session_id":"s","text":"` + strings.Repeat("x", maxAnnotationBody) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(url, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("HTTP POST failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("HTTP GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", resp.StatusCode)
+	}
+	if len(store.ListSessions()) != 0 {
+		t.Error("rejected annotations should not create sessions")
+	}
+}
+
+func TestParseAnnotation_TimestampAndTruncation(t *testing.T) {
+	now := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	long := strings.Repeat("a", maxAnnotationText+50)
+
+	e, sid, err := parseAnnotation([]byte(`{"session_id":" s1 ","text":"`+long+`","timestamp":"2026-02-20T11:00:00Z"}`), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sid != "s1" {
+		t.Errorf("session = %q, want s1", sid)
+	}
+	if !e.Timestamp.Equal(now.Add(-time.Hour)) {
+		t.Errorf("timestamp = %v, want request timestamp", e.Timestamp)
+	}
+	if len(e.Attributes["text"]) != maxAnnotationText {
+		t.Errorf("text length = %d, want %d", len(e.Attributes["text"]), maxAnnotationText)
+	}
+
+	// A 3-byte character straddling the limit is dropped whole.
+	e, _, err = parseAnnotation([]byte(`{"session_id":"s1","text":"`+strings.Repeat("€", maxAnnotationText)+`"}`), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := e.Attributes["text"]; !utf8.ValidString(text) || len(text) != maxAnnotationText-2 {
+		t.Errorf("text = %d bytes (valid UTF-8: %v), want %d", len(text), utf8.ValidString(text), maxAnnotationText-2)
+	}
+}
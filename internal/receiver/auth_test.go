@@ -0,0 +1,111 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthorized(t *testing.T) {
+	tests := []struct {
+		header, token string
+		want          bool
+	}{
+		{"", "", true},
+		{"anything", "", true},
+		{"Bearer s3cret", "s3cret", true},
+		{"bearer  s3cret ", "s3cret", true},
+		{"", "s3cret", false},
+		{"Bearer wrong", "s3cret", false},
+		{"Basic s3cret", "s3cret", false},
+		{"s3cret", "s3cret", false},
+	}
+	for _, tt := range tests {
+		if got := Authorized(tt.header, tt.token); got != tt.want {
+			t.Errorf("Authorized(%q, %q) = %v, want %v", tt.header, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPReceiver_AuthToken(t *testing.T) {
+	store := state.NewMemoryStore()
+	r := NewHTTPReceiver(config.ReceiverConfig{AuthToken: "s3cret"}, store, nil, NopLogger{})
+	body, err := proto.Marshal(makeCostMetricRequest("sess-auth", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		header string
+		want   int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer s3cret", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		r.routes().ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Authorization %q: status %d, want %d", tt.header, rec.Code, tt.want)
+		}
+	}
+	if s := store.GetSession("sess-auth"); s == nil || s.TotalCost != 1 {
+		t.Error("only the authorized request should be stored")
+	}
+}
+
+func TestGRPCReceiver_AuthToken(t *testing.T) {
+	store := state.NewMemoryStore()
+	r := NewGRPCReceiver(config.ReceiverConfig{AuthToken: "s3cret"}, store, nil, NopLogger{})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	r.listener = lis
+	r.server = grpc.NewServer(tokenServerOptions(r.cfg.AuthToken)...)
+	colmetricspb.RegisterMetricsServiceServer(r.server, r)
+	go func() { _ = r.server.Serve(lis) }()
+	defer r.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	client := colmetricspb.NewMetricsServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.Export(ctx, makeCostMetricRequest("sess-auth", 1))
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("without a token: err = %v, want Unauthenticated", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer s3cret")
+	if _, err := client.Export(ctx, makeCostMetricRequest("sess-auth", 1)); err != nil {
+		t.Fatalf("with the token: %v", err)
+	}
+	if s := store.GetSession("sess-auth"); s == nil || s.TotalCost != 1 {
+		t.Error("only the authorized export should be stored")
+	}
+}
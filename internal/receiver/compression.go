@@ -0,0 +1,104 @@
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor for gRPC
+)
+
+// maxDecompressedBody caps the size of a compressed OTLP/HTTP payload once
+// decompressed, so a small request cannot expand without bound.
+const maxDecompressedBody = 64 << 20
+
+var (
+	errUnsupportedEncoding = errors.New("unsupported content encoding")
+	errBodyTooLarge        = errors.New("decompressed body too large")
+)
+
+func init() {
+	encoding.RegisterCompressor(zstdCompressor{})
+}
+
+// readBody reads an OTLP/HTTP request body, decompressing it according to
+// its Content-Encoding: gzip, zstd or none.
+func readBody(req *http.Request) ([]byte, error) {
+	var r io.Reader
+	switch enc := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding"))); enc {
+	case "", "identity":
+		return io.ReadAll(req.Body)
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	case "zstd":
+		zr, err := zstd.NewReader(req.Body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxDecompressedBody))
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("%w %q", errUnsupportedEncoding, enc)
+	}
+
+	return readDecompressed(r)
+}
+
+// readDecompressed reads a decompressing reader up to maxDecompressedBody.
+func readDecompressed(r io.Reader) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, maxDecompressedBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDecompressedBody {
+		return nil, errBodyTooLarge
+	}
+	return body, nil
+}
+
+// bodyErrorStatus maps a readBody error to the HTTP status to reply with.
+func bodyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errUnsupportedEncoding):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, errBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// zstdCompressor is the gRPC "zstd" compressor, which grpc-go does not
+// ship. The server decompresses requests sent with it and answers in kind.
+type zstdCompressor struct{}
+
+func (zstdCompressor) Name() string { return "zstd" }
+
+func (zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+}
+
+func (zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxDecompressedBody))
+	if err != nil {
+		return nil, err
+	}
+	// Decode eagerly so the decoder can be closed; the gRPC message size
+	// limit is applied to what is returned.
+	defer d.Close()
+	data, err := readDecompressed(d)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
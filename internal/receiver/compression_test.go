@@ -0,0 +1,145 @@
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func compressGzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func compressZstd(t *testing.T, data []byte) []byte {
+	t.Helper()
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zw.Close()
+	return zw.EncodeAll(data, nil)
+}
+
+func TestOTLPReceiver_HTTPContentEncoding(t *testing.T) {
+	metrics, logs := TestPayload("sess-compressed", time.Now())
+	metricsBody, _ := proto.Marshal(metrics)
+	logsBody, _ := proto.Marshal(logs)
+
+	for _, tc := range []struct {
+		encoding string
+		compress func(*testing.T, []byte) []byte
+	}{
+		{"gzip", compressGzip},
+		{"zstd", compressZstd},
+	} {
+		t.Run(tc.encoding, func(t *testing.T) {
+			store := state.NewMemoryStore()
+			r := startTestHTTP(t, store, newTestPortMapper())
+			defer r.Stop()
+
+			for path, body := range map[string][]byte{"/v1/metrics": metricsBody, "/v1/logs": logsBody} {
+				req, _ := http.NewRequest(http.MethodPost, "http://"+r.Addr().String()+path, bytes.NewReader(tc.compress(t, body)))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				req.Header.Set("Content-Encoding", tc.encoding)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("POST %s: %v", path, err)
+				}
+				_ = resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("POST %s: status %d, want 200", path, resp.StatusCode)
+				}
+			}
+
+			s := store.GetSession("sess-compressed")
+			if s == nil || len(s.Metrics) != 1 || len(s.Events) != 1 {
+				t.Fatalf("compressed payloads should be stored, got %+v", s)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		r := startTestHTTP(t, state.NewMemoryStore(), newTestPortMapper())
+		defer r.Stop()
+
+		req, _ := http.NewRequest(http.MethodPost, "http://"+r.Addr().String()+"/v1/logs", bytes.NewReader(logsBody))
+		req.Header.Set("Content-Encoding", "br")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("status %d, want 415", resp.StatusCode)
+		}
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		r := startTestHTTP(t, state.NewMemoryStore(), newTestPortMapper())
+		defer r.Stop()
+
+		req, _ := http.NewRequest(http.MethodPost, "http://"+r.Addr().String()+"/v1/logs", bytes.NewReader(logsBody))
+		req.Header.Set("Content-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("status %d, want 400", resp.StatusCode)
+		}
+	})
+}
+
+func TestReadBody_DecompressedLimit(t *testing.T) {
+	big := compressGzip(t, make([]byte, maxDecompressedBody+1))
+	req, _ := http.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(big))
+	req.Header.Set("Content-Encoding", "gzip")
+	if _, err := readBody(req); bodyErrorStatus(err) != http.StatusRequestEntityTooLarge {
+		t.Errorf("err = %v, want a 413", err)
+	}
+}
+
+func TestOTLPReceiver_GRPCCompression(t *testing.T) {
+	for _, name := range []string{"gzip", "zstd"} {
+		t.Run(name, func(t *testing.T) {
+			store := state.NewMemoryStore()
+			recv, clients, conn := startTestGRPC(t, store, newTestPortMapper())
+			defer conn.Close()
+			defer recv.Stop()
+
+			metrics, logs := TestPayload("sess-grpc-"+name, time.Now())
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := clients.metrics.Export(ctx, metrics, grpc.UseCompressor(name)); err != nil {
+				t.Fatalf("metrics Export: %v", err)
+			}
+			if _, err := clients.logs.Export(ctx, logs, grpc.UseCompressor(name)); err != nil {
+				t.Fatalf("logs Export: %v", err)
+			}
+
+			s := store.GetSession("sess-grpc-" + name)
+			if s == nil || len(s.Metrics) != 1 || len(s.Events) != 1 {
+				t.Fatalf("compressed exports should be stored, got %+v", s)
+			}
+		})
+	}
+}
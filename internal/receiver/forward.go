@@ -0,0 +1,299 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// forwardQueueSize bounds the payloads waiting to be forwarded. When the
+// upstream collector falls behind, further payloads are dropped rather than
+// slowing down ingestion.
+const forwardQueueSize = 1000
+
+// forwardItem is one received export request; exactly one field is set.
+type forwardItem struct {
+	metrics *colmetricspb.ExportMetricsServiceRequest
+	logs    *collogspb.ExportLogsServiceRequest
+	traces  *coltracepb.ExportTraceServiceRequest
+}
+
+// Forwarder re-exports received OTLP payloads, unmodified, to an upstream
+// collector over OTLP/HTTP (protobuf) or gRPC. Payloads are sent in the
+// background in the order received; failures are logged and not retried.
+// All methods are safe to call on a nil *Forwarder, which forwards nothing.
+type Forwarder struct {
+	cfg      config.ForwardConfig
+	endpoint string // base URL without a trailing slash
+	timeout  time.Duration
+
+	httpClient *http.Client
+
+	conn    *grpc.ClientConn
+	metrics colmetricspb.MetricsServiceClient
+	logs    collogspb.LogsServiceClient
+	traces  coltracepb.TraceServiceClient
+
+	mu        sync.RWMutex // guards closing queue against concurrent sends
+	closed    bool
+	queue     chan forwardItem
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+
+	dropped atomic.Int64
+	failing atomic.Bool // the last send failed; used to log state changes only
+}
+
+// NewForwarder creates a forwarder for cfg. It returns nil without error when
+// forwarding is disabled, and an error when the TLS files cannot be loaded.
+func NewForwarder(cfg config.ForwardConfig) (*Forwarder, error) {
+	if cfg.Endpoint == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("forward endpoint: %w", err)
+	}
+
+	f := &Forwarder{
+		cfg:      cfg,
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		timeout:  time.Duration(cfg.TimeoutSeconds) * time.Second,
+		queue:    make(chan forwardItem, forwardQueueSize),
+		done:     make(chan struct{}),
+	}
+
+	var tlsCfg *tls.Config
+	if u.Scheme == "https" {
+		if tlsCfg, err = forwardTLSConfig(cfg.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Protocol == "grpc" {
+		creds := insecure.NewCredentials()
+		if tlsCfg != nil {
+			creds = credentials.NewTLS(tlsCfg)
+		}
+		conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("forward endpoint: %w", err)
+		}
+		f.conn = conn
+		f.metrics = colmetricspb.NewMetricsServiceClient(conn)
+		f.logs = collogspb.NewLogsServiceClient(conn)
+		f.traces = coltracepb.NewTraceServiceClient(conn)
+		return f, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	f.httpClient = &http.Client{Transport: transport, Timeout: f.timeout}
+	return f, nil
+}
+
+// forwardTLSConfig builds the client TLS configuration for an https endpoint.
+func forwardTLSConfig(c config.ForwardTLSConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("forward tls ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("forward tls ca_file %s: no PEM certificates found", c.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("forward tls client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+// Start begins sending queued payloads.
+func (f *Forwarder) Start() {
+	if f == nil {
+		return
+	}
+	f.startOnce.Do(func() {
+		log.Printf("Forwarding OTLP telemetry to %s (%s)", f.endpoint, f.cfg.Protocol)
+		go f.run()
+	})
+}
+
+// Stop stops accepting payloads and waits up to the send timeout for the
+// queued ones to be forwarded.
+func (f *Forwarder) Stop() {
+	if f == nil {
+		return
+	}
+	f.stopOnce.Do(func() {
+		f.startOnce.Do(func() { close(f.done) }) // never started: nothing to drain
+		f.mu.Lock()
+		f.closed = true
+		close(f.queue)
+		f.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-time.After(f.timeout):
+			log.Printf("WARNING: forward: %d payload(s) not sent before shutdown", len(f.queue))
+		}
+		if f.conn != nil {
+			_ = f.conn.Close()
+		}
+	})
+}
+
+// ForwardMetrics queues a metrics export request for forwarding.
+func (f *Forwarder) ForwardMetrics(req *colmetricspb.ExportMetricsServiceRequest) {
+	if f != nil {
+		f.enqueue(forwardItem{metrics: req})
+	}
+}
+
+// ForwardLogs queues a logs export request for forwarding.
+func (f *Forwarder) ForwardLogs(req *collogspb.ExportLogsServiceRequest) {
+	if f != nil {
+		f.enqueue(forwardItem{logs: req})
+	}
+}
+
+// ForwardTraces queues a trace export request for forwarding.
+func (f *Forwarder) ForwardTraces(req *coltracepb.ExportTraceServiceRequest) {
+	if f != nil {
+		f.enqueue(forwardItem{traces: req})
+	}
+}
+
+// SendMetrics exports req and waits for the collector's answer, bypassing
+// the queue. It works on a forwarder that was never started.
+func (f *Forwarder) SendMetrics(req *colmetricspb.ExportMetricsServiceRequest) error {
+	return f.send(forwardItem{metrics: req})
+}
+
+// SendLogs exports req and waits for the collector's answer, bypassing the
+// queue.
+func (f *Forwarder) SendLogs(req *collogspb.ExportLogsServiceRequest) error {
+	return f.send(forwardItem{logs: req})
+}
+
+// Dropped returns the number of payloads dropped because the queue was full.
+func (f *Forwarder) Dropped() int64 {
+	if f == nil {
+		return 0
+	}
+	return f.dropped.Load()
+}
+
+func (f *Forwarder) enqueue(item forwardItem) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		f.dropped.Add(1)
+		return
+	}
+	select {
+	case f.queue <- item:
+	default:
+		if f.dropped.Add(1) == 1 {
+			log.Printf("WARNING: forward: queue full, dropping payloads for %s", f.endpoint)
+		}
+	}
+}
+
+func (f *Forwarder) run() {
+	defer close(f.done)
+	for item := range f.queue {
+		err := f.send(item)
+		switch {
+		case err != nil && !f.failing.Swap(true):
+			log.Printf("WARNING: forward to %s failing: %v", f.endpoint, err)
+		case err == nil && f.failing.Swap(false):
+			log.Printf("Forward to %s recovered", f.endpoint)
+		}
+	}
+}
+
+func (f *Forwarder) send(item forwardItem) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+
+	if f.conn != nil {
+		if len(f.cfg.Headers) > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, metadata.New(f.cfg.Headers))
+		}
+		var err error
+		switch {
+		case item.metrics != nil:
+			_, err = f.metrics.Export(ctx, item.metrics)
+		case item.traces != nil:
+			_, err = f.traces.Export(ctx, item.traces)
+		default:
+			_, err = f.logs.Export(ctx, item.logs)
+		}
+		return err
+	}
+
+	var msg proto.Message = item.logs
+	path := "/v1/logs"
+	switch {
+	case item.metrics != nil:
+		msg, path = item.metrics, "/v1/metrics"
+	case item.traces != nil:
+		msg, path = item.traces, "/v1/traces"
+	}
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range f.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s%s: %s", f.endpoint, path, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package receiver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func forwardConfig(endpoint, protocol string) config.ForwardConfig {
+	return config.ForwardConfig{
+		Endpoint:       endpoint,
+		Protocol:       protocol,
+		Headers:        map[string]string{"authorization": "Bearer test"},
+		TimeoutSeconds: 5,
+	}
+}
+
+func TestForwarder_Disabled(t *testing.T) {
+	f, err := NewForwarder(config.ForwardConfig{Protocol: "http/protobuf", TimeoutSeconds: 5})
+	if err != nil || f != nil {
+		t.Fatalf("an empty endpoint should disable forwarding, got %v, %v", f, err)
+	}
+	// A nil forwarder is a no-op.
+	f.Start()
+	f.ForwardMetrics(makeCostMetricRequest("sess", 1))
+	f.Stop()
+}
+
+func TestForwarder_HTTPUpstream(t *testing.T) {
+	type received struct {
+		path, auth string
+		body       []byte
+	}
+	got := make(chan received, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		got <- received{path: req.URL.Path, auth: req.Header.Get("Authorization"), body: body}
+	}))
+	defer upstream.Close()
+
+	f, err := NewForwarder(forwardConfig(upstream.URL, "http/protobuf"))
+	if err != nil {
+		t.Fatalf("NewForwarder: %v", err)
+	}
+	f.Start()
+	defer f.Stop()
+
+	store := state.NewMemoryStore()
+	r := startTestHTTP(t, store, nil)
+	r.forwarder = f
+	defer r.Stop()
+
+	req := makeCostMetricRequest("sess-fwd-http", 2.5)
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(fmt.Sprintf("http://%s/v1/metrics", r.Addr()), "application/x-protobuf", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("HTTP POST failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if store.GetSession("sess-fwd-http") == nil {
+		t.Error("forwarding should not replace local ingestion")
+	}
+
+	select {
+	case rec := <-got:
+		if rec.path != "/v1/metrics" || rec.auth != "Bearer test" {
+			t.Errorf("upstream got path %q auth %q", rec.path, rec.auth)
+		}
+		var fwd colmetricspb.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(rec.body, &fwd); err != nil {
+			t.Fatalf("upstream payload: %v", err)
+		}
+		if !proto.Equal(&fwd, req) {
+			t.Error("the forwarded payload should equal the received one")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("payload was not forwarded")
+	}
+}
+
+func TestForwarder_GRPCUpstream(t *testing.T) {
+	// Another receiver plays the upstream collector.
+	upstreamStore := state.NewMemoryStore()
+	upstream, _, conn := startTestGRPC(t, upstreamStore, nil)
+	defer conn.Close()
+	defer upstream.Stop()
+
+	f, err := NewForwarder(forwardConfig("http://"+upstream.Addr().String(), "grpc"))
+	if err != nil {
+		t.Fatalf("NewForwarder: %v", err)
+	}
+	f.Start()
+	f.ForwardMetrics(makeCostMetricRequest("sess-fwd-grpc", 1.5))
+	f.Stop() // drains the queue
+
+	s := upstreamStore.GetSession("sess-fwd-grpc")
+	if s == nil || s.TotalCost != 1.5 {
+		t.Fatalf("upstream should have received the forwarded metric, got %+v", s)
+	}
+	if f.Dropped() != 0 {
+		t.Errorf("dropped = %d, want 0", f.Dropped())
+	}
+}
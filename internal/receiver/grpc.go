@@ -11,16 +11,18 @@ import (
 
 	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-// GRPCReceiver listens for OTLP metrics and log events via gRPC on the configured port.
-// It implements MetricsServiceServer for metrics. Log events are handled by an internal
-// grpcLogsHandler that implements LogsServiceServer separately, since both interfaces
-// define an Export method with different signatures.
+// GRPCReceiver listens for OTLP metrics, log events and traces via gRPC on the configured port.
+// It implements MetricsServiceServer for metrics. Log events and spans are handled by the internal
+// grpcLogsHandler and grpcTracesHandler, since all three interfaces define an Export method with
+// different signatures.
 type GRPCReceiver struct {
 	colmetricspb.UnimplementedMetricsServiceServer
 
@@ -28,6 +30,7 @@ type GRPCReceiver struct {
 	store      state.Store
 	portMapper PortMapper
 	logger     Logger
+	forwarder  *Forwarder
 	server     *grpc.Server
 	listener   net.Listener
 }
@@ -41,6 +44,7 @@ type grpcLogsHandler struct {
 	store      state.Store
 	portMapper PortMapper
 	logger     Logger
+	forwarder  *Forwarder
 }
 
 // NewGRPCReceiver creates a new gRPC-based OTLP metrics receiver.
@@ -58,21 +62,37 @@ func NewGRPCReceiver(cfg config.ReceiverConfig, store state.Store, portMapper Po
 func (r *GRPCReceiver) Start(ctx context.Context) error {
 	addr := fmt.Sprintf("%s:%d", r.cfg.Bind, r.cfg.GRPCPort)
 
+	tlsCfg, err := serverTLSConfig(r.cfg.TLS)
+	if err != nil {
+		return err
+	}
+	opts := tokenServerOptions(r.cfg.AuthToken)
+	if tlsCfg != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("port %d already in use", r.cfg.GRPCPort)
 	}
 	r.listener = lis
 
-	r.server = grpc.NewServer()
+	r.server = grpc.NewServer(opts...)
 	colmetricspb.RegisterMetricsServiceServer(r.server, r)
 	collogspb.RegisterLogsServiceServer(r.server, &grpcLogsHandler{
 		store:      r.store,
 		portMapper: r.portMapper,
 		logger:     r.logger,
+		forwarder:  r.forwarder,
+	})
+	coltracepb.RegisterTraceServiceServer(r.server, &grpcTracesHandler{
+		store:      r.store,
+		portMapper: r.portMapper,
+		logger:     r.logger,
+		forwarder:  r.forwarder,
 	})
 
-	log.Printf("OTLP gRPC receiver listening on %s", addr)
+	log.Printf("OTLP gRPC receiver listening on %s%s", addr, tlsNote(tlsCfg))
 
 	go func() {
 		if err := r.server.Serve(lis); err != nil {
@@ -114,6 +134,7 @@ func (r *GRPCReceiver) Export(ctx context.Context, req *colmetricspb.ExportMetri
 			extractMetrics(r.store, resource, sm.GetMetrics(), sourcePort, r.portMapper, r.logger)
 		}
 	}
+	r.forwarder.ForwardMetrics(req)
 
 	return &colmetricspb.ExportMetricsServiceResponse{}, nil
 }
@@ -133,6 +154,7 @@ func (h *grpcLogsHandler) Export(ctx context.Context, req *collogspb.ExportLogsS
 	}
 
 	processLogExport(h.store, h.portMapper, req, sourcePort, h.logger)
+	h.forwarder.ForwardLogs(req)
 
 	return &collogspb.ExportLogsServiceResponse{}, nil
 }
@@ -2,8 +2,10 @@ package receiver
 
 import (
 	"context"
+	"math"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -209,6 +211,43 @@ func TestOTLPReceiver_GRPCMetrics(t *testing.T) {
 	}
 }
 
+func TestOTLPReceiver_GRPCMetrics_InterleavedExporters(t *testing.T) {
+	store := state.NewMemoryStore()
+	r, clients, conn := startTestGRPC(t, store, nil)
+	defer func() {
+		conn.Close()
+		r.Stop()
+	}()
+
+	// Two processes export cumulative cost under one session ID, e.g. a
+	// forked subprocess that inherited the session's environment.
+	export := func(instance string, value float64) {
+		t.Helper()
+		req := makeCostMetricRequest("sess-dup", value)
+		res := req.ResourceMetrics[0].Resource
+		res.Attributes = append(res.Attributes, &commonpb.KeyValue{
+			Key:   "service.instance.id",
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: instance}},
+		})
+		if _, err := clients.metrics.Export(context.Background(), req); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+	export("proc-a", 5.0)
+	export("proc-b", 0.2)
+	export("proc-a", 5.1)
+	export("proc-b", 0.3)
+
+	session := store.GetSession("sess-dup")
+	if session == nil {
+		t.Fatal("expected session sess-dup to exist in store")
+	}
+	// $5.10 from one process and $0.30 from the other, not resets.
+	if math.Abs(session.TotalCost-5.4) > 1e-9 {
+		t.Errorf("expected TotalCost=5.40, got %f", session.TotalCost)
+	}
+}
+
 func TestOTLPReceiver_GRPCLogs(t *testing.T) {
 	store := state.NewMemoryStore()
 	pm := newTestPortMapper()
@@ -455,6 +494,11 @@ func TestOTLPReceiver_GRPCMetrics_ResourceMetadata(t *testing.T) {
 						{Key: "os.type", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "darwin"}}},
 						{Key: "os.version", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "24.1.0"}}},
 						{Key: "host.arch", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "arm64"}}},
+						{Key: "host.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "build-01"}}},
+						{Key: "service.instance.id", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "agent-7"}}},
+						{Key: "deployment.environment", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "prod"}}},
+						{Key: "cc_top.tags", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "experiment, demo,"}}},
+						{Key: "cc_top.expected_duration", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "45m"}}},
 					},
 				},
 				ScopeMetrics: []*metricspb.ScopeMetrics{
@@ -501,6 +545,21 @@ func TestOTLPReceiver_GRPCMetrics_ResourceMetadata(t *testing.T) {
 	if session.Metadata.HostArch != "arm64" {
 		t.Errorf("expected HostArch=arm64, got %q", session.Metadata.HostArch)
 	}
+	if session.Metadata.HostName != "build-01" {
+		t.Errorf("expected HostName=build-01, got %q", session.Metadata.HostName)
+	}
+	if session.Metadata.ServiceInstanceID != "agent-7" {
+		t.Errorf("expected ServiceInstanceID=agent-7, got %q", session.Metadata.ServiceInstanceID)
+	}
+	if session.Metadata.Environment != "prod" {
+		t.Errorf("expected Environment=prod, got %q", session.Metadata.Environment)
+	}
+	if got := strings.Join(session.Metadata.Tags, ","); got != "experiment,demo" {
+		t.Errorf("expected Tags=experiment,demo, got %q", got)
+	}
+	if session.Metadata.ExpectedDuration != 45*time.Minute {
+		t.Errorf("expected ExpectedDuration=45m, got %v", session.Metadata.ExpectedDuration)
+	}
 
 	// session.id extraction should still work.
 	if session.SessionID != "sess-meta-grpc" {
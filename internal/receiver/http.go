@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
+	"mime"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/nixlim/cc-top/internal/config"
@@ -17,18 +18,21 @@ import (
 	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
 	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
 	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
 	"google.golang.org/protobuf/proto"
 )
 
-// HTTPReceiver listens for OTLP log exports via HTTP POST on the configured port.
-// It supports both protobuf and JSON content types as specified by the OTLP/HTTP
-// protocol, and extracts session.id and source port information from each request.
+// HTTPReceiver listens for OTLP log, metric and trace exports via HTTP POST on the
+// configured port.
+// It supports both protobuf and JSON content types, uncompressed or with gzip
+// or zstd content encoding, as specified by the OTLP/HTTP protocol, and extracts session.id and source port information from each request.
 type HTTPReceiver struct {
 	cfg        config.ReceiverConfig
 	store      state.Store
 	portMapper PortMapper
 	logger     Logger
+	forwarder  *Forwarder
 	server     *http.Server
 	listener   net.Listener
 }
@@ -48,26 +52,35 @@ func NewHTTPReceiver(cfg config.ReceiverConfig, store state.Store, portMapper Po
 func (r *HTTPReceiver) Start(ctx context.Context) error {
 	addr := fmt.Sprintf("%s:%d", r.cfg.Bind, r.cfg.HTTPPort)
 
+	tlsCfg, err := serverTLSConfig(r.cfg.TLS)
+	if err != nil {
+		return err
+	}
+
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("port %d already in use", r.cfg.HTTPPort)
 	}
 	r.listener = lis
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/v1/logs", r.handleLogs)
-	mux.HandleFunc("/v1/metrics", r.handleMetrics)
-
 	r.server = &http.Server{
-		Handler:      mux,
+		Handler:      r.routes(),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
+		TLSConfig:    tlsCfg,
 	}
 
-	log.Printf("OTLP HTTP receiver listening on %s", addr)
+	log.Printf("OTLP HTTP receiver listening on %s%s", addr, tlsNote(tlsCfg))
 
 	go func() {
-		if err := r.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsCfg != nil {
+			// The certificate is already in TLSConfig.
+			err = r.server.ServeTLS(lis, "", "")
+		} else {
+			err = r.server.Serve(lis)
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Printf("HTTP server stopped: %v", err)
 		}
 	}()
@@ -75,6 +88,17 @@ func (r *HTTPReceiver) Start(ctx context.Context) error {
 	return nil
 }
 
+// routes returns the handler for all HTTP endpoints served by the receiver,
+// behind the bearer token check when auth_token is set.
+func (r *HTTPReceiver) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", r.handleLogs)
+	mux.HandleFunc("/v1/metrics", r.handleMetrics)
+	mux.HandleFunc("/v1/traces", r.handleTraces)
+	mux.HandleFunc("/v1/annotations", r.handleAnnotations)
+	return requireToken(r.cfg.AuthToken, mux)
+}
+
 // Stop gracefully shuts down the HTTP server with a 5-second deadline
 // for in-flight requests to complete.
 func (r *HTTPReceiver) Stop() {
@@ -96,10 +120,10 @@ func (r *HTTPReceiver) handleLogs(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	body, err := io.ReadAll(req.Body)
+	body, err := readBody(req)
 	if err != nil {
 		logReceiveError("HTTP", "reading request body", err)
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), bodyErrorStatus(err))
 		return
 	}
 	defer req.Body.Close()
@@ -119,6 +143,7 @@ func (r *HTTPReceiver) handleLogs(w http.ResponseWriter, req *http.Request) {
 	}
 
 	processLogExport(r.store, r.portMapper, exportReq, sourcePort, r.logger)
+	r.forwarder.ForwardLogs(exportReq)
 
 	// Return success response.
 	w.Header().Set("Content-Type", "application/json")
@@ -131,12 +156,11 @@ func (r *HTTPReceiver) handleLogs(w http.ResponseWriter, req *http.Request) {
 func (r *HTTPReceiver) decodeLogsRequest(contentType string, body []byte) (*collogspb.ExportLogsServiceRequest, error) {
 	exportReq := &collogspb.ExportLogsServiceRequest{}
 
-	switch contentType {
-	case "application/json":
+	if isJSONContent(contentType) {
 		if err := decodeLogsJSON(body, exportReq); err != nil {
 			return nil, fmt.Errorf("JSON decode: %w", err)
 		}
-	default:
+	} else {
 		// Default to protobuf (application/x-protobuf or empty content type).
 		if err := proto.Unmarshal(body, exportReq); err != nil {
 			return nil, fmt.Errorf("protobuf decode: %w", err)
@@ -146,8 +170,8 @@ func (r *HTTPReceiver) decodeLogsRequest(contentType string, body []byte) (*coll
 	return exportReq, nil
 }
 
-// handleMetrics processes incoming OTLP HTTP metric export requests.
-// It accepts application/x-protobuf content type (default).
+// handleMetrics processes incoming OTLP HTTP metric export requests. It
+// accepts both application/x-protobuf and application/json content types.
 // Invalid payloads receive an HTTP 400 response; the server continues operating.
 func (r *HTTPReceiver) handleMetrics(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
@@ -155,10 +179,10 @@ func (r *HTTPReceiver) handleMetrics(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	body, err := io.ReadAll(req.Body)
+	body, err := readBody(req)
 	if err != nil {
 		logReceiveError("HTTP", "reading metrics request body", err)
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), bodyErrorStatus(err))
 		return
 	}
 	defer req.Body.Close()
@@ -183,6 +207,7 @@ func (r *HTTPReceiver) handleMetrics(w http.ResponseWriter, req *http.Request) {
 			extractMetrics(r.store, resource, sm.GetMetrics(), sourcePort, r.portMapper, r.logger)
 		}
 	}
+	r.forwarder.ForwardMetrics(exportReq)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -190,18 +215,111 @@ func (r *HTTPReceiver) handleMetrics(w http.ResponseWriter, req *http.Request) {
 }
 
 // decodeMetricsRequest parses the metrics request body based on content type.
-// Supports application/x-protobuf (default).
+// Supports application/x-protobuf (default) and application/json.
 func (r *HTTPReceiver) decodeMetricsRequest(contentType string, body []byte) (*colmetricspb.ExportMetricsServiceRequest, error) {
 	exportReq := &colmetricspb.ExportMetricsServiceRequest{}
 
-	// Default to protobuf decoding.
-	if err := proto.Unmarshal(body, exportReq); err != nil {
-		return nil, fmt.Errorf("protobuf decode: %w", err)
+	if isJSONContent(contentType) {
+		if err := decodeMetricsJSON(body, exportReq); err != nil {
+			return nil, fmt.Errorf("JSON decode: %w", err)
+		}
+	} else {
+		// Default to protobuf (application/x-protobuf or empty content type).
+		if err := proto.Unmarshal(body, exportReq); err != nil {
+			return nil, fmt.Errorf("protobuf decode: %w", err)
+		}
 	}
 
 	return exportReq, nil
 }
 
+// isJSONContent reports whether a Content-Type header selects the OTLP JSON
+// encoding, ignoring parameters such as charset.
+func isJSONContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// decodeMetricsJSON decodes a JSON-encoded OTLP metrics export request.
+// Only sum and gauge metrics are converted; other types are skipped just as
+// they are for protobuf requests.
+func decodeMetricsJSON(body []byte, out *colmetricspb.ExportMetricsServiceRequest) error {
+	var raw jsonExportMetricsRequest
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+
+	for _, rm := range raw.ResourceMetrics {
+		resourceMetrics := &metricspb.ResourceMetrics{}
+
+		if rm.Resource != nil {
+			resourceMetrics.Resource = &resourcepb.Resource{}
+			for _, attr := range rm.Resource.Attributes {
+				resourceMetrics.Resource.Attributes = append(resourceMetrics.Resource.Attributes,
+					jsonAttrToKV(attr))
+			}
+		}
+
+		for _, sm := range rm.ScopeMetrics {
+			scopeMetrics := &metricspb.ScopeMetrics{}
+			for _, jm := range sm.Metrics {
+				metric := &metricspb.Metric{Name: jm.Name, Unit: jm.Unit}
+				switch {
+				case jm.Sum != nil:
+					points, err := jsonDataPoints(jm.Sum.DataPoints)
+					if err != nil {
+						return fmt.Errorf("metric %s: %w", jm.Name, err)
+					}
+					metric.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+						DataPoints:             points,
+						AggregationTemporality: metricspb.AggregationTemporality(jm.Sum.AggregationTemporality),
+						IsMonotonic:            jm.Sum.IsMonotonic,
+					}}
+				case jm.Gauge != nil:
+					points, err := jsonDataPoints(jm.Gauge.DataPoints)
+					if err != nil {
+						return fmt.Errorf("metric %s: %w", jm.Name, err)
+					}
+					metric.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: points}}
+				}
+				scopeMetrics.Metrics = append(scopeMetrics.Metrics, metric)
+			}
+			resourceMetrics.ScopeMetrics = append(resourceMetrics.ScopeMetrics, scopeMetrics)
+		}
+
+		out.ResourceMetrics = append(out.ResourceMetrics, resourceMetrics)
+	}
+
+	return nil
+}
+
+// jsonDataPoints converts JSON number data points to proto. OTLP JSON
+// encodes asInt (int64) as a string and asDouble as a number.
+func jsonDataPoints(in []jsonNumberDataPoint) ([]*metricspb.NumberDataPoint, error) {
+	points := make([]*metricspb.NumberDataPoint, 0, len(in))
+	for _, dp := range in {
+		point := &metricspb.NumberDataPoint{
+			StartTimeUnixNano: dp.StartTimeUnixNano,
+			TimeUnixNano:      dp.TimeUnixNano,
+		}
+		switch {
+		case dp.AsDouble != nil:
+			point.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: *dp.AsDouble}
+		case dp.AsInt != nil:
+			v, err := strconv.ParseInt(*dp.AsInt, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("asInt %q: %w", *dp.AsInt, err)
+			}
+			point.Value = &metricspb.NumberDataPoint_AsInt{AsInt: v}
+		}
+		for _, attr := range dp.Attributes {
+			point.Attributes = append(point.Attributes, jsonAttrToKV(attr))
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
 // decodeLogsJSON decodes a JSON-encoded OTLP logs export request.
 // This handles the simplified JSON representation used by OTLP/HTTP.
 func decodeLogsJSON(body []byte, out *collogspb.ExportLogsServiceRequest) error {
@@ -291,6 +409,46 @@ type jsonLogRecord struct {
 	Attributes   []jsonKeyValue `json:"attributes"`
 }
 
+// JSON types for OTLP/HTTP metric export decoding.
+
+type jsonExportMetricsRequest struct {
+	ResourceMetrics []jsonResourceMetrics `json:"resourceMetrics"`
+}
+
+type jsonResourceMetrics struct {
+	Resource     *jsonResource      `json:"resource"`
+	ScopeMetrics []jsonScopeMetrics `json:"scopeMetrics"`
+}
+
+type jsonScopeMetrics struct {
+	Metrics []jsonMetric `json:"metrics"`
+}
+
+type jsonMetric struct {
+	Name  string     `json:"name"`
+	Unit  string     `json:"unit"`
+	Sum   *jsonSum   `json:"sum"`
+	Gauge *jsonGauge `json:"gauge"`
+}
+
+type jsonSum struct {
+	DataPoints             []jsonNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int32                 `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type jsonGauge struct {
+	DataPoints []jsonNumberDataPoint `json:"dataPoints"`
+}
+
+type jsonNumberDataPoint struct {
+	StartTimeUnixNano uint64         `json:"startTimeUnixNano,string"`
+	TimeUnixNano      uint64         `json:"timeUnixNano,string"`
+	AsDouble          *float64       `json:"asDouble"`
+	AsInt             *string        `json:"asInt"`
+	Attributes        []jsonKeyValue `json:"attributes"`
+}
+
 type jsonKeyValue struct {
 	Key   string       `json:"key"`
 	Value jsonAnyValue `json:"value"`
@@ -39,11 +39,8 @@ func startTestHTTP(t *testing.T, store state.Store, pm PortMapper) *HTTPReceiver
 	}
 	r.listener = lis
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/v1/logs", r.handleLogs)
-	mux.HandleFunc("/v1/metrics", r.handleMetrics)
 	r.server = &http.Server{
-		Handler:      mux,
+		Handler:      r.routes(),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -424,6 +421,98 @@ func TestOTLPReceiver_HTTPMetrics(t *testing.T) {
 		}
 	})
 
+	t.Run("valid_json_returns_200", func(t *testing.T) {
+		store := state.NewMemoryStore()
+		r := startTestHTTP(t, store, nil)
+		defer r.Stop()
+
+		ts := fmt.Sprintf("%d", time.Now().UnixNano())
+		jsonBody := map[string]any{
+			"resourceMetrics": []map[string]any{
+				{
+					"resource": map[string]any{
+						"attributes": []map[string]any{
+							{"key": "session.id", "value": map[string]any{"stringValue": "sess-json-metrics"}},
+						},
+					},
+					"scopeMetrics": []map[string]any{
+						{
+							"metrics": []map[string]any{
+								{
+									"name": "claude_code.cost.usage",
+									"sum": map[string]any{
+										"aggregationTemporality": 2,
+										"isMonotonic":            true,
+										"dataPoints": []map[string]any{
+											{"timeUnixNano": ts, "asDouble": 0.40},
+										},
+									},
+								},
+								{
+									"name": "claude_code.token.usage",
+									"sum": map[string]any{
+										"dataPoints": []map[string]any{
+											{
+												"timeUnixNano": ts,
+												"asInt":        "1500",
+												"attributes": []map[string]any{
+													{"key": "type", "value": map[string]any{"stringValue": "input"}},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		body, err := json.Marshal(jsonBody)
+		if err != nil {
+			t.Fatalf("failed to marshal JSON: %v", err)
+		}
+
+		url := fmt.Sprintf("http://%s/v1/metrics", r.Addr().String())
+		resp, err := http.Post(url, "application/json; charset=utf-8", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("HTTP POST failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", resp.StatusCode)
+		}
+
+		session := store.GetSession("sess-json-metrics")
+		if session == nil {
+			t.Fatal("expected session sess-json-metrics to exist")
+		}
+		if session.TotalCost != 0.40 {
+			t.Errorf("expected TotalCost=0.40, got %f", session.TotalCost)
+		}
+		if session.TotalTokens != 1500 {
+			t.Errorf("expected TotalTokens=1500, got %d", session.TotalTokens)
+		}
+	})
+
+	t.Run("invalid_json_returns_400", func(t *testing.T) {
+		store := state.NewMemoryStore()
+		r := startTestHTTP(t, store, nil)
+		defer r.Stop()
+
+		url := fmt.Sprintf("http://%s/v1/metrics", r.Addr().String())
+		resp, err := http.Post(url, "application/json", bytes.NewReader([]byte(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"x","gauge":{"dataPoints":[{"asInt":"nope"}]}}]}]}]}`)))
+		if err != nil {
+			t.Fatalf("HTTP POST failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
 	t.Run("GET_returns_405", func(t *testing.T) {
 		store := state.NewMemoryStore()
 		r := startTestHTTP(t, store, nil)
@@ -1,6 +1,7 @@
 package receiver
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,6 +19,9 @@ type Logger interface {
 
 	// LogMetric logs a received OTEL metric with its session ID and attributes.
 	LogMetric(sessionID string, metric state.Metric)
+
+	// LogSpan logs a received trace span with its session ID and attributes.
+	LogSpan(sessionID string, span state.Span)
 }
 
 // NopLogger discards all log output. This is the default when debug logging
@@ -30,6 +34,9 @@ func (NopLogger) LogEvent(string, state.Event) {}
 // LogMetric is a no-op.
 func (NopLogger) LogMetric(string, state.Metric) {}
 
+// LogSpan is a no-op.
+func (NopLogger) LogSpan(string, state.Span) {}
+
 // logEntry is the JSON structure written by FileLogger.
 type logEntry struct {
 	Timestamp  string            `json:"ts"`
@@ -90,6 +97,22 @@ func (l *FileLogger) LogMetric(sessionID string, m state.Metric) {
 	l.write(entry)
 }
 
+// LogSpan writes a JSON line for a received span, timestamped with its
+// start; the value is its duration in milliseconds.
+func (l *FileLogger) LogSpan(sessionID string, sp state.Span) {
+	ms := float64(sp.Duration()) / float64(time.Millisecond)
+	entry := logEntry{
+		Timestamp:  sp.Start.UTC().Format(time.RFC3339Nano),
+		Type:       "span",
+		SessionID:  sessionID,
+		Name:       sp.Name,
+		Value:      &ms,
+		Attributes: sp.Attributes,
+	}
+
+	l.write(entry)
+}
+
 // write serialises a logEntry as JSON and writes it as a single line.
 // Serialisation errors are silently dropped to avoid disrupting the receiver.
 func (l *FileLogger) write(entry logEntry) {
@@ -102,3 +125,49 @@ func (l *FileLogger) write(entry logEntry) {
 	defer l.mu.Unlock()
 	fmt.Fprintf(l.w, "%s\n", data)
 }
+
+// LogRecord is a metric or event read back from a debug log. Exactly one of
+// Metric and Event is set.
+type LogRecord struct {
+	SessionID string
+	Metric    *state.Metric
+	Event     *state.Event
+}
+
+// ReadLog reads the metrics and events of a debug log written by
+// FileLogger, in file order. Spans are skipped.
+func ReadLog(r io.Reader) ([]LogRecord, error) {
+	var records []LogRecord
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var entry logEntry
+		if err := json.Unmarshal(sc.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: timestamp: %w", line, err)
+		}
+		switch entry.Type {
+		case "metric":
+			if entry.Value == nil {
+				return nil, fmt.Errorf("line %d: metric without a value", line)
+			}
+			records = append(records, LogRecord{SessionID: entry.SessionID, Metric: &state.Metric{
+				Name: entry.Name, Value: *entry.Value, Attributes: entry.Attributes, Timestamp: ts,
+			}})
+		case "event":
+			records = append(records, LogRecord{SessionID: entry.SessionID, Event: &state.Event{
+				Name: entry.Name, Attributes: entry.Attributes, Timestamp: ts,
+			}})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -207,3 +207,31 @@ func TestFileLogger_ZeroTimestamp(t *testing.T) {
 // Verify Logger interface compliance at compile time.
 var _ Logger = NopLogger{}
 var _ Logger = (*FileLogger)(nil)
+
+func TestReadLog_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewFileLogger(&buf)
+	ts := time.Date(2026, 2, 15, 10, 30, 0, 0, time.UTC)
+	l.LogMetric("sess-1", state.Metric{Name: "claude_code.cost.usage", Value: 0.25, Attributes: map[string]string{"model": "opus"}, Timestamp: ts})
+	l.LogSpan("sess-1", state.Span{Name: "claude_code.tool", Start: ts, End: ts})
+	l.LogEvent("sess-2", state.Event{Name: "claude_code.api_request", Timestamp: ts.Add(time.Second)})
+	buf.WriteString("\n")
+
+	records, err := ReadLog(&buf)
+	if err != nil {
+		t.Fatalf("ReadLog: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want the metric and the event", len(records))
+	}
+	if m := records[0].Metric; records[0].SessionID != "sess-1" || m == nil || m.Value != 0.25 || m.Attributes["model"] != "opus" || !m.Timestamp.Equal(ts) {
+		t.Errorf("metric = %+v", records[0])
+	}
+	if e := records[1].Event; records[1].SessionID != "sess-2" || e == nil || e.Name != "claude_code.api_request" || !e.Timestamp.Equal(ts.Add(time.Second)) {
+		t.Errorf("event = %+v", records[1])
+	}
+
+	if _, err := ReadLog(strings.NewReader("{\"ts\": \"x\"}\n")); err == nil {
+		t.Error("a bad timestamp should be an error")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nixlim/cc-top/internal/config"
@@ -32,9 +33,12 @@ type PortMapper interface {
 
 // Receiver manages both gRPC and HTTP OTLP receivers.
 type Receiver struct {
-	grpc   *GRPCReceiver
-	http   *HTTPReceiver
-	logger Logger
+	grpc      *GRPCReceiver
+	http      *HTTPReceiver
+	logger    Logger
+	admission *Admission
+	redactor  *Redactor
+	forwarder *Forwarder
 }
 
 // ReceiverOption configures the Receiver.
@@ -49,6 +53,30 @@ func WithLogger(l Logger) ReceiverOption {
 	}
 }
 
+// WithAdmission filters received telemetry through a, so only admitted
+// sessions reach the store.
+func WithAdmission(a *Admission) ReceiverOption {
+	return func(r *Receiver) {
+		r.admission = a
+	}
+}
+
+// WithRedactor redacts the attributes of received metrics and events with
+// r before they reach the store. A nil r disables redaction.
+func WithRedactor(r *Redactor) ReceiverOption {
+	return func(rc *Receiver) {
+		rc.redactor = r
+	}
+}
+
+// WithForwarder re-exports every received payload through f. Payloads are
+// forwarded as received, before admission filtering and redaction.
+func WithForwarder(f *Forwarder) ReceiverOption {
+	return func(r *Receiver) {
+		r.forwarder = f
+	}
+}
+
 // New creates a new Receiver with gRPC and HTTP endpoints configured from cfg.
 // The store is used to persist received metrics and events.
 // portMapper may be nil if port correlation is not needed.
@@ -59,8 +87,17 @@ func New(cfg config.ReceiverConfig, store state.Store, portMapper PortMapper, op
 	for _, opt := range opts {
 		opt(r)
 	}
+	if r.redactor != nil {
+		store = redactingStore{Store: store, redactor: r.redactor}
+	}
+	if r.admission != nil {
+		store = admittingStore{Store: store, admission: r.admission}
+		portMapper = admissionPortMapper{admission: r.admission, next: portMapper}
+	}
 	r.grpc = NewGRPCReceiver(cfg, store, portMapper, r.logger)
 	r.http = NewHTTPReceiver(cfg, store, portMapper, r.logger)
+	r.grpc.forwarder = r.forwarder
+	r.http.forwarder = r.forwarder
 	return r
 }
 
@@ -75,6 +112,7 @@ func (r *Receiver) Start(ctx context.Context) error {
 		r.grpc.Stop()
 		return err
 	}
+	r.forwarder.Start()
 	return nil
 }
 
@@ -83,6 +121,7 @@ func (r *Receiver) Start(ctx context.Context) error {
 func (r *Receiver) Stop() {
 	r.grpc.Stop()
 	r.http.Stop()
+	r.forwarder.Stop()
 }
 
 // extractSessionID searches for session.id in resource attributes first,
@@ -150,11 +189,34 @@ func extractResourceMetadata(resource *resourcepb.Resource) state.SessionMetadat
 			meta.OSVersion = anyValueToString(kv.GetValue())
 		case "host.arch":
 			meta.HostArch = anyValueToString(kv.GetValue())
+		case "host.name":
+			meta.HostName = anyValueToString(kv.GetValue())
+		case "service.instance.id":
+			meta.ServiceInstanceID = anyValueToString(kv.GetValue())
+		case "deployment.environment", "deployment.environment.name":
+			meta.Environment = anyValueToString(kv.GetValue())
+		case "cc_top.tags":
+			meta.Tags = parseTags(anyValueToString(kv.GetValue()))
+		case "cc_top.expected_duration":
+			if d, err := time.ParseDuration(anyValueToString(kv.GetValue())); err == nil && d > 0 {
+				meta.ExpectedDuration = d
+			}
 		}
 	}
 	return meta
 }
 
+// parseTags splits a comma-separated tag list, dropping empty entries.
+func parseTags(v string) []string {
+	var tags []string
+	for _, t := range strings.Split(v, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
 // extractMetrics converts OTLP metric data points into state.Metric values
 // and stores them in the state store, keyed by session ID.
 func extractMetrics(store state.Store, resource *resourcepb.Resource, metrics []*metricspb.Metric, sourcePort int, portMapper PortMapper, logger Logger) {
@@ -199,10 +261,18 @@ func extractMetrics(store state.Store, resource *resourcepb.Resource, metrics []
 				ts = time.Now()
 			}
 
+			attrs := kvToMap(dp.GetAttributes())
+			// Counters are tracked per attribute set, so tagging each data
+			// point with its exporter keeps two processes sending under one
+			// session ID from reading each other's values as resets.
+			if _, ok := attrs["service.instance.id"]; !ok && meta.ServiceInstanceID != "" {
+				attrs["service.instance.id"] = meta.ServiceInstanceID
+			}
+
 			sm := state.Metric{
 				Name:       m.GetName(),
 				Value:      value,
-				Attributes: kvToMap(dp.GetAttributes()),
+				Attributes: attrs,
 				Timestamp:  ts,
 			}
 
@@ -0,0 +1,107 @@
+package receiver
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Built-in redaction patterns. apiKeyPattern covers Anthropic and OpenAI
+// style keys, AWS access key IDs, GitHub and Slack tokens, Google API keys
+// and bearer tokens.
+var (
+	apiKeyPattern = regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,}|AIza[0-9A-Za-z_-]{35})|(?i:bearer\s+)[A-Za-z0-9._~+/-]{20,}=*`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+)
+
+// Redactor replaces sensitive substrings of attribute values, per the
+// [receiver.redaction] config. It is safe for concurrent use.
+type Redactor struct {
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// NewRedactor creates a Redactor from cfg. It returns nil when redaction is
+// disabled or cfg enables no pattern, and an error if a pattern is invalid.
+func NewRedactor(cfg config.RedactionConfig) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	r := &Redactor{replacement: cfg.Replacement}
+	if cfg.APIKeys {
+		r.patterns = append(r.patterns, apiKeyPattern)
+	}
+	if cfg.Emails {
+		r.patterns = append(r.patterns, emailPattern)
+	}
+	for _, dir := range cfg.SensitiveDirs {
+		r.patterns = append(r.patterns, dirPattern(dir))
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	if len(r.patterns) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// dirPattern matches dir, written with or without a leading ~, and any
+// path beneath it.
+func dirPattern(dir string) *regexp.Regexp {
+	dir = strings.TrimRight(strings.TrimSpace(dir), `/\`)
+	forms := []string{regexp.QuoteMeta(dir)}
+	if expanded := filepath.ToSlash(pathutil.ExpandHome(dir)); expanded != dir {
+		forms = append(forms, regexp.QuoteMeta(expanded), regexp.QuoteMeta(filepath.FromSlash(expanded)))
+	}
+	return regexp.MustCompile(`(?:` + strings.Join(forms, "|") + `)(?:[/\\][^\s"'` + "`" + `]*|\b|$)`)
+}
+
+// Redact returns s with every match of the patterns replaced.
+func (r *Redactor) Redact(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllLiteralString(s, r.replacement)
+	}
+	return s
+}
+
+// RedactAttributes redacts the values of attrs in place.
+func (r *Redactor) RedactAttributes(attrs map[string]string) {
+	for k, v := range attrs {
+		if red := r.Redact(v); red != v {
+			attrs[k] = red
+		}
+	}
+}
+
+// redactingStore redacts the attributes of metrics, events and spans
+// before they reach the wrapped store. Attributes are redacted in place, so
+// the debug log shows the redacted values too.
+type redactingStore struct {
+	state.Store
+	redactor *Redactor
+}
+
+func (s redactingStore) AddMetric(sessionID string, m state.Metric) {
+	s.redactor.RedactAttributes(m.Attributes)
+	s.Store.AddMetric(sessionID, m)
+}
+
+func (s redactingStore) AddEvent(sessionID string, e state.Event) {
+	s.redactor.RedactAttributes(e.Attributes)
+	s.Store.AddEvent(sessionID, e)
+}
+
+func (s redactingStore) AddSpan(sessionID string, sp state.Span) {
+	s.redactor.RedactAttributes(sp.Attributes)
+	s.Store.AddSpan(sessionID, sp)
+}
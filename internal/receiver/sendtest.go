@@ -0,0 +1,67 @@
+package receiver
+
+import (
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// TestServiceName is the service.name of the synthetic telemetry sent by
+// `cc-top send-test`.
+const TestServiceName = "cc-top-send-test"
+
+// TestPayload builds a synthetic batch for sessionID, shaped like Claude
+// Code's telemetry: a claude_code.session.count metric and a
+// claude_code.user_prompt event, both stamped now. It carries no cost or
+// tokens, so it does not change spend totals.
+func TestPayload(sessionID string, now time.Time) (*colmetricspb.ExportMetricsServiceRequest, *collogspb.ExportLogsServiceRequest) {
+	str := func(key, value string) *commonpb.KeyValue {
+		return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+	}
+	resource := &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+		str("service.name", TestServiceName),
+		str("session.id", sessionID),
+	}}
+	ts := uint64(now.UnixNano())
+
+	metrics := &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Metrics: []*metricspb.Metric{{
+					Name: "claude_code.session.count",
+					Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+						AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+						IsMonotonic:            true,
+						DataPoints: []*metricspb.NumberDataPoint{{
+							TimeUnixNano: ts,
+							Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: 1},
+						}},
+					}},
+				}},
+			}},
+		}},
+	}
+	logs := &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				LogRecords: []*logspb.LogRecord{{
+					TimeUnixNano: ts,
+					EventName:    "claude_code.user_prompt",
+					Body:         &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "claude_code.user_prompt"}},
+					Attributes: []*commonpb.KeyValue{
+						str("event.name", "user_prompt"),
+						str("prompt_length", "0"),
+					},
+				}},
+			}},
+		}},
+	}
+	return metrics, logs
+}
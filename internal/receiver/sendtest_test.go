@@ -0,0 +1,44 @@
+package receiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestTestPayload_RoundTrip(t *testing.T) {
+	store := state.NewMemoryStore()
+	recv, _, conn := startTestGRPC(t, store, nil)
+	defer conn.Close()
+	defer recv.Stop()
+
+	f, err := NewForwarder(forwardConfig("http://"+recv.Addr().String(), "grpc"))
+	if err != nil {
+		t.Fatalf("NewForwarder: %v", err)
+	}
+	defer f.Stop()
+
+	now := time.Now()
+	metrics, logs := TestPayload("send-test-1", now)
+	if err := f.SendMetrics(metrics); err != nil {
+		t.Fatalf("SendMetrics: %v", err)
+	}
+	if err := f.SendLogs(logs); err != nil {
+		t.Fatalf("SendLogs: %v", err)
+	}
+
+	s := store.GetSession("send-test-1")
+	if s == nil {
+		t.Fatal("the test session should be in the store")
+	}
+	if len(s.Metrics) != 1 || s.Metrics[0].Name != "claude_code.session.count" {
+		t.Errorf("metrics = %+v, want one claude_code.session.count", s.Metrics)
+	}
+	if len(s.Events) != 1 || s.Events[0].Name != "claude_code.user_prompt" {
+		t.Errorf("events = %+v, want one claude_code.user_prompt", s.Events)
+	}
+	if s.TotalCost != 0 || s.TotalTokens != 0 {
+		t.Errorf("the test payload should not add spend, got $%v and %d tokens", s.TotalCost, s.TotalTokens)
+	}
+}
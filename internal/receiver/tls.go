@@ -0,0 +1,53 @@
+package receiver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/pathutil"
+)
+
+// serverTLSConfig builds the TLS configuration both receivers serve with,
+// or returns nil when [receiver.tls] is not set. With a client CA file,
+// clients must present a certificate it signed.
+func serverTLSConfig(c config.ReceiverTLSConfig) (*tls.Config, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(pathutil.ExpandHome(c.CertFile), pathutil.ExpandHome(c.KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("receiver tls certificate: %w", err)
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(pathutil.ExpandHome(c.ClientCAFile))
+		if err != nil {
+			return nil, fmt.Errorf("receiver tls client_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("receiver tls client_ca_file %s: no PEM certificates found", c.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsCfg, nil
+}
+
+// tlsNote describes how a receiver is secured, for its startup log line.
+func tlsNote(tlsCfg *tls.Config) string {
+	switch {
+	case tlsCfg == nil:
+		return ""
+	case tlsCfg.ClientAuth == tls.RequireAndVerifyClientCert:
+		return " (mTLS)"
+	default:
+		return " (TLS)"
+	}
+}
@@ -0,0 +1,201 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/proto"
+)
+
+// testPKI is a CA with a server certificate for 127.0.0.1 and a client
+// certificate, written as PEM files to a temporary directory.
+type testPKI struct {
+	caFile, serverCert, serverKey string
+	roots                         *x509.CertPool
+	client                        tls.Certificate
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+	writePEM := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cc-top test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage, ips []net.IP) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "cc-top test"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  ips,
+		}, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, key
+	}
+
+	pki := testPKI{caFile: writePEM("ca.pem", "CERTIFICATE", caDER), roots: x509.NewCertPool()}
+	pki.roots.AddCert(ca)
+
+	serverDER, serverKey := issue(2, x509.ExtKeyUsageServerAuth, []net.IP{net.ParseIP("127.0.0.1")})
+	keyDER, err := x509.MarshalECPrivateKey(serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pki.serverCert = writePEM("server.pem", "CERTIFICATE", serverDER)
+	pki.serverKey = writePEM("server-key.pem", "EC PRIVATE KEY", keyDER)
+
+	clientDER, clientKey := issue(3, x509.ExtKeyUsageClientAuth, nil)
+	pki.client = tls.Certificate{Certificate: [][]byte{clientDER}, PrivateKey: clientKey}
+	return pki
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	if cfg, err := serverTLSConfig(config.ReceiverTLSConfig{}); cfg != nil || err != nil {
+		t.Errorf("without [receiver.tls]: %v, %v", cfg, err)
+	}
+
+	pki := newTestPKI(t)
+	cfg, err := serverTLSConfig(config.ReceiverTLSConfig{CertFile: pki.serverCert, KeyFile: pki.serverKey})
+	if err != nil || cfg.ClientAuth != tls.NoClientCert || tlsNote(cfg) != " (TLS)" {
+		t.Errorf("TLS: %v, %v", cfg, err)
+	}
+	cfg, err = serverTLSConfig(config.ReceiverTLSConfig{CertFile: pki.serverCert, KeyFile: pki.serverKey, ClientCAFile: pki.caFile})
+	if err != nil || cfg.ClientAuth != tls.RequireAndVerifyClientCert || tlsNote(cfg) != " (mTLS)" {
+		t.Errorf("mTLS: %v, %v", cfg, err)
+	}
+
+	for _, bad := range []config.ReceiverTLSConfig{
+		{CertFile: filepath.Join(t.TempDir(), "missing.pem"), KeyFile: pki.serverKey},
+		{CertFile: pki.serverCert, KeyFile: pki.serverKey, ClientCAFile: pki.serverKey},
+	} {
+		if _, err := serverTLSConfig(bad); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestHTTPReceiver_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	store := state.NewMemoryStore()
+	r := NewHTTPReceiver(config.ReceiverConfig{
+		Bind: "127.0.0.1",
+		TLS:  config.ReceiverTLSConfig{CertFile: pki.serverCert, KeyFile: pki.serverKey, ClientCAFile: pki.caFile},
+	}, store, nil, NopLogger{})
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer r.Stop()
+
+	body, err := proto.Marshal(makeCostMetricRequest("sess-tls", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := func(scheme string, certs []tls.Certificate) error {
+		client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pki.roots, Certificates: certs},
+		}}
+		resp, err := client.Post(scheme+"://"+r.Addr().String()+"/v1/metrics", "application/x-protobuf", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %s", resp.Status)
+		}
+		return nil
+	}
+
+	if err := post("http", nil); err == nil {
+		t.Error("plain HTTP should be refused")
+	}
+	if err := post("https", nil); err == nil {
+		t.Error("a client without a certificate should be refused")
+	}
+	if err := post("https", []tls.Certificate{pki.client}); err != nil {
+		t.Fatalf("with a client certificate: %v", err)
+	}
+	if s := store.GetSession("sess-tls"); s == nil || s.TotalCost != 1 {
+		t.Error("only the request with a client certificate should be stored")
+	}
+}
+
+func TestGRPCReceiver_TLS(t *testing.T) {
+	pki := newTestPKI(t)
+	store := state.NewMemoryStore()
+	r := NewGRPCReceiver(config.ReceiverConfig{
+		Bind: "127.0.0.1",
+		TLS:  config.ReceiverTLSConfig{CertFile: pki.serverCert, KeyFile: pki.serverKey},
+	}, store, nil, NopLogger{})
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer r.Stop()
+
+	conn, err := grpc.NewClient(r.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pki.roots})))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := colmetricspb.NewMetricsServiceClient(conn).Export(ctx, makeCostMetricRequest("sess-tls", 2)); err != nil {
+		t.Fatalf("Export over TLS: %v", err)
+	}
+	if s := store.GetSession("sess-tls"); s == nil || s.TotalCost != 2 {
+		t.Error("the export should be stored")
+	}
+}
@@ -0,0 +1,230 @@
+package receiver
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// grpcTracesHandler implements TraceServiceServer for the gRPC receiver. Like
+// grpcLogsHandler it is a separate type because of the conflicting Export
+// signatures.
+type grpcTracesHandler struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	store      state.Store
+	portMapper PortMapper
+	logger     Logger
+	forwarder  *Forwarder
+}
+
+// Export handles incoming ExportTraceServiceRequest RPCs, storing each span
+// under its session.
+func (h *grpcTracesHandler) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	sourcePort := 0
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		sourcePort = sourcePortFromAddr(p.Addr)
+	}
+
+	processTraceExport(h.store, h.portMapper, req, sourcePort, h.logger)
+	h.forwarder.ForwardTraces(req)
+
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// handleTraces processes incoming OTLP HTTP trace export requests, in
+// protobuf or JSON.
+func (r *HTTPReceiver) handleTraces(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		logReceiveError("HTTP", "reading traces request body", err)
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), bodyErrorStatus(err))
+		return
+	}
+	defer req.Body.Close()
+
+	sourcePort := 0
+	if req.RemoteAddr != "" {
+		addr := &netAddr{network: "tcp", addr: req.RemoteAddr}
+		sourcePort = sourcePortFromAddr(addr)
+	}
+
+	exportReq, err := decodeTracesRequest(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		logReceiveError("HTTP", "decoding traces payload", err)
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	processTraceExport(r.store, r.portMapper, exportReq, sourcePort, r.logger)
+	r.forwarder.ForwardTraces(exportReq)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{}"))
+}
+
+// decodeTracesRequest parses a trace export body based on the content type.
+func decodeTracesRequest(contentType string, body []byte) (*coltracepb.ExportTraceServiceRequest, error) {
+	exportReq := &coltracepb.ExportTraceServiceRequest{}
+
+	if isJSONContent(contentType) {
+		if err := decodeTracesJSON(body, exportReq); err != nil {
+			return nil, fmt.Errorf("JSON decode: %w", err)
+		}
+	} else {
+		if err := proto.Unmarshal(body, exportReq); err != nil {
+			return nil, fmt.Errorf("protobuf decode: %w", err)
+		}
+	}
+
+	return exportReq, nil
+}
+
+// processTraceExport extracts the spans of a trace export request and
+// stores them. It is shared by the gRPC and HTTP receivers.
+func processTraceExport(store state.Store, portMapper PortMapper, req *coltracepb.ExportTraceServiceRequest, sourcePort int, logger Logger) {
+	for _, rs := range req.GetResourceSpans() {
+		resource := rs.GetResource()
+
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				sessionID := extractSessionID(resource, s.GetAttributes())
+
+				if portMapper != nil && sessionID != "" && sourcePort > 0 {
+					portMapper.RecordSourcePort(sourcePort, sessionID)
+				}
+
+				sp := state.Span{
+					TraceID:      hex.EncodeToString(s.GetTraceId()),
+					SpanID:       hex.EncodeToString(s.GetSpanId()),
+					ParentSpanID: hex.EncodeToString(s.GetParentSpanId()),
+					Name:         s.GetName(),
+					Start:        time.Unix(0, int64(s.GetStartTimeUnixNano())),
+					End:          time.Unix(0, int64(s.GetEndTimeUnixNano())),
+					Failed:       s.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR,
+					Attributes:   kvToMap(s.GetAttributes()),
+				}
+				if s.GetStartTimeUnixNano() == 0 {
+					sp.Start = time.Now()
+				}
+				if sp.End.Before(sp.Start) {
+					sp.End = sp.Start
+				}
+
+				store.AddSpan(sessionID, sp)
+				logger.LogSpan(sessionID, sp)
+			}
+		}
+	}
+}
+
+// decodeTracesJSON decodes a JSON-encoded OTLP trace export request. OTLP
+// JSON encodes trace and span IDs as hex strings.
+func decodeTracesJSON(body []byte, out *coltracepb.ExportTraceServiceRequest) error {
+	var raw jsonExportTracesRequest
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+
+	for _, rs := range raw.ResourceSpans {
+		resourceSpans := &tracepb.ResourceSpans{}
+
+		if rs.Resource != nil {
+			resourceSpans.Resource = &resourcepb.Resource{}
+			for _, attr := range rs.Resource.Attributes {
+				resourceSpans.Resource.Attributes = append(resourceSpans.Resource.Attributes,
+					jsonAttrToKV(attr))
+			}
+		}
+
+		for _, ss := range rs.ScopeSpans {
+			scopeSpans := &tracepb.ScopeSpans{}
+			for _, js := range ss.Spans {
+				span := &tracepb.Span{
+					Name:              js.Name,
+					StartTimeUnixNano: js.StartTimeUnixNano,
+					EndTimeUnixNano:   js.EndTimeUnixNano,
+				}
+				var err error
+				if span.TraceId, err = hex.DecodeString(js.TraceID); err != nil {
+					return fmt.Errorf("span %s: traceId: %w", js.Name, err)
+				}
+				if span.SpanId, err = hex.DecodeString(js.SpanID); err != nil {
+					return fmt.Errorf("span %s: spanId: %w", js.Name, err)
+				}
+				if span.ParentSpanId, err = hex.DecodeString(js.ParentSpanID); err != nil {
+					return fmt.Errorf("span %s: parentSpanId: %w", js.Name, err)
+				}
+				if js.Status != nil {
+					span.Status = &tracepb.Status{
+						Code:    tracepb.Status_StatusCode(js.Status.Code),
+						Message: js.Status.Message,
+					}
+				}
+				for _, attr := range js.Attributes {
+					span.Attributes = append(span.Attributes, jsonAttrToKV(attr))
+				}
+				scopeSpans.Spans = append(scopeSpans.Spans, span)
+			}
+			resourceSpans.ScopeSpans = append(resourceSpans.ScopeSpans, scopeSpans)
+		}
+
+		out.ResourceSpans = append(out.ResourceSpans, resourceSpans)
+	}
+
+	return nil
+}
+
+// JSON types for OTLP/HTTP trace export decoding.
+
+type jsonExportTracesRequest struct {
+	ResourceSpans []jsonResourceSpans `json:"resourceSpans"`
+}
+
+type jsonResourceSpans struct {
+	Resource   *jsonResource    `json:"resource"`
+	ScopeSpans []jsonScopeSpans `json:"scopeSpans"`
+}
+
+type jsonScopeSpans struct {
+	Spans []jsonSpan `json:"spans"`
+}
+
+type jsonSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId"`
+	Name              string         `json:"name"`
+	StartTimeUnixNano uint64         `json:"startTimeUnixNano,string"`
+	EndTimeUnixNano   uint64         `json:"endTimeUnixNano,string"`
+	Attributes        []jsonKeyValue `json:"attributes"`
+	Status            *jsonStatus    `json:"status"`
+}
+
+type jsonStatus struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
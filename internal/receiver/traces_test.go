@@ -0,0 +1,150 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func makeTraceRequest(sessionID string, start time.Time) *coltracepb.ExportTraceServiceRequest {
+	str := func(v string) *commonpb.AnyValue {
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	}
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "session.id", Value: str(sessionID)},
+			}},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{
+					{
+						TraceId:           []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+						SpanId:            []byte{1, 1, 1, 1, 1, 1, 1, 1},
+						Name:              "claude_code.interaction",
+						StartTimeUnixNano: uint64(start.UnixNano()),
+						EndTimeUnixNano:   uint64(start.Add(3 * time.Second).UnixNano()),
+					},
+					{
+						TraceId:           []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+						SpanId:            []byte{2, 2, 2, 2, 2, 2, 2, 2},
+						ParentSpanId:      []byte{1, 1, 1, 1, 1, 1, 1, 1},
+						Name:              "claude_code.tool",
+						StartTimeUnixNano: uint64(start.Add(time.Second).UnixNano()),
+						EndTimeUnixNano:   uint64(start.Add(1500 * time.Millisecond).UnixNano()),
+						Status:            &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR},
+						Attributes: []*commonpb.KeyValue{
+							{Key: "tool_name", Value: str("Bash")},
+						},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func checkStoredSpans(t *testing.T, store state.Store, start time.Time) {
+	t.Helper()
+	session := store.GetSession("sess-trace")
+	if session == nil || len(session.Spans) != 2 {
+		t.Fatalf("expected 2 spans for sess-trace, got %+v", session)
+	}
+	root, tool := session.Spans[0], session.Spans[1]
+	if root.Name != "claude_code.interaction" || root.Duration() != 3*time.Second || root.ParentSpanID != "" {
+		t.Errorf("unexpected root span %+v", root)
+	}
+	if tool.ParentSpanID != root.SpanID || tool.SpanID != "0202020202020202" {
+		t.Errorf("tool span IDs = %q (parent %q), root %q", tool.SpanID, tool.ParentSpanID, root.SpanID)
+	}
+	if !tool.Failed || tool.Attributes["tool_name"] != "Bash" || !tool.Start.Equal(start.Add(time.Second)) {
+		t.Errorf("unexpected tool span %+v", tool)
+	}
+}
+
+func TestOTLPReceiver_GRPCTraces(t *testing.T) {
+	store := state.NewMemoryStore()
+	h := &grpcTracesHandler{store: store, logger: NopLogger{}}
+	start := time.Now().Truncate(time.Millisecond)
+
+	if _, err := h.Export(context.Background(), makeTraceRequest("sess-trace", start)); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	checkStoredSpans(t, store, start)
+
+	if _, err := h.Export(context.Background(), nil); err == nil {
+		t.Error("expected an error for an empty request")
+	}
+}
+
+func TestOTLPReceiver_HTTPTraces(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+
+	t.Run("protobuf", func(t *testing.T) {
+		store := state.NewMemoryStore()
+		pm := newTestPortMapper()
+		r := startTestHTTP(t, store, pm)
+		defer r.Stop()
+
+		body, err := proto.Marshal(makeTraceRequest("sess-trace", start))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.Post(fmt.Sprintf("http://%s/v1/traces", r.Addr()), "application/x-protobuf", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+		checkStoredSpans(t, store, start)
+		if len(pm.mappings) == 0 {
+			t.Error("expected the source port to be recorded")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		store := state.NewMemoryStore()
+		r := startTestHTTP(t, store, nil)
+		defer r.Stop()
+
+		body := fmt.Sprintf(`{"resourceSpans":[{"resource":{"attributes":[{"key":"session.id","value":{"stringValue":"sess-trace"}}]},
+"scopeSpans":[{"spans":[
+{"traceId":"0102030405060708090a0b0c0d0e0f10","spanId":"0101010101010101","name":"claude_code.interaction",
+ "startTimeUnixNano":"%d","endTimeUnixNano":"%d"},
+{"traceId":"0102030405060708090a0b0c0d0e0f10","spanId":"0202020202020202","parentSpanId":"0101010101010101",
+ "name":"claude_code.tool","startTimeUnixNano":"%d","endTimeUnixNano":"%d","status":{"code":2},
+ "attributes":[{"key":"tool_name","value":{"stringValue":"Bash"}}]}]}]}]}`,
+			start.UnixNano(), start.Add(3*time.Second).UnixNano(),
+			start.Add(time.Second).UnixNano(), start.Add(1500*time.Millisecond).UnixNano())
+		resp, err := http.Post(fmt.Sprintf("http://%s/v1/traces", r.Addr()), "application/json", bytes.NewReader([]byte(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+		checkStoredSpans(t, store, start)
+
+		resp, err = http.Post(fmt.Sprintf("http://%s/v1/traces", r.Addr()), "application/json",
+			bytes.NewReader([]byte(`{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"zz"}]}]}]}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("invalid trace ID: status = %d, want 400", resp.StatusCode)
+		}
+	})
+}
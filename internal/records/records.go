@@ -0,0 +1,270 @@
+// Package records tracks all-time spending and usage records: the most
+// expensive day, the highest hourly rate, the longest session and the
+// biggest single API request.
+package records
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Record names, in display order.
+const (
+	CostliestDay   = "costliest_day"
+	HourlyRate     = "hourly_rate"
+	LongestSession = "longest_session"
+	BiggestRequest = "biggest_request"
+)
+
+// Names lists every record in display order.
+var Names = []string{CostliestDay, HourlyRate, LongestSession, BiggestRequest}
+
+// refreshInterval bounds how often records are recomputed; the candidates
+// come from the daily history and every session's events.
+const refreshInterval = 30 * time.Second
+
+// historyDays is how far back the daily history is scanned for the most
+// expensive day when no record has been saved yet.
+const historyDays = 3650
+
+// Record is the best value seen for one record name.
+type Record struct {
+	Name       string
+	Value      float64 // USD for costs, USD/hr for HourlyRate, seconds for LongestSession
+	SessionID  string  // session that set the record, if any
+	Detail     string  // the date of CostliestDay, the model of BiggestRequest
+	AchievedAt time.Time
+}
+
+// Label returns a human-readable name for the record.
+func (r Record) Label() string {
+	switch r.Name {
+	case CostliestDay:
+		return "Most expensive day"
+	case HourlyRate:
+		return "Highest hourly rate"
+	case LongestSession:
+		return "Longest session"
+	case BiggestRequest:
+		return "Biggest API request"
+	}
+	return r.Name
+}
+
+// FormatValue formats the record's value, e.g. "$42.10", "$12.30/hr" or
+// "3h12m".
+func (r Record) FormatValue() string {
+	switch r.Name {
+	case HourlyRate:
+		return fmt.Sprintf("$%.2f/hr", r.Value)
+	case LongestSession:
+		d := time.Duration(r.Value) * time.Second
+		if d < time.Hour {
+			return fmt.Sprintf("%dm", int(d.Minutes()))
+		}
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("$%.2f", r.Value)
+}
+
+// Break is a record beaten by a new value. Previous is the zero Record when
+// the record was set for the first time.
+type Break struct {
+	Record   Record
+	Previous Record
+}
+
+// Store persists records across restarts.
+type Store interface {
+	LoadRecords() map[string]Record
+	SaveRecord(r Record)
+}
+
+// RateSource supplies the current global burn rate.
+type RateSource interface {
+	GetGlobal() burnrate.BurnRate
+}
+
+// Tracker keeps the records up to date from the state store. Records
+// beaten since the last call to Breaks are queued for it.
+type Tracker struct {
+	store   state.Store
+	rates   RateSource
+	persist Store
+
+	mu         sync.Mutex
+	loaded     bool
+	best       map[string]Record
+	breaks     []Break
+	computedAt time.Time
+}
+
+// Option configures a Tracker.
+type Option func(*Tracker)
+
+// WithRateSource tracks the highest hourly rate reported by src.
+func WithRateSource(src RateSource) Option {
+	return func(t *Tracker) { t.rates = src }
+}
+
+// WithStore loads records from s and saves new ones to it.
+func WithStore(s Store) Option {
+	return func(t *Tracker) { t.persist = s }
+}
+
+// NewTracker creates a tracker over the sessions in store.
+func NewTracker(store state.Store, opts ...Option) *Tracker {
+	t := &Tracker{store: store, best: make(map[string]Record)}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Records returns the current records in display order, leaving out those
+// not yet set.
+func (t *Tracker) Records(now time.Time) []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refresh(now)
+
+	var result []Record
+	for _, name := range Names {
+		if r, ok := t.best[name]; ok {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Breaks returns the records beaten since the previous call. Records set for
+// the first time, with nothing to beat, are not reported.
+func (t *Tracker) Breaks(now time.Time) []Break {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refresh(now)
+
+	breaks := t.breaks
+	t.breaks = nil
+	return breaks
+}
+
+func (t *Tracker) refresh(now time.Time) {
+	if now.Sub(t.computedAt) < refreshInterval && now.After(t.computedAt) {
+		return
+	}
+	first := !t.loaded
+	if first {
+		if t.persist != nil {
+			for name, r := range t.persist.LoadRecords() {
+				t.best[name] = r
+			}
+		}
+		t.loaded = true
+	}
+
+	days := 2
+	if _, ok := t.best[CostliestDay]; !ok {
+		days = historyDays
+	}
+	for _, c := range t.candidates(now, days) {
+		t.offer(c, first)
+	}
+	t.computedAt = now
+}
+
+// offer replaces the record of c.Name if c beats it. Improvements by the
+// record holder itself, such as a longer run of the longest session, update
+// the record without being reported again.
+func (t *Tracker) offer(c Record, quiet bool) {
+	if c.Value <= 0 {
+		return
+	}
+	prev, ok := t.best[c.Name]
+	if ok && c.Value <= prev.Value {
+		return
+	}
+	t.best[c.Name] = c
+	if t.persist != nil {
+		t.persist.SaveRecord(c)
+	}
+	if ok && !quiet && !sameHolder(prev, c) {
+		t.breaks = append(t.breaks, Break{Record: c, Previous: prev})
+	}
+}
+
+// sameHolder reports whether a and b were set by the same day or session.
+// Hourly rate records have no holder, so every new high is reported.
+func sameHolder(a, b Record) bool {
+	switch a.Name {
+	case CostliestDay:
+		return a.Detail == b.Detail
+	case LongestSession, BiggestRequest:
+		return a.SessionID == b.SessionID && a.AchievedAt.Equal(b.AchievedAt)
+	}
+	return false
+}
+
+// candidates returns the best current value of each record.
+func (t *Tracker) candidates(now time.Time, days int) []Record {
+	var result []Record
+
+	var day Record
+	for date, cost := range t.dailyCosts(days) {
+		if cost > day.Value || (cost == day.Value && date > day.Detail) {
+			day = Record{Name: CostliestDay, Value: cost, Detail: date, AchievedAt: now}
+		}
+	}
+	result = append(result, day)
+
+	if t.rates != nil {
+		result = append(result, Record{Name: HourlyRate, Value: t.rates.GetGlobal().HourlyRate, AchievedAt: now})
+	}
+
+	longest := Record{Name: LongestSession}
+	biggest := Record{Name: BiggestRequest}
+	for _, s := range t.store.ListSessions() {
+		if !s.StartedAt.IsZero() && s.LastEventAt.After(s.StartedAt) {
+			if d := s.LastEventAt.Sub(s.StartedAt).Seconds(); d > longest.Value {
+				longest = Record{Name: LongestSession, Value: d, SessionID: s.SessionID, AchievedAt: s.StartedAt}
+			}
+		}
+		for _, e := range s.Events {
+			if e.Name != "claude_code.api_request" {
+				continue
+			}
+			cost, err := strconv.ParseFloat(e.Attributes["cost_usd"], 64)
+			if err != nil || cost <= biggest.Value {
+				continue
+			}
+			biggest = Record{Name: BiggestRequest, Value: cost, SessionID: s.SessionID, Detail: e.Attributes["model"], AchievedAt: e.Timestamp}
+		}
+	}
+	return append(result, longest, biggest)
+}
+
+// dailyCosts returns the cost per local date over the last days days, from
+// the persisted daily summaries when available and the in-memory sessions
+// otherwise.
+func (t *Tracker) dailyCosts(days int) map[string]float64 {
+	costs := make(map[string]float64)
+	if summaries := t.store.QueryDailySummaries(days); len(summaries) > 0 {
+		for _, ds := range summaries {
+			costs[ds.Date] += ds.TotalCost
+		}
+		return costs
+	}
+	for _, s := range t.store.ListSessions() {
+		last := s.LastEventAt
+		if last.IsZero() {
+			last = s.StartedAt
+		}
+		costs[last.Format("2006-01-02")] += s.TotalCost
+	}
+	return costs
+}
@@ -0,0 +1,93 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+type fakeRates struct{ rate float64 }
+
+func (f *fakeRates) GetGlobal() burnrate.BurnRate { return burnrate.BurnRate{HourlyRate: f.rate} }
+
+// mapStore keeps saved records in memory.
+type mapStore map[string]Record
+
+func (s mapStore) LoadRecords() map[string]Record { return s }
+func (s mapStore) SaveRecord(r Record)            { s[r.Name] = r }
+
+// addSession adds a session that starts at start and makes one API request
+// costing cost at start+d.
+func addSession(store *state.MemoryStore, id string, start time.Time, d time.Duration, cost string) {
+	store.AddMetric(id, state.Metric{Name: "claude_code.session.count", Value: 1, Timestamp: start})
+	store.AddMetric(id, state.Metric{Name: "claude_code.cost.usage", Value: 1, Timestamp: start.Add(d)})
+	store.AddEvent(id, state.Event{Name: "claude_code.api_request", Timestamp: start.Add(d),
+		Attributes: map[string]string{"cost_usd": cost, "model": "claude-opus-4"}})
+}
+
+func TestTracker_RecordsAndBreaks(t *testing.T) {
+	store := state.NewMemoryStore()
+	rates := &fakeRates{rate: 3}
+	saved := mapStore{}
+	tracker := NewTracker(store, WithRateSource(rates), WithStore(saved))
+
+	now := time.Now()
+	addSession(store, "sess-a", now.Add(-2*time.Hour), time.Hour, "0.50")
+
+	recs := tracker.Records(now)
+	if len(recs) != 4 {
+		t.Fatalf("expected 4 records, got %+v", recs)
+	}
+	byName := make(map[string]Record)
+	for _, r := range recs {
+		byName[r.Name] = r
+	}
+	if r := byName[LongestSession]; r.Value != 3600 || r.SessionID != "sess-a" || r.FormatValue() != "1h0m" {
+		t.Errorf("longest session = %+v", r)
+	}
+	if r := byName[BiggestRequest]; r.Value != 0.5 || r.Detail != "claude-opus-4" {
+		t.Errorf("biggest request = %+v", r)
+	}
+	if r := byName[HourlyRate]; r.FormatValue() != "$3.00/hr" {
+		t.Errorf("hourly rate = %+v", r)
+	}
+	if len(saved) != 4 {
+		t.Errorf("expected the records to be saved, got %+v", saved)
+	}
+	if b := tracker.Breaks(now); len(b) != 0 {
+		t.Errorf("records set for the first time should not be reported: %+v", b)
+	}
+
+	// A longer session with a bigger request, and a higher rate.
+	later := now.Add(time.Minute)
+	addSession(store, "sess-b", now.Add(-3*time.Hour), 2*time.Hour, "2.00")
+	rates.rate = 5
+	breaks := tracker.Breaks(later)
+	got := make(map[string]Break)
+	for _, b := range breaks {
+		got[b.Record.Name] = b
+	}
+	if b, ok := got[LongestSession]; !ok || b.Record.SessionID != "sess-b" || b.Previous.SessionID != "sess-a" {
+		t.Errorf("longest session break = %+v", b)
+	}
+	if b, ok := got[BiggestRequest]; !ok || b.Record.Value != 2 || b.Previous.Value != 0.5 {
+		t.Errorf("biggest request break = %+v", b)
+	}
+	if _, ok := got[HourlyRate]; !ok {
+		t.Error("a higher hourly rate should be reported")
+	}
+	if _, ok := got[CostliestDay]; ok {
+		t.Error("today growing its own record should not be reported")
+	}
+	if again := tracker.Breaks(later.Add(time.Second)); len(again) != 0 {
+		t.Errorf("breaks should be reported once, got %+v", again)
+	}
+
+	// Saved records come back on a fresh tracker.
+	restored := NewTracker(state.NewMemoryStore(), WithStore(saved)).Records(later)
+	if len(restored) != 4 || restored[2].SessionID != "sess-b" {
+		t.Errorf("restored records = %+v", restored)
+	}
+}
@@ -63,9 +63,10 @@ func ClassifyTelemetry(proc ProcessInfo, configuredPort int, hasReceivedData boo
 				Label:  "Console only",
 			}
 		}
-		// Exporters set to otlp but no endpoint means they'll try default (localhost:4317).
+		// Exporters set to otlp but no endpoint means they'll try the
+		// protocol's default (localhost:4317, or 4318 for http/*).
 		// Check if our port matches the default.
-		if configuredPort == 4317 {
+		if configuredPort == defaultOTLPPort(proc) {
 			return connectedOrWaiting(hasReceivedData)
 		}
 		return StatusInfo{
@@ -89,6 +90,30 @@ func ClassifyTelemetry(proc ProcessInfo, configuredPort int, hasReceivedData boo
 	}
 }
 
+// usesHTTPProtocol reports whether the process exports OTLP over HTTP
+// (http/protobuf or http/json) rather than gRPC.
+func usesHTTPProtocol(proc ProcessInfo) bool {
+	return strings.HasPrefix(proc.EnvVars["OTEL_EXPORTER_OTLP_PROTOCOL"], "http/")
+}
+
+// defaultOTLPPort returns the port an OTLP exporter targets when no endpoint
+// is configured.
+func defaultOTLPPort(proc ProcessInfo) int {
+	if usesHTTPProtocol(proc) {
+		return 4318
+	}
+	return 4317
+}
+
+// ReceiverPort returns the cc-top receiver port a process should export to:
+// httpPort for the http/* protocols, grpcPort otherwise.
+func ReceiverPort(proc ProcessInfo, grpcPort, httpPort int) int {
+	if usesHTTPProtocol(proc) {
+		return httpPort
+	}
+	return grpcPort
+}
+
 // connectedOrWaiting returns Connected or Waiting status based on whether
 // OTLP data has been received from this process.
 func connectedOrWaiting(hasReceivedData bool) StatusInfo {
@@ -336,3 +336,33 @@ func TestTelemetryClassifier_OtlpExporterWithoutEndpoint(t *testing.T) {
 		}
 	})
 }
+
+func TestTelemetryClassifier_HTTPProtocol(t *testing.T) {
+	proc := ProcessInfo{
+		PID:         1234,
+		EnvReadable: true,
+		EnvVars: map[string]string{
+			"CLAUDE_CODE_ENABLE_TELEMETRY": "1",
+			"OTEL_METRICS_EXPORTER":        "otlp",
+			"OTEL_EXPORTER_OTLP_PROTOCOL":  "http/protobuf",
+		},
+	}
+
+	port := ReceiverPort(proc, 4317, 4318)
+	if port != 4318 {
+		t.Fatalf("ReceiverPort = %d, want the HTTP port 4318", port)
+	}
+	if result := ClassifyTelemetry(proc, port, false); result.Status != TelemetryWaiting {
+		t.Errorf("no endpoint: Status = %v, want TelemetryWaiting (http/* defaults to 4318)", result.Status)
+	}
+
+	proc.EnvVars["OTEL_EXPORTER_OTLP_ENDPOINT"] = "http://localhost:4318"
+	if result := ClassifyTelemetry(proc, port, false); result.Status != TelemetryWaiting {
+		t.Errorf("endpoint on HTTP port: Status = %v, want TelemetryWaiting", result.Status)
+	}
+
+	proc.EnvVars["OTEL_EXPORTER_OTLP_PROTOCOL"] = "grpc"
+	if got := ReceiverPort(proc, 4317, 4318); got != 4317 {
+		t.Errorf("ReceiverPort for grpc = %d, want 4317", got)
+	}
+}
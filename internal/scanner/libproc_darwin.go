@@ -35,11 +35,15 @@ import (
 	"unsafe"
 )
 
+// managedSettingsPath is where Claude Code reads organisation-managed
+// settings on macOS.
+const managedSettingsPath = "/Library/Application Support/ClaudeCode/managed-settings.json"
+
 // darwinProcessAPI implements ProcessAPI using macOS libproc and sysctl.
 type darwinProcessAPI struct{}
 
-// newDarwinProcessAPI returns a ProcessAPI backed by macOS system calls.
-func newDarwinProcessAPI() ProcessAPI {
+// newProcessAPI returns a ProcessAPI backed by macOS system calls.
+func newProcessAPI() ProcessAPI {
 	return &darwinProcessAPI{}
 }
 
@@ -0,0 +1,40 @@
+//go:build !darwin && !windows
+
+package scanner
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// managedSettingsPath is where Claude Code reads organisation-managed
+// settings on Linux.
+const managedSettingsPath = "/etc/claude-code/managed-settings.json"
+
+// otherProcessAPI is used on platforms without a native process API. It
+// lists no PIDs, so only the pgrep fallback finds Claude Code; argv,
+// environment, working directory and ports are unavailable.
+type otherProcessAPI struct{}
+
+// newProcessAPI returns the fallback ProcessAPI.
+func newProcessAPI() ProcessAPI {
+	return &otherProcessAPI{}
+}
+
+var errUnsupported = fmt.Errorf("process inspection is not supported on %s", runtime.GOOS)
+
+func (o *otherProcessAPI) ListAllPIDs() ([]int, error) { return nil, nil }
+
+func (o *otherProcessAPI) GetProcessInfo(pid int) (*RawProcessInfo, error) {
+	return nil, errUnsupported
+}
+
+func (o *otherProcessAPI) GetProcessArgs(pid int) ([]string, map[string]string, error) {
+	return nil, nil, errUnsupported
+}
+
+func (o *otherProcessAPI) GetProcessCWD(pid int) (string, error) { return "", errUnsupported }
+
+func (o *otherProcessAPI) PgrepClaude() []int { return pgrepClaude() }
+
+func (o *otherProcessAPI) GetOpenPorts(pid int) ([][2]int, error) { return nil, errUnsupported }
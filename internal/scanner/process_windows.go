@@ -0,0 +1,370 @@
+//go:build windows
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// managedSettingsPath is where Claude Code reads organisation-managed
+// settings on Windows.
+const managedSettingsPath = `C:\Program Files\ClaudeCode\managed-settings.json`
+
+// windowsProcessAPI implements ProcessAPI using the Toolhelp snapshot API,
+// the target process's PEB for argv/env/CWD, and the IP Helper TCP tables
+// for ports.
+//
+// Reading another process's PEB needs PROCESS_VM_READ, which Windows grants
+// for the user's own processes. A 32-bit cc-top cannot read the PEB of 64-bit
+// processes, so those show up without environment.
+type windowsProcessAPI struct{}
+
+// newProcessAPI returns a ProcessAPI backed by Windows system calls.
+func newProcessAPI() ProcessAPI {
+	return &windowsProcessAPI{}
+}
+
+// ListAllPIDs returns the PIDs of processes owned by the current user.
+func (w *windowsProcessAPI) ListAllPIDs() ([]int, error) {
+	procs, err := snapshotProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := windows.OpenCurrentProcessToken()
+	if err != nil {
+		return nil, fmt.Errorf("opening process token: %w", err)
+	}
+	defer func() { _ = token.Close() }()
+	self, err := token.GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("reading token user: %w", err)
+	}
+
+	pids := make([]int, 0, len(procs))
+	for _, p := range procs {
+		if p.ProcessID == 0 {
+			continue
+		}
+		// Processes we cannot open belong to other users or the system.
+		if owner, err := processOwner(p.ProcessID); err == nil && owner.Equals(self.User.Sid) {
+			pids = append(pids, int(p.ProcessID))
+		}
+	}
+	return pids, nil
+}
+
+// GetProcessInfo returns the executable name of pid without its .exe
+// extension, so it matches the names used on other platforms.
+func (w *windowsProcessAPI) GetProcessInfo(pid int) (*RawProcessInfo, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("opening pid %d: %w", pid, err)
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return nil, fmt.Errorf("querying image name for pid %d: %w", pid, err)
+	}
+
+	return &RawProcessInfo{
+		PID:        pid,
+		BinaryName: exeName(windows.UTF16ToString(buf[:size])),
+	}, nil
+}
+
+// GetProcessArgs reads the command line and environment block from the
+// process parameters in pid's PEB. The command line is split with the same
+// rules the C runtime uses.
+func (w *windowsProcessAPI) GetProcessArgs(pid int) (args []string, envVars map[string]string, err error) {
+	var params windows.RTL_USER_PROCESS_PARAMETERS
+	h, err := readProcessParameters(pid, &params)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	cmdline, err := readUnicodeString(h, params.CommandLine)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading command line of pid %d: %w", pid, err)
+	}
+	args, err = windows.DecomposeCommandLine(cmdline)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing command line of pid %d: %w", pid, err)
+	}
+
+	if params.Environment == nil || params.EnvironmentSize == 0 {
+		return args, nil, fmt.Errorf("no environment block for pid %d", pid)
+	}
+	block := make([]uint16, params.EnvironmentSize/2)
+	if err := readMemory(h, uintptr(params.Environment), unsafe.Pointer(&block[0]), params.EnvironmentSize); err != nil {
+		return args, nil, fmt.Errorf("reading environment of pid %d: %w", pid, err)
+	}
+
+	// The block is a sequence of NUL-terminated KEY=VALUE strings ended by
+	// an empty string. Entries starting with '=' are per-drive CWDs.
+	envVars = make(map[string]string)
+	for len(block) > 0 {
+		end := 0
+		for end < len(block) && block[end] != 0 {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		entry := string(utf16.Decode(block[:end]))
+		if eq := strings.IndexByte(entry, '='); eq > 0 {
+			envVars[entry[:eq]] = entry[eq+1:]
+		}
+		if end == len(block) {
+			break
+		}
+		block = block[end+1:]
+	}
+	return args, envVars, nil
+}
+
+// GetProcessCWD reads the current directory from pid's process parameters.
+func (w *windowsProcessAPI) GetProcessCWD(pid int) (string, error) {
+	var params windows.RTL_USER_PROCESS_PARAMETERS
+	h, err := readProcessParameters(pid, &params)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	cwd, err := readUnicodeString(h, params.CurrentDirectory.DosPath)
+	if err != nil {
+		return "", fmt.Errorf("reading current directory of pid %d: %w", pid, err)
+	}
+	// The DOS path keeps a trailing separator, except for a drive root.
+	if len(cwd) > 3 {
+		cwd = strings.TrimSuffix(cwd, `\`)
+	}
+	return cwd, nil
+}
+
+// PgrepClaude finds processes named claude.exe in a fresh snapshot, for
+// when the owner check in ListAllPIDs fails.
+func (w *windowsProcessAPI) PgrepClaude() []int {
+	procs, err := snapshotProcesses()
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, p := range procs {
+		if exeName(windows.UTF16ToString(p.ExeFile[:])) == "claude" {
+			pids = append(pids, int(p.ProcessID))
+		}
+	}
+	return pids
+}
+
+var (
+	iphlpapi                = windows.NewLazySystemDLL("iphlpapi.dll")
+	procGetExtendedTcpTable = iphlpapi.NewProc("GetExtendedTcpTable")
+)
+
+const tcpTableOwnerPIDAll = 5 // TCP_TABLE_OWNER_PID_ALL
+
+// mibTCPRowOwnerPID is MIB_TCPROW_OWNER_PID.
+type mibTCPRowOwnerPID struct {
+	State      uint32
+	LocalAddr  uint32
+	LocalPort  uint32
+	RemoteAddr uint32
+	RemotePort uint32
+	OwningPID  uint32
+}
+
+// mibTCP6RowOwnerPID is MIB_TCP6ROW_OWNER_PID.
+type mibTCP6RowOwnerPID struct {
+	LocalAddr     [16]byte
+	LocalScopeID  uint32
+	LocalPort     uint32
+	RemoteAddr    [16]byte
+	RemoteScopeID uint32
+	RemotePort    uint32
+	State         uint32
+	OwningPID     uint32
+}
+
+// GetOpenPorts returns local and remote port pairs for the IPv4 and IPv6
+// TCP connections owned by pid. Each entry is [localPort, remotePort].
+func (w *windowsProcessAPI) GetOpenPorts(pid int) ([][2]int, error) {
+	var ports [][2]int
+
+	v4, err := extendedTCPTable(windows.AF_INET)
+	if err != nil {
+		return nil, err
+	}
+	if n := tableRows(v4); n > 0 {
+		rows := unsafe.Slice((*mibTCPRowOwnerPID)(unsafe.Pointer(&v4[4])), n)
+		for _, r := range rows {
+			if int(r.OwningPID) == pid {
+				ports = appendPorts(ports, r.LocalPort, r.RemotePort)
+			}
+		}
+	}
+
+	v6, err := extendedTCPTable(windows.AF_INET6)
+	if err != nil {
+		return ports, nil // IPv6 may be disabled
+	}
+	if n := tableRows(v6); n > 0 {
+		rows := unsafe.Slice((*mibTCP6RowOwnerPID)(unsafe.Pointer(&v6[4])), n)
+		for _, r := range rows {
+			if int(r.OwningPID) == pid {
+				ports = appendPorts(ports, r.LocalPort, r.RemotePort)
+			}
+		}
+	}
+	return ports, nil
+}
+
+// extendedTCPTable fetches the TCP connection table with owning PIDs for an
+// address family, growing the buffer until it fits.
+func extendedTCPTable(family uint32) ([]byte, error) {
+	size := uint32(16 * 1024)
+	for range 4 {
+		buf := make([]byte, size)
+		ret, _, _ := procGetExtendedTcpTable.Call(
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)),
+			0, uintptr(family), tcpTableOwnerPIDAll, 0)
+		switch windows.Errno(ret) {
+		case 0:
+			return buf[:size], nil
+		case windows.ERROR_INSUFFICIENT_BUFFER:
+			continue // size now holds the required length
+		default:
+			return nil, fmt.Errorf("GetExtendedTcpTable: %w", windows.Errno(ret))
+		}
+	}
+	return nil, errors.New("GetExtendedTcpTable: table keeps growing")
+}
+
+// tableRows returns the row count at the start of a TCP table.
+func tableRows(table []byte) int {
+	if len(table) < 4 {
+		return 0
+	}
+	return int(*(*uint32)(unsafe.Pointer(&table[0])))
+}
+
+// appendPorts adds a connection's ports, which the TCP tables store in
+// network byte order in the low 16 bits.
+func appendPorts(ports [][2]int, local, remote uint32) [][2]int {
+	lp := int(local&0xff)<<8 | int(local>>8&0xff)
+	rp := int(remote&0xff)<<8 | int(remote>>8&0xff)
+	if lp > 0 || rp > 0 {
+		ports = append(ports, [2]int{lp, rp})
+	}
+	return ports
+}
+
+// snapshotProcesses lists every running process.
+func snapshotProcesses() ([]windows.ProcessEntry32, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("CreateToolhelp32Snapshot: %w", err)
+	}
+	defer func() { _ = windows.CloseHandle(snap) }()
+
+	var procs []windows.ProcessEntry32
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snap, &entry); err == nil; err = windows.Process32Next(snap, &entry) {
+		procs = append(procs, entry)
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, fmt.Errorf("walking process snapshot: %w", err)
+	}
+	return procs, nil
+}
+
+// processOwner returns the user SID a process runs as.
+func processOwner(pid uint32) (*windows.SID, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	var token windows.Token
+	if err := windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token); err != nil {
+		return nil, err
+	}
+	defer func() { _ = token.Close() }()
+	user, err := token.GetTokenUser()
+	if err != nil {
+		return nil, err
+	}
+	return user.User.Sid, nil
+}
+
+// readProcessParameters opens pid for reading and copies its
+// RTL_USER_PROCESS_PARAMETERS into params. The caller closes the handle.
+func readProcessParameters(pid int, params *windows.RTL_USER_PROCESS_PARAMETERS) (windows.Handle, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION|windows.PROCESS_VM_READ, false, uint32(pid))
+	if err != nil {
+		return 0, fmt.Errorf("opening pid %d: %w", pid, err)
+	}
+
+	var info windows.PROCESS_BASIC_INFORMATION
+	if err := windows.NtQueryInformationProcess(h, windows.ProcessBasicInformation,
+		unsafe.Pointer(&info), uint32(unsafe.Sizeof(info)), nil); err != nil {
+		_ = windows.CloseHandle(h)
+		return 0, fmt.Errorf("querying pid %d: %w", pid, err)
+	}
+
+	var peb windows.PEB
+	if err := readMemory(h, uintptr(unsafe.Pointer(info.PebBaseAddress)), unsafe.Pointer(&peb), unsafe.Sizeof(peb)); err != nil {
+		_ = windows.CloseHandle(h)
+		return 0, fmt.Errorf("reading PEB of pid %d: %w", pid, err)
+	}
+	if err := readMemory(h, uintptr(unsafe.Pointer(peb.ProcessParameters)), unsafe.Pointer(params), unsafe.Sizeof(*params)); err != nil {
+		_ = windows.CloseHandle(h)
+		return 0, fmt.Errorf("reading process parameters of pid %d: %w", pid, err)
+	}
+	return h, nil
+}
+
+// readUnicodeString copies a UNICODE_STRING whose buffer lives in another
+// process.
+func readUnicodeString(h windows.Handle, s windows.NTUnicodeString) (string, error) {
+	if s.Length == 0 || s.Buffer == nil {
+		return "", nil
+	}
+	buf := make([]uint16, s.Length/2)
+	if err := readMemory(h, uintptr(unsafe.Pointer(s.Buffer)), unsafe.Pointer(&buf[0]), uintptr(s.Length)); err != nil {
+		return "", err
+	}
+	return string(utf16.Decode(buf)), nil
+}
+
+// readMemory copies size bytes at addr in the process into dst.
+func readMemory(h windows.Handle, addr uintptr, dst unsafe.Pointer, size uintptr) error {
+	var n uintptr
+	if err := windows.ReadProcessMemory(h, addr, (*byte)(dst), size, &n); err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("short read: %d of %d bytes", n, size)
+	}
+	return nil
+}
+
+// exeName returns the lowercase base name of an executable path without
+// its .exe extension.
+func exeName(path string) string {
+	name := strings.ToLower(filepath.Base(path))
+	return strings.TrimSuffix(name, ".exe")
+}
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/nixlim/cc-top/internal/gitlog"
 )
 
 // RawProcessInfo holds data returned by the low-level process API before
@@ -42,7 +44,7 @@ type ProcessAPI interface {
 	GetOpenPorts(pid int) ([][2]int, error)
 
 	// PgrepClaude uses pgrep as a fallback to find Claude Code PIDs when
-	// libproc-based detection fails (e.g. macOS privacy restrictions).
+	// the native process API fails (e.g. macOS privacy restrictions).
 	PgrepClaude() []int
 }
 
@@ -76,19 +78,18 @@ func NewScanner(api ProcessAPI, interval time.Duration) *Scanner {
 	}
 }
 
-// NewDefaultScanner creates a Scanner using the real macOS libproc API
-// and the given scan interval in seconds. This is the production constructor.
+// NewDefaultScanner creates a Scanner using the platform's process API
+// (libproc on macOS, Toolhelp and the PEB on Windows) and the given scan
+// interval in seconds. This is the production constructor.
 func NewDefaultScanner(intervalSeconds int) *Scanner {
-	s := NewScanner(newDarwinProcessAPI(), time.Duration(intervalSeconds)*time.Second)
+	s := NewScanner(newProcessAPI(), time.Duration(intervalSeconds)*time.Second)
 	home, _ := os.UserHomeDir()
 	if home != "" {
 		s.globalConfigPaths = append(s.globalConfigPaths,
 			filepath.Join(home, ".claude", "settings.json"),
 		)
 	}
-	s.globalConfigPaths = append(s.globalConfigPaths,
-		filepath.Join("/Library", "Application Support", "ClaudeCode", "managed-settings.json"),
-	)
+	s.globalConfigPaths = append(s.globalConfigPaths, managedSettingsPath)
 	return s
 }
 
@@ -129,6 +130,7 @@ func (s *Scanner) Scan() []ProcessInfo {
 			EnvVars:     filterTelemetryEnvVars(envVars),
 			EnvReadable: envReadable,
 		}
+		info.Repo, info.Branch = gitlog.Head(cwd)
 
 		discovered[pid] = info
 	}
@@ -162,6 +164,7 @@ func (s *Scanner) Scan() []ProcessInfo {
 				EnvVars:     filterTelemetryEnvVars(envVars),
 				EnvReadable: envReadable,
 			}
+			info.Repo, info.Branch = gitlog.Head(cwd)
 			discovered[pid] = info
 		}
 	}
@@ -269,7 +272,7 @@ func (s *Scanner) listAllLocked() []ProcessInfo {
 }
 
 // isClaude returns true if the process is a Claude Code CLI instance.
-// Detection: process name is "claude" (rejecting Claude Desktop .app and helpers),
+// Detection: process name is "claude" (rejecting Claude Desktop and helpers),
 // or it's a node process with "@anthropic-ai/claude-code" in argv.
 func isClaude(binaryName string, args []string) bool {
 	name := strings.ToLower(binaryName)
@@ -277,8 +280,9 @@ func isClaude(binaryName string, args []string) bool {
 	// Direct "claude" binary match.
 	if name == "claude" {
 		// Reject Claude Desktop app and Electron helpers: their argv[0]
-		// contains ".app/" (e.g. /Applications/Claude.app/Contents/MacOS/Claude).
-		if len(args) > 0 && strings.Contains(args[0], ".app/") {
+		// contains ".app/" (e.g. /Applications/Claude.app/Contents/MacOS/Claude)
+		// or, on Windows, the AnthropicClaude install directory.
+		if len(args) > 0 && (strings.Contains(args[0], ".app/") || strings.Contains(args[0], `\AnthropicClaude\`)) {
 			return false
 		}
 		return true
@@ -287,7 +291,7 @@ func isClaude(binaryName string, args []string) bool {
 	// Node process with Claude Code module path in arguments.
 	if name == "node" || name == "nodejs" {
 		for _, arg := range args {
-			if strings.Contains(arg, "@anthropic-ai/claude-code") {
+			if strings.Contains(strings.ReplaceAll(arg, `\`, "/"), "@anthropic-ai/claude-code") {
 				return true
 			}
 		}
@@ -323,6 +327,11 @@ func detectTerminal(envVars map[string]string) string {
 		return "tmux"
 	}
 
+	// Windows Terminal sets WT_SESSION in every tab.
+	if envVars["WT_SESSION"] != "" {
+		return "Windows Terminal"
+	}
+
 	// Check for VS Code via VSCODE_PID.
 	if envVars["VSCODE_PID"] != "" {
 		return "VS Code"
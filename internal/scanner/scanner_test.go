@@ -374,6 +374,11 @@ func TestProcessScanner_TerminalDetection(t *testing.T) {
 			env:      map[string]string{"TERM_PROGRAM": "Apple_Terminal"},
 			wantTerm: "Terminal",
 		},
+		{
+			name:     "Windows Terminal",
+			env:      map[string]string{"WT_SESSION": "0b1f2c3d-4e5f-6071-8293-a4b5c6d7e8f9"},
+			wantTerm: "Windows Terminal",
+		},
 		{
 			name:     "unknown terminal",
 			env:      map[string]string{},
@@ -405,6 +410,9 @@ func TestIsClaude(t *testing.T) {
 		{"node with claude-code", "node", []string{"node", "/path/@anthropic-ai/claude-code/cli.js"}, true},
 		{"node without claude-code", "node", []string{"node", "/path/server.js"}, false},
 		{"nodejs with claude-code", "nodejs", []string{"nodejs", "/path/@anthropic-ai/claude-code/cli.js"}, true},
+		{"Windows claude.exe", "claude", []string{`C:\Users\dev\.local\bin\claude.exe`}, true},
+		{"Windows Claude Desktop app", "claude", []string{`C:\Users\dev\AppData\Local\AnthropicClaude\app-0.9.3\claude.exe`}, false},
+		{"Windows node with claude-code", "node", []string{"node", `C:\Users\dev\AppData\Roaming\npm\node_modules\@anthropic-ai\claude-code\cli.js`}, true},
 		{"random binary", "vim", nil, false},
 	}
 
@@ -704,3 +712,34 @@ func TestGlobalConfigMerge_NoConfigPaths(t *testing.T) {
 		t.Error("scanner should work without global config paths")
 	}
 }
+
+func TestProcessScanner_GitBranch(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(repo+"/.git", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repo+"/.git/HEAD", []byte("ref: refs/heads/main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	api := newMockAPI()
+	api.addProcess(&mockProcess{
+		info: &RawProcessInfo{PID: 100, BinaryName: "claude"},
+		args: []string{"claude"},
+		cwd:  repo,
+	})
+	api.addProcess(&mockProcess{
+		info: &RawProcessInfo{PID: 101, BinaryName: "claude"},
+		args: []string{"claude"},
+		cwd:  t.TempDir(),
+	})
+
+	for _, p := range NewScanner(api, time.Second).Scan() {
+		switch {
+		case p.PID == 100 && (p.Branch != "main" || p.Repo == ""):
+			t.Errorf("process in a repository: repo %q, branch %q", p.Repo, p.Branch)
+		case p.PID == 101 && (p.Branch != "" || p.Repo != ""):
+			t.Errorf("process outside a repository: repo %q, branch %q", p.Repo, p.Branch)
+		}
+	}
+}
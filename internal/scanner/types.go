@@ -7,6 +7,8 @@ type ProcessInfo struct {
 	Args         []string
 	CWD          string
 	Terminal     string
+	Repo         string // repository the CWD is in, see gitlog.Head
+	Branch       string // checked-out branch, or a short hash when detached
 	EnvVars      map[string]string
 	EnvReadable  bool
 	IsNew        bool   // first scan cycle where this PID appeared
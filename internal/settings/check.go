@@ -0,0 +1,59 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// EnvIssue is an OTel variable that settings.json lacks or sets to a value
+// other than the one cc-top needs.
+type EnvIssue struct {
+	Key     string
+	Want    string
+	Got     string // the configured value; empty when Missing
+	Missing bool
+}
+
+// DefaultPath returns the path of Claude Code's user settings,
+// ~/.claude/settings.json.
+func DefaultPath() string {
+	return defaultSettingsPath()
+}
+
+// CheckOTelEnv reads the settings at path (default ~/.claude/settings.json)
+// without changing them and reports the variables of RequiredOTelEnv whose
+// "env" value is missing or different, sorted by key. A missing file
+// reports every variable as missing.
+func CheckOTelEnv(path string, grpcPort int) ([]EnvIssue, error) {
+	if path == "" {
+		path = defaultSettingsPath()
+	}
+	var settings struct {
+		Env map[string]any `json:"env"`
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("reading settings file: %w", err)
+	default:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("%s contains invalid JSON: %w", path, err)
+		}
+	}
+
+	required := RequiredOTelEnv(grpcPort)
+	var issues []EnvIssue
+	for _, key := range sortedKeys(required) {
+		want := required[key]
+		got, ok := settings.Env[key]
+		switch {
+		case !ok:
+			issues = append(issues, EnvIssue{Key: key, Want: want, Missing: true})
+		case fmt.Sprint(got) != want:
+			issues = append(issues, EnvIssue{Key: key, Want: want, Got: fmt.Sprint(got)})
+		}
+	}
+	return issues, nil
+}
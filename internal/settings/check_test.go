@@ -0,0 +1,44 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckOTelEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+
+	issues, err := CheckOTelEnv(path, 4317)
+	if err != nil {
+		t.Fatalf("CheckOTelEnv on a missing file: %v", err)
+	}
+	if len(issues) != len(RequiredOTelEnv(4317)) || !issues[0].Missing {
+		t.Errorf("missing file: got %+v, want every variable missing", issues)
+	}
+
+	out := Merge(MergeOptions{SettingsPath: path, GRPCPort: 4317})
+	if out.Err != nil {
+		t.Fatalf("Merge: %v", out.Err)
+	}
+	if issues, err := CheckOTelEnv(path, 4317); err != nil || len(issues) != 0 {
+		t.Errorf("after setup: got %+v, %v; want no issues", issues, err)
+	}
+
+	issues, err = CheckOTelEnv(path, 5317)
+	if err != nil {
+		t.Fatalf("CheckOTelEnv: %v", err)
+	}
+	want := EnvIssue{Key: "OTEL_EXPORTER_OTLP_ENDPOINT", Want: "http://localhost:5317", Got: "http://localhost:4317"}
+	if len(issues) != 1 || issues[0] != want {
+		t.Errorf("other port: got %+v, want [%+v]", issues, want)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckOTelEnv(path, 4317); err == nil {
+		t.Error("invalid JSON should be an error")
+	}
+}
@@ -0,0 +1,64 @@
+package state
+
+import "time"
+
+const (
+	// downsampleMinAge is how old a session must be before its metrics are
+	// downsampled; younger sessions keep full resolution.
+	downsampleMinAge = time.Hour
+
+	// downsampleResolution is the bucket width kept per metric series.
+	downsampleResolution = time.Minute
+
+	// downsampleMinMetrics is the metric count below which a session is
+	// never downsampled. Above it, a session is downsampled again each time
+	// its metric list doubles, keeping the cost amortised.
+	downsampleMinMetrics = 512
+)
+
+// maybeDownsample downsamples s's metrics when the session is old and large
+// enough. It must be called with ms.mu held.
+func (ms *MemoryStore) maybeDownsample(s *SessionData, now time.Time) {
+	if len(s.Metrics) < max(downsampleMinMetrics, 2*ms.downsampledLen[s.SessionID]) {
+		return
+	}
+	if s.StartedAt.IsZero() || now.Sub(s.StartedAt) < downsampleMinAge {
+		return
+	}
+	s.Metrics = downsampleMetrics(s.Metrics)
+	ms.downsampledLen[s.SessionID] = len(s.Metrics)
+}
+
+// downsampleMetrics reduces each metric series (name plus attributes) to the
+// last sample in every downsampleResolution bucket, always keeping the latest
+// sample. Cumulative counters replay to exactly the same totals: a sample
+// followed by a lower value (a counter reset) is also kept, so no increase is
+// lost. Samples without a timestamp are kept as-is. Order is preserved.
+func downsampleMetrics(metrics []Metric) []Metric {
+	keep := make([]bool, len(metrics))
+	next := make(map[string]Metric)
+
+	for i := len(metrics) - 1; i >= 0; i-- {
+		m := metrics[i]
+		key := MetricKey(m.Name, m.Attributes)
+		succ, hasSucc := next[key]
+		next[key] = m
+
+		switch {
+		case !hasSucc, m.Timestamp.IsZero(), succ.Timestamp.IsZero():
+			keep[i] = true
+		case !m.Timestamp.Truncate(downsampleResolution).Equal(succ.Timestamp.Truncate(downsampleResolution)):
+			keep[i] = true
+		case succ.Value < m.Value:
+			keep[i] = true
+		}
+	}
+
+	out := metrics[:0:0]
+	for i, m := range metrics {
+		if keep[i] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
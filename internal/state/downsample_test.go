@@ -0,0 +1,101 @@
+package state
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// replayTotal sums cumulative counter increases the same way AddMetric does.
+func replayTotal(metrics []Metric, name string) float64 {
+	prev := make(map[string]float64)
+	var total float64
+	for _, m := range metrics {
+		if m.Name != name {
+			continue
+		}
+		key := MetricKey(m.Name, m.Attributes)
+		last, ok := prev[key]
+		prev[key] = m.Value
+		if ok && m.Value >= last {
+			total += m.Value - last
+		} else {
+			total += m.Value
+		}
+	}
+	return total
+}
+
+func TestDownsampleMetrics_PreservesTotals(t *testing.T) {
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	cost := func(v float64, offset time.Duration, model string) Metric {
+		return Metric{Name: "claude_code.cost.usage", Value: v, Timestamp: base.Add(offset), Attributes: map[string]string{"model": model}}
+	}
+
+	metrics := []Metric{
+		cost(1, 0, "opus"),
+		cost(2, 10*time.Second, "opus"),
+		cost(3, 20*time.Second, "sonnet"),
+		cost(5, 30*time.Second, "opus"),
+		cost(1, 40*time.Second, "opus"), // counter reset within the minute
+		cost(2, 50*time.Second, "opus"),
+		cost(4, 70*time.Second, "opus"),
+		cost(6, 80*time.Second, "opus"), // latest
+	}
+
+	got := downsampleMetrics(metrics)
+
+	if len(got) >= len(metrics) {
+		t.Fatalf("expected fewer metrics after downsampling, got %d of %d", len(got), len(metrics))
+	}
+	if want := replayTotal(metrics, "claude_code.cost.usage"); replayTotal(got, "claude_code.cost.usage") != want {
+		t.Errorf("replayed total = %v, want %v", replayTotal(got, "claude_code.cost.usage"), want)
+	}
+	if last := got[len(got)-1]; last.Value != 6 || !last.Timestamp.Equal(base.Add(80*time.Second)) {
+		t.Errorf("latest sample should be kept, got %+v", last)
+	}
+	// One sample per series per minute, plus the pre-reset peak.
+	wantValues := []float64{3, 5, 2, 6}
+	if len(got) != len(wantValues) {
+		t.Fatalf("kept %d samples, want %d: %+v", len(got), len(wantValues), got)
+	}
+	for i, v := range wantValues {
+		if got[i].Value != v {
+			t.Errorf("sample %d = %v, want %v", i, got[i].Value, v)
+		}
+	}
+}
+
+func TestStateStore_DownsamplesOldSessions(t *testing.T) {
+	store := NewMemoryStore()
+	start := time.Now().Add(-2 * time.Hour)
+
+	store.AddMetric("old", Metric{Name: "claude_code.session.count", Value: 1, Timestamp: start})
+	store.AddMetric("young", Metric{Name: "claude_code.session.count", Value: 1, Timestamp: time.Now()})
+
+	// One sample per second for 20 minutes: far above the threshold.
+	var want float64
+	for i := range 1200 {
+		v := float64(i+1) * 0.01
+		want = v
+		store.AddMetric("old", Metric{Name: "claude_code.cost.usage", Value: v, Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+	for i := range 600 {
+		store.AddMetric("young", Metric{Name: "claude_code.cost.usage", Value: 1, Timestamp: time.Now().Add(time.Duration(i) * time.Millisecond)})
+	}
+
+	old := store.GetSession("old")
+	if len(old.Metrics) >= downsampleMinMetrics {
+		t.Errorf("old session should be downsampled, has %d metrics", len(old.Metrics))
+	}
+	if math.Abs(old.TotalCost-want) > 1e-9 {
+		t.Errorf("TotalCost = %v, want %v", old.TotalCost, want)
+	}
+	if got := replayTotal(old.Metrics, "claude_code.cost.usage"); math.Abs(got-want) > 1e-9 {
+		t.Errorf("replayed total from downsampled metrics = %v, want %v", got, want)
+	}
+
+	if young := store.GetSession("young"); len(young.Metrics) != 601 {
+		t.Errorf("sessions younger than an hour keep full resolution, got %d metrics", len(young.Metrics))
+	}
+}
@@ -1,6 +1,9 @@
 package state
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // SessionAge returns the duration since the session started.
 func SessionAge(s *SessionData) time.Duration {
@@ -77,3 +80,49 @@ func EventsByName(s *SessionData, name string) []Event {
 	}
 	return result
 }
+
+// TrackContext records the context an api_request event sent in
+// ContextTokens; other events are ignored.
+func (s *SessionData) TrackContext(e Event) {
+	model := e.Attributes["model"]
+	if e.Name != "claude_code.api_request" || model == "" {
+		return
+	}
+	var tokens int64
+	for _, attr := range []string{"input_tokens", "cache_read_tokens", "cache_creation_tokens"} {
+		n, _ := strconv.ParseInt(e.Attributes[attr], 10, 64)
+		tokens += n
+	}
+	if s.ContextTokens == nil {
+		s.ContextTokens = make(map[string]int64)
+	}
+	s.ContextTokens[model] = tokens
+}
+
+// ContextUsage returns the model whose context window is fullest, given
+// the window size per model, with its context and limit. Models without a
+// known limit are skipped; model is empty when none has one.
+func (s *SessionData) ContextUsage(limits map[string]int) (model string, tokens int64, limit int) {
+	var best float64
+	for m, t := range s.ContextTokens {
+		l := limits[m]
+		if l <= 0 {
+			continue
+		}
+		pct := float64(t) / float64(l)
+		if model == "" || pct > best || (pct == best && m < model) {
+			model, tokens, limit, best = m, t, l, pct
+		}
+	}
+	return model, tokens, limit
+}
+
+// ContextUsagePercent returns how full the session's fullest context
+// window is, as a percentage; 0 when no model's limit is known.
+func (s *SessionData) ContextUsagePercent(limits map[string]int) float64 {
+	_, tokens, limit := s.ContextUsage(limits)
+	if limit == 0 {
+		return 0
+	}
+	return float64(tokens) / float64(limit) * 100
+}
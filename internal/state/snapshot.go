@@ -0,0 +1,85 @@
+package state
+
+import (
+	"sort"
+	"time"
+)
+
+// Snapshot is an immutable copy of every session at one point in time,
+// shared by all readers until the store changes. Sessions are sorted by
+// start time like ListSessions; neither they nor their slices may be
+// modified.
+type Snapshot struct {
+	// Version increases with every store mutation, so two snapshots with the
+	// same Version hold the same data.
+	Version   uint64
+	TakenAt   time.Time
+	Sessions  []SessionData
+	TotalCost float64
+
+	index map[string]int
+}
+
+// Session returns the session with the given ID, or nil. The result points
+// into the snapshot and must not be modified.
+func (s *Snapshot) Session(sessionID string) *SessionData {
+	if s == nil {
+		return nil
+	}
+	i, ok := s.index[sessionID]
+	if !ok {
+		return nil
+	}
+	return &s.Sessions[i]
+}
+
+// NewSnapshot builds a snapshot that takes ownership of sessions, which
+// should already be in display order.
+func NewSnapshot(version uint64, sessions []SessionData) *Snapshot {
+	snap := &Snapshot{
+		Version:  version,
+		TakenAt:  time.Now(),
+		Sessions: sessions,
+		index:    make(map[string]int, len(sessions)),
+	}
+	for i := range sessions {
+		snap.TotalCost += sessions[i].TotalCost
+		snap.index[sessions[i].SessionID] = i
+	}
+	return snap
+}
+
+// Snapshot returns the current snapshot, building a new one only when the
+// store has changed since the last call. Every caller between two
+// mutations gets the same *Snapshot, so reading it costs no locking or
+// copying beyond the first build.
+func (ms *MemoryStore) Snapshot() *Snapshot {
+	ms.snapMu.Lock()
+	defer ms.snapMu.Unlock()
+
+	ms.mu.RLock()
+	if ms.snap != nil && ms.snap.Version == ms.version {
+		ms.mu.RUnlock()
+		return ms.snap
+	}
+	version := ms.version
+	sessions := make([]SessionData, 0, len(ms.sessions))
+	for _, s := range ms.sessions {
+		sessions = append(sessions, *ms.copySession(s))
+	}
+	ms.mu.RUnlock()
+
+	sortSessions(sessions)
+	ms.snap = NewSnapshot(version, sessions)
+	return ms.snap
+}
+
+// sortSessions orders sessions by start time, then by ID.
+func sortSessions(sessions []SessionData) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].StartedAt.Equal(sessions[j].StartedAt) {
+			return sessions[i].SessionID < sessions[j].SessionID
+		}
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+}
@@ -0,0 +1,82 @@
+package state
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshot_SharedUntilMutation(t *testing.T) {
+	store := NewMemoryStore()
+	store.AddMetric("sess-b", Metric{Name: "claude_code.cost.usage", Value: 2, Timestamp: time.Now()})
+	store.AddMetric("sess-a", Metric{Name: "claude_code.cost.usage", Value: 1, Timestamp: time.Now()})
+	store.UpdatePID("sess-a", 42)
+
+	first := store.Snapshot()
+	if again := store.Snapshot(); again != first {
+		t.Fatal("expected the same snapshot while the store is unchanged")
+	}
+	if len(first.Sessions) != 2 || first.Sessions[0].SessionID != "sess-b" {
+		t.Fatalf("expected sessions in start order, got %+v", first.Sessions)
+	}
+	if first.TotalCost != 3 {
+		t.Errorf("TotalCost: got %v, want 3", first.TotalCost)
+	}
+	if s := first.Session("sess-a"); s == nil || s.PID != 42 {
+		t.Errorf("Session(sess-a): got %+v", s)
+	}
+	if first.Session("missing") != nil {
+		t.Error("expected nil for an unknown session")
+	}
+
+	store.MarkExited(99)
+	if store.Snapshot() != first {
+		t.Error("a MarkExited that changes nothing should keep the snapshot")
+	}
+
+	store.MarkExited(42)
+	second := store.Snapshot()
+	if second == first || second.Version <= first.Version {
+		t.Fatalf("expected a newer snapshot after a mutation: %d -> %d", first.Version, second.Version)
+	}
+	if !second.Session("sess-a").Exited {
+		t.Error("new snapshot should see the exit")
+	}
+	if first.Session("sess-a").Exited {
+		t.Error("old snapshot must not change")
+	}
+}
+
+func TestSnapshot_ConcurrentReaders(t *testing.T) {
+	store := NewMemoryStore()
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				store.AddEvent("sess", Event{Name: "claude_code.api_request", Timestamp: time.Now()})
+				if i%10 == 0 {
+					store.UpdatePID("sess", w+1)
+				}
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				snap := store.Snapshot()
+				if s := snap.Session("sess"); s != nil && len(s.Events) > 800 {
+					t.Errorf("unexpected event count %d", len(s.Events))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s := store.Snapshot().Session("sess"); s == nil || len(s.Events) != 800 {
+		t.Fatalf("final snapshot should hold all 800 events")
+	}
+}
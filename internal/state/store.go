@@ -3,6 +3,7 @@ package state
 import (
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,14 +16,28 @@ type Store interface {
 
 	AddEvent(sessionID string, e Event)
 
+	AddSpan(sessionID string, sp Span)
+
 	GetSession(sessionID string) *SessionData
 
 	ListSessions() []SessionData
 
+	// Snapshot returns a shared, read-only view of every session. Callers
+	// must not modify it.
+	Snapshot() *Snapshot
+
 	GetAggregatedCost() float64
 
 	UpdatePID(sessionID string, pid int)
 
+	// UpdateGit records the repository and branch the session's process
+	// works in, as found by the process scanner.
+	UpdateGit(sessionID, repo, branch string)
+
+	// SetDuplicatePIDs records the other processes found exporting under
+	// the session's ID; nil clears the conflict.
+	SetDuplicatePIDs(sessionID string, pids []int)
+
 	MarkExited(pid int)
 
 	UpdateMetadata(sessionID string, meta SessionMetadata)
@@ -42,11 +57,19 @@ type MemoryStore struct {
 	mu             sync.RWMutex
 	sessions       map[string]*SessionData
 	eventListeners []EventListener
+	downsampledLen map[string]int // session ID -> metric count after last downsample
+
+	// version counts mutations; snap is the last snapshot built, reused
+	// while its Version matches. snapMu serialises rebuilds.
+	version uint64
+	snapMu  sync.Mutex
+	snap    *Snapshot
 }
 
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		sessions: make(map[string]*SessionData),
+		sessions:       make(map[string]*SessionData),
+		downsampledLen: make(map[string]int),
 	}
 }
 
@@ -58,7 +81,7 @@ func (ms *MemoryStore) OnEvent(fn EventListener) {
 
 func resolveSessionID(sessionID string) string {
 	if sessionID == "" {
-		log.Printf("WARNING: metric/event received without session.id, storing under %q", UnknownSessionID)
+		log.Printf("WARNING: metric, event or span received without session.id, storing under %q", UnknownSessionID)
 		return UnknownSessionID
 	}
 	return sessionID
@@ -98,6 +121,7 @@ func (ms *MemoryStore) AddMetric(sessionID string, m Metric) {
 
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
+	ms.version++
 
 	if m.Name == "claude_code.session.count" {
 		if _, exists := ms.sessions[sessionID]; !exists {
@@ -121,6 +145,7 @@ func (ms *MemoryStore) AddMetric(sessionID string, m Metric) {
 	} else {
 		s.LastEventAt = time.Now()
 	}
+	ms.maybeDownsample(s, time.Now())
 
 	key := MetricKey(m.Name, m.Attributes)
 	prev, hasPrev := s.PreviousValues[key]
@@ -169,6 +194,7 @@ func (ms *MemoryStore) AddEvent(sessionID string, e Event) {
 	sessionID = resolveSessionID(sessionID)
 
 	ms.mu.Lock()
+	ms.version++
 
 	s := ms.getOrCreateSession(sessionID)
 
@@ -215,6 +241,7 @@ func (ms *MemoryStore) AddEvent(sessionID string, e Event) {
 				s.CacheCreationTokens += n
 			}
 		}
+		s.TrackContext(e)
 	}
 
 	if speed, ok := e.Attributes["speed"]; ok && speed != "" {
@@ -239,6 +266,28 @@ func (ms *MemoryStore) AddEvent(sessionID string, e Event) {
 	}
 }
 
+// MaxSessionSpans caps the spans kept per session; the oldest are dropped
+// first.
+const MaxSessionSpans = 2000
+
+// AddSpan stores a finished span, keeping the session's spans in start
+// order. Spans don't count as session activity: Claude Code sends the
+// matching events as well.
+func (ms *MemoryStore) AddSpan(sessionID string, sp Span) {
+	sessionID = resolveSessionID(sessionID)
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.version++
+
+	s := ms.getOrCreateSession(sessionID)
+	i := sort.Search(len(s.Spans), func(i int) bool { return s.Spans[i].Start.After(sp.Start) })
+	s.Spans = slices.Insert(s.Spans, i, sp)
+	if n := len(s.Spans) - MaxSessionSpans; n > 0 {
+		s.Spans = slices.Delete(s.Spans, 0, n)
+	}
+}
+
 func (ms *MemoryStore) GetSession(sessionID string) *SessionData {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
@@ -259,12 +308,7 @@ func (ms *MemoryStore) ListSessions() []SessionData {
 		result = append(result, *ms.copySession(s))
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		if result[i].StartedAt.Equal(result[j].StartedAt) {
-			return result[i].SessionID < result[j].SessionID
-		}
-		return result[i].StartedAt.Before(result[j].StartedAt)
-	})
+	sortSessions(result)
 	return result
 }
 
@@ -282,11 +326,36 @@ func (ms *MemoryStore) GetAggregatedCost() float64 {
 func (ms *MemoryStore) UpdatePID(sessionID string, pid int) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
+	ms.version++
 
 	s := ms.getOrCreateSession(sessionID)
 	s.PID = pid
 }
 
+func (ms *MemoryStore) UpdateGit(sessionID, repo, branch string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	s, ok := ms.sessions[sessionID]
+	if !ok || (s.Repo == repo && s.Branch == branch) {
+		return
+	}
+	s.Repo, s.Branch = repo, branch
+	ms.version++
+}
+
+func (ms *MemoryStore) SetDuplicatePIDs(sessionID string, pids []int) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	s, ok := ms.sessions[sessionID]
+	if !ok || slices.Equal(s.DuplicatePIDs, pids) {
+		return
+	}
+	s.DuplicatePIDs = slices.Clone(pids)
+	ms.version++
+}
+
 func (ms *MemoryStore) MarkExited(pid int) {
 	if pid == 0 {
 		return
@@ -296,8 +365,9 @@ func (ms *MemoryStore) MarkExited(pid int) {
 	defer ms.mu.Unlock()
 
 	for _, s := range ms.sessions {
-		if s.PID == pid {
+		if s.PID == pid && !s.Exited {
 			s.Exited = true
+			ms.version++
 		}
 	}
 }
@@ -307,6 +377,7 @@ func (ms *MemoryStore) UpdateMetadata(sessionID string, meta SessionMetadata) {
 
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
+	ms.version++
 
 	s := ms.getOrCreateSession(sessionID)
 	if meta.ServiceVersion != "" {
@@ -321,6 +392,24 @@ func (ms *MemoryStore) UpdateMetadata(sessionID string, meta SessionMetadata) {
 	if meta.HostArch != "" {
 		s.Metadata.HostArch = meta.HostArch
 	}
+	if meta.HostName != "" {
+		s.Metadata.HostName = meta.HostName
+	}
+	if meta.ServiceInstanceID != "" {
+		s.Metadata.ServiceInstanceID = meta.ServiceInstanceID
+	}
+	if meta.Environment != "" {
+		s.Metadata.Environment = meta.Environment
+	}
+	if meta.User != "" {
+		s.Metadata.User = meta.User
+	}
+	if len(meta.Tags) > 0 {
+		s.Metadata.Tags = append([]string(nil), meta.Tags...)
+	}
+	if meta.ExpectedDuration > 0 {
+		s.Metadata.ExpectedDuration = meta.ExpectedDuration
+	}
 }
 
 func (ms *MemoryStore) Close() error {
@@ -338,6 +427,7 @@ func (ms *MemoryStore) QueryDailySummaries(days int) []DailySummary {
 func (ms *MemoryStore) RestoreSession(session *SessionData) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
+	ms.version++
 	ms.sessions[session.SessionID] = session
 }
 
@@ -353,12 +443,24 @@ func (ms *MemoryStore) copySession(s *SessionData) *SessionData {
 		copy(cp.Events, s.Events)
 	}
 
+	if len(s.Spans) > 0 {
+		cp.Spans = make([]Span, len(s.Spans))
+		copy(cp.Spans, s.Spans)
+	}
+	cp.DuplicatePIDs = slices.Clone(s.DuplicatePIDs)
+
 	if len(s.PreviousValues) > 0 {
 		cp.PreviousValues = make(map[string]float64, len(s.PreviousValues))
 		for k, v := range s.PreviousValues {
 			cp.PreviousValues[k] = v
 		}
 	}
+	if len(s.ContextTokens) > 0 {
+		cp.ContextTokens = make(map[string]int64, len(s.ContextTokens))
+		for k, v := range s.ContextTokens {
+			cp.ContextTokens[k] = v
+		}
+	}
 
 	return &cp
 }
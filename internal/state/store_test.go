@@ -222,6 +222,54 @@ func TestStateStore_UpdatePID(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpdateGit(t *testing.T) {
+	store := NewMemoryStore()
+	store.UpdateGit("sess-missing", "cc-top", "main")
+	if store.GetSession("sess-missing") != nil {
+		t.Error("UpdateGit should not create sessions")
+	}
+
+	store.UpdatePID("sess-001", 4821)
+	before := store.Snapshot().Version
+	store.UpdateGit("sess-001", "cc-top", "feature")
+	s := store.GetSession("sess-001")
+	if s.Repo != "cc-top" || s.Branch != "feature" {
+		t.Errorf("repo/branch = %q/%q", s.Repo, s.Branch)
+	}
+	after := store.Snapshot().Version
+	store.UpdateGit("sess-001", "cc-top", "feature")
+	if after == before || store.Snapshot().Version != after {
+		t.Error("only a change of branch should invalidate the snapshot")
+	}
+}
+
+func TestStateStore_SetDuplicatePIDs(t *testing.T) {
+	store := NewMemoryStore()
+	store.SetDuplicatePIDs("sess-missing", []int{200})
+	if store.GetSession("sess-missing") != nil {
+		t.Error("SetDuplicatePIDs should not create sessions")
+	}
+
+	store.UpdatePID("sess-001", 100)
+	pids := []int{200}
+	store.SetDuplicatePIDs("sess-001", pids)
+	pids[0] = 999
+	s := store.GetSession("sess-001")
+	if len(s.DuplicatePIDs) != 1 || s.DuplicatePIDs[0] != 200 {
+		t.Errorf("DuplicatePIDs = %v, want [200]", s.DuplicatePIDs)
+	}
+
+	before := store.Snapshot().Version
+	store.SetDuplicatePIDs("sess-001", []int{200})
+	if store.Snapshot().Version != before {
+		t.Error("setting the same PIDs should not invalidate the snapshot")
+	}
+	store.SetDuplicatePIDs("sess-001", nil)
+	if s := store.GetSession("sess-001"); len(s.DuplicatePIDs) != 0 {
+		t.Errorf("DuplicatePIDs after clearing = %v", s.DuplicatePIDs)
+	}
+}
+
 func TestStateStore_MarkExited(t *testing.T) {
 	store := NewMemoryStore()
 
@@ -818,6 +866,47 @@ func TestStateStore_ModelFromAPIError(t *testing.T) {
 	}
 }
 
+func TestStateStore_ContextUsage(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	request := func(model, input, cacheRead string, at time.Time) {
+		store.AddEvent("sess-ctx", Event{
+			Name: "claude_code.api_request",
+			Attributes: map[string]string{
+				"model":             model,
+				"input_tokens":      input,
+				"cache_read_tokens": cacheRead,
+			},
+			Timestamp: at,
+		})
+	}
+	limits := map[string]int{"opus": 200000, "haiku": 100000}
+
+	request("opus", "1000", "99000", now)
+	request("haiku", "5000", "0", now.Add(time.Second))
+	request("unknown", "190000", "0", now.Add(2*time.Second))
+
+	s := store.GetSession("sess-ctx")
+	// The fullest window with a known limit wins: opus at 50%, not haiku
+	// at 5% although haiku sent the latest request.
+	if model, tokens, limit := s.ContextUsage(limits); model != "opus" || tokens != 100000 || limit != 200000 {
+		t.Errorf("ContextUsage = %q, %d, %d; want opus, 100000, 200000", model, tokens, limit)
+	}
+	if pct := s.ContextUsagePercent(limits); pct != 50 {
+		t.Errorf("ContextUsagePercent = %v, want 50", pct)
+	}
+
+	// After a compaction the next request is smaller; the latest counts.
+	request("opus", "2000", "18000", now.Add(3*time.Second))
+	if pct := store.GetSession("sess-ctx").ContextUsagePercent(limits); pct != 10 {
+		t.Errorf("ContextUsagePercent after compaction = %v, want 10", pct)
+	}
+
+	if pct := s.ContextUsagePercent(nil); pct != 0 {
+		t.Errorf("ContextUsagePercent without limits = %v, want 0", pct)
+	}
+}
+
 func TestStateStore_ModelFromAnyEvent(t *testing.T) {
 	store := NewMemoryStore()
 
@@ -860,6 +949,39 @@ func TestStateStore_ModelNotSetWithoutAttribute(t *testing.T) {
 	}
 }
 
+func TestStateStore_Spans(t *testing.T) {
+	store := NewMemoryStore()
+	base := time.Now()
+	for _, offset := range []int{3, 1, 2} {
+		start := base.Add(time.Duration(offset) * time.Second)
+		store.AddSpan("sess-001", Span{SpanID: string(rune('a' + offset)), Start: start, End: start.Add(time.Second)})
+	}
+
+	got := store.GetSession("sess-001")
+	if got == nil || len(got.Spans) != 3 {
+		t.Fatalf("expected 3 spans, got %+v", got)
+	}
+	for i, want := range []string{"b", "c", "d"} {
+		if got.Spans[i].SpanID != want {
+			t.Errorf("Spans[%d] = %q, want %q (start order)", i, got.Spans[i].SpanID, want)
+		}
+	}
+	if got.Spans[0].Duration() != time.Second {
+		t.Errorf("Duration = %v, want 1s", got.Spans[0].Duration())
+	}
+	if !got.LastEventAt.IsZero() {
+		t.Error("spans should not count as session activity")
+	}
+
+	for i := range MaxSessionSpans {
+		store.AddSpan("sess-001", Span{Start: base.Add(time.Minute + time.Duration(i)*time.Millisecond)})
+	}
+	got = store.GetSession("sess-001")
+	if len(got.Spans) != MaxSessionSpans || got.Spans[0].SpanID != "" {
+		t.Errorf("expected the %d newest spans, got %d starting with %q", MaxSessionSpans, len(got.Spans), got.Spans[0].SpanID)
+	}
+}
+
 func TestSessionStatus(t *testing.T) {
 	now := time.Now()
 
@@ -4,11 +4,18 @@ import "time"
 
 const UnknownSessionID = "unknown"
 
+// AnnotationEventName is the event name used for free-text annotations
+// posted by Claude Code hooks to the receiver.
+const AnnotationEventName = "cc_top.annotation"
+
 type SessionData struct {
 	SessionID           string
 	PID                 int
+	DuplicatePIDs       []int // other live processes exporting under this session ID, see Store.SetDuplicatePIDs
 	Terminal            string
 	CWD                 string
+	Repo                string // repository of the session's process, see Store.UpdateGit
+	Branch              string // its checked-out branch
 	Model               string
 	TotalCost           float64
 	TotalTokens         int64
@@ -26,9 +33,19 @@ type SessionData struct {
 	Metrics []Metric
 	Events  []Event
 
+	// Spans holds the session's newest trace spans in start order, when
+	// Claude Code exports traces. See MaxSessionSpans.
+	Spans []Span
+
 	Metadata SessionMetadata
 
 	PreviousValues map[string]float64
+
+	// ContextTokens is, per model, the context the session's latest
+	// api_request sent: input, cache read and cache creation tokens. Each
+	// request resends the conversation, so this is how full the model's
+	// context window is now.
+	ContextTokens map[string]int64
 }
 
 type SessionMetadata struct {
@@ -36,6 +53,20 @@ type SessionMetadata struct {
 	OSType         string
 	OSVersion      string
 	HostArch       string
+	// HostName, ServiceInstanceID and Environment come from the host.name,
+	// service.instance.id and deployment.environment resource attributes.
+	HostName          string
+	ServiceInstanceID string
+	Environment       string
+	// User labels who ran the session; it is set for sessions pushed to
+	// `cc-top serve` by another instance.
+	User string
+	// Tags come from the cc_top.tags resource attribute, e.g.
+	// OTEL_RESOURCE_ATTRIBUTES=cc_top.tags=experiment,demo.
+	Tags []string
+	// ExpectedDuration comes from the cc_top.expected_duration resource
+	// attribute (a Go duration such as "45m"); zero means no SLA timer.
+	ExpectedDuration time.Duration
 }
 
 func (s *SessionData) Status() SessionStatus {
@@ -70,6 +101,24 @@ type Event struct {
 	Sequence   int64
 }
 
+// Span is one OTLP trace span, such as a tool execution or an API call.
+// IDs are hex-encoded.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Start        time.Time
+	End          time.Time
+	Failed       bool // the span's status is ERROR
+	Attributes   map[string]string
+}
+
+// Duration returns how long the span ran.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
 type SessionStatus string
 
 const (
@@ -87,3 +136,58 @@ type DailySummary struct {
 	APIRequests  int
 	APIErrors    int
 }
+
+// Write queue thresholds for WriterStats.Backlogged.
+const (
+	QueueHighPercent  = 80
+	QueueBacklogAfter = time.Minute
+)
+
+// WriterStats describes a persistent store's write queue and its recent
+// flushes to disk. Batch and flush figures cover the last flushes only.
+type WriterStats struct {
+	QueueDepth    int
+	QueueCapacity int
+	// HighSince is when the queue last rose above QueueHighPercent; zero
+	// while it is below.
+	HighSince time.Time
+
+	Flushes   int64
+	LastBatch int
+	AvgBatch  float64
+	MaxBatch  int
+	LastFlush time.Duration
+	AvgFlush  time.Duration
+	MaxFlush  time.Duration
+	Dropped   int64
+
+	// Role is the store's part when several cc-top instances share the
+	// database: StoreRoleLeader, StoreRoleStandby or StoreRoleReadOnly.
+	Role string
+}
+
+// Store roles reported in WriterStats.Role.
+const (
+	// StoreRoleLeader holds the maintenance lease: it prunes and vacuums
+	// the database and records stats snapshots.
+	StoreRoleLeader = "leader"
+	// StoreRoleStandby writes telemetry while another instance maintains
+	// the database, and takes over if that one stops.
+	StoreRoleStandby = "standby"
+	// StoreRoleReadOnly reads a database another instance writes.
+	StoreRoleReadOnly = "read-only"
+)
+
+// QueuePercent returns how full the write queue is, in percent.
+func (w WriterStats) QueuePercent() float64 {
+	if w.QueueCapacity <= 0 {
+		return 0
+	}
+	return float64(w.QueueDepth) / float64(w.QueueCapacity) * 100
+}
+
+// Backlogged reports whether the queue has stayed above QueueHighPercent
+// for QueueBacklogAfter, which comes before dropped writes.
+func (w WriterStats) Backlogged(now time.Time) bool {
+	return !w.HighSince.IsZero() && now.Sub(w.HighSince) >= QueueBacklogAfter
+}
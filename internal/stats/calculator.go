@@ -7,20 +7,65 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nixlim/cc-top/internal/state"
 )
 
+// Latency averaging methods accepted by WithLatencyAverage.
+const (
+	LatencyAvgMean       = "mean"
+	LatencyAvgTrimmed    = "trimmed"
+	LatencyAvgWinsorized = "winsorized"
+)
+
 // Calculator computes aggregate statistics from state store data.
 type Calculator struct {
-	pricing map[string][4]float64 // model -> [input, output, cacheRead, cacheCreation] per 1M tokens
+	pricing map[string][4]float64            // model -> [input, output, cacheRead, cacheCreation] per 1M tokens
+	tiers   map[string]map[string][4]float64 // service tier -> model -> prices, excluding standard
+
+	latencyAvg  string  // one of the LatencyAvg* methods
+	latencyTrim float64 // fraction cut (or clamped) from each tail, 0-0.5
+
+	minVersion string      // oldest current Claude Code release; empty disables
+	projectOf  ProjectFunc // nil groups projects by session CWD
+
+	contextLimits   map[string]int // model -> context window in tokens
+	contextPressure int            // percent of the window counted as near the limit
+}
+
+// CalculatorOption configures optional Calculator behaviour.
+type CalculatorOption func(*Calculator)
+
+// WithLatencyAverage selects how AvgAPILatency is computed. For the trimmed
+// and winsorized methods, trimPercent of the samples at each end are
+// dropped or clamped to the nearest kept value respectively. Unknown
+// methods fall back to the plain mean.
+func WithLatencyAverage(method string, trimPercent float64) CalculatorOption {
+	return func(c *Calculator) {
+		c.latencyAvg = method
+		c.latencyTrim = trimPercent / 100
+	}
+}
+
+// WithTierPricing sets per-model prices for non-standard service tiers
+// (e.g. "batch", "priority"), used to cost api_request events that report
+// a service_tier.
+func WithTierPricing(tiers map[string]map[string][4]float64) CalculatorOption {
+	return func(c *Calculator) {
+		c.tiers = tiers
+	}
 }
 
 // NewCalculator creates a new Calculator instance.
 // pricing maps model name to [input, output, cacheRead, cacheCreation] price per 1M tokens.
 // Pass nil if pricing is not needed.
-func NewCalculator(pricing map[string][4]float64) *Calculator {
-	return &Calculator{pricing: pricing}
+func NewCalculator(pricing map[string][4]float64, opts ...CalculatorOption) *Calculator {
+	c := &Calculator{pricing: pricing, latencyAvg: LatencyAvgMean}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Compute calculates the full DashboardStats from the given sessions.
@@ -36,7 +81,10 @@ func (c *Calculator) Compute(sessions []state.SessionData) DashboardStats {
 	stats.PRs = c.computeCounterMetric(sessions, "claude_code.pull_request.count")
 	stats.ToolAcceptance = c.computeToolAcceptance(sessions)
 	stats.CacheEfficiency = c.computeCacheEfficiency(sessions)
-	stats.AvgAPILatency = c.computeAvgAPILatency(sessions)
+	latencies := apiLatenciesMS(sessions)
+	stats.AvgAPILatency = c.computeAvgAPILatency(latencies)
+	stats.LatencyAvgMethod = c.latencyAvg
+	stats.LatencyMax, stats.LatencyOutliers = computeLatencyOutliers(latencies)
 	stats.ModelBreakdown = c.computeModelBreakdown(sessions)
 	stats.TopTools = c.computeTopTools(sessions)
 	stats.ErrorRate = c.computeErrorRate(sessions)
@@ -45,10 +93,21 @@ func (c *Calculator) Compute(sessions []state.SessionData) DashboardStats {
 	stats.ErrorCategories = c.computeErrorCategories(sessions)
 	stats.RetryRate = c.computeRetryRate(sessions)
 	stats.ToolPerformance = c.computeToolPerformance(sessions)
-	stats.LatencyPercentiles = c.computeLatencyPercentiles(sessions)
+	stats.ToolFailures = c.computeToolFailures(sessions)
+	stats.LatencyPercentiles = c.computeLatencyPercentiles(latencies)
 	stats.TokenBreakdown = c.computeTokenBreakdown(sessions)
 	stats.CacheSavingsUSD = c.computeCacheSavings(sessions)
+	stats.TierBreakdown, stats.TierSavingsUSD = c.computeTierBreakdown(sessions)
 	stats.MCPToolUsage = c.computeMCPToolUsage(sessions)
+	stats.AccountBreakdown = c.computeAccountBreakdown(sessions)
+	stats.ProjectBreakdown = c.ComputeByProject(sessions)
+	stats.ContextUsage = c.computeContextUsage(sessions)
+	stats.VersionBreakdown = c.computeVersionBreakdown(sessions)
+	stats.MinVersion = c.minVersion
+	stats.HostBreakdown = computeTeamBreakdown(sessions, sessionHost)
+	stats.UserBreakdown = computeTeamBreakdown(sessions, sessionUser)
+	stats.RateLimitPacing = computeRateLimitPacing(sessions)
+	stats.TurnBreakdown = computeTurnBreakdown(sessions)
 
 	return stats
 }
@@ -181,36 +240,86 @@ func (c *Calculator) computeCacheEfficiency(sessions []state.SessionData) float6
 	return cacheRead / denominator
 }
 
-// computeAvgAPILatency calculates the mean duration_ms from api_request
-// events, converted to seconds.
-func (c *Calculator) computeAvgAPILatency(sessions []state.SessionData) float64 {
-	var totalMS float64
-	var count int
-
+// apiLatenciesMS collects duration_ms from all api_request events and
+// returns them sorted ascending.
+func apiLatenciesMS(sessions []state.SessionData) []float64 {
+	var durations []float64
 	for i := range sessions {
 		for _, e := range sessions[i].Events {
 			if e.Name != "claude_code.api_request" {
 				continue
 			}
-			durStr, ok := e.Attributes["duration_ms"]
-			if !ok {
+			durStr := e.Attributes["duration_ms"]
+			if durStr == "" {
 				continue
 			}
 			dur, err := strconv.ParseFloat(durStr, 64)
 			if err != nil {
 				continue
 			}
-			totalMS += dur
-			count++
+			durations = append(durations, dur)
 		}
 	}
+	sort.Float64s(durations)
+	return durations
+}
 
-	if count == 0 {
+// computeAvgAPILatency averages the sorted api_request durations using the
+// configured method, converted to seconds.
+func (c *Calculator) computeAvgAPILatency(sortedMS []float64) float64 {
+	n := len(sortedMS)
+	if n == 0 {
 		return 0
 	}
+
+	k := 0
+	if c.latencyAvg == LatencyAvgTrimmed || c.latencyAvg == LatencyAvgWinsorized {
+		k = int(c.latencyTrim * float64(n))
+		if 2*k >= n {
+			k = (n - 1) / 2
+		}
+	}
+
+	var totalMS float64
+	count := 0
+	switch {
+	case k > 0 && c.latencyAvg == LatencyAvgTrimmed:
+		for _, d := range sortedMS[k : n-k] {
+			totalMS += d
+			count++
+		}
+	case k > 0 && c.latencyAvg == LatencyAvgWinsorized:
+		lo, hi := sortedMS[k], sortedMS[n-1-k]
+		for _, d := range sortedMS {
+			totalMS += max(lo, min(d, hi))
+			count++
+		}
+	default:
+		for _, d := range sortedMS {
+			totalMS += d
+			count++
+		}
+	}
 	return totalMS / float64(count) / 1000.0 // Convert ms to seconds.
 }
 
+// computeLatencyOutliers returns the raw maximum latency in seconds and the
+// number of samples above the upper Tukey fence (Q3 + 1.5*IQR).
+func computeLatencyOutliers(sortedMS []float64) (maxSec float64, outliers int) {
+	if len(sortedMS) == 0 {
+		return 0, 0
+	}
+	q1 := percentile(sortedMS, 0.25)
+	q3 := percentile(sortedMS, 0.75)
+	fence := q3 + 1.5*(q3-q1)
+	for _, d := range sortedMS {
+		if d > fence {
+			outliers++
+		}
+	}
+	return sortedMS[len(sortedMS)-1] / 1000.0, outliers
+}
+
 // computeModelBreakdown aggregates cost and tokens by model from
 // api_request events. Returns sorted by cost descending.
 func (c *Calculator) computeModelBreakdown(sessions []state.SessionData) []ModelStats {
@@ -236,11 +345,8 @@ func (c *Calculator) computeModelBreakdown(sessions []state.SessionData) []Model
 				models[model] = agg
 			}
 
-			if costStr, ok := e.Attributes["cost_usd"]; ok {
-				if cost, err := strconv.ParseFloat(costStr, 64); err == nil {
-					agg.cost += cost
-				}
-			}
+			cost, _ := c.requestCost(e)
+			agg.cost += cost
 
 			// Sum input and output tokens.
 			if inStr, ok := e.Attributes["input_tokens"]; ok {
@@ -393,6 +499,72 @@ func (c *Calculator) computeErrorCategories(sessions []state.SessionData) map[st
 	return cats
 }
 
+// Rate limit pacing parameters. The window is measured back from the most
+// recent 429; the margin keeps the suggestion slightly below the throughput
+// that actually succeeded.
+const (
+	pacingWindow       = 5 * time.Minute
+	pacingSafetyMargin = 0.9
+)
+
+// computeRateLimitPacing looks at API traffic in the pacingWindow leading up
+// to the most recent 429 and suggests a requests/min ceiling: the successful
+// request rate over that window less a safety margin, or half the attempted
+// rate if nothing succeeded.
+func computeRateLimitPacing(sessions []state.SessionData) RateLimitPacing {
+	var last time.Time
+	for i := range sessions {
+		for _, e := range sessions[i].Events {
+			if e.Name == "claude_code.api_error" && e.Attributes["status_code"] == "429" && e.Timestamp.After(last) {
+				last = e.Timestamp
+			}
+		}
+	}
+	if last.IsZero() {
+		return RateLimitPacing{}
+	}
+
+	windowStart := last.Add(-pacingWindow)
+	earliest := last
+	var limited, succeeded, attempted int
+	for i := range sessions {
+		for _, e := range sessions[i].Events {
+			if e.Timestamp.Before(windowStart) || e.Timestamp.After(last) {
+				continue
+			}
+			switch e.Name {
+			case "claude_code.api_request":
+				succeeded++
+			case "claude_code.api_error":
+				if e.Attributes["status_code"] == "429" {
+					limited++
+				}
+			default:
+				continue
+			}
+			attempted++
+			if e.Timestamp.Before(earliest) {
+				earliest = e.Timestamp
+			}
+		}
+	}
+
+	// Divide by the span actually covered, but never less than a minute so a
+	// tight burst isn't extrapolated into an inflated rate.
+	minutes := max(last.Sub(earliest).Minutes(), 1)
+	p := RateLimitPacing{
+		RateLimited: limited,
+		LastAt:      last,
+		ObservedRPM: float64(attempted) / minutes,
+	}
+	if succeeded > 0 {
+		p.SuggestedRPM = float64(succeeded) / minutes * pacingSafetyMargin
+	} else {
+		p.SuggestedRPM = p.ObservedRPM / 2
+	}
+	return p
+}
+
 // computeRetryRate returns the fraction of api_error events with attempt >= 2.
 // Returns 0 when there are no api_error events.
 func (c *Calculator) computeRetryRate(sessions []state.SessionData) float64 {
@@ -469,36 +641,57 @@ func (c *Calculator) computeToolPerformance(sessions []state.SessionData) []Tool
 	return result
 }
 
-// computeLatencyPercentiles computes P50, P95, P99 from api_request
-// duration_ms values. Returns all zeros when no events exist.
-func (c *Calculator) computeLatencyPercentiles(sessions []state.SessionData) LatencyPercentiles {
-	var durations []float64
+// computeToolFailures computes the failure rate per tool_name from
+// tool_result events, most failures first. Events without a success
+// attribute are not counted.
+func (c *Calculator) computeToolFailures(sessions []state.SessionData) []ToolFailureRate {
+	byTool := make(map[string]*ToolFailureRate)
 	for i := range sessions {
 		for _, e := range sessions[i].Events {
-			if e.Name != "claude_code.api_request" {
+			if e.Name != "claude_code.tool_result" {
 				continue
 			}
-			durStr := e.Attributes["duration_ms"]
-			if durStr == "" {
+			toolName := e.Attributes["tool_name"]
+			success := e.Attributes["success"]
+			if toolName == "" || success == "" {
 				continue
 			}
-			dur, err := strconv.ParseFloat(durStr, 64)
-			if err != nil {
-				continue
+			r := byTool[toolName]
+			if r == nil {
+				r = &ToolFailureRate{ToolName: toolName}
+				byTool[toolName] = r
+			}
+			r.Calls++
+			if success != "true" {
+				r.Failures++
 			}
-			durations = append(durations, dur)
 		}
 	}
 
-	if len(durations) == 0 {
-		return LatencyPercentiles{}
+	result := make([]ToolFailureRate, 0, len(byTool))
+	for _, r := range byTool {
+		r.Rate = float64(r.Failures) / float64(r.Calls)
+		result = append(result, *r)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Failures != result[j].Failures {
+			return result[i].Failures > result[j].Failures
+		}
+		return result[i].ToolName < result[j].ToolName
+	})
+	return result
+}
 
-	sort.Float64s(durations)
+// computeLatencyPercentiles computes P50, P95, P99 from sorted api_request
+// durations in ms. Returns all zeros when no events exist.
+func (c *Calculator) computeLatencyPercentiles(sortedMS []float64) LatencyPercentiles {
+	if len(sortedMS) == 0 {
+		return LatencyPercentiles{}
+	}
 	return LatencyPercentiles{
-		P50: percentile(durations, 0.50) / 1000.0,
-		P95: percentile(durations, 0.95) / 1000.0,
-		P99: percentile(durations, 0.99) / 1000.0,
+		P50: percentile(sortedMS, 0.50) / 1000.0,
+		P95: percentile(sortedMS, 0.95) / 1000.0,
+		P99: percentile(sortedMS, 0.99) / 1000.0,
 	}
 }
 
@@ -576,6 +769,45 @@ func (c *Calculator) computeCacheSavings(sessions []state.SessionData) float64 {
 	return totalSavings
 }
 
+// computeAccountBreakdown aggregates session cost and tokens by the
+// org_id/user_uuid pair. Sessions that have not reported account attributes
+// are grouped under an empty account. Returns sorted by cost descending.
+func (c *Calculator) computeAccountBreakdown(sessions []state.SessionData) []AccountStats {
+	type accountKey struct {
+		org  string
+		user string
+	}
+	accounts := make(map[accountKey]*AccountStats)
+
+	for i := range sessions {
+		key := accountKey{org: sessions[i].OrgID, user: sessions[i].UserUUID}
+		agg, ok := accounts[key]
+		if !ok {
+			agg = &AccountStats{OrgID: key.org, UserUUID: key.user}
+			accounts[key] = agg
+		}
+		agg.TotalCost += sessions[i].TotalCost
+		agg.TotalTokens += sessions[i].TotalTokens
+		agg.SessionCount++
+	}
+
+	result := make([]AccountStats, 0, len(accounts))
+	for _, agg := range accounts {
+		result = append(result, *agg)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		if result[i].OrgID != result[j].OrgID {
+			return result[i].OrgID < result[j].OrgID
+		}
+		return result[i].UserUUID < result[j].UserUUID
+	})
+	return result
+}
+
 // computeMCPToolUsage counts MCP tool usage from tool_result events
 // by checking tool_parameters JSON for mcp_server_name and mcp_tool_name.
 func (c *Calculator) computeMCPToolUsage(sessions []state.SessionData) map[string]int {
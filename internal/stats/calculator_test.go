@@ -2,6 +2,7 @@ package stats
 
 import (
 	"math"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -363,6 +364,85 @@ func TestStatsCalc_AvgLatency(t *testing.T) {
 	})
 }
 
+func TestStatsCalc_LatencySmoothing(t *testing.T) {
+	// Nine 1s requests and one 300s timeout.
+	var events []state.Event
+	for i := 0; i < 9; i++ {
+		events = append(events, state.Event{
+			Name:       "claude_code.api_request",
+			Attributes: map[string]string{"duration_ms": "1000"},
+		})
+	}
+	events = append(events, state.Event{
+		Name:       "claude_code.api_request",
+		Attributes: map[string]string{"duration_ms": "300000"},
+	})
+	sessions := []state.SessionData{{SessionID: "sess-001", Events: events}}
+
+	tests := []struct {
+		name    string
+		method  string
+		trim    float64
+		wantAvg float64
+	}{
+		{"mean", LatencyAvgMean, 10, 30.9},
+		{"trimmed", LatencyAvgTrimmed, 10, 1.0},
+		{"winsorized", LatencyAvgWinsorized, 10, 1.0},
+		{"trimmed with zero trim", LatencyAvgTrimmed, 0, 30.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := NewCalculator(nil, WithLatencyAverage(tt.method, tt.trim)).Compute(sessions)
+			if math.Abs(stats.AvgAPILatency-tt.wantAvg) > 0.001 {
+				t.Errorf("AvgAPILatency = %f, want %f", stats.AvgAPILatency, tt.wantAvg)
+			}
+			if stats.LatencyAvgMethod != tt.method {
+				t.Errorf("LatencyAvgMethod = %q, want %q", stats.LatencyAvgMethod, tt.method)
+			}
+			if stats.LatencyMax != 300 {
+				t.Errorf("LatencyMax = %f, want 300", stats.LatencyMax)
+			}
+			if stats.LatencyOutliers != 1 {
+				t.Errorf("LatencyOutliers = %d, want 1", stats.LatencyOutliers)
+			}
+		})
+	}
+}
+
+func TestModelFamily(t *testing.T) {
+	tests := []struct{ model, want string }{
+		{"claude-sonnet-4-5-20250929", "sonnet"},
+		{"claude-sonnet-4-5-20251101", "sonnet"},
+		{"claude-3-5-haiku-20241022", "haiku"},
+		{"claude-opus-4-1", "opus"},
+		{"Claude-Opus-4", "opus"},
+		{"gpt-4o-20240806", "gpt-4o"},
+		{"custom-model", "custom-model"},
+	}
+	for _, tt := range tests {
+		if got := ModelFamily(tt.model); got != tt.want {
+			t.Errorf("ModelFamily(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestGroupByFamily(t *testing.T) {
+	got := GroupByFamily([]ModelStats{
+		{Model: "claude-sonnet-4-5-20250929", TotalCost: 1, TotalTokens: 100},
+		{Model: "claude-opus-4-1-20250805", TotalCost: 1.5, TotalTokens: 50},
+		{Model: "claude-sonnet-4-5-20251101", TotalCost: 2, TotalTokens: 200},
+		{Model: "claude-haiku-4-5-20251001", TotalCost: 0.1, TotalTokens: 10},
+	})
+	want := []ModelStats{
+		{Model: "sonnet", TotalCost: 3, TotalTokens: 300, Variants: 2},
+		{Model: "opus", TotalCost: 1.5, TotalTokens: 50, Variants: 1},
+		{Model: "haiku", TotalCost: 0.1, TotalTokens: 10, Variants: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByFamily = %+v, want %+v", got, want)
+	}
+}
+
 func TestStatsCalc_ModelBreakdown(t *testing.T) {
 	sessions := []state.SessionData{
 		{
@@ -888,6 +968,40 @@ func TestStatsCalc_ToolPerformance(t *testing.T) {
 	}
 }
 
+func TestStatsCalc_ToolFailures(t *testing.T) {
+	sessions := []state.SessionData{
+		{
+			SessionID: "sess-001",
+			Events: []state.Event{
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Bash", "success": "false"}},
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Bash", "success": "false"}},
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Bash", "success": "true"}},
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Bash", "success": "true"}},
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Read", "success": "true"}},
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Edit", "success": "false"}},
+				{Name: "claude_code.tool_result", Attributes: map[string]string{"tool_name": "Grep"}}, // no success
+			},
+		},
+	}
+
+	stats := NewCalculator(nil).Compute(sessions)
+
+	if len(stats.ToolFailures) != 3 {
+		t.Fatalf("expected 3 tools, got %+v", stats.ToolFailures)
+	}
+	// Most failures first, ties by name.
+	want := []ToolFailureRate{
+		{ToolName: "Bash", Calls: 4, Failures: 2, Rate: 0.5},
+		{ToolName: "Edit", Calls: 1, Failures: 1, Rate: 1},
+		{ToolName: "Read", Calls: 1, Failures: 0, Rate: 0},
+	}
+	for i, w := range want {
+		if stats.ToolFailures[i] != w {
+			t.Errorf("ToolFailures[%d] = %+v, want %+v", i, stats.ToolFailures[i], w)
+		}
+	}
+}
+
 func TestStatsCalc_LatencyPercentiles(t *testing.T) {
 	t.Run("normal percentiles", func(t *testing.T) {
 		// Create 100 events with durations 1000..100000 ms (1s..100s).
@@ -1117,3 +1231,126 @@ func makeAPIEvents(requests, errors int) []state.Event {
 	}
 	return events
 }
+
+func TestStatsCalc_AccountBreakdown(t *testing.T) {
+	sessions := []state.SessionData{
+		{SessionID: "s1", OrgID: "org-a", UserUUID: "u1", TotalCost: 1.50, TotalTokens: 1000},
+		{SessionID: "s2", OrgID: "org-a", UserUUID: "u1", TotalCost: 2.00, TotalTokens: 3000},
+		{SessionID: "s3", OrgID: "org-b", UserUUID: "u2", TotalCost: 5.00, TotalTokens: 500},
+		{SessionID: "s4", TotalCost: 0.25},
+	}
+
+	calc := NewCalculator(nil)
+	got := calc.Compute(sessions).AccountBreakdown
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 accounts, got %d", len(got))
+	}
+	if got[0].OrgID != "org-b" || got[0].TotalCost != 5.00 {
+		t.Errorf("expected org-b first by cost, got %+v", got[0])
+	}
+	if got[1].OrgID != "org-a" || got[1].SessionCount != 2 || got[1].TotalTokens != 4000 {
+		t.Errorf("unexpected org-a aggregate: %+v", got[1])
+	}
+	if got[2].OrgID != "" || got[2].UserUUID != "" {
+		t.Errorf("expected unattributed sessions last, got %+v", got[2])
+	}
+	if math.Abs(got[1].TotalCost-3.50) > 1e-9 {
+		t.Errorf("org-a cost: want 3.50, got %f", got[1].TotalCost)
+	}
+}
+
+func TestStatsCalc_RateLimitPacing(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var events []state.Event
+	// 40 successful requests over the 4 minutes before the last 429, plus
+	// an old burst that falls outside the pacing window.
+	for i := range 40 {
+		events = append(events, state.Event{Name: "claude_code.api_request", Timestamp: base.Add(time.Duration(i) * 6 * time.Second)})
+	}
+	events = append(events,
+		state.Event{Name: "claude_code.api_error", Attributes: map[string]string{"status_code": "429"}, Timestamp: base.Add(-time.Hour)},
+		state.Event{Name: "claude_code.api_error", Attributes: map[string]string{"status_code": "429"}, Timestamp: base.Add(3 * time.Minute)},
+		state.Event{Name: "claude_code.api_error", Attributes: map[string]string{"status_code": "500"}, Timestamp: base.Add(3 * time.Minute)},
+		state.Event{Name: "claude_code.api_error", Attributes: map[string]string{"status_code": "429"}, Timestamp: base.Add(4 * time.Minute)},
+	)
+
+	calc := NewCalculator(nil)
+	rl := calc.Compute([]state.SessionData{{SessionID: "sess-001", Events: events}}).RateLimitPacing
+
+	if rl.RateLimited != 2 {
+		t.Errorf("RateLimited = %d, want 2", rl.RateLimited)
+	}
+	if !rl.LastAt.Equal(base.Add(4 * time.Minute)) {
+		t.Errorf("LastAt = %v, want %v", rl.LastAt, base.Add(4*time.Minute))
+	}
+	// 43 attempts over 4 minutes.
+	if math.Abs(rl.ObservedRPM-10.75) > 0.001 {
+		t.Errorf("ObservedRPM = %v, want 10.75", rl.ObservedRPM)
+	}
+	// 40 successes over 4 minutes, less the 10% margin.
+	if math.Abs(rl.SuggestedRPM-9) > 0.001 {
+		t.Errorf("SuggestedRPM = %v, want 9", rl.SuggestedRPM)
+	}
+
+	if got := calc.Compute([]state.SessionData{{SessionID: "sess-002"}}).RateLimitPacing; got.RateLimited != 0 || got.SuggestedRPM != 0 {
+		t.Errorf("expected zero pacing without 429s, got %+v", got)
+	}
+}
+
+func TestStatsCalc_TierBreakdown(t *testing.T) {
+	pricing := map[string][4]float64{
+		"sonnet-4.5": {3.0, 15.0, 0.3, 3.75},
+	}
+	tiers := map[string]map[string][4]float64{
+		"batch":    {"sonnet-4.5": {1.5, 7.5, 0.15, 1.875}},
+		"priority": {"other": {9, 9, 9, 9}},
+	}
+	req := func(tier, cost string) state.Event {
+		attrs := map[string]string{
+			"model":         "sonnet-4.5",
+			"input_tokens":  "1000000",
+			"output_tokens": "100000",
+		}
+		if tier != "" {
+			attrs["service_tier"] = tier
+		}
+		if cost != "" {
+			attrs["cost_usd"] = cost
+		}
+		return state.Event{Name: "claude_code.api_request", Attributes: attrs}
+	}
+	sessions := []state.SessionData{{
+		SessionID: "sess-001",
+		Events: []state.Event{
+			req("", "4.50"),
+			req("batch", "4.50"),
+			req("batch", ""),
+			// No priority prices for this model: reported cost is kept.
+			req("priority", "4.50"),
+		},
+	}}
+
+	stats := NewCalculator(pricing, WithTierPricing(tiers)).Compute(sessions)
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.001 }
+
+	byTier := make(map[string]TierStats)
+	for _, ts := range stats.TierBreakdown {
+		byTier[ts.Tier] = ts
+	}
+	if got := byTier["batch"]; got.Requests != 2 || !near(got.CostUSD, 4.50) || !near(got.StandardCostUSD, 9.00) {
+		t.Errorf("batch tier: got %+v, want 2 requests, $4.50 actual, $9.00 standard", got)
+	}
+	if got := byTier["standard"]; got.Requests != 1 || !near(got.CostUSD, 4.50) {
+		t.Errorf("standard tier: got %+v", got)
+	}
+	if got := byTier["priority"]; !near(got.CostUSD, got.StandardCostUSD) {
+		t.Errorf("priority without prices should keep the reported cost, got %+v", got)
+	}
+	if !near(stats.TierSavingsUSD, 4.50) {
+		t.Errorf("TierSavingsUSD: want 4.50, got %f", stats.TierSavingsUSD)
+	}
+	if len(stats.ModelBreakdown) != 1 || !near(stats.ModelBreakdown[0].TotalCost, 13.50) {
+		t.Errorf("model breakdown should use tier-adjusted cost, got %+v", stats.ModelBreakdown)
+	}
+}
@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// ContextUsage describes how much of its context window a model's requests
+// used: the largest request, and how many came close to the limit.
+type ContextUsage struct {
+	Model      string  `json:"model"`
+	MaxTokens  int64   `json:"max_tokens"`  // input + cache read + cache creation of the largest request
+	Limit      int     `json:"limit"`       // 0 when the model's limit is unknown
+	MaxPercent float64 `json:"max_percent"` // MaxTokens as a percentage of Limit, 0 when unknown
+	Requests   int     `json:"requests"`
+	NearLimit  int     `json:"near_limit"` // requests at or above the pressure percentage
+}
+
+// WithContextLimits sets the context window size per model and the
+// percentage of it above which a request counts as near the limit, for
+// ContextUsage.
+func WithContextLimits(limits map[string]int, pressurePercent int) CalculatorOption {
+	return func(c *Calculator) {
+		c.contextLimits = limits
+		c.contextPressure = pressurePercent
+	}
+}
+
+// computeContextUsage finds each model's largest api_request context, most
+// utilized model first. The context of a request is everything sent to the
+// model, cached or not.
+func (c *Calculator) computeContextUsage(sessions []state.SessionData) []ContextUsage {
+	byModel := make(map[string]*ContextUsage)
+	for i := range sessions {
+		for _, e := range sessions[i].Events {
+			if e.Name != "claude_code.api_request" || e.Attributes["model"] == "" {
+				continue
+			}
+			var tokens int64
+			for _, attr := range []string{"input_tokens", "cache_read_tokens", "cache_creation_tokens"} {
+				n, _ := strconv.ParseInt(e.Attributes[attr], 10, 64)
+				tokens += n
+			}
+
+			model := e.Attributes["model"]
+			u, ok := byModel[model]
+			if !ok {
+				u = &ContextUsage{Model: model, Limit: c.contextLimits[model]}
+				byModel[model] = u
+			}
+			u.Requests++
+			u.MaxTokens = max(u.MaxTokens, tokens)
+			if u.Limit > 0 && float64(tokens)*100 >= float64(u.Limit)*float64(c.contextPressure) {
+				u.NearLimit++
+			}
+		}
+	}
+	if len(byModel) == 0 {
+		return nil
+	}
+
+	result := make([]ContextUsage, 0, len(byModel))
+	for _, u := range byModel {
+		if u.Limit > 0 {
+			u.MaxPercent = float64(u.MaxTokens) / float64(u.Limit) * 100
+		}
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MaxPercent != result[j].MaxPercent {
+			return result[i].MaxPercent > result[j].MaxPercent
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
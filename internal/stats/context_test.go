@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestStatsCalc_ContextUsage(t *testing.T) {
+	request := func(model, input, cacheRead, cacheCreation string) state.Event {
+		return state.Event{Name: "claude_code.api_request", Attributes: map[string]string{
+			"model": model, "input_tokens": input,
+			"cache_read_tokens": cacheRead, "cache_creation_tokens": cacheCreation,
+		}}
+	}
+	sessions := []state.SessionData{
+		{Events: []state.Event{
+			request("claude-opus-4-6", "2000", "150000", "10000"), // 162k, 81%
+			request("claude-opus-4-6", "1000", "40000", "0"),
+			request("claude-haiku-4-5", "500", "20000", "0"),
+		}},
+		{Events: []state.Event{
+			request("claude-opus-4-6", "3000", "170000", "5000"), // 178k, 89%
+			request("local-model", "9000", "", ""),
+			{Name: "claude_code.api_error", Attributes: map[string]string{"model": "claude-opus-4-6"}},
+		}},
+	}
+
+	calc := NewCalculator(nil, WithContextLimits(map[string]int{
+		"claude-opus-4-6":  200000,
+		"claude-haiku-4-5": 200000,
+	}, 80))
+	got := calc.Compute(sessions).ContextUsage
+
+	if len(got) != 3 {
+		t.Fatalf("want 3 models, got %+v", got)
+	}
+	opus := got[0]
+	if opus.Model != "claude-opus-4-6" || opus.MaxTokens != 178000 || opus.Requests != 3 || opus.NearLimit != 2 {
+		t.Errorf("opus: got %+v", opus)
+	}
+	if opus.MaxPercent < 88.99 || opus.MaxPercent > 89.01 {
+		t.Errorf("opus max percent = %.2f, want 89", opus.MaxPercent)
+	}
+	if got[1].Model != "claude-haiku-4-5" || got[1].NearLimit != 0 {
+		t.Errorf("haiku should come second with no near-limit requests, got %+v", got[1])
+	}
+	// Models without a known limit keep their token count but no percentage.
+	if last := got[2]; last.Model != "local-model" || last.MaxTokens != 9000 || last.Limit != 0 || last.MaxPercent != 0 {
+		t.Errorf("unknown model: got %+v", last)
+	}
+
+	if usage := NewCalculator(nil).Compute(nil).ContextUsage; usage != nil {
+		t.Errorf("no requests should give no usage, got %+v", usage)
+	}
+}
@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// modelFamilies are the Claude model families, matched anywhere in a model
+// ID so versioned and dated IDs like claude-sonnet-4-5-20250929 group
+// together.
+var modelFamilies = []string{"opus", "sonnet", "haiku"}
+
+// dateSuffix matches the release date Anthropic model IDs end with.
+var dateSuffix = regexp.MustCompile(`-\d{8}$`)
+
+// ModelFamily returns the family of a model ID: "opus", "sonnet" or "haiku"
+// for Claude models, and the ID without its date suffix otherwise.
+func ModelFamily(model string) string {
+	lower := strings.ToLower(model)
+	for _, f := range modelFamilies {
+		if strings.Contains(lower, f) {
+			return f
+		}
+	}
+	return dateSuffix.ReplaceAllString(model, "")
+}
+
+// GroupByFamily merges the model stats of each family into one entry named
+// after the family, sorted by cost descending. Variants counts the model IDs
+// merged into it.
+func GroupByFamily(models []ModelStats) []ModelStats {
+	byFamily := make(map[string]*ModelStats)
+	for _, ms := range models {
+		f := ModelFamily(ms.Model)
+		agg, ok := byFamily[f]
+		if !ok {
+			agg = &ModelStats{Model: f}
+			byFamily[f] = agg
+		}
+		agg.TotalCost += ms.TotalCost
+		agg.TotalTokens += ms.TotalTokens
+		agg.Variants++
+	}
+
+	result := make([]ModelStats, 0, len(byFamily))
+	for _, agg := range byFamily {
+		result = append(result, *agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
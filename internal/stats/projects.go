@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// ProjectStats aggregates the sessions that ran in one project: a repository
+// root, or the working directory when it is not inside a repository.
+type ProjectStats struct {
+	Project      string  `json:"project"` // empty when the directory is unknown
+	SessionCount int     `json:"session_count"`
+	TotalCost    float64 `json:"total_cost"`
+	TotalTokens  int64   `json:"total_tokens"`
+	LinesAdded   int     `json:"lines_added"`
+	LinesRemoved int     `json:"lines_removed"`
+	Commits      int     `json:"commits"`
+	APIRequests  int     `json:"api_requests"`
+	APIErrors    int     `json:"api_errors"`
+	ErrorRate    float64 `json:"error_rate"` // 0-1
+	// Branches splits the project's cost by git branch, most expensive
+	// first. It is empty when no session's branch is known.
+	Branches []BranchStats `json:"branches,omitempty"`
+}
+
+// BranchStats aggregates the sessions of a project that ran on one branch.
+type BranchStats struct {
+	Branch       string  `json:"branch"` // empty when unknown
+	SessionCount int     `json:"session_count"`
+	TotalCost    float64 `json:"total_cost"`
+	TotalTokens  int64   `json:"total_tokens"`
+}
+
+// ProjectFunc resolves the project a session belongs to.
+type ProjectFunc func(s state.SessionData) string
+
+// WithProjectFunc sets how sessions are grouped in ProjectBreakdown
+// (default: the session's CWD).
+func WithProjectFunc(fn ProjectFunc) CalculatorOption {
+	return func(c *Calculator) {
+		c.projectOf = fn
+	}
+}
+
+// ComputeByProject groups sessions by project and returns their cost,
+// tokens, lines changed, commits and API error rate, most expensive first.
+func (c *Calculator) ComputeByProject(sessions []state.SessionData) []ProjectStats {
+	groups := make(map[string][]state.SessionData)
+	for i := range sessions {
+		project := sessions[i].CWD
+		if c.projectOf != nil {
+			project = c.projectOf(sessions[i])
+		}
+		groups[project] = append(groups[project], sessions[i])
+	}
+
+	result := make([]ProjectStats, 0, len(groups))
+	for project, group := range groups {
+		ps := ProjectStats{Project: project, SessionCount: len(group)}
+		for i := range group {
+			ps.TotalCost += group[i].TotalCost
+			ps.TotalTokens += group[i].TotalTokens
+			for _, e := range group[i].Events {
+				switch e.Name {
+				case "claude_code.api_request":
+					ps.APIRequests++
+				case "claude_code.api_error":
+					ps.APIErrors++
+				}
+			}
+		}
+		ps.Branches = computeByBranch(group)
+		ps.LinesAdded, ps.LinesRemoved = c.computeLinesOfCode(group)
+		ps.Commits = c.computeCounterMetric(group, "claude_code.commit.count")
+		if ps.APIRequests > 0 {
+			ps.ErrorRate = float64(ps.APIErrors) / float64(ps.APIRequests)
+		}
+		result = append(result, ps)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Project < result[j].Project
+	})
+	return result
+}
+
+// computeByBranch splits sessions by branch, or returns nil when none has
+// a known branch.
+func computeByBranch(sessions []state.SessionData) []BranchStats {
+	index := make(map[string]int)
+	var result []BranchStats
+	known := false
+	for i := range sessions {
+		b := sessions[i].Branch
+		known = known || b != ""
+		j, ok := index[b]
+		if !ok {
+			j = len(result)
+			index[b] = j
+			result = append(result, BranchStats{Branch: b})
+		}
+		result[j].SessionCount++
+		result[j].TotalCost += sessions[i].TotalCost
+		result[j].TotalTokens += sessions[i].TotalTokens
+	}
+	if !known {
+		return nil
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Branch < result[j].Branch
+	})
+	return result
+}
@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestStatsCalc_ComputeByProject(t *testing.T) {
+	session := func(cwd string, cost float64, tokens int64, added, commits float64, requests, errors int) state.SessionData {
+		s := state.SessionData{CWD: cwd, TotalCost: cost, TotalTokens: tokens}
+		s.Metrics = []state.Metric{
+			{Name: "claude_code.lines_of_code.count", Value: added, Attributes: map[string]string{"type": "added"}},
+			{Name: "claude_code.lines_of_code.count", Value: added / 2, Attributes: map[string]string{"type": "removed"}},
+			{Name: "claude_code.commit.count", Value: commits},
+		}
+		for range requests {
+			s.Events = append(s.Events, state.Event{Name: "claude_code.api_request"})
+		}
+		for range errors {
+			s.Events = append(s.Events, state.Event{Name: "claude_code.api_error"})
+		}
+		return s
+	}
+	sessions := []state.SessionData{
+		session("/src/api", 1.00, 1000, 10, 1, 4, 1),
+		session("/src/web/ui", 3.00, 5000, 40, 2, 10, 0),
+		session("/src/api/internal", 2.00, 2000, 20, 1, 4, 1),
+		session("", 0.50, 100, 0, 0, 0, 0),
+	}
+
+	// Group nested directories under their repository.
+	root := func(s state.SessionData) string {
+		for _, repo := range []string{"/src/api", "/src/web"} {
+			if strings.HasPrefix(s.CWD, repo) {
+				return repo
+			}
+		}
+		return s.CWD
+	}
+	got := NewCalculator(nil, WithProjectFunc(root)).ComputeByProject(sessions)
+	want := []ProjectStats{
+		{Project: "/src/api", SessionCount: 2, TotalCost: 3.00, TotalTokens: 3000, LinesAdded: 30, LinesRemoved: 15,
+			Commits: 2, APIRequests: 8, APIErrors: 2, ErrorRate: 0.25},
+		{Project: "/src/web", SessionCount: 1, TotalCost: 3.00, TotalTokens: 5000, LinesAdded: 40, LinesRemoved: 20,
+			Commits: 2, APIRequests: 10},
+		{Project: "", SessionCount: 1, TotalCost: 0.50, TotalTokens: 100},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeByProject:\n got %+v\nwant %+v", got, want)
+	}
+
+	// Without a project func sessions are grouped by CWD, and Compute
+	// includes the breakdown.
+	ds := NewCalculator(nil).Compute(sessions)
+	if len(ds.ProjectBreakdown) != 4 {
+		t.Errorf("expected one project per CWD, got %+v", ds.ProjectBreakdown)
+	}
+}
+
+func TestStatsCalc_ComputeByProjectBranches(t *testing.T) {
+	sessions := []state.SessionData{
+		{CWD: "/src/api", Branch: "main", TotalCost: 1, TotalTokens: 100},
+		{CWD: "/src/api", Branch: "feature", TotalCost: 4, TotalTokens: 400},
+		{CWD: "/src/api", Branch: "main", TotalCost: 2, TotalTokens: 200},
+		{CWD: "/src/api", TotalCost: 0.5},
+		{CWD: "/src/web", TotalCost: 1},
+	}
+	got := NewCalculator(nil).ComputeByProject(sessions)
+	want := []BranchStats{
+		{Branch: "feature", SessionCount: 1, TotalCost: 4, TotalTokens: 400},
+		{Branch: "main", SessionCount: 2, TotalCost: 3, TotalTokens: 300},
+		{Branch: "", SessionCount: 1, TotalCost: 0.5},
+	}
+	if !reflect.DeepEqual(got[0].Branches, want) {
+		t.Errorf("branches of /src/api:\n got %+v\nwant %+v", got[0].Branches, want)
+	}
+	if got[1].Branches != nil {
+		t.Errorf("no branch is known for /src/web, got %+v", got[1].Branches)
+	}
+}
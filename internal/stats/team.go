@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// TeamStats holds the cost and sessions of one host or user, for the team
+// view of `cc-top serve`.
+type TeamStats struct {
+	Name         string // empty for sessions that did not report one
+	TotalCost    float64
+	TotalTokens  int64
+	SessionCount int
+	Active       int // sessions with activity in the last 30 seconds
+}
+
+// computeTeamBreakdown groups sessions by the name returned by key, most
+// expensive first.
+func computeTeamBreakdown(sessions []state.SessionData, key func(*state.SessionData) string) []TeamStats {
+	groups := make(map[string]*TeamStats)
+	for i := range sessions {
+		name := key(&sessions[i])
+		agg, ok := groups[name]
+		if !ok {
+			agg = &TeamStats{Name: name}
+			groups[name] = agg
+		}
+		agg.TotalCost += sessions[i].TotalCost
+		agg.TotalTokens += sessions[i].TotalTokens
+		agg.SessionCount++
+		if sessions[i].Status() == state.StatusActive {
+			agg.Active++
+		}
+	}
+
+	result := make([]TeamStats, 0, len(groups))
+	for _, agg := range groups {
+		result = append(result, *agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func sessionHost(s *state.SessionData) string { return s.Metadata.HostName }
+
+func sessionUser(s *state.SessionData) string { return s.Metadata.User }
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestTeamBreakdown(t *testing.T) {
+	session := func(host, user string, cost float64, active bool) state.SessionData {
+		s := state.SessionData{TotalCost: cost, TotalTokens: int64(cost * 1000), LastEventAt: time.Now().Add(-time.Hour)}
+		if active {
+			s.LastEventAt = time.Now()
+		}
+		s.Metadata.HostName = host
+		s.Metadata.User = user
+		return s
+	}
+	sessions := []state.SessionData{
+		session("laptop-a", "alice", 2, true),
+		session("build-box", "bob", 5, false),
+		session("laptop-a", "alice", 1, false),
+		session("", "", 0.5, true),
+	}
+
+	got := NewCalculator(nil).Compute(sessions)
+	wantHosts := []TeamStats{
+		{Name: "build-box", TotalCost: 5, TotalTokens: 5000, SessionCount: 1},
+		{Name: "laptop-a", TotalCost: 3, TotalTokens: 3000, SessionCount: 2, Active: 1},
+		{Name: "", TotalCost: 0.5, TotalTokens: 500, SessionCount: 1, Active: 1},
+	}
+	if !reflect.DeepEqual(got.HostBreakdown, wantHosts) {
+		t.Errorf("HostBreakdown =\n%+v\nwant\n%+v", got.HostBreakdown, wantHosts)
+	}
+	if len(got.UserBreakdown) != 3 || got.UserBreakdown[0].Name != "bob" || got.UserBreakdown[1].Name != "alice" {
+		t.Errorf("UserBreakdown = %+v, want bob, alice, unknown", got.UserBreakdown)
+	}
+}
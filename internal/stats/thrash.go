@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// DefaultThrashThreshold is the number of reads or edits of the same file,
+// with no commit in between, at which a session is flagged as possibly
+// thrashing.
+const DefaultThrashThreshold = 5
+
+// FileThrash holds the repeated-access counts for a single file since the
+// session's last commit.
+type FileThrash struct {
+	Path  string
+	Reads int
+	Edits int
+}
+
+// ThrashReport summarises duplicated work within a session. Files lists only
+// the files at or above the threshold, sorted by total accesses descending.
+type ThrashReport struct {
+	Files      []FileThrash
+	LastCommit time.Time // zero if no commit has been observed
+}
+
+// PossibleThrash reports whether any file crossed the threshold.
+func (r ThrashReport) PossibleThrash() bool {
+	return len(r.Files) > 0
+}
+
+// DetectThrash scans a session's tool_result events for files that were read
+// or edited at least threshold times without an intervening commit. Commits
+// are recognised from successful "git commit" Bash calls and from increases
+// of the claude_code.commit.count metric; either resets all file counters.
+// A threshold below 1 uses DefaultThrashThreshold.
+func DetectThrash(session state.SessionData, threshold int) ThrashReport {
+	if threshold < 1 {
+		threshold = DefaultThrashThreshold
+	}
+
+	commits := commitTimes(session.Metrics)
+	nextCommit := 0
+
+	var report ThrashReport
+	counts := make(map[string]*FileThrash)
+
+	for _, e := range session.Events {
+		if e.Name != "claude_code.tool_result" {
+			continue
+		}
+
+		for nextCommit < len(commits) && !commits[nextCommit].After(e.Timestamp) {
+			report.LastCommit = commits[nextCommit]
+			counts = make(map[string]*FileThrash)
+			nextCommit++
+		}
+
+		toolName := e.Attributes["tool_name"]
+		params := e.Attributes["tool_parameters"]
+
+		if toolName == "Bash" {
+			cmd := extractJSONString(params, "bash_command")
+			if cmd == "" {
+				cmd = extractJSONString(params, "command")
+			}
+			if strings.Contains(cmd, "git commit") && e.Attributes["success"] == "true" {
+				report.LastCommit = e.Timestamp
+				counts = make(map[string]*FileThrash)
+			}
+			continue
+		}
+
+		var isEdit bool
+		switch toolName {
+		case "Read":
+		case "Edit", "MultiEdit", "Write", "NotebookEdit":
+			isEdit = true
+		default:
+			continue
+		}
+
+		path := extractFilePath(params)
+		if path == "" {
+			continue
+		}
+
+		ft, ok := counts[path]
+		if !ok {
+			ft = &FileThrash{Path: path}
+			counts[path] = ft
+		}
+		if isEdit {
+			ft.Edits++
+		} else {
+			ft.Reads++
+		}
+	}
+
+	for _, ft := range counts {
+		if ft.Reads >= threshold || ft.Edits >= threshold {
+			report.Files = append(report.Files, *ft)
+		}
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		ti := report.Files[i].Reads + report.Files[i].Edits
+		tj := report.Files[j].Reads + report.Files[j].Edits
+		if ti != tj {
+			return ti > tj
+		}
+		return report.Files[i].Path < report.Files[j].Path
+	})
+
+	return report
+}
+
+// commitTimes returns the timestamps at which the cumulative
+// claude_code.commit.count metric increased, in ascending order.
+func commitTimes(metrics []state.Metric) []time.Time {
+	var times []time.Time
+	var last float64
+	for _, m := range metrics {
+		if m.Name != "claude_code.commit.count" {
+			continue
+		}
+		if m.Value > last {
+			times = append(times, m.Timestamp)
+		}
+		last = m.Value
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// extractFilePath returns the target file of a file tool from its
+// tool_parameters JSON.
+func extractFilePath(toolParams string) string {
+	for _, k := range []string{"file_path", "notebook_path", "path"} {
+		if p := extractJSONString(toolParams, k); p != "" {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func toolEvent(tool, params string, at time.Time) state.Event {
+	return state.Event{
+		Name:      "claude_code.tool_result",
+		Timestamp: at,
+		Attributes: map[string]string{
+			"tool_name":       tool,
+			"success":         "true",
+			"tool_parameters": params,
+		},
+	}
+}
+
+func TestDetectThrash_RepeatedReads(t *testing.T) {
+	base := time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC)
+	var evts []state.Event
+	for i := range 5 {
+		evts = append(evts, toolEvent("Read", `{"file_path":"/repo/main.go"}`, base.Add(time.Duration(i)*time.Second)))
+	}
+	evts = append(evts, toolEvent("Edit", `{"file_path":"/repo/other.go"}`, base.Add(10*time.Second)))
+
+	report := DetectThrash(state.SessionData{Events: evts}, 5)
+	if !report.PossibleThrash() {
+		t.Fatal("expected possible thrash")
+	}
+	if len(report.Files) != 1 {
+		t.Fatalf("expected 1 flagged file, got %d", len(report.Files))
+	}
+	if report.Files[0].Path != "/repo/main.go" || report.Files[0].Reads != 5 {
+		t.Errorf("unexpected file thrash: %+v", report.Files[0])
+	}
+}
+
+func TestDetectThrash_CommitResetsCounts(t *testing.T) {
+	base := time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC)
+	var evts []state.Event
+	for i := range 3 {
+		evts = append(evts, toolEvent("Edit", `{"file_path":"a.go"}`, base.Add(time.Duration(i)*time.Second)))
+	}
+	evts = append(evts, toolEvent("Bash", `{"bash_command":"git commit -m wip"}`, base.Add(5*time.Second)))
+	for i := range 3 {
+		evts = append(evts, toolEvent("Edit", `{"file_path":"a.go"}`, base.Add(time.Duration(10+i)*time.Second)))
+	}
+
+	report := DetectThrash(state.SessionData{Events: evts}, 5)
+	if report.PossibleThrash() {
+		t.Errorf("commit should reset counts, got %+v", report.Files)
+	}
+	if !report.LastCommit.Equal(base.Add(5 * time.Second)) {
+		t.Errorf("LastCommit = %v", report.LastCommit)
+	}
+}
+
+func TestDetectThrash_CommitMetricResetsCounts(t *testing.T) {
+	base := time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC)
+	var evts []state.Event
+	for i := range 6 {
+		evts = append(evts, toolEvent("Write", `{"file_path":"a.go"}`, base.Add(time.Duration(i)*time.Minute)))
+	}
+	session := state.SessionData{
+		Events: evts,
+		Metrics: []state.Metric{
+			{Name: "claude_code.commit.count", Value: 1, Timestamp: base.Add(150 * time.Second)},
+		},
+	}
+
+	report := DetectThrash(session, 5)
+	if report.PossibleThrash() {
+		t.Errorf("commit metric should reset counts, got %+v", report.Files)
+	}
+
+	report = DetectThrash(session, 3)
+	if len(report.Files) != 1 || report.Files[0].Edits != 3 {
+		t.Errorf("expected 3 edits after commit, got %+v", report.Files)
+	}
+}
+
+func TestDetectThrash_Empty(t *testing.T) {
+	report := DetectThrash(state.SessionData{}, 0)
+	if report.PossibleThrash() {
+		t.Error("empty session should not thrash")
+	}
+}
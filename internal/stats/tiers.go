@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// standardTier is the service tier assumed when a request does not report one.
+const standardTier = "standard"
+
+// requestTier returns the service tier of an api_request event.
+func requestTier(e state.Event) string {
+	if t := e.Attributes["service_tier"]; t != "" {
+		return t
+	}
+	return standardTier
+}
+
+// requestCost returns what an api_request event cost on its service tier
+// and what it would have cost at standard prices. Claude Code reports
+// cost_usd at standard prices, so for other tiers the cost is recomputed
+// from the request's token counts when the tier has prices for its model;
+// otherwise the reported cost is used for both.
+func (c *Calculator) requestCost(e state.Event) (actual, standard float64) {
+	model := e.Attributes["model"]
+	if v, err := strconv.ParseFloat(e.Attributes["cost_usd"], 64); err == nil {
+		standard = v
+	} else if prices, ok := c.pricing[model]; ok {
+		standard = priceRequest(e, prices)
+	}
+
+	tier := requestTier(e)
+	if tier == standardTier {
+		return standard, standard
+	}
+	if prices, ok := c.tiers[tier][model]; ok {
+		return priceRequest(e, prices), standard
+	}
+	return standard, standard
+}
+
+// priceRequest prices an api_request event's token counts with
+// [input, output, cacheRead, cacheCreation] prices per 1M tokens.
+func priceRequest(e state.Event, prices [4]float64) float64 {
+	var cost float64
+	for i, attr := range [4]string{"input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens"} {
+		if n, err := strconv.ParseInt(e.Attributes[attr], 10, 64); err == nil {
+			cost += float64(n) * prices[i] / 1_000_000.0
+		}
+	}
+	return cost
+}
+
+// computeTierBreakdown aggregates api_request cost by service tier and
+// returns the total saved versus standard prices (negative when premium
+// tiers cost more). Returns sorted by request count descending.
+func (c *Calculator) computeTierBreakdown(sessions []state.SessionData) ([]TierStats, float64) {
+	tiers := make(map[string]*TierStats)
+	var savings float64
+
+	for i := range sessions {
+		for _, e := range sessions[i].Events {
+			if e.Name != "claude_code.api_request" {
+				continue
+			}
+			tier := requestTier(e)
+			agg, ok := tiers[tier]
+			if !ok {
+				agg = &TierStats{Tier: tier}
+				tiers[tier] = agg
+			}
+			actual, standard := c.requestCost(e)
+			agg.Requests++
+			agg.CostUSD += actual
+			agg.StandardCostUSD += standard
+			savings += standard - actual
+		}
+	}
+
+	result := make([]TierStats, 0, len(tiers))
+	for _, agg := range tiers {
+		result = append(result, *agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].Tier < result[j].Tier
+	})
+	return result, savings
+}
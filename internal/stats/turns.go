@@ -0,0 +1,161 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// TurnBreakdown splits the wall time of conversation turns into API latency,
+// tool execution, other agent time (streaming, hooks, local processing) and
+// user wait. A turn runs from a user prompt to the next one; the user wait
+// is the gap between the turn's last event and that next prompt, so the
+// latest turn of each session is counted without it.
+type TurnBreakdown struct {
+	Turns    int
+	API      time.Duration
+	Tool     time.Duration
+	Other    time.Duration
+	UserWait time.Duration
+}
+
+// Wall returns the total time covered by the breakdown.
+func (b TurnBreakdown) Wall() time.Duration {
+	return b.API + b.Tool + b.Other + b.UserWait
+}
+
+// interval is a span of time within a turn.
+type interval struct {
+	start, end time.Time
+}
+
+// computeTurnBreakdown aggregates the per-turn split over all sessions.
+// Overlapping API requests or tool calls (parallel tools, subagents) count
+// once, and time covered by both an API request and a tool call counts as
+// API time, so the parts always add up to the turn's wall time.
+func computeTurnBreakdown(sessions []state.SessionData) TurnBreakdown {
+	var b TurnBreakdown
+	for i := range sessions {
+		addSessionTurns(&b, sessions[i].Events)
+	}
+	return b
+}
+
+func addSessionTurns(b *TurnBreakdown, events []state.Event) {
+	var (
+		inTurn    bool
+		turnStart time.Time
+		lastEnd   time.Time
+		api, tool []interval
+	)
+	closeTurn := func(next *time.Time) {
+		if !inTurn {
+			return
+		}
+		b.Turns++
+		apiTime := unionLength(api)
+		toolTime := unionLength(subtract(tool, api))
+		b.API += apiTime
+		b.Tool += toolTime
+		b.Other += max(0, lastEnd.Sub(turnStart)-apiTime-toolTime)
+		if next != nil && next.After(lastEnd) {
+			b.UserWait += next.Sub(lastEnd)
+		}
+	}
+
+	for _, e := range events {
+		switch e.Name {
+		case "claude_code.user_prompt":
+			ts := e.Timestamp
+			closeTurn(&ts)
+			inTurn = true
+			turnStart, lastEnd = ts, ts
+			api, tool = api[:0], tool[:0]
+			continue
+		case "claude_code.api_request", "claude_code.tool_result":
+		default:
+			if inTurn && e.Timestamp.After(lastEnd) {
+				lastEnd = e.Timestamp
+			}
+			continue
+		}
+		if !inTurn {
+			continue
+		}
+
+		// Events are emitted when the request or tool call completes.
+		end := e.Timestamp
+		start := end
+		if ms, err := strconv.ParseFloat(e.Attributes["duration_ms"], 64); err == nil && ms > 0 {
+			start = end.Add(-time.Duration(ms * float64(time.Millisecond)))
+		}
+		if start.Before(turnStart) {
+			start = turnStart
+		}
+		if end.After(lastEnd) {
+			lastEnd = end
+		}
+		if !end.After(start) {
+			continue
+		}
+		if e.Name == "claude_code.api_request" {
+			api = append(api, interval{start, end})
+		} else {
+			tool = append(tool, interval{start, end})
+		}
+	}
+	closeTurn(nil)
+}
+
+// merge sorts spans and joins overlapping ones.
+func merge(spans []interval) []interval {
+	if len(spans) == 0 {
+		return nil
+	}
+	sorted := append([]interval(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+	out := []interval{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &out[len(out)-1]
+		if s.start.After(last.end) {
+			out = append(out, s)
+			continue
+		}
+		if s.end.After(last.end) {
+			last.end = s.end
+		}
+	}
+	return out
+}
+
+func unionLength(spans []interval) time.Duration {
+	var d time.Duration
+	for _, s := range merge(spans) {
+		d += s.end.Sub(s.start)
+	}
+	return d
+}
+
+// subtract returns the parts of spans not covered by cut.
+func subtract(spans, cut []interval) []interval {
+	cut = merge(cut)
+	var out []interval
+	for _, s := range merge(spans) {
+		start := s.start
+		for _, c := range cut {
+			if !c.end.After(start) || !c.start.Before(s.end) {
+				continue
+			}
+			if c.start.After(start) {
+				out = append(out, interval{start, c.start})
+			}
+			start = c.end
+		}
+		if s.end.After(start) {
+			out = append(out, interval{start, s.end})
+		}
+	}
+	return out
+}
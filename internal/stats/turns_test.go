@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func turnEvent(name string, at time.Time, durationMS string) state.Event {
+	e := state.Event{Name: name, Timestamp: at, Attributes: map[string]string{}}
+	if durationMS != "" {
+		e.Attributes["duration_ms"] = durationMS
+	}
+	return e
+}
+
+func TestComputeTurnBreakdown(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return t0.Add(time.Duration(sec) * time.Second) }
+
+	session := state.SessionData{Events: []state.Event{
+		turnEvent("claude_code.api_request", at(-30), "5000"), // before the first prompt: ignored
+		// Turn 1: 0s-60s active, then 40s of user wait.
+		turnEvent("claude_code.user_prompt", at(0), ""),
+		turnEvent("claude_code.api_request", at(10), "8000"),  // 2-10
+		turnEvent("claude_code.tool_result", at(30), "15000"), // 15-30
+		turnEvent("claude_code.tool_result", at(28), "8000"),  // 20-28, parallel to the one above
+		turnEvent("claude_code.api_request", at(50), "20000"), // 30-50
+		turnEvent("claude_code.tool_result", at(45), "10000"), // 35-45, overlaps the API call
+		turnEvent("claude_code.tool_decision", at(60), ""),
+		// Turn 2: still running, no user wait yet.
+		turnEvent("claude_code.user_prompt", at(100), ""),
+		turnEvent("claude_code.api_request", at(110), "10000"),
+	}}
+
+	b := computeTurnBreakdown([]state.SessionData{session})
+	if b.Turns != 2 {
+		t.Errorf("turns: got %d, want 2", b.Turns)
+	}
+	if b.API != 38*time.Second {
+		t.Errorf("API: got %v, want 38s", b.API)
+	}
+	if b.Tool != 15*time.Second {
+		t.Errorf("tool: got %v, want 15s (overlaps counted once)", b.Tool)
+	}
+	if b.Other != 17*time.Second {
+		t.Errorf("other: got %v, want 17s", b.Other)
+	}
+	if b.UserWait != 40*time.Second {
+		t.Errorf("user wait: got %v, want 40s", b.UserWait)
+	}
+	if b.Wall() != 110*time.Second {
+		t.Errorf("wall: got %v, want 110s", b.Wall())
+	}
+}
+
+func TestComputeTurnBreakdown_NoPrompts(t *testing.T) {
+	session := state.SessionData{Events: []state.Event{
+		turnEvent("claude_code.api_request", time.Now(), "1000"),
+	}}
+	if b := computeTurnBreakdown([]state.SessionData{session}); b != (TurnBreakdown{}) {
+		t.Errorf("expected an empty breakdown without prompts, got %+v", b)
+	}
+}
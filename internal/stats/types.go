@@ -1,5 +1,7 @@
 package stats
 
+import "time"
+
 // DashboardStats holds aggregate statistics computed from session data.
 type DashboardStats struct {
 	LinesAdded    int
@@ -8,7 +10,10 @@ type DashboardStats struct {
 	PRs           int
 	ToolAcceptance    map[string]float64 // tool name -> acceptance rate (0-1)
 	CacheEfficiency   float64            // 0-1
-	AvgAPILatency     float64            // seconds
+	AvgAPILatency     float64            // seconds, averaged per LatencyAvgMethod
+	LatencyAvgMethod  string             // mean, trimmed or winsorized
+	LatencyMax        float64            // seconds, raw maximum
+	LatencyOutliers   int                // samples above Q3 + 1.5*IQR
 	ModelBreakdown    []ModelStats
 	TopTools          []ToolUsage
 	ErrorRate         float64 // 0-1
@@ -17,10 +22,47 @@ type DashboardStats struct {
 	ErrorCategories   map[string]int     // category -> count (rate_limit, auth_failure, server_error, other)
 	RetryRate         float64            // fraction of api_error events with attempt >= 2
 	ToolPerformance   []ToolPerf
+	ToolFailures      []ToolFailureRate
 	LatencyPercentiles LatencyPercentiles
 	TokenBreakdown    map[string]int64   // input, output, cacheRead, cacheCreation
 	CacheSavingsUSD   float64
 	MCPToolUsage      map[string]int     // "server:tool" -> count
+	AccountBreakdown  []AccountStats
+	RateLimitPacing   RateLimitPacing
+	TierBreakdown     []TierStats
+	TierSavingsUSD    float64 // saved vs standard prices by batch/priority tiers
+	TurnBreakdown     TurnBreakdown
+
+	// VersionBreakdown groups sessions by Claude Code release; MinVersion is
+	// the configured minimum they are compared with, if any.
+	VersionBreakdown []VersionStats
+	MinVersion       string
+
+	// HostBreakdown and UserBreakdown group sessions by machine and by the
+	// user who pushed them to `cc-top serve`.
+	HostBreakdown []TeamStats
+	UserBreakdown []TeamStats
+
+	ProjectBreakdown []ProjectStats // see Calculator.ComputeByProject
+	ContextUsage     []ContextUsage // per model, see WithContextLimits
+}
+
+// TierStats holds api_request cost for one service tier (standard, batch,
+// priority).
+type TierStats struct {
+	Tier            string
+	Requests        int
+	CostUSD         float64 // at the tier's prices
+	StandardCostUSD float64 // the same requests at standard prices
+}
+
+// RateLimitPacing summarises the most recent burst of 429 responses and the
+// request rate that would likely have stayed under the limit.
+type RateLimitPacing struct {
+	RateLimited  int       // 429s in the pacing window; 0 when none seen
+	LastAt       time.Time // most recent 429
+	ObservedRPM  float64   // requests/min attempted in the window, including 429s
+	SuggestedRPM float64   // suggested ceiling in requests/min
 }
 
 // ModelStats holds per-model cost and token data.
@@ -28,6 +70,17 @@ type ModelStats struct {
 	Model       string
 	TotalCost   float64
 	TotalTokens int64
+	Variants    int // model IDs merged by GroupByFamily; 0 for a single model
+}
+
+// AccountStats holds cost data for a single Anthropic account, identified by
+// the org_id/user_uuid pair reported in session resource attributes.
+type AccountStats struct {
+	OrgID        string  `json:"org_id"`
+	UserUUID     string  `json:"user_uuid"`
+	TotalCost    float64 `json:"total_cost"`
+	TotalTokens  int64   `json:"total_tokens"`
+	SessionCount int     `json:"session_count"`
 }
 
 // ToolUsage holds tool frequency data.
@@ -43,6 +96,15 @@ type ToolPerf struct {
 	P95DurationMS float64
 }
 
+// ToolFailureRate holds how often a tool's calls failed, from the success
+// attribute of tool_result events.
+type ToolFailureRate struct {
+	ToolName string
+	Calls    int
+	Failures int
+	Rate     float64 // 0-1
+}
+
 // LatencyPercentiles holds API latency percentile values in seconds.
 type LatencyPercentiles struct {
 	P50 float64
@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// VersionStats counts the sessions, machines and users running one Claude
+// Code release, from the service.version resource attribute.
+type VersionStats struct {
+	Version      string // empty when sessions did not report a version
+	SessionCount int
+	Hosts        []string // distinct host.name values, sorted; empty names are omitted
+	Users        int      // distinct user_uuid values, including unknown
+	Outdated     bool     // older than the configured minimum version
+}
+
+// WithMinVersion sets the oldest Claude Code release considered current.
+// Versions below it are marked Outdated in VersionBreakdown.
+func WithMinVersion(v string) CalculatorOption {
+	return func(c *Calculator) {
+		c.minVersion = v
+	}
+}
+
+// computeVersionBreakdown groups sessions by Claude Code version, newest
+// first, with sessions that reported no version last.
+func (c *Calculator) computeVersionBreakdown(sessions []state.SessionData) []VersionStats {
+	type versionAgg struct {
+		stats VersionStats
+		hosts map[string]bool
+		users map[string]bool
+	}
+	versions := make(map[string]*versionAgg)
+
+	for i := range sessions {
+		v := sessions[i].Metadata.ServiceVersion
+		agg, ok := versions[v]
+		if !ok {
+			agg = &versionAgg{
+				stats: VersionStats{Version: v},
+				hosts: make(map[string]bool),
+				users: make(map[string]bool),
+			}
+			versions[v] = agg
+		}
+		agg.stats.SessionCount++
+		if host := sessions[i].Metadata.HostName; host != "" {
+			agg.hosts[host] = true
+		}
+		agg.users[sessions[i].UserUUID] = true
+	}
+
+	result := make([]VersionStats, 0, len(versions))
+	for _, agg := range versions {
+		vs := agg.stats
+		for host := range agg.hosts {
+			vs.Hosts = append(vs.Hosts, host)
+		}
+		sort.Strings(vs.Hosts)
+		vs.Users = len(agg.users)
+		vs.Outdated = c.minVersion != "" && vs.Version != "" && CompareVersions(vs.Version, c.minVersion) < 0
+		result = append(result, vs)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Version == "") != (result[j].Version == "") {
+			return result[j].Version == ""
+		}
+		return CompareVersions(result[i].Version, result[j].Version) > 0
+	})
+	return result
+}
+
+// CompareVersions compares two dotted release numbers such as "2.0.14"
+// numerically, returning -1, 0 or 1. A pre-release or build suffix
+// ("2.0.14-beta.1") sorts before the release itself.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+	for i := 0; i < max(len(aCore), len(bCore)); i++ {
+		var x, y uint64
+		if i < len(aCore) {
+			x = aCore[i]
+		}
+		if i < len(bCore) {
+			y = bCore[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return strings.Compare(aPre, bPre)
+}
+
+// splitVersion splits a version into its numeric parts and any suffix after
+// "-" or "+". Non-numeric parts count as 0.
+func splitVersion(v string) ([]uint64, string) {
+	v = strings.TrimPrefix(v, "v")
+	var suffix string
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v, suffix = v[:i], v[i+1:]
+	}
+	parts := strings.Split(v, ".")
+	nums := make([]uint64, len(parts))
+	for i, p := range parts {
+		nums[i], _ = strconv.ParseUint(p, 10, 64)
+	}
+	return nums, suffix
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.0.14", "2.0.14", 0},
+		{"2.0.9", "2.0.14", -1},
+		{"2.1", "2.0.14", 1},
+		{"2.0", "2.0.0", 0},
+		{"2.0.14-beta.1", "2.0.14", -1},
+		{"v2.0.15", "2.0.14", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStatsCalc_VersionBreakdown(t *testing.T) {
+	session := func(version, host, user string) state.SessionData {
+		s := state.SessionData{UserUUID: user}
+		s.Metadata.ServiceVersion = version
+		s.Metadata.HostName = host
+		return s
+	}
+	sessions := []state.SessionData{
+		session("2.0.9", "laptop-b", "u2"),
+		session("2.0.14", "laptop-a", "u1"),
+		session("", "", ""),
+		session("2.0.9", "laptop-c", "u2"),
+		session("2.0.14", "laptop-a", "u1"),
+	}
+
+	got := NewCalculator(nil, WithMinVersion("2.0.10")).Compute(sessions)
+	want := []VersionStats{
+		{Version: "2.0.14", SessionCount: 2, Hosts: []string{"laptop-a"}, Users: 1},
+		{Version: "2.0.9", SessionCount: 2, Hosts: []string{"laptop-b", "laptop-c"}, Users: 1, Outdated: true},
+		{Version: "", SessionCount: 1, Users: 1},
+	}
+	if !reflect.DeepEqual(got.VersionBreakdown, want) {
+		t.Errorf("VersionBreakdown =\n%+v\nwant\n%+v", got.VersionBreakdown, want)
+	}
+	if got.MinVersion != "2.0.10" {
+		t.Errorf("MinVersion = %q, want 2.0.10", got.MinVersion)
+	}
+
+	for _, vs := range NewCalculator(nil).Compute(sessions).VersionBreakdown {
+		if vs.Outdated {
+			t.Errorf("without a minimum no version should be outdated, got %+v", vs)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"database/sql"
+	"log"
+	"time"
+)
+
+// alertAckRow holds an alert_acks change. Delete removes the row.
+type alertAckRow struct {
+	Key     string
+	Until   string // RFC3339, empty for an acknowledgment
+	AckedAt string
+	Delete  bool
+}
+
+// SaveAlertAck implements the alerts.AckStore interface.
+func (s *SQLiteStore) SaveAlertAck(key string, until time.Time) {
+	row := &alertAckRow{Key: key, AckedAt: time.Now().UTC().Format(time.RFC3339)}
+	if !until.IsZero() {
+		row.Until = until.UTC().Format(time.RFC3339)
+	}
+	s.sendWrite(writeOp{opType: "alertAck", ack: row})
+}
+
+// DeleteAlertAck implements the alerts.AckStore interface.
+func (s *SQLiteStore) DeleteAlertAck(key string) {
+	s.sendWrite(writeOp{opType: "alertAck", ack: &alertAckRow{Key: key, Delete: true}})
+}
+
+// LoadAlertAcks implements the alerts.AckStore interface. Snoozes that have
+// already ended are skipped.
+func (s *SQLiteStore) LoadAlertAcks() map[string]time.Time {
+	rows, err := s.db.Query("SELECT alert_key, until FROM alert_acks")
+	if err != nil {
+		log.Printf("ERROR: querying alert acks: %v", err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	now := time.Now()
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var key, until string
+		if err := rows.Scan(&key, &until); err != nil {
+			log.Printf("ERROR: scanning alert ack: %v", err)
+			continue
+		}
+		var t time.Time
+		if until != "" {
+			if t, err = time.Parse(time.RFC3339, until); err != nil || !now.Before(t) {
+				continue
+			}
+		}
+		result[key] = t
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating alert acks: %v", err)
+	}
+	return result
+}
+
+func (s *SQLiteStore) writeAlertAck(tx *sql.Tx, row *alertAckRow) error {
+	if row.Delete {
+		_, err := tx.Exec("DELETE FROM alert_acks WHERE alert_key = ?", row.Key)
+		return err
+	}
+	_, err := tx.Exec(`
+		INSERT INTO alert_acks (alert_key, until, acked_at) VALUES (?, ?, ?)
+		ON CONFLICT(alert_key) DO UPDATE SET until=excluded.until, acked_at=excluded.acked_at
+	`, row.Key, row.Until, row.AckedAt)
+	return err
+}
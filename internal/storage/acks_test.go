@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/records"
+)
+
+func TestSQLiteStore_AlertAcksRoundTrip(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store1, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	snoozeEnd := time.Now().Add(time.Hour).Truncate(time.Second)
+	store1.SaveAlertAck("CostSurge:sess-1", time.Time{})
+	store1.SaveAlertAck("ErrorStorm:sess-2", snoozeEnd)
+	store1.SaveAlertAck("LoopDetector:sess-3", time.Now().Add(-time.Minute))
+	store1.SaveAlertAck("StaleSession:sess-4", time.Time{})
+	store1.DeleteAlertAck("StaleSession:sess-4")
+	_ = store1.Close()
+
+	store2, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (reopen) failed: %v", err)
+	}
+	defer func() { _ = store2.Close() }()
+
+	acks := store2.LoadAlertAcks()
+	if until, ok := acks["CostSurge:sess-1"]; !ok || !until.IsZero() {
+		t.Errorf("acknowledgment not restored: %v, %v", until, ok)
+	}
+	if until := acks["ErrorStorm:sess-2"]; !until.Equal(snoozeEnd) {
+		t.Errorf("snooze end: got %v, want %v", until, snoozeEnd)
+	}
+	if _, ok := acks["LoopDetector:sess-3"]; ok {
+		t.Error("an ended snooze should not be loaded")
+	}
+	if _, ok := acks["StaleSession:sess-4"]; ok {
+		t.Error("a deleted ack should not be loaded")
+	}
+}
+
+func TestSilences(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	now := time.Now().Truncate(time.Second)
+
+	id, err := AddSilence(dbPath, alerts.Silence{Rule: "CostSurge", Reason: "load test", CreatedAt: now, Until: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("AddSilence: %v", err)
+	}
+	if _, err := AddSilence(dbPath, alerts.Silence{Rule: "*", CreatedAt: now, Until: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("AddSilence: %v", err)
+	}
+	if _, err := AddSilence(dbPath, alerts.Silence{Rule: "ErrorStorm", CreatedAt: now.Add(-time.Hour), Until: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("AddSilence: %v", err)
+	}
+
+	store, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	got := store.LoadAlertSilences(now)
+	if len(got) != 2 || got[0].Rule != "*" || got[1].Rule != "CostSurge" {
+		t.Fatalf("want the two silences in effect, soonest ending first; got %+v", got)
+	}
+	if got[1].ID != id || got[1].Reason != "load test" || !got[1].Until.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("unexpected silence %+v", got[1])
+	}
+
+	ended, err := EndSilence(dbPath, id, now)
+	if err != nil || !ended {
+		t.Fatalf("EndSilence = %v, %v", ended, err)
+	}
+	if ended, _ := EndSilence(dbPath, id, now); ended {
+		t.Error("ending an ended silence should report false")
+	}
+	if got, err := ListSilences(dbPath, now); err != nil || len(got) != 1 || got[0].Rule != "*" {
+		t.Errorf("ListSilences after EndSilence = %+v, %v", got, err)
+	}
+}
+
+func TestSQLiteStore_RecordsRoundTrip(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store1, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	at := time.Now().Truncate(time.Second)
+	store1.SaveRecord(records.Record{Name: records.BiggestRequest, Value: 1.5, SessionID: "sess-1", Detail: "claude-opus-4", AchievedAt: at})
+	store1.SaveRecord(records.Record{Name: records.BiggestRequest, Value: 2.5, SessionID: "sess-2", Detail: "claude-opus-4", AchievedAt: at})
+	store1.SaveRecord(records.Record{Name: records.CostliestDay, Value: 40, Detail: "2026-03-12", AchievedAt: at})
+	_ = store1.Close()
+
+	store2, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (reopen) failed: %v", err)
+	}
+	defer func() { _ = store2.Close() }()
+
+	recs := store2.LoadRecords()
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %+v", recs)
+	}
+	if r := recs[records.BiggestRequest]; r.Value != 2.5 || r.SessionID != "sess-2" || !r.AchievedAt.Equal(at) {
+		t.Errorf("the later save should win: %+v", r)
+	}
+	if r := recs[records.CostliestDay]; r.Value != 40 || r.Detail != "2026-03-12" {
+		t.Errorf("costliest day: %+v", r)
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+)
+
+const (
+	// spendBaselineDays is the trailing window of the spend baseline. Burn
+	// rate snapshots are kept for retention_days_raw, so a shorter retention
+	// also shortens the baseline.
+	spendBaselineDays = 7
+
+	// spendBaselineMinHours is one day of active hours; with less history
+	// the AnomalousSpend rule stays quiet.
+	spendBaselineMinHours = 24
+)
+
+// EnableSpendBaseline turns on the rolling spend baseline. It is computed
+// immediately and then refreshed by each hourly maintenance cycle.
+func (s *SQLiteStore) EnableSpendBaseline() {
+	s.baselineMu.Lock()
+	s.baselineEnabled = true
+	s.baselineMu.Unlock()
+
+	if err := s.refreshSpendBaseline(time.Now()); err != nil {
+		log.Printf("WARNING: computing spend baseline: %v", err)
+	}
+}
+
+// SpendBaseline returns the most recently computed baseline. ok is false
+// until enough history has been collected. It implements
+// alerts.BaselineSource.
+func (s *SQLiteStore) SpendBaseline() (alerts.SpendBaseline, bool) {
+	s.baselineMu.RLock()
+	defer s.baselineMu.RUnlock()
+	return s.baseline, s.baselineValid
+}
+
+func (s *SQLiteStore) spendBaselineEnabled() bool {
+	s.baselineMu.RLock()
+	defer s.baselineMu.RUnlock()
+	return s.baselineEnabled
+}
+
+func (s *SQLiteStore) refreshSpendBaseline(now time.Time) error {
+	b, err := s.computeSpendBaseline(now, spendBaselineDays)
+	if err != nil {
+		return err
+	}
+
+	s.baselineMu.Lock()
+	s.baseline = b
+	s.baselineValid = b.Hours >= spendBaselineMinHours
+	s.baselineMu.Unlock()
+	return nil
+}
+
+// computeSpendBaseline averages the burn rate snapshots of each completed
+// UTC hour in the trailing window into that hour's spend, then returns the
+// mean and sample standard deviation across hours. Idle hours are excluded,
+// like for auto thresholds, so that every working hour does not look
+// anomalous against nights and weekends.
+func (s *SQLiteStore) computeSpendBaseline(now time.Time, days int) (alerts.SpendBaseline, error) {
+	from := now.AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	to := now.UTC().Truncate(time.Hour).Format(time.RFC3339)
+
+	rows, err := s.db.Query(`
+		SELECT AVG(hourly_rate) FROM burn_rate_snapshots
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY substr(timestamp, 1, 13)
+		HAVING AVG(hourly_rate) > 0
+	`, from, to)
+	if err != nil {
+		return alerts.SpendBaseline{}, fmt.Errorf("querying burn rate snapshots: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hourly []float64
+	for rows.Next() {
+		var rate float64
+		if err := rows.Scan(&rate); err != nil {
+			return alerts.SpendBaseline{}, fmt.Errorf("scanning hourly spend: %w", err)
+		}
+		hourly = append(hourly, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return alerts.SpendBaseline{}, fmt.Errorf("iterating hourly spend: %w", err)
+	}
+
+	b := alerts.SpendBaseline{Hours: len(hourly), ComputedAt: now}
+	if len(hourly) < 2 {
+		return b, nil
+	}
+	var sum float64
+	for _, v := range hourly {
+		sum += v
+	}
+	b.MeanPerHour = sum / float64(len(hourly))
+	var sq float64
+	for _, v := range hourly {
+		sq += (v - b.MeanPerHour) * (v - b.MeanPerHour)
+	}
+	b.StdDevPerHour = math.Sqrt(sq / float64(len(hourly)-1))
+	return b, nil
+}
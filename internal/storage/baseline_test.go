@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpendBaseline(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 30, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+	insert := func(ts time.Time, rate float64) {
+		t.Helper()
+		_, err := store.db.Exec("INSERT INTO burn_rate_snapshots (timestamp, hourly_rate) VALUES (?, ?)",
+			ts.UTC().Format(time.RFC3339), rate)
+		if err != nil {
+			t.Fatalf("insert burn rate snapshot: %v", err)
+		}
+	}
+	// 30 active hours alternating $2/hr and $4/hr, sampled twice per hour.
+	start := now.Truncate(time.Hour).Add(-30 * time.Hour)
+	for h := range 30 {
+		rate := 2.0
+		if h%2 == 1 {
+			rate = 4.0
+		}
+		insert(start.Add(time.Duration(h)*time.Hour), rate-0.5)
+		insert(start.Add(time.Duration(h)*time.Hour+30*time.Minute), rate+0.5)
+	}
+	// Idle hours, the current hour and snapshots older than the window are
+	// left out.
+	insert(start.Add(-2*time.Hour), 0)
+	insert(now.Truncate(time.Hour).Add(5*time.Minute), 500)
+	insert(now.AddDate(0, 0, -8), 500)
+
+	b, err := store.computeSpendBaseline(now, spendBaselineDays)
+	if err != nil {
+		t.Fatalf("computeSpendBaseline: %v", err)
+	}
+	if b.Hours != 30 {
+		t.Errorf("Hours = %d, want 30", b.Hours)
+	}
+	if b.MeanPerHour != 3 {
+		t.Errorf("MeanPerHour = %v, want 3", b.MeanPerHour)
+	}
+	// Sample standard deviation of 15 twos and 15 fours.
+	if want := math.Sqrt(30.0 / 29.0); math.Abs(b.StdDevPerHour-want) > 1e-9 {
+		t.Errorf("StdDevPerHour = %v, want %v", b.StdDevPerHour, want)
+	}
+
+	if _, ok := store.SpendBaseline(); ok {
+		t.Error("the baseline should be unavailable until enabled")
+	}
+	store.EnableSpendBaseline()
+	if !store.spendBaselineEnabled() {
+		t.Error("EnableSpendBaseline should enable refreshes")
+	}
+}
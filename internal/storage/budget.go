@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+)
+
+// SessionCosts returns the highest cumulative cost each session reported on
+// each day from start on, and the highest it reported before start. Days
+// come from the raw metrics, bucketed in start's location; days whose raw
+// metrics were already pruned come from the daily summaries, which keep the
+// date they were aggregated under. It implements budget.CostHistory.
+func (s *SQLiteStore) SessionCosts(start time.Time) ([]budget.SessionDay, map[string]float64, error) {
+	type sessionDate struct{ session, date string }
+	peak := make(map[sessionDate]float64)
+	from := start.UTC().Format(sqliteDateTime)
+	fromDate := start.Format("2006-01-02")
+
+	rows, err := s.db.Query(`
+		SELECT session_id, timestamp, value FROM metrics
+		WHERE name = 'claude_code.cost.usage' AND datetime(timestamp) >= ?
+	`, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying cost metrics: %w", err)
+	}
+	for rows.Next() {
+		var sessionID, ts string
+		var value float64
+		if err := rows.Scan(&sessionID, &ts, &value); err != nil {
+			_ = rows.Close()
+			return nil, nil, fmt.Errorf("scanning cost metric: %w", err)
+		}
+		at, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			continue
+		}
+		key := sessionDate{sessionID, at.In(start.Location()).Format("2006-01-02")}
+		peak[key] = max(peak[key], value)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating cost metrics: %w", err)
+	}
+
+	rows, err = s.db.Query(`
+		SELECT session_id, date, total_cost FROM daily_summaries WHERE date >= ?
+	`, fromDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying daily summaries: %w", err)
+	}
+	for rows.Next() {
+		var key sessionDate
+		var cost float64
+		if err := rows.Scan(&key.session, &key.date, &cost); err != nil {
+			_ = rows.Close()
+			return nil, nil, fmt.Errorf("scanning daily summary: %w", err)
+		}
+		if _, ok := peak[key]; !ok {
+			peak[key] = cost
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating daily summaries: %w", err)
+	}
+
+	before := make(map[string]float64)
+	rows, err = s.db.Query(`
+		SELECT session_id, MAX(value) FROM metrics
+		WHERE name = 'claude_code.cost.usage' AND datetime(timestamp) < ?
+		GROUP BY session_id
+		UNION ALL
+		SELECT session_id, MAX(total_cost) FROM daily_summaries WHERE date < ?
+		GROUP BY session_id
+	`, from, fromDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying earlier costs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var sessionID string
+		var cost float64
+		if err := rows.Scan(&sessionID, &cost); err != nil {
+			return nil, nil, fmt.Errorf("scanning earlier cost: %w", err)
+		}
+		before[sessionID] = max(before[sessionID], cost)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating earlier costs: %w", err)
+	}
+
+	days := make([]budget.SessionDay, 0, len(peak))
+	for key, cost := range peak {
+		days = append(days, budget.SessionDay{SessionID: key.session, Date: key.date, Cost: cost})
+	}
+	slices.SortFunc(days, func(a, b budget.SessionDay) int {
+		return cmp.Or(cmp.Compare(a.SessionID, b.SessionID), cmp.Compare(a.Date, b.Date))
+	})
+	return days, before, nil
+}
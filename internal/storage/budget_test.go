@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+)
+
+func TestSessionCosts(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 30, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for _, m := range []struct {
+		session, ts string
+		cost        float64
+	}{
+		{"s1", "2026-03-08T23:00:00-05:00", 2},
+		{"s1", "2026-03-09T03:00:00Z", 3}, // still the 8th in New York
+		{"s1", "2026-03-10T02:00:00Z", 5}, // the 9th in New York
+		{"s1", "2026-03-10T15:00:00Z", 7},
+	} {
+		if _, err := store.db.Exec("INSERT INTO metrics (session_id, name, value, timestamp) VALUES (?, 'claude_code.cost.usage', ?, ?)",
+			m.session, m.cost, m.ts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, ds := range []struct {
+		session, date string
+		cost          float64
+	}{
+		{"s1", "2026-03-10", 100}, // the raw metrics win
+		{"s2", "2026-03-09", 4},   // raw metrics already pruned
+		{"s2", "2026-03-01", 1},
+	} {
+		if _, err := store.db.Exec("INSERT INTO daily_summaries (session_id, date, total_cost) VALUES (?, ?, ?)",
+			ds.session, ds.date, ds.cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ny := time.FixedZone("EST", -5*60*60)
+	days, before, err := store.SessionCosts(time.Date(2026, 3, 9, 0, 0, 0, 0, ny))
+	if err != nil {
+		t.Fatalf("SessionCosts: %v", err)
+	}
+	want := []budget.SessionDay{
+		{SessionID: "s1", Date: "2026-03-09", Cost: 5},
+		{SessionID: "s1", Date: "2026-03-10", Cost: 7},
+		{SessionID: "s2", Date: "2026-03-09", Cost: 4},
+	}
+	if !reflect.DeepEqual(days, want) {
+		t.Errorf("days = %+v, want %+v", days, want)
+	}
+	if !reflect.DeepEqual(before, map[string]float64{"s1": 3, "s2": 1}) {
+		t.Errorf("before = %v, want s1 $3 and s2 $1", before)
+	}
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+const (
+	// bulkRowsPerStatement is the number of rows in one multi-row INSERT,
+	// kept under SQLite's historical limit of 999 bound variables.
+	bulkRowsPerStatement = 100
+	// bulkRowsPerTx bounds each transaction so a live writer is never
+	// blocked for longer than busy_timeout.
+	bulkRowsPerTx = 20000
+	// bulkIndexThreshold is the ingest size above which the metrics and
+	// events indexes are dropped and rebuilt once afterwards.
+	bulkIndexThreshold = 100000
+)
+
+// BulkRecord is one metric or event ingested by BulkIngest. Exactly one of
+// Metric and Event is set.
+type BulkRecord struct {
+	SessionID string
+	Metric    *state.Metric
+	Event     *state.Event
+}
+
+// bulkIndexes are the secondary indexes rebuilt after a large ingest.
+var bulkIndexes = []struct{ name, def string }{
+	{"idx_metrics_session", "metrics(session_id)"},
+	{"idx_metrics_name", "metrics(name)"},
+	{"idx_metrics_ts", "metrics(timestamp)"},
+	{"idx_events_session", "events(session_id)"},
+	{"idx_events_name", "events(name)"},
+	{"idx_events_ts", "events(timestamp)"},
+}
+
+// BulkIngest stores many records at once, for replaying or importing
+// telemetry logs. It bypasses the async write channel: rows are written with
+// multi-row INSERTs in large transactions and, for big batches, the metrics
+// and events indexes are rebuilt once at the end instead of per row. The
+// in-memory state is updated as if each record had been received, and the
+// resulting session totals and counter state are persisted.
+//
+// The indexes are only dropped while this store holds the maintenance
+// lease: when another cc-top instance shares the database, its queries
+// would otherwise run unindexed until the ingest finishes. A read-only
+// follower (storage.read_only) refuses to ingest.
+func (s *SQLiteStore) BulkIngest(records []BulkRecord) error {
+	if s.readOnly {
+		return fmt.Errorf("database is opened read-only (storage.read_only)")
+	}
+	for _, r := range records {
+		if r.Metric != nil {
+			s.MemoryStore.AddMetric(r.SessionID, *r.Metric)
+		} else if r.Event != nil {
+			s.MemoryStore.AddEvent(r.SessionID, *r.Event)
+		}
+	}
+
+	deferIndexes := len(records) >= bulkIndexThreshold && s.leader.Load()
+	if err := bulkInsert(s.db, records, deferIndexes); err != nil {
+		return err
+	}
+	return s.persistBulkSessions(records)
+}
+
+// bulkInsert writes the rows of records to db. With deferIndexes the
+// metrics and events indexes are dropped first and rebuilt at the end.
+func bulkInsert(db *sql.DB, records []BulkRecord, deferIndexes bool) (err error) {
+	if deferIndexes {
+		for _, idx := range bulkIndexes {
+			if _, err := db.Exec("DROP INDEX IF EXISTS " + idx.name); err != nil {
+				return fmt.Errorf("dropping %s: %w", idx.name, err)
+			}
+		}
+		// Rebuild even if the ingest fails part-way.
+		defer func() {
+			for _, idx := range bulkIndexes {
+				if _, ierr := db.Exec("CREATE INDEX IF NOT EXISTS " + idx.name + " ON " + idx.def); ierr != nil && err == nil {
+					err = fmt.Errorf("rebuilding %s: %w", idx.name, ierr)
+				}
+			}
+		}()
+	}
+
+	for start := 0; start < len(records); start += bulkRowsPerTx {
+		end := min(start+bulkRowsPerTx, len(records))
+		if err := bulkInsertTx(db, records[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bulkInsertTx(db *sql.DB, records []BulkRecord) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	metrics := newBulkStatement(tx, "INSERT INTO metrics (session_id, name, value, timestamp, attributes) VALUES ", 5)
+	events := newBulkStatement(tx, "INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ", 5)
+	lastSeen := make(map[string]time.Time)
+
+	for _, r := range records {
+		switch {
+		case r.Metric != nil:
+			m := r.Metric
+			if err := metrics.add(r.SessionID, m.Name, m.Value, m.Timestamp.Format(time.RFC3339Nano), attributesJSON(m.Attributes)); err != nil {
+				return fmt.Errorf("inserting metrics: %w", err)
+			}
+			if m.Timestamp.After(lastSeen[r.SessionID]) {
+				lastSeen[r.SessionID] = m.Timestamp
+			}
+		case r.Event != nil:
+			e := r.Event
+			if err := events.add(r.SessionID, e.Name, e.Timestamp.Format(time.RFC3339Nano), e.Sequence, attributesJSON(e.Attributes)); err != nil {
+				return fmt.Errorf("inserting events: %w", err)
+			}
+		}
+	}
+	if err := metrics.flush(); err != nil {
+		return fmt.Errorf("inserting metrics: %w", err)
+	}
+	if err := events.flush(); err != nil {
+		return fmt.Errorf("inserting events: %w", err)
+	}
+
+	for sessionID, ts := range lastSeen {
+		_, err := tx.Exec(`
+			INSERT INTO sessions (session_id, last_event_at) VALUES (?, ?)
+			ON CONFLICT(session_id) DO UPDATE SET last_event_at=excluded.last_event_at
+			WHERE sessions.last_event_at IS NULL OR excluded.last_event_at > sessions.last_event_at
+		`, sessionID, ts.Format(time.RFC3339Nano))
+		if err != nil {
+			return fmt.Errorf("updating session %s: %w", sessionID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// persistBulkSessions writes the session snapshot and counter state of every
+// session touched by records, once per session.
+func (s *SQLiteStore) persistBulkSessions(records []BulkRecord) error {
+	seen := make(map[string]bool)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, r := range records {
+		if seen[r.SessionID] {
+			continue
+		}
+		seen[r.SessionID] = true
+		session := s.GetSession(r.SessionID)
+		if session == nil {
+			continue
+		}
+		if err := s.writeSessionSnapshot(tx, r.SessionID, buildSnapshot(session)); err != nil {
+			return fmt.Errorf("writing session %s: %w", r.SessionID, err)
+		}
+		for key, val := range session.PreviousValues {
+			if err := s.writeCounterState(tx, r.SessionID, key, val); err != nil {
+				return fmt.Errorf("writing counter state for %s: %w", r.SessionID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// attributesJSON encodes attributes the way the async writer does: an empty
+// string when there are none.
+func attributesJSON(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		log.Printf("WARNING: failed to marshal attributes: %v", err)
+		return ""
+	}
+	return string(data)
+}
+
+// bulkStatement accumulates rows into multi-row INSERTs, reusing one
+// prepared statement for every full batch.
+type bulkStatement struct {
+	tx     *sql.Tx
+	prefix string
+	cols   int
+	full   *sql.Stmt
+	args   []any
+}
+
+func newBulkStatement(tx *sql.Tx, prefix string, cols int) *bulkStatement {
+	return &bulkStatement{tx: tx, prefix: prefix, cols: cols, args: make([]any, 0, cols*bulkRowsPerStatement)}
+}
+
+func (b *bulkStatement) add(values ...any) error {
+	b.args = append(b.args, values...)
+	if len(b.args) < b.cols*bulkRowsPerStatement {
+		return nil
+	}
+	if b.full == nil {
+		stmt, err := b.tx.Prepare(b.query(bulkRowsPerStatement))
+		if err != nil {
+			return err
+		}
+		b.full = stmt
+	}
+	_, err := b.full.Exec(b.args...)
+	b.args = b.args[:0]
+	return err
+}
+
+// flush inserts any remaining rows and releases the prepared statement.
+func (b *bulkStatement) flush() error {
+	if b.full != nil {
+		defer b.full.Close()
+	}
+	if len(b.args) == 0 {
+		return nil
+	}
+	_, err := b.tx.Exec(b.query(len(b.args)/b.cols), b.args...)
+	b.args = b.args[:0]
+	return err
+}
+
+func (b *bulkStatement) query(rows int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", b.cols), ", ") + ")"
+	var sb strings.Builder
+	sb.WriteString(b.prefix)
+	for i := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(row)
+	}
+	return sb.String()
+}
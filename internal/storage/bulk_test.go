@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// bulkTestRecords returns n records alternating between a cost metric and an
+// api_request event, spread over two sessions.
+func bulkTestRecords(n int, base time.Time) []BulkRecord {
+	records := make([]BulkRecord, 0, n)
+	for i := range n {
+		sessionID := fmt.Sprintf("bulk-%d", i%2)
+		ts := base.Add(time.Duration(i) * time.Millisecond)
+		if i%2 == 0 {
+			records = append(records, BulkRecord{SessionID: sessionID, Metric: &state.Metric{
+				Name:       "claude_code.cost.usage",
+				Value:      float64(i) / 1000,
+				Attributes: map[string]string{"model": "claude-opus-4-6"},
+				Timestamp:  ts,
+			}})
+		} else {
+			records = append(records, BulkRecord{SessionID: sessionID, Event: &state.Event{
+				Name:       "claude_code.api_request",
+				Attributes: map[string]string{"model": "claude-opus-4-6"},
+				Timestamp:  ts,
+				Sequence:   int64(i),
+			}})
+		}
+	}
+	return records
+}
+
+func TestSQLiteStore_BulkIngest(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	// 1001 records: ten full multi-row statements per kind plus a remainder.
+	base := time.Now().Add(-time.Hour)
+	if err := store.BulkIngest(bulkTestRecords(1001, base)); err != nil {
+		t.Fatalf("BulkIngest: %v", err)
+	}
+
+	for table, want := range map[string]int{"metrics": 501, "events": 500} {
+		if got := countRows(t, store.db, "SELECT COUNT(*) FROM "+table); got != want {
+			t.Errorf("%s: got %d rows, want %d", table, got, want)
+		}
+	}
+
+	// bulk-0 holds the even metrics; its cost counter ends at 1.000.
+	mem := store.GetSession("bulk-0")
+	if mem == nil || mem.TotalCost < 0.999 || mem.TotalCost > 1.001 {
+		t.Fatalf("in-memory session not updated: %+v", mem)
+	}
+	var cost float64
+	var lastEvent string
+	err = store.db.QueryRow("SELECT total_cost, last_event_at FROM sessions WHERE session_id = 'bulk-0'").Scan(&cost, &lastEvent)
+	if err != nil {
+		t.Fatalf("reading session: %v", err)
+	}
+	if cost != mem.TotalCost {
+		t.Errorf("persisted total_cost = %v, want %v", cost, mem.TotalCost)
+	}
+	if want := base.Add(1000 * time.Millisecond).Format(time.RFC3339Nano); lastEvent != want {
+		t.Errorf("last_event_at = %s, want %s", lastEvent, want)
+	}
+	if got := countRows(t, store.db, "SELECT COUNT(*) FROM counter_state WHERE session_id = 'bulk-0'"); got != 1 {
+		t.Errorf("counter_state rows for bulk-0 = %d, want 1", got)
+	}
+	if got := len(store.GetSession("bulk-1").Events); got != 500 {
+		t.Errorf("in-memory events for bulk-1 = %d, want 500", got)
+	}
+}
+
+func TestSQLiteStore_BulkIngestReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "shared.db")
+	writer, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = writer.Close() }()
+
+	ro := Tuning{BusyTimeout: time.Second, BatchSize: 10, FlushInterval: 10 * time.Millisecond, ChannelSize: 10, ReadOnly: true}
+	follower, err := NewSQLiteStore(dbPath, 7, 90, WithTuning(ro))
+	if err != nil {
+		t.Fatalf("read-only NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = follower.Close() }()
+
+	if err := follower.BulkIngest(bulkTestRecords(10, time.Now())); err == nil {
+		t.Error("a read-only store should refuse to ingest")
+	}
+	if n := countRows(t, writer.db, "SELECT COUNT(*) FROM metrics"); n != 0 {
+		t.Errorf("a read-only store wrote %d metrics", n)
+	}
+}
+
+func TestBulkInsert_DeferredIndexes(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := bulkInsert(db, bulkTestRecords(50, time.Now()), true); err != nil {
+		t.Fatalf("bulkInsert: %v", err)
+	}
+	for _, idx := range bulkIndexes {
+		var name string
+		if err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='index' AND name=?", idx.name).Scan(&name); err != nil {
+			t.Errorf("index %s not rebuilt: %v", idx.name, err)
+		}
+	}
+	if got := countRows(t, db, "SELECT COUNT(*) FROM metrics"); got != 25 {
+		t.Errorf("metrics: got %d rows, want 25", got)
+	}
+}
+
+func BenchmarkBulkInsert(b *testing.B) {
+	const n = 100000
+	records := bulkTestRecords(n, time.Now())
+	for b.Loop() {
+		b.StopTimer()
+		db, err := OpenDB(filepath.Join(b.TempDir(), "bench.db"))
+		if err != nil {
+			b.Fatalf("OpenDB: %v", err)
+		}
+		b.StartTimer()
+		if err := bulkInsert(db, records, true); err != nil {
+			b.Fatalf("bulkInsert: %v", err)
+		}
+		b.StopTimer()
+		_ = db.Close()
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "records/s")
+}
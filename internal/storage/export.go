@@ -0,0 +1,426 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/gitlog"
+	"github.com/nixlim/cc-top/internal/pathutil"
+)
+
+// commitExportTimeout bounds each git log run of ExportCommits.
+const commitExportTimeout = 5 * time.Second
+
+// ExportTables are the tables ExportDB can write, in export order.
+var ExportTables = []string{"sessions", "session_tags", "events", "metrics", "daily_stats", "alerts"}
+
+// exportSpecs describe how each exported table is read and filtered. An
+// empty sessionCol means the table is not per session and ignores the
+// session filter.
+var exportSpecs = map[string]struct {
+	from       string
+	sessionCol string
+	startExpr  string // compared with Until
+	endExpr    string // compared with Since
+	orderBy    string
+}{
+	"sessions": {
+		from:       "sessions",
+		sessionCol: "session_id",
+		startExpr:  "date(COALESCE(started_at, last_event_at))",
+		endExpr:    "date(COALESCE(last_event_at, started_at))",
+		orderBy:    "started_at, session_id",
+	},
+	// Tags are dated by their session, so a range keeps the tags of the
+	// sessions it exports.
+	"session_tags": {
+		from:       "session_tags",
+		sessionCol: "session_id",
+		startExpr:  "(SELECT date(COALESCE(started_at, last_event_at)) FROM sessions s WHERE s.session_id = session_tags.session_id)",
+		endExpr:    "(SELECT date(COALESCE(last_event_at, started_at)) FROM sessions s WHERE s.session_id = session_tags.session_id)",
+		orderBy:    "session_id",
+	},
+	"events": {
+		from:       "events",
+		sessionCol: "session_id",
+		startExpr:  "date(timestamp)",
+		endExpr:    "date(timestamp)",
+		orderBy:    "timestamp, id",
+	},
+	"metrics": {
+		from:       "metrics",
+		sessionCol: "session_id",
+		startExpr:  "date(timestamp)",
+		endExpr:    "date(timestamp)",
+		orderBy:    "timestamp, id",
+	},
+	"daily_stats": {
+		from:      "daily_stats",
+		startExpr: "date",
+		endExpr:   "date",
+		orderBy:   "date",
+	},
+	"alerts": {
+		from:       "alert_history",
+		sessionCol: "session_id",
+		startExpr:  "date(fired_at)",
+		endExpr:    "date(fired_at)",
+		orderBy:    "fired_at, id",
+	},
+}
+
+// ExportOptions select what ExportDB writes.
+type ExportOptions struct {
+	Format string   // "json" or "csv"
+	Dir    string   // output directory, one <table>.<format> file per table
+	Tables []string // defaults to ExportTables
+	// Since and Until bound the export to an inclusive range of UTC dates
+	// (YYYY-MM-DD); empty means unbounded. Sessions are included when
+	// they were active on any day in the range.
+	Since, Until string
+	// SessionIDs limits per-session tables to these sessions.
+	SessionIDs []string
+}
+
+// ExportDB writes the selected tables of the database at dbPath to files in
+// opts.Dir and returns the number of rows written per table. The database is
+// opened read-only, so exporting is safe while cc-top is running.
+func ExportDB(dbPath string, opts ExportOptions) (map[string]int, error) {
+	if opts.Format != "json" && opts.Format != "csv" {
+		return nil, fmt.Errorf("unknown export format %q (want json or csv)", opts.Format)
+	}
+	tables := opts.Tables
+	if len(tables) == 0 {
+		tables = ExportTables
+	}
+	for _, t := range tables {
+		if _, ok := exportSpecs[t]; !ok {
+			return nil, fmt.Errorf("unknown table %q (want one of %s)", t, strings.Join(ExportTables, ", "))
+		}
+	}
+
+	db, err := openExportDB(dbPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	counts := make(map[string]int, len(tables))
+	for _, t := range tables {
+		n, err := exportTable(db, t, opts)
+		if err != nil {
+			return counts, fmt.Errorf("exporting %s: %w", t, err)
+		}
+		counts[t] = n
+	}
+	return counts, nil
+}
+
+// openExportDB opens the database at dbPath read-only and creates the
+// output directory.
+func openExportDB(dbPath string, opts ExportOptions) (*sql.DB, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	return db, nil
+}
+
+// exportQuery builds the filtered SELECT of columns for a table.
+func exportQuery(table, columns string, opts ExportOptions) (string, []any) {
+	spec := exportSpecs[table]
+	var where []string
+	var args []any
+	if opts.Since != "" {
+		where = append(where, spec.endExpr+" >= ?")
+		args = append(args, opts.Since)
+	}
+	if opts.Until != "" {
+		where = append(where, spec.startExpr+" <= ?")
+		args = append(args, opts.Until)
+	}
+	if len(opts.SessionIDs) > 0 && spec.sessionCol != "" {
+		where = append(where, spec.sessionCol+" IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(opts.SessionIDs)), ", ")+")")
+		for _, id := range opts.SessionIDs {
+			args = append(args, id)
+		}
+	}
+
+	q := "SELECT " + columns + " FROM " + spec.from
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	return q + " ORDER BY " + spec.orderBy, args
+}
+
+func exportTable(db *sql.DB, table string, opts ExportOptions) (n int, err error) {
+	query, args := exportQuery(table, "*", opts)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	w, closeFile, err := createExportFile(opts, table)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := closeFile(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := w.begin(columns); err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, err
+		}
+		if err := w.row(columns, values); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	return n, w.end()
+}
+
+// createExportFile creates <name>.<format> in opts.Dir. closeFile flushes
+// and closes it.
+func createExportFile(opts ExportOptions, name string) (w exportWriter, closeFile func() error, err error) {
+	f, err := os.Create(filepath.Join(opts.Dir, name+"."+opts.Format))
+	if err != nil {
+		return nil, nil, err
+	}
+	bw := bufio.NewWriter(f)
+	if opts.Format == "csv" {
+		w = &csvExportWriter{w: csv.NewWriter(bw)}
+	} else {
+		w = &jsonExportWriter{w: bw}
+	}
+	return w, func() error {
+		ferr := bw.Flush()
+		if cerr := f.Close(); ferr == nil {
+			ferr = cerr
+		}
+		return ferr
+	}, nil
+}
+
+// exportWriter encodes exported rows in one output format.
+type exportWriter interface {
+	begin(columns []string) error
+	row(columns []string, values []any) error
+	end() error
+}
+
+// csvExportWriter writes a header line and one record per row. JSON
+// attribute columns are kept as JSON text.
+type csvExportWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvExportWriter) begin(columns []string) error {
+	c.record = make([]string, len(columns))
+	return c.w.Write(columns)
+}
+
+func (c *csvExportWriter) row(_ []string, values []any) error {
+	for i, v := range values {
+		c.record[i] = exportText(v)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvExportWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonExportWriter writes a JSON array of objects keyed by column name, in
+// column order. Text columns holding JSON objects or arrays (attributes,
+// breakdowns) are embedded as JSON rather than strings.
+type jsonExportWriter struct {
+	w     *bufio.Writer
+	first bool
+}
+
+func (j *jsonExportWriter) begin([]string) error {
+	j.first = true
+	_, err := j.w.WriteString("[")
+	return err
+}
+
+func (j *jsonExportWriter) row(columns []string, values []any) error {
+	if !j.first {
+		j.w.WriteString(",")
+	}
+	j.first = false
+	j.w.WriteString("\n  {")
+	for i, col := range columns {
+		if i > 0 {
+			j.w.WriteString(", ")
+		}
+		key, _ := json.Marshal(col)
+		j.w.Write(key)
+		j.w.WriteString(": ")
+		val, err := exportJSON(values[i])
+		if err != nil {
+			return err
+		}
+		j.w.Write(val)
+	}
+	_, err := j.w.WriteString("}")
+	return err
+}
+
+func (j *jsonExportWriter) end() error {
+	if !j.first {
+		j.w.WriteString("\n")
+	}
+	_, err := j.w.WriteString("]\n")
+	return err
+}
+
+// exportText formats a scanned SQLite value for CSV.
+func exportText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// exportJSON encodes a scanned SQLite value for JSON output.
+func exportJSON(v any) ([]byte, error) {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	if s, ok := v.(string); ok {
+		if t := strings.TrimSpace(s); (strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[")) && json.Valid([]byte(t)) {
+			return []byte(t), nil
+		}
+	}
+	return json.Marshal(v)
+}
+
+// commitExportColumns are the columns of commits.<format>.
+var commitExportColumns = []string{"session_id", "commit_hash", "committed_at", "subject", "attributed_cost_usd"}
+
+// ExportCommits writes commits.<format> to opts.Dir: the git commits made in
+// the working directory of each session opts selects, between its start and
+// its last event, found by running git log with run. Each commit's
+// attributed_cost_usd is the session's cost split evenly between its
+// commits, as in the session detail overlay. Sessions outside a git
+// repository have no commits. It returns the number of commits written.
+func ExportCommits(dbPath string, opts ExportOptions, run gitlog.Runner) (n int, err error) {
+	if opts.Format != "json" && opts.Format != "csv" {
+		return 0, fmt.Errorf("unknown export format %q (want json or csv)", opts.Format)
+	}
+	db, err := openExportDB(dbPath, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = db.Close() }()
+
+	type window struct {
+		sessionID, cwd string
+		start, end     time.Time
+		cost           float64
+	}
+	query, args := exportQuery("sessions", "session_id, cwd, started_at, last_event_at, total_cost", opts)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("querying sessions: %w", err)
+	}
+	var windows []window
+	for rows.Next() {
+		var w window
+		var cwd, started, last sql.NullString
+		var cost sql.NullFloat64
+		if err := rows.Scan(&w.sessionID, &cwd, &started, &last, &cost); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scanning session: %w", err)
+		}
+		w.cwd, w.cost = cwd.String, cost.Float64
+		w.start, _ = time.Parse(time.RFC3339Nano, started.String)
+		w.end, _ = time.Parse(time.RFC3339Nano, last.String)
+		if w.cwd != "" && !w.start.IsZero() && !w.end.IsZero() {
+			windows = append(windows, w)
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterating sessions: %w", err)
+	}
+
+	w, closeFile, err := createExportFile(opts, "commits")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := closeFile(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	if err := w.begin(commitExportColumns); err != nil {
+		return 0, err
+	}
+	for _, sw := range windows {
+		ctx, cancel := context.WithTimeout(context.Background(), commitExportTimeout)
+		commits, err := gitlog.Log(ctx, run, pathutil.ExpandHome(sw.cwd), sw.start, sw.end)
+		cancel()
+		if err != nil || len(commits) == 0 {
+			// Not a git repository, or git missing: nothing to link.
+			continue
+		}
+		share := sw.cost / float64(len(commits))
+		for i := len(commits) - 1; i >= 0; i-- {
+			c := commits[i]
+			values := []any{sw.sessionID, c.Hash, c.Time.UTC().Format(time.RFC3339), c.Subject, share}
+			if err := w.row(commitExportColumns, values); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, w.end()
+}
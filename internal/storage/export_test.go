@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func seedExportDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "history.db")
+	seedSyncDB(t, path,
+		`INSERT INTO sessions (session_id, total_cost, started_at, last_event_at) VALUES ('s1', 1.5, '2026-03-01T09:00:00Z', '2026-03-01T10:00:00Z')`,
+		`INSERT INTO sessions (session_id, total_cost, started_at, last_event_at) VALUES ('s2', 2.5, '2026-03-02T23:00:00Z', '2026-03-03T01:00:00Z')`,
+		`INSERT INTO metrics (session_id, name, value, timestamp, attributes) VALUES ('s1', 'claude_code.cost.usage', 1.5, '2026-03-01T09:30:00Z', '{"model":"opus"}')`,
+		`INSERT INTO metrics (session_id, name, value, timestamp, attributes) VALUES ('s2', 'claude_code.cost.usage', 2.5, '2026-03-03T00:30:00Z', '')`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('s2', 'claude_code.api_request', '2026-03-03T00:30:00Z', 1, '{"model":"opus"}')`,
+		`INSERT INTO daily_stats (date, total_cost) VALUES ('2026-03-01', 1.5)`,
+		`INSERT INTO daily_stats (date, total_cost) VALUES ('2026-03-03', 2.5)`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('SessionCost', 'warning', 'costly', 's2', '2026-03-03T00:40:00Z')`,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('s1', 'experiment', '', '2026-03-01T10:00:00Z')`,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('s2', 'prod-fix,demo', 'hotfix for #42', '2026-03-03T01:00:00Z')`,
+	)
+	return path
+}
+
+func TestExportDB_JSON(t *testing.T) {
+	dbPath := seedExportDB(t)
+	dir := t.TempDir()
+
+	counts, err := ExportDB(dbPath, ExportOptions{Format: "json", Dir: dir})
+	if err != nil {
+		t.Fatalf("ExportDB: %v", err)
+	}
+	want := map[string]int{"sessions": 2, "session_tags": 2, "events": 1, "metrics": 2, "daily_stats": 2, "alerts": 1}
+	for table, n := range want {
+		if counts[table] != n {
+			t.Errorf("%s: exported %d rows, want %d", table, counts[table], n)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "metrics.json"))
+	if err != nil {
+		t.Fatalf("reading metrics.json: %v", err)
+	}
+	var metrics []map[string]any
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("metrics.json is not a JSON array: %v\n%s", err, data)
+	}
+	attrs, ok := metrics[0]["attributes"].(map[string]any)
+	if !ok || attrs["model"] != "opus" {
+		t.Errorf("attributes should be embedded as an object, got %#v", metrics[0]["attributes"])
+	}
+	if metrics[0]["value"] != 1.5 || metrics[1]["session_id"] != "s2" {
+		t.Errorf("unexpected metric rows: %v", metrics)
+	}
+}
+
+func TestExportDB_CSVFilters(t *testing.T) {
+	dbPath := seedExportDB(t)
+	dir := t.TempDir()
+
+	counts, err := ExportDB(dbPath, ExportOptions{
+		Format:     "csv",
+		Dir:        dir,
+		Tables:     []string{"sessions", "session_tags", "metrics", "daily_stats"},
+		Since:      "2026-03-02",
+		SessionIDs: []string{"s1", "s2"},
+	})
+	if err != nil {
+		t.Fatalf("ExportDB: %v", err)
+	}
+	// s2 was active from 03-02 to 03-03; daily stats ignore the session filter.
+	if counts["sessions"] != 1 || counts["session_tags"] != 1 || counts["metrics"] != 1 || counts["daily_stats"] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "events.csv")); !os.IsNotExist(err) {
+		t.Error("tables not selected should not be written")
+	}
+
+	f, err := os.Open(filepath.Join(dir, "sessions.csv"))
+	if err != nil {
+		t.Fatalf("opening sessions.csv: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("reading sessions.csv: %v", err)
+	}
+	if len(records) != 2 || records[0][0] != "session_id" || records[1][0] != "s2" {
+		t.Errorf("unexpected sessions.csv: %v", records)
+	}
+
+	counts, err = ExportDB(dbPath, ExportOptions{Format: "csv", Dir: dir, Tables: []string{"alerts"}, Until: "2026-03-02"})
+	if err != nil {
+		t.Fatalf("ExportDB: %v", err)
+	}
+	if counts["alerts"] != 0 {
+		t.Errorf("alerts after --until should be excluded, got %d", counts["alerts"])
+	}
+}
+
+func TestExportCommits(t *testing.T) {
+	dbPath := seedExportDB(t)
+	seedSyncDB(t, dbPath,
+		`UPDATE sessions SET cwd = '/repo' WHERE session_id = 's1'`,
+		`UPDATE sessions SET cwd = '/tmp' WHERE session_id = 's2'`,
+	)
+	run := func(_ context.Context, dir string, _ ...string) ([]byte, error) {
+		if dir != "/repo" {
+			return nil, errors.New("not a git repository")
+		}
+		var out string
+		for i, at := range []string{"2026-03-01T09:50:00Z", "2026-03-01T09:20:00Z"} {
+			ts, _ := time.Parse(time.RFC3339, at)
+			out += fmt.Sprintf("hash%d\x1f%d\x1fcommit %d\n", 2-i, ts.Unix(), 2-i)
+		}
+		return []byte(out), nil
+	}
+
+	dir := t.TempDir()
+	n, err := ExportCommits(dbPath, ExportOptions{Format: "json", Dir: dir}, run)
+	if err != nil {
+		t.Fatalf("ExportCommits: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("exported %d commits, want 2", n)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "commits.json"))
+	if err != nil {
+		t.Fatalf("reading commits.json: %v", err)
+	}
+	var commits []map[string]any
+	if err := json.Unmarshal(data, &commits); err != nil {
+		t.Fatalf("commits.json is not a JSON array: %v\n%s", err, data)
+	}
+	first := commits[0]
+	if first["session_id"] != "s1" || first["commit_hash"] != "hash1" || first["committed_at"] != "2026-03-01T09:20:00Z" {
+		t.Errorf("commits should be oldest first, got %v", commits)
+	}
+	if first["attributed_cost_usd"] != 0.75 || commits[1]["attributed_cost_usd"] != 0.75 {
+		t.Errorf("s1's $1.50 should be split between its 2 commits, got %v", commits)
+	}
+}
+
+func TestExportDB_Errors(t *testing.T) {
+	dbPath := seedExportDB(t)
+	if _, err := ExportDB(dbPath, ExportOptions{Format: "xml", Dir: t.TempDir()}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, err := ExportDB(dbPath, ExportOptions{Format: "csv", Dir: t.TempDir(), Tables: []string{"counter_state"}}); err == nil {
+		t.Error("expected an error for a table that cannot be exported")
+	}
+	if _, err := ExportDB(filepath.Join(t.TempDir(), "missing.db"), ExportOptions{Format: "csv", Dir: t.TempDir()}); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
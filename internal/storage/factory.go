@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nixlim/cc-top/internal/config"
 	"github.com/nixlim/cc-top/internal/state"
@@ -17,15 +18,30 @@ func NewStore(cfg config.StorageConfig) (state.Store, bool, error) {
 
 	dbPath := expandTilde(cfg.DBPath)
 
-	store, err := NewSQLiteStore(dbPath, cfg.RetentionDays, cfg.SummaryRetentionDays)
+	store, err := NewSQLiteStore(dbPath, cfg.RetentionDays, cfg.SummaryRetentionDays, WithTuning(TuningFromConfig(cfg)))
 	if err != nil {
 		log.Printf("WARNING: SQLite storage unavailable (%v), falling back to in-memory store", err)
 		return state.NewMemoryStore(), false, nil
 	}
+	store.SetMaxDBSize(cfg.MaxDBSizeMB)
+	store.SetMaintenanceSchedule(cfg.MaintenanceWindow, cfg.MaintenanceBusyWritesPerMinute)
 
 	return store, true, nil
 }
 
+// TuningFromConfig returns the tuning set in [storage].
+func TuningFromConfig(cfg config.StorageConfig) Tuning {
+	return Tuning{
+		JournalMode:   cfg.JournalMode,
+		Synchronous:   cfg.Synchronous,
+		BusyTimeout:   time.Duration(cfg.BusyTimeoutMs) * time.Millisecond,
+		BatchSize:     cfg.BatchSize,
+		FlushInterval: time.Duration(cfg.FlushIntervalMs) * time.Millisecond,
+		ChannelSize:   cfg.ChannelSize,
+		ReadOnly:      cfg.ReadOnly,
+	}
+}
+
 func expandTilde(path string) string {
 	if strings.HasPrefix(path, "~/") {
 		home, err := os.UserHomeDir()
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+)
+
+// forecastHistoryDays is the trailing window of burn rate snapshots cost
+// forecasts are fitted to. Like the spend baseline, it is also bounded by
+// retention_days_raw.
+const forecastHistoryDays = 7
+
+// SpendHistory returns the spend of each completed hour of the trailing
+// week, averaged from its burn rate snapshots, and the month's spend before
+// today from the daily stats. It implements burnrate.HistorySource.
+func (s *SQLiteStore) SpendHistory(now time.Time) (burnrate.SpendHistory, bool) {
+	h, err := s.querySpendHistory(now, forecastHistoryDays)
+	if err != nil {
+		log.Printf("WARNING: reading spend history for the forecast: %v", err)
+		return burnrate.SpendHistory{}, false
+	}
+	return h, true
+}
+
+func (s *SQLiteStore) querySpendHistory(now time.Time, days int) (burnrate.SpendHistory, error) {
+	from := now.AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	to := now.UTC().Truncate(time.Hour).Format(time.RFC3339)
+
+	rows, err := s.db.Query(`
+		SELECT substr(timestamp, 1, 13) AS hour, AVG(hourly_rate) FROM burn_rate_snapshots
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY hour
+		ORDER BY hour
+	`, from, to)
+	if err != nil {
+		return burnrate.SpendHistory{}, fmt.Errorf("querying burn rate snapshots: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var h burnrate.SpendHistory
+	for rows.Next() {
+		var hour string
+		var cost float64
+		if err := rows.Scan(&hour, &cost); err != nil {
+			return burnrate.SpendHistory{}, fmt.Errorf("scanning hourly spend: %w", err)
+		}
+		at, err := time.Parse("2006-01-02T15", hour)
+		if err != nil {
+			continue
+		}
+		h.Hours = append(h.Hours, burnrate.HourSpend{Hour: at, Cost: cost})
+	}
+	if err := rows.Err(); err != nil {
+		return burnrate.SpendHistory{}, fmt.Errorf("iterating hourly spend: %w", err)
+	}
+
+	y, m, _ := now.Date()
+	monthStart := time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+	err = s.db.QueryRow(`
+		SELECT COALESCE(SUM(total_cost), 0) FROM daily_stats WHERE date >= ? AND date < ?
+	`, monthStart.Format("2006-01-02"), now.Format("2006-01-02")).Scan(&h.MonthBeforeToday)
+	if err != nil {
+		return burnrate.SpendHistory{}, fmt.Errorf("querying month to date spend: %w", err)
+	}
+	return h, nil
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpendHistory(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 30, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+	for _, s := range []struct {
+		ts   time.Time
+		rate float64
+	}{
+		{now.Add(-3 * time.Hour), 1},
+		{now.Add(-3*time.Hour + 20*time.Minute), 3},
+		{now.Add(-2 * time.Hour), 0},
+		{now.Add(-10 * time.Minute), 500}, // current hour
+		{now.AddDate(0, 0, -8), 500},      // before the window
+	} {
+		if _, err := store.db.Exec("INSERT INTO burn_rate_snapshots (timestamp, hourly_rate) VALUES (?, ?)",
+			s.ts.UTC().Format(time.RFC3339), s.rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for date, cost := range map[string]float64{"2026-09-30": 100, "2026-10-01": 5, "2026-10-15": 7, "2026-10-16": 50} {
+		if _, err := store.db.Exec("INSERT INTO daily_stats (date, total_cost) VALUES (?, ?)", date, cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, ok := store.SpendHistory(now)
+	if !ok {
+		t.Fatal("SpendHistory failed")
+	}
+	if len(h.Hours) != 2 {
+		t.Fatalf("Hours = %+v, want 2 completed hours", h.Hours)
+	}
+	if !h.Hours[0].Hour.Equal(now.Add(-3*time.Hour).Truncate(time.Hour)) || h.Hours[0].Cost != 2 {
+		t.Errorf("first hour = %+v, want $2 at 09:00", h.Hours[0])
+	}
+	if h.Hours[1].Cost != 0 {
+		t.Errorf("an idle hour should be kept as $0, got %+v", h.Hours[1])
+	}
+	if h.MonthBeforeToday != 12 {
+		t.Errorf("MonthBeforeToday = %v, want 12", h.MonthBeforeToday)
+	}
+}
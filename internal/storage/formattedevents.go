@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"database/sql"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/events"
+)
+
+// SpillEvent stores an event evicted from the TUI's event buffer, so the
+// Events panel can scroll back past the buffer.
+func (s *SQLiteStore) SpillEvent(e events.FormattedEvent) {
+	s.sendWrite(writeOp{opType: "formattedEvent", formatted: &e})
+}
+
+// QuerySpilledEvents returns the newest limit spilled events, of sessionID
+// or of all sessions when it is empty, oldest first.
+func (s *SQLiteStore) QuerySpilledEvents(sessionID string, limit int) []events.FormattedEvent {
+	query := "SELECT session_id, event_type, formatted, timestamp, success, attributes FROM formatted_events"
+	var args []any
+	if sessionID != "" {
+		query += " WHERE session_id = ?"
+		args = append(args, sessionID)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("ERROR: querying spilled events: %v", err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []events.FormattedEvent
+	for rows.Next() {
+		var e events.FormattedEvent
+		var ts string
+		var success sql.NullBool
+		var attrs sql.NullString
+		if err := rows.Scan(&e.SessionID, &e.EventType, &e.Formatted, &ts, &success, &attrs); err != nil {
+			log.Printf("ERROR: scanning spilled event: %v", err)
+			continue
+		}
+		e.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
+		e.Timestamp = e.Timestamp.Local()
+		if success.Valid {
+			e.Success = &success.Bool
+		}
+		unmarshalJSONField(attrs.String, &e.RawAttributes)
+		result = append(result, e)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating spilled events: %v", err)
+	}
+	slices.Reverse(result)
+	return result
+}
+
+func (s *SQLiteStore) writeFormattedEvent(tx *sql.Tx, e *events.FormattedEvent) error {
+	var success sql.NullBool
+	if e.Success != nil {
+		success = sql.NullBool{Bool: *e.Success, Valid: true}
+	}
+	_, err := tx.Exec(`
+		INSERT INTO formatted_events (session_id, event_type, formatted, timestamp, success, attributes)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, e.SessionID, e.EventType, e.Formatted, e.Timestamp.UTC().Format(time.RFC3339Nano), success, attributesJSON(e.RawAttributes))
+	return err
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/events"
+)
+
+func TestSQLiteStore_SpilledEvents(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store1, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	at := time.Now().Truncate(time.Millisecond)
+	failed := false
+	for i, sid := range []string{"sess-a", "sess-b", "sess-a"} {
+		store1.SpillEvent(events.FormattedEvent{
+			SessionID:     sid,
+			EventType:     "tool_result",
+			Formatted:     "Bash " + string(rune('1'+i)),
+			Timestamp:     at.Add(time.Duration(i) * time.Second),
+			Success:       &failed,
+			RawAttributes: map[string]string{"tool_name": "Bash"},
+		})
+	}
+	_ = store1.Close()
+
+	store2, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (reopen) failed: %v", err)
+	}
+	defer func() { _ = store2.Close() }()
+
+	got := store2.QuerySpilledEvents("", 2)
+	if len(got) != 2 || got[0].Formatted != "Bash 2" || got[1].Formatted != "Bash 3" {
+		t.Fatalf("newest two, oldest first: got %+v", got)
+	}
+	e := got[1]
+	if !e.Timestamp.Equal(at.Add(2*time.Second)) || e.Success == nil || *e.Success || e.RawAttributes["tool_name"] != "Bash" {
+		t.Errorf("round trip lost fields: %+v", e)
+	}
+
+	got = store2.QuerySpilledEvents("sess-a", 10)
+	if len(got) != 2 || got[0].Formatted != "Bash 1" {
+		t.Errorf("sess-a: got %+v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// maintenanceLeaseTTL is how long the maintenance lease lasts unless it is
+// renewed. The holder renews it on every maintenance check, so when it
+// stops, another instance sharing the database takes over within this long.
+const maintenanceLeaseTTL = 3 * maintenanceCheckInterval
+
+// newInstanceID returns an ID for this process's store that no other
+// instance sharing the database has.
+func newInstanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// renewLease takes the maintenance lease, or renews it, unless another
+// instance holds an unexpired one, and reports whether this store holds it
+// now. Only the holder runs maintenance and records stats snapshots, so
+// instances sharing a database don't prune or vacuum under each other.
+func (s *SQLiteStore) renewLease(now time.Time) (bool, error) {
+	if s.readOnly {
+		return false, nil
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO maintenance_lease (id, holder, expires_at) VALUES (1, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE maintenance_lease.holder = excluded.holder OR maintenance_lease.expires_at < ?
+	`, s.instanceID, now.Add(maintenanceLeaseTTL).UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339))
+	if err != nil {
+		s.leader.Store(false)
+		return false, fmt.Errorf("renewing maintenance lease: %w", err)
+	}
+	n, err := res.RowsAffected()
+	held := err == nil && n > 0
+	s.leader.Store(held)
+	return held, nil
+}
+
+// checkLease renews the maintenance lease and logs when this store gains
+// or loses it.
+func (s *SQLiteStore) checkLease(now time.Time) bool {
+	was := s.leader.Load()
+	held, err := s.renewLease(now)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+	switch {
+	case held && !was:
+		log.Printf("INFO: this cc-top instance now runs database maintenance")
+	case !held && was:
+		log.Printf("INFO: another cc-top instance took over database maintenance")
+	}
+	return held
+}
+
+// releaseLease gives up the maintenance lease, so another instance can
+// take over at its next check instead of waiting for the lease to expire.
+func (s *SQLiteStore) releaseLease() {
+	if !s.leader.Load() {
+		return
+	}
+	if _, err := s.db.Exec("DELETE FROM maintenance_lease WHERE holder = ?", s.instanceID); err != nil {
+		log.Printf("WARNING: releasing maintenance lease: %v", err)
+	}
+	s.leader.Store(false)
+}
+
+// role is this store's part in sharing the database with other instances.
+func (s *SQLiteStore) role() string {
+	switch {
+	case s.readOnly:
+		return state.StoreRoleReadOnly
+	case s.leader.Load():
+		return state.StoreRoleLeader
+	default:
+		return state.StoreRoleStandby
+	}
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestMaintenanceLease(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "shared.db")
+	first, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	second, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("second NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = second.Close() }()
+
+	if first.role() != state.StoreRoleLeader || second.role() != state.StoreRoleStandby {
+		t.Fatalf("roles: first %s, second %s", first.role(), second.role())
+	}
+	now := time.Now()
+	if held, err := first.renewLease(now); !held || err != nil {
+		t.Errorf("the holder should renew its lease: %v, %v", held, err)
+	}
+	if held, _ := second.renewLease(now); held {
+		t.Error("a standby should not take an unexpired lease")
+	}
+	if held, _ := second.renewLease(now.Add(maintenanceLeaseTTL + time.Minute)); !held {
+		t.Error("a standby should take an expired lease")
+	}
+	if held, _ := first.renewLease(now.Add(maintenanceLeaseTTL + 2*time.Minute)); held {
+		t.Error("the previous holder should not take the lease back")
+	}
+
+	// Closing the holder releases the lease at once.
+	if held, _ := first.renewLease(now.Add(3 * maintenanceLeaseTTL)); !held {
+		t.Fatal("first should take the lease back once it expires")
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if held, _ := second.renewLease(time.Now()); !held {
+		t.Error("the standby should take over a released lease")
+	}
+}
+
+func TestReadOnlyStore(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "shared.db")
+	ro := Tuning{BusyTimeout: time.Second, BatchSize: 10, FlushInterval: 10 * time.Millisecond, ChannelSize: 10, ReadOnly: true}
+
+	if _, err := NewSQLiteStore(dbPath, 7, 90, WithTuning(ro)); err == nil {
+		t.Fatal("a read-only store should not create the database")
+	}
+
+	writer, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = writer.Close() }()
+
+	follower, err := NewSQLiteStore(dbPath, 7, 90, WithTuning(ro))
+	if err != nil {
+		t.Fatalf("read-only NewSQLiteStore failed: %v", err)
+	}
+	if follower.role() != state.StoreRoleReadOnly || writer.role() != state.StoreRoleLeader {
+		t.Errorf("roles: writer %s, follower %s", writer.role(), follower.role())
+	}
+
+	follower.AddMetric("sess-ro", state.Metric{Name: "claude_code.cost.usage", Value: 1, Timestamp: time.Now()})
+	follower.SetSessionTags("sess-ro", []string{"demo"}, "")
+	if s := follower.GetSession("sess-ro"); s == nil || s.TotalCost != 1 {
+		t.Error("a read-only store should still keep telemetry in memory")
+	}
+	if err := follower.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := countRows(t, writer.db, "SELECT COUNT(*) FROM sessions"); n != 0 {
+		t.Errorf("a read-only store wrote %d sessions", n)
+	}
+	if n := countRows(t, writer.db, "SELECT COUNT(*) FROM session_tags"); n != 0 {
+		t.Errorf("a read-only store wrote %d session tags", n)
+	}
+}
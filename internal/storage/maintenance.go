@@ -2,25 +2,85 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
 )
 
 const (
 	maintenanceInterval = 1 * time.Hour
 	vacuumInterval      = 7 * 24 * time.Hour
+
+	// maintenanceCheckInterval is how often the maintenance loop looks for
+	// due work and measures the write rate.
+	maintenanceCheckInterval = 5 * time.Minute
+	// maxBusyDeferral is how long busy telemetry may hold back overdue
+	// pruning, so retention still applies to an instance that is never idle.
+	maxBusyDeferral = 48 * time.Hour
+
+	// sqliteDateTime is the layout of SQLite's datetime() function.
+	sqliteDateTime = "2006-01-02 15:04:05"
 )
 
+// maintenanceSchedule is when pruning, the size cap and VACUUM may run:
+// inside a daily window, if one is set, and while writes arrive no faster
+// than busyWrites a minute.
+type maintenanceSchedule struct {
+	window     *config.DailyWindow // nil allows any time
+	busyWrites int                 // 0 never defers
+}
+
+// SetMaintenanceSchedule limits pruning, the size cap and VACUUM to a daily
+// window "HH:MM-HH:MM" ("" allows any time) and defers them while more than
+// busyWritesPerMinute writes a minute arrive (0 never defers). The daily
+// stats snapshot is taken hourly regardless.
+func (s *SQLiteStore) SetMaintenanceSchedule(window string, busyWritesPerMinute int) {
+	sched := maintenanceSchedule{busyWrites: busyWritesPerMinute}
+	if window != "" {
+		// The config was validated, so a bad window can only mean none.
+		if w, err := config.ParseDailyWindow(window); err == nil {
+			sched.window = &w
+		}
+	}
+	s.maintMu.Lock()
+	s.maint = sched
+	s.maintMu.Unlock()
+}
+
+// allows reports whether heavy maintenance may run at now, in its own
+// location, with writes arriving at writesPerMinute. busy is set when only
+// the write rate holds it back.
+func (m maintenanceSchedule) allows(now time.Time, writesPerMinute float64) (ok, busy bool) {
+	if m.window != nil && !m.window.Contains(now) {
+		return false, false
+	}
+	if m.busyWrites > 0 && writesPerMinute > float64(m.busyWrites) {
+		return false, true
+	}
+	return true, false
+}
+
 func (s *SQLiteStore) startMaintenance(ctx context.Context, retentionDays, summaryRetentionDays int) {
 	go s.maintenanceLoop(ctx, retentionDays, summaryRetentionDays)
 }
 
+// maintenanceLoop snapshots daily stats and refreshes the alert baselines
+// every hour, and runs the heavy work (pruning, the size cap and VACUUM)
+// hourly too, but only when the maintenance schedule allows it. While
+// another instance holds the maintenance lease, or the store is read-only,
+// it only refreshes the baselines.
 func (s *SQLiteStore) maintenanceLoop(ctx context.Context, retentionDays, summaryRetentionDays int) {
 	defer close(s.maintenanceDone)
 
-	lastVacuum := time.Now()
-	ticker := time.NewTicker(maintenanceInterval)
+	started := time.Now()
+	lastSnapshot, lastPrune, lastVacuum := started, started, started
+	lastCheck, lastWritten := started, s.writer.totalWritten()
+	deferred := false
+	ticker := time.NewTicker(maintenanceCheckInterval)
 	defer ticker.Stop()
 
 	for {
@@ -28,11 +88,70 @@ func (s *SQLiteStore) maintenanceLoop(ctx context.Context, retentionDays, summar
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := s.runMaintenanceCycle(retentionDays, summaryRetentionDays); err != nil {
+			now := time.Now()
+			written := s.writer.totalWritten()
+			rate := float64(written-lastWritten) / now.Sub(lastCheck).Minutes()
+			lastCheck, lastWritten = now, written
+			leader := s.checkLease(now)
+
+			if now.Sub(lastSnapshot) >= maintenanceInterval {
+				if leader {
+					s.snapshotDailyStats(now)
+				}
+				if s.autoThresholdsDue(now) {
+					if err := s.refreshAutoThresholds(now); err != nil {
+						log.Printf("ERROR: auto threshold refresh failed: %v", err)
+					}
+				}
+				if s.spendBaselineEnabled() {
+					if err := s.refreshSpendBaseline(now); err != nil {
+						log.Printf("ERROR: spend baseline refresh failed: %v", err)
+					}
+				}
+				lastSnapshot = now
+			}
+
+			// Another instance sharing the database maintains it; after
+			// taking over, this one waits a full interval like at startup.
+			if !leader {
+				lastPrune = now
+				continue
+			}
+			if now.Sub(lastPrune) < maintenanceInterval {
+				continue
+			}
+			s.maintMu.Lock()
+			sched := s.maint
+			s.maintMu.Unlock()
+			ok, busy := sched.allows(now.Local(), rate)
+			if !ok && busy && now.Sub(lastPrune) >= maxBusyDeferral {
+				log.Printf("WARNING: maintenance deferred for %s by busy telemetry, running it anyway", maxBusyDeferral)
+				ok = true
+			}
+			if !ok {
+				if busy && !deferred {
+					log.Printf("INFO: deferring maintenance: receiving %.0f writes/min (maintenance_busy_writes_per_minute = %d)", rate, sched.busyWrites)
+				}
+				deferred = deferred || busy
+				continue
+			}
+			deferred = false
+
+			if err := s.pruneRetention(retentionDays, summaryRetentionDays); err != nil {
 				log.Printf("ERROR: maintenance cycle failed: %v", err)
 			}
+			lastPrune = now
+
+			vacuum := now.Sub(lastVacuum) >= vacuumInterval
+			if maxBytes := s.maxDBBytes.Load(); maxBytes > 0 {
+				pruned, err := s.enforceSizeCap(maxBytes)
+				if err != nil {
+					log.Printf("ERROR: enforcing max_db_size_mb failed: %v", err)
+				}
+				vacuum = vacuum || pruned
+			}
 
-			if time.Since(lastVacuum) >= vacuumInterval {
+			if vacuum {
 				if _, err := s.db.Exec("VACUUM"); err != nil {
 					log.Printf("ERROR: VACUUM failed: %v", err)
 				} else {
@@ -43,17 +162,134 @@ func (s *SQLiteStore) maintenanceLoop(ctx context.Context, retentionDays, summar
 	}
 }
 
+// runMaintenanceCycle snapshots daily stats and applies retention in one
+// go, regardless of the maintenance schedule.
 func (s *SQLiteStore) runMaintenanceCycle(retentionDays, summaryRetentionDays int) error {
+	s.snapshotDailyStats(time.Now())
+	return s.pruneRetention(retentionDays, summaryRetentionDays)
+}
+
+// snapshotDailyStats writes today's stats and records how past daily
+// projections compared with the actual cost.
+func (s *SQLiteStore) snapshotDailyStats(now time.Time) {
 	// Capture stats snapshot if callback is set (FR-005).
 	if s.statsSnapshotFn != nil {
 		ds := s.statsSnapshotFn()
-		today := time.Now().Format("2006-01-02")
-		s.WriteDailyStats(today, ds)
+		s.WriteDailyStats(now.Format("2006-01-02"), ds)
 	}
 
+	// Record before pruning, while the day's burn rate snapshots still exist.
+	if err := s.recordProjectionAccuracy(now); err != nil {
+		log.Printf("ERROR: recording projection accuracy: %v", err)
+	}
+}
+
+// pruneRetention downsamples raw data older than retentionDays and deletes
+// summaries, stats and history older than summaryRetentionDays.
+func (s *SQLiteStore) pruneRetention(retentionDays, summaryRetentionDays int) error {
 	retentionModifier := fmt.Sprintf("-%d days", retentionDays)
 	summaryModifier := fmt.Sprintf("-%d days", summaryRetentionDays)
 
+	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+	if err := s.downsampleRaw(cutoff); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec("DELETE FROM daily_summaries WHERE date < date('now', ?)", summaryModifier)
+	if err != nil {
+		return fmt.Errorf("pruning old summaries: %w", err)
+	}
+
+	// Prune new v2 tables
+	_, err = s.db.Exec("DELETE FROM burn_rate_snapshots WHERE timestamp < datetime('now', ?)", retentionModifier)
+	if err != nil {
+		return fmt.Errorf("pruning old burn rate snapshots: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM daily_stats WHERE date < date('now', ?)", summaryModifier)
+	if err != nil {
+		return fmt.Errorf("pruning old daily stats: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM alert_history WHERE fired_at < datetime('now', ?)", summaryModifier)
+	if err != nil {
+		return fmt.Errorf("pruning old alert history: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM projection_accuracy WHERE date < date('now', ?)", summaryModifier)
+	if err != nil {
+		return fmt.Errorf("pruning old projection accuracy: %w", err)
+	}
+
+	return nil
+}
+
+// recordProjectionAccuracy stores, for each completed day with daily stats
+// that is not recorded yet, the last daily projection captured that day next
+// to the day's actual cost. Days are local dates, like daily_stats.
+func (s *SQLiteStore) recordProjectionAccuracy(now time.Time) error {
+	rows, err := s.db.Query(`
+		SELECT date, total_cost FROM daily_stats
+		WHERE date < ? AND date NOT IN (SELECT date FROM projection_accuracy)
+	`, now.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("querying completed days: %w", err)
+	}
+	type day struct {
+		date   string
+		actual float64
+	}
+	var days []day
+	for rows.Next() {
+		var d day
+		if err := rows.Scan(&d.date, &d.actual); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scanning completed day: %w", err)
+		}
+		days = append(days, d)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("querying completed days: %w", err)
+	}
+
+	recordedAt := now.UTC().Format(time.RFC3339)
+	for _, d := range days {
+		start, err := time.ParseInLocation("2006-01-02", d.date, now.Location())
+		if err != nil {
+			continue
+		}
+		end := start.AddDate(0, 0, 1)
+
+		var projected float64
+		err = s.db.QueryRow(`
+			SELECT daily_projection FROM burn_rate_snapshots
+			WHERE timestamp >= ? AND timestamp < ?
+			ORDER BY timestamp DESC LIMIT 1
+		`, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339)).Scan(&projected)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("querying projection for %s: %w", d.date, err)
+		}
+
+		_, err = s.db.Exec(`
+			INSERT OR IGNORE INTO projection_accuracy (date, projected_cost, actual_cost, recorded_at)
+			VALUES (?, ?, ?, ?)
+		`, d.date, projected, d.actual, recordedAt)
+		if err != nil {
+			return fmt.Errorf("recording projection for %s: %w", d.date, err)
+		}
+	}
+	return nil
+}
+
+// downsampleRaw aggregates the metrics and events recorded before cutoff
+// into daily_summaries and then deletes them.
+func (s *SQLiteStore) downsampleRaw(cutoff time.Time) error {
+	before := cutoff.UTC().Format(sqliteDateTime)
+
 	_, err := s.db.Exec(`
 		INSERT INTO daily_summaries (session_id, date, total_cost, total_tokens, api_requests, api_errors, active_seconds)
 		SELECT
@@ -72,7 +308,7 @@ func (s *SQLiteStore) runMaintenanceCycle(retentionDays, summaryRetentionDays in
 				MAX(CASE WHEN m.name = 'claude_code.token.usage' THEN CAST(m.value AS INTEGER) ELSE 0 END) AS total_tokens,
 				MAX(CASE WHEN m.name = 'claude_code.active_time.total' THEN m.value ELSE 0 END) AS active_seconds
 			FROM metrics m
-			WHERE datetime(m.timestamp) < datetime('now', ?)
+			WHERE datetime(m.timestamp) < datetime(?)
 			GROUP BY m.session_id, date(m.timestamp)
 		) src
 		LEFT JOIN (
@@ -82,7 +318,7 @@ func (s *SQLiteStore) runMaintenanceCycle(retentionDays, summaryRetentionDays in
 				COUNT(*) AS api_requests,
 				COUNT(CASE WHEN e.attributes LIKE '%"error"%' OR e.attributes LIKE '%"status":"error"%' THEN 1 END) AS api_errors
 			FROM events e
-			WHERE e.name = 'claude_code.api_request' AND datetime(e.timestamp) < datetime('now', ?)
+			WHERE e.name = 'claude_code.api_request' AND datetime(e.timestamp) < datetime(?)
 			GROUP BY e.session_id, date(e.timestamp)
 		) ev ON src.session_id = ev.session_id AND src.date = ev.date
 		ON CONFLICT(session_id, date) DO UPDATE SET
@@ -91,41 +327,90 @@ func (s *SQLiteStore) runMaintenanceCycle(retentionDays, summaryRetentionDays in
 			api_requests = excluded.api_requests,
 			api_errors = excluded.api_errors,
 			active_seconds = excluded.active_seconds
-	`, retentionModifier, retentionModifier)
+	`, before, before)
 	if err != nil {
 		return fmt.Errorf("aggregating old data: %w", err)
 	}
 
-	_, err = s.db.Exec("DELETE FROM metrics WHERE datetime(timestamp) < datetime('now', ?)", retentionModifier)
+	_, err = s.db.Exec("DELETE FROM metrics WHERE datetime(timestamp) < datetime(?)", before)
 	if err != nil {
 		return fmt.Errorf("pruning old metrics: %w", err)
 	}
 
-	_, err = s.db.Exec("DELETE FROM events WHERE datetime(timestamp) < datetime('now', ?)", retentionModifier)
+	_, err = s.db.Exec("DELETE FROM events WHERE datetime(timestamp) < datetime(?)", before)
 	if err != nil {
 		return fmt.Errorf("pruning old events: %w", err)
 	}
 
-	_, err = s.db.Exec("DELETE FROM daily_summaries WHERE date < date('now', ?)", summaryModifier)
+	_, err = s.db.Exec("DELETE FROM formatted_events WHERE datetime(timestamp) < datetime(?)", before)
 	if err != nil {
-		return fmt.Errorf("pruning old summaries: %w", err)
+		return fmt.Errorf("pruning old spilled events: %w", err)
 	}
+	return nil
+}
 
-	// Prune new v2 tables
-	_, err = s.db.Exec("DELETE FROM burn_rate_snapshots WHERE timestamp < datetime('now', ?)", retentionModifier)
-	if err != nil {
-		return fmt.Errorf("pruning old burn rate snapshots: %w", err)
-	}
+// SetMaxDBSize caps the database at mb megabytes; 0 removes the cap. When
+// the data outgrows it, maintenance downsamples raw metrics and events a day
+// at a time, oldest first, and then vacuums the file. The current day is
+// always kept.
+func (s *SQLiteStore) SetMaxDBSize(mb int) {
+	s.maxDBBytes.Store(int64(mb) * 1024 * 1024)
+}
 
-	_, err = s.db.Exec("DELETE FROM daily_stats WHERE date < date('now', ?)", summaryModifier)
-	if err != nil {
-		return fmt.Errorf("pruning old daily stats: %w", err)
+// usedBytes returns the size of the pages holding data, which unlike the
+// file size drops as soon as rows are deleted.
+func (s *SQLiteStore) usedBytes() (int64, error) {
+	var pages, free, pageSize int64
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pages); err != nil {
+		return 0, err
 	}
-
-	_, err = s.db.Exec("DELETE FROM alert_history WHERE fired_at < datetime('now', ?)", summaryModifier)
-	if err != nil {
-		return fmt.Errorf("pruning old alert history: %w", err)
+	if err := s.db.QueryRow("PRAGMA freelist_count").Scan(&free); err != nil {
+		return 0, err
 	}
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return (pages - free) * pageSize, nil
+}
 
-	return nil
+// enforceSizeCap downsamples the oldest days of raw data until the database
+// fits in maxBytes or only today's data is left. It reports whether anything
+// was removed.
+func (s *SQLiteStore) enforceSizeCap(maxBytes int64) (bool, error) {
+	today := time.Now().UTC().Format("2006-01-02")
+	pruned := false
+	for {
+		used, err := s.usedBytes()
+		if err != nil {
+			return pruned, fmt.Errorf("measuring database size: %w", err)
+		}
+		if used <= maxBytes {
+			return pruned, nil
+		}
+
+		var oldest sql.NullString
+		err = s.db.QueryRow(`
+			SELECT MIN(day) FROM (
+				SELECT MIN(date(timestamp)) AS day FROM metrics
+				UNION ALL
+				SELECT MIN(date(timestamp)) FROM events
+			)
+		`).Scan(&oldest)
+		if err != nil {
+			return pruned, fmt.Errorf("finding oldest raw data: %w", err)
+		}
+		if !oldest.Valid || oldest.String >= today {
+			log.Printf("WARNING: database uses %d MB, over max_db_size_mb, with only today's raw data left", used/(1024*1024))
+			return pruned, nil
+		}
+
+		day, err := time.Parse("2006-01-02", oldest.String)
+		if err != nil {
+			return pruned, fmt.Errorf("parsing day %q: %w", oldest.String, err)
+		}
+		if err := s.downsampleRaw(day.AddDate(0, 0, 1)); err != nil {
+			return pruned, err
+		}
+		pruned = true
+	}
 }
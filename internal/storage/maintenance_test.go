@@ -403,3 +403,145 @@ func TestMaintenance_FailureRetried(t *testing.T) {
 		t.Error("memory store should still work after maintenance failure")
 	}
 }
+
+func TestMaintenance_EnforceSizeCap(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Now().UTC()
+	for _, ts := range []time.Time{now.AddDate(0, 0, -3), now.AddDate(0, 0, -2), now} {
+		_, err := store.db.Exec(
+			"INSERT INTO metrics (session_id, name, value, timestamp) VALUES (?, ?, ?, ?)",
+			"sess-cap", "claude_code.cost.usage", 1.0, ts.Format(time.RFC3339Nano))
+		if err != nil {
+			t.Fatalf("insert metric: %v", err)
+		}
+	}
+
+	pruned, err := store.enforceSizeCap(1 << 40)
+	if err != nil || pruned {
+		t.Fatalf("under the cap nothing should be pruned: pruned=%v err=%v", pruned, err)
+	}
+
+	// A cap below any possible size prunes everything but today.
+	pruned, err = store.enforceSizeCap(1)
+	if err != nil {
+		t.Fatalf("enforceSizeCap: %v", err)
+	}
+	if !pruned {
+		t.Error("expected old raw data to be pruned")
+	}
+	var remaining, summaries int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM metrics").Scan(&remaining); err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 1 {
+		t.Errorf("only today's metric should remain, got %d rows", remaining)
+	}
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM daily_summaries WHERE session_id = 'sess-cap'").Scan(&summaries); err != nil {
+		t.Fatal(err)
+	}
+	if summaries != 2 {
+		t.Errorf("pruned days should be downsampled into daily_summaries, got %d rows", summaries)
+	}
+}
+
+func TestMaintenance_RecordProjectionAccuracy(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	day := yesterday.Format("2006-01-02")
+	start, _ := time.ParseInLocation("2006-01-02", day, time.Local)
+
+	for _, d := range []string{day, now.Format("2006-01-02")} {
+		if _, err := store.db.Exec("INSERT INTO daily_stats (date, total_cost) VALUES (?, ?)", d, 12.0); err != nil {
+			t.Fatalf("insert daily stats: %v", err)
+		}
+	}
+	// The last snapshot of the day is the end-of-day projection.
+	for _, snap := range []struct {
+		at   time.Time
+		proj float64
+	}{
+		{start.Add(9 * time.Hour), 40},
+		{start.Add(23 * time.Hour), 15},
+		{start.AddDate(0, 0, 1).Add(time.Minute), 99},
+	} {
+		_, err := store.db.Exec("INSERT INTO burn_rate_snapshots (timestamp, daily_projection) VALUES (?, ?)",
+			snap.at.UTC().Format(time.RFC3339), snap.proj)
+		if err != nil {
+			t.Fatalf("insert snapshot: %v", err)
+		}
+	}
+
+	if err := store.recordProjectionAccuracy(now); err != nil {
+		t.Fatalf("recordProjectionAccuracy: %v", err)
+	}
+	// A day is recorded once; later changes to its stats are ignored.
+	if _, err := store.db.Exec("UPDATE daily_stats SET total_cost = 50 WHERE date = ?", day); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.recordProjectionAccuracy(now); err != nil {
+		t.Fatalf("recordProjectionAccuracy: %v", err)
+	}
+
+	rows := store.QueryProjectionAccuracy(7)
+	if len(rows) != 1 {
+		t.Fatalf("want only the completed day recorded, got %+v", rows)
+	}
+	if rows[0].Date != day || rows[0].ProjectedCost != 15 || rows[0].ActualCost != 12 {
+		t.Errorf("got %+v, want %s projected 15 actual 12", rows[0], day)
+	}
+}
+
+func TestMaintenanceSchedule_Allows(t *testing.T) {
+	at := func(hhmm string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", "2026-03-10 "+hhmm, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	store.SetMaintenanceSchedule("", 0)
+	if ok, _ := store.maint.allows(at("14:00"), 1000); !ok {
+		t.Error("no window and no busy threshold should always allow maintenance")
+	}
+
+	store.SetMaintenanceSchedule("03:00-04:00", 60)
+	tests := []struct {
+		at       string
+		rate     float64
+		ok, busy bool
+	}{
+		{"03:30", 10, true, false},
+		{"03:30", 60, true, false},
+		{"03:30", 61, false, true},
+		{"04:00", 0, false, false},
+		{"14:00", 0, false, false},
+	}
+	for _, tt := range tests {
+		ok, busy := store.maint.allows(at(tt.at), tt.rate)
+		if ok != tt.ok || busy != tt.busy {
+			t.Errorf("allows(%s, %.0f) = %v, %v; want %v, %v", tt.at, tt.rate, ok, busy, tt.ok, tt.busy)
+		}
+	}
+
+	store.SetMaintenanceSchedule("23:00-02:00", 0)
+	for hhmm, want := range map[string]bool{"23:30": true, "01:59": true, "02:00": false, "12:00": false} {
+		if ok, _ := store.maint.allows(at(hhmm), 0); ok != want {
+			t.Errorf("wrapping window at %s: allows = %v, want %v", hhmm, ok, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// alertNoteRow sets the note of the alert_history rows of one alert.
+type alertNoteRow struct {
+	Rule      string
+	SessionID string
+	FiredAt   string // RFC3339, as written by PersistAlert
+	Note      string
+}
+
+// SetAlertNote attaches a note, such as an incident doc URL, to a persisted
+// alert; an empty note removes it. The write is queued behind the alert's
+// own insert, so an alert that has only just fired can be annotated.
+func (s *SQLiteStore) SetAlertNote(rule, sessionID string, firedAt time.Time, note string) {
+	s.sendWrite(writeOp{opType: "alertNote", note: &alertNoteRow{
+		Rule:      rule,
+		SessionID: sessionID,
+		FiredAt:   firedAt.UTC().Format(time.RFC3339),
+		Note:      note,
+	}})
+}
+
+func (s *SQLiteStore) writeAlertNote(tx *sql.Tx, row *alertNoteRow) error {
+	_, err := tx.Exec(`
+		UPDATE alert_history SET note = ?
+		WHERE rule = ? AND session_id = ? AND fired_at = ?
+	`, row.Note, row.Rule, row.SessionID, row.FiredAt)
+	return err
+}
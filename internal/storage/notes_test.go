@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+)
+
+func TestSQLiteStore_SetAlertNote(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store1, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	firedAt := time.Now()
+	store1.PersistAlert(alerts.Alert{Rule: "CostSurge", Severity: "critical", Message: "first", SessionID: "sess-1", FiredAt: firedAt})
+	store1.PersistAlert(alerts.Alert{Rule: "CostSurge", Severity: "critical", Message: "other", SessionID: "sess-2", FiredAt: firedAt})
+	store1.SetAlertNote("CostSurge", "sess-1", firedAt, "https://example.com/incident-42")
+	_ = store1.Close()
+
+	store2, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (reopen) failed: %v", err)
+	}
+
+	notes := map[string]string{}
+	for _, r := range store2.QueryAlertHistory(7, "CostSurge") {
+		notes[r.SessionID] = r.Note
+	}
+	if notes["sess-1"] != "https://example.com/incident-42" {
+		t.Errorf("note not persisted: %q", notes["sess-1"])
+	}
+	if notes["sess-2"] != "" {
+		t.Errorf("note should only apply to its own alert, got %q", notes["sess-2"])
+	}
+
+	store2.SetAlertNote("CostSurge", "sess-1", firedAt, "")
+	_ = store2.Close()
+	store3, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (reopen) failed: %v", err)
+	}
+	defer func() { _ = store3.Close() }()
+	for _, r := range store3.QueryAlertHistory(7, "CostSurge") {
+		if r.Note != "" {
+			t.Errorf("an empty note should clear it, got %q", r.Note)
+		}
+	}
+}
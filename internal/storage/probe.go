@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SessionRows counts what a database holds for one session.
+type SessionRows struct {
+	Session bool // listed in the sessions table
+	Metrics int
+	Events  int
+}
+
+// CountSessionRows opens the database at dbPath read-only, so it can run
+// alongside the cc-top instance writing it, and counts the rows stored for
+// sessionID. `cc-top send-test` uses it to follow a test batch into storage.
+func CountSessionRows(dbPath, sessionID string) (SessionRows, error) {
+	var rows SessionRows
+	if _, err := os.Stat(dbPath); err != nil {
+		return rows, fmt.Errorf("database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return rows, fmt.Errorf("opening database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var sessions int
+	err = db.QueryRow(`SELECT
+			(SELECT COUNT(*) FROM sessions WHERE session_id = ?),
+			(SELECT COUNT(*) FROM metrics WHERE session_id = ?),
+			(SELECT COUNT(*) FROM events WHERE session_id = ?)`,
+		sessionID, sessionID, sessionID).Scan(&sessions, &rows.Metrics, &rows.Events)
+	if err != nil {
+		return rows, fmt.Errorf("counting rows: %w", err)
+	}
+	rows.Session = sessions > 0
+	return rows, nil
+}
+
+// SchemaVersion is the schema version this build of cc-top reads and writes.
+const SchemaVersion = currentSchemaVersion
+
+// DBHealth describes a database for `cc-top doctor`.
+type DBHealth struct {
+	SizeBytes       int64 // database file plus its write-ahead log
+	SchemaVersion   int
+	Integrity       string    // "ok", or the first problem PRAGMA quick_check found
+	LatestTelemetry time.Time // newest stored metric or event; zero when there is none
+}
+
+// latestTelemetryRows is how many of the newest rows per table InspectDB
+// parses. Timestamps carry the writer's UTC offset, so the lexically
+// greatest one is not always the latest.
+const latestTelemetryRows = 20
+
+// InspectDB opens the database at dbPath read-only, like CountSessionRows,
+// and reports its size, schema version, integrity and newest telemetry.
+func InspectDB(dbPath string) (DBHealth, error) {
+	var h DBHealth
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return h, fmt.Errorf("database: %w", err)
+	}
+	h.SizeBytes = info.Size()
+	if wal, err := os.Stat(dbPath + "-wal"); err == nil {
+		h.SizeBytes += wal.Size()
+	}
+
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return h, fmt.Errorf("opening database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := db.QueryRow("SELECT version FROM schema_version LIMIT 1").Scan(&h.SchemaVersion); err != nil {
+		return h, fmt.Errorf("reading schema version: %w", err)
+	}
+	if err := db.QueryRow("PRAGMA quick_check").Scan(&h.Integrity); err != nil {
+		return h, fmt.Errorf("checking integrity: %w", err)
+	}
+
+	for _, table := range []string{"metrics", "events"} {
+		rows, err := db.Query(fmt.Sprintf("SELECT timestamp FROM %s ORDER BY timestamp DESC LIMIT %d", table, latestTelemetryRows))
+		if err != nil {
+			return h, fmt.Errorf("reading %s timestamps: %w", table, err)
+		}
+		for rows.Next() {
+			var ts string
+			if err := rows.Scan(&ts); err != nil {
+				_ = rows.Close()
+				return h, fmt.Errorf("reading %s timestamps: %w", table, err)
+			}
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil && t.After(h.LatestTelemetry) {
+				h.LatestTelemetry = t
+			}
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return h, fmt.Errorf("reading %s timestamps: %w", table, err)
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountSessionRows(t *testing.T) {
+	dbPath := seedExportDB(t)
+
+	got, err := CountSessionRows(dbPath, "s2")
+	if err != nil {
+		t.Fatalf("CountSessionRows: %v", err)
+	}
+	if want := (SessionRows{Session: true, Metrics: 1, Events: 1}); got != want {
+		t.Errorf("s2: got %+v, want %+v", got, want)
+	}
+
+	got, err = CountSessionRows(dbPath, "missing")
+	if err != nil {
+		t.Fatalf("CountSessionRows: %v", err)
+	}
+	if got != (SessionRows{}) {
+		t.Errorf("unknown session: got %+v, want no rows", got)
+	}
+
+	if _, err := CountSessionRows(dbPath+".nope", "s2"); err == nil {
+		t.Error("a missing database should be an error")
+	}
+}
+
+func TestInspectDB(t *testing.T) {
+	dbPath := seedExportDB(t)
+
+	got, err := InspectDB(dbPath)
+	if err != nil {
+		t.Fatalf("InspectDB: %v", err)
+	}
+	if got.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", got.SchemaVersion, SchemaVersion)
+	}
+	if got.Integrity != "ok" {
+		t.Errorf("Integrity = %q, want ok", got.Integrity)
+	}
+	if got.SizeBytes <= 0 {
+		t.Errorf("SizeBytes = %d, want > 0", got.SizeBytes)
+	}
+	if want := time.Date(2026, 3, 3, 0, 30, 0, 0, time.UTC); !got.LatestTelemetry.Equal(want) {
+		t.Errorf("LatestTelemetry = %v, want %v", got.LatestTelemetry, want)
+	}
+
+	if _, err := InspectDB(dbPath + ".nope"); err == nil {
+		t.Error("a missing database should be an error")
+	}
+}
@@ -3,7 +3,10 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
+	"os"
+	"sort"
 	"time"
 
 	"github.com/nixlim/cc-top/internal/state"
@@ -11,33 +14,36 @@ import (
 
 // DailyStatsRow represents a row from the daily_stats table for query results.
 type DailyStatsRow struct {
-	Date             string
-	TotalCost        float64
-	TokenInput       int64
-	TokenOutput      int64
-	TokenCacheRead   int64
-	TokenCacheWrite  int64
-	SessionCount     int
-	APIRequests      int
-	APIErrors        int
-	LinesAdded       int
-	LinesRemoved     int
-	Commits          int
-	PRsOpened        int
-	CacheEfficiency  float64
-	CacheSavingsUSD  float64
-	ErrorRate        float64
-	RetryRate        float64
-	AvgAPILatency    float64 // seconds (converted from ms on read)
-	LatencyP50       float64 // seconds
-	LatencyP95       float64 // seconds
-	LatencyP99       float64 // seconds
-	ModelBreakdown   string  // raw JSON
-	TopTools         string  // raw JSON
-	ErrorCategories  string  // raw JSON
-	LanguageBreakdown string // raw JSON
-	DecisionSources  string  // raw JSON
-	MCPToolUsage     string  // raw JSON
+	Date              string
+	TotalCost         float64
+	TokenInput        int64
+	TokenOutput       int64
+	TokenCacheRead    int64
+	TokenCacheWrite   int64
+	SessionCount      int
+	APIRequests       int
+	APIErrors         int
+	LinesAdded        int
+	LinesRemoved      int
+	Commits           int
+	PRsOpened         int
+	CacheEfficiency   float64
+	CacheSavingsUSD   float64
+	ErrorRate         float64
+	RetryRate         float64
+	AvgAPILatency     float64 // seconds (converted from ms on read)
+	LatencyP50        float64 // seconds
+	LatencyP95        float64 // seconds
+	LatencyP99        float64 // seconds
+	ModelBreakdown    string  // raw JSON
+	TopTools          string  // raw JSON
+	ErrorCategories   string  // raw JSON
+	LanguageBreakdown string  // raw JSON
+	DecisionSources   string  // raw JSON
+	MCPToolUsage      string  // raw JSON
+	AccountBreakdown  string  // raw JSON
+	ProjectBreakdown  string  // raw JSON
+	ContextUsage      string  // raw JSON
 }
 
 // BurnRateDailySummary aggregates burn rate snapshots by day.
@@ -51,6 +57,13 @@ type BurnRateDailySummary struct {
 	SnapshotCount        int
 }
 
+// BurnRateHourlyRow is the average $/hr of the snapshots in one UTC hour.
+type BurnRateHourlyRow struct {
+	Date          string
+	Hour          int // 0-23
+	AvgHourlyRate float64
+}
+
 // BurnRateSnapshotRow represents a single burn rate snapshot for query results.
 type BurnRateSnapshotRow struct {
 	Timestamp         string
@@ -71,6 +84,21 @@ type AlertHistoryRow struct {
 	Message   string
 	SessionID string
 	FiredAt   string
+	Note      string
+}
+
+// SessionEvent is a persisted event with the session it belongs to.
+type SessionEvent struct {
+	SessionID string
+	Event     state.Event
+}
+
+// ProjectionAccuracyRow compares the daily projection shown at the end of a
+// day with what the day actually cost.
+type ProjectionAccuracyRow struct {
+	Date          string
+	ProjectedCost float64
+	ActualCost    float64
 }
 
 func (s *SQLiteStore) QueryDailySummaries(days int) []state.DailySummary {
@@ -148,21 +176,46 @@ func (s *SQLiteStore) QueryDailySummaries(days int) []state.DailySummary {
 // Latency values are converted from milliseconds back to seconds on read (FR-034).
 func (s *SQLiteStore) QueryDailyStats(days int) []DailyStatsRow {
 	cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	result, err := queryDailyStats(s.db, cutoff, "")
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+	return result
+}
 
-	rows, err := s.db.Query(`
+// ReadDailyStats returns the daily stats from one date to another
+// (inclusive, YYYY-MM-DD) in the database at dbPath, newest first. The
+// database is opened read-only, so cc-top report can run alongside the
+// cc-top instance writing it.
+func ReadDailyStats(dbPath, from, to string) ([]DailyStatsRow, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	return queryDailyStats(db, from, to)
+}
+
+// queryDailyStats reads the daily stats from one date on, up to to unless
+// it is empty.
+func queryDailyStats(db *sql.DB, cutoff, to string) ([]DailyStatsRow, error) {
+	rows, err := db.Query(`
 		SELECT date, total_cost, token_input, token_output, token_cache_read, token_cache_write,
 			session_count, api_requests, api_errors, lines_added, lines_removed,
 			commits, prs_opened, cache_efficiency, cache_savings_usd, error_rate, retry_rate,
 			avg_api_latency_ms, latency_p50_ms, latency_p95_ms, latency_p99_ms,
 			model_breakdown, top_tools, error_categories, language_breakdown,
-			decision_sources, mcp_tool_usage
+			decision_sources, mcp_tool_usage, account_breakdown, project_breakdown,
+			context_usage
 		FROM daily_stats
-		WHERE date >= ?
+		WHERE date >= ? AND (? = '' OR date <= ?)
 		ORDER BY date DESC
-	`, cutoff)
+	`, cutoff, to, to)
 	if err != nil {
-		log.Printf("ERROR: querying daily stats: %v", err)
-		return nil
+		return nil, fmt.Errorf("querying daily stats: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
@@ -172,14 +225,14 @@ func (s *SQLiteStore) QueryDailyStats(days int) []DailyStatsRow {
 	for rows.Next() {
 		var r DailyStatsRow
 		var avgLatMs, p50Ms, p95Ms, p99Ms float64
-		var modelJSON, toolsJSON, errCatJSON, langJSON, decJSON, mcpJSON sql.NullString
+		var modelJSON, toolsJSON, errCatJSON, langJSON, decJSON, mcpJSON, acctJSON, projJSON, ctxJSON sql.NullString
 
 		if err := rows.Scan(
 			&r.Date, &r.TotalCost, &r.TokenInput, &r.TokenOutput, &r.TokenCacheRead, &r.TokenCacheWrite,
 			&r.SessionCount, &r.APIRequests, &r.APIErrors, &r.LinesAdded, &r.LinesRemoved,
 			&r.Commits, &r.PRsOpened, &r.CacheEfficiency, &r.CacheSavingsUSD, &r.ErrorRate, &r.RetryRate,
 			&avgLatMs, &p50Ms, &p95Ms, &p99Ms,
-			&modelJSON, &toolsJSON, &errCatJSON, &langJSON, &decJSON, &mcpJSON,
+			&modelJSON, &toolsJSON, &errCatJSON, &langJSON, &decJSON, &mcpJSON, &acctJSON, &projJSON, &ctxJSON,
 		); err != nil {
 			log.Printf("ERROR: scanning daily stats row: %v", err)
 			continue
@@ -198,6 +251,9 @@ func (s *SQLiteStore) QueryDailyStats(days int) []DailyStatsRow {
 		r.LanguageBreakdown = nullStringValue(langJSON)
 		r.DecisionSources = nullStringValue(decJSON)
 		r.MCPToolUsage = nullStringValue(mcpJSON)
+		r.AccountBreakdown = nullStringValue(acctJSON)
+		r.ProjectBreakdown = nullStringValue(projJSON)
+		r.ContextUsage = nullStringValue(ctxJSON)
 
 		seenDates[r.Date] = true
 		result = append(result, r)
@@ -207,17 +263,16 @@ func (s *SQLiteStore) QueryDailyStats(days int) []DailyStatsRow {
 	}
 
 	// Merge in daily_summaries for dates not covered by daily_stats (FR-035)
-	summaryRows, err := s.db.Query(`
+	summaryRows, err := db.Query(`
 		SELECT date, SUM(total_cost), SUM(total_tokens), SUM(api_requests), SUM(api_errors),
 			COUNT(DISTINCT session_id)
 		FROM daily_summaries
-		WHERE date >= ?
+		WHERE date >= ? AND (? = '' OR date <= ?)
 		GROUP BY date
 		ORDER BY date DESC
-	`, cutoff)
+	`, cutoff, to, to)
 	if err != nil {
-		log.Printf("ERROR: querying daily summaries for merge: %v", err)
-		return result
+		return result, fmt.Errorf("querying daily summaries for merge: %w", err)
 	}
 	defer func() { _ = summaryRows.Close() }()
 
@@ -250,7 +305,7 @@ func (s *SQLiteStore) QueryDailyStats(days int) []DailyStatsRow {
 	// Re-sort by date descending after merge
 	sortDailyStatsDesc(result)
 
-	return result
+	return result, nil
 }
 
 // QueryBurnRateDailySummary aggregates burn rate snapshots by day.
@@ -289,6 +344,42 @@ func (s *SQLiteStore) QueryBurnRateDailySummary(days int) []BurnRateDailySummary
 	return result
 }
 
+// QueryBurnRateHourly averages burn rate snapshots by day and hour, for
+// intra-day sparklines. Rows are ordered by day, then hour; hours without
+// snapshots are omitted.
+func (s *SQLiteStore) QueryBurnRateHourly(days int) []BurnRateHourlyRow {
+	cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+
+	rows, err := s.db.Query(`
+		SELECT date(timestamp) AS day,
+			CAST(strftime('%H', timestamp) AS INTEGER) AS hour,
+			AVG(hourly_rate)
+		FROM burn_rate_snapshots
+		WHERE date(timestamp) >= ?
+		GROUP BY day, hour
+		ORDER BY day, hour
+	`, cutoff)
+	if err != nil {
+		log.Printf("ERROR: querying hourly burn rate: %v", err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []BurnRateHourlyRow
+	for rows.Next() {
+		var r BurnRateHourlyRow
+		if err := rows.Scan(&r.Date, &r.Hour, &r.AvgHourlyRate); err != nil {
+			log.Printf("ERROR: scanning hourly burn rate row: %v", err)
+			continue
+		}
+		result = append(result, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating hourly burn rate rows: %v", err)
+	}
+	return result
+}
+
 // QueryBurnRateSnapshots returns individual burn rate snapshots, max 500 (FR-023).
 func (s *SQLiteStore) QueryBurnRateSnapshots(days int) []BurnRateSnapshotRow {
 	cutoff := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
@@ -358,6 +449,38 @@ func (s *SQLiteStore) QueryBurnRateSnapshotsForDate(date string) []BurnRateSnaps
 	return result
 }
 
+// QueryProjectionAccuracy returns the recorded projection accuracy of the
+// last days, newest first.
+func (s *SQLiteStore) QueryProjectionAccuracy(days int) []ProjectionAccuracyRow {
+	cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+
+	rows, err := s.db.Query(`
+		SELECT date, projected_cost, actual_cost
+		FROM projection_accuracy
+		WHERE date >= ?
+		ORDER BY date DESC
+	`, cutoff)
+	if err != nil {
+		log.Printf("ERROR: querying projection accuracy: %v", err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []ProjectionAccuracyRow
+	for rows.Next() {
+		var r ProjectionAccuracyRow
+		if err := rows.Scan(&r.Date, &r.ProjectedCost, &r.ActualCost); err != nil {
+			log.Printf("ERROR: scanning projection accuracy row: %v", err)
+			continue
+		}
+		result = append(result, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating projection accuracy rows: %v", err)
+	}
+	return result
+}
+
 // QueryAlertHistory returns alert history rows, max 200 (FR-024).
 // If ruleFilter is non-empty, only alerts matching that rule are returned.
 func (s *SQLiteStore) QueryAlertHistory(days int, ruleFilter string) []AlertHistoryRow {
@@ -368,7 +491,7 @@ func (s *SQLiteStore) QueryAlertHistory(days int, ruleFilter string) []AlertHist
 
 	if ruleFilter != "" {
 		dbRows, err = s.db.Query(`
-			SELECT id, rule, severity, message, session_id, fired_at
+			SELECT id, rule, severity, message, session_id, fired_at, COALESCE(note, '')
 			FROM alert_history
 			WHERE fired_at >= ? AND rule = ?
 			ORDER BY fired_at DESC
@@ -376,7 +499,7 @@ func (s *SQLiteStore) QueryAlertHistory(days int, ruleFilter string) []AlertHist
 		`, cutoff, ruleFilter)
 	} else {
 		dbRows, err = s.db.Query(`
-			SELECT id, rule, severity, message, session_id, fired_at
+			SELECT id, rule, severity, message, session_id, fired_at, COALESCE(note, '')
 			FROM alert_history
 			WHERE fired_at >= ?
 			ORDER BY fired_at DESC
@@ -392,7 +515,7 @@ func (s *SQLiteStore) QueryAlertHistory(days int, ruleFilter string) []AlertHist
 	var result []AlertHistoryRow
 	for dbRows.Next() {
 		var r AlertHistoryRow
-		if err := dbRows.Scan(&r.ID, &r.Rule, &r.Severity, &r.Message, &r.SessionID, &r.FiredAt); err != nil {
+		if err := dbRows.Scan(&r.ID, &r.Rule, &r.Severity, &r.Message, &r.SessionID, &r.FiredAt, &r.Note); err != nil {
 			log.Printf("ERROR: scanning alert history row: %v", err)
 			continue
 		}
@@ -404,6 +527,189 @@ func (s *SQLiteStore) QueryAlertHistory(days int, ruleFilter string) []AlertHist
 	return result
 }
 
+// ReadAlertHistory returns the alerts fired from one time up to another
+// (exclusive) in the database at dbPath, oldest first. The database is
+// opened read-only, like ReadDailyStats.
+func ReadAlertHistory(dbPath string, from, to time.Time) ([]AlertHistoryRow, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.Query(`
+		SELECT id, rule, severity, message, session_id, fired_at, COALESCE(note, '')
+		FROM alert_history
+		WHERE fired_at >= ? AND fired_at < ?
+		ORDER BY fired_at, id
+	`, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying alert history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []AlertHistoryRow
+	for rows.Next() {
+		var r AlertHistoryRow
+		if err := rows.Scan(&r.ID, &r.Rule, &r.Severity, &r.Message, &r.SessionID, &r.FiredAt, &r.Note); err != nil {
+			return nil, fmt.Errorf("scanning alert history row: %w", err)
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// QuerySessionEvents returns every persisted event of a session in
+// timeline order, for replaying it.
+func (s *SQLiteStore) QuerySessionEvents(sessionID string) []state.Event {
+	rows, err := s.db.Query(`
+		SELECT name, timestamp, sequence, attributes
+		FROM events
+		WHERE session_id = ?
+		ORDER BY id ASC
+	`, sessionID)
+	if err != nil {
+		log.Printf("ERROR: querying events for session %s: %v", sessionID, err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []state.Event
+	for rows.Next() {
+		var e state.Event
+		var timestamp string
+		var sequence sql.NullInt64
+		var attributesJSON sql.NullString
+		if err := rows.Scan(&e.Name, &timestamp, &sequence, &attributesJSON); err != nil {
+			log.Printf("ERROR: scanning event row: %v", err)
+			continue
+		}
+		e.Sequence = sequence.Int64
+		if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			e.Timestamp = t
+		}
+		if attributesJSON.Valid && attributesJSON.String != "" {
+			if err := json.Unmarshal([]byte(attributesJSON.String), &e.Attributes); err != nil {
+				log.Printf("WARNING: unmarshaling event attributes: %v", err)
+			}
+		}
+		result = append(result, e)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating event rows: %v", err)
+	}
+
+	// Timestamps are stored with their original offsets, so order in Go
+	// rather than by string.
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Timestamp.Equal(result[j].Timestamp) {
+			return result[i].Timestamp.Before(result[j].Timestamp)
+		}
+		return result[i].Sequence < result[j].Sequence
+	})
+	return result
+}
+
+// QueryEvents returns the persisted events recorded in [from, to), newest
+// first, optionally only those of one session and one event name. At most
+// limit events are returned.
+func (s *SQLiteStore) QueryEvents(from, to time.Time, sessionID, name string, limit int) []SessionEvent {
+	query := `
+		SELECT session_id, name, timestamp, sequence, attributes
+		FROM events
+		WHERE datetime(timestamp) >= ? AND datetime(timestamp) < ?`
+	args := []any{from.UTC().Format(sqliteDateTime), to.UTC().Format(sqliteDateTime)}
+	if sessionID != "" {
+		query += " AND session_id = ?"
+		args = append(args, sessionID)
+	}
+	if name != "" {
+		query += " AND name = ?"
+		args = append(args, name)
+	}
+	query += " ORDER BY datetime(timestamp) DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("ERROR: querying events: %v", err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []SessionEvent
+	for rows.Next() {
+		var se SessionEvent
+		var timestamp string
+		var sequence sql.NullInt64
+		var attributesJSON sql.NullString
+		if err := rows.Scan(&se.SessionID, &se.Event.Name, &timestamp, &sequence, &attributesJSON); err != nil {
+			log.Printf("ERROR: scanning event row: %v", err)
+			continue
+		}
+		se.Event.Sequence = sequence.Int64
+		if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			se.Event.Timestamp = t
+		}
+		if attributesJSON.Valid && attributesJSON.String != "" {
+			if err := json.Unmarshal([]byte(attributesJSON.String), &se.Event.Attributes); err != nil {
+				log.Printf("WARNING: unmarshaling event attributes: %v", err)
+			}
+		}
+		result = append(result, se)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating event rows: %v", err)
+	}
+
+	// datetime() drops fractional seconds; order within a second in Go.
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Event.Timestamp.After(result[j].Event.Timestamp)
+	})
+	return result
+}
+
+// QueryEventFacets returns the distinct sessions and event names of the
+// events recorded in [from, to), sorted, for filtering QueryEvents.
+func (s *SQLiteStore) QueryEventFacets(from, to time.Time) (sessions, names []string) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT session_id, name
+		FROM events
+		WHERE datetime(timestamp) >= ? AND datetime(timestamp) < ?
+	`, from.UTC().Format(sqliteDateTime), to.UTC().Format(sqliteDateTime))
+	if err != nil {
+		log.Printf("ERROR: querying event facets: %v", err)
+		return nil, nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	seenSessions, seenNames := make(map[string]bool), make(map[string]bool)
+	for rows.Next() {
+		var sessionID, name string
+		if err := rows.Scan(&sessionID, &name); err != nil {
+			log.Printf("ERROR: scanning event facet row: %v", err)
+			continue
+		}
+		if !seenSessions[sessionID] {
+			seenSessions[sessionID] = true
+			sessions = append(sessions, sessionID)
+		}
+		if !seenNames[name] {
+			seenNames[name] = true
+			names = append(names, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating event facet rows: %v", err)
+	}
+	sort.Strings(sessions)
+	sort.Strings(names)
+	return sessions, names
+}
+
 // QueryDistinctAlertRules returns the distinct alert rule names from history.
 func (s *SQLiteStore) QueryDistinctAlertRules() []string {
 	rows, err := s.db.Query("SELECT DISTINCT rule FROM alert_history ORDER BY rule")
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -414,6 +416,37 @@ func TestQueryDailyStats_MergesDailySummaries(t *testing.T) {
 	}
 }
 
+func TestReadDailyStats_DateRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	seedSyncDB(t, path,
+		`INSERT INTO daily_stats (date, total_cost, model_breakdown) VALUES ('2026-03-08', 1, NULL)`,
+		`INSERT INTO daily_stats (date, total_cost, model_breakdown) VALUES ('2026-03-09', 2, '[{"Model":"opus","TotalCost":2}]')`,
+		`INSERT INTO daily_stats (date, total_cost, model_breakdown) VALUES ('2026-03-15', 3, NULL)`,
+		`INSERT INTO daily_stats (date, total_cost, model_breakdown) VALUES ('2026-03-16', 4, NULL)`,
+		`INSERT INTO daily_summaries (session_id, date, total_cost, total_tokens, api_requests, api_errors, active_seconds) VALUES ('s1', '2026-03-10', 5, 100, 1, 0, 60)`,
+		`INSERT INTO daily_summaries (session_id, date, total_cost, total_tokens, api_requests, api_errors, active_seconds) VALUES ('s1', '2026-03-17', 6, 100, 1, 0, 60)`,
+	)
+
+	rows, err := ReadDailyStats(path, "2026-03-09", "2026-03-15")
+	if err != nil {
+		t.Fatalf("ReadDailyStats: %v", err)
+	}
+	var dates []string
+	for _, r := range rows {
+		dates = append(dates, r.Date)
+	}
+	if strings.Join(dates, ",") != "2026-03-15,2026-03-10,2026-03-09" {
+		t.Errorf("dates: got %v", dates)
+	}
+	if rows[2].ModelBreakdown == "" {
+		t.Error("breakdowns should be read")
+	}
+
+	if _, err := ReadDailyStats(filepath.Join(t.TempDir(), "missing.db"), "2026-03-09", "2026-03-15"); err == nil {
+		t.Error("a missing database should fail")
+	}
+}
+
 func TestQueryDailyStats_EmptyDB(t *testing.T) {
 	store := newTestStore(t)
 	defer func() { _ = store.Close() }()
@@ -505,6 +538,38 @@ func TestQueryBurnRateDailySummary_EmptyDB(t *testing.T) {
 	}
 }
 
+func TestQueryBurnRateHourly(t *testing.T) {
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	today := time.Now().UTC().Format("2006-01-02")
+	for _, snap := range []struct {
+		ts   string
+		rate float64
+	}{
+		{today + "T10:05:00Z", 2.0},
+		{today + "T10:40:00Z", 4.0},
+		{today + "T15:00:00Z", 1.0},
+	} {
+		if _, err := store.db.Exec(
+			"INSERT INTO burn_rate_snapshots (timestamp, total_cost, hourly_rate, trend, token_velocity, daily_projection, monthly_projection) VALUES (?, 0, ?, 0, 0, 0, 0)",
+			snap.ts, snap.rate); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	rows := store.QueryBurnRateHourly(7)
+	if len(rows) != 2 {
+		t.Fatalf("want 2 hours, got %d: %+v", len(rows), rows)
+	}
+	if rows[0].Date != today || rows[0].Hour != 10 || rows[0].AvgHourlyRate != 3.0 {
+		t.Errorf("first hour: want %s 10 $3.00, got %+v", today, rows[0])
+	}
+	if rows[1].Hour != 15 || rows[1].AvgHourlyRate != 1.0 {
+		t.Errorf("second hour: want 15 $1.00, got %+v", rows[1])
+	}
+}
+
 // --- QueryBurnRateSnapshots Tests ---
 
 func TestQueryBurnRateSnapshots_RoundTrip(t *testing.T) {
@@ -604,6 +669,28 @@ func TestQueryBurnRateSnapshotsForDate_Filters(t *testing.T) {
 
 // --- QueryAlertHistory Tests ---
 
+func TestReadAlertHistory_Range(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	seedSyncDB(t, path,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('A', 'critical', 'too early', 's1', '2026-03-08T23:59:59Z')`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('B', 'warning', 'second', 's1', '2026-03-10T12:00:00Z')`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('C', 'critical', 'first', 's2', '2026-03-09T00:00:00Z')`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('D', 'critical', 'too late', 's2', '2026-03-11T00:00:00Z')`,
+	)
+
+	rows, err := ReadAlertHistory(path, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ReadAlertHistory: %v", err)
+	}
+	var rules []string
+	for _, r := range rows {
+		rules = append(rules, r.Rule)
+	}
+	if strings.Join(rules, ",") != "C,B" {
+		t.Errorf("rules: got %v, want C,B", rules)
+	}
+}
+
 func TestQueryAlertHistory_RoundTrip(t *testing.T) {
 	store := newTestStore(t)
 	defer func() { _ = store.Close() }()
@@ -728,3 +815,166 @@ func TestQueryDistinctAlertRules_EmptyDB(t *testing.T) {
 		t.Errorf("empty DB should return 0 rules, got %d", len(rules))
 	}
 }
+
+func TestQueryDailyStats_AccountBreakdown(t *testing.T) {
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	today := time.Now().Format("2006-01-02")
+	ds := stats.DashboardStats{
+		AccountBreakdown: []stats.AccountStats{
+			{OrgID: "org-a", UserUUID: "user-1", TotalCost: 4.25, SessionCount: 2},
+			{OrgID: "org-b", UserUUID: "user-2", TotalCost: 1.10, SessionCount: 1},
+		},
+	}
+
+	store.WriteDailyStats(today, ds)
+	time.Sleep(200 * time.Millisecond)
+
+	rows := store.QueryDailyStats(7)
+	if len(rows) != 1 {
+		t.Fatalf("want 1 row, got %d", len(rows))
+	}
+
+	var accounts []stats.AccountStats
+	unmarshalJSONField(rows[0].AccountBreakdown, &accounts)
+	if len(accounts) != 2 {
+		t.Fatalf("want 2 accounts, got %d (%q)", len(accounts), rows[0].AccountBreakdown)
+	}
+	if accounts[0].OrgID != "org-a" || accounts[0].TotalCost != 4.25 || accounts[0].SessionCount != 2 {
+		t.Errorf("unexpected first account: %+v", accounts[0])
+	}
+}
+
+func TestQueryDailyStats_ProjectBreakdown(t *testing.T) {
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	today := time.Now().Format("2006-01-02")
+	ds := stats.DashboardStats{
+		ProjectBreakdown: []stats.ProjectStats{
+			{Project: "/src/api", SessionCount: 2, TotalCost: 3.5, Commits: 4, APIRequests: 20, APIErrors: 1, ErrorRate: 0.05,
+				Branches: []stats.BranchStats{{Branch: "main", SessionCount: 2, TotalCost: 3.5}}},
+			{Project: "/src/web", SessionCount: 1, TotalCost: 0.5},
+		},
+	}
+
+	store.WriteDailyStats(today, ds)
+	time.Sleep(200 * time.Millisecond)
+
+	rows := store.QueryDailyStats(7)
+	if len(rows) != 1 {
+		t.Fatalf("want 1 row, got %d", len(rows))
+	}
+
+	var projects []stats.ProjectStats
+	unmarshalJSONField(rows[0].ProjectBreakdown, &projects)
+	if len(projects) != 2 {
+		t.Fatalf("want 2 projects, got %d (%q)", len(projects), rows[0].ProjectBreakdown)
+	}
+	if !reflect.DeepEqual(projects[0], ds.ProjectBreakdown[0]) {
+		t.Errorf("first project = %+v, want %+v", projects[0], ds.ProjectBreakdown[0])
+	}
+}
+
+func TestQueryDailyStats_ContextUsage(t *testing.T) {
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	today := time.Now().Format("2006-01-02")
+	ds := stats.DashboardStats{
+		ContextUsage: []stats.ContextUsage{
+			{Model: "claude-opus-4-6", MaxTokens: 178000, Limit: 200000, MaxPercent: 89, Requests: 40, NearLimit: 3},
+		},
+	}
+
+	store.WriteDailyStats(today, ds)
+	time.Sleep(200 * time.Millisecond)
+
+	rows := store.QueryDailyStats(7)
+	if len(rows) != 1 {
+		t.Fatalf("want 1 row, got %d", len(rows))
+	}
+
+	var usage []stats.ContextUsage
+	unmarshalJSONField(rows[0].ContextUsage, &usage)
+	if len(usage) != 1 || usage[0] != ds.ContextUsage[0] {
+		t.Errorf("context usage = %+v (%q), want %+v", usage, rows[0].ContextUsage, ds.ContextUsage)
+	}
+}
+
+func TestQuerySessionEvents_TimelineOrder(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	// Inserted out of order, with mixed offsets and a NULL sequence.
+	for _, q := range []string{
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-r', 'claude_code.api_request', '2026-03-01T12:00:05+02:00', 2, '{"cost_usd":"0.5"}')`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-r', 'claude_code.user_prompt', '2026-03-01T10:00:00Z', NULL, NULL)`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-r', 'claude_code.tool_result', '2026-03-01T10:00:10Z', 3, '')`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('other', 'claude_code.user_prompt', '2026-03-01T09:00:00Z', 1, NULL)`,
+	} {
+		if _, err := store.db.Exec(q); err != nil {
+			t.Fatalf("insert event: %v", err)
+		}
+	}
+
+	got := store.QuerySessionEvents("sess-r")
+	want := []string{"claude_code.user_prompt", "claude_code.api_request", "claude_code.tool_result"}
+	if len(got) != len(want) {
+		t.Fatalf("want %d events, got %d", len(want), len(got))
+	}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("event %d: want %s, got %s", i, name, got[i].Name)
+		}
+	}
+	if got[1].Attributes["cost_usd"] != "0.5" {
+		t.Errorf("attributes not decoded: %v", got[1].Attributes)
+	}
+}
+
+func TestQueryEvents_DayAndFilters(t *testing.T) {
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	for _, q := range []string{
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-a', 'claude_code.tool_result', '2026-03-01T10:00:00Z', 1, '{"tool_name":"Bash"}')`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-a', 'claude_code.api_error', '2026-03-01T10:00:05.5Z', 2, NULL)`,
+		// 2026-03-01T23:30:00Z, written with an offset.
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-b', 'claude_code.tool_result', '2026-03-02T01:30:00+02:00', 1, NULL)`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('sess-b', 'claude_code.user_prompt', '2026-03-02T00:00:00Z', 2, NULL)`,
+	} {
+		if _, err := store.db.Exec(q); err != nil {
+			t.Fatalf("insert event: %v", err)
+		}
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	got := store.QueryEvents(from, to, "", "", 100)
+	if len(got) != 3 {
+		t.Fatalf("want 3 events on the day, got %d", len(got))
+	}
+	if got[0].SessionID != "sess-b" || got[2].Event.Attributes["tool_name"] != "Bash" {
+		t.Errorf("want newest first, got %+v", got)
+	}
+
+	if got := store.QueryEvents(from, to, "sess-a", "claude_code.api_error", 100); len(got) != 1 || got[0].Event.Sequence != 2 {
+		t.Errorf("session and name filter: got %+v", got)
+	}
+	if got := store.QueryEvents(from, to, "", "", 2); len(got) != 2 {
+		t.Errorf("limit: want 2 events, got %d", len(got))
+	}
+
+	sessions, names := store.QueryEventFacets(from, to)
+	if strings.Join(sessions, ",") != "sess-a,sess-b" {
+		t.Errorf("sessions = %v", sessions)
+	}
+	if strings.Join(names, ",") != "claude_code.api_error,claude_code.tool_result" {
+		t.Errorf("names = %v", names)
+	}
+}
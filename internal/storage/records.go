@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/records"
+)
+
+// recordRow holds a records change.
+type recordRow struct {
+	Name       string
+	Value      float64
+	SessionID  string
+	Detail     string
+	AchievedAt string // RFC3339
+}
+
+// SaveRecord implements the records.Store interface.
+func (s *SQLiteStore) SaveRecord(r records.Record) {
+	s.sendWrite(writeOp{opType: "record", record: &recordRow{
+		Name:       r.Name,
+		Value:      r.Value,
+		SessionID:  r.SessionID,
+		Detail:     r.Detail,
+		AchievedAt: r.AchievedAt.UTC().Format(time.RFC3339),
+	}})
+}
+
+// LoadRecords implements the records.Store interface.
+func (s *SQLiteStore) LoadRecords() map[string]records.Record {
+	rows, err := s.db.Query("SELECT name, value, session_id, detail, achieved_at FROM records")
+	if err != nil {
+		log.Printf("ERROR: querying records: %v", err)
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[string]records.Record)
+	for rows.Next() {
+		var r records.Record
+		var achievedAt string
+		if err := rows.Scan(&r.Name, &r.Value, &r.SessionID, &r.Detail, &achievedAt); err != nil {
+			log.Printf("ERROR: scanning record: %v", err)
+			continue
+		}
+		r.AchievedAt, _ = time.Parse(time.RFC3339, achievedAt)
+		result[r.Name] = r
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: iterating records: %v", err)
+	}
+	return result
+}
+
+func (s *SQLiteStore) writeRecord(tx *sql.Tx, row *recordRow) error {
+	_, err := tx.Exec(`
+		INSERT INTO records (name, value, session_id, detail, achieved_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET value=excluded.value, session_id=excluded.session_id,
+			detail=excluded.detail, achieved_at=excluded.achieved_at
+	`, row.Name, row.Value, row.SessionID, row.Detail, row.AchievedAt)
+	return err
+}
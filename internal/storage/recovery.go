@@ -15,7 +15,8 @@ func (s *SQLiteStore) recoverSessions() error {
 		SELECT session_id, pid, terminal, cwd, model, total_cost, total_tokens,
 		       cache_read_tokens, cache_creation_tokens, active_time_seconds,
 		       started_at, last_event_at, exited, fast_mode, org_id, user_uuid,
-		       service_version, os_type, os_version, host_arch
+		       service_version, os_type, os_version, host_arch,
+		       host_name, service_instance_id, environment, user_name
 		FROM sessions
 		WHERE datetime(last_event_at) > datetime('now', '-24 hours')
 	`)
@@ -34,12 +35,14 @@ func (s *SQLiteStore) recoverSessions() error {
 		var startedAt, lastEventAt sql.NullString
 		var exited, fastMode sql.NullInt64
 		var orgID, userUUID, serviceVersion, osType, osVersion, hostArch sql.NullString
+		var hostName, serviceInstanceID, environment, userName sql.NullString
 
 		err := rows.Scan(
 			&sessionID, &pid, &terminal, &cwd, &model,
 			&totalCost, &totalTokens, &cacheReadTokens, &cacheCreationTokens,
 			&activeTimeSeconds, &startedAt, &lastEventAt, &exited, &fastMode,
 			&orgID, &userUUID, &serviceVersion, &osType, &osVersion, &hostArch,
+			&hostName, &serviceInstanceID, &environment, &userName,
 		)
 		if err != nil {
 			failCount++
@@ -77,10 +80,14 @@ func (s *SQLiteStore) recoverSessions() error {
 		}
 
 		session.Metadata = state.SessionMetadata{
-			ServiceVersion: serviceVersion.String,
-			OSType:         osType.String,
-			OSVersion:      osVersion.String,
-			HostArch:       hostArch.String,
+			ServiceVersion:    serviceVersion.String,
+			OSType:            osType.String,
+			OSVersion:         osVersion.String,
+			HostArch:          hostArch.String,
+			HostName:          hostName.String,
+			ServiceInstanceID: serviceInstanceID.String,
+			Environment:       environment.String,
+			User:              userName.String,
 		}
 
 		if err := s.recoverCounterState(sessionID, session); err != nil {
@@ -218,6 +225,7 @@ func (s *SQLiteStore) recoverEvents(sessionID string, session *state.SessionData
 		}
 
 		session.Events = append(session.Events, event)
+		session.TrackContext(event)
 	}
 
 	return rows.Err()
@@ -223,3 +223,40 @@ func TestSQLiteStore_RecoveryCompletesBeforeReturn(t *testing.T) {
 		t.Errorf("metrics not recovered synchronously: want 1, got %d", len(session.Metrics))
 	}
 }
+
+func TestSQLiteStore_RecoveryRestoresResourceMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store1, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	store1.AddMetric("sess-env", state.Metric{Name: "test.metric", Value: 1, Timestamp: time.Now()})
+	store1.UpdateMetadata("sess-env", state.SessionMetadata{
+		HostName:          "build-01",
+		ServiceInstanceID: "agent-7",
+		Environment:       "prod",
+		User:              "alice",
+	})
+	// A later resource without these attributes keeps the stored values.
+	store1.UpdateMetadata("sess-env", state.SessionMetadata{ServiceVersion: "2.0.0"})
+
+	time.Sleep(150 * time.Millisecond)
+	_ = store1.Close()
+
+	store2, err := NewSQLiteStore(dbPath, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (recovery) failed: %v", err)
+	}
+	defer func() { _ = store2.Close() }()
+
+	session := store2.GetSession("sess-env")
+	if session == nil {
+		t.Fatal("session not recovered from SQLite")
+	}
+	got := session.Metadata
+	if got.HostName != "build-01" || got.ServiceInstanceID != "agent-7" || got.Environment != "prod" || got.ServiceVersion != "2.0.0" || got.User != "alice" {
+		t.Errorf("metadata not recovered: %+v", got)
+	}
+}
@@ -3,45 +3,87 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
 	_ "modernc.org/sqlite"
 )
 
-const currentSchemaVersion = 2
+const currentSchemaVersion = 15
 
+// OpenDB opens and migrates the database at dbPath with the default
+// tuning, except that it keeps an existing database's journal mode, which
+// persists in the file.
 func OpenDB(dbPath string) (*sql.DB, error) {
+	t := DefaultTuning()
+	if _, err := os.Stat(dbPath); err == nil {
+		t.JournalMode = ""
+	}
+	return openDB(dbPath, t)
+}
+
+func openDB(dbPath string, t Tuning) (*sql.DB, error) {
+	if t.ReadOnly {
+		return openReadOnlyDB(dbPath, t)
+	}
 	parentDir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(parentDir, 0755); err != nil {
 		return nil, fmt.Errorf("creating parent directories: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	// Connection-scoped pragmas go in the DSN, so that the driver applies
+	// them to every connection of the pool, not just the first.
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", t.BusyTimeout.Milliseconds()))
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	if t.Synchronous != "" {
+		pragmas.Add("_pragma", "synchronous("+t.Synchronous+")")
+	}
+	db, err := sql.Open("sqlite", dbPath+"?"+pragmas.Encode())
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
 
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("enabling WAL mode: %w", err)
+	if t.JournalMode != "" {
+		if _, err := db.Exec("PRAGMA journal_mode=" + t.JournalMode); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("setting journal_mode %s: %w", t.JournalMode, err)
+		}
 	}
 
-	if _, err := db.Exec("PRAGMA foreign_keys=ON"); err != nil {
+	if err := migrateSchema(db, dbPath); err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("enabling foreign keys: %w", err)
+		return nil, err
 	}
 
-	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("setting busy_timeout: %w", err)
+	return db, nil
+}
+
+// openReadOnlyDB opens a database another cc-top instance writes, without
+// creating or migrating it: its schema must already be current.
+func openReadOnlyDB(dbPath string, t Tuning) (*sql.DB, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("read-only database: %w; start the cc-top instance that writes it first", err)
+	}
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", t.BusyTimeout.Milliseconds()))
+	pragmas.Add("_pragma", "query_only(1)")
+	db, err := sql.Open("sqlite", dbPath+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
 	}
 
-	if err := migrateSchema(db, dbPath); err != nil {
+	var version int
+	if err := db.QueryRow("SELECT version FROM schema_version LIMIT 1").Scan(&version); err != nil {
 		_ = db.Close()
-		return nil, err
+		return nil, fmt.Errorf("reading schema version: %w", err)
+	}
+	if version != currentSchemaVersion {
+		_ = db.Close()
+		return nil, fmt.Errorf("database schema version %d differs from this cc-top version's %d; a read-only instance can't migrate it, so run the same cc-top version as the instance that writes %s",
+			version, currentSchemaVersion, dbPath)
 	}
-
 	return db, nil
 }
 
@@ -91,6 +133,97 @@ func applyMigrations(db *sql.DB, fromVersion int) error {
 		if err := migrateV1ToV2(db); err != nil {
 			return fmt.Errorf("migration v1→v2: %w", err)
 		}
+		fromVersion = 2
+	}
+
+	if fromVersion == 2 {
+		if err := migrateV2ToV3(db); err != nil {
+			return fmt.Errorf("migration v2→v3: %w", err)
+		}
+		fromVersion = 3
+	}
+
+	if fromVersion == 3 {
+		if err := migrateV3ToV4(db); err != nil {
+			return fmt.Errorf("migration v3→v4: %w", err)
+		}
+		fromVersion = 4
+	}
+
+	if fromVersion == 4 {
+		if err := migrateV4ToV5(db); err != nil {
+			return fmt.Errorf("migration v4→v5: %w", err)
+		}
+		fromVersion = 5
+	}
+
+	if fromVersion == 5 {
+		if err := migrateV5ToV6(db); err != nil {
+			return fmt.Errorf("migration v5→v6: %w", err)
+		}
+		fromVersion = 6
+	}
+
+	if fromVersion == 6 {
+		if err := migrateV6ToV7(db); err != nil {
+			return fmt.Errorf("migration v6→v7: %w", err)
+		}
+		fromVersion = 7
+	}
+
+	if fromVersion == 7 {
+		if err := migrateV7ToV8(db); err != nil {
+			return fmt.Errorf("migration v7→v8: %w", err)
+		}
+		fromVersion = 8
+	}
+
+	if fromVersion == 8 {
+		if err := migrateV8ToV9(db); err != nil {
+			return fmt.Errorf("migration v8→v9: %w", err)
+		}
+		fromVersion = 9
+	}
+
+	if fromVersion == 9 {
+		if err := migrateV9ToV10(db); err != nil {
+			return fmt.Errorf("migration v9→v10: %w", err)
+		}
+		fromVersion = 10
+	}
+
+	if fromVersion == 10 {
+		if err := migrateV10ToV11(db); err != nil {
+			return fmt.Errorf("migration v10→v11: %w", err)
+		}
+		fromVersion = 11
+	}
+
+	if fromVersion == 11 {
+		if err := migrateV11ToV12(db); err != nil {
+			return fmt.Errorf("migration v11→v12: %w", err)
+		}
+		fromVersion = 12
+	}
+
+	if fromVersion == 12 {
+		if err := migrateV12ToV13(db); err != nil {
+			return fmt.Errorf("migration v12→v13: %w", err)
+		}
+		fromVersion = 13
+	}
+
+	if fromVersion == 13 {
+		if err := migrateV13ToV14(db); err != nil {
+			return fmt.Errorf("migration v13→v14: %w", err)
+		}
+		fromVersion = 14
+	}
+
+	if fromVersion == 14 {
+		if err := migrateV14ToV15(db); err != nil {
+			return fmt.Errorf("migration v14→v15: %w", err)
+		}
 	}
 
 	return nil
@@ -343,3 +476,386 @@ func migrateV1ToV2(db *sql.DB) error {
 
 	return nil
 }
+
+func migrateV2ToV3(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec("ALTER TABLE daily_stats ADD COLUMN account_breakdown TEXT")
+	if err != nil {
+		return fmt.Errorf("adding daily_stats.account_breakdown: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 3")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV3ToV4(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, col := range []string{"host_name", "service_instance_id", "environment"} {
+		if _, err := tx.Exec("ALTER TABLE sessions ADD COLUMN " + col + " TEXT"); err != nil {
+			return fmt.Errorf("adding sessions.%s: %w", col, err)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 4")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV4ToV5(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// until is empty for an acknowledgment, which lasts until the rule stops
+	// triggering, or the end of a snooze.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS alert_acks (
+			alert_key TEXT PRIMARY KEY,
+			until TEXT NOT NULL DEFAULT '',
+			acked_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating alert_acks table: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 5")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV5ToV6(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// One row per completed local day: the daily projection shown at the end
+	// of the day against what the day actually cost.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS projection_accuracy (
+			date TEXT PRIMARY KEY,
+			projected_cost REAL NOT NULL,
+			actual_cost REAL NOT NULL,
+			recorded_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating projection_accuracy table: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 6")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV6ToV7(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec("ALTER TABLE daily_stats ADD COLUMN project_breakdown TEXT")
+	if err != nil {
+		return fmt.Errorf("adding daily_stats.project_breakdown: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 7")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV7ToV8(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec("ALTER TABLE daily_stats ADD COLUMN context_usage TEXT")
+	if err != nil {
+		return fmt.Errorf("adding daily_stats.context_usage: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 8")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV8ToV9(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec("ALTER TABLE alert_history ADD COLUMN note TEXT DEFAULT ''")
+	if err != nil {
+		return fmt.Errorf("adding alert_history.note: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 9")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV9ToV10(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// user_name labels sessions pushed to `cc-top serve` by another instance.
+	_, err = tx.Exec("ALTER TABLE sessions ADD COLUMN user_name TEXT")
+	if err != nil {
+		return fmt.Errorf("adding sessions.user_name: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 10")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV10ToV11(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// One row per all-time record, such as the most expensive day.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS records (
+			name TEXT PRIMARY KEY,
+			value REAL NOT NULL,
+			session_id TEXT NOT NULL DEFAULT '',
+			detail TEXT NOT NULL DEFAULT '',
+			achieved_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating records table: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 11")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV11ToV12(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Display-ready events spilled from the TUI's event buffer, so the
+	// Events panel can scroll back past it.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS formatted_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			formatted TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			success INTEGER,
+			attributes TEXT
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating formatted_events table: %w", err)
+	}
+
+	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS idx_formatted_events_session ON formatted_events(session_id)")
+	if err != nil {
+		return fmt.Errorf("creating formatted_events session index: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 12")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV12ToV13(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Rule-wide silences created with cc-top silence; rule is "*" for all
+	// rules.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS alert_silences (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rule TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			until TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating alert_silences table: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 13")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV13ToV14(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Tags and a note attached to a session from the TUI; tags is a
+	// comma-separated list.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS session_tags (
+			session_id TEXT PRIMARY KEY,
+			tags TEXT NOT NULL DEFAULT '',
+			note TEXT NOT NULL DEFAULT '',
+			updated_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating session_tags table: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 14")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV14ToV15(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The single row names the cc-top instance that runs maintenance on a
+	// database shared by several instances, until its lease expires.
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS maintenance_lease (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			holder TEXT NOT NULL,
+			expires_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating maintenance_lease table: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE schema_version SET version = 15")
+	if err != nil {
+		return fmt.Errorf("updating schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
@@ -22,11 +22,11 @@ func TestSchema_CreateFresh(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read schema_version: %v", err)
 	}
-	if version != 2 {
-		t.Errorf("schema version: want 2, got %d", version)
+	if version != currentSchemaVersion {
+		t.Errorf("schema version: want %d, got %d", currentSchemaVersion, version)
 	}
 
-	tables := []string{"schema_version", "sessions", "metrics", "events", "counter_state", "daily_summaries", "daily_stats", "burn_rate_snapshots", "alert_history"}
+	tables := []string{"schema_version", "sessions", "metrics", "events", "counter_state", "daily_summaries", "daily_stats", "burn_rate_snapshots", "alert_history", "alert_acks", "projection_accuracy", "alert_silences"}
 	for _, tableName := range tables {
 		var name string
 		err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", tableName).Scan(&name)
@@ -77,8 +77,8 @@ func TestSchema_MigrateV0ToV1(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read schema_version after migration: %v", err)
 	}
-	if version != 2 {
-		t.Errorf("schema version after migration: want 2, got %d", version)
+	if version != currentSchemaVersion {
+		t.Errorf("schema version after migration: want %d, got %d", currentSchemaVersion, version)
 	}
 
 	tables := []string{"sessions", "metrics", "events", "counter_state", "daily_summaries", "daily_stats", "burn_rate_snapshots", "alert_history"}
@@ -119,8 +119,8 @@ func TestSchema_NoMigrationAtCurrentVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read schema_version: %v", err)
 	}
-	if version != 2 {
-		t.Errorf("schema version: want 2, got %d", version)
+	if version != currentSchemaVersion {
+		t.Errorf("schema version: want %d, got %d", currentSchemaVersion, version)
 	}
 
 	var sessionID string
@@ -312,7 +312,7 @@ func TestMigrateV1ToV2_CreatesIndexes(t *testing.T) {
 	}
 }
 
-func TestMigrateV1ToV2_SetsVersionTo2(t *testing.T) {
+func TestMigrateV1_SetsCurrentVersion(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := createV1Database(t, tmpDir)
 
@@ -327,8 +327,8 @@ func TestMigrateV1ToV2_SetsVersionTo2(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read schema_version: %v", err)
 	}
-	if version != 2 {
-		t.Errorf("schema version: want 2, got %d", version)
+	if version != currentSchemaVersion {
+		t.Errorf("schema version: want %d, got %d", currentSchemaVersion, version)
 	}
 }
 
@@ -419,8 +419,8 @@ func TestFreshInstall_CreatesFullSchema(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read schema_version: %v", err)
 	}
-	if version != 2 {
-		t.Errorf("schema version: want 2, got %d", version)
+	if version != currentSchemaVersion {
+		t.Errorf("schema version: want %d, got %d", currentSchemaVersion, version)
 	}
 }
 
@@ -452,8 +452,8 @@ func TestMigrateV2ToV2_NoOp(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read schema_version: %v", err)
 	}
-	if version != 2 {
-		t.Errorf("schema version: want 2, got %d", version)
+	if version != currentSchemaVersion {
+		t.Errorf("schema version: want %d, got %d", currentSchemaVersion, version)
 	}
 
 	// Data preserved
@@ -517,3 +517,40 @@ func TestMigrateV1ToV2_RollbackOnPartialFailure(t *testing.T) {
 
 	_ = db.Close()
 }
+
+func TestMigrateV2ToV3_AddsAccountBreakdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := createV1Database(t, tmpDir)
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := migrateV1ToV2(db); err != nil {
+		t.Fatalf("migrateV1ToV2 failed: %v", err)
+	}
+	_, err = db.Exec("INSERT INTO daily_stats (date, total_cost) VALUES (?, ?)", "2026-02-20", 7.5)
+	if err != nil {
+		t.Fatalf("insert daily_stats: %v", err)
+	}
+	_ = db.Close()
+
+	db, err = OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var cost float64
+	var accounts sql.NullString
+	err = db.QueryRow("SELECT total_cost, account_breakdown FROM daily_stats WHERE date = ?", "2026-02-20").Scan(&cost, &accounts)
+	if err != nil {
+		t.Fatalf("reading migrated daily_stats: %v", err)
+	}
+	if cost != 7.5 {
+		t.Errorf("total_cost: want 7.5, got %f", cost)
+	}
+	if accounts.Valid {
+		t.Errorf("account_breakdown should be NULL for pre-v3 rows, got %q", accounts.String)
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+)
+
+// LoadAlertSilences implements the alerts.SilenceStore interface.
+func (s *SQLiteStore) LoadAlertSilences(now time.Time) []alerts.Silence {
+	silences, err := querySilences(s.db, now)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+	return silences
+}
+
+// AddSilence stores a silence in the database at dbPath and returns its ID.
+// A running cc-top picks it up within seconds.
+func AddSilence(dbPath string, sl alerts.Silence) (int64, error) {
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = db.Close() }()
+
+	res, err := db.Exec("INSERT INTO alert_silences (rule, reason, created_at, until) VALUES (?, ?, ?, ?)",
+		sl.Rule, sl.Reason, sl.CreatedAt.UTC().Format(time.RFC3339), sl.Until.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("inserting silence: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// ListSilences returns the silences in the database at dbPath that haven't
+// ended at now, soonest ending first.
+func ListSilences(dbPath string, now time.Time) ([]alerts.Silence, error) {
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	return querySilences(db, now)
+}
+
+// EndSilence ends the silence with id at now. It reports whether the
+// silence was still in effect.
+func EndSilence(dbPath string, id int64, now time.Time) (bool, error) {
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = db.Close() }()
+
+	ts := now.UTC().Format(time.RFC3339)
+	res, err := db.Exec("UPDATE alert_silences SET until = ? WHERE id = ? AND until > ?", ts, id, ts)
+	if err != nil {
+		return false, fmt.Errorf("ending silence: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func querySilences(db *sql.DB, now time.Time) ([]alerts.Silence, error) {
+	rows, err := db.Query("SELECT id, rule, reason, created_at, until FROM alert_silences WHERE until > ? ORDER BY until, id",
+		now.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying alert silences: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []alerts.Silence
+	for rows.Next() {
+		var sl alerts.Silence
+		var created, until string
+		if err := rows.Scan(&sl.ID, &sl.Rule, &sl.Reason, &created, &until); err != nil {
+			return result, fmt.Errorf("scanning alert silence: %w", err)
+		}
+		sl.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		if sl.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			continue
+		}
+		result = append(result, sl)
+	}
+	return result, rows.Err()
+}
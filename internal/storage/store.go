@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/nixlim/cc-top/internal/alerts"
 	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/events"
 	"github.com/nixlim/cc-top/internal/state"
 	"github.com/nixlim/cc-top/internal/stats"
 )
@@ -20,6 +22,42 @@ const (
 	flushInterval    = 100 * time.Millisecond
 )
 
+// Tuning holds the SQLite pragmas and write batching of a SQLiteStore.
+// Writes are queued in a ChannelSize buffer, dropped when it is full, and
+// committed BatchSize at a time or every FlushInterval. A ReadOnly store
+// reads a database another cc-top instance writes and writes nothing.
+type Tuning struct {
+	JournalMode   string // empty keeps the database's
+	Synchronous   string // empty keeps SQLite's default
+	BusyTimeout   time.Duration
+	BatchSize     int
+	FlushInterval time.Duration
+	ChannelSize   int
+	ReadOnly      bool
+}
+
+// DefaultTuning returns the tuning used unless [storage] overrides it.
+func DefaultTuning() Tuning {
+	return Tuning{
+		JournalMode:   "wal",
+		Synchronous:   "full",
+		BusyTimeout:   5 * time.Second,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		ChannelSize:   writeChannelSize,
+	}
+}
+
+// StoreOption configures a SQLiteStore.
+type StoreOption func(*Tuning)
+
+// WithTuning sets the store's SQLite pragmas and write batching.
+func WithTuning(t Tuning) StoreOption {
+	return func(dst *Tuning) {
+		*dst = t
+	}
+}
+
 type sessionSnapshot struct {
 	Model               string
 	Terminal            string
@@ -48,6 +86,11 @@ type writeOp struct {
 	dailyStats *dailyStatsRow
 	burnRate   *burnRateSnapshotRow
 	alert      *alertHistoryRow
+	ack        *alertAckRow
+	note       *alertNoteRow
+	tags       *sessionTagRow
+	record     *recordRow
+	formatted  *events.FormattedEvent
 }
 
 type SQLiteStore struct {
@@ -59,20 +102,54 @@ type SQLiteStore struct {
 	closed          atomic.Bool
 	cancelMaint     context.CancelFunc
 	maintenanceDone chan struct{}
+	maxDBBytes      atomic.Int64 // 0 means no size cap
+	maintMu         sync.Mutex
+	maint           maintenanceSchedule
+	readOnly        bool
+	instanceID      string
+	leader          atomic.Bool // holds the maintenance lease
+	batchSize       int
+	flushInterval   time.Duration
+	writer          writerMetrics
 
 	statsSnapshotFn func() stats.DashboardStats
 	burnSnapshotFn  func() burnrate.BurnRate
 	burnRateTicker  *time.Ticker
 	burnRateDone    chan struct{}
 	burnRateStop    chan struct{}
+
+	autoMu         sync.RWMutex
+	autoEnabled    bool
+	autoPercentile float64
+	autoThresholds alerts.AutoThresholds
+	autoValid      bool
+
+	baselineMu      sync.RWMutex
+	baselineEnabled bool
+	baseline        alerts.SpendBaseline
+	baselineValid   bool
+
+	// tags caches the session_tags table, see SessionTags.
+	tagsMu sync.RWMutex
+	tags   map[string]sessionTagRow
 }
 
-func NewSQLiteStore(dbPath string, retentionDays, summaryRetentionDays int) (*SQLiteStore, error) {
-	return newSQLiteStoreWithChannelSize(dbPath, writeChannelSize, retentionDays, summaryRetentionDays)
+func NewSQLiteStore(dbPath string, retentionDays, summaryRetentionDays int, opts ...StoreOption) (*SQLiteStore, error) {
+	t := DefaultTuning()
+	for _, opt := range opts {
+		opt(&t)
+	}
+	return newSQLiteStore(dbPath, t, retentionDays, summaryRetentionDays)
 }
 
 func newSQLiteStoreWithChannelSize(dbPath string, chanSize int, retentionDays, summaryRetentionDays int) (*SQLiteStore, error) {
-	db, err := OpenDB(dbPath)
+	t := DefaultTuning()
+	t.ChannelSize = chanSize
+	return newSQLiteStore(dbPath, t, retentionDays, summaryRetentionDays)
+}
+
+func newSQLiteStore(dbPath string, t Tuning, retentionDays, summaryRetentionDays int) (*SQLiteStore, error) {
+	db, err := openDB(dbPath, t)
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
@@ -82,10 +159,14 @@ func newSQLiteStoreWithChannelSize(dbPath string, chanSize int, retentionDays, s
 	store := &SQLiteStore{
 		MemoryStore:     state.NewMemoryStore(),
 		db:              db,
-		writeChan:       make(chan writeOp, chanSize),
+		writeChan:       make(chan writeOp, t.ChannelSize),
+		batchSize:       t.BatchSize,
+		flushInterval:   t.FlushInterval,
 		doneChan:        make(chan struct{}),
 		cancelMaint:     cancel,
 		maintenanceDone: make(chan struct{}),
+		readOnly:        t.ReadOnly,
+		instanceID:      newInstanceID(),
 	}
 
 	if err := store.recoverSessions(); err != nil {
@@ -93,8 +174,16 @@ func newSQLiteStoreWithChannelSize(dbPath string, chanSize int, retentionDays, s
 		_ = db.Close()
 		return nil, fmt.Errorf("recovering sessions: %w", err)
 	}
+	if err := store.loadSessionTags(); err != nil {
+		cancel()
+		_ = db.Close()
+		return nil, err
+	}
 
 	go store.writerLoop()
+	if !store.readOnly && !store.checkLease(time.Now()) {
+		log.Printf("INFO: another cc-top instance runs maintenance on %s; this one takes over if it stops", dbPath)
+	}
 	store.startMaintenance(ctx, retentionDays, summaryRetentionDays)
 
 	return store, nil
@@ -212,7 +301,7 @@ func (s *SQLiteStore) SetBurnRateSnapshotFunc(fn func() burnrate.BurnRate) {
 // StartBurnRateSnapshots starts a 5-minute ticker that captures burn rate
 // snapshots. If the burn rate callback is nil, this method returns immediately.
 func (s *SQLiteStore) StartBurnRateSnapshots() {
-	if s.burnSnapshotFn == nil {
+	if s.burnSnapshotFn == nil || s.readOnly {
 		return
 	}
 
@@ -228,6 +317,9 @@ func (s *SQLiteStore) StartBurnRateSnapshots() {
 			case <-stopCh:
 				return
 			case <-s.burnRateTicker.C:
+				if !s.leader.Load() {
+					continue
+				}
 				br := s.burnSnapshotFn()
 				s.WriteBurnRateSnapshot(br)
 			}
@@ -236,7 +328,7 @@ func (s *SQLiteStore) StartBurnRateSnapshots() {
 }
 
 func (s *SQLiteStore) sendWrite(op writeOp) {
-	if s.closed.Load() {
+	if s.closed.Load() || s.readOnly {
 		return
 	}
 	defer func() { _ = recover() }()
@@ -252,6 +344,9 @@ func (s *SQLiteStore) sendWrite(op writeOp) {
 // using a blocking send with a 1s timeout. Used during shutdown to
 // persist final snapshots before the channel is closed.
 func (s *SQLiteStore) sendFinalWrite(op writeOp) {
+	if s.readOnly {
+		return
+	}
 	defer func() { _ = recover() }()
 	select {
 	case s.writeChan <- op:
@@ -277,8 +372,10 @@ func (s *SQLiteStore) Close() error {
 		}
 	}
 
-	// Step 2: Final burn rate snapshot via sendFinalWrite.
-	if s.burnSnapshotFn != nil {
+	// Step 2: Final burn rate snapshot via sendFinalWrite. Like the other
+	// snapshots, only the maintenance lease holder records it.
+	leader := s.leader.Load()
+	if s.burnSnapshotFn != nil && leader {
 		br := s.burnSnapshotFn()
 		row := &burnRateSnapshotRow{
 			Timestamp:         time.Now().UTC().Format(time.RFC3339),
@@ -294,7 +391,7 @@ func (s *SQLiteStore) Close() error {
 	}
 
 	// Step 3: Final stats snapshot via sendFinalWrite.
-	if s.statsSnapshotFn != nil {
+	if s.statsSnapshotFn != nil && leader {
 		ds := s.statsSnapshotFn()
 		today := time.Now().Format("2006-01-02")
 		s.sendFinalWrite(writeOp{opType: "dailyStats", dailyStats: buildDailyStatsRow(today, ds)})
@@ -321,9 +418,12 @@ func (s *SQLiteStore) Close() error {
 		log.Printf("ERROR: failed to drain writes within 10s, data may be lost")
 	}
 
-	// Step 8: Daily aggregation.
-	if err := s.runDailyAggregation(); err != nil {
-		log.Printf("ERROR: failed to run final aggregation: %v", err)
+	// Step 8: Daily aggregation, then hand maintenance to another instance.
+	if leader {
+		if err := s.runDailyAggregation(); err != nil {
+			log.Printf("ERROR: failed to run final aggregation: %v", err)
+		}
+		s.releaseLease()
 	}
 
 	// Step 9: Close database.
@@ -333,8 +433,8 @@ func (s *SQLiteStore) Close() error {
 func (s *SQLiteStore) writerLoop() {
 	defer close(s.doneChan)
 
-	batch := make([]writeOp, 0, batchSize)
-	flushTimer := time.NewTimer(flushInterval)
+	batch := make([]writeOp, 0, s.batchSize)
+	flushTimer := time.NewTimer(s.flushInterval)
 	defer flushTimer.Stop()
 
 	for {
@@ -349,10 +449,10 @@ func (s *SQLiteStore) writerLoop() {
 
 			batch = append(batch, op)
 
-			if len(batch) >= batchSize {
+			if len(batch) >= s.batchSize {
 				s.flushBatch(batch)
 				batch = batch[:0]
-				flushTimer.Reset(flushInterval)
+				flushTimer.Reset(s.flushInterval)
 			}
 
 		case <-flushTimer.C:
@@ -360,12 +460,18 @@ func (s *SQLiteStore) writerLoop() {
 				s.flushBatch(batch)
 				batch = batch[:0]
 			}
-			flushTimer.Reset(flushInterval)
+			flushTimer.Reset(s.flushInterval)
 		}
 	}
 }
 
 func (s *SQLiteStore) flushBatch(batch []writeOp) {
+	start := time.Now()
+	defer func() {
+		s.writer.recordFlush(len(batch), time.Since(start))
+		s.writer.sampleQueue(len(s.writeChan), cap(s.writeChan), time.Now())
+	}()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		log.Printf("ERROR: failed to begin transaction: %v", err)
@@ -495,6 +601,9 @@ func buildDailyStatsRow(date string, ds stats.DashboardStats) *dailyStatsRow {
 		LanguageBreakdown: langBreakdown,
 		DecisionSources:   decSources,
 		MCPToolUsage:      mcpTools,
+		AccountBreakdown:  ds.AccountBreakdown,
+		ProjectBreakdown:  ds.ProjectBreakdown,
+		ContextUsage:      ds.ContextUsage,
 	}
 }
 
@@ -555,6 +664,16 @@ func (s *SQLiteStore) executeOp(tx *sql.Tx, op writeOp) error {
 		return s.writeBurnRateSnapshot(tx, op.burnRate)
 	case "alertHistory":
 		return s.writeAlertHistory(tx, op.alert)
+	case "alertAck":
+		return s.writeAlertAck(tx, op.ack)
+	case "alertNote":
+		return s.writeAlertNote(tx, op.note)
+	case "sessionTags":
+		return s.writeSessionTags(tx, op.tags)
+	case "record":
+		return s.writeRecord(tx, op.record)
+	case "formattedEvent":
+		return s.writeFormattedEvent(tx, op.formatted)
 	default:
 		return fmt.Errorf("unknown op type: %s", op.opType)
 	}
@@ -396,6 +396,64 @@ func TestSQLiteStore_WriteErrorDoesNotCrash(t *testing.T) {
 	}
 }
 
+func TestSQLiteStore_Tuning(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewSQLiteStore(dbPath, 7, 90, WithTuning(Tuning{
+		JournalMode:   "delete",
+		Synchronous:   "normal",
+		BusyTimeout:   2 * time.Second,
+		BatchSize:     5,
+		FlushInterval: 20 * time.Millisecond,
+		ChannelSize:   10,
+	}))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	if cap(store.writeChan) != 10 || store.batchSize != 5 || store.flushInterval != 20*time.Millisecond {
+		t.Errorf("writer: channel %d, batch %d, flush %v", cap(store.writeChan), store.batchSize, store.flushInterval)
+	}
+
+	// Hold connections so the pool opens several; each must be tuned.
+	var conns []*sql.Conn
+	for range 3 {
+		conn, err := store.db.Conn(t.Context())
+		if err != nil {
+			t.Fatalf("Conn: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	for i, conn := range conns {
+		var journal string
+		var synchronous, busy int
+		if err := conn.QueryRowContext(t.Context(), "PRAGMA journal_mode").Scan(&journal); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.QueryRowContext(t.Context(), "PRAGMA synchronous").Scan(&synchronous); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.QueryRowContext(t.Context(), "PRAGMA busy_timeout").Scan(&busy); err != nil {
+			t.Fatal(err)
+		}
+		if journal != "delete" || synchronous != 1 || busy != 2000 {
+			t.Errorf("connection %d: journal_mode %s, synchronous %d, busy_timeout %d", i, journal, synchronous, busy)
+		}
+		_ = conn.Close()
+	}
+	_ = store.Close()
+
+	// Other commands open the database without switching it back to WAL.
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	var journal string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&journal); err != nil || journal != "delete" {
+		t.Errorf("OpenDB changed the journal mode to %q (%v)", journal, err)
+	}
+}
+
 func TestSQLiteStore_ChannelOverflow_IncrementsCounter(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
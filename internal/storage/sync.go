@@ -0,0 +1,403 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MergeResult counts the rows copied from a peer database by MergeDB.
+type MergeResult struct {
+	Sessions          int64
+	Metrics           int64
+	Events            int64
+	DailySummaries    int64
+	DailyStats        int64
+	BurnRateSnapshots int64
+	Alerts            int64
+	SessionTags       int64
+	// SkippedDays are dates whose daily stats exist in both databases; the
+	// local row is kept.
+	SkippedDays []string
+}
+
+// mergeStatements copy the attached "peer" database into "main". Rows are
+// deduplicated by session ID and timestamps, so merging the same peer twice
+// copies nothing the second time.
+var mergeStatements = []struct {
+	name  string
+	query string
+	count func(*MergeResult) *int64
+}{
+	{
+		// Peer sessions cannot be tracked locally: they are stored without a
+		// PID (so local process exits never match them) and marked exited.
+		// Sessions known to both sides keep the most recently active row.
+		name: "sessions",
+		query: `
+			INSERT INTO main.sessions (
+				session_id, pid, terminal, cwd, model, total_cost, total_tokens,
+				cache_read_tokens, cache_creation_tokens, active_time_seconds,
+				started_at, last_event_at, exited, fast_mode, org_id, user_uuid,
+				service_version, os_type, os_version, host_arch, host_name,
+				service_instance_id, environment, user_name
+			)
+			SELECT session_id, NULL, terminal, cwd, model, total_cost, total_tokens,
+				cache_read_tokens, cache_creation_tokens, active_time_seconds,
+				started_at, last_event_at, 1, fast_mode, org_id, user_uuid,
+				service_version, os_type, os_version, host_arch, host_name,
+				service_instance_id, environment, user_name
+			FROM peer.sessions WHERE true
+			ON CONFLICT(session_id) DO UPDATE SET
+				model=excluded.model,
+				total_cost=excluded.total_cost,
+				total_tokens=excluded.total_tokens,
+				cache_read_tokens=excluded.cache_read_tokens,
+				cache_creation_tokens=excluded.cache_creation_tokens,
+				active_time_seconds=excluded.active_time_seconds,
+				last_event_at=excluded.last_event_at,
+				host_name=excluded.host_name,
+				service_instance_id=excluded.service_instance_id,
+				environment=excluded.environment,
+				user_name=excluded.user_name
+			WHERE main.sessions.last_event_at IS NULL
+				OR datetime(excluded.last_event_at) > datetime(main.sessions.last_event_at)
+		`,
+		count: func(r *MergeResult) *int64 { return &r.Sessions },
+	},
+	{
+		name: "metrics",
+		query: `
+			INSERT INTO main.metrics (session_id, name, value, timestamp, attributes)
+			SELECT p.session_id, p.name, p.value, p.timestamp, p.attributes
+			FROM peer.metrics p
+			WHERE NOT EXISTS (
+				SELECT 1 FROM main.metrics m
+				WHERE m.session_id = p.session_id AND m.name = p.name
+				  AND m.timestamp = p.timestamp AND m.value = p.value
+				  AND m.attributes IS p.attributes
+			)
+		`,
+		count: func(r *MergeResult) *int64 { return &r.Metrics },
+	},
+	{
+		name: "events",
+		query: `
+			INSERT INTO main.events (session_id, name, timestamp, sequence, attributes)
+			SELECT p.session_id, p.name, p.timestamp, p.sequence, p.attributes
+			FROM peer.events p
+			WHERE NOT EXISTS (
+				SELECT 1 FROM main.events e
+				WHERE e.session_id = p.session_id AND e.name = p.name
+				  AND e.timestamp = p.timestamp AND e.sequence IS p.sequence
+				  AND e.attributes IS p.attributes
+			)
+		`,
+		count: func(r *MergeResult) *int64 { return &r.Events },
+	},
+	{
+		name: "counter_state",
+		query: `
+			INSERT OR IGNORE INTO main.counter_state (session_id, metric_key, value)
+			SELECT session_id, metric_key, value FROM peer.counter_state
+		`,
+	},
+	{
+		// Per-session summaries only grow during a day, so the larger value
+		// wins.
+		name: "daily_summaries",
+		query: `
+			INSERT INTO main.daily_summaries (session_id, date, total_cost, total_tokens, api_requests, api_errors, active_seconds)
+			SELECT session_id, date, total_cost, total_tokens, api_requests, api_errors, active_seconds
+			FROM peer.daily_summaries WHERE true
+			ON CONFLICT(session_id, date) DO UPDATE SET
+				total_cost=max(total_cost, excluded.total_cost),
+				total_tokens=max(total_tokens, excluded.total_tokens),
+				api_requests=max(api_requests, excluded.api_requests),
+				api_errors=max(api_errors, excluded.api_errors),
+				active_seconds=max(active_seconds, excluded.active_seconds)
+			WHERE excluded.total_cost > total_cost OR excluded.total_tokens > total_tokens
+				OR excluded.api_requests > api_requests OR excluded.active_seconds > active_seconds
+		`,
+		count: func(r *MergeResult) *int64 { return &r.DailySummaries },
+	},
+	{
+		name: "daily_stats",
+		query: `
+			INSERT OR IGNORE INTO main.daily_stats (
+				date, total_cost, token_input, token_output, token_cache_read, token_cache_write,
+				session_count, api_requests, api_errors, lines_added, lines_removed,
+				commits, prs_opened, cache_efficiency, cache_savings_usd, error_rate, retry_rate,
+				avg_api_latency_ms, latency_p50_ms, latency_p95_ms, latency_p99_ms,
+				model_breakdown, top_tools, error_categories, language_breakdown,
+				decision_sources, mcp_tool_usage, account_breakdown, project_breakdown,
+				context_usage
+			)
+			SELECT
+				date, total_cost, token_input, token_output, token_cache_read, token_cache_write,
+				session_count, api_requests, api_errors, lines_added, lines_removed,
+				commits, prs_opened, cache_efficiency, cache_savings_usd, error_rate, retry_rate,
+				avg_api_latency_ms, latency_p50_ms, latency_p95_ms, latency_p99_ms,
+				model_breakdown, top_tools, error_categories, language_breakdown,
+				decision_sources, mcp_tool_usage, account_breakdown, project_breakdown,
+				context_usage
+			FROM peer.daily_stats
+		`,
+		count: func(r *MergeResult) *int64 { return &r.DailyStats },
+	},
+	{
+		// Like daily stats, a day recorded on both sides keeps the local row.
+		name: "projection_accuracy",
+		query: `
+			INSERT OR IGNORE INTO main.projection_accuracy (date, projected_cost, actual_cost, recorded_at)
+			SELECT date, projected_cost, actual_cost, recorded_at FROM peer.projection_accuracy
+		`,
+	},
+	{
+		name: "burn_rate_snapshots",
+		query: `
+			INSERT INTO main.burn_rate_snapshots (
+				timestamp, total_cost, hourly_rate, trend, token_velocity,
+				daily_projection, monthly_projection, per_model
+			)
+			SELECT p.timestamp, p.total_cost, p.hourly_rate, p.trend, p.token_velocity,
+				p.daily_projection, p.monthly_projection, p.per_model
+			FROM peer.burn_rate_snapshots p
+			WHERE NOT EXISTS (
+				SELECT 1 FROM main.burn_rate_snapshots b WHERE b.timestamp = p.timestamp
+			)
+		`,
+		count: func(r *MergeResult) *int64 { return &r.BurnRateSnapshots },
+	},
+	{
+		name: "alert_history",
+		query: `
+			INSERT INTO main.alert_history (rule, severity, message, session_id, fired_at, note)
+			SELECT p.rule, p.severity, p.message, p.session_id, p.fired_at, p.note
+			FROM peer.alert_history p
+			WHERE NOT EXISTS (
+				SELECT 1 FROM main.alert_history a
+				WHERE a.rule = p.rule AND a.session_id IS p.session_id
+				  AND a.fired_at = p.fired_at AND a.message = p.message
+			)
+		`,
+		count: func(r *MergeResult) *int64 { return &r.Alerts },
+	},
+	{
+		// The most recently edited tags and note of a session win.
+		name: "session_tags",
+		query: `
+			INSERT INTO main.session_tags (session_id, tags, note, updated_at)
+			SELECT session_id, tags, note, updated_at FROM peer.session_tags WHERE true
+			ON CONFLICT(session_id) DO UPDATE SET
+				tags=excluded.tags,
+				note=excluded.note,
+				updated_at=excluded.updated_at
+			WHERE datetime(excluded.updated_at) > datetime(main.session_tags.updated_at)
+		`,
+		count: func(r *MergeResult) *int64 { return &r.SessionTags },
+	},
+}
+
+// MergeDB copies the history in the database at peerPath into the one at
+// dbPath, skipping rows dbPath already has. The local database is created or
+// migrated as needed; the peer is only read and must be at the current
+// schema version. Merging is safe while a cc-top instance is using dbPath,
+// but that instance only shows the merged sessions after a restart.
+func MergeDB(dbPath, peerPath string) (MergeResult, error) {
+	if err := checkPeerSchema(peerPath); err != nil {
+		return MergeResult{}, err
+	}
+	return mergeDB(dbPath, peerPath, false)
+}
+
+// ImportDB merges the history in the database at otherPath into the one at
+// dbPath like MergeDB, but also accepts a database from an older cc-top
+// version: a copy of it is migrated first, leaving the original untouched.
+// With dryRun the local database is only read: the merge runs against a
+// scratch copy of it and only the counts are reported.
+func ImportDB(dbPath, otherPath string, dryRun bool) (MergeResult, error) {
+	if _, err := os.Stat(otherPath); err != nil {
+		return MergeResult{}, fmt.Errorf("database to import: %w", err)
+	}
+	if same, err := samePath(dbPath, otherPath); err != nil {
+		return MergeResult{}, err
+	} else if same {
+		return MergeResult{}, fmt.Errorf("%s is the local database", otherPath)
+	}
+
+	version, err := peerSchemaVersion(otherPath)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	switch {
+	case version > currentSchemaVersion:
+		return MergeResult{}, fmt.Errorf("%s has schema version %d, newer than this cc-top version supports (%d); upgrade cc-top", otherPath, version, currentSchemaVersion)
+	case version < currentSchemaVersion:
+		tmpDir, err := os.MkdirTemp("", "cc-top-import-*")
+		if err != nil {
+			return MergeResult{}, fmt.Errorf("creating temp directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		migrated := filepath.Join(tmpDir, "import.db")
+		if err := copyAndMigrate(otherPath, migrated); err != nil {
+			return MergeResult{}, err
+		}
+		otherPath = migrated
+	}
+	return mergeDB(dbPath, otherPath, dryRun)
+}
+
+// copyAndMigrate writes a consistent copy of the database at src to dst,
+// including changes still in its WAL, and migrates the copy to the current
+// schema.
+func copyAndMigrate(src, dst string) error {
+	db, err := sql.Open("sqlite", "file:"+src+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", src, err)
+	}
+	_, err = db.Exec("VACUUM INTO ?", dst)
+	_ = db.Close()
+	if err != nil {
+		return fmt.Errorf("copying %s: %w", src, err)
+	}
+
+	migrated, err := OpenDB(dst)
+	if err != nil {
+		return fmt.Errorf("migrating a copy of %s: %w", src, err)
+	}
+	return migrated.Close()
+}
+
+// samePath reports whether a and b name the same existing file. A missing a
+// is not the same as anything.
+func samePath(a, b string) (bool, error) {
+	ai, err := os.Stat(a)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(ai, bi), nil
+}
+
+func mergeDB(dbPath, peerPath string, dryRun bool) (MergeResult, error) {
+	var result MergeResult
+
+	if dryRun {
+		tmpDir, err := os.MkdirTemp("", "cc-top-dry-run-*")
+		if err != nil {
+			return result, fmt.Errorf("creating temp directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		scratch := filepath.Join(tmpDir, "local.db")
+		if _, err := os.Stat(dbPath); err == nil {
+			if err := copyAndMigrate(dbPath, scratch); err != nil {
+				return result, err
+			}
+		} else if !os.IsNotExist(err) {
+			return result, err
+		}
+		dbPath = scratch
+	}
+
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		return result, err
+	}
+	defer func() { _ = db.Close() }()
+
+	// ATTACH applies per connection, so pin the merge to one.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("ATTACH DATABASE ? AS peer", peerPath); err != nil {
+		return result, fmt.Errorf("attaching peer database: %w", err)
+	}
+	defer func() { _, _ = db.Exec("DETACH DATABASE peer") }()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.Query(`
+		SELECT p.date FROM peer.daily_stats p
+		JOIN main.daily_stats m ON m.date = p.date
+		ORDER BY p.date
+	`)
+	if err != nil {
+		return result, fmt.Errorf("comparing daily stats: %w", err)
+	}
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			_ = rows.Close()
+			return result, fmt.Errorf("scanning daily stats date: %w", err)
+		}
+		result.SkippedDays = append(result.SkippedDays, date)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return result, fmt.Errorf("comparing daily stats: %w", err)
+	}
+
+	for _, stmt := range mergeStatements {
+		res, err := tx.Exec(stmt.query)
+		if err != nil {
+			return result, fmt.Errorf("merging %s: %w", stmt.name, err)
+		}
+		if stmt.count != nil {
+			n, err := res.RowsAffected()
+			if err != nil {
+				return result, fmt.Errorf("merging %s: %w", stmt.name, err)
+			}
+			*stmt.count(&result) = n
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("committing merge: %w", err)
+	}
+	return result, nil
+}
+
+// checkPeerSchema verifies that the peer database exists and uses the
+// current schema version, without modifying it.
+func checkPeerSchema(peerPath string) error {
+	if _, err := os.Stat(peerPath); err != nil {
+		return fmt.Errorf("peer database: %w", err)
+	}
+	version, err := peerSchemaVersion(peerPath)
+	if err != nil {
+		return err
+	}
+	if version != currentSchemaVersion {
+		return fmt.Errorf("peer database schema version %d differs from this cc-top version (%d); run the same cc-top version on both machines", version, currentSchemaVersion)
+	}
+	return nil
+}
+
+// peerSchemaVersion reads the schema version of another database without
+// modifying it.
+func peerSchemaVersion(peerPath string) (int, error) {
+	db, err := sql.Open("sqlite", "file:"+peerPath+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("opening peer database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var version int
+	if err := db.QueryRow("SELECT version FROM schema_version LIMIT 1").Scan(&version); err != nil {
+		return 0, fmt.Errorf("peer %s is not a cc-top database: %w", peerPath, err)
+	}
+	return version, nil
+}
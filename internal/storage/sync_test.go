@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func seedSyncDB(t *testing.T, path string, stmts ...string) {
+	t.Helper()
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatalf("OpenDB(%s): %v", path, err)
+	}
+	defer func() { _ = db.Close() }()
+	for _, q := range stmts {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seeding %s: %v\n%s", path, err, q)
+		}
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestMergeDB(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "desktop.db")
+	peer := filepath.Join(dir, "laptop.db")
+
+	seedSyncDB(t, local,
+		`INSERT INTO sessions (session_id, pid, total_cost, last_event_at, exited) VALUES ('desk-1', 100, 1.0, '2026-03-01T10:00:00Z', 0)`,
+		`INSERT INTO sessions (session_id, total_cost, last_event_at) VALUES ('shared', 2.0, '2026-03-01T09:00:00Z')`,
+		`INSERT INTO metrics (session_id, name, value, timestamp, attributes) VALUES ('shared', 'claude_code.cost.usage', 2.0, '2026-03-01T09:00:00Z', '{"model":"opus"}')`,
+		`INSERT INTO daily_stats (date, total_cost) VALUES ('2026-03-01', 3.0)`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('CostSurge', 'warning', 'surge', '', '2026-03-01T09:30:00Z')`,
+	)
+	seedSyncDB(t, peer,
+		`INSERT INTO sessions (session_id, pid, total_cost, last_event_at, exited, host_name, service_instance_id, environment, user_name)
+			VALUES ('lap-1', 100, 4.0, '2026-03-02T10:00:00Z', 0, 'laptop', 'inst-1', 'dev', 'sam')`,
+		`INSERT INTO sessions (session_id, total_cost, last_event_at) VALUES ('shared', 2.5, '2026-03-01T11:00:00Z')`,
+		`INSERT INTO metrics (session_id, name, value, timestamp, attributes) VALUES ('shared', 'claude_code.cost.usage', 2.0, '2026-03-01T09:00:00Z', '{"model":"opus"}')`,
+		`INSERT INTO metrics (session_id, name, value, timestamp, attributes) VALUES ('lap-1', 'claude_code.cost.usage', 4.0, '2026-03-02T10:00:00Z', NULL)`,
+		`INSERT INTO events (session_id, name, timestamp, sequence, attributes) VALUES ('lap-1', 'claude_code.api_request', '2026-03-02T10:00:00Z', 1, '{}')`,
+		`INSERT INTO daily_summaries (session_id, date, total_cost) VALUES ('lap-1', '2026-03-02', 4.0)`,
+		`INSERT INTO daily_stats (date, total_cost) VALUES ('2026-03-01', 9.0)`,
+		`INSERT INTO daily_stats (date, total_cost) VALUES ('2026-03-02', 4.0)`,
+		`INSERT INTO burn_rate_snapshots (timestamp, hourly_rate) VALUES ('2026-03-02T10:00:00Z', 1.5)`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('CostSurge', 'warning', 'surge', '', '2026-03-01T09:30:00Z')`,
+	)
+
+	result, err := MergeDB(local, peer)
+	if err != nil {
+		t.Fatalf("MergeDB: %v", err)
+	}
+	want := MergeResult{Sessions: 2, Metrics: 1, Events: 1, DailySummaries: 1, DailyStats: 1, BurnRateSnapshots: 1, Alerts: 0}
+	got := result
+	got.SkippedDays = nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("first merge: got %+v, want %+v", got, want)
+	}
+	if len(result.SkippedDays) != 1 || result.SkippedDays[0] != "2026-03-01" {
+		t.Errorf("skipped days: got %v, want [2026-03-01]", result.SkippedDays)
+	}
+
+	again, err := MergeDB(local, peer)
+	if err != nil {
+		t.Fatalf("second MergeDB: %v", err)
+	}
+	again.SkippedDays = nil
+	if !reflect.DeepEqual(again, MergeResult{}) {
+		t.Errorf("second merge should copy nothing, got %+v", again)
+	}
+
+	db, err := OpenDB(local)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if n := countRows(t, db, "SELECT COUNT(*) FROM sessions"); n != 3 {
+		t.Errorf("sessions: got %d, want 3", n)
+	}
+	var cost float64
+	var pid sql.NullInt64
+	var exited int
+	if err := db.QueryRow("SELECT total_cost, pid, exited FROM sessions WHERE session_id = 'lap-1'").Scan(&cost, &pid, &exited); err != nil {
+		t.Fatalf("reading lap-1: %v", err)
+	}
+	if cost != 4.0 || pid.Valid || exited != 1 {
+		t.Errorf("lap-1: cost=%v pid=%v exited=%d, want 4.0, NULL, 1", cost, pid, exited)
+	}
+	var host, instance, env, user string
+	err = db.QueryRow("SELECT host_name, service_instance_id, environment, user_name FROM sessions WHERE session_id = 'lap-1'").
+		Scan(&host, &instance, &env, &user)
+	if err != nil || host != "laptop" || instance != "inst-1" || env != "dev" || user != "sam" {
+		t.Errorf("lap-1 identity: %q %q %q %q (%v), want laptop inst-1 dev sam", host, instance, env, user, err)
+	}
+	if err := db.QueryRow("SELECT total_cost FROM sessions WHERE session_id = 'shared'").Scan(&cost); err != nil {
+		t.Fatalf("reading shared: %v", err)
+	}
+	if cost != 2.5 {
+		t.Errorf("shared session should take the more recent peer row, got cost %v", cost)
+	}
+	if err := db.QueryRow("SELECT pid FROM sessions WHERE session_id = 'desk-1'").Scan(&pid); err != nil || pid.Int64 != 100 {
+		t.Errorf("local session should be untouched, got pid %v (%v)", pid, err)
+	}
+	if err := db.QueryRow("SELECT total_cost FROM daily_stats WHERE date = '2026-03-01'").Scan(&cost); err != nil || cost != 3.0 {
+		t.Errorf("local daily stats should be kept, got %v (%v)", cost, err)
+	}
+	if n := countRows(t, db, "SELECT COUNT(*) FROM metrics"); n != 2 {
+		t.Errorf("metrics: got %d, want 2", n)
+	}
+}
+
+func TestMergeDB_PeerErrors(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.db")
+
+	if _, err := MergeDB(local, filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("expected an error for a missing peer")
+	}
+
+	old := filepath.Join(dir, "old.db")
+	seedSyncDB(t, old, "UPDATE schema_version SET version = 2")
+	if _, err := MergeDB(local, old); err == nil {
+		t.Error("expected an error for a peer at another schema version")
+	}
+}
+
+func TestImportDB(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "desktop.db")
+	other := filepath.Join(dir, "laptop.db")
+
+	seedSyncDB(t, local,
+		`INSERT INTO sessions (session_id, total_cost, last_event_at) VALUES ('desk-1', 1.0, '2026-03-01T10:00:00Z')`,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('shared', 'demo', '', '2026-03-01T10:00:00Z')`,
+	)
+	// A database from before session tags existed.
+	seedSyncDB(t, other,
+		`INSERT INTO sessions (session_id, total_cost, last_event_at) VALUES ('lap-1', 4.0, '2026-03-02T10:00:00Z')`,
+		`INSERT INTO alert_history (rule, severity, message, session_id, fired_at) VALUES ('CostSurge', 'warning', 'surge', 'lap-1', '2026-03-02T09:30:00Z')`,
+		`DROP TABLE session_tags`,
+		`UPDATE schema_version SET version = 13`,
+	)
+
+	missing := filepath.Join(dir, "missing.db")
+	if dry, err := ImportDB(missing, other, true); err != nil || dry.Sessions != 1 {
+		t.Errorf("dry run into a missing database: %+v, %v", dry, err)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("a dry run should not create the local database: %v", err)
+	}
+
+	dry, err := ImportDB(local, other, true)
+	if err != nil {
+		t.Fatalf("ImportDB dry run: %v", err)
+	}
+	if dry.Sessions != 1 || dry.Alerts != 1 {
+		t.Errorf("dry run counts: %+v", dry)
+	}
+
+	db, err := OpenDB(local)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	if n := countRows(t, db, "SELECT COUNT(*) FROM sessions"); n != 1 {
+		t.Fatalf("a dry run should not write, got %d sessions", n)
+	}
+
+	result, err := ImportDB(local, other, false)
+	if err != nil {
+		t.Fatalf("ImportDB: %v", err)
+	}
+	if result.Sessions != 1 || result.Alerts != 1 {
+		t.Errorf("import counts: %+v", result)
+	}
+	if n := countRows(t, db, "SELECT COUNT(*) FROM sessions WHERE session_id = 'lap-1' AND exited = 1"); n != 1 {
+		t.Error("the imported session should be stored as exited")
+	}
+	if version, err := peerSchemaVersion(other); err != nil || version != 13 {
+		t.Errorf("the imported database should be left at its version, got %d (%v)", version, err)
+	}
+
+	if _, err := ImportDB(local, local, false); err == nil {
+		t.Error("expected an error importing the local database into itself")
+	}
+	newer := filepath.Join(dir, "newer.db")
+	seedSyncDB(t, newer, "UPDATE schema_version SET version = 999")
+	if _, err := ImportDB(local, newer, false); err == nil {
+		t.Error("expected an error for a database from a newer cc-top")
+	}
+}
+
+func TestMergeDB_SessionTags(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.db")
+	peer := filepath.Join(dir, "peer.db")
+	seedSyncDB(t, local,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('a', 'demo', '', '2026-03-02T10:00:00Z')`,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('b', 'old', '', '2026-03-01T10:00:00Z')`,
+	)
+	seedSyncDB(t, peer,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('a', 'stale', '', '2026-03-01T10:00:00Z')`,
+		`INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES ('b', 'new', 'edited later', '2026-03-03T10:00:00Z')`,
+	)
+	result, err := MergeDB(local, peer)
+	if err != nil {
+		t.Fatalf("MergeDB: %v", err)
+	}
+	if result.SessionTags != 1 {
+		t.Errorf("session tags merged: got %d, want 1", result.SessionTags)
+	}
+
+	db, err := OpenDB(local)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	for id, want := range map[string]string{"a": "demo", "b": "new"} {
+		var tags string
+		if err := db.QueryRow("SELECT tags FROM session_tags WHERE session_id = ?", id).Scan(&tags); err != nil || tags != want {
+			t.Errorf("tags of %s = %q (%v), want %q", id, tags, err, want)
+		}
+	}
+}
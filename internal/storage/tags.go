@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sessionTagRow holds a session_tags change. A row without tags or a note
+// is deleted.
+type sessionTagRow struct {
+	SessionID string
+	Tags      []string
+	Note      string
+	UpdatedAt string // RFC3339
+}
+
+// SessionTags returns the tags and note attached to a session from the TUI.
+// They are kept in memory, so this is cheap enough to call while rendering.
+func (s *SQLiteStore) SessionTags(sessionID string) (tags []string, note string) {
+	s.tagsMu.RLock()
+	defer s.tagsMu.RUnlock()
+	row, ok := s.tags[sessionID]
+	if !ok {
+		return nil, ""
+	}
+	return slices.Clone(row.Tags), row.Note
+}
+
+// SetSessionTags attaches tags, such as "experiment" or "prod-fix", and a
+// note to a session, replacing any it had. No tags and an empty note remove
+// them. Tags are trimmed, lower-cased and deduplicated.
+func (s *SQLiteStore) SetSessionTags(sessionID string, tags []string, note string) {
+	row := &sessionTagRow{
+		SessionID: sessionID,
+		Tags:      normalizeTags(tags),
+		Note:      strings.TrimSpace(note),
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.tagsMu.Lock()
+	if len(row.Tags) == 0 && row.Note == "" {
+		delete(s.tags, sessionID)
+	} else {
+		s.tags[sessionID] = *row
+	}
+	s.tagsMu.Unlock()
+
+	s.sendWrite(writeOp{opType: "sessionTags", tags: row})
+}
+
+// normalizeTags trims and lower-cases tags, dropping empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// loadSessionTags reads the session_tags table into memory.
+func (s *SQLiteStore) loadSessionTags() error {
+	rows, err := s.db.Query("SELECT session_id, tags, note, updated_at FROM session_tags")
+	if err != nil {
+		return fmt.Errorf("querying session tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tags := make(map[string]sessionTagRow)
+	for rows.Next() {
+		var row sessionTagRow
+		var list string
+		if err := rows.Scan(&row.SessionID, &list, &row.Note, &row.UpdatedAt); err != nil {
+			return fmt.Errorf("scanning session tags: %w", err)
+		}
+		row.Tags = normalizeTags(strings.Split(list, ","))
+		tags[row.SessionID] = row
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating session tags: %w", err)
+	}
+
+	s.tagsMu.Lock()
+	s.tags = tags
+	s.tagsMu.Unlock()
+	return nil
+}
+
+func (s *SQLiteStore) writeSessionTags(tx *sql.Tx, row *sessionTagRow) error {
+	if len(row.Tags) == 0 && row.Note == "" {
+		_, err := tx.Exec("DELETE FROM session_tags WHERE session_id = ?", row.SessionID)
+		return err
+	}
+	_, err := tx.Exec(`
+		INSERT INTO session_tags (session_id, tags, note, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET tags=excluded.tags, note=excluded.note, updated_at=excluded.updated_at
+	`, row.SessionID, strings.Join(row.Tags, ","), row.Note, row.UpdatedAt)
+	return err
+}
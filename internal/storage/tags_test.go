@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSessionTags_PersistAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags.db")
+	store, err := NewSQLiteStore(path, 7, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+
+	store.SetSessionTags("s1", []string{" Experiment", "demo", "", "experiment"}, "  trying the new prompt ")
+	store.SetSessionTags("s2", []string{"prod-fix"}, "")
+	store.SetSessionTags("s2", nil, "")
+
+	tags, note := store.SessionTags("s1")
+	if !slices.Equal(tags, []string{"experiment", "demo"}) || note != "trying the new prompt" {
+		t.Errorf("SessionTags(s1) = %v, %q", tags, note)
+	}
+	if tags, note := store.SessionTags("s2"); tags != nil || note != "" {
+		t.Errorf("SessionTags(s2) after clearing = %v, %q", tags, note)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	store, err = NewSQLiteStore(path, 7, 90)
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	defer store.Close()
+	tags, note = store.SessionTags("s1")
+	if !slices.Equal(tags, []string{"experiment", "demo"}) || note != "trying the new prompt" {
+		t.Errorf("SessionTags(s1) after reopening = %v, %q", tags, note)
+	}
+	if tags, _ := store.SessionTags("s2"); tags != nil {
+		t.Errorf("cleared tags came back: %v", tags)
+	}
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+)
+
+const (
+	// autoThresholdWindowDays is the trailing window used for auto
+	// thresholds. The effective window is also capped by retention_days_raw,
+	// since older burn rate snapshots are pruned.
+	autoThresholdWindowDays = 30
+	autoThresholdInterval   = 7 * 24 * time.Hour
+
+	// autoThresholdMinSamples is roughly one day of five-minute snapshots;
+	// with less history the configured static thresholds stay in effect.
+	autoThresholdMinSamples = 288
+)
+
+// EnableAutoThresholds turns on history-derived alert thresholds at the given
+// percentile. Values are computed immediately and then refreshed weekly by
+// the maintenance loop.
+func (s *SQLiteStore) EnableAutoThresholds(percentile float64) {
+	s.autoMu.Lock()
+	s.autoEnabled = true
+	s.autoPercentile = percentile
+	s.autoMu.Unlock()
+
+	if err := s.refreshAutoThresholds(time.Now()); err != nil {
+		log.Printf("WARNING: computing auto alert thresholds: %v", err)
+	}
+}
+
+// AutoThresholds returns the most recently computed thresholds. ok is false
+// until enough history has been collected. It implements
+// alerts.ThresholdSource.
+func (s *SQLiteStore) AutoThresholds() (alerts.AutoThresholds, bool) {
+	s.autoMu.RLock()
+	defer s.autoMu.RUnlock()
+	return s.autoThresholds, s.autoValid
+}
+
+// autoThresholdsDue reports whether auto thresholds are enabled and either
+// older than autoThresholdInterval or still short of history, in which case
+// they are retried every maintenance cycle until enough has been collected.
+func (s *SQLiteStore) autoThresholdsDue(now time.Time) bool {
+	s.autoMu.RLock()
+	defer s.autoMu.RUnlock()
+	return s.autoEnabled && (!s.autoValid || now.Sub(s.autoThresholds.ComputedAt) >= autoThresholdInterval)
+}
+
+func (s *SQLiteStore) refreshAutoThresholds(now time.Time) error {
+	s.autoMu.RLock()
+	p := s.autoPercentile
+	s.autoMu.RUnlock()
+
+	t, err := s.computeAutoThresholds(now, autoThresholdWindowDays, p)
+	if err != nil {
+		return err
+	}
+
+	s.autoMu.Lock()
+	s.autoThresholds = t
+	s.autoValid = t.Samples >= autoThresholdMinSamples
+	s.autoMu.Unlock()
+	return nil
+}
+
+// computeAutoThresholds derives the p-th percentile of hourly cost rate and
+// token velocity from burn rate snapshots in the trailing window. Idle
+// snapshots (zero rate) are excluded so quiet periods don't drag the
+// threshold down.
+func (s *SQLiteStore) computeAutoThresholds(now time.Time, days int, p float64) (alerts.AutoThresholds, error) {
+	cutoff := now.AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+
+	rows, err := s.db.Query(`
+		SELECT hourly_rate, token_velocity FROM burn_rate_snapshots
+		WHERE timestamp >= ? AND (hourly_rate > 0 OR token_velocity > 0)
+	`, cutoff)
+	if err != nil {
+		return alerts.AutoThresholds{}, fmt.Errorf("querying burn rate snapshots: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rates, velocities []float64
+	samples := 0
+	for rows.Next() {
+		var rate, velocity float64
+		if err := rows.Scan(&rate, &velocity); err != nil {
+			return alerts.AutoThresholds{}, fmt.Errorf("scanning burn rate snapshot: %w", err)
+		}
+		samples++
+		if rate > 0 {
+			rates = append(rates, rate)
+		}
+		if velocity > 0 {
+			velocities = append(velocities, velocity)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return alerts.AutoThresholds{}, fmt.Errorf("iterating burn rate snapshots: %w", err)
+	}
+
+	return alerts.AutoThresholds{
+		CostPerHour:     nearestRank(rates, p),
+		TokensPerMinute: nearestRank(velocities, p),
+		Samples:         samples,
+		ComputedAt:      now,
+	}, nil
+}
+
+// nearestRank returns the p-th percentile of values using the nearest-rank
+// method, or 0 for an empty slice. values is sorted in place.
+func nearestRank(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	rank = max(1, min(rank, len(values)))
+	return values[rank-1]
+}
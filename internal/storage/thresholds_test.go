@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func insertBurnSnapshots(t *testing.T, store *SQLiteStore, now time.Time, n int, rate, velocity func(i int) float64) {
+	t.Helper()
+	for i := range n {
+		ts := now.Add(-time.Duration(i) * 5 * time.Minute).UTC().Format(time.RFC3339)
+		_, err := store.db.Exec(
+			"INSERT INTO burn_rate_snapshots (timestamp, hourly_rate, token_velocity) VALUES (?, ?, ?)",
+			ts, rate(i), velocity(i))
+		if err != nil {
+			t.Fatalf("insert burn rate snapshot: %v", err)
+		}
+	}
+}
+
+func TestAutoThresholds_Percentile(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 30, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Now()
+	// Rates 1..400 and velocities 10..4000 so P95 is rank 380.
+	insertBurnSnapshots(t, store, now, 400,
+		func(i int) float64 { return float64(i + 1) },
+		func(i int) float64 { return float64((i + 1) * 10) })
+	// Idle and out-of-window snapshots must not count.
+	insertBurnSnapshots(t, store, now, 50,
+		func(int) float64 { return 0 },
+		func(int) float64 { return 0 })
+	insertBurnSnapshots(t, store, now.AddDate(0, 0, -40), 10,
+		func(int) float64 { return 1000 },
+		func(int) float64 { return 100000 })
+
+	store.EnableAutoThresholds(95)
+
+	got, ok := store.AutoThresholds()
+	if !ok {
+		t.Fatal("expected auto thresholds to be available")
+	}
+	if got.Samples != 400 {
+		t.Errorf("Samples = %d, want 400", got.Samples)
+	}
+	if got.CostPerHour != 380 {
+		t.Errorf("CostPerHour = %v, want 380", got.CostPerHour)
+	}
+	if got.TokensPerMinute != 3800 {
+		t.Errorf("TokensPerMinute = %v, want 3800", got.TokensPerMinute)
+	}
+	if store.autoThresholdsDue(now) {
+		t.Error("freshly computed thresholds should not be due")
+	}
+	if !store.autoThresholdsDue(now.Add(autoThresholdInterval + time.Minute)) {
+		t.Error("thresholds should be due after a week")
+	}
+}
+
+func TestAutoThresholds_InsufficientHistory(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 30, 90)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	insertBurnSnapshots(t, store, time.Now(), autoThresholdMinSamples-1,
+		func(int) float64 { return 5 },
+		func(int) float64 { return 500 })
+
+	store.EnableAutoThresholds(95)
+
+	if _, ok := store.AutoThresholds(); ok {
+		t.Error("expected no auto thresholds with less than a day of history")
+	}
+	if !store.autoThresholdsDue(time.Now()) {
+		t.Error("thresholds short of history should be retried every cycle")
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// snapshotGap is the longest gap between burn rate snapshots that still
+// counts as one stretch; longer gaps mean cc-top was not running.
+const snapshotGap = 15 * time.Minute
+
+// ThresholdFirings replays the history since since against cfg and returns
+// how many times rule would have fired: once per stretch of burn rate
+// snapshots at or above the threshold for CostSurge and RunawayTokens (the
+// latter only once sustained), once per session over the threshold for
+// SessionCost. ok is false for rules that cannot be replayed.
+func (s *SQLiteStore) ThresholdFirings(rule string, cfg config.AlertsConfig, since time.Time) (n int, ok bool) {
+	var err error
+	switch rule {
+	case alerts.RuleCostSurge:
+		n, err = s.snapshotFirings("hourly_rate", cfg.CostSurgeThresholdPerHour, 0, since)
+	case alerts.RuleRunawayTokens:
+		n, err = s.snapshotFirings("token_velocity", float64(cfg.RunawayTokenVelocity),
+			time.Duration(cfg.RunawayTokenSustainedMinutes)*time.Minute, since)
+	case alerts.RuleSessionCost:
+		n, err = s.sessionCostFirings(cfg.SessionCostThreshold, since)
+	default:
+		return 0, false
+	}
+	if err != nil {
+		log.Printf("WARNING: replaying %s history: %v", rule, err)
+		return 0, false
+	}
+	return n, true
+}
+
+// snapshotFirings counts the stretches of snapshots whose column is at or
+// above threshold for at least sustained.
+func (s *SQLiteStore) snapshotFirings(column string, threshold float64, sustained time.Duration, since time.Time) (int, error) {
+	rows, err := s.db.Query(`SELECT timestamp, `+column+` FROM burn_rate_snapshots
+		WHERE timestamp >= ? ORDER BY timestamp`, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("querying burn rate snapshots: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var fired int
+	var runStart, prev time.Time
+	counted := false
+	for rows.Next() {
+		var ts string
+		var value float64
+		if err := rows.Scan(&ts, &value); err != nil {
+			return 0, fmt.Errorf("scanning burn rate snapshot: %w", err)
+		}
+		at, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			continue
+		}
+		if value < threshold || (!prev.IsZero() && at.Sub(prev) > snapshotGap) {
+			runStart, counted = time.Time{}, false
+		}
+		prev = at
+		if value < threshold {
+			continue
+		}
+		if runStart.IsZero() {
+			runStart = at
+		}
+		if !counted && at.Sub(runStart) >= sustained {
+			fired++
+			counted = true
+		}
+	}
+	return fired, rows.Err()
+}
+
+// sessionCostFirings counts the sessions active since since whose total
+// cost exceeds threshold.
+func (s *SQLiteStore) sessionCostFirings(threshold float64, since time.Time) (int, error) {
+	rows, err := s.db.Query(`SELECT last_event_at FROM sessions WHERE total_cost > ?`, threshold)
+	if err != nil {
+		return 0, fmt.Errorf("querying sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var fired int
+	for rows.Next() {
+		var last sql.NullString
+		if err := rows.Scan(&last); err != nil {
+			return 0, fmt.Errorf("scanning session: %w", err)
+		}
+		if at, err := time.Parse(time.RFC3339Nano, last.String); err == nil && !at.Before(since) {
+			fired++
+		}
+	}
+	return fired, rows.Err()
+}
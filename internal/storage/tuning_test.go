@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestThresholdFirings(t *testing.T) {
+	store := newTestStore(t)
+	defer func() { _ = store.Close() }()
+
+	now := time.Now()
+	since := now.Add(-7 * 24 * time.Hour)
+	insert := func(at time.Time, rate, velocity float64) {
+		t.Helper()
+		_, err := store.db.Exec("INSERT INTO burn_rate_snapshots (timestamp, hourly_rate, token_velocity) VALUES (?, ?, ?)",
+			at.UTC().Format(time.RFC3339), rate, velocity)
+		if err != nil {
+			t.Fatalf("insert burn rate snapshot: %v", err)
+		}
+	}
+	insert(now.Add(-8*24*time.Hour), 10, 0) // before the window
+	// Two stretches above $5/hr, the second split by a gap in the history.
+	start := now.Add(-2 * time.Hour)
+	for i, rate := range []float64{1, 6, 7, 2, 6} {
+		insert(start.Add(time.Duration(i)*5*time.Minute), rate, 0)
+	}
+	insert(start.Add(time.Hour), 8, 0)
+	// 20 minutes of 60k tokens/min, then 5 minutes.
+	for i := range 5 {
+		insert(now.Add(-30*time.Minute+time.Duration(i)*5*time.Minute), 0, 60000)
+	}
+	insert(now.Add(-2*time.Minute), 0, 60000)
+
+	if _, err := store.db.Exec(`INSERT INTO sessions (session_id, total_cost, last_event_at) VALUES
+		('s1', 12, ?), ('s2', 3, ?), ('s3', 50, ?)`,
+		now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), now.Add(-30*24*time.Hour).Format(time.RFC3339Nano)); err != nil {
+		t.Fatalf("insert sessions: %v", err)
+	}
+
+	cfg := config.DefaultConfig().Alerts
+	cfg.CostSurgeThresholdPerHour = 5
+	cfg.RunawayTokenVelocity = 50000
+	cfg.RunawayTokenSustainedMinutes = 15
+	cfg.SessionCostThreshold = 10
+
+	tests := []struct {
+		rule string
+		want int
+	}{
+		{alerts.RuleCostSurge, 3},
+		{alerts.RuleRunawayTokens, 1},
+		{alerts.RuleSessionCost, 1},
+	}
+	for _, tt := range tests {
+		if got, ok := store.ThresholdFirings(tt.rule, cfg, since); !ok || got != tt.want {
+			t.Errorf("%s fired %d times (ok=%v), want %d", tt.rule, got, ok, tt.want)
+		}
+	}
+
+	cfg.CostSurgeThresholdPerHour = 6.5
+	if got, _ := store.ThresholdFirings(alerts.RuleCostSurge, cfg, since); got != 2 {
+		t.Errorf("at $6.50/hr CostSurge fired %d times, want 2", got)
+	}
+	if _, ok := store.ThresholdFirings(alerts.RuleLoopDetector, cfg, since); ok {
+		t.Error("LoopDetector cannot be replayed from snapshots")
+	}
+}
@@ -13,33 +13,36 @@ import (
 
 // dailyStatsRow holds the data for a single daily_stats row.
 type dailyStatsRow struct {
-	Date             string
-	TotalCost        float64
-	TokenInput       int64
-	TokenOutput      int64
-	TokenCacheRead   int64
-	TokenCacheWrite  int64
-	SessionCount     int
-	APIRequests      int
-	APIErrors        int
-	LinesAdded       int
-	LinesRemoved     int
-	Commits          int
-	PRsOpened        int
-	CacheEfficiency  float64
-	CacheSavingsUSD  float64
-	ErrorRate        float64
-	RetryRate        float64
-	AvgAPILatencyMs  float64
-	LatencyP50Ms     float64
-	LatencyP95Ms     float64
-	LatencyP99Ms     float64
-	ModelBreakdown   interface{} // JSON-marshalable
-	TopTools         interface{} // JSON-marshalable
-	ErrorCategories  interface{} // JSON-marshalable
+	Date              string
+	TotalCost         float64
+	TokenInput        int64
+	TokenOutput       int64
+	TokenCacheRead    int64
+	TokenCacheWrite   int64
+	SessionCount      int
+	APIRequests       int
+	APIErrors         int
+	LinesAdded        int
+	LinesRemoved      int
+	Commits           int
+	PRsOpened         int
+	CacheEfficiency   float64
+	CacheSavingsUSD   float64
+	ErrorRate         float64
+	RetryRate         float64
+	AvgAPILatencyMs   float64
+	LatencyP50Ms      float64
+	LatencyP95Ms      float64
+	LatencyP99Ms      float64
+	ModelBreakdown    interface{} // JSON-marshalable
+	TopTools          interface{} // JSON-marshalable
+	ErrorCategories   interface{} // JSON-marshalable
 	LanguageBreakdown interface{} // JSON-marshalable
-	DecisionSources  interface{} // JSON-marshalable
-	MCPToolUsage     interface{} // JSON-marshalable
+	DecisionSources   interface{} // JSON-marshalable
+	MCPToolUsage      interface{} // JSON-marshalable
+	AccountBreakdown  interface{} // JSON-marshalable
+	ProjectBreakdown  interface{} // JSON-marshalable
+	ContextUsage      interface{} // JSON-marshalable
 }
 
 // burnRateSnapshotRow holds the data for a single burn_rate_snapshots row.
@@ -139,14 +142,20 @@ func (s *SQLiteStore) writePID(tx *sql.Tx, sessionID string, pid int) error {
 
 func (s *SQLiteStore) writeMetadata(tx *sql.Tx, sessionID string, meta state.SessionMetadata) error {
 	_, err := tx.Exec(`
-		INSERT INTO sessions (session_id, service_version, os_type, os_version, host_arch)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO sessions (session_id, service_version, os_type, os_version, host_arch,
+			host_name, service_instance_id, environment, user_name)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(session_id) DO UPDATE SET
 			service_version=excluded.service_version,
 			os_type=excluded.os_type,
 			os_version=excluded.os_version,
-			host_arch=excluded.host_arch
-	`, sessionID, meta.ServiceVersion, meta.OSType, meta.OSVersion, meta.HostArch)
+			host_arch=excluded.host_arch,
+			host_name=COALESCE(NULLIF(excluded.host_name, ''), host_name),
+			service_instance_id=COALESCE(NULLIF(excluded.service_instance_id, ''), service_instance_id),
+			environment=COALESCE(NULLIF(excluded.environment, ''), environment),
+			user_name=COALESCE(NULLIF(excluded.user_name, ''), user_name)
+	`, sessionID, meta.ServiceVersion, meta.OSType, meta.OSVersion, meta.HostArch,
+		meta.HostName, meta.ServiceInstanceID, meta.Environment, meta.User)
 	return err
 }
 
@@ -202,8 +211,9 @@ func (s *SQLiteStore) writeDailyStats(tx *sql.Tx, row *dailyStatsRow) error {
 			commits, prs_opened, cache_efficiency, cache_savings_usd, error_rate, retry_rate,
 			avg_api_latency_ms, latency_p50_ms, latency_p95_ms, latency_p99_ms,
 			model_breakdown, top_tools, error_categories, language_breakdown,
-			decision_sources, mcp_tool_usage
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			decision_sources, mcp_tool_usage, account_breakdown, project_breakdown,
+			context_usage
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		row.Date,
 		sanitizeFloat(row.TotalCost),
@@ -232,6 +242,9 @@ func (s *SQLiteStore) writeDailyStats(tx *sql.Tx, row *dailyStatsRow) error {
 		marshalJSONColumn("language_breakdown", row.LanguageBreakdown),
 		marshalJSONColumn("decision_sources", row.DecisionSources),
 		marshalJSONColumn("mcp_tool_usage", row.MCPToolUsage),
+		marshalJSONColumn("account_breakdown", row.AccountBreakdown),
+		marshalJSONColumn("project_breakdown", row.ProjectBreakdown),
+		marshalJSONColumn("context_usage", row.ContextUsage),
 	)
 	return err
 }
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// flushHistory is how many recent flushes the writer stats cover.
+const flushHistory = 100
+
+// writerMetrics records the writer's recent flushes and how long the write
+// queue has been nearly full.
+type writerMetrics struct {
+	mu        sync.Mutex
+	flushes   int64
+	written   int64 // writes committed in all flushes
+	batches   [flushHistory]int
+	latencies [flushHistory]time.Duration
+	highSince time.Time
+}
+
+func (w *writerMetrics) recordFlush(n int, d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	i := w.flushes % flushHistory
+	w.batches[i], w.latencies[i] = n, d
+	w.flushes++
+	w.written += int64(n)
+}
+
+// totalWritten returns how many writes have been committed since startup.
+func (w *writerMetrics) totalWritten() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written
+}
+
+// sampleQueue notes whether the queue is above state.QueueHighPercent at now.
+func (w *writerMetrics) sampleQueue(depth, capacity int, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if capacity == 0 || depth*100 <= capacity*state.QueueHighPercent {
+		w.highSince = time.Time{}
+	} else if w.highSince.IsZero() {
+		w.highSince = now
+	}
+}
+
+// WriterStats returns the write queue depth and the recent batch sizes and
+// flush latencies, for the diagnostics in the Stats view.
+func (s *SQLiteStore) WriterStats(now time.Time) state.WriterStats {
+	depth, capacity := len(s.writeChan), cap(s.writeChan)
+	s.writer.sampleQueue(depth, capacity, now)
+
+	w := &s.writer
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ws := state.WriterStats{
+		QueueDepth:    depth,
+		QueueCapacity: capacity,
+		HighSince:     w.highSince,
+		Flushes:       w.flushes,
+		Dropped:       s.droppedWrites.Load(),
+		Role:          s.role(),
+	}
+	n := int(min(w.flushes, flushHistory))
+	if n == 0 {
+		return ws
+	}
+	last := (w.flushes - 1) % flushHistory
+	ws.LastBatch, ws.LastFlush = w.batches[last], w.latencies[last]
+	var batches int
+	var latency time.Duration
+	for i := range n {
+		batches += w.batches[i]
+		latency += w.latencies[i]
+		ws.MaxBatch = max(ws.MaxBatch, w.batches[i])
+		ws.MaxFlush = max(ws.MaxFlush, w.latencies[i])
+	}
+	ws.AvgBatch = float64(batches) / float64(n)
+	ws.AvgFlush = latency / time.Duration(n)
+	return ws
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestWriterStats(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), 7, 90, WithTuning(Tuning{
+		BatchSize: 5, FlushInterval: 10 * time.Millisecond, ChannelSize: 10,
+	}))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for i := range 7 {
+		store.AddEvent("s1", state.Event{Name: "claude_code.user_prompt", Timestamp: time.Now(), Sequence: int64(i)})
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for store.WriterStats(time.Now()).Flushes < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	ws := store.WriterStats(time.Now())
+	if ws.QueueCapacity != 10 || ws.Flushes < 2 || ws.MaxBatch > 5 || ws.AvgBatch <= 0 || ws.MaxFlush <= 0 {
+		t.Errorf("writer stats: %+v", ws)
+	}
+	if n := store.writer.totalWritten(); n < 1 {
+		t.Errorf("total written: want some writes, got %d", n)
+	}
+
+	var counted writerMetrics
+	counted.recordFlush(5, time.Millisecond)
+	counted.recordFlush(2, time.Millisecond)
+	if n := counted.totalWritten(); n != 7 {
+		t.Errorf("total written: want 7, got %d", n)
+	}
+
+	// A nearly full queue is backlogged once it stays that way.
+	now := time.Now()
+	var w writerMetrics
+	w.sampleQueue(9, 10, now)
+	w.sampleQueue(10, 10, now.Add(30*time.Second))
+	backlog := state.WriterStats{HighSince: w.highSince}
+	if !backlog.HighSince.Equal(now) || backlog.Backlogged(now.Add(30*time.Second)) || !backlog.Backlogged(now.Add(time.Minute)) {
+		t.Errorf("high since %v", w.highSince)
+	}
+	w.sampleQueue(8, 10, now.Add(time.Minute))
+	if !w.highSince.IsZero() {
+		t.Error("80% or less is not high")
+	}
+}
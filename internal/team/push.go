@@ -0,0 +1,152 @@
+package team
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// pushTimeout bounds a single push.
+const pushTimeout = 30 * time.Second
+
+// Pusher periodically sends the sessions of the local store that changed
+// to a `cc-top serve` instance. Sessions the store received from other
+// instances (those with a User) are not pushed on.
+type Pusher struct {
+	url      string
+	token    string
+	host     string
+	user     string
+	interval time.Duration
+	store    state.Store
+	client   *http.Client
+
+	mu sync.Mutex
+	// sent is the LastEventAt of each session as of the last push that
+	// succeeded.
+	sent map[string]time.Time
+}
+
+// PushOption configures a Pusher.
+type PushOption func(*Pusher)
+
+// WithPushClient sets the client used to push.
+func WithPushClient(c *http.Client) PushOption {
+	return func(p *Pusher) { p.client = c }
+}
+
+// NewPusher returns a Pusher for cfg reading store. It returns nil when cfg
+// has no URL.
+func NewPusher(cfg config.PushConfig, store state.Store, opts ...PushOption) *Pusher {
+	if cfg.URL == "" {
+		return nil
+	}
+	p := &Pusher{
+		url:      strings.TrimSuffix(cfg.URL, "/") + PushPath,
+		token:    cfg.Token,
+		host:     cfg.Host,
+		user:     cfg.User,
+		interval: time.Duration(cfg.IntervalSeconds) * time.Second,
+		store:    store,
+		client:   &http.Client{Timeout: pushTimeout},
+		sent:     make(map[string]time.Time),
+	}
+	if p.host == "" {
+		p.host, _ = os.Hostname()
+	}
+	if p.user == "" {
+		if u, err := user.Current(); err == nil {
+			p.user = u.Username
+		}
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Start pushes every interval until ctx is cancelled. A failed push is
+// logged and its sessions are sent again with the next one.
+func (p *Pusher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		var failing bool
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			err := p.Push(ctx)
+			switch {
+			case err != nil && ctx.Err() == nil && !failing:
+				log.Printf("WARNING: pushing sessions to %s failed, will retry: %v", p.url, err)
+				failing = true
+			case err == nil && failing:
+				log.Printf("pushing sessions to %s again", p.url)
+				failing = false
+			}
+		}
+	}()
+}
+
+// Push sends the sessions that changed since the last successful push. It
+// sends nothing when no session changed.
+func (p *Pusher) Push(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sum := Summary{Host: p.host, User: p.user}
+	for _, s := range p.store.Snapshot().Sessions {
+		if s.Metadata.User != "" || len(s.Metrics) == 0 {
+			continue
+		}
+		if sent, ok := p.sent[s.SessionID]; ok && sent.Equal(s.LastEventAt) {
+			continue
+		}
+		sum.Sessions = append(sum.Sessions, Summarize(&s))
+	}
+	if len(sum.Sessions) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(sum)
+	if err != nil {
+		return fmt.Errorf("encoding summary: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	for _, ss := range sum.Sessions {
+		p.sent[ss.SessionID] = ss.LastEventAt
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/receiver"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// PushPath is where a Server accepts summaries.
+const PushPath = "/v1/push"
+
+// maxPushSize caps a pushed summary.
+const maxPushSize = 16 << 20
+
+// Server accepts summaries pushed by other cc-top instances and merges them
+// into a store.
+type Server struct {
+	addr   string
+	token  string
+	store  state.Store
+	server *http.Server
+}
+
+// NewServer creates a server for cfg that merges pushes into store.
+func NewServer(cfg config.ServeConfig, store state.Store) *Server {
+	return &Server{addr: cfg.Listen, token: cfg.Token, store: store}
+}
+
+// Start listens on the configured address and serves pushes in the
+// background. It returns an error if no token is configured or the address
+// is unavailable.
+func (s *Server) Start(ctx context.Context) error {
+	if s.token == "" {
+		return errors.New("serve: set [serve] token; pushing instances must send it")
+	}
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("serve %s: %w", s.addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(PushPath, s)
+	s.server = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	log.Printf("accepting pushes from other cc-top instances on http://%s%s", s.addr, PushPath)
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Printf("serve API stopped: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		s.Stop()
+	}()
+	return nil
+}
+
+// Stop shuts the server down, waiting up to 5 seconds for in-flight pushes.
+func (s *Server) Stop() {
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.server.Shutdown(ctx); err != nil {
+			log.Printf("serve API forced shutdown: %v", err)
+		}
+	}
+}
+
+// ServeHTTP accepts one pushed Summary.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !receiver.Authorized(req.Header.Get("Authorization"), s.token) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="cc-top"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPushSize+1))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPushSize {
+		http.Error(w, "summary too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	var sum Summary
+	if err := json.Unmarshal(body, &sum); err != nil {
+		http.Error(w, "invalid summary: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	added := Merge(s.store, sum)
+	if added > 0 {
+		log.Printf("push from %s (%s): %d session(s), %d sample(s)", sum.Host, sum.User, len(sum.Sessions), added)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
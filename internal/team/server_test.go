@@ -0,0 +1,125 @@
+package team
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestServer_RejectsBadRequests(t *testing.T) {
+	srv := httptest.NewServer(NewServer(config.ServeConfig{Token: "s3cret"}, state.NewMemoryStore()))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		auth   string
+		body   string
+		want   int
+	}{
+		{"wrong method", http.MethodGet, "Bearer s3cret", "", http.StatusMethodNotAllowed},
+		{"no token", http.MethodPost, "", `{}`, http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "Bearer guess", `{}`, http.StatusUnauthorized},
+		{"invalid json", http.MethodPost, "Bearer s3cret", `{"sessions":`, http.StatusBadRequest},
+		{"empty summary", http.MethodPost, "Bearer s3cret", `{}`, http.StatusNoContent},
+		// The header is parsed like the receiver's and the web dashboard's.
+		{"lowercase scheme", http.MethodPost, "bearer s3cret", `{}`, http.StatusNoContent},
+		{"padded header", http.MethodPost, "  Bearer  s3cret ", `{}`, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+PushPath, strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if got := resp.Header.Get("WWW-Authenticate"); resp.StatusCode == http.StatusUnauthorized && got != `Bearer realm="cc-top"` {
+				t.Errorf("WWW-Authenticate = %q", got)
+			}
+		})
+	}
+}
+
+func TestServer_StartRequiresToken(t *testing.T) {
+	s := NewServer(config.ServeConfig{Listen: "127.0.0.1:0"}, state.NewMemoryStore())
+	if err := s.Start(context.Background()); err == nil {
+		s.Stop()
+		t.Fatal("serve without a token should not start")
+	}
+}
+
+func TestPusher_PushesChangedSessions(t *testing.T) {
+	team := state.NewMemoryStore()
+	var requests int
+	handler := NewServer(config.ServeConfig{Token: "s3cret"}, team)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	local := state.NewMemoryStore()
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	addUsage(local, "sess-1", "claude-sonnet-4-5-20250929", 0.5, 1000, base)
+	// A session this instance received from another one is not pushed on.
+	local.AddMetric("sess-relayed", state.Metric{Name: "claude_code.cost.usage", Value: 9, Timestamp: base})
+	local.UpdateMetadata("sess-relayed", state.SessionMetadata{User: "carol"})
+
+	p := NewPusher(config.PushConfig{URL: srv.URL + "/", Token: "s3cret", IntervalSeconds: 30, Host: "laptop-a", User: "alice"}, local)
+	ctx := context.Background()
+	if err := p.Push(ctx); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if err := p.Push(ctx); err != nil || requests != 1 {
+		t.Fatalf("an unchanged store should not be pushed again: err %v, %d request(s)", err, requests)
+	}
+
+	addUsage(local, "sess-1", "claude-sonnet-4-5-20250929", 1.25, 2500, base.Add(time.Minute))
+	if err := p.Push(ctx); err != nil || requests != 2 {
+		t.Fatalf("a changed session should be pushed: err %v, %d request(s)", err, requests)
+	}
+
+	s := team.GetSession("sess-1")
+	if s == nil || s.TotalCost != 1.25 || s.Metadata.HostName != "laptop-a" || s.Metadata.User != "alice" {
+		t.Errorf("team session = %+v, want $1.25 from laptop-a by alice", s)
+	}
+	if team.GetSession("sess-relayed") != nil {
+		t.Error("relayed sessions should not be pushed")
+	}
+}
+
+func TestPusher_ReportsRejectedPush(t *testing.T) {
+	srv := httptest.NewServer(NewServer(config.ServeConfig{Token: "s3cret"}, state.NewMemoryStore()))
+	defer srv.Close()
+
+	local := state.NewMemoryStore()
+	addUsage(local, "sess-1", "claude-sonnet-4-5-20250929", 0.5, 1000, time.Now())
+	p := NewPusher(config.PushConfig{URL: srv.URL, Token: "wrong", IntervalSeconds: 30}, local)
+	err := p.Push(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Push with a wrong token = %v, want a 401 error", err)
+	}
+	// The session is retried with the next push.
+	if _, ok := p.sent["sess-1"]; ok {
+		t.Error("a rejected push should not mark its sessions as sent")
+	}
+}
+
+func TestNewPusher_DisabledWithoutURL(t *testing.T) {
+	if p := NewPusher(config.PushConfig{}, state.NewMemoryStore()); p != nil {
+		t.Error("NewPusher without a URL should return nil")
+	}
+}
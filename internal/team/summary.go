@@ -0,0 +1,132 @@
+// Package team connects cc-top instances into a team-wide view: a Pusher
+// sends session summaries from each machine to a Server run by
+// `cc-top serve`, which merges them into its store.
+package team
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// Summary is one push: the sessions of one instance that changed since its
+// previous push.
+type Summary struct {
+	Host     string           `json:"host"`
+	User     string           `json:"user"`
+	Sessions []SessionSummary `json:"sessions"`
+}
+
+// SessionSummary carries a session's resource attributes and the latest
+// value of each of its cumulative metric series, enough for the receiving
+// store to replay the same totals.
+type SessionSummary struct {
+	SessionID      string    `json:"session_id"`
+	LastEventAt    time.Time `json:"last_event_at"`
+	ServiceVersion string    `json:"service_version,omitempty"`
+	OSType         string    `json:"os_type,omitempty"`
+	OSVersion      string    `json:"os_version,omitempty"`
+	HostArch       string    `json:"host_arch,omitempty"`
+	// HostName is the host.name the session reported, if any; the
+	// summary's Host is used otherwise.
+	HostName    string    `json:"host_name,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Counters    []Counter `json:"counters"`
+}
+
+// Counter is the latest sample of one metric series.
+type Counter struct {
+	Name       string            `json:"name"`
+	Value      float64           `json:"value"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// Summarize builds the summary of one session: its metadata and the latest
+// sample of each metric series, oldest first so that replaying them ends
+// at the session's last activity.
+func Summarize(s *state.SessionData) SessionSummary {
+	ss := SessionSummary{
+		SessionID:      s.SessionID,
+		LastEventAt:    s.LastEventAt,
+		ServiceVersion: s.Metadata.ServiceVersion,
+		OSType:         s.Metadata.OSType,
+		OSVersion:      s.Metadata.OSVersion,
+		HostArch:       s.Metadata.HostArch,
+		HostName:       s.Metadata.HostName,
+		Environment:    s.Metadata.Environment,
+	}
+
+	latest := make(map[string]int)
+	for _, m := range s.Metrics {
+		key := state.MetricKey(m.Name, m.Attributes)
+		c := Counter{Name: m.Name, Value: m.Value, Attributes: m.Attributes, Timestamp: m.Timestamp}
+		if i, ok := latest[key]; ok {
+			ss.Counters[i] = c
+			continue
+		}
+		latest[key] = len(ss.Counters)
+		ss.Counters = append(ss.Counters, c)
+	}
+	sort.SliceStable(ss.Counters, func(i, j int) bool {
+		return ss.Counters[i].Timestamp.Before(ss.Counters[j].Timestamp)
+	})
+	return ss
+}
+
+// Merge adds a pushed summary to store. Series whose value the store
+// already has are skipped, so pushing the same summary twice changes
+// nothing. It returns the number of samples added.
+func Merge(store state.Store, sum Summary) int {
+	var added int
+	for _, ss := range sum.Sessions {
+		if ss.SessionID == "" {
+			continue
+		}
+		existing := store.GetSession(ss.SessionID)
+		for _, c := range ss.Counters {
+			if existing != nil {
+				if v, ok := existing.PreviousValues[state.MetricKey(c.Name, c.Attributes)]; ok && v == c.Value {
+					continue
+				}
+			}
+			store.AddMetric(ss.SessionID, state.Metric{
+				Name:       c.Name,
+				Value:      c.Value,
+				Attributes: c.Attributes,
+				Timestamp:  c.Timestamp,
+			})
+			added++
+		}
+
+		meta := state.SessionMetadata{
+			ServiceVersion: ss.ServiceVersion,
+			OSType:         ss.OSType,
+			OSVersion:      ss.OSVersion,
+			HostArch:       ss.HostArch,
+			HostName:       ss.HostName,
+			Environment:    ss.Environment,
+			User:           sum.User,
+		}
+		if meta.HostName == "" {
+			meta.HostName = sum.Host
+		}
+		if existing == nil || !sameMetadata(existing.Metadata, meta) {
+			store.UpdateMetadata(ss.SessionID, meta)
+		}
+	}
+	return added
+}
+
+// sameMetadata reports whether a pushed summary would leave the stored
+// metadata unchanged, to spare the store a write on every push.
+func sameMetadata(have, pushed state.SessionMetadata) bool {
+	return have.ServiceVersion == pushed.ServiceVersion &&
+		have.OSType == pushed.OSType &&
+		have.OSVersion == pushed.OSVersion &&
+		have.HostArch == pushed.HostArch &&
+		have.HostName == pushed.HostName &&
+		have.Environment == pushed.Environment &&
+		have.User == pushed.User
+}
@@ -0,0 +1,91 @@
+package team
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// addUsage reports cumulative cost and tokens for a session, like an OTLP
+// export would.
+func addUsage(store state.Store, sessionID, model string, cost, tokens float64, at time.Time) {
+	store.AddMetric(sessionID, state.Metric{
+		Name: "claude_code.cost.usage", Value: cost,
+		Attributes: map[string]string{"model": model}, Timestamp: at,
+	})
+	store.AddMetric(sessionID, state.Metric{
+		Name: "claude_code.token.usage", Value: tokens,
+		Attributes: map[string]string{"model": model, "type": "input"}, Timestamp: at,
+	})
+}
+
+func TestSummarize_LatestSamplePerSeries(t *testing.T) {
+	store := state.NewMemoryStore()
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	addUsage(store, "sess-1", "claude-sonnet-4-5-20250929", 0.5, 1000, base)
+	addUsage(store, "sess-1", "claude-sonnet-4-5-20250929", 1.5, 3000, base.Add(time.Minute))
+	addUsage(store, "sess-1", "claude-haiku-4-5-20251001", 0.1, 500, base.Add(2*time.Minute))
+	store.UpdateMetadata("sess-1", state.SessionMetadata{HostName: "laptop-a", ServiceVersion: "2.0.14"})
+
+	ss := Summarize(store.GetSession("sess-1"))
+	if len(ss.Counters) != 4 {
+		t.Fatalf("want one counter per series, got %+v", ss.Counters)
+	}
+	if c := ss.Counters[0]; c.Name != "claude_code.cost.usage" || c.Value != 1.5 {
+		t.Errorf("first counter = %+v, want the latest sonnet cost", c)
+	}
+	if last := ss.Counters[3]; !last.Timestamp.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("counters should be oldest first, last is %+v", last)
+	}
+	if ss.HostName != "laptop-a" || ss.ServiceVersion != "2.0.14" {
+		t.Errorf("metadata not summarized: %+v", ss)
+	}
+}
+
+func TestMerge_ReplaysTotalsOnce(t *testing.T) {
+	local := state.NewMemoryStore()
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	addUsage(local, "sess-1", "claude-sonnet-4-5-20250929", 0.5, 1000, base)
+
+	team := state.NewMemoryStore()
+	push := func() int {
+		return Merge(team, Summary{Host: "laptop-a", User: "alice", Sessions: []SessionSummary{Summarize(local.GetSession("sess-1"))}})
+	}
+	if got := push(); got != 2 {
+		t.Errorf("first push added %d samples, want 2", got)
+	}
+	if got := push(); got != 0 {
+		t.Errorf("pushing the same summary again added %d samples, want 0", got)
+	}
+
+	addUsage(local, "sess-1", "claude-sonnet-4-5-20250929", 2.0, 4000, base.Add(5*time.Minute))
+	push()
+
+	s := team.GetSession("sess-1")
+	if s == nil {
+		t.Fatal("pushed session missing from the team store")
+	}
+	if math.Abs(s.TotalCost-2.0) > 1e-9 || s.TotalTokens != 4000 {
+		t.Errorf("team totals = $%f, %d tokens, want $2 and 4000", s.TotalCost, s.TotalTokens)
+	}
+	if s.Metadata.HostName != "laptop-a" || s.Metadata.User != "alice" {
+		t.Errorf("metadata = %+v, want host laptop-a and user alice", s.Metadata)
+	}
+	if !s.LastEventAt.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("LastEventAt = %v, want the last pushed sample", s.LastEventAt)
+	}
+}
+
+func TestMerge_KeepsReportedHostName(t *testing.T) {
+	team := state.NewMemoryStore()
+	Merge(team, Summary{Host: "pusher", Sessions: []SessionSummary{{
+		SessionID: "sess-1",
+		HostName:  "devcontainer-7",
+		Counters:  []Counter{{Name: "claude_code.cost.usage", Value: 1, Timestamp: time.Now()}},
+	}}})
+	if got := team.GetSession("sess-1").Metadata.HostName; got != "devcontainer-7" {
+		t.Errorf("HostName = %q, want the host.name the session reported", got)
+	}
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+)
+
+// alertFlashFor is how long the header flashes an alert.
+const alertFlashFor = 5 * time.Second
+
+// AlertFlashMsg flashes an alert in the header. The bell notification
+// backend sends it, so alerts stand out where no desktop notifier is
+// available.
+type AlertFlashMsg alerts.Alert
+
+// alertFlashIndicator returns the header's alert flash, or "" when none is
+// showing.
+func (m Model) alertFlashIndicator(now time.Time) string {
+	if m.alertFlash.Rule == "" || now.Sub(m.alertFlashAt) >= alertFlashFor {
+		return ""
+	}
+	style := alertWarningStyle
+	if m.alertFlash.Severity == alerts.SeverityCritical {
+		style = alertCriticalStyle
+	}
+	label := fmt.Sprintf("[!] %s", m.alertFlash.Rule)
+	if m.alertFlash.SessionID != "" {
+		label += " " + truncateID(m.alertFlash.SessionID, 8)
+	}
+	return style.Reverse(true).Render(label)
+}
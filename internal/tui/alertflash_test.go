@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestAlertFlash(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+	if got := m.alertFlashIndicator(time.Now()); got != "" {
+		t.Errorf("no flash before an alert, got %q", got)
+	}
+
+	updated, _ := m.Update(AlertFlashMsg{Rule: alerts.RuleErrorStorm, Severity: alerts.SeverityCritical, SessionID: "abcdef123456"})
+	m = updated.(Model)
+	if got := stripAnsi(m.headerIndicators()); !strings.Contains(got, "[!] ErrorStorm abcdef12") {
+		t.Errorf("header should flash the alert, got %q", got)
+	}
+	if got := m.alertFlashIndicator(m.alertFlashAt.Add(alertFlashFor)); got != "" {
+		t.Errorf("flash should end after %v, got %q", alertFlashFor, got)
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -13,6 +14,7 @@ import (
 var severityIcons = map[string]string{
 	"critical": "!!",
 	"warning":  "!?",
+	"info":     "i ",
 }
 
 // renderAlertsPanel renders the bottom alerts bar.
@@ -28,6 +30,9 @@ func (m Model) renderAlertsPanel(w, h int) string {
 
 	if len(activeAlerts) == 0 {
 		statusLine := statusBarStyle.Render(" Alerts: None ")
+		if silenceLine := m.renderSilenceLine(contentW - 26); silenceLine != "" {
+			statusLine += " " + silenceLine
+		}
 		if focused {
 			statusLine += dimStyle.Render(" (Esc:back)")
 		}
@@ -39,12 +44,16 @@ func (m Model) renderAlertsPanel(w, h int) string {
 	}
 
 	var lines []string
+	silenceLine := m.renderSilenceLine(contentW)
 
 	// Show alerts, scrollable if many.
 	visibleH := h - 2 // borders
 	if visibleH < 1 {
 		visibleH = 1
 	}
+	if silenceLine != "" && visibleH > 1 {
+		visibleH--
+	}
 
 	// When focused, scroll to keep cursor visible.
 	startIdx := 0
@@ -93,8 +102,12 @@ func (m Model) renderAlertsPanel(w, h int) string {
 		lines = append(lines, countLine)
 	}
 
+	if silenceLine != "" && h-2 > 1 {
+		lines = append(lines, silenceLine)
+	}
+
 	content := strings.Join(lines, "\n")
-	borderColor := lipgloss.Color("196")
+	borderColor := currentPalette.Critical
 	if focused {
 		borderColor = focusBorderColor
 	}
@@ -102,15 +115,51 @@ func (m Model) renderAlertsPanel(w, h int) string {
 		panelBorderStyle.BorderForeground(borderColor))
 }
 
-// getActiveAlerts retrieves alerts from the provider.
+// getActiveAlerts retrieves the alerts shown in the alert bar: those not
+// acknowledged, snoozed or silenced.
 func (m Model) getActiveAlerts() []alerts.Alert {
 	if m.alerts == nil {
 		return nil
 	}
+	var all []alerts.Alert
 	if m.selectedSession != "" {
-		return m.alerts.ActiveForSession(m.selectedSession)
+		all = m.alerts.ActiveForSession(m.selectedSession)
+	} else {
+		all = m.alerts.Active()
+	}
+	var result []alerts.Alert
+	for _, a := range all {
+		if !a.Silenced {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// renderSilenceLine lists the rule silences in effect and when they end,
+// e.g. "Silenced: CostSurge until 15:30 (load test)". It is empty when
+// there are none.
+func (m Model) renderSilenceLine(maxW int) string {
+	if m.alerts == nil {
+		return ""
+	}
+	silences := m.alerts.Silences()
+	if len(silences) == 0 {
+		return ""
+	}
+	now := time.Now()
+	parts := make([]string, len(silences))
+	for i, s := range silences {
+		until := m.formatClockMinutes(s.Until)
+		if s.Until.Sub(now) >= 24*time.Hour {
+			until = s.Until.Local().Format("Jan 2 ") + until
+		}
+		parts[i] = s.Rule + " until " + until
+		if s.Reason != "" {
+			parts[i] += " (" + s.Reason + ")"
+		}
 	}
-	return m.alerts.Active()
+	return dimStyle.Render(truncateStr("Silenced: "+strings.Join(parts, ", "), maxW))
 }
 
 // renderAlertLine formats a single alert for display in the bottom bar.
@@ -124,6 +173,8 @@ func renderAlertLine(a alerts.Alert, maxW int, selectedSession string) string {
 	switch a.Severity {
 	case "critical":
 		style = alertCriticalStyle
+	case "info":
+		style = dimStyle
 	default:
 		style = alertWarningStyle
 	}
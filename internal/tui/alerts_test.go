@@ -54,6 +54,27 @@ func TestRenderAlertsPanel_WithAlerts(t *testing.T) {
 	}
 }
 
+func TestRenderAlertsPanel_Silences(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mockAlerts := &mockAlertProvider{
+		silences: []alerts.Silence{{ID: 1, Rule: "CostSurge", Reason: "load test", Until: time.Now().Add(2 * time.Hour)}},
+	}
+	m := NewModel(cfg, WithAlertProvider(mockAlerts), WithStartView(ViewDashboard))
+	m.width = 120
+	m.height = 40
+
+	panel := stripAnsi(m.renderAlertsPanel(120, 3))
+	if !strings.Contains(panel, "None") || !strings.Contains(panel, "Silenced: CostSurge until") || !strings.Contains(panel, "(load test)") {
+		t.Errorf("panel should list the silence next to 'None':\n%s", panel)
+	}
+
+	mockAlerts.alerts = []alerts.Alert{{Rule: "ErrorStorm", Severity: "critical", Message: "API errors", SessionID: "sess-001", FiredAt: time.Now()}}
+	panel = stripAnsi(m.renderAlertsPanel(120, 5))
+	if !strings.Contains(panel, "ErrorStorm") || !strings.Contains(panel, "Silenced: CostSurge") {
+		t.Errorf("panel should list alerts and the silence:\n%s", panel)
+	}
+}
+
 func TestRenderAlertsPanel_SessionSpecific(t *testing.T) {
 	cfg := config.DefaultConfig()
 	mockAlerts := &mockAlertProvider{
@@ -145,3 +166,34 @@ func TestRenderAlertsPanel_NilProvider(t *testing.T) {
 		t.Error("alerts panel with nil provider should show 'None'")
 	}
 }
+
+func TestAlertsPanel_AcknowledgeAndSnooze(t *testing.T) {
+	mockAlerts := &mockAlertProvider{
+		alerts: []alerts.Alert{
+			{Rule: "CostSurge", Severity: "warning", SessionID: "sess-001", FiredAt: time.Now()},
+			{Rule: "ErrorStorm", Severity: "critical", SessionID: "sess-002", FiredAt: time.Now()},
+		},
+	}
+	m := NewModel(config.DefaultConfig(), WithAlertProvider(mockAlerts), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+
+	m = typeKeys(t, m, runes("a"), runes("j"), runes("x"))
+	if bar := m.getActiveAlerts(); len(bar) != 1 || bar[0].Rule != "CostSurge" {
+		t.Fatalf("x should acknowledge the focused alert, left %+v", bar)
+	}
+	if !mockAlerts.alerts[1].Silenced {
+		t.Error("the acknowledged alert should be marked silenced")
+	}
+	if m.alertCursor != 0 {
+		t.Errorf("cursor should move back onto the remaining alert, got %d", m.alertCursor)
+	}
+
+	m = typeKeys(t, m, runes("z"))
+	if bar := m.getActiveAlerts(); len(bar) != 0 {
+		t.Errorf("z should snooze the focused alert, left %+v", bar)
+	}
+	if len(mockAlerts.alerts) != 2 {
+		t.Errorf("acknowledged and snoozed alerts should stay fired, got %+v", mockAlerts.alerts)
+	}
+	typeKeys(t, m, runes("x"))
+}
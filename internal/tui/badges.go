@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// kpiRefreshInterval bounds how often the header badges recompute their
+// KPIs; error rate and cache efficiency need the global stats, which are
+// too expensive for every tick.
+const kpiRefreshInterval = 5 * time.Second
+
+// badgeWarnShare is the share of its target at which a badge turns yellow.
+const badgeWarnShare = 0.8
+
+// refreshKPIs recomputes the KPIs pinned by [display.badges].
+func (m *Model) refreshKPIs(now time.Time) {
+	badges := m.cfg.Display.Badges
+	if len(badges) == 0 || (now.Sub(m.kpisAt) < kpiRefreshInterval && now.After(m.kpisAt)) {
+		return
+	}
+	kpis := make(map[string]float64, len(badges))
+	if m.burnRate != nil {
+		br := m.burnRate.GetGlobal()
+		var today float64
+		for _, c := range br.TodayByHour {
+			today += c
+		}
+		kpis["cost_today"] = today
+		kpis["burn_rate"] = br.HourlyRate
+	}
+	_, errorRate := badges["error_rate"]
+	_, cacheEfficiency := badges["cache_efficiency"]
+	if m.stats != nil && (errorRate || cacheEfficiency) {
+		ds := m.stats.GetGlobal()
+		kpis["error_rate"] = ds.ErrorRate * 100
+		kpis["cache_efficiency"] = ds.CacheEfficiency * 100
+	}
+	m.kpis, m.kpisAt = kpis, now
+}
+
+// renderKPIBadges renders the KPIs pinned by [display.badges], e.g.
+// " Today $12.40 ". A badge is green on target, yellow within a fifth of
+// missing it and red once missed; cache efficiency should stay above its
+// target, the other KPIs below.
+func (m Model) renderKPIBadges() string {
+	var sb strings.Builder
+	for _, name := range config.BadgeKPIs {
+		target, ok := m.cfg.Display.Badges[name]
+		if !ok {
+			continue
+		}
+		label, format := kpiLabel(name)
+		value, known := m.kpis[name]
+		if !known {
+			sb.WriteString("  " + dimStyle.Render(label+" --"))
+			continue
+		}
+
+		higherIsBetter := name == "cache_efficiency"
+		style := costGreenStyle
+		switch {
+		case higherIsBetter && value < target*badgeWarnShare, !higherIsBetter && value > target:
+			style = costRedStyle
+		case higherIsBetter && value < target, !higherIsBetter && value > target*badgeWarnShare:
+			style = costYellowStyle
+		}
+		sb.WriteString("  " + style.Reverse(true).Render(" "+label+" "+fmt.Sprintf(format, value)+" "))
+	}
+	return sb.String()
+}
+
+// kpiLabel returns the badge label of a KPI and the format of its value.
+func kpiLabel(name string) (label, format string) {
+	switch name {
+	case "cost_today":
+		return "Today", "$%.2f"
+	case "burn_rate":
+		return "Rate", "$%.2f/hr"
+	case "error_rate":
+		return "Err", "%.1f%%"
+	case "cache_efficiency":
+		return "Cache", "%.0f%%"
+	}
+	return name, "%g"
+}
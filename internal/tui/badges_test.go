@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/stats"
+)
+
+func TestRenderKPIBadges(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg, WithStartView(ViewDashboard))
+	if got := m.renderKPIBadges(); got != "" {
+		t.Errorf("no badges configured, got %q", got)
+	}
+
+	cfg.Display.Badges = map[string]float64{"cost_today": 20, "error_rate": 5, "cache_efficiency": 60}
+	br := burnrate.BurnRate{HourlyRate: 3}
+	br.TodayByHour[9], br.TodayByHour[10] = 10, 7 // $17 of $20: within a fifth of the target
+	sp := &mockStatsProvider{global: stats.DashboardStats{ErrorRate: 0.12, CacheEfficiency: 0.75}}
+	m = NewModel(cfg, WithStartView(ViewDashboard),
+		WithBurnRateProvider(&mockBurnRateProvider{global: br}), WithStatsProvider(sp))
+	if got := stripAnsi(m.renderKPIBadges()); !strings.Contains(got, "Today --") {
+		t.Errorf("badges before the first refresh should show --, got %q", got)
+	}
+
+	now := time.Now()
+	m.refreshKPIs(now)
+	got := stripAnsi(m.renderKPIBadges())
+	for _, want := range []string{" Today $17.00 ", " Err 12.0% ", " Cache 75% "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("badges should contain %q, got %q", want, got)
+		}
+	}
+	if strings.Contains(got, "Rate") {
+		t.Errorf("burn_rate is not pinned, got %q", got)
+	}
+	if i, j := strings.Index(got, "Today"), strings.Index(got, "Cache"); i > j {
+		t.Errorf("badges should follow the KPI order, got %q", got)
+	}
+
+	// Values are cached between refreshes.
+	sp.global.ErrorRate = 0.01
+	m.refreshKPIs(now.Add(time.Second))
+	if m.kpis["error_rate"] != 12 {
+		t.Errorf("error_rate refreshed too early: %v", m.kpis["error_rate"])
+	}
+	m.refreshKPIs(now.Add(kpiRefreshInterval))
+	if m.kpis["error_rate"] != 1 {
+		t.Errorf("error_rate not refreshed: %v", m.kpis["error_rate"])
+	}
+}
+
+func TestRenderKPIBadges_Colors(t *testing.T) {
+	defer applyTheme(config.DefaultConfig().Display.Theme)
+	applyTheme(config.ThemeConfig{Name: "dark"})
+
+	cfg := config.DefaultConfig()
+	cfg.Display.Badges = map[string]float64{"burn_rate": 10}
+	m := NewModel(cfg)
+	tests := []struct {
+		rate float64
+		want string
+	}{
+		{5, costGreenStyle.Reverse(true).Render(" Rate $5.00/hr ")},
+		{9, costYellowStyle.Reverse(true).Render(" Rate $9.00/hr ")},
+		{11, costRedStyle.Reverse(true).Render(" Rate $11.00/hr ")},
+	}
+	for _, tt := range tests {
+		m.kpis = map[string]float64{"burn_rate": tt.rate}
+		if got := m.renderKPIBadges(); got != "  "+tt.want {
+			t.Errorf("rate %v: got %q, want %q", tt.rate, got, "  "+tt.want)
+		}
+	}
+
+	cfg.Display.Badges = map[string]float64{"cache_efficiency": 60}
+	m = NewModel(cfg)
+	m.kpis = map[string]float64{"cache_efficiency": 40}
+	if got, want := m.renderKPIBadges(), "  "+costRedStyle.Reverse(true).Render(" Cache 40% "); got != want {
+		t.Errorf("low cache efficiency should be red: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+)
+
+// budgetGaugeCells is the width of each budget bar in the header.
+const budgetGaugeCells = 8
+
+// renderBudgetGauge renders one bar per configured budget, e.g.
+// "  Wk ■■■■■□□□ $62/$100", coloured by the share spent.
+func (m Model) renderBudgetGauge(now time.Time) string {
+	if m.budgets == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, s := range m.budgets.Status(now) {
+		label := "Mo"
+		if s.Period == budget.Weekly {
+			label = "Wk"
+		}
+		pct := s.Percent()
+		filled := min(budgetGaugeCells, int(pct/100*budgetGaugeCells+0.5))
+		style := costGreenStyle
+		switch {
+		case pct >= 100:
+			style = costRedStyle
+		case pct >= 80:
+			style = costYellowStyle
+		}
+		sb.WriteString(dimStyle.Render("  " + label + " "))
+		sb.WriteString(style.Render(strings.Repeat("■", filled)))
+		sb.WriteString(dimStyle.Render(strings.Repeat("□", budgetGaugeCells-filled)))
+		sb.WriteString(style.Render(fmt.Sprintf(" $%.0f/$%.0f", s.Spent, s.Limit)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/budget"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+type mockBudgetProvider struct {
+	statuses []budget.Status
+}
+
+func (m *mockBudgetProvider) Status(time.Time) []budget.Status { return m.statuses }
+
+func TestRenderBudgetGauge(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	if got := m.renderBudgetGauge(time.Now()); got != "" {
+		t.Errorf("no budget provider should render nothing, got %q", got)
+	}
+
+	m = NewModel(config.DefaultConfig(), WithBudgetProvider(&mockBudgetProvider{statuses: []budget.Status{
+		{Period: budget.Weekly, Limit: 100, Spent: 50},
+		{Period: budget.Monthly, Limit: 400, Spent: 500},
+	}}))
+	got := m.renderBudgetGauge(time.Now())
+	for _, want := range []string{"Wk ■■■■□□□□ $50/$100", "Mo ■■■■■■■■ $500/$400"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("gauge %q should contain %q", got, want)
+		}
+	}
+}
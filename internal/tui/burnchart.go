@@ -0,0 +1,319 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nixlim/cc-top/internal/burnrate"
+)
+
+const (
+	rateChartHeight   = 8
+	rateChartMinWidth = 24
+	rateChartMaxWidth = 96
+	rateChartGutter   = 10 // "  $123.45 ┤"
+
+	barChartHeight = 4
+	sparklineWidth = 24
+)
+
+// rateChartCell is one character of the chart grid.
+type rateChartCell struct {
+	ch    rune
+	style *lipgloss.Style
+}
+
+// trendStyle returns the chart color for a trend direction, or nil for flat.
+func trendStyle(t burnrate.TrendDirection) *lipgloss.Style {
+	switch t {
+	case burnrate.TrendUp:
+		return &costRedStyle
+	case burnrate.TrendDown:
+		return &costGreenStyle
+	}
+	return nil
+}
+
+// rateChartWidth returns the plot width of a rate chart in the detail
+// overlay, at most rateChartMaxWidth, or 0 when not even rateChartMinWidth
+// fits beside the axis labels.
+func (m Model) rateChartWidth() int {
+	width := min(m.detailContentWidth()-rateChartGutter-2, rateChartMaxWidth)
+	if width < rateChartMinWidth {
+		return 0
+	}
+	return width
+}
+
+// rateChartTooNarrow stands in for a rate chart that does not fit.
+func rateChartTooNarrow() []string {
+	return []string{dimStyle.Render("  Too narrow for the chart.")}
+}
+
+// renderRateChart draws the $/hr of snapshots (oldest first) as a line chart
+// spanning the first to the last snapshot. Points are colored by trend, gaps
+// between snapshots are interpolated, and the peak is marked and annotated.
+func (m Model) renderRateChart(snapshots []BurnRateSnapshotRow) []string {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	width := m.rateChartWidth()
+	if width == 0 {
+		return rateChartTooNarrow()
+	}
+	first, last := snapshots[0].Timestamp, snapshots[len(snapshots)-1].Timestamp
+	span := last.Sub(first)
+	if span <= 0 {
+		width = 1
+	}
+
+	// Bucket snapshots into columns, keeping the highest rate per column so
+	// the peak is never averaged away.
+	values := make([]float64, width)
+	filled := make([]bool, width)
+	trends := make([]burnrate.TrendDirection, width)
+	peak := 0
+	for i, snap := range snapshots {
+		col := 0
+		if span > 0 {
+			col = int(float64(snap.Timestamp.Sub(first)) / float64(span) * float64(width-1))
+			col = min(max(col, 0), width-1)
+		}
+		if !filled[col] || snap.HourlyRate > values[col] {
+			values[col] = snap.HourlyRate
+			trends[col] = snap.Trend
+			filled[col] = true
+		}
+		if snap.HourlyRate > snapshots[peak].HourlyRate {
+			peak = i
+		}
+	}
+	peakRate := snapshots[peak].HourlyRate
+	peakCol := 0
+	if span > 0 {
+		peakCol = int(float64(snapshots[peak].Timestamp.Sub(first)) / float64(span) * float64(width-1))
+	}
+
+	rowOf := func(v float64) int {
+		if peakRate <= 0 {
+			return 0
+		}
+		return min(max(int(math.Round(v/peakRate*float64(rateChartHeight-1))), 0), rateChartHeight-1)
+	}
+
+	grid := make([][]rateChartCell, rateChartHeight)
+	for r := range grid {
+		grid[r] = make([]rateChartCell, width)
+		for c := range grid[r] {
+			grid[r][c] = rateChartCell{ch: ' '}
+		}
+	}
+
+	// Fill empty columns between snapshots by linear interpolation, taking
+	// the trend of the snapshot the line is heading towards.
+	interpolated := make([]bool, width)
+	prev := -1
+	for c := range width {
+		if !filled[c] {
+			continue
+		}
+		for g := prev + 1; prev >= 0 && g < c; g++ {
+			frac := float64(g-prev) / float64(c-prev)
+			values[g] = values[prev] + (values[c]-values[prev])*frac
+			trends[g] = trends[c]
+			interpolated[g] = true
+		}
+		prev = c
+	}
+
+	prevRow := -1
+	for c := range width {
+		if !filled[c] && !interpolated[c] {
+			continue
+		}
+		row := rowOf(values[c])
+		style := trendStyle(trends[c])
+		if prevRow >= 0 {
+			for r := min(prevRow, row) + 1; r < max(prevRow, row); r++ {
+				grid[r][c] = rateChartCell{ch: '│', style: style}
+			}
+		}
+		ch := '•'
+		if interpolated[c] {
+			ch = '·'
+		}
+		grid[row][c] = rateChartCell{ch: ch, style: style}
+		prevRow = row
+	}
+	grid[rowOf(peakRate)][peakCol] = rateChartCell{ch: '◆', style: &alertWarningStyle}
+
+	var lines []string
+	for r := rateChartHeight - 1; r >= 0; r-- {
+		label := ""
+		switch r {
+		case rateChartHeight - 1:
+			label = fmt.Sprintf("$%.2f", peakRate)
+		case (rateChartHeight - 1) / 2:
+			label = fmt.Sprintf("$%.2f", peakRate/2)
+		case 0:
+			label = "$0.00"
+		}
+		axis := "│"
+		if label != "" {
+			axis = "┤"
+		}
+		lines = append(lines, fmt.Sprintf("  %7s %s", label, axis)+renderChartRow(grid[r]))
+	}
+	lines = append(lines, "  "+strings.Repeat(" ", 8)+"└"+strings.Repeat("─", width))
+
+	// Time labels at the start, middle and end of the axis.
+	axisLabels := []rune(strings.Repeat(" ", width+2))
+	place := func(col int, text string) {
+		n := len([]rune(text))
+		col = min(max(col-n/2, 0), len(axisLabels)-n)
+		if col < 0 {
+			return
+		}
+		for i := max(col-1, 0); i < min(col+n+1, len(axisLabels)); i++ {
+			if axisLabels[i] != ' ' {
+				return
+			}
+		}
+		copy(axisLabels[col:], []rune(text))
+	}
+	place(0, m.formatClockMinutes(first))
+	if span > 0 {
+		place(len(axisLabels)-1, m.formatClockMinutes(last))
+		place(width/2, m.formatClockMinutes(first.Add(span/2)))
+	}
+	lines = append(lines, "  "+strings.Repeat(" ", 8)+strings.TrimRight(string(axisLabels), " "))
+
+	lines = append(lines, "  "+alertWarningStyle.Render("◆")+
+		fmt.Sprintf(" peak $%.2f/hr at %s   ", peakRate, m.formatClockMinutes(snapshots[peak].Timestamp))+
+		costRedStyle.Render("•")+" rising  "+
+		costGreenStyle.Render("•")+" falling  • flat")
+	return lines
+}
+
+// renderChartRow renders a grid row, styling runs of same-styled cells
+// together to keep escape sequences short.
+func renderChartRow(cells []rateChartCell) string {
+	var b strings.Builder
+	for i := 0; i < len(cells); {
+		j := i
+		var run strings.Builder
+		for j < len(cells) && cells[j].style == cells[i].style {
+			run.WriteRune(cells[j].ch)
+			j++
+		}
+		if cells[i].style != nil && strings.TrimSpace(run.String()) != "" {
+			b.WriteString(cells[i].style.Render(run.String()))
+		} else {
+			b.WriteString(run.String())
+		}
+		i = j
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// sparkline renders values as one block character each, scaled to the
+// largest value. Zero values show as a dot. When there are more values than
+// width, each character shows the highest value of its bucket.
+func sparkline(values []float64, width int) string {
+	var peak float64
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	return sparklineTo(values, width, peak)
+}
+
+// sparklineTo is sparkline scaled to a fixed ceiling; larger values show as
+// a full block.
+func sparklineTo(values []float64, width int, ceiling float64) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i, v := range values {
+			b := i * width / len(values)
+			buckets[b] = max(buckets[b], v)
+		}
+		values = buckets
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if v <= 0 || ceiling <= 0 {
+			sb.WriteRune('·')
+			continue
+		}
+		idx := int(v / ceiling * float64(len(chartBlocks)-1))
+		sb.WriteRune(chartBlocks[min(idx, len(chartBlocks)-1)])
+	}
+	return sb.String()
+}
+
+// renderBarChart draws values (oldest first) as vertical bars spread across
+// the detail overlay, in eighth-of-a-row steps, with the first and last
+// labels under the axis.
+func (m Model) renderBarChart(values []float64, firstLabel, lastLabel string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	width := max(m.detailContentWidth()-rateChartGutter-2, rateChartMinWidth)
+	barW := max(width/len(values), 1)
+	gap := 0
+	if barW >= 3 {
+		gap = 1
+	}
+	width = barW * len(values)
+
+	var peak float64
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var lines []string
+	for r := barChartHeight - 1; r >= 0; r-- {
+		var row strings.Builder
+		for _, v := range values {
+			eighths := 0
+			if peak > 0 {
+				eighths = int(math.Round(v/peak*barChartHeight*8)) - r*8
+			}
+			ch := ' '
+			switch {
+			case eighths >= 8:
+				ch = '█'
+			case eighths > 0:
+				ch = chartBlocks[eighths-1]
+			}
+			row.WriteString(strings.Repeat(string(ch), barW-gap) + strings.Repeat(" ", gap))
+		}
+		label := ""
+		switch r {
+		case barChartHeight - 1:
+			label = fmt.Sprintf("$%.2f", peak)
+		case 0:
+			label = "$0.00"
+		}
+		axis := "│"
+		if label != "" {
+			axis = "┤"
+		}
+		lines = append(lines, fmt.Sprintf("  %7s %s", label, axis)+
+			costYellowStyle.Render(strings.TrimRight(row.String(), " ")))
+	}
+	lines = append(lines, "  "+strings.Repeat(" ", 8)+"└"+strings.Repeat("─", width))
+
+	pad := width - gap - len([]rune(firstLabel)) - len([]rune(lastLabel))
+	if len(values) == 1 || pad < 1 {
+		lines = append(lines, "  "+strings.Repeat(" ", 9)+firstLabel)
+	} else {
+		lines = append(lines, "  "+strings.Repeat(" ", 9)+firstLabel+strings.Repeat(" ", pad)+lastLabel)
+	}
+	return lines
+}
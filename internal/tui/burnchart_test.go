@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+)
+
+func TestRenderRateChart(t *testing.T) {
+	base := time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+	var snapshots []BurnRateSnapshotRow
+	for i, rate := range []float64{1.0, 2.0, 4.5, 3.0, 0.5} {
+		trend := burnrate.TrendUp
+		if i > 2 {
+			trend = burnrate.TrendDown
+		}
+		snapshots = append(snapshots, BurnRateSnapshotRow{
+			Timestamp:  base.Add(time.Duration(i) * time.Hour),
+			HourlyRate: rate,
+			Trend:      trend,
+		})
+	}
+	m := newHistoryModel()
+	m.width = 120
+
+	lines := m.renderRateChart(snapshots)
+	plain := make([]string, len(lines))
+	for i, l := range lines {
+		plain[i] = stripAnsi(l)
+	}
+	out := strings.Join(plain, "\n")
+
+	// Chart rows, x axis, time labels, legend.
+	if len(lines) != rateChartHeight+3 {
+		t.Fatalf("expected %d lines, got %d:\n%s", rateChartHeight+3, len(lines), out)
+	}
+	if !strings.Contains(plain[0], "$4.50 ┤") {
+		t.Errorf("top row should be labelled with the peak rate:\n%s", out)
+	}
+	if !strings.Contains(plain[rateChartHeight-1], "$0.00 ┤") {
+		t.Errorf("bottom row should be labelled $0.00:\n%s", out)
+	}
+	if strings.Count(out, "◆") != 2 {
+		t.Errorf("expected the peak marker on the chart and in the legend:\n%s", out)
+	}
+	if !strings.Contains(out, "peak $4.50/hr at 11:00") {
+		t.Errorf("missing peak annotation:\n%s", out)
+	}
+	axis := plain[rateChartHeight+1]
+	if !strings.Contains(axis, "09:00") || !strings.Contains(axis, "13:00") {
+		t.Errorf("time axis should show first and last snapshot times: %q", axis)
+	}
+
+	maxW := m.detailContentWidth()
+	for _, l := range plain {
+		if w := len([]rune(l)); w > maxW {
+			t.Errorf("line wider than detail overlay (%d > %d): %q", w, maxW, l)
+		}
+	}
+}
+
+func TestRenderRateChart_SingleSnapshot(t *testing.T) {
+	m := newHistoryModel()
+	lines := m.renderRateChart([]BurnRateSnapshotRow{
+		{Timestamp: time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC), HourlyRate: 2},
+	})
+	if len(lines) == 0 || !strings.Contains(stripAnsi(strings.Join(lines, "\n")), "peak $2.00/hr at 09:00") {
+		t.Errorf("single snapshot chart should still annotate the peak: %v", lines)
+	}
+	if m.renderRateChart(nil) != nil {
+		t.Error("no snapshots should render no chart")
+	}
+}
+
+func TestRenderRateChart_TooNarrow(t *testing.T) {
+	m := newHistoryModel()
+	m.width = 40
+	snapshots := []BurnRateSnapshotRow{
+		{Timestamp: time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC), HourlyRate: 2},
+		{Timestamp: time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC), HourlyRate: 3},
+	}
+	lines := m.renderRateChart(snapshots)
+	maxW := m.detailContentWidth()
+	for _, l := range lines {
+		if w := len([]rune(stripAnsi(l))); w > maxW {
+			t.Errorf("line wider than detail overlay (%d > %d): %q", w, maxW, stripAnsi(l))
+		}
+	}
+	if len(lines) != 1 || !strings.Contains(stripAnsi(lines[0]), "Too narrow") {
+		t.Errorf("a chart that does not fit should be skipped, got %q", lines)
+	}
+}
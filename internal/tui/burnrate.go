@@ -53,9 +53,15 @@ func (m Model) renderBurnRatePanel(w, h int) string {
 	tokenLine := fmt.Sprintf("%s tokens/min", formatNumber(int64(br.TokenVelocity)))
 	lines = append(lines, dimStyle.Render(tokenLine))
 
-	// Cost projections.
-	projLine := fmt.Sprintf("Projected Spend: $%.2f/day  $%.2f/mon", br.DailyProjection, br.MonthlyProjection)
-	lines = append(lines, dimStyle.Render(projLine))
+	// Cost projections: a forecast fitted to the spend history when there
+	// is enough of it, otherwise the current rate extrapolated.
+	if f := br.Forecast; f.Valid() {
+		lines = append(lines, dimStyle.Render("Forecast day: "+formatRange(f.EndOfDay)))
+		lines = append(lines, dimStyle.Render("Forecast mon: "+formatRange(f.EndOfMonth)))
+	} else {
+		projLine := fmt.Sprintf("Projected Spend: $%.2f/day  $%.2f/mon", br.DailyProjection, br.MonthlyProjection)
+		lines = append(lines, dimStyle.Render(projLine))
+	}
 
 	// Per-model cost breakdown (shown when multiple models are present).
 	if len(br.PerModel) > 1 {
@@ -80,6 +86,18 @@ func (m Model) getBurnRate() burnrate.BurnRate {
 	return m.cachedBurnRate
 }
 
+// globalBurnRate returns the burn rate across all sessions, whether or not
+// a session is selected.
+func (m Model) globalBurnRate() burnrate.BurnRate {
+	if m.selectedSession == "" {
+		return m.cachedBurnRate
+	}
+	if m.burnRate == nil {
+		return burnrate.BurnRate{}
+	}
+	return m.burnRate.GetGlobal()
+}
+
 // computeBurnRate retrieves a fresh burn rate from the provider.
 // Called only from the tick handler to avoid per-render recalculation.
 func (m Model) computeBurnRate() burnrate.BurnRate {
@@ -92,6 +110,11 @@ func (m Model) computeBurnRate() burnrate.BurnRate {
 	return m.burnRate.GetGlobal()
 }
 
+// formatRange formats a forecast as its low to high band and likely value.
+func formatRange(r burnrate.Range) string {
+	return fmt.Sprintf("$%.2f-$%.2f (~$%.2f)", r.Low, r.High, r.Likely)
+}
+
 // getRateColor returns the color classification for a given hourly rate.
 func (m Model) getRateColor(hourlyRate float64) burnrate.RateColor {
 	if hourlyRate < m.cfg.Display.CostColorGreenBelow {
@@ -178,3 +201,31 @@ func formatNumber(n int64) string {
 	}
 	return result.String()
 }
+
+// chartBlocks are the bar heights used by renderTodayChart, lowest first.
+var chartBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// renderTodayChart renders today's cost per hour as a 24-column bar chart,
+// scaled to the busiest hour. Hours with no spend show as a dot, and hours
+// after currentHour are left blank.
+func renderTodayChart(byHour [24]float64, currentHour int) string {
+	var peak, total float64
+	for _, c := range byHour {
+		peak = max(peak, c)
+		total += c
+	}
+
+	var sb strings.Builder
+	for h, c := range byHour {
+		switch {
+		case h > currentHour:
+			sb.WriteRune(' ')
+		case c <= 0:
+			sb.WriteRune('·')
+		default:
+			idx := int(c / peak * float64(len(chartBlocks)-1))
+			sb.WriteRune(chartBlocks[idx])
+		}
+	}
+	return dimStyle.Render("Today ") + costYellowStyle.Render(sb.String()) + dimStyle.Render(fmt.Sprintf(" $%.2f", total))
+}
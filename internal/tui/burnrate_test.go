@@ -209,6 +209,39 @@ func TestRenderBurnRatePanel_WithProjections(t *testing.T) {
 	}
 }
 
+func TestRenderBurnRatePanel_WithForecast(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mockBR := &mockBurnRateProvider{
+		global: burnrate.BurnRate{
+			TotalCost:         10.00,
+			HourlyRate:        1.00,
+			DailyProjection:   24.00,
+			MonthlyProjection: 720.00,
+			Forecast: burnrate.Forecast{
+				EndOfDay:   burnrate.Range{Low: 14.00, Likely: 16.50, High: 19.25},
+				EndOfMonth: burnrate.Range{Low: 300.00, Likely: 350.00, High: 410.00},
+				Hours:      72,
+			},
+		},
+	}
+
+	m := NewModel(cfg, WithBurnRateProvider(mockBR))
+	m.width = 120
+	m.height = 40
+	m.cachedBurnRate = m.computeBurnRate()
+
+	stripped := stripAnsi(m.renderBurnRatePanel(60, 14))
+	if !strings.Contains(stripped, "Forecast day: $14.00-$19.25 (~$16.50)") {
+		t.Errorf("panel should contain the end-of-day forecast:\n%s", stripped)
+	}
+	if !strings.Contains(stripped, "Forecast mon: $300.00-$410.00 (~$350.00)") {
+		t.Errorf("panel should contain the end-of-month forecast:\n%s", stripped)
+	}
+	if strings.Contains(stripped, "$720.00/mon") {
+		t.Error("the forecast should replace the extrapolated projection")
+	}
+}
+
 func TestRenderBurnRatePanel_WithPerModel(t *testing.T) {
 	cfg := config.DefaultConfig()
 	mockBR := &mockBurnRateProvider{
@@ -310,3 +343,45 @@ func TestRenderBurnRatePanel_SessionAware(t *testing.T) {
 		t.Error("session panel should contain 'Cost (session):' label")
 	}
 }
+
+func TestRenderTodayChart(t *testing.T) {
+	var byHour [24]float64
+	byHour[2] = 1.0
+	byHour[9] = 4.0
+	byHour[10] = 2.0
+
+	got := stripAnsi(renderTodayChart(byHour, 10))
+	want := "Today ··▂······█▄"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("chart = %q, want prefix %q", got, want)
+	}
+	// Hours after the current one are blank, then the day's total.
+	if !strings.HasSuffix(got, strings.Repeat(" ", 13)+" $7.00") {
+		t.Errorf("chart should leave future hours blank and end with the total, got %q", got)
+	}
+}
+
+func TestRenderHeader_TodayChartFitsWidth(t *testing.T) {
+	var byHour [24]float64
+	byHour[0] = 1.0
+	m := NewModel(config.DefaultConfig(), WithPersistenceFlag(true))
+	m.cachedBurnRate = burnrate.BurnRate{TodayByHour: byHour}
+
+	m.width = 200
+	wide := stripAnsi(m.renderHeader(computeDimensions(m.width, 40)))
+	if !strings.Contains(wide, "Today ") || !strings.Contains(wide, "Ctrl+K:Kill") {
+		t.Errorf("wide header should show the chart and full help, got %q", wide)
+	}
+
+	m.width = 110
+	mid := stripAnsi(m.renderHeader(computeDimensions(m.width, 40)))
+	if !strings.Contains(mid, "Today ") || strings.Contains(mid, "Ctrl+K:Kill") {
+		t.Errorf("medium header should keep the chart and shorten help, got %q", mid)
+	}
+
+	m.width = 60
+	narrow := stripAnsi(m.renderHeader(computeDimensions(m.width, 40)))
+	if strings.Contains(narrow, "Today ") {
+		t.Errorf("narrow header should drop the chart, got %q", narrow)
+	}
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// detailKeyPattern matches a "Key:" label at the start of a detail line,
+// possibly indented, followed by alignment padding (two or more spaces) or
+// nothing, as in "Session:   abc" or a "Message:" heading.
+var detailKeyPattern = regexp.MustCompile(`^( *[A-Za-z][^:]{0,30}:)(  +|$)`)
+
+// styleDetailLine renders the leading label of a detail line in bold.
+func styleDetailLine(line string) string {
+	loc := detailKeyPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	label := line[:loc[3]]
+	key := strings.TrimLeft(label, " ")
+	return label[:len(label)-len(key)] + detailKeyStyle.Render(key) + line[loc[3]:]
+}
+
+// detailIndent returns the hanging indent for the continuation lines of a
+// wrapped detail line: the value column after a label, or the line's own
+// leading spaces.
+func detailIndent(line string, width int) int {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if loc := detailKeyPattern.FindStringSubmatchIndex(line); loc != nil && loc[5] < len(line) {
+		indent = loc[5]
+	}
+	if indent > width/2 {
+		return 0
+	}
+	return indent
+}
+
+// wrapDetailLine word-wraps line to width columns, indenting continuation
+// lines so wrapped values and paragraphs stay aligned. Lines that fit are
+// returned unchanged, so pre-styled lines (such as charts) pass through.
+func wrapDetailLine(line string, width int) []string {
+	if lipgloss.Width(line) <= width {
+		return []string{line}
+	}
+	indent := detailIndent(line, width)
+	pad := strings.Repeat(" ", indent)
+
+	var out []string
+	rest := []rune(line)
+	for first := true; len(rest) > 0; first = false {
+		prefix, limit := "", width
+		if !first {
+			prefix, limit = pad, width-indent
+		}
+		if len(rest) <= limit {
+			out = append(out, prefix+string(rest))
+			break
+		}
+		cut := limit
+		for i := limit; i > 0; i-- {
+			if rest[i] == ' ' && strings.TrimSpace(string(rest[:i])) != "" {
+				cut = i
+				break
+			}
+		}
+		out = append(out, prefix+strings.TrimRight(string(rest[:cut]), " "))
+		rest = []rune(strings.TrimLeft(string(rest[cut:]), " "))
+	}
+	return out
+}
+
+// prettyJSON indents v when it is a JSON object or array.
+func prettyJSON(v string) (string, bool) {
+	v = strings.TrimSpace(v)
+	if !strings.HasPrefix(v, "{") && !strings.HasPrefix(v, "[") {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(v), "", "  "); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// detailBlock returns the lines of a free-text detail value indented by
+// indent spaces, pretty-printing it when it is JSON.
+func detailBlock(v, indent string) []string {
+	if pretty, ok := prettyJSON(v); ok {
+		v = pretty
+	}
+	lines := strings.Split(v, "\n")
+	for i := range lines {
+		lines[i] = indent + lines[i]
+	}
+	return lines
+}
+
+// detailAttributes lists attrs sorted by key, one aligned "key: value" line
+// each. JSON values are pretty-printed on the following lines.
+func detailAttributes(attrs map[string]string) []string {
+	keys := make([]string, 0, len(attrs))
+	keyW := 0
+	for k := range attrs {
+		keys = append(keys, k)
+		keyW = max(keyW, len(k))
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range keys {
+		if pretty, ok := prettyJSON(attrs[k]); ok {
+			lines = append(lines, "  "+k+":")
+			lines = append(lines, detailBlock(pretty, "    ")...)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %-*s  %s", keyW+1, k+":", attrs[k]))
+	}
+	return lines
+}
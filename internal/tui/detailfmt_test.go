@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/events"
+)
+
+func TestWrapDetailLine_HangingIndent(t *testing.T) {
+	line := "Message:  " + strings.Repeat("word ", 12)
+	got := wrapDetailLine(line, 30)
+	if len(got) < 2 {
+		t.Fatalf("expected the line to wrap, got %q", got)
+	}
+	for i, l := range got {
+		if len(l) > 30 {
+			t.Errorf("line %d exceeds width: %q", i, l)
+		}
+		if i > 0 && !strings.HasPrefix(l, strings.Repeat(" ", 10)+"word") {
+			t.Errorf("continuation %d should align with the value column: %q", i, l)
+		}
+	}
+
+	if got := wrapDetailLine("short", 30); len(got) != 1 || got[0] != "short" {
+		t.Errorf("a line that fits should be unchanged, got %q", got)
+	}
+
+	long := strings.Repeat("x", 70)
+	got = wrapDetailLine(long, 30)
+	if strings.Join(got, "") != long {
+		t.Errorf("a word longer than the width should be split without loss, got %q", got)
+	}
+}
+
+func TestStyleDetailLine_KeepsText(t *testing.T) {
+	for _, line := range []string{"Session:   abc", "  tool_name:  Bash", "Attributes:", "plain text: here", "  12:30  note"} {
+		if got := stripAnsi(styleDetailLine(line)); got != line {
+			t.Errorf("styleDetailLine(%q) = %q changed the text", line, got)
+		}
+	}
+	if detailKeyPattern.MatchString("plain text: here") {
+		t.Error("a label followed by a single space should not be treated as a key")
+	}
+	if detailKeyPattern.MatchString("  12:30  note") {
+		t.Error("times should not be treated as keys")
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	got, ok := prettyJSON(`{"a":1,"b":[true]}`)
+	if !ok || !strings.Contains(got, "\n  \"a\": 1") {
+		t.Errorf("object not indented: %q (ok=%v)", got, ok)
+	}
+	for _, v := range []string{"plain", "{not json", "42"} {
+		if _, ok := prettyJSON(v); ok {
+			t.Errorf("prettyJSON(%q) should not apply", v)
+		}
+	}
+}
+
+func TestFormatEventDetail_Attributes(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	e := events.FormattedEvent{
+		SessionID: "sess-1",
+		EventType: "tool_result",
+		Formatted: "Bash ok",
+		Timestamp: time.Now(),
+		RawAttributes: map[string]string{
+			"tool_name":       "Bash",
+			"tool_parameters": `{"command":"ls -la","timeout":30}`,
+		},
+	}
+	got := m.formatEventDetail(e)
+	for _, want := range []string{"Attributes:", "  tool_name:        Bash", "  tool_parameters:\n    {\n      \"command\": \"ls -la\","} {
+		if !strings.Contains(got, want) {
+			t.Errorf("event detail missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import "github.com/nixlim/cc-top/internal/alerts"
+
+// NotificationMuter holds back alert notifications; the alert engine
+// implements it. Alerts still fire and are recorded while held.
+type NotificationMuter interface {
+	SetNotificationsMuted(muted bool)
+	// NotificationsHeld reports whether notifications are held back and
+	// why: alerts.HeldMuted or alerts.HeldQuietHours.
+	NotificationsHeld() (held bool, reason string)
+}
+
+// WithNotificationMuter enables the do-not-disturb toggle (Ctrl+N) and the
+// header indicator for muted notifications and quiet hours.
+func WithNotificationMuter(n NotificationMuter) ModelOption {
+	return func(m *Model) { m.muter = n }
+}
+
+// toggleNotificationsMuted turns do-not-disturb on or off.
+func (m *Model) toggleNotificationsMuted() {
+	if m.muter == nil {
+		return
+	}
+	m.notifyMuted = !m.notifyMuted
+	m.muter.SetNotificationsMuted(m.notifyMuted)
+}
+
+// notifyIndicator is the header label while notifications are held back.
+func (m Model) notifyIndicator() string {
+	if m.muter == nil {
+		return ""
+	}
+	switch held, reason := m.muter.NotificationsHeld(); {
+	case !held:
+		return ""
+	case reason == alerts.HeldQuietHours:
+		return "[Quiet hours]"
+	}
+	return "[DND]"
+}
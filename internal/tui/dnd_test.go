@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+type mockMuter struct {
+	muted bool
+	quiet bool
+}
+
+func (m *mockMuter) SetNotificationsMuted(muted bool) { m.muted = muted }
+
+func (m *mockMuter) NotificationsHeld() (bool, string) {
+	switch {
+	case m.muted:
+		return true, alerts.HeldMuted
+	case m.quiet:
+		return true, alerts.HeldQuietHours
+	}
+	return false, ""
+}
+
+func TestMuteNotificationsToggle(t *testing.T) {
+	muter := &mockMuter{}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(&mockStateProvider{}), WithStartView(ViewDashboard), WithNotificationMuter(muter))
+	m.width, m.height = 160, 40
+	ctrlN := tea.KeyMsg{Type: tea.KeyCtrlN}
+
+	if strings.Contains(stripAnsi(m.View()), "[DND]") {
+		t.Fatal("notifications start unmuted")
+	}
+	m = typeKeys(t, m, ctrlN)
+	if !muter.muted || !strings.Contains(stripAnsi(m.View()), "[DND]") {
+		t.Error("Ctrl+N should mute notifications and show [DND]")
+	}
+	m = typeKeys(t, m, ctrlN)
+	if muter.muted {
+		t.Error("a second Ctrl+N should unmute")
+	}
+
+	muter.quiet = true
+	if got := m.notifyIndicator(); got != "[Quiet hours]" {
+		t.Errorf("indicator during quiet hours = %q", got)
+	}
+}
@@ -1,13 +1,20 @@
 package tui
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/nixlim/cc-top/internal/events"
 )
 
+// alertEventType is the EventType of the synthetic rows that mark alert
+// firings in the event stream.
+const alertEventType = "alert"
+
 // eventTypeIcons maps event types to their display icons.
 var eventTypeIcons = map[string]string{
 	"user_prompt":   ">>",
@@ -15,16 +22,13 @@ var eventTypeIcons = map[string]string{
 	"api_request":   "AI",
 	"api_error":     "!!",
 	"tool_decision": "TD",
+	"annotation":    "NB",
+	alertEventType:  "AL",
 }
 
-// eventTypeStyles maps event types to their display styles.
-var eventTypeStyles = map[string]lipgloss.Style{
-	"user_prompt":   lipgloss.NewStyle().Foreground(lipgloss.Color("117")),
-	"tool_result":   lipgloss.NewStyle().Foreground(lipgloss.Color("222")),
-	"api_request":   lipgloss.NewStyle().Foreground(lipgloss.Color("114")),
-	"api_error":     lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
-	"tool_decision": lipgloss.NewStyle().Foreground(lipgloss.Color("183")),
-}
+// eventTypeStyles maps event types to their display styles. It is built by
+// applyPalette.
+var eventTypeStyles map[string]lipgloss.Style
 
 // renderEventStreamPanel renders the scrolling event stream panel.
 func (m Model) renderEventStreamPanel(w, h int) string {
@@ -52,7 +56,7 @@ func (m Model) renderEventStreamPanel(w, h int) string {
 	lines = append(lines, title)
 
 	// Get events from provider.
-	evts := m.getFilteredEvents(m.cfg.Display.EventBufferSize)
+	evts := m.getFilteredEvents(m.eventLimit())
 
 	if len(evts) == 0 {
 		lines = append(lines, "")
@@ -109,8 +113,11 @@ func (m Model) renderEventStreamPanel(w, h int) string {
 		endIdx = len(evts)
 	}
 
+	// Ages are computed on every render, so they keep counting up between
+	// events.
+	now := time.Now()
 	for i := startIdx; i < endIdx; i++ {
-		line := renderEventLine(evts[i], contentW)
+		line := renderTickerEventLine(evts[i], contentW, now)
 		if focused && i == m.eventCursor {
 			line = cursorStyle.Width(contentW).Render(stripAnsi(line))
 		}
@@ -137,6 +144,18 @@ func (m Model) renderEventStreamPanel(w, h int) string {
 	return renderBorderedPanelStyled(content, w, h, borderStyle)
 }
 
+// eventScrollback is how many spilled events the Events panel lists
+// behind the buffer when display.event_buffer_eviction is "spill".
+const eventScrollback = 5000
+
+// eventLimit is how many events the Events panel lists.
+func (m Model) eventLimit() int {
+	if m.cfg.Display.EventBufferEviction == "spill" && m.isPersistent {
+		return m.cfg.Display.EventBufferSize + eventScrollback
+	}
+	return m.cfg.Display.EventBufferSize
+}
+
 // getFilteredEvents returns events matching the current filter.
 func (m Model) getFilteredEvents(limit int) []events.FormattedEvent {
 	if m.events == nil {
@@ -150,17 +169,74 @@ func (m Model) getFilteredEvents(limit int) []events.FormattedEvent {
 		evts = m.events.Recent(limit)
 	}
 
+	evts = m.interleaveAlerts(evts)
+
 	// Apply event type and success/failure filters.
 	var filtered []events.FormattedEvent
 	for _, e := range evts {
-		if m.eventFilter.Matches(e.SessionID, e.EventType, e.Success) {
+		sessionID := e.SessionID
+		if e.EventType == alertEventType && sessionID == "" {
+			sessionID = m.eventFilter.SessionID // global alerts pass the session filter
+		}
+		if m.eventFilter.Matches(sessionID, e.EventType, e.Success) {
 			filtered = append(filtered, e)
 		}
 	}
 
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[len(filtered)-limit:]
+	}
 	return filtered
 }
 
+// interleaveAlerts merges alert firings into evts as marker rows placed at
+// their FiredAt timestamp. Only alerts at or after the oldest event are
+// included, so markers never pile up above the visible history.
+func (m Model) interleaveAlerts(evts []events.FormattedEvent) []events.FormattedEvent {
+	if m.alerts == nil || len(evts) == 0 {
+		return evts
+	}
+
+	oldest := evts[0].Timestamp
+	var markers []events.FormattedEvent
+	for _, a := range m.alerts.Active() {
+		if a.FiredAt.Before(oldest) {
+			continue
+		}
+		if m.eventFilter.SessionID != "" && a.SessionID != "" && a.SessionID != m.eventFilter.SessionID {
+			continue
+		}
+		markers = append(markers, events.FormattedEvent{
+			SessionID: a.SessionID,
+			EventType: alertEventType,
+			Formatted: "[" + a.Rule + "] " + a.Message,
+			Timestamp: a.FiredAt,
+			RawAttributes: map[string]string{
+				"rule":     a.Rule,
+				"severity": a.Severity,
+			},
+		})
+	}
+	if len(markers) == 0 {
+		return evts
+	}
+	sort.SliceStable(markers, func(i, j int) bool {
+		return markers[i].Timestamp.Before(markers[j].Timestamp)
+	})
+
+	// Merge; a marker follows any event with the same timestamp.
+	merged := make([]events.FormattedEvent, 0, len(evts)+len(markers))
+	j := 0
+	for _, e := range evts {
+		for j < len(markers) && markers[j].Timestamp.Before(e.Timestamp) {
+			merged = append(merged, markers[j])
+			j++
+		}
+		merged = append(merged, e)
+	}
+	return append(merged, markers[j:]...)
+}
+
 // renderEventLine formats a single event for display.
 func renderEventLine(e events.FormattedEvent, maxW int) string {
 	icon := eventTypeIcons[e.EventType]
@@ -183,6 +259,42 @@ func renderEventLine(e events.FormattedEvent, maxW int) string {
 	return style.Render(icon + " " + formatted)
 }
 
+// tickerMinWidth is the narrowest Events panel that shows the age and cost
+// columns; narrower panels keep the whole width for the event.
+const tickerMinWidth = 60
+
+// renderTickerEventLine prefixes an event with its age and, for API
+// requests, the cost it added to its session, so the Events panel reads as
+// a spend ticker: "  12s ago  +$0.0312 AI [abc123] ...".
+func renderTickerEventLine(e events.FormattedEvent, maxW int, now time.Time) string {
+	if maxW < tickerMinWidth {
+		return renderEventLine(e, maxW)
+	}
+	var cost string
+	if e.DeltaCost != nil {
+		cost = fmt.Sprintf("+$%.4f", *e.DeltaCost)
+	}
+	prefix := fmt.Sprintf("%8s %-8s ", formatAge(now.Sub(e.Timestamp)), cost)
+	return dimStyle.Render(prefix) + renderEventLine(e, maxW-len(prefix))
+}
+
+// formatAge formats how long ago something happened, e.g. "12s ago" or
+// "3h ago".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return "now"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
 // formatScrollPos returns a string like "[10-20/100]".
 func formatScrollPos(start, end, total int) string {
 	return strings.Join([]string{
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nixlim/cc-top/internal/alerts"
 	"github.com/nixlim/cc-top/internal/config"
 	"github.com/nixlim/cc-top/internal/events"
 )
@@ -84,6 +85,22 @@ func TestRenderEventStreamPanel_FilteredBySession(t *testing.T) {
 	}
 }
 
+func TestEventLimit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.EventBufferSize = 200
+	if got := NewModel(cfg).eventLimit(); got != 200 {
+		t.Errorf("drop: eventLimit = %d, want the buffer size", got)
+	}
+
+	cfg.Display.EventBufferEviction = "spill"
+	if got := NewModel(cfg).eventLimit(); got != 200 {
+		t.Errorf("spill without persistence: eventLimit = %d, want the buffer size", got)
+	}
+	if got := NewModel(cfg, WithPersistenceFlag(true)).eventLimit(); got != 200+eventScrollback {
+		t.Errorf("spill: eventLimit = %d, want %d", got, 200+eventScrollback)
+	}
+}
+
 func TestRenderEventLine(t *testing.T) {
 	boolTrue := true
 	tests := []struct {
@@ -260,3 +277,117 @@ func TestEventFilter_Matches(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFilteredEvents_InterleavesAlerts(t *testing.T) {
+	base := time.Now().Add(-time.Minute)
+	mockEvents := &mockEventProvider{
+		events: []events.FormattedEvent{
+			{SessionID: "sess-001", EventType: "user_prompt", Formatted: "prompt", Timestamp: base},
+			{SessionID: "sess-001", EventType: "tool_result", Formatted: "Bash", Timestamp: base.Add(20 * time.Second)},
+			{SessionID: "sess-002", EventType: "api_request", Formatted: "api", Timestamp: base.Add(40 * time.Second)},
+		},
+	}
+	mockAlerts := &mockAlertProvider{
+		alerts: []alerts.Alert{
+			{Rule: "CostSurge", Severity: "warning", Message: "surge", FiredAt: base.Add(30 * time.Second)},
+			{Rule: "LoopDetector", Severity: "warning", Message: "loop", SessionID: "sess-002", FiredAt: base.Add(10 * time.Second)},
+			{Rule: "StaleSession", Severity: "warning", Message: "old", FiredAt: base.Add(-time.Hour)},
+		},
+	}
+	m := NewModel(config.DefaultConfig(), WithEventProvider(mockEvents), WithAlertProvider(mockAlerts))
+
+	got := m.getFilteredEvents(100)
+	var order []string
+	for _, e := range got {
+		order = append(order, e.Formatted)
+	}
+	want := "prompt,[LoopDetector] loop,Bash,[CostSurge] surge,api"
+	if strings.Join(order, ",") != want {
+		t.Errorf("order = %s, want %s", strings.Join(order, ","), want)
+	}
+
+	// Session filter keeps global alerts but drops other sessions' alerts.
+	m.eventFilter.SessionID = "sess-001"
+	order = nil
+	for _, e := range m.getFilteredEvents(100) {
+		order = append(order, e.Formatted)
+	}
+	want = "prompt,Bash,[CostSurge] surge"
+	if strings.Join(order, ",") != want {
+		t.Errorf("filtered order = %s, want %s", strings.Join(order, ","), want)
+	}
+
+	// Alert markers can be toggled off via the event type filter.
+	m.eventFilter = NewEventFilter()
+	m.eventFilter.EventTypes[alertEventType] = false
+	for _, e := range m.getFilteredEvents(100) {
+		if e.EventType == alertEventType {
+			t.Error("alert markers should be hidden when filtered out")
+		}
+	}
+}
+
+func TestGetFilteredEvents_KeepsAcknowledgedAlerts(t *testing.T) {
+	base := time.Now().Add(-time.Minute)
+	mockEvents := &mockEventProvider{
+		events: []events.FormattedEvent{
+			{SessionID: "sess-001", EventType: "user_prompt", Formatted: "prompt", Timestamp: base},
+		},
+	}
+	mockAlerts := &mockAlertProvider{
+		alerts: []alerts.Alert{
+			{Rule: "CostSurge", Severity: "warning", Message: "surge", SessionID: "sess-001", FiredAt: base.Add(10 * time.Second)},
+		},
+	}
+	m := NewModel(config.DefaultConfig(), WithEventProvider(mockEvents), WithAlertProvider(mockAlerts), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+
+	// Acknowledging takes the alert off the alert bar only.
+	m = typeKeys(t, m, runes("a"), runes("x"))
+	if bar := m.getActiveAlerts(); len(bar) != 0 {
+		t.Fatalf("acknowledged alert should leave the alert bar, got %+v", bar)
+	}
+	var order []string
+	for _, e := range m.getFilteredEvents(100) {
+		order = append(order, e.Formatted)
+	}
+	if want := "prompt,[CostSurge] surge"; strings.Join(order, ",") != want {
+		t.Errorf("order = %s, want %s", strings.Join(order, ","), want)
+	}
+}
+
+func TestRenderEventLine_AlertMarker(t *testing.T) {
+	line := renderEventLine(events.FormattedEvent{
+		EventType: alertEventType,
+		Formatted: "[CostSurge] surge",
+	}, 80)
+	if !strings.Contains(line, "AL [CostSurge] surge") {
+		t.Errorf("alert marker line = %q", line)
+	}
+}
+
+func TestRenderTickerEventLine(t *testing.T) {
+	now := time.Now()
+	cost := 0.0312
+	api := events.FormattedEvent{
+		EventType: "api_request",
+		Formatted: "[sess] sonnet",
+		Timestamp: now.Add(-12 * time.Second),
+		DeltaCost: &cost,
+	}
+	if line := stripAnsi(renderTickerEventLine(api, 80, now)); line != " 12s ago +$0.0312 AI [sess] sonnet" {
+		t.Errorf("api_request line = %q", line)
+	}
+
+	prompt := events.FormattedEvent{EventType: "user_prompt", Formatted: "[sess] Prompt", Timestamp: now.Add(-3 * time.Hour)}
+	if line := stripAnsi(renderTickerEventLine(prompt, 80, now)); line != "  3h ago          >> [sess] Prompt" {
+		t.Errorf("user_prompt line = %q", line)
+	}
+	// Ages follow the render time, not the time the event arrived.
+	if line := stripAnsi(renderTickerEventLine(api, 80, now.Add(time.Minute))); !strings.HasPrefix(line, "  1m ago") {
+		t.Errorf("age should be recomputed on render, got %q", line)
+	}
+	if line := stripAnsi(renderTickerEventLine(api, 40, now)); line != "AI [sess] sonnet" {
+		t.Errorf("narrow panels should leave out the ticker columns, got %q", line)
+	}
+}
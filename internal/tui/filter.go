@@ -23,6 +23,8 @@ func AllEventTypes() map[string]bool {
 		"api_request":   true,
 		"api_error":     true,
 		"tool_decision": true,
+		"annotation":    true,
+		alertEventType:  true,
 	}
 }
 
@@ -81,6 +83,8 @@ func NewFilterMenu() FilterMenuState {
 			{Label: "API Requests", Key: "api_request", Enabled: true},
 			{Label: "API Errors", Key: "api_error", Enabled: true},
 			{Label: "Tool Decisions", Key: "tool_decision", Enabled: true},
+			{Label: "Annotations", Key: "annotation", Enabled: true},
+			{Label: "Alert Markers", Key: alertEventType, Enabled: true},
 			{Label: "Success Only", Key: "success_only", Enabled: false},
 			{Label: "Failure Only", Key: "failure_only", Enabled: false},
 		},
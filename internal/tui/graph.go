@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// graphSampleEvery is the spacing of the burn rate samples kept for
+	// the graph overlay; ticks in between are not recorded.
+	graphSampleEvery = 2 * time.Second
+
+	graphChartHeight = 8
+)
+
+// graphWindows are the time spans the graph overlay cycles through (w).
+var graphWindows = []time.Duration{5 * time.Minute, 15 * time.Minute, 30 * time.Minute, time.Hour}
+
+// Graph series, cycled with s.
+const (
+	graphBoth = iota
+	graphCost
+	graphTokens
+	graphSeriesCount
+)
+
+// rateSample is the global burn rate at one tick.
+type rateSample struct {
+	at            time.Time
+	hourlyRate    float64
+	tokenVelocity float64
+}
+
+// recordRateSample keeps the global burn rate for the graph overlay,
+// dropping samples older than the longest graph window.
+func (m *Model) recordRateSample(now time.Time) {
+	if n := len(m.rateSamples); n > 0 && now.Sub(m.rateSamples[n-1].at) < graphSampleEvery {
+		return
+	}
+	br := m.globalBurnRate()
+	cutoff := now.Add(-graphWindows[len(graphWindows)-1])
+	i := 0
+	for i < len(m.rateSamples) && m.rateSamples[i].at.Before(cutoff) {
+		i++
+	}
+	// Copy rather than reslice so the oldest samples can be collected.
+	m.rateSamples = append(m.rateSamples[:0:0], m.rateSamples[i:]...)
+	m.rateSamples = append(m.rateSamples, rateSample{at: now, hourlyRate: br.HourlyRate, tokenVelocity: br.TokenVelocity})
+}
+
+func (m Model) handleGraphKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Graph):
+		m.graphOverlay = false
+	case key.Matches(msg, m.keys.GraphSeries):
+		m.graphSeries = (m.graphSeries + 1) % graphSeriesCount
+	case key.Matches(msg, m.keys.GraphWindow):
+		m.graphWindow = (m.graphWindow + 1) % len(graphWindows)
+	}
+	return m, nil
+}
+
+// overlayGraph renders the cost/hour and tokens/minute of the last graph
+// window as line charts, centred over base.
+func (m Model) overlayGraph(base string, now time.Time) string {
+	window := graphWindows[m.graphWindow]
+	width := m.rateChartWidth()
+
+	var samples []rateSample
+	for _, s := range m.rateSamples {
+		if !s.at.Before(now.Add(-window)) {
+			samples = append(samples, s)
+		}
+	}
+
+	lines := []string{panelTitleStyle.Render("Burn Rate Graph") + dimStyle.Render(" (global, last "+formatDuration(window)+")"), ""}
+	switch {
+	case width == 0:
+		lines = append(lines, rateChartTooNarrow()...)
+	case len(samples) < 2:
+		lines = append(lines, dimStyle.Render("  Collecting samples..."))
+	default:
+		if m.graphSeries != graphTokens {
+			lines = append(lines, "  Cost $/hr")
+			lines = append(lines, m.renderSeriesChart(samples, now.Add(-window), now, width,
+				func(s rateSample) float64 { return s.hourlyRate },
+				func(v float64) string { return fmt.Sprintf("$%.2f", v) }, costYellowStyle)...)
+		}
+		if m.graphSeries == graphBoth {
+			lines = append(lines, "")
+		}
+		if m.graphSeries != graphCost {
+			lines = append(lines, "  Tokens/min")
+			lines = append(lines, m.renderSeriesChart(samples, now.Add(-window), now, width,
+				func(s rateSample) float64 { return s.tokenVelocity },
+				func(v float64) string { return formatNumber(int64(v)) }, costGreenStyle)...)
+		}
+	}
+	lines = append(lines, "", dimStyle.Render("s: Series  w: Window  Esc/G: Close"))
+
+	dialog := detailOverlayStyle.
+		Width(m.detailOverlayWidth() - 2).
+		Render(strings.Join(lines, "\n"))
+	return placeOverlay(0, 0, dialog, base)
+}
+
+// renderSeriesChart draws value over [from, to] as a line chart width
+// columns wide. Each column shows the highest sample it covers, and columns
+// without a sample are joined to their neighbours.
+func (m Model) renderSeriesChart(samples []rateSample, from, to time.Time, width int,
+	value func(rateSample) float64, label func(float64) string, style lipgloss.Style) []string {
+	span := to.Sub(from)
+	values := make([]float64, width)
+	filled := make([]bool, width)
+	var peak float64
+	for _, s := range samples {
+		col := min(max(int(float64(s.at.Sub(from))/float64(span)*float64(width-1)), 0), width-1)
+		v := value(s)
+		if !filled[col] || v > values[col] {
+			values[col], filled[col] = v, true
+		}
+		peak = max(peak, v)
+	}
+
+	rowOf := func(v float64) int {
+		if peak <= 0 {
+			return 0
+		}
+		return min(max(int(math.Round(v/peak*float64(graphChartHeight-1))), 0), graphChartHeight-1)
+	}
+
+	grid := make([][]rateChartCell, graphChartHeight)
+	for r := range grid {
+		grid[r] = make([]rateChartCell, width)
+		for c := range grid[r] {
+			grid[r][c] = rateChartCell{ch: ' '}
+		}
+	}
+	prev := -1
+	for c := range width {
+		if !filled[c] {
+			continue
+		}
+		row := rowOf(values[c])
+		if prev >= 0 {
+			for g := prev + 1; g < c; g++ {
+				v := values[prev] + (values[c]-values[prev])*float64(g-prev)/float64(c-prev)
+				grid[rowOf(v)][g] = rateChartCell{ch: '·', style: &style}
+			}
+			prevRow := rowOf(values[prev])
+			for r := min(prevRow, row) + 1; r < max(prevRow, row); r++ {
+				grid[r][c] = rateChartCell{ch: '│', style: &style}
+			}
+		}
+		grid[row][c] = rateChartCell{ch: '•', style: &style}
+		prev = c
+	}
+
+	var lines []string
+	for r := graphChartHeight - 1; r >= 0; r-- {
+		text := ""
+		switch r {
+		case graphChartHeight - 1:
+			text = label(peak)
+		case (graphChartHeight - 1) / 2:
+			text = label(peak / 2)
+		case 0:
+			text = label(0)
+		}
+		axis := "│"
+		if text != "" {
+			axis = "┤"
+		}
+		lines = append(lines, fmt.Sprintf("  %7s %s", text, axis)+renderChartRow(grid[r]))
+	}
+	lines = append(lines, "  "+strings.Repeat(" ", 8)+"└"+strings.Repeat("─", width))
+
+	start, end := m.formatClock(from), m.formatClock(to)
+	pad := max(width-len(start)-len(end), 1)
+	lines = append(lines, "  "+strings.Repeat(" ", 9)+start+strings.Repeat(" ", pad)+end)
+	return lines
+}
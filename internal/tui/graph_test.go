@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestRecordRateSample(t *testing.T) {
+	br := &mockBurnRateProvider{global: burnrate.BurnRate{HourlyRate: 3, TokenVelocity: 500}}
+	m := NewModel(config.DefaultConfig(), WithBurnRateProvider(br))
+	m.cachedBurnRate = br.global
+
+	now := time.Now()
+	m.recordRateSample(now)
+	m.recordRateSample(now.Add(time.Second))
+	if len(m.rateSamples) != 1 {
+		t.Fatalf("samples closer than %v should be skipped, got %d", graphSampleEvery, len(m.rateSamples))
+	}
+
+	// The graph stays global while a session is selected.
+	m.selectedSession, m.cachedBurnRate = "s1", burnrate.BurnRate{HourlyRate: 99}
+	m.recordRateSample(now.Add(2 * time.Hour))
+	if len(m.rateSamples) != 1 || m.rateSamples[0].hourlyRate != 3 {
+		t.Errorf("samples = %+v, want one global sample, older ones pruned", m.rateSamples)
+	}
+}
+
+func TestGraphOverlay(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStateProvider(&mockStateProvider{}), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 50
+
+	m = typeKeys(t, m, runes("G"))
+	if !m.graphOverlay {
+		t.Fatal("G should open the graph")
+	}
+	if got := stripAnsi(m.View()); !strings.Contains(got, "Collecting samples") {
+		t.Errorf("graph without samples:\n%s", got)
+	}
+
+	now := time.Now()
+	for i := range 10 {
+		m.rateSamples = append(m.rateSamples, rateSample{
+			at: now.Add(time.Duration(i-9) * 20 * time.Second), hourlyRate: float64(i), tokenVelocity: float64(i * 1000),
+		})
+	}
+	got := stripAnsi(m.overlayGraph(m.renderDashboard(), now))
+	for _, want := range []string{"last 5m", "Cost $/hr", "$9.00 ┤", "Tokens/min", "9,000 ┤", "•"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("graph should contain %q:\n%s", want, got)
+		}
+	}
+
+	m = typeKeys(t, m, runes("s"), runes("w"))
+	got = stripAnsi(m.overlayGraph(m.renderDashboard(), now))
+	if m.graphSeries != graphCost || !strings.Contains(got, "last 15m") || strings.Contains(got, "Tokens/min") {
+		t.Errorf("s should show cost only, w the next window:\n%s", got)
+	}
+
+	m.width = 40
+	got = stripAnsi(m.overlayGraph(m.renderDashboard(), now))
+	if !strings.Contains(got, "Too narrow for the chart") || strings.Contains(got, "┤") {
+		t.Errorf("a graph that does not fit should be skipped:\n%s", got)
+	}
+	m.width = 120
+
+	m = typeKeys(t, m, runes("G"))
+	if m.graphOverlay {
+		t.Error("G should close the graph")
+	}
+}
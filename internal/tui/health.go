@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/scanner"
+)
+
+const (
+	// healthSummaryFor is how long after launch the header shows the full
+	// health summary. Afterwards it shows only components with a problem.
+	healthSummaryFor = 30 * time.Second
+
+	// noTelemetryGrace is how long Claude Code may run with telemetry on
+	// before the receiver is reported as getting no data.
+	noTelemetryGrace = 2 * time.Minute
+)
+
+// healthCheck is one component of the header's health summary, e.g.
+// "receiver:ok". Problems carry a hint pointing at the cc-top doctor
+// section that diagnoses them.
+type healthCheck struct {
+	name, status string
+	problem      bool
+	hint         string
+}
+
+// WithNotifyStatus sets a function reporting whether alert notifications
+// work: "off", "untested" before the first alert, or "ok".
+func WithNotifyStatus(fn func() string) ModelOption {
+	return func(m *Model) { m.notifyStatus = fn }
+}
+
+// healthChecks checks the receiver, scanner, database and notifications,
+// and flags sessions exported by more than one process.
+func (m Model) healthChecks(now time.Time) []healthCheck {
+	var waiting, misconfigured int
+	if m.scanner != nil && !m.scannerDisabled {
+		for _, p := range m.scanner.Processes() {
+			switch m.scanner.GetTelemetryStatus(p).Status {
+			case scanner.TelemetryWaiting:
+				waiting++
+			case scanner.TelemetryOff, scanner.TelemetryConsoleOnly, scanner.TelemetryWrongPort:
+				misconfigured++
+			}
+		}
+	}
+
+	receiver := healthCheck{name: "receiver", status: "ok"}
+	if snap := m.currentSnapshot(); snap == nil || len(snap.Sessions) == 0 {
+		receiver.status = "waiting"
+		if waiting > 0 && now.Sub(m.launchedAt) >= noTelemetryGrace {
+			receiver = healthCheck{name: "receiver", status: "no data", problem: true,
+				hint: "Claude Code is running but nothing arrives; see cc-top doctor (Receiver)"}
+		}
+	}
+
+	sc := healthCheck{name: "scanner", status: "ok"}
+	switch {
+	case m.scannerDisabled || m.scanner == nil:
+		sc.status = "off"
+	case misconfigured > 0:
+		sc = healthCheck{name: "scanner", status: fmt.Sprintf("%d without telemetry", misconfigured), problem: true,
+			hint: "run cc-top -setup and restart them; see cc-top doctor (Process scanner)"}
+	}
+
+	db := healthCheck{name: "db", status: "ok"}
+	switch {
+	case !m.isPersistent:
+		db.status = "off"
+	case m.state != nil && m.state.DroppedWrites() > 0:
+		db = healthCheck{name: "db", status: "dropping writes", problem: true,
+			hint: "raise [storage] channel_size and batch_size"}
+	case m.writerStats != nil && m.writerStats.WriterStats(now).Backlogged(now):
+		db = healthCheck{name: "db", status: "write queue backlogged", problem: true,
+			hint: "writes will be dropped soon; see the Write Queue in the Stats view"}
+	}
+
+	checks := []healthCheck{receiver, sc, db}
+	if dups := m.duplicateExporters(); dups > 0 {
+		checks = append(checks, healthCheck{name: "exporters", status: fmt.Sprintf("%d duplicate session IDs", dups), problem: true,
+			hint: "several processes send the same session.id; see the session's PID line"})
+	}
+	if m.notifyStatus != nil {
+		checks = append(checks, healthCheck{name: "notify", status: m.notifyStatus()})
+	}
+	return checks
+}
+
+// healthSummary renders the health checks for the header, with the first
+// problem's hint. Unless full, only problems are listed. It is empty when
+// there is nothing to report.
+func (m Model) healthSummary(now time.Time, full bool) string {
+	var parts []string
+	var hint string
+	for _, c := range m.healthChecks(now) {
+		if !full && !c.problem {
+			continue
+		}
+		part := c.name + ":" + c.status
+		if c.problem {
+			part = alertWarningStyle.Render(part)
+			if hint == "" {
+				hint = c.hint
+			}
+		} else {
+			part = dimStyle.Render(part)
+		}
+		parts = append(parts, part)
+	}
+	if hint != "" {
+		parts = append(parts, dimStyle.Render("("+hint+")"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// duplicateExporters counts running sessions that more than one process
+// exports telemetry under.
+func (m Model) duplicateExporters() int {
+	snap := m.currentSnapshot()
+	if snap == nil {
+		return 0
+	}
+	n := 0
+	for _, s := range snap.Sessions {
+		if len(s.DuplicatePIDs) > 0 && !s.Exited {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/scanner"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// droppingStateProvider reports dropped database writes.
+type droppingStateProvider struct {
+	mockStateProvider
+}
+
+func (droppingStateProvider) DroppedWrites() int64 { return 3 }
+
+func TestHealthSummary(t *testing.T) {
+	sp := &mockScannerProvider{
+		processes: []scanner.ProcessInfo{{PID: 1}},
+		statuses:  map[int]scanner.StatusInfo{1: {Status: scanner.TelemetryWaiting}},
+	}
+	m := NewModel(config.DefaultConfig(),
+		WithStateProvider(&mockStateProvider{}),
+		WithScannerProvider(sp),
+		WithPersistenceFlag(true),
+		WithNotifyStatus(func() string { return "untested" }))
+	now := m.launchedAt.Add(time.Second)
+
+	got := stripAnsi(m.healthSummary(now, true))
+	if got != "receiver:waiting scanner:ok db:ok notify:untested" {
+		t.Errorf("full summary = %q", got)
+	}
+	if got := m.healthSummary(now, false); got != "" {
+		t.Errorf("no problems yet, got %q", stripAnsi(got))
+	}
+
+	// Claude Code runs with telemetry on, but nothing arrives.
+	got = stripAnsi(m.healthSummary(m.launchedAt.Add(noTelemetryGrace), false))
+	if !strings.HasPrefix(got, "receiver:no data (") || !strings.Contains(got, "cc-top doctor (Receiver)") {
+		t.Errorf("problem summary = %q", got)
+	}
+
+	// Sessions with telemetry off and dropped writes.
+	sp.statuses[1] = scanner.StatusInfo{Status: scanner.TelemetryOff}
+	m.state = &droppingStateProvider{mockStateProvider{sessions: []state.SessionData{{SessionID: "s1"}}}}
+	got = stripAnsi(m.healthSummary(now, false))
+	if !strings.HasPrefix(got, "scanner:1 without telemetry db:dropping writes (run cc-top -setup") {
+		t.Errorf("problem summary = %q", got)
+	}
+	if !strings.Contains(stripAnsi(m.headerIndicators()), "db:dropping writes") {
+		t.Error("problems should show in the header indicators")
+	}
+}
+
+func TestHealthSummary_DuplicateExporters(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStateProvider(&mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "s1", PID: 100, DuplicatePIDs: []int{200}},
+		{SessionID: "s2", PID: 300, DuplicatePIDs: []int{400}, Exited: true},
+	}}))
+	got := stripAnsi(m.healthSummary(m.launchedAt.Add(time.Second), false))
+	if !strings.HasPrefix(got, "exporters:1 duplicate session IDs (") {
+		t.Errorf("problem summary = %q", got)
+	}
+
+	detail := stripAnsi(m.formatSessionDetail(state.SessionData{SessionID: "s1", PID: 100, DuplicatePIDs: []int{200, 201}}))
+	if !strings.Contains(detail, "also exported by PID 200, 201") {
+		t.Errorf("session detail should name the other exporters:\n%s", detail)
+	}
+}
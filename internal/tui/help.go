@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpContext returns a short label describing where the help overlay was
+// opened from, shown as the overlay subtitle.
+func (m Model) helpContext() string {
+	switch {
+	case m.replay != nil:
+		return "Replay"
+	case m.detailOverlay:
+		return "Detail"
+	case m.filterMenu.Active, m.historyFilterMenu.Active:
+		return "Filter Menu"
+	case m.sessionSortMenu.Active:
+		return "Sort Menu"
+	case m.graphOverlay:
+		return "Graph"
+	case m.tune != nil:
+		return "Threshold Tuning"
+	}
+
+	switch m.view {
+	case ViewStartup:
+		return "Startup"
+	case ViewStats:
+		return "Stats"
+	case ViewHistory:
+		return "History"
+	case ViewProjects:
+		return "Projects"
+	}
+
+	switch m.panelFocus {
+	case FocusEvents:
+		return "Dashboard: Events"
+	case FocusAlerts:
+		return "Dashboard: Alerts"
+	default:
+		return "Dashboard: Sessions"
+	}
+}
+
+// helpBindings returns the key bindings that are handled in the current
+// view and focus. The list is built from m.keys so the help overlay always
+// reflects the bindings actually matched in handleKey.
+func (m Model) helpBindings() []key.Binding {
+	k := m.keys
+
+	switch {
+	case m.replay != nil:
+		return []key.Binding{k.ReplayPause, k.ReplayFaster, k.ReplaySlower, k.ReplayNext, k.ReplayPrev, k.Escape, k.Help}
+	case m.detailOverlay:
+		return []key.Binding{k.Up, k.Down, k.ScrollUp, k.ScrollDown, k.Escape, k.Help}
+	case m.filterMenu.Active, m.historyFilterMenu.Active, m.sessionSortMenu.Active:
+		return []key.Binding{k.Up, k.Down, k.Enter, k.Escape, k.Help}
+	case m.graphOverlay:
+		return []key.Binding{k.GraphSeries, k.GraphWindow, k.Escape, k.Help}
+	case m.tune != nil:
+		return []key.Binding{k.Up, k.Down, k.TuneLower, k.TuneRaise, k.Escape, k.Help}
+	}
+
+	switch m.view {
+	case ViewStartup:
+		if m.scannerDisabled {
+			return []key.Binding{k.Enter, k.Enable, k.Help, k.Quit}
+		}
+		return []key.Binding{k.Up, k.Down, k.ToggleGroup, k.Enter, k.Enable, k.Fix, k.Rescan, k.Help, k.Quit}
+
+	case ViewStats:
+		if m.scannerDisabled {
+			return []key.Binding{k.Up, k.Down, k.ModelFamily, k.Tab, k.Help, k.Quit}
+		}
+		return []key.Binding{k.Up, k.Down, k.ModelFamily, k.Tab, k.KillSwitch, k.Help, k.Quit}
+
+	case ViewProjects:
+		return []key.Binding{k.Up, k.Down, k.Tab, k.Help, k.Quit}
+
+	case ViewHistory:
+		bindings := []key.Binding{k.Up, k.Down, k.Enter, k.HistorySection}
+		switch m.historySection {
+		case 3:
+			bindings = append(bindings, k.HistoryFilter, k.AlertNote)
+		case 4:
+			bindings = append(bindings, k.PrevDay, k.NextDay, k.HistoryFilter)
+		default:
+			bindings = append(bindings, k.Daily, k.Weekly, k.Monthly)
+		}
+		return append(bindings, k.Tab, k.Help, k.Quit)
+	}
+
+	var bindings []key.Binding
+	switch m.panelFocus {
+	case FocusEvents:
+		bindings = []key.Binding{k.Up, k.Down, k.Enter, k.Escape, k.FocusAlerts}
+	case FocusAlerts:
+		bindings = []key.Binding{k.Up, k.Down, k.Enter, k.AckAlert, k.SnoozeAlert, k.Escape, k.FocusEvents}
+		if m.history != nil {
+			bindings = append(bindings, k.AlertNote)
+		}
+		if m.tuner != nil {
+			bindings = append(bindings, k.TuneThresholds)
+		}
+	default:
+		bindings = []key.Binding{k.Up, k.Down, k.Enter, k.Escape, k.ScrollUp, k.ScrollDown, k.SessionSearch, k.SessionSort, k.Replay, k.FocusAlerts, k.FocusEvents}
+		if m.sla != nil {
+			bindings = append(bindings, k.SLATimer)
+		}
+		if m.tagger != nil {
+			bindings = append(bindings, k.TagSession)
+		}
+	}
+	bindings = append(bindings, k.Filter, k.Graph)
+	if m.muter != nil {
+		bindings = append(bindings, k.MuteNotifications)
+	}
+	if !m.scannerDisabled {
+		bindings = append(bindings, k.KillSwitch)
+	}
+	return append(bindings, k.Tab, k.Help, k.Quit)
+}
+
+// overlayHelp renders the key binding reference for the current context
+// centred over base.
+func (m Model) overlayHelp(base string) string {
+	// The screen dump key works everywhere.
+	bindings := append(m.helpBindings(), m.keys.ScreenDump)
+
+	keyW := 0
+	for _, b := range bindings {
+		if w := lipgloss.Width(b.Help().Key); w > keyW {
+			keyW = w
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(panelTitleStyle.Render("Keys — " + m.helpContext()))
+	sb.WriteString("\n\n")
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		h := b.Help()
+		sb.WriteString(fmt.Sprintf("%-*s  %s\n", keyW, h.Key, h.Desc))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render("?/Esc: Close"))
+
+	dialog := filterMenuStyle.Render(sb.String())
+	return placeOverlay(0, 0, dialog, base)
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func helpKeys(m Model) []string {
+	var keys []string
+	for _, b := range m.helpBindings() {
+		keys = append(keys, b.Help().Key)
+	}
+	return keys
+}
+
+func containsKey(keys []string, k string) bool {
+	for _, s := range keys {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHelpOverlay_ToggleWithQuestionMark(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard))
+	m.width = 120
+	m.height = 40
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = result.(Model)
+	if !m.helpOverlay {
+		t.Fatal("'?' should open the help overlay")
+	}
+	if !strings.Contains(m.View(), "Keys — Dashboard: Sessions") {
+		t.Error("help overlay should render the dashboard context title")
+	}
+
+	// Other keys are swallowed while help is open.
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = result.(Model)
+	if m.filterMenu.Active {
+		t.Error("filter menu should not open while help overlay is shown")
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if m.helpOverlay {
+		t.Error("Esc should close the help overlay")
+	}
+}
+
+func TestHelpBindings_ContextSensitive(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard))
+
+	keys := helpKeys(m)
+	if !containsKey(keys, "f") || !containsKey(keys, "ctrl+k") {
+		t.Errorf("dashboard help should list filter and kill switch, got %v", keys)
+	}
+
+	m.panelFocus = FocusEvents
+	keys = helpKeys(m)
+	if containsKey(keys, "e") {
+		t.Errorf("events focus help should not list 'e', got %v", keys)
+	}
+
+	m.detailOverlay = true
+	keys = helpKeys(m)
+	if containsKey(keys, "f") || containsKey(keys, "tab") {
+		t.Errorf("detail overlay help should only list overlay keys, got %v", keys)
+	}
+
+	h := NewModel(config.DefaultConfig(), WithStartView(ViewHistory))
+	keys = helpKeys(h)
+	if !containsKey(keys, "w") || containsKey(keys, "/") {
+		t.Errorf("history overview help should list granularity keys only, got %v", keys)
+	}
+	h.historySection = 3
+	keys = helpKeys(h)
+	if containsKey(keys, "w") || !containsKey(keys, "/") {
+		t.Errorf("history alerts help should list the rule filter only, got %v", keys)
+	}
+}
+
+func TestHelpBindings_MatchKeyMap(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewStartup))
+	km := DefaultKeyMap()
+	for _, b := range m.helpBindings() {
+		if len(b.Keys()) == 0 {
+			t.Errorf("help binding %q has no keys", b.Help().Key)
+		}
+	}
+	if !containsKey(helpKeys(m), km.Rescan.Help().Key) {
+		t.Error("startup help should include the rescan binding from the key map")
+	}
+}
+
+func TestHistoryKeys_GranularityIgnoredOnAlerts(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewHistory))
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m = result.(Model)
+	if m.historyGranularity != "weekly" {
+		t.Errorf("granularity = %q, want weekly", m.historyGranularity)
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}})
+	m = result.(Model)
+	if m.historySection != 3 {
+		t.Fatalf("section = %d, want 3", m.historySection)
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = result.(Model)
+	if m.historyGranularity != "weekly" {
+		t.Errorf("granularity changed on Alerts sub-tab: %q", m.historyGranularity)
+	}
+}
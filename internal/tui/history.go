@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -69,21 +71,31 @@ func (m Model) renderHistoryHeader() string {
 		{"2", "Performance"},
 		{"3", "Burn Rate"},
 		{"4", "Alerts"},
-	}
-	var tabParts []string
-	for i, t := range tabs {
-		label := fmt.Sprintf("[%s] %s", t.key, t.label)
-		if i != m.historySection {
-			label = dimStyle.Render(label)
+		{"5", "Events"},
+	}
+	// On narrow terminals the key hints are dropped first, then only the
+	// current granularity and tab are named.
+	renderTabs := func(compact bool) string {
+		var tabParts []string
+		for i, t := range tabs {
+			label := fmt.Sprintf("[%s] %s", t.key, t.label)
+			if i != m.historySection {
+				if compact {
+					label = "[" + t.key + "]"
+				}
+				label = dimStyle.Render(label)
+			}
+			tabParts = append(tabParts, label)
+		}
+		return "  " + strings.Join(tabParts, "  ")
+	}
+	renderMode := func(compact bool) string {
+		switch m.historySection {
+		case 3:
+			return "  |  /:Filter"
+		case 4:
+			return "  |  ←/→:Day  /:Filter"
 		}
-		tabParts = append(tabParts, label)
-	}
-	tabSection := "  " + strings.Join(tabParts, "  ")
-
-	var modeSection string
-	if m.historySection == 3 {
-		modeSection = "  |  /:Filter"
-	} else {
 		granularities := []struct {
 			key   string
 			label string
@@ -97,17 +109,32 @@ func (m Model) renderHistoryHeader() string {
 		for _, g := range granularities {
 			label := fmt.Sprintf("[%s]%s", g.key, g.label)
 			if m.historyGranularity != g.value {
+				if compact {
+					label = "[" + g.key + "]"
+				}
 				label = dimStyle.Render(label)
 			}
 			gParts = append(gParts, label)
 		}
-		modeSection = "  |  " + strings.Join(gParts, " / ")
+		return "  |  " + strings.Join(gParts, " / ")
 	}
 
+	tabSection, modeSection := renderTabs(false), renderMode(false)
 	indicators := m.headerIndicators()
 	help := "  |  Tab:Dashboard  q:Quit "
 
 	rawContent := title + tabSection + modeSection + indicators + help
+	for step := 0; step < 3 && lipgloss.Width(rawContent) > m.width; step++ {
+		switch step {
+		case 0:
+			help = " "
+		case 1:
+			modeSection = renderMode(true)
+		case 2:
+			tabSection = renderTabs(true)
+		}
+		rawContent = title + tabSection + modeSection + indicators + help
+	}
 	padding := m.width - lipgloss.Width(rawContent)
 	if padding < 0 {
 		padding = 0
@@ -141,14 +168,21 @@ func (m Model) renderHistory() string {
 		sb.WriteString(m.renderHistoryBurnRate())
 	case 3:
 		sb.WriteString(m.renderHistoryAlerts())
+	case 4:
+		sb.WriteString(m.renderHistoryEvents())
 	}
 
+	result := sb.String()
 	if m.historyFilterMenu.Active {
-		result := sb.String()
-		return m.overlayHistoryFilterMenu(result)
+		result = m.overlayHistoryFilterMenu(result)
 	}
-
-	return sb.String()
+	if m.notePrompt {
+		result = m.overlayNotePrompt(result)
+	}
+	if m.detailOverlay {
+		result = m.overlayDetail(result)
+	}
+	return result
 }
 
 func (m Model) historyQueryDays() int {
@@ -227,6 +261,15 @@ func (m Model) renderHistoryOverview() string {
 		sb.WriteByte('\n')
 	}
 
+	t := overviewTotals(aggRows[startIdx:endIdx])
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 90)))
+	sb.WriteByte('\n')
+	sb.WriteString(historyFooterStyle.Render(fmt.Sprintf("  %-14s   $%7.2f %12s %8d %8d %6d %7d %7d %7d",
+		t.label, t.cost, formatNumber(t.tokens),
+		t.sessions, t.requests, t.errors,
+		t.linesAdd, t.linesDel, t.commits)))
+	sb.WriteByte('\n')
+
 	return sb.String()
 }
 
@@ -288,6 +331,89 @@ func (m Model) renderHistoryPerformance() string {
 		sb.WriteByte('\n')
 	}
 
+	a := perfAverages(aggRows[startIdx:endIdx])
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 85)))
+	sb.WriteByte('\n')
+	var footer string
+	if a.isLegacy {
+		footer = fmt.Sprintf("  %-14s %7s %8s %8s %7s %7s %7s %8s %9s",
+			a.label, "--", "--", "--", "--", "--", "--", "--", "--")
+	} else {
+		footer = fmt.Sprintf("  %-14s %6.0f%% %7.1f%% %7.1fs %6.1fs %6.1fs %6.1fs %7.1f%% $%7.2f",
+			a.label, a.cacheEff*100, a.errRate*100, a.avgLat,
+			a.p50, a.p95, a.p99, a.retryRate*100, a.cacheSave)
+	}
+	sb.WriteString(historyFooterStyle.Render(footer))
+	sb.WriteByte('\n')
+
+	sb.WriteString(m.renderContextHistory(rows))
+
+	return sb.String()
+}
+
+// renderContextHistory charts each model's largest context per day as a
+// percentage of its window, oldest day first, and counts the days that
+// reached the context pressure threshold. Models with an unknown context
+// limit are left out.
+func (m Model) renderContextHistory(rows []DailyStatsRow) string {
+	type modelDays struct {
+		model    string
+		percents []float64 // per row, oldest first
+		peak     float64
+		days     int // days with requests
+		nearDays int
+	}
+	byModel := make(map[string]*modelDays)
+	var models []*modelDays
+	for i := range rows {
+		col := len(rows) - 1 - i
+		for _, u := range rows[i].ContextUsage {
+			if u.Limit <= 0 {
+				continue
+			}
+			md, ok := byModel[u.Model]
+			if !ok {
+				md = &modelDays{model: u.Model, percents: make([]float64, len(rows))}
+				byModel[u.Model] = md
+				models = append(models, md)
+			}
+			md.percents[col] = u.MaxPercent
+			md.peak = max(md.peak, u.MaxPercent)
+			md.days++
+			if u.MaxPercent >= float64(m.cfg.Alerts.ContextPressurePercent) {
+				md.nearDays++
+			}
+		}
+	}
+	if len(models) == 0 {
+		return ""
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].peak != models[j].peak {
+			return models[i].peak > models[j].peak
+		}
+		return models[i].model < models[j].model
+	})
+
+	chartW := min(max(m.width-60, 7), len(rows))
+	nearH := fmt.Sprintf("Days ≥%d%%", m.cfg.Alerts.ContextPressurePercent)
+
+	var sb strings.Builder
+	sb.WriteByte('\n')
+	sb.WriteString(panelTitleStyle.Render("  Context Window") + dimStyle.Render("  largest request per day, % of the model's limit"))
+	sb.WriteByte('\n')
+	sb.WriteString(fmt.Sprintf("  %-25s %6s %11s   %s", "Model", "Peak", nearH, "Oldest → newest"))
+	sb.WriteByte('\n')
+	for _, md := range models {
+		near := fmt.Sprintf("%11s", fmt.Sprintf("%d of %d", md.nearDays, md.days))
+		if md.nearDays > 0 {
+			near = alertWarningStyle.Render(near)
+		}
+		sb.WriteString(fmt.Sprintf("  %-25s %5.0f%% %s   %s",
+			truncateStr(md.model, 25), md.peak, near,
+			costYellowStyle.Render(sparklineTo(md.percents, chartW, 100))))
+		sb.WriteByte('\n')
+	}
 	return sb.String()
 }
 
@@ -318,13 +444,30 @@ func (m Model) renderHistoryBurnRate() string {
 		}
 	}
 
+	// Daily rows chart each UTC hour of the day, aggregated rows each day.
+	showSpark := m.width >= 72+sparklineWidth+6
+	var hourly map[string][24]float64
+	sparkH := "$/hr by day"
+	if showSpark && m.historyGranularity != "weekly" && m.historyGranularity != "monthly" {
+		hourly = m.history.QueryBurnRateHourly(days)
+		sparkH = "$/hr by hour (UTC)"
+	}
+	sepW := 72
+	if showSpark {
+		sepW += sparklineWidth + 3
+	}
+
 	var sb strings.Builder
 	sb.WriteByte('\n')
 	dateH := m.historyDateHeader()
-	sb.WriteString(fmt.Sprintf("  %-14s %10s %10s %12s %10s %10s",
-		dateH, "Avg $/hr", "Peak $/hr", "Tokens/min", "Daily $", "Monthly $"))
+	header := fmt.Sprintf("  %-14s %10s %10s %12s %10s %10s",
+		dateH, "Avg $/hr", "Peak $/hr", "Tokens/min", "Daily $", "Monthly $")
+	if showSpark {
+		header += fmt.Sprintf("   %-*s", sparklineWidth, sparkH)
+	}
+	sb.WriteString(header)
 	sb.WriteByte('\n')
-	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 72)))
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", sepW)))
 	sb.WriteByte('\n')
 
 	m.clampHistoryCursor(len(aggRows))
@@ -338,12 +481,85 @@ func (m Model) renderHistoryBurnRate() string {
 			line = cursorStyle.Render(line)
 		}
 		sb.WriteString(line)
+		if showSpark {
+			sb.WriteString("   " + costYellowStyle.Render(burnSparkline(r, hourly)))
+		}
 		sb.WriteByte('\n')
 	}
 
+	b := burnAverages(aggRows[startIdx:endIdx])
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", sepW)))
+	sb.WriteByte('\n')
+	sb.WriteString(historyFooterStyle.Render(fmt.Sprintf("  %-14s   $%7.2f   $%7.2f %12.1f   $%7.2f   $%7.2f",
+		b.label, b.avgRate, b.peakRate, b.tokenVel, b.dailyProj, b.monthProj)))
+	sb.WriteByte('\n')
+
+	sb.WriteByte('\n')
+	sb.WriteString(renderProjectionAccuracy(m.history.QueryProjectionAccuracy(days)))
+	sb.WriteByte('\n')
+
 	return sb.String()
 }
 
+// burnSparkline charts a burn rate row: the day's hourly averages when
+// hourly is set, otherwise the average $/hr of each day in the row.
+func burnSparkline(r burnAggRow, hourly map[string][24]float64) string {
+	if hourly != nil {
+		if len(r.days) == 0 {
+			return ""
+		}
+		hours, ok := hourly[r.days[0].Date]
+		if !ok {
+			return ""
+		}
+		return sparkline(hours[:], sparklineWidth)
+	}
+	// Days are newest first; chart them oldest first.
+	values := make([]float64, len(r.days))
+	for i, d := range r.days {
+		values[len(r.days)-1-i] = d.AvgHourlyRate
+	}
+	return sparkline(values, sparklineWidth)
+}
+
+// renderProjectionAccuracy summarises how close the end-of-day daily
+// projection came to each day's actual cost. Monthly projections are the
+// daily projection times 30, so they are off by the same percentage.
+func renderProjectionAccuracy(rows []ProjectionAccuracyRow) string {
+	accuracy, bias, n := projectionAccuracy(rows)
+	if n == 0 {
+		return dimStyle.Render("  Projection accuracy: not enough data yet (recorded for each completed day)")
+	}
+	direction := "high"
+	if bias < 0 {
+		direction = "low"
+	}
+	return fmt.Sprintf("  Projection accuracy: %.0f%% over %d day(s)  %s",
+		accuracy, n, dimStyle.Render(fmt.Sprintf("projections ran %.0f%% %s on average", math.Abs(bias), direction)))
+}
+
+// projectionAccuracy returns 100 minus the mean absolute percentage error of
+// the projections (floored at 0) and their signed bias in percent of the
+// actual cost. Days without spend are skipped, as no percentage applies.
+func projectionAccuracy(rows []ProjectionAccuracyRow) (accuracy, bias float64, n int) {
+	var absErr, projected, actual float64
+	for _, r := range rows {
+		if r.ActualCost <= 0 {
+			continue
+		}
+		absErr += math.Abs(r.ProjectedCost-r.ActualCost) / r.ActualCost
+		projected += r.ProjectedCost
+		actual += r.ActualCost
+		n++
+	}
+	if n == 0 {
+		return 0, 0, 0
+	}
+	accuracy = max(0, 100-absErr/float64(n)*100)
+	bias = (projected - actual) / actual * 100
+	return accuracy, bias, n
+}
+
 // --- Alerts sub-tab (v63.13) ---
 
 func (m Model) renderHistoryAlerts() string {
@@ -355,8 +571,8 @@ func (m Model) renderHistoryAlerts() string {
 
 	var sb strings.Builder
 	sb.WriteByte('\n')
-	sb.WriteString(fmt.Sprintf("  %-19s %-20s %-10s %-12s %s",
-		"Time", "Rule", "Severity", "Session", "Message"))
+	sb.WriteString(fmt.Sprintf("  %-*s %-20s %-10s %-12s %s",
+		m.dateTimeWidth(), "Time", "Rule", "Severity", "Session", "Message"))
 	sb.WriteByte('\n')
 	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 85)))
 	sb.WriteByte('\n')
@@ -374,8 +590,11 @@ func (m Model) renderHistoryAlerts() string {
 		if len(msg) > 30 {
 			msg = msg[:30] + "..."
 		}
-		line := fmt.Sprintf("  %-19s %-20s %-10s %-12s %s",
-			a.FiredAt.Local().Format("2006-01-02 15:04:05"),
+		if a.Note != "" {
+			msg = "✎ " + msg
+		}
+		line := fmt.Sprintf("  %-*s %-20s %-10s %-12s %s",
+			m.dateTimeWidth(), m.formatDateTime(a.FiredAt),
 			truncateStr(a.Rule, 20),
 			a.Severity,
 			truncateStr(sess, 12),
@@ -387,6 +606,21 @@ func (m Model) renderHistoryAlerts() string {
 		sb.WriteByte('\n')
 	}
 
+	var warning, critical int
+	for _, a := range alerts[startIdx:endIdx] {
+		switch a.Severity {
+		case "critical":
+			critical++
+		case "warning":
+			warning++
+		}
+	}
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 85)))
+	sb.WriteByte('\n')
+	sb.WriteString(historyFooterStyle.Render(fmt.Sprintf("  %-19s %d alerts (%d critical, %d warning)",
+		"Total", endIdx-startIdx, critical, warning)))
+	sb.WriteByte('\n')
+
 	return sb.String()
 }
 
@@ -406,6 +640,8 @@ func (m Model) openHistoryDetail() (Model, tea.Cmd) {
 		return m.openBurnRateDetail()
 	case 3:
 		return m.openAlertDetail()
+	case 4:
+		return m.openEventHistoryDetail()
 	}
 	return m, nil
 }
@@ -476,6 +712,28 @@ func (m Model) openOverviewDetail() (Model, tea.Cmd) {
 		}
 	}
 
+	if len(r.AccountBreakdown) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, "Account Breakdown:")
+		lines = append(lines, fmt.Sprintf("  %-40s %10s %8s", "Account (org / user)", "Cost", "Sessions"))
+		lines = append(lines, "  "+strings.Repeat("─", 60))
+		for _, ab := range r.AccountBreakdown {
+			lines = append(lines, fmt.Sprintf("  %-40s $%9.2f %8d",
+				truncateStr(formatAccount(ab.OrgID, ab.UserUUID), 40), ab.TotalCost, ab.SessionCount))
+		}
+	}
+
+	if len(r.ProjectBreakdown) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, "Project Breakdown:")
+		lines = append(lines, fmt.Sprintf("  %-40s %10s %8s %7s %6s", "Project", "Cost", "Sessions", "Commits", "Err%"))
+		lines = append(lines, "  "+strings.Repeat("─", 75))
+		for _, pb := range r.ProjectBreakdown {
+			lines = append(lines, fmt.Sprintf("  %-40s $%9.2f %8d %7d %5.1f%%",
+				truncateCWD(pb.Project, 40), pb.TotalCost, pb.SessionCount, pb.Commits, pb.ErrorRate*100))
+		}
+	}
+
 	if len(r.TopTools) > 0 {
 		lines = append(lines, "")
 		lines = append(lines, "Tool Usage:")
@@ -584,6 +842,22 @@ func (m Model) openPerformanceDetail() (Model, tea.Cmd) {
 		}
 	}
 
+	if len(r.ContextUsage) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, "Context Window:")
+		lines = append(lines, fmt.Sprintf("  %-25s %12s %10s %6s %11s", "Model", "Largest", "Limit", "Max%", "Near Limit"))
+		lines = append(lines, "  "+strings.Repeat("─", 68))
+		for _, u := range r.ContextUsage {
+			limit, pct := "--", "--"
+			if u.Limit > 0 {
+				limit = formatNumber(int64(u.Limit))
+				pct = fmt.Sprintf("%.0f%%", u.MaxPercent)
+			}
+			lines = append(lines, fmt.Sprintf("  %-25s %12s %10s %6s %4d of %3d",
+				truncateStr(u.Model, 25), formatNumber(u.MaxTokens), limit, pct, u.NearLimit, u.Requests))
+		}
+	}
+
 	if len(r.TopTools) > 0 {
 		lines = append(lines, "")
 		lines = append(lines, "Top Tools + Performance:")
@@ -647,6 +921,13 @@ func (m Model) openBurnRateDetail() (Model, tea.Cmd) {
 		var lines []string
 		lines = append(lines, fmt.Sprintf("Period: %s (%d days)", g.label, len(g.days)))
 		lines = append(lines, "")
+		values := make([]float64, len(g.days))
+		for i, d := range g.days {
+			values[len(g.days)-1-i] = d.AvgHourlyRate
+		}
+		lines = append(lines, "Avg $/hr by day:")
+		lines = append(lines, m.renderBarChart(values, g.days[len(g.days)-1].Date, g.days[0].Date)...)
+		lines = append(lines, "")
 		lines = append(lines, fmt.Sprintf("  %-12s %10s %10s %12s %10s %10s",
 			"Date", "Avg $/hr", "Peak $/hr", "Tokens/min", "Daily $", "Monthly $"))
 		lines = append(lines, "  "+strings.Repeat("─", 72))
@@ -676,13 +957,16 @@ func (m Model) openBurnRateDetail() (Model, tea.Cmd) {
 	lines = append(lines, "")
 
 	if len(snapshots) > 0 {
+		lines = append(lines, "Hourly Rate:")
+		lines = append(lines, m.renderRateChart(snapshots)...)
+		lines = append(lines, "")
 		lines = append(lines, "Intra-day Snapshots:")
 		lines = append(lines, fmt.Sprintf("  %-8s %10s %10s %8s %12s",
 			"Time", "Cost", "$/hr", "Trend", "Tokens/min"))
 		lines = append(lines, "  "+strings.Repeat("─", 55))
 		for _, snap := range snapshots {
 			lines = append(lines, fmt.Sprintf("  %-8s   $%7.2f   $%7.2f %8s %12.1f",
-				snap.Timestamp.Local().Format("15:04"),
+				m.formatClockMinutes(snap.Timestamp),
 				snap.TotalCost, snap.HourlyRate,
 				snap.Trend.String(), snap.TokenVelocity))
 		}
@@ -712,10 +996,11 @@ func (m Model) openAlertDetail() (Model, tea.Cmd) {
 		sess = "(global)"
 	}
 	lines = append(lines, "Session:   "+sess)
-	lines = append(lines, "Fired at:  "+a.FiredAt.Local().Format("2006-01-02 15:04:05"))
+	lines = append(lines, "Fired at:  "+m.formatDateTime(a.FiredAt))
 	lines = append(lines, "")
 	lines = append(lines, "Message:")
 	lines = append(lines, a.Message)
+	lines = append(lines, m.alertNoteLines(a.Rule, a.SessionID, a.FiredAt)...)
 
 	m.detailOverlay = true
 	m.detailTitle = "Alert Detail"
@@ -773,7 +1058,11 @@ func (m Model) handleHistoryFilterMenuKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Enter):
 		if m.historyFilterMenu.Cursor >= 0 && m.historyFilterMenu.Cursor < len(m.historyFilterMenu.Options) {
 			opt := m.historyFilterMenu.Options[m.historyFilterMenu.Cursor]
-			m.historyAlertFilter = opt.Key
+			if m.historySection == 4 {
+				m.applyHistoryEventFilter(opt)
+			} else {
+				m.historyAlertFilter = opt.Key
+			}
 			m.historyCursor = 0
 			m.historyFilterMenu.Active = false
 		}
@@ -783,7 +1072,11 @@ func (m Model) handleHistoryFilterMenuKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 }
 
 func (m Model) overlayHistoryFilterMenu(base string) string {
-	content := panelTitleStyle.Render("Alert Rule Filter") + "\n\n"
+	title := "Alert Rule Filter"
+	if m.historySection == 4 {
+		title = "Event Filter"
+	}
+	content := panelTitleStyle.Render(title) + "\n\n"
 	for i, opt := range m.historyFilterMenu.Options {
 		cursor := "  "
 		if i == m.historyFilterMenu.Cursor {
@@ -820,8 +1113,10 @@ func (m *Model) clampHistoryCursor(rowCount int) {
 	}
 }
 
+// visibleRange returns the [start, end) slice of table rows that fit on
+// screen, leaving room for the header and the pinned footer row.
 func (m Model) visibleRange(rowCount int) (start, end int) {
-	visibleH := m.height - 6
+	visibleH := m.height - 8
 	if visibleH < 1 {
 		visibleH = 1
 	}
@@ -1010,6 +1305,79 @@ func aggregateBurnByGroup(rows []BurnRateDailySummary, labelFn func(string) stri
 	return result
 }
 
+// overviewTotals sums the given Overview rows into a "Total" footer row.
+func overviewTotals(rows []overviewAggRow) overviewAggRow {
+	t := overviewAggRow{label: "Total"}
+	for _, r := range rows {
+		t.cost += r.cost
+		t.tokens += r.tokens
+		t.sessions += r.sessions
+		t.requests += r.requests
+		t.errors += r.errors
+		t.linesAdd += r.linesAdd
+		t.linesDel += r.linesDel
+		t.commits += r.commits
+	}
+	return t
+}
+
+// perfAverages averages the given non-legacy Performance rows into an
+// "Average" footer row. Cache savings are summed rather than averaged.
+// The result is marked legacy when every row is legacy.
+func perfAverages(rows []perfAggRow) perfAggRow {
+	a := perfAggRow{label: "Average"}
+	n := 0
+	for _, r := range rows {
+		if r.isLegacy {
+			continue
+		}
+		n++
+		a.cacheEff += r.cacheEff
+		a.errRate += r.errRate
+		a.avgLat += r.avgLat
+		a.p50 += r.p50
+		a.p95 += r.p95
+		a.p99 += r.p99
+		a.retryRate += r.retryRate
+		a.cacheSave += r.cacheSave
+	}
+	if n == 0 {
+		a.isLegacy = true
+		return a
+	}
+	f := float64(n)
+	a.cacheEff /= f
+	a.errRate /= f
+	a.avgLat /= f
+	a.p50 /= f
+	a.p95 /= f
+	a.p99 /= f
+	a.retryRate /= f
+	return a
+}
+
+// burnAverages combines the given Burn Rate rows into an "Average" footer
+// row: rates, velocity and monthly projection are averaged, the peak is the
+// maximum and daily projections are summed.
+func burnAverages(rows []burnAggRow) burnAggRow {
+	a := burnAggRow{label: "Average"}
+	for _, r := range rows {
+		a.avgRate += r.avgRate
+		a.tokenVel += r.tokenVel
+		a.monthProj += r.monthProj
+		a.dailyProj += r.dailyProj
+		if r.peakRate > a.peakRate {
+			a.peakRate = r.peakRate
+		}
+	}
+	if n := float64(len(rows)); n > 0 {
+		a.avgRate /= n
+		a.tokenVel /= n
+		a.monthProj /= n
+	}
+	return a
+}
+
 func weekLabelForDate(date string) string {
 	if len(date) >= 10 {
 		t, err := time.Parse("2006-01-02", date[:10])
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -15,11 +16,22 @@ import (
 // --- Mock HistoryProvider ---
 
 type mockHistoryProvider struct {
-	dailyStats     []DailyStatsRow
-	burnSummaries  []BurnRateDailySummary
-	burnSnapshots  []BurnRateSnapshotRow
-	alertHistory   []AlertHistoryRow
-	callLog        []string // tracks method calls for verification
+	dailyStats    []DailyStatsRow
+	burnSummaries []BurnRateDailySummary
+	burnSnapshots []BurnRateSnapshotRow
+	burnHourly    map[string][24]float64
+	alertHistory  []AlertHistoryRow
+	accuracy      []ProjectionAccuracyRow
+	callLog       []string // tracks method calls for verification
+}
+
+func (m *mockHistoryProvider) SetAlertNote(rule, sessionID string, firedAt time.Time, note string) {
+	m.callLog = append(m.callLog, "SetAlertNote")
+	for i, a := range m.alertHistory {
+		if a.Rule == rule && a.SessionID == sessionID && a.FiredAt.Equal(firedAt.Truncate(time.Second)) {
+			m.alertHistory[i].Note = note
+		}
+	}
 }
 
 func (m *mockHistoryProvider) QueryDailyStats(days int) []DailyStatsRow {
@@ -37,6 +49,11 @@ func (m *mockHistoryProvider) QueryBurnRateSnapshots(date string) []BurnRateSnap
 	return m.burnSnapshots
 }
 
+func (m *mockHistoryProvider) QueryBurnRateHourly(days int) map[string][24]float64 {
+	m.callLog = append(m.callLog, "QueryBurnRateHourly")
+	return m.burnHourly
+}
+
 func (m *mockHistoryProvider) QueryAlertHistory(days int, ruleFilter string) []AlertHistoryRow {
 	m.callLog = append(m.callLog, "QueryAlertHistory")
 	// Respect ruleFilter the same way the real impl would.
@@ -52,6 +69,10 @@ func (m *mockHistoryProvider) QueryAlertHistory(days int, ruleFilter string) []A
 	return filtered
 }
 
+func (m *mockHistoryProvider) QueryProjectionAccuracy(days int) []ProjectionAccuracyRow {
+	return m.accuracy
+}
+
 // --- Helpers ---
 
 func newHistoryModel(opts ...ModelOption) Model {
@@ -402,6 +423,77 @@ func TestHistoryBurnRate_RendersColumns(t *testing.T) {
 	}
 }
 
+func TestHistoryBurnRate_Sparklines(t *testing.T) {
+	var hours [24]float64
+	hours[9], hours[10], hours[14] = 1, 4, 2
+	mock := &mockHistoryProvider{
+		burnSummaries: sampleBurnSummaries(),
+		burnHourly:    map[string][24]float64{"2026-02-20": hours},
+	}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.historySection = 2
+
+	view := stripAnsi(m.renderHistoryBurnRate())
+	if !strings.Contains(view, "$/hr by hour (UTC)") {
+		t.Errorf("daily view should label the hourly sparkline, got:\n%s", view)
+	}
+	want := strings.Repeat("·", 9) + "▂█" + "···" + "▄" + strings.Repeat("·", 9)
+	if !strings.Contains(view, want) {
+		t.Errorf("expected the 2026-02-20 sparkline %q, got:\n%s", want, view)
+	}
+
+	m.historyGranularity = "weekly"
+	view = stripAnsi(m.renderHistoryBurnRate())
+	if !strings.Contains(view, "$/hr by day") {
+		t.Errorf("weekly view should chart each day, got:\n%s", view)
+	}
+	// 2026-02-19 ($1.20) then 2026-02-20 ($1.50), oldest first.
+	if !strings.Contains(view, "▆█") {
+		t.Errorf("expected the per-day sparkline, got:\n%s", view)
+	}
+
+	m.width = 80
+	if view := m.renderHistoryBurnRate(); strings.Contains(view, "$/hr by day") {
+		t.Error("narrow terminals should drop the sparkline column")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline(nil, 10); got != "" {
+		t.Errorf("no values: got %q", got)
+	}
+	if got := sparkline([]float64{0, 1, 2, 4}, 10); got != "·▂▄█" {
+		t.Errorf("got %q, want %q", got, "·▂▄█")
+	}
+	// Four values into two characters keep the peak of each pair.
+	if got := sparkline([]float64{1, 4, 2, 0}, 2); got != "█▄" {
+		t.Errorf("bucketed: got %q, want %q", got, "█▄")
+	}
+}
+
+func TestHistoryBurnRate_ProjectionAccuracy(t *testing.T) {
+	mock := &mockHistoryProvider{burnSummaries: sampleBurnSummaries()}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.historySection = 2
+
+	if view := m.renderHistoryBurnRate(); !strings.Contains(view, "not enough data yet") {
+		t.Errorf("without recorded days the accuracy should say so, got:\n%s", view)
+	}
+
+	mock.accuracy = []ProjectionAccuracyRow{
+		{Date: "2026-02-20", ProjectedCost: 12, ActualCost: 10},
+		{Date: "2026-02-19", ProjectedCost: 9, ActualCost: 10},
+		{Date: "2026-02-18", ProjectedCost: 3, ActualCost: 0}, // no spend, skipped
+	}
+	view := stripAnsi(m.renderHistoryBurnRate())
+	if !strings.Contains(view, "Projection accuracy: 85% over 2 day(s)") {
+		t.Errorf("expected 85%% accuracy over 2 days, got:\n%s", view)
+	}
+	if !strings.Contains(view, "projections ran 5% high") {
+		t.Errorf("expected a 5%% high bias, got:\n%s", view)
+	}
+}
+
 func TestHistoryAlerts_RendersColumns(t *testing.T) {
 	mock := &mockHistoryProvider{alertHistory: sampleAlerts()}
 	m := newHistoryModel(WithHistoryProvider(mock))
@@ -694,6 +786,45 @@ func TestHistoryPerformanceDetail(t *testing.T) {
 	}
 }
 
+func TestHistoryPerformance_ContextWindow(t *testing.T) {
+	rows := []DailyStatsRow{
+		{Date: "2026-02-20", ContextUsage: []stats.ContextUsage{
+			{Model: "claude-opus-4-6", MaxTokens: 180000, Limit: 200000, MaxPercent: 90, Requests: 30, NearLimit: 4},
+			{Model: "local-model", MaxTokens: 9000, Requests: 2},
+		}},
+		{Date: "2026-02-19", ContextUsage: []stats.ContextUsage{
+			{Model: "claude-opus-4-6", MaxTokens: 100000, Limit: 200000, MaxPercent: 50, Requests: 12},
+			{Model: "claude-haiku-4-5", MaxTokens: 20000, Limit: 200000, MaxPercent: 10, Requests: 5},
+		}},
+		{Date: "2026-02-18"},
+	}
+	mock := &mockHistoryProvider{dailyStats: rows}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.historySection = 1
+
+	view := stripAnsi(m.renderHistoryPerformance())
+	if !strings.Contains(view, "Context Window") || !strings.Contains(view, "Days ≥80%") {
+		t.Fatalf("performance tab should chart context usage, got:\n%s", view)
+	}
+	opus := strings.Index(view, "claude-opus-4-6")
+	haiku := strings.Index(view, "claude-haiku-4-5")
+	if opus < 0 || haiku < opus {
+		t.Errorf("models should be listed by peak usage, got:\n%s", view)
+	}
+	if strings.Contains(view, "local-model") {
+		t.Error("models without a known limit should be left out")
+	}
+	// 2026-02-18 has no data, then 50% and 90% against a 100% scale.
+	if !strings.Contains(view, "1 of 2   ·▄▇") {
+		t.Errorf("expected opus at 1 of 2 days near the limit with its daily chart, got:\n%s", view)
+	}
+
+	m, _ = m.openPerformanceDetail()
+	if !strings.Contains(m.detailContent, "Context Window:") || !strings.Contains(m.detailContent, "90%") {
+		t.Errorf("daily detail should list context usage, got:\n%s", m.detailContent)
+	}
+}
+
 func TestHistoryBurnRateDetail(t *testing.T) {
 	snapshots := []BurnRateSnapshotRow{
 		{Timestamp: time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC), TotalCost: 5.00, HourlyRate: 1.50, Trend: burnrate.TrendUp, TokenVelocity: 500},
@@ -714,6 +845,9 @@ func TestHistoryBurnRateDetail(t *testing.T) {
 	if !strings.Contains(m.detailTitle, "Burn Rate Detail") {
 		t.Errorf("detail title should contain 'Burn Rate Detail', got %q", m.detailTitle)
 	}
+	if !strings.Contains(m.detailContent, "Hourly Rate:") {
+		t.Error("detail should contain the hourly rate chart")
+	}
 	if !strings.Contains(m.detailContent, "Intra-day Snapshots") {
 		t.Error("detail should contain 'Intra-day Snapshots'")
 	}
@@ -722,6 +856,37 @@ func TestHistoryBurnRateDetail(t *testing.T) {
 	}
 }
 
+func TestHistoryBurnRateDetail_WeeklyBarChart(t *testing.T) {
+	mock := &mockHistoryProvider{burnSummaries: []BurnRateDailySummary{
+		{Date: "2026-02-18", AvgHourlyRate: 4.00, SnapshotCount: 48},
+		{Date: "2026-02-17", AvgHourlyRate: 2.00, SnapshotCount: 48},
+		{Date: "2026-02-16", AvgHourlyRate: 1.00, SnapshotCount: 48},
+	}}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.historySection = 2
+	m.historyGranularity = "weekly"
+
+	m, _ = m.openBurnRateDetail()
+	content := stripAnsi(m.detailContent)
+	if !strings.Contains(content, "Avg $/hr by day:") {
+		t.Fatalf("weekly detail should chart each day, got:\n%s", content)
+	}
+	if !strings.Contains(content, "$4.00 ┤") {
+		t.Errorf("chart should be scaled to the $4.00 peak, got:\n%s", content)
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "└") {
+			labels := lines[i+1]
+			if !strings.Contains(labels, "2026-02-16") || !strings.Contains(labels, "2026-02-18") ||
+				strings.Index(labels, "2026-02-16") > strings.Index(labels, "2026-02-18") {
+				t.Errorf("axis should run from 2026-02-16 to 2026-02-18, got %q", labels)
+			}
+			break
+		}
+	}
+}
+
 func TestHistoryBurnRateDetail_NoSnapshots(t *testing.T) {
 	mock := &mockHistoryProvider{
 		burnSummaries: sampleBurnSummaries(),
@@ -1028,11 +1193,11 @@ func TestHistoryQueryDays(t *testing.T) {
 
 func TestVisibleRange(t *testing.T) {
 	m := newHistoryModel()
-	m.height = 20 // visibleH = 20 - 6 = 14
+	m.height = 20 // visibleH = 20 - 8 = 12
 
 	start, end := m.visibleRange(5)
 	if start != 0 || end != 5 {
-		t.Errorf("5 rows in 14-height: expected range [0,5), got [%d,%d)", start, end)
+		t.Errorf("5 rows in 12-height: expected range [0,5), got [%d,%d)", start, end)
 	}
 
 	m.historyCursor = 3
@@ -1041,3 +1206,71 @@ func TestVisibleRange(t *testing.T) {
 		t.Errorf("cursor=3: should be within [%d,%d)", start, end)
 	}
 }
+
+// --- Footer rows ---
+
+func TestHistoryOverview_TotalsFooter(t *testing.T) {
+	mock := &mockHistoryProvider{dailyStats: sampleDailyStats()}
+	m := newHistoryModel(WithHistoryProvider(mock))
+
+	out := m.renderHistoryOverview()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	footer := lines[len(lines)-1]
+	if !strings.Contains(footer, "Total") {
+		t.Fatalf("last line should be the totals footer, got %q", footer)
+	}
+	// 12.50 + 8.00 + 5.25 = 25.75; 7 commits; 145,000 tokens.
+	for _, want := range []string{"$  25.75", "145,000", " 7"} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("totals footer missing %q: %q", want, footer)
+		}
+	}
+}
+
+func TestHistoryPerformance_AveragesFooterSkipsLegacy(t *testing.T) {
+	mock := &mockHistoryProvider{dailyStats: sampleDailyStats()}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.historySection = 1
+
+	out := m.renderHistoryPerformance()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	footer := lines[len(lines)-1]
+	if !strings.Contains(footer, "Average") {
+		t.Fatalf("last line should be the averages footer, got %q", footer)
+	}
+	// Cache% averages the two non-legacy days: (85 + 90) / 2 = 87.5 -> 88%.
+	if !strings.Contains(footer, "88%") {
+		t.Errorf("averages footer should show 88%% cache efficiency: %q", footer)
+	}
+	// Error rate: (3% + 0%) / 2 = 1.5%.
+	if !strings.Contains(footer, "1.5%") {
+		t.Errorf("averages footer should show 1.5%% error rate: %q", footer)
+	}
+}
+
+func TestHistoryFooters_VisibleRangeOnly(t *testing.T) {
+	rows := make([]DailyStatsRow, 20)
+	for i := range rows {
+		rows[i] = DailyStatsRow{Date: "2026-02-01", TotalCost: 1.00, Commits: 1}
+	}
+	mock := &mockHistoryProvider{dailyStats: rows}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.height = 13 // 5 visible rows
+
+	out := m.renderHistoryOverview()
+	if !strings.Contains(out, "$   5.00") {
+		t.Errorf("totals should cover only the 5 visible rows, got:\n%s", out)
+	}
+}
+
+func TestHistoryAlerts_CountFooter(t *testing.T) {
+	mock := &mockHistoryProvider{alertHistory: sampleAlerts()}
+	m := newHistoryModel(WithHistoryProvider(mock))
+	m.historySection = 3
+
+	out := m.renderHistoryAlerts()
+	want := fmt.Sprintf("%d alerts", len(sampleAlerts()))
+	if !strings.Contains(out, want) || !strings.Contains(out, "critical") {
+		t.Errorf("alerts footer should contain %q with severity counts, got:\n%s", want, out)
+	}
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/events"
+)
+
+// historyEventLimit caps the events the Events sub-tab loads for a day.
+const historyEventLimit = 500
+
+// historyEventRange is the local day the Events sub-tab shows.
+func (m Model) historyEventRange(now time.Time) (from, to time.Time) {
+	y, mo, d := now.Date()
+	from = time.Date(y, mo, d-m.historyEventDay, 0, 0, 0, 0, now.Location())
+	return from, from.AddDate(0, 0, 1)
+}
+
+// historyEventDayLabel names the Events sub-tab day, e.g. "2026-03-01 (yesterday)".
+func (m Model) historyEventDayLabel(from time.Time) string {
+	switch m.historyEventDay {
+	case 0:
+		return from.Format("2006-01-02") + " (today)"
+	case 1:
+		return from.Format("2006-01-02") + " (yesterday)"
+	}
+	return from.Format("2006-01-02 Mon")
+}
+
+// queryHistoryEvents loads the Events sub-tab's events, newest first.
+func (m Model) queryHistoryEvents() []events.FormattedEvent {
+	from, to := m.historyEventRange(time.Now())
+	return m.eventHistory.QueryEvents(from, to, m.historyEventSession, m.historyEventType, historyEventLimit)
+}
+
+// --- Events sub-tab ---
+
+func (m Model) renderHistoryEvents() string {
+	if m.eventHistory == nil {
+		return "\n" + dimStyle.Render("  Event history is not available.") + "\n"
+	}
+
+	from, _ := m.historyEventRange(time.Now())
+	session, eventType := "all", "all"
+	if m.historyEventSession != "" {
+		session = truncateID(m.historyEventSession, 12)
+	}
+	if m.historyEventType != "" {
+		eventType = eventTypeLabel(m.historyEventType)
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('\n')
+	sb.WriteString(fmt.Sprintf("  Day: %s   Session: %s   Type: %s",
+		m.historyEventDayLabel(from), session, eventType))
+	sb.WriteByte('\n')
+
+	evts := m.queryHistoryEvents()
+	if len(evts) == 0 {
+		sb.WriteByte('\n')
+		sb.WriteString(dimStyle.Render("  No events recorded on this day. ←/→ change the day, / the filters."))
+		sb.WriteByte('\n')
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("  %-*s %s", m.dateTimeWidth(), "Time", "Event"))
+	sb.WriteByte('\n')
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 85)))
+	sb.WriteByte('\n')
+
+	m.clampHistoryCursor(len(evts))
+	startIdx, endIdx := m.visibleRange(len(evts))
+	lineW := max(m.width-m.dateTimeWidth()-3, 20)
+	for i := startIdx; i < endIdx; i++ {
+		e := evts[i]
+		line := fmt.Sprintf("  %-*s ", m.dateTimeWidth(), m.formatDateTime(e.Timestamp))
+		if i == m.historyCursor {
+			line = cursorStyle.Render(line+truncateStr(e.Formatted, lineW)) + "\n"
+		} else {
+			line += renderEventLine(e, lineW) + "\n"
+		}
+		sb.WriteString(line)
+	}
+
+	var failed int
+	for _, e := range evts {
+		if e.Success != nil && !*e.Success {
+			failed++
+		}
+	}
+	total := fmt.Sprintf("%d events (%d failed)", len(evts), failed)
+	if len(evts) == historyEventLimit {
+		total = fmt.Sprintf("latest %d events (%d failed)", len(evts), failed)
+	}
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", 85)))
+	sb.WriteByte('\n')
+	sb.WriteString(historyFooterStyle.Render(fmt.Sprintf("  %-19s %s", "Total", total)))
+	sb.WriteByte('\n')
+
+	return sb.String()
+}
+
+func (m Model) openEventHistoryDetail() (Model, tea.Cmd) {
+	if m.eventHistory == nil {
+		return m, nil
+	}
+	evts := m.queryHistoryEvents()
+	if m.historyCursor >= len(evts) {
+		return m, nil
+	}
+	m.detailOverlay = true
+	m.detailTitle = "Event Detail"
+	m.detailContent = m.formatEventDetail(evts[m.historyCursor])
+	m.detailScrollPos = 0
+	return m, nil
+}
+
+// stepHistoryEventDay moves the Events sub-tab by delta days, never past
+// today.
+func (m *Model) stepHistoryEventDay(delta int) {
+	m.historyEventDay = max(0, m.historyEventDay-delta)
+	m.historyCursor = 0
+	m.historyScrollPos = 0
+}
+
+// eventTypeLabel shortens an event name for display, e.g.
+// "claude_code.tool_result" to "tool_result".
+func eventTypeLabel(name string) string {
+	return strings.TrimPrefix(name, "claude_code.")
+}
+
+// openHistoryEventFilterMenu lists the sessions and event types of the
+// Events sub-tab's day. Selecting one sets that filter.
+func (m *Model) openHistoryEventFilterMenu() {
+	from, to := m.historyEventRange(time.Now())
+	var sessions, names []string
+	if m.eventHistory != nil {
+		sessions, names = m.eventHistory.EventFacets(from, to)
+	}
+
+	options := []FilterOption{{Label: "All types", Key: "type:", Enabled: m.historyEventType == ""}}
+	for _, n := range names {
+		options = append(options, FilterOption{Label: "Type: " + eventTypeLabel(n), Key: "type:" + n, Enabled: m.historyEventType == n})
+	}
+	options = append(options, FilterOption{Label: "All sessions", Key: "session:", Enabled: m.historyEventSession == ""})
+	for _, s := range sessions {
+		options = append(options, FilterOption{Label: "Session: " + truncateID(s, 12), Key: "session:" + s, Enabled: m.historyEventSession == s})
+	}
+
+	m.historyFilterMenu = FilterMenuState{
+		Active:  true,
+		Cursor:  0,
+		Options: options,
+	}
+}
+
+// applyHistoryEventFilter sets the filter of a selected Events menu option.
+func (m *Model) applyHistoryEventFilter(opt FilterOption) {
+	if v, ok := strings.CutPrefix(opt.Key, "type:"); ok {
+		m.historyEventType = v
+	} else if v, ok := strings.CutPrefix(opt.Key, "session:"); ok {
+		m.historyEventSession = v
+	}
+}
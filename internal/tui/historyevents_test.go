@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/events"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// mockEventHistory serves fixed events and records the last query.
+type mockEventHistory struct {
+	events []events.FormattedEvent
+
+	from, to        time.Time
+	sessionID, name string
+}
+
+func (m *mockEventHistory) QueryEvents(from, to time.Time, sessionID, name string, limit int) []events.FormattedEvent {
+	m.from, m.to, m.sessionID, m.name = from, to, sessionID, name
+	var result []events.FormattedEvent
+	for _, e := range m.events {
+		if (sessionID == "" || e.SessionID == sessionID) && (name == "" || "claude_code."+e.EventType == name) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (m *mockEventHistory) EventFacets(from, to time.Time) (sessions, names []string) {
+	return []string{"sess-a", "sess-b"}, []string{"claude_code.api_error", "claude_code.tool_result"}
+}
+
+func sampleHistoryEvents() []events.FormattedEvent {
+	at := time.Now().Add(-time.Minute)
+	return []events.FormattedEvent{
+		events.FormatEvent("sess-b", state.Event{Name: "claude_code.api_error", Timestamp: at,
+			Attributes: map[string]string{"status_code": "529", "error": "overloaded"}}),
+		events.FormatEvent("sess-a", state.Event{Name: "claude_code.tool_result", Timestamp: at.Add(-time.Minute),
+			Attributes: map[string]string{"tool_name": "Bash", "success": "true", "duration_ms": "120"}}),
+	}
+}
+
+func TestHistoryEvents_RendersDay(t *testing.T) {
+	eh := &mockEventHistory{events: sampleHistoryEvents()}
+	m := newHistoryModel(WithHistoryProvider(&mockHistoryProvider{}), WithEventHistory(eh))
+	m = sendKey(m, "5")
+	if m.historySection != 4 {
+		t.Fatalf("key 5: historySection = %d, want 4", m.historySection)
+	}
+
+	out := m.renderHistory()
+	for _, want := range []string{"[5] Events", "(today)", "Bash", "529", "2 events (1 failed)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Events sub-tab should contain %q:\n%s", want, out)
+		}
+	}
+	if eh.to.Sub(eh.from) != 24*time.Hour || eh.from.Hour() != 0 {
+		t.Errorf("query range = %v - %v, want one local day", eh.from, eh.to)
+	}
+
+	// ← goes back a day, → forward, but not past today.
+	m = sendSpecialKey(m, tea.KeyLeft)
+	if !strings.Contains(m.renderHistory(), "(yesterday)") || !eh.to.Before(time.Now()) {
+		t.Errorf("left should show yesterday, queried %v - %v", eh.from, eh.to)
+	}
+	m = sendSpecialKey(m, tea.KeyRight)
+	m = sendSpecialKey(m, tea.KeyRight)
+	if m.historyEventDay != 0 {
+		t.Errorf("right past today: historyEventDay = %d, want 0", m.historyEventDay)
+	}
+}
+
+func TestHistoryEvents_FilterMenu(t *testing.T) {
+	eh := &mockEventHistory{events: sampleHistoryEvents()}
+	m := newHistoryModel(WithHistoryProvider(&mockHistoryProvider{}), WithEventHistory(eh))
+	m = sendKey(m, "5")
+	m = sendKey(m, "/")
+	if !m.historyFilterMenu.Active {
+		t.Fatal("/ should open the event filter menu")
+	}
+	if !strings.Contains(m.renderHistory(), "Event Filter") {
+		t.Error("menu should be titled Event Filter")
+	}
+
+	// Options: All types, api_error, tool_result, All sessions, sess-a, sess-b.
+	m = sendSpecialKey(m, tea.KeyDown)
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.historyEventType != "claude_code.api_error" || m.historyFilterMenu.Active {
+		t.Fatalf("type filter = %q, menu active = %v", m.historyEventType, m.historyFilterMenu.Active)
+	}
+
+	m = sendKey(m, "/")
+	for range 4 {
+		m = sendSpecialKey(m, tea.KeyDown)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.historyEventSession != "sess-a" || m.historyEventType != "claude_code.api_error" {
+		t.Errorf("filters = %q / %q, want sess-a / claude_code.api_error", m.historyEventSession, m.historyEventType)
+	}
+
+	out := m.renderHistory()
+	if !strings.Contains(out, "No events recorded") {
+		t.Errorf("sess-a has no API errors:\n%s", out)
+	}
+	if eh.sessionID != "sess-a" || eh.name != "claude_code.api_error" {
+		t.Errorf("query filters = %q / %q", eh.sessionID, eh.name)
+	}
+}
+
+func TestHistoryEvents_Detail(t *testing.T) {
+	eh := &mockEventHistory{events: sampleHistoryEvents()}
+	m := newHistoryModel(WithHistoryProvider(&mockHistoryProvider{}), WithEventHistory(eh))
+	m = sendKey(m, "5")
+	m = sendSpecialKey(m, tea.KeyDown)
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if !m.detailOverlay || m.detailTitle != "Event Detail" || !strings.Contains(m.detailContent, "Bash") {
+		t.Errorf("Enter should open the event detail, got %q:\n%s", m.detailTitle, m.detailContent)
+	}
+}
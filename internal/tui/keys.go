@@ -23,6 +23,42 @@ type KeyMap struct {
 	FocusAlerts key.Binding
 	FocusEvents key.Binding
 	Backspace   key.Binding
+	Help        key.Binding
+	ToggleGroup key.Binding
+	SLATimer    key.Binding
+	AckAlert    key.Binding
+	SnoozeAlert key.Binding
+	AlertNote   key.Binding
+	ScreenDump  key.Binding
+	ModelFamily key.Binding
+
+	HistorySection key.Binding
+	Daily          key.Binding
+	Weekly         key.Binding
+	Monthly        key.Binding
+	HistoryFilter  key.Binding
+	PrevDay        key.Binding
+	NextDay        key.Binding
+	SessionSearch  key.Binding
+	SessionSort    key.Binding
+	TagSession     key.Binding
+
+	Graph       key.Binding
+	GraphSeries key.Binding
+	GraphWindow key.Binding
+
+	TuneThresholds key.Binding
+	TuneLower      key.Binding
+	TuneRaise      key.Binding
+
+	MuteNotifications key.Binding
+
+	Replay       key.Binding
+	ReplayPause  key.Binding
+	ReplayFaster key.Binding
+	ReplaySlower key.Binding
+	ReplayNext   key.Binding
+	ReplayPrev   key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings for cc-top.
@@ -100,5 +136,129 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("backspace"),
 			key.WithHelp("backspace", "back"),
 		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		ToggleGroup: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "collapse/expand group"),
+		),
+		ModelFamily: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "models by ID/family"),
+		),
+		SLATimer: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "set expected duration"),
+		),
+		AckAlert: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "acknowledge alert"),
+		),
+		SnoozeAlert: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "snooze alert"),
+		),
+		AlertNote: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "note or link on alert"),
+		),
+		HistorySection: key.NewBinding(
+			key.WithKeys("1", "2", "3", "4", "5"),
+			key.WithHelp("1-5", "switch section"),
+		),
+		Daily: key.NewBinding(
+			key.WithKeys("d", "D"),
+			key.WithHelp("d", "daily granularity"),
+		),
+		Weekly: key.NewBinding(
+			key.WithKeys("w", "W"),
+			key.WithHelp("w", "weekly granularity"),
+		),
+		Monthly: key.NewBinding(
+			key.WithKeys("m", "M"),
+			key.WithHelp("m", "monthly granularity"),
+		),
+		HistoryFilter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter alerts or events"),
+		),
+		PrevDay: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous day"),
+		),
+		NextDay: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next day"),
+		),
+		SessionSearch: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search sessions"),
+		),
+		SessionSort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort sessions"),
+		),
+		TagSession: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tag session"),
+		),
+		Graph: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "burn rate graph"),
+		),
+		GraphSeries: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "cost, tokens or both"),
+		),
+		GraphWindow: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "5m/15m/30m/60m window"),
+		),
+		TuneThresholds: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tune alert thresholds"),
+		),
+		TuneLower: key.NewBinding(
+			key.WithKeys("left", "h", "-"),
+			key.WithHelp("←/-", "lower threshold"),
+		),
+		TuneRaise: key.NewBinding(
+			key.WithKeys("right", "l", "+", "="),
+			key.WithHelp("→/+", "raise threshold"),
+		),
+		MuteNotifications: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", "mute/unmute notifications"),
+		),
+		ScreenDump: key.NewBinding(
+			key.WithKeys("f12"),
+			key.WithHelp("F12", "dump screen to file"),
+		),
+		Replay: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "replay session"),
+		),
+		ReplayPause: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "pause/resume"),
+		),
+		ReplayFaster: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "faster"),
+		),
+		ReplaySlower: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "slower"),
+		),
+		ReplayNext: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next event"),
+		),
+		ReplayPrev: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous event"),
+		),
 	}
 }
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,10 +11,26 @@ import (
 	"github.com/nixlim/cc-top/internal/state"
 )
 
+// killNoticeFor is how long the header shows why the kill switch did
+// nothing or failed.
+const killNoticeFor = 5 * time.Second
+
+// setKillNotice shows msg in the header, where it is visible from the
+// Dashboard and Stats views the kill switch is used from.
+func (m *Model) setKillNotice(msg string) {
+	m.killNotice = msg
+	m.killNoticeAt = time.Now()
+}
+
 // initiateKillSwitch begins the kill sequence. If a session is selected,
 // it sends SIGSTOP and shows the confirmation dialog. If no session is
 // selected, it selects the first active session.
 func (m Model) initiateKillSwitch() (tea.Model, tea.Cmd) {
+	if m.scannerDisabled {
+		m.setKillNotice("Kill switch unavailable: process scanner disabled")
+		return m, nil
+	}
+
 	sessions := m.getSessions()
 	if len(sessions) == 0 {
 		return m, nil
@@ -42,13 +59,13 @@ func (m Model) initiateKillSwitch() (tea.Model, tea.Cmd) {
 
 	// Check if session already exited.
 	if target.Exited {
-		m.startupMessage = "Session already exited"
+		m.setKillNotice("Session already exited")
 		return m, nil
 	}
 
 	// Check if we have a PID.
 	if target.PID <= 0 {
-		m.startupMessage = "No PID available for this session"
+		m.setKillNotice("No PID available for this session")
 		return m, nil
 	}
 
@@ -56,10 +73,10 @@ func (m Model) initiateKillSwitch() (tea.Model, tea.Cmd) {
 	err := process.SendSignal(target.PID, process.SignalStop)
 	if err != nil {
 		if process.IsNoSuchProcess(err) {
-			m.startupMessage = "Session already exited"
+			m.setKillNotice("Session already exited")
 			return m, nil
 		}
-		m.startupMessage = fmt.Sprintf("Error stopping process: %v", err)
+		m.setKillNotice(fmt.Sprintf("Error stopping process: %v", err))
 		return m, nil
 	}
 
@@ -81,7 +98,7 @@ func (m Model) handleKillConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// User confirmed: send SIGKILL.
 		err := process.SendSignal(m.killTargetPID, process.SignalKill)
 		if err != nil && !process.IsNoSuchProcess(err) {
-			m.startupMessage = fmt.Sprintf("Error killing process: %v", err)
+			m.setKillNotice(fmt.Sprintf("Error killing process: %v", err))
 		}
 		m.killConfirm = false
 		m.killTargetPID = 0
@@ -92,7 +109,7 @@ func (m Model) handleKillConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// User cancelled: send SIGCONT to resume.
 		err := process.SendSignal(m.killTargetPID, process.SignalContinue)
 		if err != nil && !process.IsNoSuchProcess(err) {
-			m.startupMessage = fmt.Sprintf("Error resuming process: %v", err)
+			m.setKillNotice(fmt.Sprintf("Error resuming process: %v", err))
 		}
 		m.killConfirm = false
 		m.killTargetPID = 0
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -41,8 +42,8 @@ func TestKillSwitch_ExitedSession(t *testing.T) {
 	if m2.killConfirm {
 		t.Error("kill confirm should not activate for exited session")
 	}
-	if m2.startupMessage != "Session already exited" {
-		t.Errorf("startupMessage = %q, want 'Session already exited'", m2.startupMessage)
+	if m2.killNotice != "Session already exited" {
+		t.Errorf("killNotice = %q, want 'Session already exited'", m2.killNotice)
 	}
 }
 
@@ -63,8 +64,8 @@ func TestKillSwitch_NoPID(t *testing.T) {
 	if m2.killConfirm {
 		t.Error("kill confirm should not activate with no PID")
 	}
-	if m2.startupMessage != "No PID available for this session" {
-		t.Errorf("startupMessage = %q, want 'No PID available for this session'", m2.startupMessage)
+	if m2.killNotice != "No PID available for this session" {
+		t.Errorf("killNotice = %q, want 'No PID available for this session'", m2.killNotice)
 	}
 }
 
@@ -126,3 +127,25 @@ func TestKillSwitch_CursorSelection(t *testing.T) {
 	// the intent to target the right session is what matters.
 	_ = m2
 }
+
+func TestKillSwitch_ScannerDisabled(t *testing.T) {
+	mockState := &mockStateProvider{
+		sessions: []state.SessionData{
+			{SessionID: "sess-001", PID: 4242, LastEventAt: time.Now()},
+		},
+	}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState), WithStartView(ViewDashboard), WithScannerDisabled(true))
+	m.width = 160
+	m.height = 40
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlK})
+	if m.killConfirm {
+		t.Error("kill switch should not arm when the scanner is disabled")
+	}
+	if view := m.View(); !strings.Contains(view, "Kill switch unavailable: process scanner disabled") {
+		t.Errorf("the dashboard should show why the kill switch did nothing, got:\n%s", view)
+	}
+	if strings.Contains(m.headerHelp(), "Kill") {
+		t.Errorf("header help should not offer the kill switch: %q", m.headerHelp())
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -26,6 +27,10 @@ const (
 	burnRateMinHeight = 7
 
 	burnRateMaxHeight = 10
+
+	// wideLayoutWidth is the terminal width above which the session list
+	// gets the larger share of the screen, for its wide columns.
+	wideLayoutWidth = 180
 )
 
 func computeDimensions(totalW, totalH int) panelDimensions {
@@ -45,7 +50,11 @@ func computeDimensions(totalW, totalH int) panelDimensions {
 		usableH = 4
 	}
 
-	d.sessionListW = totalW * 40 / 100
+	share := 40
+	if totalW > wideLayoutWidth {
+		share = 60
+	}
+	d.sessionListW = totalW * share / 100
 	if d.sessionListW < 20 {
 		d.sessionListW = 20
 	}
@@ -85,88 +94,133 @@ func computeDimensions(totalW, totalH int) panelDimensions {
 }
 
 var (
+	headerStyle        lipgloss.Style
+	panelBorderStyle   lipgloss.Style
+	panelTitleStyle    lipgloss.Style
+	selectedStyle      lipgloss.Style
+	dimStyle           lipgloss.Style
+	activeStyle        lipgloss.Style
+	idleStyle          lipgloss.Style
+	doneStyle          lipgloss.Style
+	exitedStyle        lipgloss.Style
+	costGreenStyle     lipgloss.Style
+	costYellowStyle    lipgloss.Style
+	costRedStyle       lipgloss.Style
+	alertWarningStyle  lipgloss.Style
+	alertCriticalStyle lipgloss.Style
+	filterMenuStyle    lipgloss.Style
+	killDialogStyle    lipgloss.Style
+	statusBarStyle     lipgloss.Style
+	newBadgeStyle      lipgloss.Style
+	focusBorderColor   lipgloss.Color
+	cursorStyle        lipgloss.Style
+	historyFooterStyle lipgloss.Style
+	detailOverlayStyle lipgloss.Style
+	detailKeyStyle     lipgloss.Style
+)
+
+// applyPalette builds the package styles from p.
+func applyPalette(p palette) {
+	currentPalette = p
+
 	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("15")).
-			Background(lipgloss.Color("62"))
+		Bold(true).
+		Foreground(p.HeaderFg).
+		Background(p.HeaderBg)
 
 	panelBorderStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("240"))
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Border)
 
 	panelTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("69"))
+		Bold(true).
+		Foreground(p.Accent)
 
 	selectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("15")).
-			Background(lipgloss.Color("62"))
+		Bold(true).
+		Foreground(p.HeaderFg).
+		Background(p.HeaderBg)
 
 	dimStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240"))
+		Foreground(p.Dim)
 
 	activeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("82"))
+		Foreground(p.Good)
 
 	idleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226"))
+		Foreground(p.Warning)
 
 	doneStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245"))
+		Foreground(p.Muted)
 
 	exitedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196"))
+		Foreground(p.Critical)
 
 	costGreenStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("82"))
+		Bold(true).
+		Foreground(p.Good)
 
 	costYellowStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("226"))
+		Bold(true).
+		Foreground(p.Warning)
 
 	costRedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("196"))
+		Bold(true).
+		Foreground(p.Critical)
 
 	alertWarningStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("226"))
+		Foreground(p.Warning)
 
 	alertCriticalStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("196"))
+		Bold(true).
+		Foreground(p.Critical)
 
 	filterMenuStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("63")).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Focus).
+		Padding(1, 2)
 
 	killDialogStyle = lipgloss.NewStyle().
-			Border(lipgloss.DoubleBorder()).
-			BorderForeground(lipgloss.Color("196")).
-			Padding(1, 3).
-			Bold(true)
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(p.Critical).
+		Padding(1, 3).
+		Bold(true)
 
 	statusBarStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245"))
+		Foreground(p.Muted)
 
 	newBadgeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("82")).
-			Bold(true)
+		Foreground(p.Good).
+		Bold(true)
 
-	focusBorderColor = lipgloss.Color("63")
+	focusBorderColor = p.Focus
 
 	cursorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("15")).
-			Background(lipgloss.Color("62"))
+		Bold(true).
+		Foreground(p.HeaderFg).
+		Background(p.HeaderBg)
+
+	historyFooterStyle = lipgloss.NewStyle().
+		Bold(true)
 
 	detailOverlayStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("69")).
-				Padding(1, 2)
-)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Accent).
+		Padding(1, 2)
+
+	detailKeyStyle = lipgloss.NewStyle().
+		Bold(true)
+
+	eventTypeStyles = map[string]lipgloss.Style{
+		"user_prompt":   lipgloss.NewStyle().Foreground(p.Prompt),
+		"tool_result":   lipgloss.NewStyle().Foreground(p.Tool),
+		"api_request":   lipgloss.NewStyle().Foreground(p.API),
+		"api_error":     lipgloss.NewStyle().Foreground(p.Critical),
+		"tool_decision": lipgloss.NewStyle().Foreground(p.Decision),
+		"annotation":    lipgloss.NewStyle().Italic(true).Foreground(p.Annotation),
+		alertEventType:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("0")).Background(p.AlertBadge),
+	}
+}
 
 func renderBorderedPanel(content string, w, h int) string {
 	return renderBorderedPanelStyled(content, w, h, panelBorderStyle)
@@ -199,12 +253,12 @@ func stripAnsi(s string) string {
 func (m Model) renderDashboard() string {
 	dims := computeDimensions(m.width, m.height)
 
-	header := m.renderHeader(dims)
-
-	sessionList := m.renderSessionListPanel(dims.sessionListW, dims.sessionListH)
-	burnRatePanel := m.renderBurnRatePanel(dims.burnRateW, dims.burnRateH)
-	eventStream := m.renderEventStreamPanel(dims.eventStreamW, dims.eventStreamH)
-	alertsBar := m.renderAlertsPanel(dims.alertsW, dims.alertsH)
+	w := m.watchdog
+	header := w.timed("header", func() string { return m.renderHeader(dims) })
+	sessionList := w.timed("sessions", func() string { return m.renderSessionListPanel(dims.sessionListW, dims.sessionListH) })
+	burnRatePanel := w.timed("burnrate", func() string { return m.renderBurnRatePanel(dims.burnRateW, dims.burnRateH) })
+	eventStream := w.timed("events", func() string { return m.renderEventStreamPanel(dims.eventStreamW, dims.eventStreamH) })
+	alertsBar := w.timed("alerts", func() string { return m.renderAlertsPanel(dims.alertsW, dims.alertsH) })
 
 	rightCol := lipgloss.JoinVertical(lipgloss.Left, burnRatePanel, eventStream)
 
@@ -226,10 +280,34 @@ func (m Model) renderDashboard() string {
 		layout = m.overlayKillDialog(layout)
 	}
 
+	if m.slaPrompt {
+		layout = m.overlaySLAPrompt(layout)
+	}
+
+	if m.notePrompt {
+		layout = m.overlayNotePrompt(layout)
+	}
+
+	if m.tagPrompt {
+		layout = m.overlayTagPrompt(layout)
+	}
+
 	if m.filterMenu.Active {
 		layout = m.overlayFilterMenu(layout)
 	}
 
+	if m.sessionSortMenu.Active {
+		layout = m.overlaySessionSortMenu(layout)
+	}
+
+	if m.graphOverlay {
+		layout = m.overlayGraph(layout, time.Now())
+	}
+
+	if m.tune != nil {
+		layout = m.overlayTuning(layout)
+	}
+
 	if m.detailOverlay {
 		layout = m.overlayDetail(layout)
 	}
@@ -246,9 +324,29 @@ func (m Model) renderHeader(dims panelDimensions) string {
 		viewLabel += " Global"
 	}
 
-	indicators := m.headerIndicators()
+	indicators := m.headerIndicators() + m.renderKPIBadges() + m.renderBudgetGauge(time.Now()) + m.renderPlanGauge(time.Now())
 	help := m.headerHelp()
 
+	// The time-of-day chart takes priority over the full key hints (which
+	// are also available via ?), and is dropped only if it still won't fit.
+	chart := "  " + renderTodayChart(m.getBurnRate().TodayByHour, time.Now().Hour())
+	fixedW := lipgloss.Width(title) + lipgloss.Width(viewLabel) + lipgloss.Width(indicators)
+	switch {
+	case fixedW+lipgloss.Width(chart)+lipgloss.Width(help) <= m.width:
+		indicators += chart
+	case fixedW+lipgloss.Width(chart)+lipgloss.Width(shortHeaderHelp) <= m.width:
+		indicators += chart
+		help = shortHeaderHelp
+	}
+
+	// Shortly after launch, the full health summary, if it fits.
+	if time.Since(m.launchedAt) < healthSummaryFor {
+		health := " " + m.healthSummary(time.Now(), true)
+		if lipgloss.Width(title+viewLabel+indicators+health+help) <= m.width {
+			indicators += health
+		}
+	}
+
 	padding := m.width - lipgloss.Width(title) - lipgloss.Width(viewLabel) - lipgloss.Width(indicators) - lipgloss.Width(help)
 	if padding < 0 {
 		padding = 0
@@ -261,14 +359,21 @@ func (m Model) renderHeader(dims panelDimensions) string {
 	return headerStyle.Width(m.width).Render(title + viewLabel + indicators + spaces + help)
 }
 
+// shortHeaderHelp replaces headerHelp when the header is too narrow for the
+// full hints alongside the time-of-day chart.
+const shortHeaderHelp = "?:Help  q:Quit "
+
 func (m Model) headerHelp() string {
 	switch m.panelFocus {
 	case FocusEvents:
-		return "Enter:Detail  Esc:Back  a:Alerts  Tab:Stats  q:Quit "
+		return "Enter:Detail  Esc:Back  a:Alerts  Tab:Stats  ?:Help  q:Quit "
 	case FocusAlerts:
-		return "Enter:Detail  Esc:Back  e:Events  Tab:Stats  q:Quit "
+		return "Enter:Detail  Esc:Back  e:Events  Tab:Stats  ?:Help  q:Quit "
 	default:
-		return "a:Alerts  e:Events  Tab:Stats  q:Quit  f:Filter  Ctrl+K:Kill "
+		if m.scannerDisabled {
+			return "a:Alerts  e:Events  Tab:Stats  ?:Help  q:Quit  f:Filter "
+		}
+		return "a:Alerts  e:Events  Tab:Stats  ?:Help  q:Quit  f:Filter  Ctrl+K:Kill "
 	}
 }
 
@@ -333,7 +438,8 @@ func (m Model) overlayFilterMenu(base string) string {
 	return placeOverlay(x, y, dialog, base)
 }
 
-func (m Model) overlayDetail(base string) string {
+// detailOverlayWidth returns the outer width of the detail overlay.
+func (m Model) detailOverlayWidth() int {
 	overlayW := m.width * 70 / 100
 	if overlayW < 40 {
 		overlayW = 40
@@ -341,6 +447,16 @@ func (m Model) overlayDetail(base string) string {
 	if overlayW > m.width-4 {
 		overlayW = m.width - 4
 	}
+	return overlayW
+}
+
+// detailContentWidth returns the usable text width inside the detail overlay.
+func (m Model) detailContentWidth() int {
+	return max(m.detailOverlayWidth()-6, 10)
+}
+
+func (m Model) overlayDetail(base string) string {
+	overlayW := m.detailOverlayWidth()
 	overlayH := m.height * 60 / 100
 	if overlayH < 10 {
 		overlayH = 10
@@ -349,39 +465,19 @@ func (m Model) overlayDetail(base string) string {
 		overlayH = m.height - 4
 	}
 
-	contentW := overlayW - 6
-	if contentW < 10 {
-		contentW = 10
-	}
+	contentW := m.detailContentWidth()
 	contentH := overlayH - 4
 	if contentH < 3 {
 		contentH = 3
 	}
 
-	allLines := strings.Split(m.detailContent, "\n")
-
 	var wrapped []string
-	for _, line := range allLines {
-		if len(line) <= contentW {
-			wrapped = append(wrapped, line)
-		} else {
-			for len(line) > contentW {
-				cutAt := contentW
-				for i := contentW; i > 0; i-- {
-					if line[i] == ' ' {
-						cutAt = i
-						break
-					}
-				}
-				wrapped = append(wrapped, line[:cutAt])
-				line = line[cutAt:]
-				if len(line) > 0 && line[0] == ' ' {
-					line = line[1:]
-				}
-			}
-			if line != "" {
-				wrapped = append(wrapped, line)
+	for _, line := range strings.Split(m.detailContent, "\n") {
+		for i, part := range wrapDetailLine(line, contentW) {
+			if i == 0 {
+				part = styleDetailLine(part)
 			}
+			wrapped = append(wrapped, part)
 		}
 	}
 
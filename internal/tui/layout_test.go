@@ -36,6 +36,10 @@ func (m *mockStateProvider) ListSessions() []state.SessionData {
 	return m.sessions
 }
 
+func (m *mockStateProvider) Snapshot() *state.Snapshot {
+	return state.NewSnapshot(0, append([]state.SessionData(nil), m.sessions...))
+}
+
 func (m *mockStateProvider) GetAggregatedCost() float64 {
 	var total float64
 	for _, s := range m.sessions {
@@ -90,13 +94,34 @@ func (m *mockEventProvider) RecentForSession(sessionID string, limit int) []even
 }
 
 type mockAlertProvider struct {
-	alerts []alerts.Alert
+	alerts   []alerts.Alert
+	silences []alerts.Silence
+}
+
+func (m *mockAlertProvider) Silences() []alerts.Silence {
+	return m.silences
 }
 
 func (m *mockAlertProvider) Active() []alerts.Alert {
 	return m.alerts
 }
 
+func (m *mockAlertProvider) Acknowledge(a alerts.Alert) {
+	m.silence(a)
+}
+
+func (m *mockAlertProvider) Snooze(a alerts.Alert, _ time.Duration) {
+	m.silence(a)
+}
+
+func (m *mockAlertProvider) silence(a alerts.Alert) {
+	for i, x := range m.alerts {
+		if x.Rule == a.Rule && x.SessionID == a.SessionID {
+			m.alerts[i].Silenced = true
+		}
+	}
+}
+
 func (m *mockAlertProvider) ActiveForSession(sessionID string) []alerts.Alert {
 	var result []alerts.Alert
 	for _, a := range m.alerts {
@@ -285,15 +310,21 @@ func TestModel_TabToggle(t *testing.T) {
 	}
 
 	result, _ = m2.Update(tea.KeyMsg{Type: tea.KeyTab})
+	mp := result.(Model)
+	if mp.view != ViewProjects {
+		t.Errorf("after second Tab, view = %d, want ViewProjects (%d)", mp.view, ViewProjects)
+	}
+
+	result, _ = mp.Update(tea.KeyMsg{Type: tea.KeyTab})
 	m3 := result.(Model)
 	if m3.view != ViewHistory {
-		t.Errorf("after second Tab, view = %d, want ViewHistory (%d)", m3.view, ViewHistory)
+		t.Errorf("after third Tab, view = %d, want ViewHistory (%d)", m3.view, ViewHistory)
 	}
 
 	result, _ = m3.Update(tea.KeyMsg{Type: tea.KeyTab})
 	m4 := result.(Model)
 	if m4.view != ViewDashboard {
-		t.Errorf("after third Tab, view = %d, want ViewDashboard (%d)", m4.view, ViewDashboard)
+		t.Errorf("after fourth Tab, view = %d, want ViewDashboard (%d)", m4.view, ViewDashboard)
 	}
 }
 
@@ -510,6 +541,7 @@ func TestModel_ViewZeroDimensions(t *testing.T) {
 		{"startup", ViewStartup},
 		{"dashboard", ViewDashboard},
 		{"stats", ViewStats},
+		{"projects", ViewProjects},
 		{"history", ViewHistory},
 	}
 
@@ -569,6 +601,7 @@ func TestModel_ViewSmallDimensions(t *testing.T) {
 		{"startup", ViewStartup},
 		{"dashboard", ViewDashboard},
 		{"stats", ViewStats},
+		{"projects", ViewProjects},
 		{"history", ViewHistory},
 	}
 
@@ -1215,7 +1248,7 @@ func TestModel_RenderWithFocusedPanels(t *testing.T) {
 	}
 }
 
-func TestTabCycle_DashboardStatsHistory(t *testing.T) {
+func TestTabCycle_DashboardStatsProjectsHistory(t *testing.T) {
 	cfg := config.DefaultConfig()
 	m := NewModel(cfg, WithStartView(ViewDashboard), WithStateProvider(&mockStateProvider{}), WithPersistenceFlag(true))
 	m.width = 120
@@ -1228,9 +1261,15 @@ func TestTabCycle_DashboardStatsHistory(t *testing.T) {
 	}
 
 	result, _ = m1.Update(tea.KeyMsg{Type: tea.KeyTab})
+	mp := result.(Model)
+	if mp.view != ViewProjects {
+		t.Fatalf("Stats Tab: got view %d, want ViewProjects (%d)", mp.view, ViewProjects)
+	}
+
+	result, _ = mp.Update(tea.KeyMsg{Type: tea.KeyTab})
 	m2 := result.(Model)
 	if m2.view != ViewHistory {
-		t.Fatalf("Stats Tab: got view %d, want ViewHistory (%d)", m2.view, ViewHistory)
+		t.Fatalf("Projects Tab: got view %d, want ViewHistory (%d)", m2.view, ViewHistory)
 	}
 
 	result, _ = m2.Update(tea.KeyMsg{Type: tea.KeyTab})
@@ -1249,6 +1288,7 @@ func TestNoPersistenceIndicator(t *testing.T) {
 	}{
 		{"dashboard", ViewDashboard},
 		{"stats", ViewStats},
+		{"projects", ViewProjects},
 		{"history", ViewHistory},
 	}
 
@@ -1,6 +1,9 @@
 package tui
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -8,9 +11,13 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/budget"
 	"github.com/nixlim/cc-top/internal/burnrate"
 	"github.com/nixlim/cc-top/internal/config"
 	"github.com/nixlim/cc-top/internal/events"
+	"github.com/nixlim/cc-top/internal/gitlog"
+	"github.com/nixlim/cc-top/internal/plan"
+	"github.com/nixlim/cc-top/internal/records"
 	"github.com/nixlim/cc-top/internal/scanner"
 	"github.com/nixlim/cc-top/internal/state"
 	"github.com/nixlim/cc-top/internal/stats"
@@ -20,35 +27,38 @@ import (
 // Fields populated from daily_stats have their full values; fields from daily_summaries fallback
 // have IsLegacy=true and performance fields are zero-valued (displayed as "--").
 type DailyStatsRow struct {
-	Date             string
-	TotalCost        float64
-	TokenInput       int64
-	TokenOutput      int64
-	TokenCacheRead   int64
-	TokenCacheWrite  int64
-	SessionCount     int
-	APIRequests      int
-	APIErrors        int
-	LinesAdded       int
-	LinesRemoved     int
-	Commits          int
-	PRsOpened        int
-	CacheEfficiency  float64
-	CacheSavingsUSD  float64
-	ErrorRate        float64
-	RetryRate        float64
-	AvgAPILatency    float64 // seconds
-	LatencyP50       float64 // seconds
-	LatencyP95       float64 // seconds
-	LatencyP99       float64 // seconds
-	ModelBreakdown   []stats.ModelStats
-	TopTools         []stats.ToolUsage
-	ToolPerformance  []stats.ToolPerf
-	ErrorCategories  map[string]int
+	Date              string
+	TotalCost         float64
+	TokenInput        int64
+	TokenOutput       int64
+	TokenCacheRead    int64
+	TokenCacheWrite   int64
+	SessionCount      int
+	APIRequests       int
+	APIErrors         int
+	LinesAdded        int
+	LinesRemoved      int
+	Commits           int
+	PRsOpened         int
+	CacheEfficiency   float64
+	CacheSavingsUSD   float64
+	ErrorRate         float64
+	RetryRate         float64
+	AvgAPILatency     float64 // seconds
+	LatencyP50        float64 // seconds
+	LatencyP95        float64 // seconds
+	LatencyP99        float64 // seconds
+	ModelBreakdown    []stats.ModelStats
+	TopTools          []stats.ToolUsage
+	ToolPerformance   []stats.ToolPerf
+	ErrorCategories   map[string]int
 	LanguageBreakdown map[string]int
-	DecisionSources  map[string]int
-	MCPToolUsage     map[string]int
-	IsLegacy         bool // true when sourced from daily_summaries (pre-v2)
+	DecisionSources   map[string]int
+	MCPToolUsage      map[string]int
+	AccountBreakdown  []stats.AccountStats
+	ProjectBreakdown  []stats.ProjectStats
+	ContextUsage      []stats.ContextUsage
+	IsLegacy          bool // true when sourced from daily_summaries (pre-v2)
 }
 
 // BurnRateDailySummary holds aggregated burn rate data for a single day.
@@ -81,6 +91,15 @@ type AlertHistoryRow struct {
 	Message   string
 	SessionID string
 	FiredAt   time.Time
+	Note      string
+}
+
+// ProjectionAccuracyRow compares the daily projection shown at the end of a
+// completed day with what the day actually cost.
+type ProjectionAccuracyRow struct {
+	Date          string
+	ProjectedCost float64
+	ActualCost    float64
 }
 
 // HistoryProvider supplies historical data for the redesigned History tab.
@@ -89,7 +108,27 @@ type HistoryProvider interface {
 	QueryDailyStats(days int) []DailyStatsRow
 	QueryBurnRateDailySummary(days int) []BurnRateDailySummary
 	QueryBurnRateSnapshots(date string) []BurnRateSnapshotRow
+	QueryBurnRateHourly(days int) map[string][24]float64 // date -> average $/hr per UTC hour
 	QueryAlertHistory(days int, ruleFilter string) []AlertHistoryRow
+	QueryProjectionAccuracy(days int) []ProjectionAccuracyRow
+	// SetAlertNote attaches a note to the persisted alert identified by
+	// rule, session and firing time; an empty note removes it.
+	SetAlertNote(rule, sessionID string, firedAt time.Time, note string)
+}
+
+// ReplaySource returns the persisted events of a session, for replaying it.
+type ReplaySource interface {
+	SessionEvents(sessionID string) []state.Event
+}
+
+// EventHistory returns persisted events for the History Events sub-tab.
+type EventHistory interface {
+	// QueryEvents returns the events recorded in [from, to), newest first,
+	// optionally only those of one session and one event name. At most
+	// limit events are returned.
+	QueryEvents(from, to time.Time, sessionID, name string, limit int) []events.FormattedEvent
+	// EventFacets lists the sessions and event names recorded in [from, to).
+	EventFacets(from, to time.Time) (sessions, names []string)
 }
 
 type ViewState int
@@ -99,6 +138,7 @@ const (
 	ViewDashboard
 	ViewStats
 	ViewHistory
+	ViewProjects
 )
 
 type PanelFocus int
@@ -114,6 +154,7 @@ type tickMsg time.Time
 type StateProvider interface {
 	GetSession(sessionID string) *state.SessionData
 	ListSessions() []state.SessionData
+	Snapshot() *state.Snapshot
 	GetAggregatedCost() float64
 	QueryDailySummaries(days int) []state.DailySummary
 	DroppedWrites() int64
@@ -132,6 +173,13 @@ type EventProvider interface {
 type AlertProvider interface {
 	Active() []alerts.Alert
 	ActiveForSession(sessionID string) []alerts.Alert
+	// Acknowledge and Snooze mark an alert's rule and session silenced, which
+	// takes them off the alert bar, and keep them from firing again until
+	// the rule clears or for d.
+	Acknowledge(a alerts.Alert)
+	Snooze(a alerts.Alert, d time.Duration)
+	// Silences returns the rule silences in effect (cc-top silence).
+	Silences() []alerts.Silence
 }
 
 type StatsProvider interface {
@@ -145,6 +193,33 @@ type ScannerProvider interface {
 	Rescan()
 }
 
+// CommitProvider returns the git commits linked to a session.
+type CommitProvider interface {
+	Commits(sessionID string) []gitlog.Commit
+}
+
+// SLAProvider reads and sets the expected duration of sessions.
+type SLAProvider interface {
+	ExpectedDuration(s state.SessionData) time.Duration
+	SetExpectedDuration(sessionID string, d time.Duration)
+}
+
+// BudgetProvider reports spending against the configured budgets.
+type BudgetProvider interface {
+	Status(now time.Time) []budget.Status
+}
+
+// PlanProvider reports token usage against the subscription plan quotas.
+type PlanProvider interface {
+	Name() string
+	Usage(now time.Time) []plan.Usage
+}
+
+// RecordsProvider reports the all-time records.
+type RecordsProvider interface {
+	Records(now time.Time) []records.Record
+}
+
 type SettingsWriter interface {
 	EnableTelemetry() error
 	FixMisconfigured() error
@@ -167,23 +242,69 @@ type Model struct {
 	scanner  ScannerProvider
 	settings SettingsWriter
 	history  HistoryProvider
+	commits  CommitProvider
+	sla      SLAProvider
+	budgets  BudgetProvider
+	plan     PlanProvider
+	records  RecordsProvider
+
+	writerStats WriterStatsProvider
+	tuner       ThresholdTuner
+	muter       NotificationMuter
+	tagger      SessionTagger
+
+	replaySource ReplaySource
+	eventHistory EventHistory
+	replay       *replayState // open session replay, nil when closed
 
 	selectedSession    string
 	sessionCursor      int
 	sessionScrollOffset int
 
+	sessionSearch bool   // typing into the Sessions panel search
+	sessionQuery  string // filters the session list; see filterSessions
+
+	sessionSort     string // key of sessionSortKeys; empty keeps the snapshot order
+	sessionSortAsc  bool
+	sessionSortMenu FilterMenuState
+
 	eventScrollPos int
 	autoScroll     bool
 	eventFilter    EventFilter
 	filterMenu     FilterMenuState
 
-	startupMessage string
+	startupMessage   string
+	startupCursor    int             // index into startupGroupKeys
+	startupCollapsed map[string]bool // group key -> collapsed
+	// autoAdvance is set with start_view = "auto" until the dashboard opens.
+	autoAdvance bool
 
 	killConfirm    bool
 	killTargetPID  int
 	killTargetInfo string
 
+	slaPrompt  bool
+	slaTarget  string
+	slaInput   string
+	slaMessage string
+
+	notePrompt bool
+	noteTarget AlertHistoryRow
+	noteInput  string
+
+	tagPrompt      bool
+	tagTarget      string
+	tagInput       string
+	tagNoteInput   string
+	tagEditingNote bool
+
 	cachedBurnRate burnrate.BurnRate
+	rateSamples    []rateSample // global burn rate for the graph overlay
+	kpis           map[string]float64 // [display.badges] KPI values
+	kpisAt         time.Time
+	// snapshot is the session state taken on the last tick, shared by every
+	// frame until the next one.
+	snapshot *state.Snapshot
 
 	alertScrollPos int
 	alertCursor    int
@@ -194,24 +315,68 @@ type Model struct {
 	detailContent   string
 	detailTitle     string
 	detailScrollPos int
+	helpOverlay     bool
+
+	graphOverlay bool
+	graphSeries  int // graphBoth, graphCost or graphTokens
+	graphWindow  int // index into graphWindows
+
+	tune *tuneState // open threshold tuning overlay, nil when closed
+
+	notifyMuted bool // do-not-disturb toggled on with Ctrl+N
+
+	termOut      io.Writer // terminal for OSC 9;4 taskbar progress
+	termTitle    string    // window title last set, "" when unset
+	termProgress string    // taskbar progress sequence last written
 
-	statsScrollPos int
+	statsScrollPos   int
+	statsModelFamily bool // group the Model Breakdown by family
 
-	isPersistent bool
+	// Projects view state
+	projectsScrollPos int
 
-	historySection     int // 0=Overview, 1=Performance, 2=Burn Rate, 3=Alerts
+	pendingSelection string // remembered session to select once it is listed
+
+	isPersistent    bool
+	scannerDisabled bool
+
+	// launchedAt is when the model was created; the header shows the full
+	// health summary for a while after it.
+	launchedAt   time.Time
+	notifyStatus func() string
+
+	historySection     int // 0=Overview, 1=Performance, 2=Burn Rate, 3=Alerts, 4=Events
 	historyCursor      int
 	historyGranularity string
 	historyScrollPos   int
 	historyAlertFilter string          // "" = all, or specific rule name
-	historyFilterMenu  FilterMenuState // filter menu for Alerts sub-tab
+	historyFilterMenu  FilterMenuState // filter menu for Alerts and Events sub-tabs
+
+	historyEventDay     int    // Events sub-tab day, in days before today
+	historyEventSession string // "" = all sessions
+	historyEventType    string // "" = all event names
 
 	refreshRate time.Duration
+	watchdog    *RenderWatchdog
+
+	screenDumpDir    string
+	screenDumpNotice string // result of the last F12 dump
+	screenDumpAt     time.Time
+
+	configNotice   string // result of the last config reload
+	configNoticeAt time.Time
+	killNotice     string // why the last kill switch press did nothing or failed
+	killNoticeAt   time.Time
+	alertFlash     alerts.Alert // last alert rung by the bell backend
+	alertFlashAt   time.Time
 
 	onShutdown func()
 }
 
 func NewModel(cfg config.Config, opts ...ModelOption) Model {
+	// Styles are package-level, so the theme applies to every model.
+	applyTheme(cfg.Display.Theme)
+
 	m := Model{
 		view:               ViewStartup,
 		keys:               DefaultKeyMap(),
@@ -219,13 +384,16 @@ func NewModel(cfg config.Config, opts ...ModelOption) Model {
 		autoScroll:         true,
 		eventFilter:        NewEventFilter(),
 		filterMenu:         NewFilterMenu(),
+		startupCollapsed:   make(map[string]bool),
 		historyGranularity: "daily",
 		refreshRate:        time.Duration(cfg.Display.RefreshRateMS) * time.Millisecond,
+		launchedAt:         time.Now(),
 	}
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+	m.applyStartView()
 
 	return m
 }
@@ -256,6 +424,30 @@ func WithScannerProvider(s ScannerProvider) ModelOption {
 	return func(m *Model) { m.scanner = s }
 }
 
+func WithCommitProvider(c CommitProvider) ModelOption {
+	return func(m *Model) { m.commits = c }
+}
+
+func WithSLAProvider(p SLAProvider) ModelOption {
+	return func(m *Model) { m.sla = p }
+}
+
+// WithBudgetProvider shows the budget gauge in the dashboard header.
+func WithBudgetProvider(p BudgetProvider) ModelOption {
+	return func(m *Model) { m.budgets = p }
+}
+
+// WithPlanProvider shows plan utilization in the dashboard header and the
+// Stats view.
+func WithPlanProvider(p PlanProvider) ModelOption {
+	return func(m *Model) { m.plan = p }
+}
+
+// WithRecordsProvider shows the all-time records in the Stats view.
+func WithRecordsProvider(p RecordsProvider) ModelOption {
+	return func(m *Model) { m.records = p }
+}
+
 func WithSettingsWriter(s SettingsWriter) ModelOption {
 	return func(m *Model) { m.settings = s }
 }
@@ -272,10 +464,33 @@ func WithPersistenceFlag(isPersistent bool) ModelOption {
 	return func(m *Model) { m.isPersistent = isPersistent }
 }
 
+// WithScannerDisabled marks the process scanner as turned off (--no-scanner).
+// PID/terminal columns, process actions and the kill switch are hidden.
+func WithScannerDisabled(disabled bool) ModelOption {
+	return func(m *Model) { m.scannerDisabled = disabled }
+}
+
+// WithRenderWatchdog enables timing of View and Update calls.
+func WithRenderWatchdog(w *RenderWatchdog) ModelOption {
+	return func(m *Model) { m.watchdog = w }
+}
+
 func WithHistoryProvider(h HistoryProvider) ModelOption {
 	return func(m *Model) { m.history = h }
 }
 
+// WithReplaySource makes session replays read persisted events instead of
+// the events held in memory.
+func WithReplaySource(r ReplaySource) ModelOption {
+	return func(m *Model) { m.replaySource = r }
+}
+
+// WithEventHistory enables the History Events sub-tab, reading persisted
+// events from e.
+func WithEventHistory(e EventHistory) ModelOption {
+	return func(m *Model) { m.eventHistory = e }
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.tickCmd(),
@@ -289,6 +504,10 @@ func (m Model) tickCmd() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.watchdog != nil {
+		defer m.watchdog.done(fmt.Sprintf("Update %T", msg), time.Now())
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -296,21 +515,83 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
+		if m.state != nil {
+			m.snapshot = m.state.Snapshot()
+		}
+		m.maybeAutoAdvance()
+		if m.replay != nil {
+			m.replay.advance(m.refreshRate)
+		}
 		m.cachedBurnRate = m.computeBurnRate()
-		return m, m.tickCmd()
+		m.recordRateSample(time.Now())
+		m.refreshKPIs(time.Now())
+		m.restoreSelection()
+		status := m.updateTerminalStatus(time.Now())
+		return m, tea.Batch(m.tickCmd(), status)
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
+
+	case ConfigReloadedMsg:
+		return m.applyConfigReload(config.Reload(msg), time.Now()), nil
+
+	case AlertFlashMsg:
+		m.alertFlash, m.alertFlashAt = alerts.Alert(msg), time.Now()
+		return m, nil
 	}
 
 	return m, nil
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// F12 captures whatever is on screen, including prompts and overlays.
+	if key.Matches(msg, m.keys.ScreenDump) {
+		now := time.Now()
+		if path, err := m.dumpScreen(now); err != nil {
+			m.screenDumpNotice = "[!] " + err.Error()
+		} else {
+			m.screenDumpNotice = "Screen saved to " + path
+		}
+		m.screenDumpAt = now
+		return m, nil
+	}
+
 	if m.killConfirm {
 		return m.handleKillConfirmKey(msg)
 	}
 
+	if m.slaPrompt {
+		return m.handleSLAPromptKey(msg)
+	}
+
+	if m.notePrompt {
+		return m.handleNotePromptKey(msg)
+	}
+
+	if m.tagPrompt {
+		return m.handleTagPromptKey(msg)
+	}
+
+	if m.sessionSearch {
+		return m.handleSessionSearchKey(msg)
+	}
+
+	if m.replay != nil && !m.helpOverlay && !key.Matches(msg, m.keys.Help) {
+		return m.handleReplayKey(msg)
+	}
+
+	if m.helpOverlay {
+		if key.Matches(msg, m.keys.Help) || key.Matches(msg, m.keys.Escape) {
+			m.helpOverlay = false
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, m.keys.Help) {
+		m.helpOverlay = true
+		return m, nil
+	}
+
 	if m.detailOverlay {
 		return m.handleDetailOverlayKey(msg)
 	}
@@ -319,6 +600,18 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleFilterMenuKey(msg)
 	}
 
+	if m.sessionSortMenu.Active {
+		return m.handleSessionSortMenuKey(msg)
+	}
+
+	if m.graphOverlay {
+		return m.handleGraphKey(msg)
+	}
+
+	if m.tune != nil {
+		return m.handleTuningKey(msg)
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Quit):
 		m.quitting = true
@@ -327,6 +620,10 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Quit
 
+	case key.Matches(msg, m.keys.MuteNotifications):
+		m.toggleNotificationsMuted()
+		return m, nil
+
 	case key.Matches(msg, m.keys.KillSwitch):
 		if m.view == ViewDashboard || m.view == ViewStats {
 			return m.initiateKillSwitch()
@@ -342,6 +639,8 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleStatsKey(msg)
 	case ViewHistory:
 		return m.handleHistoryKey(msg)
+	case ViewProjects:
+		return m.handleProjectsKey(msg)
 	}
 
 	return m, nil
@@ -364,7 +663,7 @@ func (m Model) handleStartupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Fix):
-		if m.settings != nil {
+		if m.settings != nil && !m.scannerDisabled {
 			if err := m.settings.FixMisconfigured(); err != nil {
 				m.startupMessage = "Error: " + err.Error()
 			} else {
@@ -379,6 +678,29 @@ func (m Model) handleStartupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.startupMessage = "Rescanning..."
 		}
 		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.startupCursor > 0 {
+			m.startupCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.startupCursor < len(m.startupGroupKeys())-1 {
+			m.startupCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.ToggleGroup):
+		keys := m.startupGroupKeys()
+		if m.startupCursor < len(keys) {
+			if m.startupCollapsed == nil {
+				m.startupCollapsed = make(map[string]bool)
+			}
+			k := keys[m.startupCursor]
+			m.startupCollapsed[k] = !m.startupCollapsed[k]
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -396,6 +718,10 @@ func (m Model) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterMenu.Cursor = 0
 		return m, nil
 
+	case key.Matches(msg, m.keys.Graph):
+		m.graphOverlay = true
+		return m, nil
+
 	case key.Matches(msg, m.keys.FocusAlerts):
 		if m.panelFocus != FocusAlerts {
 			m.panelFocus = FocusAlerts
@@ -407,7 +733,7 @@ func (m Model) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.panelFocus != FocusEvents {
 			m.panelFocus = FocusEvents
 			m.autoScroll = false
-			evts := m.getFilteredEvents(m.cfg.Display.EventBufferSize)
+			evts := m.getFilteredEvents(m.eventLimit())
 			if len(evts) > 0 {
 				m.eventCursor = len(evts) - 1
 			}
@@ -453,16 +779,43 @@ func (m Model) handleSessionsPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Enter):
 		sessions := m.getSessions()
 		if m.sessionCursor >= 0 && m.sessionCursor < len(sessions) {
-			m.selectedSession = sessions[m.sessionCursor].SessionID
+			s := sessions[m.sessionCursor]
+			if s.SessionID == m.selectedSession {
+				m.detailOverlay = true
+				m.detailTitle = "Session Detail"
+				m.detailContent = m.formatSessionDetail(s)
+				m.detailScrollPos = 0
+				return m, nil
+			}
+			m.selectedSession = s.SessionID
 			m.eventFilter.SessionID = m.selectedSession
+			m.pendingSelection = ""
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keys.Escape):
 		m.selectedSession = ""
+		m.pendingSelection = ""
 		m.eventFilter.SessionID = ""
 		return m, nil
 
+	case key.Matches(msg, m.keys.SLATimer):
+		return m.openSLAPrompt()
+
+	case key.Matches(msg, m.keys.TagSession):
+		return m.openTagPrompt()
+
+	case key.Matches(msg, m.keys.SessionSearch):
+		m.sessionSearch = true
+		return m, nil
+
+	case key.Matches(msg, m.keys.SessionSort):
+		m.openSessionSortMenu()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Replay):
+		return m.openReplay()
+
 	case key.Matches(msg, m.keys.ScrollDown):
 		m.autoScroll = false
 		m.eventScrollPos++
@@ -480,7 +833,7 @@ func (m Model) handleSessionsPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleEventsPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	evts := m.getFilteredEvents(m.cfg.Display.EventBufferSize)
+	evts := m.getFilteredEvents(m.eventLimit())
 
 	switch {
 	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.ScrollUp):
@@ -540,6 +893,33 @@ func (m Model) handleAlertsPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.AckAlert), key.Matches(msg, m.keys.SnoozeAlert):
+		if m.alertCursor < 0 || m.alertCursor >= len(activeAlerts) {
+			return m, nil
+		}
+		a := activeAlerts[m.alertCursor]
+		if key.Matches(msg, m.keys.AckAlert) {
+			m.alerts.Acknowledge(a)
+		} else {
+			m.alerts.Snooze(a, time.Duration(m.cfg.Alerts.SnoozeMinutes)*time.Minute)
+		}
+		// Other alerts of the same rule and session go too.
+		m.alertCursor = max(min(m.alertCursor, len(m.getActiveAlerts())-1), 0)
+		return m, nil
+
+	case key.Matches(msg, m.keys.TuneThresholds):
+		if m.tuner != nil {
+			m.openTuning(time.Now())
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.AlertNote):
+		if m.alertCursor >= 0 && m.alertCursor < len(activeAlerts) {
+			a := activeAlerts[m.alertCursor]
+			m.openNotePrompt(AlertHistoryRow{Rule: a.Rule, Severity: a.Severity, Message: a.Message, SessionID: a.SessionID, FiredAt: a.FiredAt})
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.Escape):
 		m.panelFocus = FocusSessions
 		return m, nil
@@ -575,7 +955,10 @@ func (m Model) formatEventDetail(e events.FormattedEvent) string {
 	var lines []string
 	lines = append(lines, "Type:      "+e.EventType)
 	lines = append(lines, "Session:   "+e.SessionID)
-	lines = append(lines, "Timestamp: "+e.Timestamp.Format("2006-01-02 15:04:05"))
+	lines = append(lines, "Timestamp: "+m.formatDateTime(e.Timestamp))
+	if e.EventType == alertEventType {
+		lines = append(lines, "Severity:  "+e.RawAttributes["severity"])
+	}
 	if e.Success != nil {
 		if *e.Success {
 			lines = append(lines, "Status:    success")
@@ -585,7 +968,139 @@ func (m Model) formatEventDetail(e events.FormattedEvent) string {
 	}
 	lines = append(lines, "")
 	lines = append(lines, "Content:")
-	lines = append(lines, e.Formatted)
+	lines = append(lines, detailBlock(e.Formatted, "")...)
+	if len(e.RawAttributes) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, "Attributes:")
+		lines = append(lines, detailAttributes(e.RawAttributes)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// maxDetailAnnotations is the number of most recent hook annotations shown
+// in the session detail overlay.
+const maxDetailAnnotations = 10
+
+// sessionAnnotations returns the last limit hook annotations of a session,
+// oldest first, formatted as "  <time>  text".
+func (m Model) sessionAnnotations(s state.SessionData, limit int) []string {
+	var notes []string
+	for _, e := range s.Events {
+		if e.Name != state.AnnotationEventName {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("  %s  %s", m.formatClock(e.Timestamp), e.Attributes["text"]))
+	}
+	if len(notes) > limit {
+		notes = notes[len(notes)-limit:]
+	}
+	return notes
+}
+
+// maxDetailCommits caps the commits listed in the session detail overlay.
+const maxDetailCommits = 5
+
+// sessionCommits returns the commits linked to sessionID, or nil when git
+// correlation is disabled.
+func (m Model) sessionCommits(sessionID string) []gitlog.Commit {
+	if m.commits == nil {
+		return nil
+	}
+	return m.commits.Commits(sessionID)
+}
+
+func (m Model) formatSessionDetail(s state.SessionData) string {
+	var lines []string
+	lines = append(lines, "Session:   "+s.SessionID)
+	if s.PID > 0 {
+		lines = append(lines, fmt.Sprintf("PID:       %d", s.PID))
+	}
+	if len(s.DuplicatePIDs) > 0 {
+		pids := make([]string, len(s.DuplicatePIDs))
+		for i, pid := range s.DuplicatePIDs {
+			pids[i] = strconv.Itoa(pid)
+		}
+		lines = append(lines, alertWarningStyle.Render("Duplicate: also exported by PID "+strings.Join(pids, ", ")+" (merged into this session)"))
+	}
+	if s.CWD != "" {
+		lines = append(lines, "CWD:       "+s.CWD)
+	}
+	if s.Repo != "" {
+		lines = append(lines, "Repo:      "+s.Repo)
+	}
+	if s.Branch != "" {
+		lines = append(lines, "Branch:    "+s.Branch)
+	}
+	if s.Model != "" {
+		lines = append(lines, "Model:     "+s.Model)
+	}
+	if s.Metadata.Environment != "" {
+		lines = append(lines, "Env:       "+s.Metadata.Environment)
+	}
+	if s.Metadata.HostName != "" {
+		lines = append(lines, "Host:      "+s.Metadata.HostName)
+	}
+	if s.Metadata.ServiceInstanceID != "" {
+		lines = append(lines, "Instance:  "+s.Metadata.ServiceInstanceID)
+	}
+	lines = append(lines, fmt.Sprintf("Cost:      $%.2f", s.TotalCost))
+	lines = append(lines, fmt.Sprintf("Tokens:    %d", s.TotalTokens))
+	lines = append(lines, "Active:    "+formatDuration(s.ActiveTime))
+	tags, note := m.sessionTags(s.SessionID)
+	if len(tags) > 0 {
+		lines = append(lines, "Tags:      "+strings.Join(tags, ", "))
+	}
+	if note != "" {
+		lines = append(lines, "Note:      "+note)
+	}
+	if sla := m.formatSLA(&s, time.Now()); sla != "" {
+		lines = append(lines, "SLA:       "+sla)
+	}
+	if m.burnRate != nil && !s.Exited {
+		br := m.burnRate.Get(s.SessionID)
+		lines = append(lines, fmt.Sprintf("Burn rate: $%.2f/hr %s  %s tokens/min",
+			br.HourlyRate, trendArrow(br.Trend), formatNumber(int64(br.TokenVelocity))))
+		lines = append(lines, fmt.Sprintf("Projected: $%.2f/day  $%.2f/mon", br.DailyProjection, br.MonthlyProjection))
+	}
+
+	if notes := m.sessionAnnotations(s, maxDetailAnnotations); len(notes) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, "Annotations:")
+		lines = append(lines, notes...)
+	}
+
+	if commits := m.sessionCommits(s.SessionID); len(commits) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("Commits:   %d ($%.2f/commit)", len(commits), s.TotalCost/float64(len(commits))))
+		for i, c := range commits {
+			if i == maxDetailCommits {
+				lines = append(lines, fmt.Sprintf("  ... %d more", len(commits)-maxDetailCommits))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("  %s  %s  %s", c.ShortHash(), m.formatClockMinutes(c.Time), c.Subject))
+		}
+	}
+
+	if waterfall := m.formatSpanWaterfall(s); len(waterfall) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, waterfall...)
+	}
+
+	report := stats.DetectThrash(s, stats.DefaultThrashThreshold)
+	lines = append(lines, "")
+	if !report.PossibleThrash() {
+		lines = append(lines, "Duplicated work: none detected")
+		return strings.Join(lines, "\n")
+	}
+
+	since := "session start"
+	if !report.LastCommit.IsZero() {
+		since = "last commit at " + m.formatClock(report.LastCommit)
+	}
+	lines = append(lines, fmt.Sprintf("Possible thrash: %d file(s) touched repeatedly since %s", len(report.Files), since))
+	for _, f := range report.Files {
+		lines = append(lines, fmt.Sprintf("  %s  (%d reads, %d edits)", f.Path, f.Reads, f.Edits))
+	}
 	return strings.Join(lines, "\n")
 }
 
@@ -598,17 +1113,18 @@ func (m Model) formatAlertDetail(a alerts.Alert) string {
 	} else {
 		lines = append(lines, "Session:   (global)")
 	}
-	lines = append(lines, "Fired at:  "+a.FiredAt.Format("2006-01-02 15:04:05"))
+	lines = append(lines, "Fired at:  "+m.formatDateTime(a.FiredAt))
 	lines = append(lines, "")
 	lines = append(lines, "Message:")
-	lines = append(lines, a.Message)
+	lines = append(lines, detailBlock(a.Message, "")...)
+	lines = append(lines, m.alertNoteLines(a.Rule, a.SessionID, a.FiredAt)...)
 	return strings.Join(lines, "\n")
 }
 
 func (m Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Tab):
-		m.view = ViewHistory
+		m.view = ViewProjects
 		return m, nil
 	case key.Matches(msg, m.keys.Up):
 		if m.statsScrollPos > 0 {
@@ -618,6 +1134,26 @@ func (m Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Down):
 		m.statsScrollPos++
 		return m, nil
+	case key.Matches(msg, m.keys.ModelFamily):
+		m.statsModelFamily = !m.statsModelFamily
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m Model) handleProjectsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Tab):
+		m.view = ViewHistory
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		if m.projectsScrollPos > 0 {
+			m.projectsScrollPos--
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Down):
+		m.projectsScrollPos++
+		return m, nil
 	}
 	return m, nil
 }
@@ -642,62 +1178,62 @@ func (m Model) handleHistoryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case key.Matches(msg, m.keys.Enter):
 		return m.openHistoryDetail()
-	}
-
-	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
-		switch msg.Runes[0] {
-		case '1':
-			m.historySection = 0
-			m.historyCursor = 0
-			m.historyScrollPos = 0
-			return m, nil
-		case '2':
-			m.historySection = 1
-			m.historyCursor = 0
-			m.historyScrollPos = 0
-			return m, nil
-		case '3':
-			m.historySection = 2
-			m.historyCursor = 0
-			m.historyScrollPos = 0
-			return m, nil
-		case '4':
-			m.historySection = 3
-			m.historyCursor = 0
-			m.historyScrollPos = 0
-			return m, nil
-		case 'd', 'D':
-			if m.historySection != 3 {
-				m.historyGranularity = "daily"
-				m.historyCursor = 0
-				m.historyScrollPos = 0
-			}
-			return m, nil
-		case 'w', 'W':
-			if m.historySection != 3 {
-				m.historyGranularity = "weekly"
-				m.historyCursor = 0
-				m.historyScrollPos = 0
-			}
-			return m, nil
-		case 'm', 'M':
-			if m.historySection != 3 {
-				m.historyGranularity = "monthly"
-				m.historyCursor = 0
-				m.historyScrollPos = 0
-			}
-			return m, nil
-		case '/':
-			if m.historySection == 3 {
-				m.openHistoryAlertFilterMenu()
+	case key.Matches(msg, m.keys.HistorySection):
+		m.historySection = int(msg.Runes[0] - '1')
+		m.historyCursor = 0
+		m.historyScrollPos = 0
+		return m, nil
+	case key.Matches(msg, m.keys.Daily):
+		m.setHistoryGranularity("daily")
+		return m, nil
+	case key.Matches(msg, m.keys.Weekly):
+		m.setHistoryGranularity("weekly")
+		return m, nil
+	case key.Matches(msg, m.keys.Monthly):
+		m.setHistoryGranularity("monthly")
+		return m, nil
+	case key.Matches(msg, m.keys.HistoryFilter):
+		switch m.historySection {
+		case 3:
+			m.openHistoryAlertFilterMenu()
+		case 4:
+			m.openHistoryEventFilterMenu()
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.PrevDay):
+		if m.historySection == 4 {
+			m.stepHistoryEventDay(-1)
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.NextDay):
+		if m.historySection == 4 {
+			m.stepHistoryEventDay(1)
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.AlertNote):
+		if m.historySection == 3 && m.history != nil {
+			alerts := m.history.QueryAlertHistory(90, m.historyAlertFilter)
+			if m.historyCursor < len(alerts) {
+				m.openNotePrompt(alerts[m.historyCursor])
 			}
-			return m, nil
 		}
+		return m, nil
 	}
 
 	return m, nil
 }
 
+// setHistoryGranularity switches the History aggregation level. The Alerts
+// and Events sub-tabs have no granularity, so the change is ignored there.
+func (m *Model) setHistoryGranularity(g string) {
+	if m.historySection >= 3 {
+		return
+	}
+	m.historyGranularity = g
+	m.historyCursor = 0
+	m.historyScrollPos = 0
+}
+
 func (m Model) handleFilterMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Escape):
@@ -744,11 +1280,24 @@ func (m *Model) applyFilter() {
 	}
 }
 
+// currentSnapshot returns the snapshot taken on the last tick, or a fresh
+// one before the first tick.
+func (m Model) currentSnapshot() *state.Snapshot {
+	if m.snapshot != nil || m.state == nil {
+		return m.snapshot
+	}
+	return m.state.Snapshot()
+}
+
+// getSessions returns the sessions shown in the Sessions panel, in the
+// chosen sort order. The slice may be shared with the snapshot and must not
+// be modified.
 func (m Model) getSessions() []state.SessionData {
-	if m.state == nil {
+	snap := m.currentSnapshot()
+	if snap == nil {
 		return nil
 	}
-	return m.state.ListSessions()
+	return m.sortSessions(filterSessions(snap.Sessions, m.sessionQuery, m.sessionTags))
 }
 
 func (m Model) headerIndicators() string {
@@ -756,13 +1305,32 @@ func (m Model) headerIndicators() string {
 	if !m.isPersistent {
 		parts = append(parts, "[No persistence]")
 	}
-	if m.state != nil && m.state.DroppedWrites() > 0 {
-		parts = append(parts, "[!] Writes dropped")
+	if m.screenDumpNotice != "" && time.Since(m.screenDumpAt) < screenDumpNoticeFor {
+		parts = append(parts, m.screenDumpNotice)
+	}
+	if m.configNotice != "" && time.Since(m.configNoticeAt) < configNoticeFor {
+		parts = append(parts, m.configNotice)
+	}
+	if m.killNotice != "" && time.Since(m.killNoticeAt) < killNoticeFor {
+		parts = append(parts, m.killNotice)
+	}
+	if dnd := m.notifyIndicator(); dnd != "" {
+		parts = append(parts, dnd)
 	}
-	if len(parts) == 0 {
-		return ""
+	var out string
+	if len(parts) > 0 {
+		out = " " + dimStyle.Render(strings.Join(parts, " "))
 	}
-	return " " + dimStyle.Render(strings.Join(parts, " "))
+	// The Dashboard header lists every check shortly after launch.
+	if m.view != ViewDashboard || time.Since(m.launchedAt) >= healthSummaryFor {
+		if health := m.healthSummary(time.Now(), false); health != "" {
+			out += " " + health
+		}
+	}
+	if flash := m.alertFlashIndicator(time.Now()); flash != "" {
+		out += " " + flash
+	}
+	return out
 }
 
 func (m Model) View() string {
@@ -770,6 +1338,8 @@ func (m Model) View() string {
 		return "Shutting down...\n"
 	}
 
+	defer m.watchdog.done("View "+viewName(m.view), time.Now())
+
 	var output string
 	switch m.view {
 	case ViewStartup:
@@ -780,6 +1350,15 @@ func (m Model) View() string {
 		output = m.renderStats()
 	case ViewHistory:
 		output = m.renderHistory()
+	case ViewProjects:
+		output = m.renderProjects()
+	}
+	if m.replay != nil {
+		output = m.renderReplay()
+	}
+
+	if m.helpOverlay {
+		output = m.overlayHelp(output)
 	}
 
 	if m.height > 0 {
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxNoteInput caps the length of an alert note.
+	maxNoteInput = 200
+	// maxEarlierNotes is how many notes on earlier alerts of the same rule
+	// the alert detail overlay lists.
+	maxEarlierNotes = 5
+)
+
+// openNotePrompt opens the note prompt for the persisted alert a, filled
+// with its current note. Notes live in the alert history, so there is no
+// prompt without persistence.
+func (m *Model) openNotePrompt(a AlertHistoryRow) {
+	if m.history == nil {
+		return
+	}
+	m.notePrompt = true
+	m.noteTarget = a
+	m.noteInput = a.Note
+	if m.noteInput == "" {
+		// Rows of the Alerts panel come from the engine and carry no note.
+		m.noteInput, _ = m.alertNotes(a.Rule, a.SessionID, a.FiredAt)
+	}
+}
+
+// handleNotePromptKey edits and saves the note prompt. An empty input
+// removes the note.
+func (m Model) handleNotePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.notePrompt = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		t := m.noteTarget
+		m.history.SetAlertNote(t.Rule, t.SessionID, t.FiredAt, strings.TrimSpace(m.noteInput))
+		m.notePrompt = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Backspace):
+		if r := []rune(m.noteInput); len(r) > 0 {
+			m.noteInput = string(r[:len(r)-1])
+		}
+		return m, nil
+	}
+
+	if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) &&
+		len(m.noteInput)+len(msg.Runes) <= maxNoteInput {
+		m.noteInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m Model) overlayNotePrompt(base string) string {
+	t := m.noteTarget
+	sess := t.SessionID
+	if sess == "" {
+		sess = "(global)"
+	}
+	content := panelTitleStyle.Render("Alert Note") + "\n\n" +
+		"Alert: " + t.Rule + " — " + truncateID(sess, 12) + ", " + m.formatDateTime(t.FiredAt) + "\n" +
+		"\n> " + m.noteInput + "_\n" +
+		"\n" + dimStyle.Render("Text or incident doc URL  Enter: Save (empty removes)  Esc: Cancel")
+
+	dialog := filterMenuStyle.Render(content)
+	x := max((m.width-lipgloss.Width(dialog))/2, 0)
+	y := max((m.height-lipgloss.Height(dialog))/2, 0)
+	return placeOverlay(x, y, dialog, base)
+}
+
+// alertNotes returns the note on the persisted alert of rule and session
+// that fired at firedAt, and the notes on other alerts of the same rule,
+// newest first.
+func (m Model) alertNotes(rule, sessionID string, firedAt time.Time) (note string, earlier []AlertHistoryRow) {
+	if m.history == nil {
+		return "", nil
+	}
+	// Persisted firing times have second precision.
+	firedAt = firedAt.Truncate(time.Second)
+	for _, r := range m.history.QueryAlertHistory(90, rule) {
+		switch {
+		case r.SessionID == sessionID && r.FiredAt.Equal(firedAt):
+			note = r.Note
+		case r.Note != "":
+			earlier = append(earlier, r)
+		}
+	}
+	return note, earlier
+}
+
+// alertNoteLines renders the notes of an alert for its detail overlay, so
+// a recurring alert shows what was learned the last times it fired.
+func (m Model) alertNoteLines(rule, sessionID string, firedAt time.Time) []string {
+	note, earlier := m.alertNotes(rule, sessionID, firedAt)
+	var lines []string
+	if note != "" {
+		lines = append(lines, "", "Note:")
+		lines = append(lines, detailBlock(note, "")...)
+	}
+	if len(earlier) > 0 {
+		lines = append(lines, "", "Notes on earlier "+rule+" alerts:")
+		for _, r := range earlier[:min(len(earlier), maxEarlierNotes)] {
+			lines = append(lines, "  "+m.formatDateTime(r.FiredAt)+"  "+r.Note)
+		}
+	}
+	return lines
+}
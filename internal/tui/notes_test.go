@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestAlertNote_HistoryAlerts(t *testing.T) {
+	firedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	mock := &mockHistoryProvider{alertHistory: []AlertHistoryRow{
+		{Rule: "CostSurge", Severity: "critical", Message: "Cost surge", SessionID: "sess-2", FiredAt: firedAt},
+		{Rule: "CostSurge", Severity: "critical", Message: "Cost surge", SessionID: "sess-1", FiredAt: firedAt.Add(-24 * time.Hour), Note: "runaway test loop, see runbook"},
+	}}
+	m := newHistoryModel(WithHistoryProvider(mock))
+
+	m = typeKeys(t, m, runes("4"), runes("c"))
+	if !m.notePrompt {
+		t.Fatal("c should open the note prompt on the Alerts sub-tab")
+	}
+	if !strings.Contains(stripAnsi(m.View()), "Alert Note") {
+		t.Error("the note prompt should be rendered over the History view")
+	}
+	m = typeKeys(t, m, runes("https://example.com/inc-7"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.notePrompt || mock.alertHistory[0].Note != "https://example.com/inc-7" {
+		t.Fatalf("note not saved: prompt %v, note %q", m.notePrompt, mock.alertHistory[0].Note)
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	view := stripAnsi(m.View())
+	for _, want := range []string{"Alert Detail", "Note:", "https://example.com/inc-7", "Notes on earlier CostSurge alerts:", "runaway test loop, see runbook"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("alert detail should contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestAlertNote_AlertsPanel(t *testing.T) {
+	firedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	history := &mockHistoryProvider{alertHistory: []AlertHistoryRow{
+		{Rule: "ErrorStorm", Severity: "warning", SessionID: "sess-1", FiredAt: firedAt, Note: "flaky API"},
+	}}
+	active := &mockAlertProvider{alerts: []alerts.Alert{
+		// The engine's firing time is more precise than the persisted one.
+		{Rule: "ErrorStorm", Severity: "warning", Message: "errors", SessionID: "sess-1", FiredAt: firedAt.Add(300 * time.Millisecond)},
+	}}
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard),
+		WithStateProvider(&mockStateProvider{}), WithAlertProvider(active), WithHistoryProvider(history))
+	m.width, m.height = 120, 40
+	m.panelFocus = FocusAlerts
+
+	m = typeKeys(t, m, runes("c"))
+	if !m.notePrompt || m.noteInput != "flaky API" {
+		t.Fatalf("prompt should open with the current note, got %v %q", m.notePrompt, m.noteInput)
+	}
+	for range len("flaky API") {
+		m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if history.alertHistory[0].Note != "" {
+		t.Errorf("an empty note should remove it, got %q", history.alertHistory[0].Note)
+	}
+
+	// Without persistence there is nowhere to keep the note.
+	m = NewModel(config.DefaultConfig(), WithStartView(ViewDashboard),
+		WithStateProvider(&mockStateProvider{}), WithAlertProvider(active))
+	m.panelFocus = FocusAlerts
+	if m = typeKeys(t, m, runes("c")); m.notePrompt {
+		t.Error("the note prompt needs a history provider")
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/plan"
+)
+
+// renderPlanGauge renders one bar per plan quota, e.g.
+// "  Max 5h ■■■□□□□□ 42% ↻14:00 Wk ■□□□□□□□ 12%", coloured like the
+// budget gauge. The window shows when it resets.
+func (m Model) renderPlanGauge(now time.Time) string {
+	if m.plan == nil {
+		return ""
+	}
+	usage := m.plan.Usage(now)
+	if len(usage) == 0 {
+		return ""
+	}
+	name := m.plan.Name()
+	if name == "" {
+		name = "Plan"
+	}
+	var sb strings.Builder
+	sb.WriteString(dimStyle.Render("  " + name))
+	for _, u := range usage {
+		pct := u.Percent()
+		filled := min(budgetGaugeCells, int(pct/100*budgetGaugeCells+0.5))
+		style := costGreenStyle
+		switch {
+		case pct >= 100:
+			style = costRedStyle
+		case pct >= 80:
+			style = costYellowStyle
+		}
+		sb.WriteString(dimStyle.Render(" " + planLabel(m.cfg.Plan.WindowHours, u) + " "))
+		sb.WriteString(style.Render(strings.Repeat("■", filled)))
+		sb.WriteString(dimStyle.Render(strings.Repeat("□", budgetGaugeCells-filled)))
+		sb.WriteString(style.Render(fmt.Sprintf(" %.0f%%", pct)))
+		if u.Period == plan.Window && u.Active() {
+			sb.WriteString(dimStyle.Render(" ↻" + m.formatClockMinutes(u.End)))
+		}
+	}
+	return sb.String()
+}
+
+// renderPlanSection renders plan utilization for the Stats view.
+func (m Model) renderPlanSection(now time.Time) string {
+	title := "Plan Utilization"
+	if name := m.plan.Name(); name != "" {
+		title += " (" + name + ")"
+	}
+	lines := []string{panelTitleStyle.Render(title)}
+	for _, u := range m.plan.Usage(now) {
+		label := "Week:"
+		detail := "resets " + m.formatDateTime(u.End)
+		if u.Period == plan.Window {
+			label = fmt.Sprintf("%dh window:", m.cfg.Plan.WindowHours)
+			detail = "starts with the next request"
+			if u.Active() {
+				detail = fmt.Sprintf("%s–%s, %s left", m.formatClockMinutes(u.Start), m.formatClockMinutes(u.End),
+					formatDuration(u.End.Sub(now)))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %-13s %5.1f%%  %s / %s tokens  %s", label, u.Percent(),
+			formatNumber(u.Used), formatNumber(u.Limit), dimStyle.Render(detail)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// planLabel returns the header label of a quota, e.g. "5h" or "Wk".
+func planLabel(windowHours int, u plan.Usage) string {
+	if u.Period == plan.Window {
+		return fmt.Sprintf("%dh", windowHours)
+	}
+	return "Wk"
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/plan"
+)
+
+type mockPlanProvider struct {
+	name  string
+	usage []plan.Usage
+}
+
+func (m *mockPlanProvider) Name() string                 { return m.name }
+func (m *mockPlanProvider) Usage(time.Time) []plan.Usage { return m.usage }
+
+func TestRenderPlanGauge(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	if got := m.renderPlanGauge(time.Now()); got != "" {
+		t.Errorf("no plan provider should render nothing, got %q", got)
+	}
+
+	now := time.Date(2026, 3, 12, 10, 30, 0, 0, time.Local)
+	start := time.Date(2026, 3, 12, 9, 0, 0, 0, time.Local)
+	m = NewModel(config.DefaultConfig(), WithPlanProvider(&mockPlanProvider{name: "Max", usage: []plan.Usage{
+		{Period: plan.Window, Limit: 1000, Used: 500, Start: start, End: start.Add(5 * time.Hour)},
+		{Period: plan.Weekly, Limit: 10_000, Used: 12_000},
+	}}))
+	got := stripAnsi(m.renderPlanGauge(now))
+	want := "  Max 5h ■■■■□□□□ 50% ↻14:00 Wk ■■■■■■■■ 120%"
+	if got != want {
+		t.Errorf("gauge = %q, want %q", got, want)
+	}
+
+	section := stripAnsi(m.renderPlanSection(now))
+	for _, want := range []string{"Plan Utilization (Max)", "5h window:", "500 / 1,000 tokens", "09:00–14:00, 3h30m left"} {
+		if !strings.Contains(section, want) {
+			t.Errorf("section %q should contain %q", section, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nixlim/cc-top/internal/stats"
+)
+
+// renderProjects shows the live sessions grouped by repository root (or
+// working directory), most expensive project first, each split by branch
+// when branches are known.
+func (m Model) renderProjects() string {
+	var sb strings.Builder
+
+	viewLabel := " [Projects]"
+	indicators := m.headerIndicators()
+	help := "Tab:History  q:Quit "
+	padding := m.width - lipgloss.Width(" cc-top") - lipgloss.Width(viewLabel) - lipgloss.Width(indicators) - lipgloss.Width(help)
+	if padding < 0 {
+		padding = 0
+	}
+	sb.WriteString(headerStyle.Width(m.width).Render(
+		" cc-top" + viewLabel + indicators + strings.Repeat(" ", padding) + help))
+	sb.WriteByte('\n')
+
+	// Projects always span all sessions, whichever one is selected.
+	var projects []stats.ProjectStats
+	if m.stats != nil {
+		projects = m.stats.GetGlobal().ProjectBreakdown
+	}
+
+	projW := m.width - 70
+	if projW < 20 {
+		projW = 20
+	}
+
+	allLines := []string{panelTitleStyle.Render("Projects")}
+	if len(projects) == 0 {
+		allLines = append(allLines, dimStyle.Render("  No sessions"))
+	} else {
+		allLines = append(allLines, fmt.Sprintf("  %-*s %5s %10s %10s %8s %8s %7s %6s",
+			projW, "Project", "Sess", "Cost", "Tokens", "+Lines", "-Lines", "Commits", "Err%"))
+		allLines = append(allLines, dimStyle.Render("  "+strings.Repeat("─", projW+64)))
+		for _, p := range projects {
+			errPct := fmt.Sprintf("%5.1f%%", p.ErrorRate*100)
+			if p.APIErrors > 0 {
+				errPct = alertWarningStyle.Render(errPct)
+			}
+			allLines = append(allLines, fmt.Sprintf("  %-*s %5d $%9.2f %10s %8s %8s %7d %s",
+				projW, truncateCWD(p.Project, projW), p.SessionCount, p.TotalCost,
+				formatNumber(p.TotalTokens), formatNumber(int64(p.LinesAdded)),
+				formatNumber(int64(p.LinesRemoved)), p.Commits, errPct))
+			for _, b := range p.Branches {
+				name := cmp.Or(b.Branch, "(unknown)")
+				allLines = append(allLines, dimStyle.Render(fmt.Sprintf("    ⎇ %-*s %5d $%9.2f %10s",
+					projW-4, truncateStr(name, projW-4), b.SessionCount, b.TotalCost, formatNumber(b.TotalTokens))))
+			}
+		}
+	}
+
+	visibleH := m.height - 3
+	if visibleH < 1 {
+		visibleH = 1
+	}
+	startIdx := m.projectsScrollPos
+	if startIdx > len(allLines)-visibleH {
+		startIdx = len(allLines) - visibleH
+	}
+	if startIdx < 0 {
+		startIdx = 0
+	}
+	endIdx := startIdx + visibleH
+	if endIdx > len(allLines) {
+		endIdx = len(allLines)
+	}
+	for i := startIdx; i < endIdx; i++ {
+		sb.WriteString(allLines[i])
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
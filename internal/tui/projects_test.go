@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/stats"
+)
+
+func TestRenderProjects(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg, WithStartView(ViewProjects))
+	m.width = 140
+	m.height = 40
+	if view := m.View(); !strings.Contains(view, "No sessions") {
+		t.Errorf("projects view without data should say so, got:\n%s", view)
+	}
+
+	mockStats := &mockStatsProvider{
+		global: stats.DashboardStats{
+			ProjectBreakdown: []stats.ProjectStats{
+				{Project: "/src/api", SessionCount: 2, TotalCost: 4.25, TotalTokens: 120000,
+					LinesAdded: 340, LinesRemoved: 12, Commits: 3, APIRequests: 40, APIErrors: 2, ErrorRate: 0.05,
+					Branches: []stats.BranchStats{
+						{Branch: "main", SessionCount: 1, TotalCost: 3.00, TotalTokens: 100000},
+						{Branch: "", SessionCount: 1, TotalCost: 1.25, TotalTokens: 20000},
+					}},
+				{Project: "/src/web", SessionCount: 1, TotalCost: 0.75, TotalTokens: 8000},
+			},
+		},
+	}
+	m = NewModel(cfg, WithStartView(ViewProjects), WithStatsProvider(mockStats))
+	m.width = 140
+	m.height = 40
+	view := stripAnsi(m.View())
+
+	for _, want := range []string{"[Projects]", "/src/api", "$     4.25", "120,000", "340", "5.0%", "/src/web",
+		"⎇ main", "$     3.00", "⎇ (unknown)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("projects view should contain %q, got:\n%s", want, view)
+		}
+	}
+	if strings.Index(view, "/src/api") > strings.Index(view, "/src/web") {
+		t.Error("projects should keep the provider's order (most expensive first)")
+	}
+}
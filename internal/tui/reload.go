@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// configNoticeFor is how long the header shows the result of a config
+// reload.
+const configNoticeFor = 5 * time.Second
+
+// ConfigReloadedMsg delivers a config reload to the TUI. Display settings
+// and the theme apply from the next frame; when the reload failed the
+// header says so and the current config stays in effect.
+type ConfigReloadedMsg config.Reload
+
+func (m Model) applyConfigReload(r config.Reload, now time.Time) Model {
+	m.configNoticeAt = now
+	if r.Err != nil {
+		m.configNotice = "[!] Config not reloaded: " + r.Err.Error()
+		return m
+	}
+
+	// Styles are package-level, like in NewModel.
+	applyTheme(r.Config.Display.Theme)
+	m.cfg = r.Config
+	m.kpisAt = time.Time{} // recompute for changed [display.badges]
+	m.refreshRate = time.Duration(r.Config.Display.RefreshRateMS) * time.Millisecond
+
+	m.configNotice = "Config reloaded"
+	if len(r.Restart) > 0 {
+		m.configNotice += "; restart to apply " + strings.Join(r.Restart, ", ")
+	}
+	return m
+}
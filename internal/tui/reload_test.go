@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestConfigReload(t *testing.T) {
+	defer applyTheme(config.DefaultConfig().Display.Theme)
+
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+
+	cfg := config.DefaultConfig()
+	cfg.Display.RefreshRateMS = 250
+	cfg.Display.CostColorGreenBelow = 0.5
+	updated, _ := m.Update(ConfigReloadedMsg{Config: cfg, Restart: []string{"receiver"}})
+	m = updated.(Model)
+
+	if m.refreshRate != 250*time.Millisecond || m.cfg.Display.CostColorGreenBelow != 0.5 {
+		t.Errorf("reload not applied: refresh %v, green below %v", m.refreshRate, m.cfg.Display.CostColorGreenBelow)
+	}
+	if got := stripAnsi(m.headerIndicators()); !strings.Contains(got, "Config reloaded; restart to apply receiver") {
+		t.Errorf("header should report the reload, got %q", got)
+	}
+
+	updated, _ = m.Update(ConfigReloadedMsg{Config: cfg, Err: errors.New("config validation error: bad port")})
+	m = updated.(Model)
+	if got := stripAnsi(m.headerIndicators()); !strings.Contains(got, "[!] Config not reloaded: config validation error: bad port") {
+		t.Errorf("header should report the failed reload, got %q", got)
+	}
+	if m.refreshRate != 250*time.Millisecond {
+		t.Errorf("failed reload should keep the config in effect, refresh %v", m.refreshRate)
+	}
+}
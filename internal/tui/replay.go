@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/events"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+const (
+	// maxReplayGap caps the idle time between two replayed events, so long
+	// pauses in a session do not stall playback.
+	maxReplayGap   = 10 * time.Second
+	minReplaySpeed = 0.25
+	maxReplaySpeed = 64
+	// replayCostCells is the width of the replay cost bar.
+	replayCostCells = 20
+)
+
+// replayState plays back the events of one session on the replay clock,
+// which runs at speed times real time with idle gaps capped at maxReplayGap.
+type replayState struct {
+	sessionID string
+	running   bool // the session had not finished when the replay opened
+	events    []events.FormattedEvent
+	offsets   []time.Duration // replay clock time of each event
+	costs     []float64       // cumulative cost after each event
+	shown     int             // number of events revealed
+	clock     time.Duration
+	speed     float64
+	paused    bool
+}
+
+func newReplayState(s state.SessionData, evts []state.Event) *replayState {
+	r := &replayState{
+		sessionID: s.SessionID,
+		running:   !s.Exited && s.Status() != state.StatusDone,
+		speed:     1,
+		events:    make([]events.FormattedEvent, len(evts)),
+		offsets:   make([]time.Duration, len(evts)),
+		costs:     make([]float64, len(evts)),
+	}
+	var clock time.Duration
+	var cost float64
+	for i, e := range evts {
+		if i > 0 {
+			gap := e.Timestamp.Sub(evts[i-1].Timestamp)
+			if gap > maxReplayGap {
+				gap = maxReplayGap
+			}
+			clock += max(0, gap)
+		}
+		if e.Name == "claude_code.api_request" {
+			if v, err := strconv.ParseFloat(e.Attributes["cost_usd"], 64); err == nil {
+				cost += v
+			}
+		}
+		r.events[i] = events.FormatEvent(s.SessionID, e)
+		r.offsets[i] = clock
+		r.costs[i] = cost
+	}
+	return r
+}
+
+// advance moves the replay clock on by d of real time and reveals the
+// events it has reached.
+func (r *replayState) advance(d time.Duration) {
+	if r.paused || r.finished() {
+		return
+	}
+	r.clock += time.Duration(float64(d) * r.speed)
+	for r.shown < len(r.events) && r.offsets[r.shown] <= r.clock {
+		r.shown++
+	}
+}
+
+// step reveals the next event, or hides the last one when delta is -1.
+func (r *replayState) step(delta int) {
+	r.shown = max(0, min(r.shown+delta, len(r.events)))
+	r.clock = 0
+	if r.shown > 0 {
+		r.clock = r.offsets[r.shown-1]
+	}
+}
+
+func (r *replayState) finished() bool {
+	return r.shown >= len(r.events)
+}
+
+func (r *replayState) duration() time.Duration {
+	if len(r.offsets) == 0 {
+		return 0
+	}
+	return r.offsets[len(r.offsets)-1]
+}
+
+func (r *replayState) cost() float64 {
+	if r.shown == 0 {
+		return 0
+	}
+	return r.costs[r.shown-1]
+}
+
+func (r *replayState) totalCost() float64 {
+	if len(r.costs) == 0 {
+		return 0
+	}
+	return r.costs[len(r.costs)-1]
+}
+
+// openReplay starts replaying the session under the cursor from its
+// persisted events, or from the events held in memory without persistence.
+func (m Model) openReplay() (tea.Model, tea.Cmd) {
+	sessions := m.getSessions()
+	if m.sessionCursor < 0 || m.sessionCursor >= len(sessions) {
+		return m, nil
+	}
+	s := sessions[m.sessionCursor]
+	var evts []state.Event
+	if m.replaySource != nil {
+		evts = m.replaySource.SessionEvents(s.SessionID)
+	} else {
+		evts = s.Events
+	}
+	m.replay = newReplayState(s, evts)
+	return m, nil
+}
+
+func (m Model) handleReplayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.replay
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.replay = nil
+
+	case key.Matches(msg, m.keys.ReplayPause):
+		if r.finished() {
+			r.step(-len(r.events))
+			r.paused = false
+		} else {
+			r.paused = !r.paused
+		}
+
+	case key.Matches(msg, m.keys.ReplayFaster):
+		if r.speed < maxReplaySpeed {
+			r.speed *= 2
+		}
+
+	case key.Matches(msg, m.keys.ReplaySlower):
+		if r.speed > minReplaySpeed {
+			r.speed /= 2
+		}
+
+	case key.Matches(msg, m.keys.ReplayNext):
+		r.paused = true
+		r.step(1)
+
+	case key.Matches(msg, m.keys.ReplayPrev):
+		r.paused = true
+		r.step(-1)
+	}
+	return m, nil
+}
+
+// renderReplay renders the replay screen: a progress header with the cost
+// accumulated so far and the events revealed, newest at the bottom.
+func (m Model) renderReplay() string {
+	r := m.replay
+	var sb strings.Builder
+
+	title := " cc-top -- Replay " + truncateID(r.sessionID, 12)
+	if r.running {
+		title += " (still running)"
+	}
+	sb.WriteString(headerStyle.Width(m.width).Render(title))
+	sb.WriteString("\n\n")
+
+	marker := "▶"
+	switch {
+	case r.finished():
+		marker = "■"
+	case r.paused:
+		marker = "❚❚"
+	}
+	sb.WriteString(fmt.Sprintf("  %s %gx  %s / %s  event %d/%d",
+		marker, r.speed, formatDuration(r.clock), formatDuration(r.duration()), r.shown, len(r.events)))
+	if r.shown > 0 {
+		sb.WriteString("  at " + m.formatDateTime(r.events[r.shown-1].Timestamp))
+	}
+	sb.WriteByte('\n')
+
+	// Cost accumulated so far against the session total.
+	total := r.totalCost()
+	sb.WriteString(fmt.Sprintf("  Cost $%.2f / $%.2f", r.cost(), total))
+	if total > 0 {
+		filled := int(r.cost()/total*replayCostCells + 0.5)
+		sb.WriteString("  " + costGreenStyle.Render(strings.Repeat("■", filled)))
+		sb.WriteString(dimStyle.Render(strings.Repeat("□", replayCostCells-filled)))
+	}
+	sb.WriteString("\n\n")
+
+	if len(r.events) == 0 {
+		sb.WriteString(dimStyle.Render("  No events recorded for this session."))
+		sb.WriteByte('\n')
+	}
+
+	// Header (4 lines), gap and footer leave the rest for events.
+	rows := max(1, m.height-8)
+	start := max(0, r.shown-rows)
+	for i := start; i < r.shown; i++ {
+		offset := dimStyle.Render(fmt.Sprintf("%8s ", "+"+formatDuration(r.events[i].Timestamp.Sub(r.events[0].Timestamp))))
+		sb.WriteString("  " + offset + renderEventLine(r.events[i], m.width-13))
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteByte('\n')
+	sb.WriteString(dimStyle.Render("  [Space] Pause/resume  [+/-] Speed  [←/→] Step  [Esc] Close"))
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+type mockReplaySource struct {
+	events map[string][]state.Event
+}
+
+func (m *mockReplaySource) SessionEvents(sessionID string) []state.Event {
+	return m.events[sessionID]
+}
+
+func replayEvents(start time.Time) []state.Event {
+	return []state.Event{
+		{Name: "claude_code.user_prompt", Timestamp: start, Attributes: map[string]string{"prompt_length": "42"}},
+		{Name: "claude_code.api_request", Timestamp: start.Add(2 * time.Second), Attributes: map[string]string{"model": "opus", "cost_usd": "0.25"}},
+		// A long idle gap is shortened to maxReplayGap.
+		{Name: "claude_code.tool_result", Timestamp: start.Add(time.Hour), Attributes: map[string]string{"tool_name": "Bash", "success": "true"}},
+		{Name: "claude_code.api_request", Timestamp: start.Add(time.Hour + time.Second), Attributes: map[string]string{"model": "opus", "cost_usd": "0.75"}},
+	}
+}
+
+func TestReplayState_Playback(t *testing.T) {
+	r := newReplayState(state.SessionData{SessionID: "sess", Exited: true}, replayEvents(time.Now()))
+
+	if got, want := r.duration(), 2*time.Second+maxReplayGap+time.Second; got != want {
+		t.Errorf("duration = %v, want %v", got, want)
+	}
+	if r.running {
+		t.Error("an exited session should not be marked running")
+	}
+
+	r.advance(time.Second)
+	if r.shown != 1 {
+		t.Fatalf("after 1s: shown = %d, want 1", r.shown)
+	}
+	r.speed = 4
+	r.advance(time.Second)
+	if r.shown != 2 || r.cost() != 0.25 {
+		t.Fatalf("after 5s of replay: shown = %d cost = %v, want 2 and 0.25", r.shown, r.cost())
+	}
+
+	r.paused = true
+	r.advance(time.Minute)
+	if r.shown != 2 {
+		t.Error("a paused replay should not advance")
+	}
+
+	r.step(1)
+	r.step(1)
+	if !r.finished() || r.cost() != 1.0 || r.totalCost() != 1.0 {
+		t.Errorf("stepping to the end: shown = %d cost = %v", r.shown, r.cost())
+	}
+	r.step(-1)
+	if r.shown != 3 || r.clock != r.offsets[2] {
+		t.Errorf("stepping back: shown = %d clock = %v", r.shown, r.clock)
+	}
+}
+
+func TestReplay_OpenFromSessionsPanel(t *testing.T) {
+	start := time.Now().Add(-2 * time.Hour)
+	mockState := &mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "sess-replay", StartedAt: start, Exited: true},
+	}}
+	source := &mockReplaySource{events: map[string][]state.Event{"sess-replay": replayEvents(start)}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState),
+		WithReplaySource(source), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+
+	m = typeKeys(t, m, runes("r"))
+	if m.replay == nil || len(m.replay.events) != 4 {
+		t.Fatalf("r should open a replay of the persisted events")
+	}
+
+	m = typeKeys(t, m, runes("l"), runes("l"))
+	view := stripAnsi(m.View())
+	for _, want := range []string{"Replay sess-replay", "event 2/4", "Cost $0.25 / $1.00", "Prompt"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("replay view missing %q:\n%s", want, view)
+		}
+	}
+	if !m.replay.paused {
+		t.Error("stepping should pause playback")
+	}
+
+	m = typeKeys(t, m, runes("+"), runes("+"))
+	if m.replay.speed != 4 {
+		t.Errorf("speed = %v, want 4", m.replay.speed)
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.replay != nil {
+		t.Error("Esc should close the replay")
+	}
+}
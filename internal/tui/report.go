@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Report summarizes the daily stats of one period for cc-top report. Totals
+// and averages are computed like the History view's weekly and monthly rows.
+type Report struct {
+	Period      string        `json:"period"` // day, week or month
+	From        string        `json:"from"`   // first date, YYYY-MM-DD
+	To          string        `json:"to"`     // last date
+	GeneratedAt time.Time     `json:"generated_at"`
+	Totals      ReportTotals  `json:"totals"`
+	Days        []ReportDay   `json:"days"`
+	Models      []ReportModel `json:"models"`
+	Tools       []ReportTool  `json:"tools"`
+	Errors      []ReportError `json:"errors"`
+}
+
+// ReportTotals holds the totals of a report period. Rates and latencies
+// are averaged over the days that have them.
+type ReportTotals struct {
+	CostUSD          float64 `json:"cost_usd"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	CacheReadTokens  int64   `json:"cache_read_tokens"`
+	CacheWriteTokens int64   `json:"cache_write_tokens"`
+	Sessions         int     `json:"sessions"`
+	APIRequests      int     `json:"api_requests"`
+	APIErrors        int     `json:"api_errors"`
+	ErrorRate        float64 `json:"error_rate"`       // 0-1
+	CacheEfficiency  float64 `json:"cache_efficiency"` // 0-1
+	CacheSavingsUSD  float64 `json:"cache_savings_usd"`
+	LatencyP50       float64 `json:"latency_p50_seconds"`
+	LatencyP95       float64 `json:"latency_p95_seconds"`
+	LinesAdded       int     `json:"lines_added"`
+	LinesRemoved     int     `json:"lines_removed"`
+	Commits          int     `json:"commits"`
+	PRsOpened        int     `json:"prs_opened"`
+	CostPerCommitUSD float64 `json:"cost_per_commit_usd"` // 0 without commits
+}
+
+// ReportDay is one day of a report.
+type ReportDay struct {
+	Date        string  `json:"date"`
+	CostUSD     float64 `json:"cost_usd"`
+	Tokens      int64   `json:"tokens"` // input + output
+	Sessions    int     `json:"sessions"`
+	APIRequests int     `json:"api_requests"`
+	APIErrors   int     `json:"api_errors"`
+	Commits     int     `json:"commits"`
+}
+
+// ReportModel is the usage of one model over a report period.
+type ReportModel struct {
+	Model   string  `json:"model"`
+	CostUSD float64 `json:"cost_usd"`
+	Tokens  int64   `json:"tokens"`
+}
+
+// ReportTool is the number of calls of one tool over a report period.
+type ReportTool struct {
+	Tool  string `json:"tool"`
+	Calls int    `json:"calls"`
+}
+
+// ReportError is the number of API errors of one category over a report
+// period.
+type ReportError struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// BuildReport summarizes rows, the daily stats from one date to another.
+func BuildReport(period, from, to string, rows []DailyStatsRow, now time.Time) Report {
+	rows = slices.Clone(rows)
+	slices.SortFunc(rows, func(a, b DailyStatsRow) int { return strings.Compare(a.Date, b.Date) })
+
+	label := func(string) string { return period }
+	overview := overviewTotals(aggregateOverviewByGroup(rows, label))
+	perf := perfAverages(aggregatePerfByGroup(rows, label))
+
+	r := Report{Period: period, From: from, To: to, GeneratedAt: now}
+	r.Totals = ReportTotals{
+		CostUSD:         overview.cost,
+		Sessions:        overview.sessions,
+		APIRequests:     overview.requests,
+		APIErrors:       overview.errors,
+		ErrorRate:       perf.errRate,
+		CacheEfficiency: perf.cacheEff,
+		CacheSavingsUSD: perf.cacheSave,
+		LatencyP50:      perf.p50,
+		LatencyP95:      perf.p95,
+		LinesAdded:      overview.linesAdd,
+		LinesRemoved:    overview.linesDel,
+		Commits:         overview.commits,
+	}
+	if overview.commits > 0 {
+		r.Totals.CostPerCommitUSD = overview.cost / float64(overview.commits)
+	}
+
+	models := make(map[string]*ReportModel)
+	tools := make(map[string]int)
+	errs := make(map[string]int)
+	for _, d := range rows {
+		r.Totals.InputTokens += d.TokenInput
+		r.Totals.OutputTokens += d.TokenOutput
+		r.Totals.CacheReadTokens += d.TokenCacheRead
+		r.Totals.CacheWriteTokens += d.TokenCacheWrite
+		r.Totals.PRsOpened += d.PRsOpened
+		r.Days = append(r.Days, ReportDay{
+			Date: d.Date, CostUSD: d.TotalCost, Tokens: d.TokenInput + d.TokenOutput,
+			Sessions: d.SessionCount, APIRequests: d.APIRequests, APIErrors: d.APIErrors, Commits: d.Commits,
+		})
+		for _, mb := range d.ModelBreakdown {
+			m, ok := models[mb.Model]
+			if !ok {
+				m = &ReportModel{Model: mb.Model}
+				models[mb.Model] = m
+			}
+			m.CostUSD += mb.TotalCost
+			m.Tokens += mb.TotalTokens
+		}
+		for _, t := range d.TopTools {
+			tools[t.ToolName] += t.Count
+		}
+		for cat, n := range d.ErrorCategories {
+			errs[cat] += n
+		}
+	}
+
+	for _, m := range models {
+		r.Models = append(r.Models, *m)
+	}
+	slices.SortFunc(r.Models, func(a, b ReportModel) int {
+		return cmp.Or(cmp.Compare(b.CostUSD, a.CostUSD), strings.Compare(a.Model, b.Model))
+	})
+	for name, n := range tools {
+		r.Tools = append(r.Tools, ReportTool{Tool: name, Calls: n})
+	}
+	slices.SortFunc(r.Tools, func(a, b ReportTool) int {
+		return cmp.Or(b.Calls-a.Calls, strings.Compare(a.Tool, b.Tool))
+	})
+	for cat, n := range errs {
+		r.Errors = append(r.Errors, ReportError{Category: cat, Count: n})
+	}
+	slices.SortFunc(r.Errors, func(a, b ReportError) int {
+		return cmp.Or(b.Count-a.Count, strings.Compare(a.Category, b.Category))
+	})
+	return r
+}
+
+// Title names the report's period, e.g. "Week 2026-11".
+func (r Report) Title() string {
+	switch r.Period {
+	case "week":
+		return weekLabelForDate(r.From)
+	case "month":
+		if t, err := time.Parse("2006-01-02", r.From); err == nil {
+			return t.Format("January 2006")
+		}
+	}
+	return r.From
+}
+
+// reportTable is one section of a report, shared by the Markdown and HTML
+// renderings.
+type reportTable struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+func (r Report) tables() []reportTable {
+	t := r.Totals
+	summary := reportTable{Title: "Summary", Header: []string{"Metric", "Value"}, Rows: [][]string{
+		{"Cost", fmt.Sprintf("$%.2f", t.CostUSD)},
+		{"Tokens in / out", formatNumber(t.InputTokens) + " / " + formatNumber(t.OutputTokens)},
+		{"Cache read / write", formatNumber(t.CacheReadTokens) + " / " + formatNumber(t.CacheWriteTokens)},
+		{"Sessions", fmt.Sprint(t.Sessions)},
+		{"API requests", fmt.Sprint(t.APIRequests)},
+		{"API errors", fmt.Sprintf("%d (%.1f%%)", t.APIErrors, t.ErrorRate*100)},
+		{"Cache efficiency", fmt.Sprintf("%.0f%% (saved $%.2f)", t.CacheEfficiency*100, t.CacheSavingsUSD)},
+		{"Latency p50 / p95", fmt.Sprintf("%.1fs / %.1fs", t.LatencyP50, t.LatencyP95)},
+	}}
+	costPerCommit := "-"
+	if t.Commits > 0 {
+		costPerCommit = fmt.Sprintf("$%.2f", t.CostPerCommitUSD)
+	}
+	productivity := reportTable{Title: "Productivity", Header: []string{"Metric", "Value"}, Rows: [][]string{
+		{"Lines added / removed", fmt.Sprintf("+%d / -%d", t.LinesAdded, t.LinesRemoved)},
+		{"Commits", fmt.Sprint(t.Commits)},
+		{"PRs opened", fmt.Sprint(t.PRsOpened)},
+		{"Cost per commit", costPerCommit},
+	}}
+
+	days := reportTable{Title: "Days", Header: []string{"Date", "Cost", "Tokens", "Sessions", "API requests", "Errors", "Commits"}}
+	for _, d := range r.Days {
+		days.Rows = append(days.Rows, []string{d.Date, fmt.Sprintf("$%.2f", d.CostUSD), formatNumber(d.Tokens),
+			fmt.Sprint(d.Sessions), fmt.Sprint(d.APIRequests), fmt.Sprint(d.APIErrors), fmt.Sprint(d.Commits)})
+	}
+	models := reportTable{Title: "Models", Header: []string{"Model", "Cost", "Tokens"}}
+	for _, m := range r.Models {
+		models.Rows = append(models.Rows, []string{m.Model, fmt.Sprintf("$%.2f", m.CostUSD), formatNumber(m.Tokens)})
+	}
+	tools := reportTable{Title: "Tools", Header: []string{"Tool", "Calls"}}
+	for _, tl := range r.Tools {
+		tools.Rows = append(tools.Rows, []string{tl.Tool, fmt.Sprint(tl.Calls)})
+	}
+	errs := reportTable{Title: "Errors", Header: []string{"Category", "Count"}}
+	for _, e := range r.Errors {
+		errs.Rows = append(errs.Rows, []string{e.Category, fmt.Sprint(e.Count)})
+	}
+	return []reportTable{summary, productivity, days, models, tools, errs}
+}
+
+func (r Report) subtitle() string {
+	return fmt.Sprintf("%s to %s, generated %s", r.From, r.To, r.GeneratedAt.Local().Format("2006-01-02 15:04"))
+}
+
+// Markdown renders the report as Markdown.
+func (r Report) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# cc-top report: %s\n\n%s\n", r.Title(), r.subtitle())
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	for _, t := range r.tables() {
+		fmt.Fprintf(&sb, "\n## %s\n\n", t.Title)
+		if len(t.Rows) == 0 {
+			sb.WriteString("None.\n")
+			continue
+		}
+		sb.WriteString("| " + strings.Join(t.Header, " | ") + " |\n")
+		sb.WriteString("|" + strings.Repeat("---|", len(t.Header)) + "\n")
+		for _, row := range t.Rows {
+			for i := range row {
+				row[i] = cell.Replace(row[i])
+			}
+			sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		}
+	}
+	return sb.String()
+}
+
+var reportHTML = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>cc-top report: {{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.7em; text-align: left; }
+th { background: #f3f3f3; }
+</style>
+</head>
+<body>
+<h1>cc-top report: {{.Title}}</h1>
+<p>{{.Subtitle}}</p>
+{{range .Tables}}<h2>{{.Title}}</h2>
+{{if .Rows}}<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{else}}<p>None.</p>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// HTML renders the report as a standalone HTML page.
+func (r Report) HTML() (string, error) {
+	var sb strings.Builder
+	err := reportHTML.Execute(&sb, struct {
+		Title, Subtitle string
+		Tables          []reportTable
+	}{r.Title(), r.subtitle(), r.tables()})
+	return sb.String(), err
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/stats"
+)
+
+func TestBuildReport(t *testing.T) {
+	rows := []DailyStatsRow{
+		{
+			Date: "2026-03-10", TotalCost: 6, TokenInput: 1000, TokenOutput: 500, SessionCount: 2,
+			APIRequests: 20, APIErrors: 2, ErrorRate: 0.1, CacheEfficiency: 0.8, Commits: 2, PRsOpened: 1,
+			ModelBreakdown:  []stats.ModelStats{{Model: "opus", TotalCost: 5, TotalTokens: 1000}, {Model: "haiku", TotalCost: 1, TotalTokens: 500}},
+			TopTools:        []stats.ToolUsage{{ToolName: "Bash", Count: 4}},
+			ErrorCategories: map[string]int{"rate_limit": 2},
+		},
+		{
+			Date: "2026-03-09", TotalCost: 4, TokenInput: 200, SessionCount: 1,
+			APIRequests: 10, ErrorRate: 0.3, CacheEfficiency: 0.6, Commits: 1,
+			ModelBreakdown: []stats.ModelStats{{Model: "opus", TotalCost: 4, TotalTokens: 200}},
+			TopTools:       []stats.ToolUsage{{ToolName: "Read", Count: 9}, {ToolName: "Bash", Count: 1}},
+		},
+	}
+	r := BuildReport("week", "2026-03-09", "2026-03-15", rows, time.Date(2026, 3, 16, 9, 0, 0, 0, time.Local))
+
+	tot := r.Totals
+	if tot.CostUSD != 10 || tot.InputTokens != 1200 || tot.APIRequests != 30 || tot.Commits != 3 || tot.PRsOpened != 1 {
+		t.Errorf("totals: %+v", tot)
+	}
+	if tot.CostPerCommitUSD < 3.33 || tot.CostPerCommitUSD > 3.34 {
+		t.Errorf("cost per commit: got %v", tot.CostPerCommitUSD)
+	}
+	if tot.ErrorRate < 0.199 || tot.ErrorRate > 0.201 || tot.CacheEfficiency < 0.699 || tot.CacheEfficiency > 0.701 {
+		t.Errorf("rates should be averaged over days: %+v", tot)
+	}
+	if len(r.Days) != 2 || r.Days[0].Date != "2026-03-09" {
+		t.Errorf("days should be oldest first: %+v", r.Days)
+	}
+	if len(r.Models) != 2 || r.Models[0] != (ReportModel{Model: "opus", CostUSD: 9, Tokens: 1200}) {
+		t.Errorf("models: %+v", r.Models)
+	}
+	if len(r.Tools) != 2 || r.Tools[0] != (ReportTool{Tool: "Read", Calls: 9}) || r.Tools[1].Calls != 5 {
+		t.Errorf("tools: %+v", r.Tools)
+	}
+	if r.Title() != "Week 2026-11" {
+		t.Errorf("title: got %q", r.Title())
+	}
+
+	md := r.Markdown()
+	for _, want := range []string{"# cc-top report: Week 2026-11", "| Cost | $10.00 |", "| opus | $9.00 | 1,200 |", "| rate_limit | 2 |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown should contain %q:\n%s", want, md)
+		}
+	}
+	html, err := r.HTML()
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	if !strings.Contains(html, "<h2>Models</h2>") || !strings.Contains(html, "<td>opus</td>") {
+		t.Errorf("html missing models table:\n%s", html)
+	}
+}
+
+func TestBuildReport_Empty(t *testing.T) {
+	r := BuildReport("month", "2026-03-01", "2026-03-31", nil, time.Now())
+	if r.Title() != "March 2026" {
+		t.Errorf("title: got %q", r.Title())
+	}
+	if md := r.Markdown(); !strings.Contains(md, "## Days\n\nNone.") {
+		t.Errorf("empty sections should say so:\n%s", md)
+	}
+}
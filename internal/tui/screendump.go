@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// screenDumpNoticeFor is how long the header shows where the last screen
+// dump was written.
+const screenDumpNoticeFor = 5 * time.Second
+
+// WithScreenDumpDir sets the directory screen dumps (F12) are written to.
+// The default is the system temporary directory.
+func WithScreenDumpDir(dir string) ModelOption {
+	return func(m *Model) { m.screenDumpDir = dir }
+}
+
+// dumpScreen writes the current frame as plain text, followed by a summary
+// of the model state and what the providers return, to a timestamped file.
+// It is meant to be attached to bug reports about rendering.
+func (m Model) dumpScreen(now time.Time) (string, error) {
+	dir := m.screenDumpDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, "cc-top-screen-"+now.Format("20060102-150405")+".txt")
+	// Session directories and event details may be private.
+	if err := os.WriteFile(path, []byte(m.screenDump(now)), 0o600); err != nil {
+		return "", fmt.Errorf("writing screen dump: %w", err)
+	}
+	return path, nil
+}
+
+func (m Model) screenDump(now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "cc-top screen dump, %s, terminal %dx%d\n", now.Format(time.RFC3339), m.width, m.height)
+
+	sb.WriteString("\n== Screen ==\n")
+	sb.WriteString(stripAnsi(m.View()))
+	sb.WriteByte('\n')
+
+	sb.WriteString("\n== Model ==\n")
+	fmt.Fprintf(&sb, "view: %s\n", viewName(m.view))
+	fmt.Fprintf(&sb, "focus: %d  session cursor: %d  scroll offset: %d  selected: %q  search: %q\n",
+		m.panelFocus, m.sessionCursor, m.sessionScrollOffset, m.selectedSession, m.sessionQuery)
+	fmt.Fprintf(&sb, "event cursor: %d  event scroll: %d  auto scroll: %v  alert cursor: %d  stats scroll: %d  projects scroll: %d\n",
+		m.eventCursor, m.eventScrollPos, m.autoScroll, m.alertCursor, m.statsScrollPos, m.projectsScrollPos)
+	fmt.Fprintf(&sb, "history: section %d, %s, cursor %d, scroll %d, alert filter %q\n",
+		m.historySection, m.historyGranularity, m.historyCursor, m.historyScrollPos, m.historyAlertFilter)
+	fmt.Fprintf(&sb, "overlays: help=%v detail=%v (%q) filter menu=%v kill confirm=%v sla prompt=%v replay=%v\n",
+		m.helpOverlay, m.detailOverlay, m.detailTitle, m.filterMenu.Active, m.killConfirm, m.slaPrompt, m.replay != nil)
+	fmt.Fprintf(&sb, "persistent: %v  scanner disabled: %v  refresh: %v  theme: %s\n",
+		m.isPersistent, m.scannerDisabled, m.refreshRate, m.cfg.Display.Theme.Name)
+
+	sb.WriteString("\n== Providers ==\n")
+	if snap := m.currentSnapshot(); snap != nil {
+		fmt.Fprintf(&sb, "snapshot: version %d, taken %s, %d session(s), total $%.4f\n",
+			snap.Version, snap.TakenAt.Format(time.RFC3339), len(snap.Sessions), snap.TotalCost)
+		for _, s := range snap.Sessions {
+			fmt.Fprintf(&sb, "  %s  pid=%d status=%s model=%s cost=$%.4f tokens=%d metrics=%d events=%d exited=%v cwd=%s\n",
+				s.SessionID, s.PID, s.Status(), s.Model, s.TotalCost, s.TotalTokens,
+				len(s.Metrics), len(s.Events), s.Exited, s.CWD)
+		}
+	} else {
+		sb.WriteString("snapshot: none\n")
+	}
+	br := m.getBurnRate()
+	fmt.Fprintf(&sb, "burn rate: $%.4f/hr trend=%d tokens/min=%.1f daily=$%.2f monthly=$%.2f\n",
+		br.HourlyRate, br.Trend, br.TokenVelocity, br.DailyProjection, br.MonthlyProjection)
+	if f := br.Forecast; f.Valid() {
+		fmt.Fprintf(&sb, "forecast: day %s month %s from %d hour(s)\n", formatRange(f.EndOfDay), formatRange(f.EndOfMonth), f.Hours)
+	}
+	if m.alerts != nil {
+		active := m.alerts.Active()
+		fmt.Fprintf(&sb, "alerts: %d active\n", len(active))
+		for _, a := range active {
+			fmt.Fprintf(&sb, "  %s %s session=%q %s\n", a.Severity, a.Rule, a.SessionID, a.Message)
+		}
+	}
+	if m.events != nil {
+		fmt.Fprintf(&sb, "events: %d recent\n", len(m.events.Recent(1000)))
+	}
+	if m.scanner != nil {
+		procs := m.scanner.Processes()
+		fmt.Fprintf(&sb, "processes: %d\n", len(procs))
+		for _, p := range procs {
+			fmt.Fprintf(&sb, "  pid=%d %s terminal=%q exited=%v cwd=%s\n", p.PID, p.BinaryName, p.Terminal, p.Exited, p.CWD)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestScreenDump_F12(t *testing.T) {
+	dir := t.TempDir()
+	mockState := &mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "sess-dump", Model: "opus", TotalCost: 1.25, StartedAt: time.Now()},
+	}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState),
+		WithStartView(ViewDashboard), WithScreenDumpDir(dir))
+	m.width, m.height = 93, 31
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyF12})
+
+	files, _ := filepath.Glob(filepath.Join(dir, "cc-top-screen-*.txt"))
+	if len(files) != 1 {
+		t.Fatalf("want one dump file, got %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := string(data)
+	for _, want := range []string{"terminal 93x31", "== Screen ==", "== Model ==", "view: dashboard", "sess-dump", "cost=$1.2500"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump missing %q:\n%s", want, dump)
+		}
+	}
+	if strings.Contains(dump, "\x1b[") {
+		t.Error("dump should not contain ANSI escape sequences")
+	}
+	if !strings.Contains(m.screenDumpNotice, files[0]) {
+		t.Errorf("notice = %q, want the dump path", m.screenDumpNotice)
+	}
+}
@@ -15,6 +15,8 @@ import (
 
 // renderSessionListPanel renders the session list panel with columns for
 // PID, Session ID, Terminal, CWD, Telemetry, Model, Status, Cost, Tokens, Active Time.
+// Wide panels add cache hit rate, API errors, last tool and org. Rows end
+// with the context gauge and SLA badge when they fit.
 func (m Model) renderSessionListPanel(w, h int) string {
 	sessions := m.getSessions()
 
@@ -37,11 +39,16 @@ func (m Model) renderSessionListPanel(w, h int) string {
 	} else {
 		title += dimStyle.Render(" [Global]")
 	}
+	title += dimStyle.Render(m.sessionSearchTitle() + m.sessionSortTitle())
 	lines = append(lines, title)
 
 	if len(sessions) == 0 {
 		lines = append(lines, "")
-		lines = append(lines, dimStyle.Render("No sessions found"))
+		if m.sessionQuery != "" {
+			lines = append(lines, dimStyle.Render("No sessions match"))
+		} else {
+			lines = append(lines, dimStyle.Render("No sessions found"))
+		}
 		content := strings.Join(lines, "\n")
 		return panelBorderStyle.
 			Width(w - 2).
@@ -50,7 +57,7 @@ func (m Model) renderSessionListPanel(w, h int) string {
 	}
 
 	// Build header row.
-	header := formatSessionHeader(contentW)
+	header := m.formatSessionHeader(contentW, !m.scannerDisabled)
 	lines = append(lines, dimStyle.Render(header))
 	lines = append(lines, dimStyle.Render(strings.Repeat("─", min(contentW, len(header)))))
 
@@ -62,15 +69,22 @@ func (m Model) renderSessionListPanel(w, h int) string {
 	telemetrySessions, telHidden := filterDoneSessions(telemetrySessions, maxDone)
 	noTelemetrySessions, noTelHidden := filterDoneSessions(noTelemetrySessions, maxDone)
 
+	now := time.Now()
 	rowIdx := 0
 	// Render telemetry-enabled sessions.
 	for _, s := range telemetrySessions {
-		line := formatSessionRow(&s, contentW)
+		line := m.formatSessionRow(&s, contentW, !m.scannerDisabled)
 		if rowIdx == m.sessionCursor {
 			line = selectedStyle.Render(line)
 		} else if s.IsNew {
 			line = newBadgeStyle.Render("NEW ") + line
 		}
+		if gauge := m.contextGauge(&s); gauge != "" && lipgloss.Width(line)+1+lipgloss.Width(gauge) <= contentW {
+			line += " " + gauge
+		}
+		if badge := m.slaBadge(&s, now); badge != "" && lipgloss.Width(line)+1+lipgloss.Width(badge) <= contentW {
+			line += " " + badge
+		}
 		lines = append(lines, line)
 		rowIdx++
 	}
@@ -83,7 +97,7 @@ func (m Model) renderSessionListPanel(w, h int) string {
 	if len(noTelemetrySessions) > 0 {
 		lines = append(lines, dimStyle.Render("── no telemetry ──"))
 		for _, s := range noTelemetrySessions {
-			line := formatSessionRow(&s, contentW)
+			line := m.formatSessionRow(&s, contentW, !m.scannerDisabled)
 			if rowIdx == m.sessionCursor {
 				line = selectedStyle.Render(line)
 			} else {
@@ -125,50 +139,228 @@ func (m Model) renderSessionListPanel(w, h int) string {
 	return renderBorderedPanel(content, w, h)
 }
 
-// formatSessionHeader returns the column header string.
-func formatSessionHeader(maxW int) string {
+// contextGaugeCells is the width of the context gauge's bar.
+const contextGaugeCells = 5
+
+// contextGauge shows how full the session's context window is, e.g.
+// "■■■□□ 62%": yellow from three quarters of the ContextPressure
+// percentage, red from it. It is empty when no model limit is known.
+func (m Model) contextGauge(s *state.SessionData) string {
+	pct := s.ContextUsagePercent(m.cfg.Models)
+	if pct == 0 {
+		return ""
+	}
+	filled := min(contextGaugeCells, int(pct/100*contextGaugeCells+0.5))
+	pressure := float64(m.cfg.Alerts.ContextPressurePercent)
+	style := costGreenStyle
+	switch {
+	case pct >= pressure:
+		style = costRedStyle
+	case pct >= pressure*3/4:
+		style = costYellowStyle
+	}
+	return style.Render(strings.Repeat("■", filled)) +
+		dimStyle.Render(strings.Repeat("□", contextGaugeCells-filled)) +
+		style.Render(fmt.Sprintf(" %.0f%%", pct))
+}
+
+// slaBadgeReserve is the room wide columns leave at the end of a row for
+// the SLA badge.
+const slaBadgeReserve = 8
+
+// wideColumn is a session column shown only when the panel has room to
+// spare after the full set of standard columns.
+type wideColumn struct {
+	header string
+	width  int
+	right  bool // right-align, for numbers
+	value  func(*state.SessionData) string
+}
+
+// wideColumns are disclosed in this order as the panel gets wider.
+var wideColumns = []wideColumn{
+	{header: "Cache", width: 5, right: true, value: sessionCacheCell},
+	{header: "Errs", width: 4, right: true, value: sessionErrorsCell},
+	{header: "Last tool", width: 12, value: sessionLastToolCell},
+	{header: "Branch", width: 12, value: func(s *state.SessionData) string {
+		if s.Branch == "" {
+			return "—"
+		}
+		return s.Branch
+	}},
+	{header: "Org", width: 8, value: func(s *state.SessionData) string {
+		if s.OrgID == "" {
+			return "—"
+		}
+		return truncateID(s.OrgID, 8)
+	}},
+}
+
+// wideColumnsFor returns the wide columns that fit in maxW after used
+// columns of standard width.
+func wideColumnsFor(maxW, used int) []wideColumn {
+	var cols []wideColumn
+	for _, c := range wideColumns {
+		if used+1+c.width > maxW-slaBadgeReserve {
+			break
+		}
+		used += 1 + c.width
+		cols = append(cols, c)
+	}
+	return cols
+}
+
+// fullSessionHeader is the header of the widest standard layout.
+func (m Model) fullSessionHeader(showTerm bool) string {
+	sw := m.startedWidth()
+	extra := sw - startedBaseWidth
+	if !showTerm {
+		return fmt.Sprintf("%-8s %-*s %-*s %-6s %-8s %-5s %-8s %-6s",
+			"Session", sw, "Started", 24-extra, "CWD", "Model", "Status", "Cost", "Tokens", "Time")
+	}
+	return fmt.Sprintf("%-8s %-*s %-8s %-*s %-6s %-8s %-5s %-8s %-6s",
+		"Session", sw, "Started", "Term", 15-extra, "CWD", "Model", "Status", "Cost", "Tokens", "Time")
+}
+
+// formatSessionHeader returns the column header string. The Term column is
+// omitted when showTerm is false (process scanner disabled).
+func (m Model) formatSessionHeader(maxW int, showTerm bool) string {
+	sw := m.startedWidth()
+	extra := sw - startedBaseWidth
 	if maxW >= 90 {
-		return fmt.Sprintf("%-8s %-9s %-8s %-15s %-6s %-8s %-5s %-8s %-6s",
-			"Session", "Started", "Term", "CWD", "Model", "Status", "Cost", "Tokens", "Time")
+		header := m.fullSessionHeader(showTerm)
+		for _, c := range wideColumnsFor(maxW, len(header)) {
+			if c.right {
+				header += fmt.Sprintf(" %*s", c.width, c.header)
+			} else {
+				header += fmt.Sprintf(" %-*s", c.width, c.header)
+			}
+		}
+		return header
 	}
 	if maxW >= 60 {
-		return fmt.Sprintf("%-8s %-9s %-8s %-12s %-6s %-5s",
-			"Session", "Started", "Term", "CWD", "Status", "Cost")
+		if !showTerm {
+			return fmt.Sprintf("%-8s %-*s %-*s %-6s %-5s",
+				"Session", sw, "Started", 21-extra, "CWD", "Status", "Cost")
+		}
+		return fmt.Sprintf("%-8s %-*s %-8s %-*s %-6s %-5s",
+			"Session", sw, "Started", "Term", 12-extra, "CWD", "Status", "Cost")
 	}
-	return fmt.Sprintf("%-8s %-9s %-6s %-5s",
-		"Session", "Started", "Status", "Cost")
+	return fmt.Sprintf("%-8s %-*s %-6s %-5s",
+		"Session", sw, "Started", "Status", "Cost")
 }
 
 // formatSessionRow formats a single session row based on available width.
-func formatSessionRow(s *state.SessionData, maxW int) string {
+// When showTerm is false the Term column's width is given to CWD.
+func (m Model) formatSessionRow(s *state.SessionData, maxW int, showTerm bool) string {
 	sessionID := truncateID(s.SessionID, 8)
-	started := formatStartedAt(s.StartedAt)
+	started := m.formatStartedAt(s.StartedAt)
 	terminal := truncateStr(s.Terminal, 8)
-	cwd := truncateCWD(s.CWD, 15)
 	model := truncateStr(s.Model, 6)
 	statusStr := renderStatus(s.Status())
 	cost := fmt.Sprintf("$%.2f", s.TotalCost)
 	tokens := formatNumber(s.TotalTokens)
 	activeTime := formatDuration(s.ActiveTime)
+	sw := m.startedWidth()
+	extra := sw - startedBaseWidth
 
 	if maxW >= 90 {
-		return fmt.Sprintf("%-8s %-9s %-8s %-15s %-6s %-8s %5s %8s %6s",
-			sessionID, started, terminal, cwd, model, statusStr, cost, tokens, activeTime)
+		var row string
+		if !showTerm {
+			row = fmt.Sprintf("%-8s %-*s %-*s %-6s %-8s %5s %8s %6s",
+				sessionID, sw, started, 24-extra, truncateCWD(s.CWD, 24-extra), model, statusStr, cost, tokens, activeTime)
+		} else {
+			row = fmt.Sprintf("%-8s %-*s %-8s %-*s %-6s %-8s %5s %8s %6s",
+				sessionID, sw, started, terminal, 15-extra, truncateCWD(s.CWD, 15-extra), model, statusStr, cost, tokens, activeTime)
+		}
+		for _, c := range wideColumnsFor(maxW, len(m.fullSessionHeader(showTerm))) {
+			v := truncateStr(c.value(s), c.width)
+			if c.right {
+				row += fmt.Sprintf(" %*s", c.width, v)
+			} else {
+				row += fmt.Sprintf(" %-*s", c.width, v)
+			}
+		}
+		return row
 	}
 	if maxW >= 60 {
-		return fmt.Sprintf("%-8s %-9s %-8s %-12s %-6s %5s",
-			sessionID, started, terminal, truncateCWD(s.CWD, 12), statusStr, cost)
+		if !showTerm {
+			return fmt.Sprintf("%-8s %-*s %-*s %-6s %5s",
+				sessionID, sw, started, 21-extra, truncateCWD(s.CWD, 21-extra), statusStr, cost)
+		}
+		return fmt.Sprintf("%-8s %-*s %-8s %-*s %-6s %5s",
+			sessionID, sw, started, terminal, 12-extra, truncateCWD(s.CWD, 12-extra), statusStr, cost)
+	}
+	return fmt.Sprintf("%-8s %-*s %-6s %5s",
+		sessionID, sw, started, statusStr, cost)
+}
+
+// sessionCacheCell is the share of input tokens served from the prompt
+// cache, cacheRead / (input + cacheRead) as in the Stats view.
+func sessionCacheCell(s *state.SessionData) string {
+	var cacheRead, input float64
+	for _, m := range s.Metrics {
+		if m.Name != "claude_code.token.usage" {
+			continue
+		}
+		// The counters are cumulative; the latest value wins.
+		switch m.Attributes["type"] {
+		case "cacheRead":
+			cacheRead = m.Value
+		case "input":
+			input = m.Value
+		}
+	}
+	if input+cacheRead == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.0f%%", cacheRead/(input+cacheRead)*100)
+}
+
+// sessionErrorsCell counts the session's API errors.
+func sessionErrorsCell(s *state.SessionData) string {
+	n := 0
+	for _, e := range s.Events {
+		if e.Name == "claude_code.api_error" {
+			n++
+		}
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// sessionLastToolCell is the tool of the session's latest tool result.
+func sessionLastToolCell(s *state.SessionData) string {
+	tool, last := "—", time.Time{}
+	for _, e := range s.Events {
+		if e.Name == "claude_code.tool_result" && !e.Timestamp.Before(last) && e.Attributes["tool_name"] != "" {
+			tool, last = e.Attributes["tool_name"], e.Timestamp
+		}
 	}
-	return fmt.Sprintf("%-8s %-9s %-6s %5s",
-		sessionID, started, statusStr, cost)
+	return tool
+}
+
+// startedBaseWidth is the width the row layouts budget for the Started
+// column; a wider time format takes the difference from the CWD column.
+const startedBaseWidth = 9
+
+// startedLayout is the Started column's layout: day and month, then the
+// local time of day in the configured display.time_format.
+func (m Model) startedLayout() string {
+	return "0201 " + m.clockLayout(false)
+}
+
+// startedWidth is the column width of formatStartedAt output, measured at
+// a two-digit hour.
+func (m Model) startedWidth() int {
+	return max(startedBaseWidth, len(time.Date(2000, 12, 31, 12, 0, 0, 0, time.UTC).Format(m.startedLayout())))
 }
 
-// formatStartedAt formats a timestamp as DDMMHHMM (day, month, hour, minute).
-func formatStartedAt(t time.Time) string {
+// formatStartedAt formats a session's start as DDMM and the time of day.
+func (m Model) formatStartedAt(t time.Time) string {
 	if t.IsZero() {
 		return "—"
 	}
-	return t.Format("0201 1504")
+	return t.Local().Format(m.startedLayout())
 }
 
 // renderStatus returns a styled string for the session status.
@@ -322,10 +514,10 @@ func min(a, b int) int {
 // TelemetryIcon returns the appropriate icon string for a session.
 func TelemetryIcon(s *state.SessionData) string {
 	if hasTelemetry(s) {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Render("OK")
+		return activeStyle.Render("OK")
 	}
 	if s.PID > 0 && !s.Exited {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("NO")
+		return exitedStyle.Render("NO")
 	}
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("??")
+	return dimStyle.Render("??")
 }
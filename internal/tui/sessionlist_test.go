@@ -8,7 +8,11 @@ import (
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
 	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/gitlog"
 	"github.com/nixlim/cc-top/internal/state"
 )
 
@@ -232,6 +236,7 @@ func TestRenderStatus(t *testing.T) {
 }
 
 func TestFormatSessionRow_Widths(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
 	s := &state.SessionData{
 		SessionID:   "sess-001-abcdef",
 		PID:         1234,
@@ -246,33 +251,111 @@ func TestFormatSessionRow_Widths(t *testing.T) {
 
 	// Test different widths.
 	for _, w := range []int{100, 70, 40} {
-		row := formatSessionRow(s, w)
+		row := m.formatSessionRow(s, w, true)
 		if row == "" {
 			t.Errorf("formatSessionRow at width %d returned empty", w)
 		}
 	}
 }
 
+func TestFormatSessionRow_WideColumns(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	now := time.Now()
+	s := &state.SessionData{
+		SessionID:   "sess-001-abcdef",
+		Terminal:    "iTerm2",
+		CWD:         "/Users/test/project",
+		Branch:      "feature/graphs",
+		Model:       "sonnet",
+		OrgID:       "org-42abcdef",
+		StartedAt:   now,
+		LastEventAt: now,
+		Metrics: []state.Metric{
+			{Name: "claude_code.token.usage", Value: 100, Attributes: map[string]string{"type": "input"}},
+			{Name: "claude_code.token.usage", Value: 300, Attributes: map[string]string{"type": "cacheRead"}},
+		},
+		Events: []state.Event{
+			{Name: "claude_code.tool_result", Timestamp: now.Add(-time.Minute), Attributes: map[string]string{"tool_name": "Read"}},
+			{Name: "claude_code.api_error", Timestamp: now.Add(-30 * time.Second)},
+			{Name: "claude_code.tool_result", Timestamp: now, Attributes: map[string]string{"tool_name": "Bash"}},
+		},
+	}
+
+	// Columns are disclosed in priority order as the width grows.
+	if h := m.formatSessionHeader(90, true); strings.Contains(h, "Cache") {
+		t.Errorf("90 columns should leave room for no wide column: %q", h)
+	}
+	mid := m.formatSessionHeader(101, true)
+	if !strings.Contains(mid, "Cache") || !strings.Contains(mid, "Errs") || strings.Contains(mid, "Last tool") {
+		t.Errorf("101 columns should add Cache and Errs only: %q", mid)
+	}
+
+	header := m.formatSessionHeader(200, true)
+	row := m.formatSessionRow(s, 200, true)
+	for _, want := range []string{"Cache", "Errs", "Last tool", "Branch", "Org"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header should contain %q: %q", want, header)
+		}
+	}
+	for _, want := range []string{" 75%", "   1 ", "Bash", "feature/gra", "org-42ab"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("row should contain %q: %q", want, row)
+		}
+	}
+	if len(header) != len(stripAnsi(row)) {
+		t.Errorf("row and header widths differ:\n%q\n%q", header, row)
+	}
+}
+
+func TestComputeDimensions_WideTerminal(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	if w := computeDimensions(180, 40).sessionListW; w != 72 {
+		t.Errorf("sessionListW at 180 columns = %d, want 72", w)
+	}
+	// Above 180 columns the session list gets room for its wide columns.
+	d := computeDimensions(200, 40)
+	if d.sessionListW != 120 {
+		t.Errorf("sessionListW at 200 columns = %d, want 120", d.sessionListW)
+	}
+	if cols := wideColumnsFor(d.sessionListW-4, len(m.fullSessionHeader(true))); len(cols) < 2 {
+		t.Errorf("a 200-column terminal should disclose wide columns, got %d", len(cols))
+	}
+}
+
 func TestFormatSessionRow_StartedAt(t *testing.T) {
 	started := time.Date(2026, 2, 22, 14, 5, 0, 0, time.Local)
 	s := &state.SessionData{
 		SessionID: "sess-001",
+		CWD:       "/tmp/project",
 		StartedAt: started,
 	}
 
-	row := formatSessionRow(s, 100)
-	// DDMM HHMM → "2202 1405"
-	if !strings.Contains(row, "2202 1405") {
-		t.Errorf("row should contain started timestamp '2202 1405', got: %s", row)
+	for _, tt := range []struct{ format, want string }{
+		{"24h_seconds", "2202 14:05"},
+		{"12h", "2202 2:05 PM"},
+	} {
+		cfg := config.DefaultConfig()
+		cfg.Display.TimeFormat = tt.format
+		m := NewModel(cfg)
+		for _, w := range []int{90, 70, 40} {
+			row, header := m.formatSessionRow(s, w, true), m.formatSessionHeader(w, true)
+			if !strings.Contains(row, tt.want) {
+				t.Errorf("%s at width %d: row should contain started timestamp %q, got: %s", tt.format, w, tt.want, row)
+			}
+			if len(stripAnsi(row)) != len(header) {
+				t.Errorf("%s at width %d: row and header widths differ:\n%q\n%q", tt.format, w, header, row)
+			}
+		}
 	}
 }
 
 func TestFormatSessionRow_NoStartedAt(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
 	s := &state.SessionData{
 		SessionID: "sess-001",
 	}
 
-	row := formatSessionRow(s, 100)
+	row := m.formatSessionRow(s, 100, true)
 	if !strings.Contains(row, "\u2014") { // em dash
 		t.Error("session with zero StartedAt should show em-dash")
 	}
@@ -414,3 +497,175 @@ func TestRenderSessionListPanel_HiddenCountIndicator(t *testing.T) {
 		t.Error("panel should show '+2 done sessions hidden' indicator")
 	}
 }
+
+func TestModel_SessionDetailShowsThrash(t *testing.T) {
+	var evts []state.Event
+	for range 5 {
+		evts = append(evts, state.Event{
+			Name: "claude_code.tool_result",
+			Attributes: map[string]string{
+				"tool_name":       "Edit",
+				"success":         "true",
+				"tool_parameters": `{"file_path":"/repo/loop.go"}`,
+			},
+		})
+	}
+	sp := &mockStateProvider{sessions: []state.SessionData{{SessionID: "sess-thrash", Events: evts}}}
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard), WithStateProvider(sp))
+	m.width = 120
+	m.height = 40
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.detailOverlay {
+		t.Fatal("first Enter should select the session, not open detail")
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if !m.detailOverlay || m.detailTitle != "Session Detail" {
+		t.Fatal("second Enter on the selected session should open session detail")
+	}
+	if !strings.Contains(m.detailContent, "Possible thrash") || !strings.Contains(m.detailContent, "/repo/loop.go") {
+		t.Errorf("session detail should report thrash, got:\n%s", m.detailContent)
+	}
+}
+
+func TestFormatSessionRow_HidesTerminalWithoutScanner(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	s := &state.SessionData{SessionID: "sess-001", Terminal: "iTerm2", CWD: "/tmp/project"}
+	for _, w := range []int{100, 70} {
+		if strings.Contains(m.formatSessionHeader(w, false), "Term") {
+			t.Errorf("header at width %d should omit Term column", w)
+		}
+		if strings.Contains(m.formatSessionRow(s, w, false), "iTerm2") {
+			t.Errorf("row at width %d should omit terminal name", w)
+		}
+		if len(m.formatSessionHeader(w, false)) != len(m.formatSessionHeader(w, true)) {
+			t.Errorf("header width at %d should be unchanged when Term is hidden", w)
+		}
+	}
+}
+
+func TestFormatSessionDetail_Annotations(t *testing.T) {
+	base := time.Date(2026, 2, 20, 10, 0, 0, 0, time.Local)
+	var evts []state.Event
+	for i := 0; i < maxDetailAnnotations+2; i++ {
+		evts = append(evts, state.Event{
+			Name:       state.AnnotationEventName,
+			Timestamp:  base.Add(time.Duration(i) * time.Minute),
+			Attributes: map[string]string{"text": fmt.Sprintf("note-%02d", i)},
+		})
+	}
+	m := NewModel(config.DefaultConfig())
+
+	out := m.formatSessionDetail(state.SessionData{SessionID: "sess-notes", Events: evts})
+	if !strings.Contains(out, "Annotations:") {
+		t.Fatalf("detail should list annotations, got:\n%s", out)
+	}
+	if strings.Contains(out, "note-00") || strings.Contains(out, "note-01") {
+		t.Error("only the most recent annotations should be shown")
+	}
+	if !strings.Contains(out, "10:11:00  note-11") {
+		t.Errorf("latest annotation missing, got:\n%s", out)
+	}
+
+	out = m.formatSessionDetail(state.SessionData{SessionID: "sess-plain"})
+	if strings.Contains(out, "Annotations:") {
+		t.Error("detail without annotations should omit the section")
+	}
+}
+
+type mockCommitProvider map[string][]gitlog.Commit
+
+func (p mockCommitProvider) Commits(sessionID string) []gitlog.Commit { return p[sessionID] }
+
+func TestFormatSessionDetail_Commits(t *testing.T) {
+	base := time.Date(2026, 2, 20, 10, 0, 0, 0, time.Local)
+	var commits []gitlog.Commit
+	for i := range maxDetailCommits + 2 {
+		commits = append(commits, gitlog.Commit{
+			Hash:    fmt.Sprintf("%02d3456789abcdef", i),
+			Subject: fmt.Sprintf("commit %d", i),
+			Time:    base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	m := NewModel(config.DefaultConfig(), WithCommitProvider(mockCommitProvider{"sess-git": commits}))
+
+	out := m.formatSessionDetail(state.SessionData{SessionID: "sess-git", TotalCost: 7.00})
+	if !strings.Contains(out, "Commits:   7 ($1.00/commit)") {
+		t.Errorf("detail should show commit count and cost per commit, got:\n%s", out)
+	}
+	if !strings.Contains(out, "00345678  10:00  commit 0") {
+		t.Errorf("commit line missing, got:\n%s", out)
+	}
+	if !strings.Contains(out, "... 2 more") || strings.Contains(out, "commit 5") {
+		t.Errorf("commit list should be capped, got:\n%s", out)
+	}
+
+	out = m.formatSessionDetail(state.SessionData{SessionID: "sess-none"})
+	if strings.Contains(out, "Commits:") {
+		t.Error("detail without commits should omit the section")
+	}
+}
+
+func TestFormatSessionDetail_BurnRate(t *testing.T) {
+	brp := &mockBurnRateProvider{perSess: map[string]burnrate.BurnRate{
+		"sess-hot": {HourlyRate: 6, Trend: burnrate.TrendUp, TokenVelocity: 1200, DailyProjection: 144, MonthlyProjection: 4320},
+	}}
+	m := NewModel(config.DefaultConfig(), WithBurnRateProvider(brp))
+
+	out := m.formatSessionDetail(state.SessionData{SessionID: "sess-hot"})
+	if !strings.Contains(out, "Burn rate: $6.00/hr ^  1,200 tokens/min") {
+		t.Errorf("detail should show the session's burn rate, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Projected: $144.00/day  $4320.00/mon") {
+		t.Errorf("detail should show the session's projections, got:\n%s", out)
+	}
+
+	out = m.formatSessionDetail(state.SessionData{SessionID: "sess-hot", Exited: true})
+	if strings.Contains(out, "Burn rate:") {
+		t.Error("exited sessions should not show a burn rate")
+	}
+}
+
+func TestSessionList_ReadsTickSnapshot(t *testing.T) {
+	mock := &mockStateProvider{sessions: []state.SessionData{{SessionID: "sess-one", StartedAt: time.Now()}}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mock), WithStartView(ViewDashboard))
+
+	updated, _ := m.Update(tickMsg(time.Now()))
+	m = updated.(Model)
+	mock.sessions = append(mock.sessions, state.SessionData{SessionID: "sess-two", StartedAt: time.Now()})
+	if got := len(m.getSessions()); got != 1 {
+		t.Fatalf("expected the tick snapshot until the next tick, got %d sessions", got)
+	}
+
+	updated, _ = m.Update(tickMsg(time.Now()))
+	m = updated.(Model)
+	if got := len(m.getSessions()); got != 2 {
+		t.Errorf("expected 2 sessions after the next tick, got %d", got)
+	}
+}
+
+func TestRenderSessionListPanel_ContextGauge(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mockState := &mockStateProvider{
+		sessions: []state.SessionData{{
+			SessionID:     "sess-ctx",
+			LastEventAt:   time.Now(),
+			ContextTokens: map[string]int64{"claude-opus-4-6": 124000},
+		}},
+	}
+
+	m := NewModel(cfg, WithStateProvider(mockState), WithStartView(ViewDashboard))
+	panel := stripAnsi(m.renderSessionListPanel(48, 30))
+	if !strings.Contains(panel, "■■■□□ 62%") {
+		t.Errorf("session row should show the context gauge:\n%s", panel)
+	}
+
+	// Without a known limit there is no gauge.
+	mockState.sessions[0].ContextTokens = map[string]int64{"some-model": 124000}
+	if panel := stripAnsi(m.renderSessionListPanel(48, 30)); strings.Contains(panel, "%") {
+		t.Errorf("unknown model should show no gauge:\n%s", panel)
+	}
+}
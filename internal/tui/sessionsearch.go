@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// maxSessionQuery caps the length of the Sessions panel search query.
+const maxSessionQuery = 64
+
+// matchesSessionQuery reports whether s matches the search query: a
+// case-insensitive substring of its CWD, session ID, model, terminal,
+// environment, host name, tags or note. A query starting with "#" matches
+// sessions carrying exactly that tag. query must already be lower case.
+func matchesSessionQuery(s *state.SessionData, query string, tags []string, note string) bool {
+	if tag, ok := strings.CutPrefix(query, "#"); ok && tag != "" {
+		return hasSessionTag(tag, tags, s.Metadata.Tags)
+	}
+	fields := []string{s.CWD, s.SessionID, s.Model, s.Terminal, s.Metadata.Environment, s.Metadata.HostName, note}
+	fields = append(append(fields, tags...), s.Metadata.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSessions returns the sessions matching query, or all of them when
+// query is empty. tagsOf returns a session's tags and note; it may be nil.
+func filterSessions(sessions []state.SessionData, query string, tagsOf func(sessionID string) ([]string, string)) []state.SessionData {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return sessions
+	}
+	var out []state.SessionData
+	for i := range sessions {
+		var tags []string
+		var note string
+		if tagsOf != nil {
+			tags, note = tagsOf(sessions[i].SessionID)
+		}
+		if matchesSessionQuery(&sessions[i], query, tags, note) {
+			out = append(out, sessions[i])
+		}
+	}
+	return out
+}
+
+// handleSessionSearchKey edits the Sessions panel search query, narrowing
+// the list as it changes. Enter keeps the filter; Esc clears it.
+func (m Model) handleSessionSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.sessionSearch = false
+		m.setSessionQuery("")
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		m.sessionSearch = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Backspace):
+		if r := []rune(m.sessionQuery); len(r) > 0 {
+			m.setSessionQuery(string(r[:len(r)-1]))
+		}
+		return m, nil
+	}
+
+	if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && len(m.sessionQuery)+len(msg.Runes) <= maxSessionQuery {
+		m.setSessionQuery(m.sessionQuery + string(msg.Runes))
+	}
+	return m, nil
+}
+
+// setSessionQuery changes the search query and moves the cursor back to the
+// top of the narrowed list.
+func (m *Model) setSessionQuery(q string) {
+	m.sessionQuery = q
+	m.sessionCursor = 0
+	m.sessionScrollOffset = 0
+}
+
+// sessionSearchTitle renders the search query shown in the Sessions panel
+// title, with a cursor while it is being typed.
+func (m Model) sessionSearchTitle() string {
+	if m.sessionSearch {
+		return " /" + m.sessionQuery + "_"
+	}
+	if m.sessionQuery != "" {
+		return " /" + m.sessionQuery
+	}
+	return ""
+}
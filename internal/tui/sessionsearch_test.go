@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestFilterSessions(t *testing.T) {
+	sessions := []state.SessionData{
+		{SessionID: "abc-111", CWD: "/Users/test/api-server", Model: "claude-opus-4", Terminal: "iTerm2"},
+		{SessionID: "def-222", CWD: "/Users/test/web", Model: "claude-sonnet-4", Terminal: "VS Code",
+			Metadata: state.SessionMetadata{Environment: "prod", HostName: "ci-runner-3"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"abc-111", "def-222"}},
+		{"API-SERVER", []string{"abc-111"}},
+		{"def", []string{"def-222"}},
+		{"sonnet", []string{"def-222"}},
+		{"vs code", []string{"def-222"}},
+		{"PROD", []string{"def-222"}},
+		{"runner", []string{"def-222"}},
+		{"/users/test", []string{"abc-111", "def-222"}},
+		{"nomatch", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range filterSessions(sessions, tt.query, nil) {
+			got = append(got, s.SessionID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("filterSessions(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSessionSearch_NarrowsAndClears(t *testing.T) {
+	now := time.Now()
+	mockState := &mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "sess-aaa", CWD: "/work/frontend", LastEventAt: now, StartedAt: now},
+		{SessionID: "sess-bbb", CWD: "/work/backend", LastEventAt: now, StartedAt: now},
+		{SessionID: "sess-ccc", CWD: "/work/queue", LastEventAt: now, StartedAt: now},
+	}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+	m.sessionCursor = 2
+
+	m = typeKeys(t, m, runes("/"), runes("q"))
+	if !m.sessionSearch || m.quitting {
+		t.Fatal("keys typed into the search should not trigger other bindings")
+	}
+	if got := m.getSessions(); len(got) != 1 || got[0].SessionID != "sess-ccc" {
+		t.Fatalf("query %q: got %d sessions, want only sess-ccc", m.sessionQuery, len(got))
+	}
+	if m.sessionCursor != 0 {
+		t.Errorf("cursor should reset when the query changes, got %d", m.sessionCursor)
+	}
+	if !strings.Contains(m.renderSessionListPanel(80, 20), "/q_") {
+		t.Error("panel title should show the query being typed")
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyBackspace}, runes("end"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.sessionSearch || m.sessionQuery != "end" {
+		t.Fatalf("Enter should keep the filter, got search=%v query=%q", m.sessionSearch, m.sessionQuery)
+	}
+	if got := m.getSessions(); len(got) != 2 {
+		t.Errorf("query %q: got %d sessions, want 2", m.sessionQuery, len(got))
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.selectedSession != "sess-aaa" {
+		t.Errorf("Enter should select the first filtered session, got %q", m.selectedSession)
+	}
+
+	m = typeKeys(t, m, runes("/"), tea.KeyMsg{Type: tea.KeyEscape})
+	if m.sessionSearch || m.sessionQuery != "" || len(m.getSessions()) != 3 {
+		t.Error("Esc in the search should clear the filter")
+	}
+}
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// sessionSortKeys are the orders of the session sort menu (s), in menu
+// order. The empty key keeps the snapshot's order.
+var sessionSortKeys = []struct {
+	key, label string
+}{
+	{"", "Default order"},
+	{"cost", "Cost"},
+	{"tokens", "Tokens"},
+	{"last_activity", "Last activity"},
+	{"started", "Start time"},
+	{"status", "Status"},
+	{"burn_rate", "Burn rate"},
+}
+
+// Menu option keys that set the direction rather than the sort key.
+const (
+	sortOptionAsc  = "dir:asc"
+	sortOptionDesc = "dir:desc"
+)
+
+// statusRank orders statuses for sorting, most active highest.
+var statusRank = map[state.SessionStatus]int{
+	state.StatusActive: 3,
+	state.StatusIdle:   2,
+	state.StatusDone:   1,
+	state.StatusExited: 0,
+}
+
+// sortSessions returns sessions in the chosen sort order, ties broken by
+// session ID. It sorts a copy, since sessions may be shared with the
+// snapshot.
+func (m Model) sortSessions(sessions []state.SessionData) []state.SessionData {
+	if m.sessionSort == "" || len(sessions) < 2 {
+		return sessions
+	}
+
+	var rates map[string]float64
+	if m.sessionSort == "burn_rate" && m.burnRate != nil {
+		rates = make(map[string]float64, len(sessions))
+		for _, s := range sessions {
+			rates[s.SessionID] = m.burnRate.Get(s.SessionID).HourlyRate
+		}
+	}
+
+	compare := func(a, b *state.SessionData) int {
+		switch m.sessionSort {
+		case "cost":
+			return cmp.Compare(a.TotalCost, b.TotalCost)
+		case "tokens":
+			return cmp.Compare(a.TotalTokens, b.TotalTokens)
+		case "last_activity":
+			return a.LastEventAt.Compare(b.LastEventAt)
+		case "started":
+			return a.StartedAt.Compare(b.StartedAt)
+		case "status":
+			return cmp.Compare(statusRank[a.Status()], statusRank[b.Status()])
+		case "burn_rate":
+			return cmp.Compare(rates[a.SessionID], rates[b.SessionID])
+		}
+		return 0
+	}
+
+	sorted := slices.Clone(sessions)
+	slices.SortStableFunc(sorted, func(a, b state.SessionData) int {
+		c := compare(&a, &b)
+		if !m.sessionSortAsc {
+			c = -c
+		}
+		return cmp.Or(c, cmp.Compare(a.SessionID, b.SessionID))
+	})
+	return sorted
+}
+
+// sessionSortTitle describes the sort order for the Sessions panel title,
+// e.g. " sort: Cost ↓".
+func (m Model) sessionSortTitle() string {
+	if m.sessionSort == "" {
+		return ""
+	}
+	arrow := "↓"
+	if m.sessionSortAsc {
+		arrow = "↑"
+	}
+	for _, k := range sessionSortKeys {
+		if k.key == m.sessionSort {
+			return " sort: " + k.label + " " + arrow
+		}
+	}
+	return ""
+}
+
+// openSessionSortMenu lists the sort keys and both directions, marking
+// the current choice.
+func (m *Model) openSessionSortMenu() {
+	var options []FilterOption
+	for _, k := range sessionSortKeys {
+		options = append(options, FilterOption{Label: k.label, Key: k.key, Enabled: m.sessionSort == k.key})
+	}
+	options = append(options,
+		FilterOption{Label: "Descending", Key: sortOptionDesc, Enabled: !m.sessionSortAsc},
+		FilterOption{Label: "Ascending", Key: sortOptionAsc, Enabled: m.sessionSortAsc},
+	)
+	cursor := 0
+	for i, opt := range options {
+		if opt.Key == m.sessionSort {
+			cursor = i
+		}
+	}
+	m.sessionSortMenu = FilterMenuState{Active: true, Cursor: cursor, Options: options}
+}
+
+func (m Model) handleSessionSortMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.SessionSort):
+		m.sessionSortMenu.Active = false
+
+	case key.Matches(msg, m.keys.Up):
+		if m.sessionSortMenu.Cursor > 0 {
+			m.sessionSortMenu.Cursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.sessionSortMenu.Cursor < len(m.sessionSortMenu.Options)-1 {
+			m.sessionSortMenu.Cursor++
+		}
+
+	case key.Matches(msg, m.keys.Enter):
+		if c := m.sessionSortMenu.Cursor; c >= 0 && c < len(m.sessionSortMenu.Options) {
+			m.applySessionSort(m.sessionSortMenu.Options[c].Key)
+			cursor := m.sessionSortMenu.Cursor
+			m.openSessionSortMenu()
+			m.sessionSortMenu.Cursor = cursor
+		}
+	}
+	return m, nil
+}
+
+// applySessionSort applies a sort menu option, keeping the cursor on the
+// session it was on.
+func (m *Model) applySessionSort(option string) {
+	var current string
+	if sessions := m.getSessions(); m.sessionCursor >= 0 && m.sessionCursor < len(sessions) {
+		current = sessions[m.sessionCursor].SessionID
+	}
+
+	switch option {
+	case sortOptionAsc:
+		m.sessionSortAsc = true
+	case sortOptionDesc:
+		m.sessionSortAsc = false
+	default:
+		m.sessionSort = option
+	}
+
+	for i, s := range m.getSessions() {
+		if s.SessionID == current {
+			m.sessionCursor = i
+			break
+		}
+	}
+}
+
+// overlaySessionSortMenu renders the sort menu in the top-left corner,
+// over the Sessions panel.
+func (m Model) overlaySessionSortMenu(base string) string {
+	content := panelTitleStyle.Render("Sort Sessions") + "\n\n"
+	for i, opt := range m.sessionSortMenu.Options {
+		if opt.Key == sortOptionDesc {
+			content += "\n"
+		}
+		cursor := "  "
+		if i == m.sessionSortMenu.Cursor {
+			cursor = "> "
+		}
+		check := "( )"
+		if opt.Enabled {
+			check = "(*)"
+		}
+		line := cursor + check + " " + opt.Label
+		if i == m.sessionSortMenu.Cursor {
+			line = selectedStyle.Render(line)
+		}
+		content += line + "\n"
+	}
+	content += "\nEnter: Select  Esc: Close"
+
+	dialog := filterMenuStyle.Render(content)
+	return placeOverlay(2, 2, dialog, base)
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func sessionIDs(sessions []state.SessionData) string {
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.SessionID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSortSessions(t *testing.T) {
+	now := time.Now()
+	sessions := []state.SessionData{
+		{SessionID: "a", TotalCost: 2, TotalTokens: 100, StartedAt: now.Add(-3 * time.Hour), LastEventAt: now.Add(-time.Hour)},
+		{SessionID: "b", TotalCost: 5, TotalTokens: 50, StartedAt: now.Add(-time.Hour), LastEventAt: now},
+		{SessionID: "c", TotalCost: 2, TotalTokens: 300, StartedAt: now.Add(-2 * time.Hour), LastEventAt: now.Add(-time.Minute)},
+	}
+	br := &mockBurnRateProvider{perSess: map[string]burnrate.BurnRate{
+		"a": {HourlyRate: 9}, "b": {HourlyRate: 1}, "c": {HourlyRate: 4},
+	}}
+	m := NewModel(config.DefaultConfig(), WithBurnRateProvider(br))
+
+	tests := []struct {
+		key  string
+		asc  bool
+		want string
+	}{
+		{"", false, "a,b,c"},
+		{"cost", false, "b,a,c"},
+		{"cost", true, "a,c,b"},
+		{"tokens", false, "c,a,b"},
+		{"last_activity", false, "b,c,a"},
+		{"started", true, "a,c,b"},
+		{"status", false, "b,c,a"},
+		{"burn_rate", false, "a,c,b"},
+	}
+	for _, tt := range tests {
+		m.sessionSort, m.sessionSortAsc = tt.key, tt.asc
+		if got := sessionIDs(m.sortSessions(sessions)); got != tt.want {
+			t.Errorf("sort %q asc=%v: got %s, want %s", tt.key, tt.asc, got, tt.want)
+		}
+	}
+	if sessionIDs(sessions) != "a,b,c" {
+		t.Error("sortSessions must not reorder its input")
+	}
+}
+
+func TestSessionSortMenu(t *testing.T) {
+	now := time.Now()
+	mockState := &mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "sess-aaa", TotalCost: 1, LastEventAt: now, StartedAt: now},
+		{SessionID: "sess-bbb", TotalCost: 3, LastEventAt: now, StartedAt: now},
+	}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+
+	m = typeKeys(t, m, runes("s"))
+	if !m.sessionSortMenu.Active {
+		t.Fatal("s should open the sort menu")
+	}
+	// Options: Default order, Cost, ...; select Cost.
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.sessionSort != "cost" || m.sessionSortAsc {
+		t.Fatalf("sort = %q asc=%v, want cost descending", m.sessionSort, m.sessionSortAsc)
+	}
+	if m.sessionCursor != 1 {
+		t.Errorf("cursor should follow sess-aaa to row 1, got %d", m.sessionCursor)
+	}
+	if got := sessionIDs(m.getSessions()); got != "sess-bbb,sess-aaa" {
+		t.Errorf("sessions = %s, want the costliest first", got)
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.sessionSortMenu.Active {
+		t.Fatal("Esc should close the sort menu")
+	}
+	if !strings.Contains(stripAnsi(m.renderSessionListPanel(100, 20)), "sort: Cost ↓") {
+		t.Error("panel title should show the sort order")
+	}
+
+	st := m.UIState()
+	if st.SessionSort != "cost" || st.SessionSortAsc {
+		t.Errorf("UIState sort = %q asc=%v", st.SessionSort, st.SessionSortAsc)
+	}
+	restored := NewModel(config.DefaultConfig(), WithUIState(UIState{SessionSort: "tokens", SessionSortAsc: true}))
+	if restored.sessionSort != "tokens" || !restored.sessionSortAsc {
+		t.Errorf("restored sort = %q asc=%v", restored.sessionSort, restored.sessionSortAsc)
+	}
+}
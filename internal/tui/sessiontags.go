@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxTagsInput caps the length of the session tags input.
+	maxTagsInput = 100
+	// maxSessionNoteInput caps the length of a session note.
+	maxSessionNoteInput = 200
+)
+
+// SessionTagger stores tags (e.g. "experiment", "prod-fix") and a note
+// attached to sessions from the TUI.
+type SessionTagger interface {
+	SessionTags(sessionID string) (tags []string, note string)
+	SetSessionTags(sessionID string, tags []string, note string)
+}
+
+// WithSessionTagger enables tagging sessions with t in the Sessions panel.
+func WithSessionTagger(t SessionTagger) ModelOption {
+	return func(m *Model) { m.tagger = t }
+}
+
+// sessionTags returns the tags and note attached to a session, if tagging
+// is enabled.
+func (m Model) sessionTags(sessionID string) ([]string, string) {
+	if m.tagger == nil {
+		return nil, ""
+	}
+	return m.tagger.SessionTags(sessionID)
+}
+
+// openTagPrompt opens the tags prompt for the selected session, or the one
+// under the cursor, filled with its current tags and note.
+func (m Model) openTagPrompt() (tea.Model, tea.Cmd) {
+	if m.tagger == nil {
+		return m, nil
+	}
+	target := m.selectedSession
+	if target == "" {
+		sessions := m.getSessions()
+		if m.sessionCursor < 0 || m.sessionCursor >= len(sessions) {
+			return m, nil
+		}
+		target = sessions[m.sessionCursor].SessionID
+	}
+	tags, note := m.tagger.SessionTags(target)
+	m.tagPrompt = true
+	m.tagTarget = target
+	m.tagInput = strings.Join(tags, ", ")
+	m.tagNoteInput = note
+	m.tagEditingNote = false
+	return m, nil
+}
+
+// handleTagPromptKey edits the tags and note fields; Tab switches between
+// them and Enter saves both.
+func (m Model) handleTagPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field, limit := &m.tagInput, maxTagsInput
+	if m.tagEditingNote {
+		field, limit = &m.tagNoteInput, maxSessionNoteInput
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.tagPrompt = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		m.tagger.SetSessionTags(m.tagTarget, strings.Split(m.tagInput, ","), m.tagNoteInput)
+		m.tagPrompt = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Tab):
+		m.tagEditingNote = !m.tagEditingNote
+		return m, nil
+
+	case key.Matches(msg, m.keys.Backspace):
+		if r := []rune(*field); len(r) > 0 {
+			*field = string(r[:len(r)-1])
+		}
+		return m, nil
+	}
+
+	if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && len(*field)+len(msg.Runes) <= limit {
+		*field += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m Model) overlayTagPrompt(base string) string {
+	tagsCursor, noteCursor := "_", ""
+	if m.tagEditingNote {
+		tagsCursor, noteCursor = "", "_"
+	}
+	content := panelTitleStyle.Render("Session Tags") + "\n\n" +
+		"Session: " + truncateID(m.tagTarget, 12) + "\n" +
+		"\nTags: " + m.tagInput + tagsCursor +
+		"\nNote: " + m.tagNoteInput + noteCursor + "\n" +
+		"\n" + dimStyle.Render("Comma-separated, e.g. experiment, demo  Tab: Tags/Note  Enter: Save  Esc: Cancel")
+
+	dialog := filterMenuStyle.Render(content)
+	x := max((m.width-lipgloss.Width(dialog))/2, 0)
+	y := max((m.height-lipgloss.Height(dialog))/2, 0)
+	return placeOverlay(x, y, dialog, base)
+}
+
+// hasSessionTag reports whether a session carries tag, attached in the TUI
+// or through the cc_top.tags resource attribute.
+func hasSessionTag(tag string, tags, resourceTags []string) bool {
+	return slices.Contains(tags, tag) || slices.ContainsFunc(resourceTags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
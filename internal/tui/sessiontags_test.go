@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// mockSessionTagger keeps session tags in memory.
+type mockSessionTagger struct {
+	tags  map[string][]string
+	notes map[string]string
+}
+
+func newMockSessionTagger() *mockSessionTagger {
+	return &mockSessionTagger{tags: make(map[string][]string), notes: make(map[string]string)}
+}
+
+func (t *mockSessionTagger) SessionTags(sessionID string) ([]string, string) {
+	return t.tags[sessionID], t.notes[sessionID]
+}
+
+func (t *mockSessionTagger) SetSessionTags(sessionID string, tags []string, note string) {
+	var clean []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			clean = append(clean, tag)
+		}
+	}
+	t.tags[sessionID], t.notes[sessionID] = clean, note
+}
+
+func TestTagPrompt_SetTagsAndNote(t *testing.T) {
+	now := time.Now()
+	tagger := newMockSessionTagger()
+	mockState := &mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "sess-aaa", CWD: "/work/frontend", LastEventAt: now, StartedAt: now},
+		{SessionID: "sess-bbb", CWD: "/work/backend", LastEventAt: now, StartedAt: now},
+	}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState), WithSessionTagger(tagger), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+	m.sessionCursor = 1
+
+	m = typeKeys(t, m, runes("t"))
+	if !m.tagPrompt || m.tagTarget != "sess-bbb" {
+		t.Fatalf("t should open the tag prompt for the session under the cursor, got prompt=%v target=%q", m.tagPrompt, m.tagTarget)
+	}
+	if !strings.Contains(stripAnsi(m.View()), "Session Tags") {
+		t.Error("the tag prompt should be drawn")
+	}
+
+	m = typeKeys(t, m, runes("experiment, demo"), tea.KeyMsg{Type: tea.KeyTab}, runes("q"), tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, runes("prompt test"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.tagPrompt || m.quitting {
+		t.Fatal("Enter should save and close the prompt; typed keys must not trigger bindings")
+	}
+	if got := tagger.tags["sess-bbb"]; strings.Join(got, ",") != "experiment,demo" {
+		t.Errorf("tags = %v", got)
+	}
+	if got := tagger.notes["sess-bbb"]; got != "q prompt test" {
+		t.Errorf("note = %q", got)
+	}
+
+	m.sessionQuery = "#demo"
+	if got := m.getSessions(); len(got) != 1 || got[0].SessionID != "sess-bbb" {
+		t.Errorf("#demo should narrow the list to the tagged session, got %d sessions", len(got))
+	}
+	m.sessionQuery = ""
+	if detail := stripAnsi(m.formatSessionDetail(mockState.sessions[1])); !strings.Contains(detail, "Tags:      experiment, demo") ||
+		!strings.Contains(detail, "Note:      q prompt test") {
+		t.Errorf("detail should list the tags and note:\n%s", detail)
+	}
+
+	// Reopening the prompt shows the saved values; Esc leaves them alone.
+	m.sessionCursor = 1
+	m = typeKeys(t, m, runes("t"))
+	if m.tagInput != "experiment, demo" || m.tagNoteInput != "q prompt test" {
+		t.Errorf("prompt should be prefilled, got %q / %q", m.tagInput, m.tagNoteInput)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.tagPrompt || len(tagger.tags["sess-bbb"]) != 2 {
+		t.Error("Esc should cancel without saving")
+	}
+}
+
+func TestFilterSessions_Tags(t *testing.T) {
+	sessions := []state.SessionData{
+		{SessionID: "abc-111", CWD: "/work/api"},
+		{SessionID: "def-222", CWD: "/work/web", Metadata: state.SessionMetadata{Tags: []string{"Demo"}}},
+		{SessionID: "ghi-333", CWD: "/work/experimental"},
+	}
+	tagsOf := func(id string) ([]string, string) {
+		if id == "abc-111" {
+			return []string{"experiment"}, "hotfix for the login bug"
+		}
+		return nil, ""
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"experiment", []string{"abc-111", "ghi-333"}},
+		{"#experiment", []string{"abc-111"}},
+		{"#demo", []string{"def-222"}},
+		{"login", []string{"abc-111"}},
+		{"#exp", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range filterSessions(sessions, tt.query, tagsOf) {
+			got = append(got, s.SessionID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("filterSessions(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// maxSLAInput caps the length of the expected-duration prompt input.
+const maxSLAInput = 16
+
+// slaRemaining returns the expected duration of s and the time left before
+// it is reached (negative once overrun). ok is false when s has no SLA
+// timer or has exited.
+func (m Model) slaRemaining(s *state.SessionData, now time.Time) (expected, remaining time.Duration, ok bool) {
+	if m.sla == nil || s.Exited || s.StartedAt.IsZero() {
+		return 0, 0, false
+	}
+	expected = m.sla.ExpectedDuration(*s)
+	if expected <= 0 {
+		return 0, 0, false
+	}
+	return expected, expected - now.Sub(s.StartedAt), true
+}
+
+// slaBadge renders the countdown shown after a session row: the time left,
+// yellow in the last quarter, or the overrun in red.
+func (m Model) slaBadge(s *state.SessionData, now time.Time) string {
+	expected, remaining, ok := m.slaRemaining(s, now)
+	if !ok {
+		return ""
+	}
+	switch {
+	case remaining < 0:
+		return costRedStyle.Render("⏱+" + formatDuration(-remaining))
+	case remaining < expected/4:
+		return costYellowStyle.Render("⏱" + formatDuration(remaining))
+	default:
+		return dimStyle.Render("⏱" + formatDuration(remaining))
+	}
+}
+
+// formatSLA describes the SLA timer of s for the session detail overlay.
+func (m Model) formatSLA(s *state.SessionData, now time.Time) string {
+	expected, remaining, ok := m.slaRemaining(s, now)
+	if !ok {
+		return ""
+	}
+	if remaining < 0 {
+		return fmt.Sprintf("%s expected, overrun by %s", formatDuration(expected), formatDuration(-remaining))
+	}
+	return fmt.Sprintf("%s expected, %s left", formatDuration(expected), formatDuration(remaining))
+}
+
+// openSLAPrompt opens the expected-duration prompt for the selected session,
+// or the one under the cursor.
+func (m Model) openSLAPrompt() (tea.Model, tea.Cmd) {
+	if m.sla == nil {
+		return m, nil
+	}
+	target := m.selectedSession
+	if target == "" {
+		sessions := m.getSessions()
+		if m.sessionCursor < 0 || m.sessionCursor >= len(sessions) {
+			return m, nil
+		}
+		target = sessions[m.sessionCursor].SessionID
+	}
+	m.slaPrompt = true
+	m.slaTarget = target
+	m.slaInput = ""
+	m.slaMessage = ""
+	return m, nil
+}
+
+// handleSLAPromptKey edits and applies the expected-duration prompt. An
+// empty input or "0" clears the session's timer.
+func (m Model) handleSLAPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.slaPrompt = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		var d time.Duration
+		if m.slaInput != "" && m.slaInput != "0" {
+			var err error
+			d, err = time.ParseDuration(m.slaInput)
+			if err != nil || d <= 0 {
+				m.slaMessage = "Enter a duration such as 45m or 1h30m"
+				return m, nil
+			}
+		}
+		m.sla.SetExpectedDuration(m.slaTarget, d)
+		m.slaPrompt = false
+		return m, nil
+
+	case key.Matches(msg, m.keys.Backspace):
+		if r := []rune(m.slaInput); len(r) > 0 {
+			m.slaInput = string(r[:len(r)-1])
+		}
+		return m, nil
+	}
+
+	if msg.Type == tea.KeyRunes && len(m.slaInput)+len(msg.Runes) <= maxSLAInput {
+		m.slaInput += string(msg.Runes)
+		m.slaMessage = ""
+	}
+	return m, nil
+}
+
+func (m Model) overlaySLAPrompt(base string) string {
+	content := panelTitleStyle.Render("Expected Duration") + "\n\n" +
+		"Session: " + truncateID(m.slaTarget, 12) + "\n"
+	if s := m.currentSnapshot().Session(m.slaTarget); s != nil {
+		if desc := m.formatSLA(s, time.Now()); desc != "" {
+			content += dimStyle.Render("Current: "+desc) + "\n"
+		}
+	}
+	content += "\n> " + m.slaInput + "_\n"
+	if m.slaMessage != "" {
+		content += alertWarningStyle.Render(m.slaMessage) + "\n"
+	}
+	content += "\n" + dimStyle.Render("e.g. 45m, 1h30m  Enter: Set (empty clears)  Esc: Cancel")
+
+	dialog := filterMenuStyle.Render(content)
+	x := max((m.width-lipgloss.Width(dialog))/2, 0)
+	y := max((m.height-lipgloss.Height(dialog))/2, 0)
+	return placeOverlay(x, y, dialog, base)
+}
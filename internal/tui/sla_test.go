@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func typeKeys(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+	for _, k := range keys {
+		result, _ := m.Update(k)
+		m = result.(Model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestSLAPrompt_SetAndClear(t *testing.T) {
+	timers := alerts.NewSLATimers()
+	mockState := &mockStateProvider{sessions: []state.SessionData{
+		{SessionID: "sess-sla", StartedAt: time.Now(), LastEventAt: time.Now()},
+	}}
+	m := NewModel(config.DefaultConfig(), WithStateProvider(mockState),
+		WithSLAProvider(timers), WithStartView(ViewDashboard))
+	m.width, m.height = 120, 40
+
+	m = typeKeys(t, m, runes("T"))
+	if !m.slaPrompt || m.slaTarget != "sess-sla" {
+		t.Fatalf("T should open the prompt for the session under the cursor")
+	}
+	if !strings.Contains(stripAnsi(m.View()), "Expected Duration") {
+		t.Error("prompt overlay should render")
+	}
+
+	m = typeKeys(t, m, runes("4"), runes("x"), tea.KeyMsg{Type: tea.KeyBackspace}, runes("5"), tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.slaPrompt || m.slaMessage == "" {
+		t.Fatal("a duration without a unit should be rejected and keep the prompt open")
+	}
+	m = typeKeys(t, m, runes("m"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.slaPrompt {
+		t.Fatal("a valid duration should close the prompt")
+	}
+	if got := timers.ExpectedDuration(mockState.sessions[0]); got != 45*time.Minute {
+		t.Fatalf("expected duration = %v, want 45m", got)
+	}
+
+	m = typeKeys(t, m, runes("T"), tea.KeyMsg{Type: tea.KeyEnter})
+	if got := timers.ExpectedDuration(mockState.sessions[0]); got != 0 {
+		t.Errorf("empty input should clear the timer, got %v", got)
+	}
+}
+
+func TestSLABadge(t *testing.T) {
+	now := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	timers := alerts.NewSLATimers()
+	m := NewModel(config.DefaultConfig(), WithSLAProvider(timers))
+
+	s := state.SessionData{SessionID: "s", StartedAt: now.Add(-30 * time.Minute)}
+	if got := m.slaBadge(&s, now); got != "" {
+		t.Errorf("no timer should render no badge, got %q", got)
+	}
+
+	s.Metadata.ExpectedDuration = 45 * time.Minute
+	if got := stripAnsi(m.slaBadge(&s, now)); got != "⏱15m0s" {
+		t.Errorf("countdown badge = %q, want ⏱15m0s", got)
+	}
+	timers.SetExpectedDuration("s", 20*time.Minute)
+	if got := stripAnsi(m.slaBadge(&s, now)); got != "⏱+10m0s" {
+		t.Errorf("overrun badge = %q, want ⏱+10m0s", got)
+	}
+	if got := m.formatSLA(&s, now); got != "20m0s expected, overrun by 10m0s" {
+		t.Errorf("detail = %q", got)
+	}
+
+	s.Exited = true
+	if got := m.slaBadge(&s, now); got != "" {
+		t.Errorf("exited sessions should not show a countdown, got %q", got)
+	}
+}
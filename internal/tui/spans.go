@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// maxDetailSpans caps the spans drawn in the session detail waterfall.
+const maxDetailSpans = 40
+
+// Column widths of the span waterfall; the bar gets the rest of the line,
+// within spanBarMinWidth and spanBarMaxWidth.
+const (
+	spanNameWidth   = 28
+	spanBarMinWidth = 10
+	spanBarMaxWidth = 60
+)
+
+// formatSpanWaterfall draws the session's latest trace as a waterfall: one
+// line per span in tree order, its bar placed on the trace's timeline so
+// tool executions and API calls can be compared. It returns nil when the
+// session has no spans.
+func (m Model) formatSpanWaterfall(s state.SessionData) []string {
+	if len(s.Spans) == 0 {
+		return nil
+	}
+
+	// The latest trace is the one whose span started last.
+	traceID := s.Spans[len(s.Spans)-1].TraceID
+	traces := make(map[string]bool)
+	var spans []state.Span
+	for _, sp := range s.Spans {
+		traces[sp.TraceID] = true
+		if sp.TraceID == traceID {
+			spans = append(spans, sp)
+		}
+	}
+
+	start, end := spans[0].Start, spans[0].End
+	failed := 0
+	for _, sp := range spans {
+		if sp.End.After(end) {
+			end = sp.End
+		}
+		if sp.Failed {
+			failed++
+		}
+	}
+	total := end.Sub(start)
+
+	lines := []string{fmt.Sprintf("Trace:     %s, %s, %d spans (%d failed), latest of %d",
+		m.formatClock(start), formatSpanDuration(total), len(spans), failed, len(traces))}
+
+	barW := min(max(m.detailContentWidth()-spanNameWidth-15, spanBarMinWidth), spanBarMaxWidth)
+	ordered, depths := spanTreeOrder(spans)
+	for i, sp := range ordered {
+		if i == maxDetailSpans {
+			lines = append(lines, fmt.Sprintf("  ... %d more", len(ordered)-maxDetailSpans))
+			break
+		}
+		name := truncateStr(strings.Repeat("  ", depths[i])+spanLabel(sp), spanNameWidth)
+		bar := spanBar(sp, start, total, barW)
+		mark := " "
+		if sp.Failed {
+			bar = costRedStyle.Render(bar)
+			mark = "✗"
+		}
+		lines = append(lines, fmt.Sprintf("  %-*s %7s %s %s", spanNameWidth, name, formatSpanDuration(sp.Duration()), bar, mark))
+	}
+	return lines
+}
+
+// spanTreeOrder orders spans depth first, children by start time, and
+// returns each span's depth. Spans whose parent is not among them are
+// roots.
+func spanTreeOrder(spans []state.Span) ([]state.Span, []int) {
+	ids := make(map[string]bool, len(spans))
+	for _, sp := range spans {
+		ids[sp.SpanID] = true
+	}
+	children := make(map[string][]state.Span)
+	var roots []state.Span
+	for _, sp := range spans {
+		if sp.ParentSpanID != "" && ids[sp.ParentSpanID] && sp.ParentSpanID != sp.SpanID {
+			children[sp.ParentSpanID] = append(children[sp.ParentSpanID], sp)
+		} else {
+			roots = append(roots, sp)
+		}
+	}
+
+	var ordered []state.Span
+	var depths []int
+	visited := make(map[string]bool, len(spans))
+	var walk func(sp state.Span, depth int)
+	walk = func(sp state.Span, depth int) {
+		if visited[sp.SpanID] {
+			return // a cycle in malformed input
+		}
+		visited[sp.SpanID] = true
+		ordered = append(ordered, sp)
+		depths = append(depths, depth)
+		for _, c := range children[sp.SpanID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, r := range roots {
+		walk(r, 0)
+	}
+	return ordered, depths
+}
+
+// spanLabel names a span for the waterfall, e.g. "tool Bash" for a
+// claude_code.tool span with tool_name Bash.
+func spanLabel(sp state.Span) string {
+	label := strings.TrimPrefix(sp.Name, "claude_code.")
+	for _, attr := range []string{"tool_name", "model"} {
+		if v := sp.Attributes[attr]; v != "" {
+			return label + " " + v
+		}
+	}
+	return label
+}
+
+// spanBar draws a span's extent on a timeline of width columns covering
+// total from start. Every span gets at least one column.
+func spanBar(sp state.Span, start time.Time, total time.Duration, width int) string {
+	from, to := 0, width
+	if total > 0 {
+		col := func(t time.Time) int {
+			return int(float64(t.Sub(start)) / float64(total) * float64(width))
+		}
+		from = min(max(col(sp.Start), 0), width-1)
+		to = min(max(col(sp.End), from+1), width)
+	}
+	cells := slices.Repeat([]string{" "}, width)
+	for i := from; i < to; i++ {
+		cells[i] = "█"
+	}
+	return "│" + strings.Join(cells, "") + "│"
+}
+
+// formatSpanDuration formats a span duration compactly: "850ms", "1.2s" or
+// "2m05s".
+func formatSpanDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestFormatSpanWaterfall(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.width = 120
+	start := time.Now().Add(-time.Minute)
+	span := func(trace, id, parent, name string, from, to time.Duration, attrs map[string]string) state.Span {
+		return state.Span{TraceID: trace, SpanID: id, ParentSpanID: parent, Name: name,
+			Start: start.Add(from), End: start.Add(to), Attributes: attrs}
+	}
+	failedTool := span("t2", "c", "a", "claude_code.tool", 2*time.Second, 4*time.Second, map[string]string{"tool_name": "Bash"})
+	failedTool.Failed = true
+	s := state.SessionData{Spans: []state.Span{
+		span("t1", "old", "", "claude_code.interaction", -time.Hour, -time.Hour+time.Second, nil),
+		span("t2", "a", "", "claude_code.interaction", 0, 10*time.Second, nil),
+		span("t2", "b", "a", "claude_code.llm_request", 0, 2*time.Second, map[string]string{"model": "claude-opus-4"}),
+		failedTool,
+	}}
+
+	lines := m.formatSpanWaterfall(s)
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 spans, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[0], "10.0s, 3 spans (1 failed), latest of 2") {
+		t.Errorf("header = %q", lines[0])
+	}
+	for i, want := range []string{"  interaction ", "    llm_request claude-opus-4", "    tool Bash"} {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Errorf("line %d = %q, want prefix %q", i+1, lines[i+1], want)
+		}
+	}
+	if !strings.Contains(stripAnsi(lines[3]), "2.0s") || !strings.HasSuffix(lines[3], "✗") {
+		t.Errorf("failed tool line = %q", lines[3])
+	}
+	for _, l := range lines {
+		if w := len([]rune(stripAnsi(l))); w > m.detailContentWidth() {
+			t.Errorf("line is %d wide, wider than the overlay (%d): %q", w, m.detailContentWidth(), l)
+		}
+	}
+
+	if lines := m.formatSpanWaterfall(state.SessionData{}); lines != nil {
+		t.Errorf("no spans should draw nothing, got %q", lines)
+	}
+}
+
+func TestSpanBar(t *testing.T) {
+	start := time.Now()
+	sp := state.Span{Start: start.Add(5 * time.Second), End: start.Add(7500 * time.Millisecond)}
+	if got := spanBar(sp, start, 10*time.Second, 10); got != "│     ██   │" {
+		t.Errorf("spanBar = %q", got)
+	}
+	// Instant spans still get a column.
+	sp.End = sp.Start
+	if got := spanBar(sp, start, 10*time.Second, 10); got != "│     █    │" {
+		t.Errorf("instant spanBar = %q", got)
+	}
+}
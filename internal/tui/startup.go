@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/nixlim/cc-top/internal/scanner"
@@ -13,41 +15,39 @@ func (m Model) renderStartup() string {
 	var sb strings.Builder
 
 	// Title bar.
-	titleLine := headerStyle.Width(m.width).Render(
-		" cc-top -- Scanning for Claude Code instances...")
+	title := " cc-top -- Scanning for Claude Code instances..."
+	if m.scannerDisabled {
+		title = " cc-top -- Process scanner disabled (--no-scanner)"
+	}
+	titleLine := headerStyle.Width(m.width).Render(title)
 	sb.WriteString(titleLine)
 	sb.WriteByte('\n')
 
 	processes := m.getProcesses()
 
-	if len(processes) == 0 {
+	if m.scannerDisabled {
+		sb.WriteByte('\n')
+		sb.WriteString(renderScannerDisabledNotice())
+	} else if len(processes) == 0 {
 		sb.WriteByte('\n')
 		sb.WriteString(dimStyle.Render("  No Claude Code instances found."))
 		sb.WriteByte('\n')
 		sb.WriteString(dimStyle.Render("  Start a Claude Code session and press [R] to rescan."))
 		sb.WriteByte('\n')
 	} else {
-		// Process table.
+		// Processes grouped by terminal, then project directory.
 		sb.WriteByte('\n')
 
-		// Table header.
-		header := fmt.Sprintf("  %-6s %-10s %-20s %-12s %-12s %-12s",
-			"PID", "Terminal", "CWD", "Telemetry", "OTLP Dest", "Status")
+		header := fmt.Sprintf("      %-6s %-12s %-12s %-12s",
+			"PID", "Telemetry", "OTLP Dest", "Status")
 		sb.WriteString(dimStyle.Render(header))
 		sb.WriteByte('\n')
 		sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", max(min(m.width-4, 80), 0))))
 		sb.WriteByte('\n')
 
 		var connected, misconfigured, noTelemetry int
-
 		for _, p := range processes {
-			statusInfo := m.getProcessStatus(p)
-			row := formatProcessRow(p, statusInfo)
-			sb.WriteString(row)
-			sb.WriteByte('\n')
-
-			// Count by status.
-			switch statusInfo.Status {
+			switch m.getProcessStatus(p).Status {
 			case scanner.TelemetryConnected, scanner.TelemetryWaiting:
 				connected++
 			case scanner.TelemetryWrongPort, scanner.TelemetryConsoleOnly:
@@ -57,6 +57,29 @@ func (m Model) renderStartup() string {
 			}
 		}
 
+		headerIdx := 0
+		for _, tg := range groupProcesses(processes) {
+			sb.WriteString(m.renderGroupHeader(headerIdx, tg.key(), tg.name, "", tg.count(), 1))
+			sb.WriteByte('\n')
+			headerIdx++
+			if m.startupCollapsed[tg.key()] {
+				continue
+			}
+			for _, pg := range tg.projects {
+				pkey := pg.key(tg.name)
+				sb.WriteString(m.renderGroupHeader(headerIdx, pkey, projectName(pg.cwd), pg.cwd, len(pg.procs), 2))
+				sb.WriteByte('\n')
+				headerIdx++
+				if m.startupCollapsed[pkey] {
+					continue
+				}
+				for _, p := range pg.procs {
+					sb.WriteString(formatProcessRow(p, m.getProcessStatus(p)))
+					sb.WriteByte('\n')
+				}
+			}
+		}
+
 		sb.WriteByte('\n')
 
 		// Summary line.
@@ -69,8 +92,16 @@ func (m Model) renderStartup() string {
 	sb.WriteByte('\n')
 
 	// Action keys.
-	sb.WriteString("  [E] Enable telemetry for all  [F] Fix misconfigured  [Enter] Continue  [R] Rescan")
+	if m.scannerDisabled {
+		sb.WriteString("  [E] Enable telemetry for all  [Enter] Continue")
+	} else {
+		sb.WriteString("  [E] Enable telemetry for all  [F] Fix misconfigured  [Enter] Continue  [R] Rescan  [Space] Collapse/expand")
+	}
 	sb.WriteByte('\n')
+	if m.autoAdvance {
+		sb.WriteString(dimStyle.Render("  The dashboard opens once a session sends telemetry."))
+		sb.WriteByte('\n')
+	}
 
 	// Status message.
 	if m.startupMessage != "" {
@@ -107,6 +138,59 @@ func (m Model) renderStartup() string {
 	return sb.String()
 }
 
+// applyStartView applies [display] start_view unless another view, such as
+// a remembered one, was already chosen.
+func (m *Model) applyStartView() {
+	if m.view != ViewStartup {
+		return
+	}
+	switch m.cfg.Display.StartView {
+	case "dashboard":
+		m.view = ViewDashboard
+	case "auto":
+		m.autoAdvance = true
+	}
+}
+
+// maybeAutoAdvance leaves the process list for the dashboard the first time
+// a session is receiving telemetry. It stops once the user leaves the
+// process list by other means.
+func (m *Model) maybeAutoAdvance() {
+	if !m.autoAdvance {
+		return
+	}
+	if m.view != ViewStartup {
+		m.autoAdvance = false
+		return
+	}
+	snap := m.currentSnapshot()
+	if snap == nil {
+		return
+	}
+	for i := range snap.Sessions {
+		if !snap.Sessions[i].Exited {
+			m.view = ViewDashboard
+			m.autoAdvance = false
+			return
+		}
+	}
+}
+
+// renderScannerDisabledNotice explains which features are unavailable when
+// cc-top runs without process inspection.
+func renderScannerDisabledNotice() string {
+	lines := []string{
+		"  Process inspection is off. Telemetry is still received and shown per session.",
+		"",
+		"  Unavailable in this mode:",
+		"    - PID and terminal columns",
+		"    - Session-to-process correlation",
+		"    - Per-process telemetry status, fix-up and rescan",
+		"    - Kill switch (Ctrl+K)",
+	}
+	return dimStyle.Render(strings.Join(lines, "\n")) + "\n"
+}
+
 // getProcesses returns the current process list from the scanner.
 func (m Model) getProcesses() []scanner.ProcessInfo {
 	if m.scanner == nil {
@@ -127,14 +211,104 @@ func (m Model) getProcessStatus(p scanner.ProcessInfo) scanner.StatusInfo {
 	return m.scanner.GetTelemetryStatus(p)
 }
 
-// formatProcessRow formats a single process for the startup screen table.
-func formatProcessRow(p scanner.ProcessInfo, status scanner.StatusInfo) string {
-	cwd := truncateCWD(p.CWD, 20)
-	terminal := truncateStr(p.Terminal, 10)
-	if terminal == "" {
-		terminal = "(headless)"
+// terminalGroup holds the processes running under one terminal app, split
+// by project directory.
+type terminalGroup struct {
+	name     string
+	projects []projectGroup
+}
+
+// projectGroup holds the processes sharing a working directory.
+type projectGroup struct {
+	cwd   string
+	procs []scanner.ProcessInfo
+}
+
+func (g terminalGroup) key() string { return g.name }
+
+func (g terminalGroup) count() int {
+	n := 0
+	for _, pg := range g.projects {
+		n += len(pg.procs)
+	}
+	return n
+}
+
+func (g projectGroup) key(terminal string) string { return terminal + "\x00" + g.cwd }
+
+// groupProcesses groups processes by terminal and then by CWD. Groups are
+// sorted by name and processes by PID so the layout is stable across scans.
+func groupProcesses(processes []scanner.ProcessInfo) []terminalGroup {
+	byTerm := make(map[string]map[string][]scanner.ProcessInfo)
+	for _, p := range processes {
+		term := p.Terminal
+		if term == "" {
+			term = "(headless)"
+		}
+		if byTerm[term] == nil {
+			byTerm[term] = make(map[string][]scanner.ProcessInfo)
+		}
+		byTerm[term][p.CWD] = append(byTerm[term][p.CWD], p)
+	}
+
+	groups := make([]terminalGroup, 0, len(byTerm))
+	for term, byCWD := range byTerm {
+		tg := terminalGroup{name: term}
+		for cwd, procs := range byCWD {
+			sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+			tg.projects = append(tg.projects, projectGroup{cwd: cwd, procs: procs})
+		}
+		sort.Slice(tg.projects, func(i, j int) bool { return tg.projects[i].cwd < tg.projects[j].cwd })
+		groups = append(groups, tg)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].name < groups[j].name })
+	return groups
+}
+
+// startupGroupKeys returns the keys of the group headers currently visible
+// on the startup screen, in display order. The startup cursor indexes into
+// this list.
+func (m Model) startupGroupKeys() []string {
+	var keys []string
+	for _, tg := range groupProcesses(m.getProcesses()) {
+		keys = append(keys, tg.key())
+		if m.startupCollapsed[tg.key()] {
+			continue
+		}
+		for _, pg := range tg.projects {
+			keys = append(keys, pg.key(tg.name))
+		}
 	}
+	return keys
+}
 
+// renderGroupHeader renders a terminal (depth 1) or project (depth 2)
+// heading with its expand/collapse marker and instance count.
+func (m Model) renderGroupHeader(idx int, key, label, detail string, count, depth int) string {
+	marker := "▾"
+	if m.startupCollapsed[key] {
+		marker = "▸"
+	}
+	line := fmt.Sprintf("%s%s %s (%d)", strings.Repeat("  ", depth), marker, label, count)
+	if detail != "" {
+		line += "  " + dimStyle.Render(truncateCWD(detail, 40))
+	}
+	if idx == m.startupCursor {
+		return selectedStyle.Render(line)
+	}
+	return panelTitleStyle.Render(line)
+}
+
+// projectName returns the last path element of cwd for use as a project label.
+func projectName(cwd string) string {
+	if cwd == "" {
+		return "(unknown)"
+	}
+	return filepath.Base(cwd)
+}
+
+// formatProcessRow formats a single process for the startup screen table.
+func formatProcessRow(p scanner.ProcessInfo, status scanner.StatusInfo) string {
 	telIcon := formatTelemetryIcon(status.Status)
 	otlpDest := formatOTLPDest(p)
 	statusLabel := status.Label
@@ -149,8 +323,8 @@ func formatProcessRow(p scanner.ProcessInfo, status scanner.StatusInfo) string {
 		style = dimStyle
 	}
 
-	row := fmt.Sprintf("  %-6d %-10s %-20s %-12s %-12s %-12s",
-		p.PID, terminal, cwd, telIcon, otlpDest, statusLabel)
+	row := fmt.Sprintf("      %-6d %-12s %-12s %-12s",
+		p.PID, telIcon, otlpDest, statusLabel)
 
 	return style.Render(row)
 }
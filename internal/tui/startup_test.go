@@ -3,9 +3,12 @@ package tui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nixlim/cc-top/internal/config"
 	"github.com/nixlim/cc-top/internal/scanner"
+	"github.com/nixlim/cc-top/internal/state"
 )
 
 // Mock scanner provider for startup tests.
@@ -190,3 +193,146 @@ func TestFormatOTLPDest(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderStartup_ScannerDisabled(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewStartup), WithScannerDisabled(true))
+	m.width = 120
+	m.height = 40
+
+	view := m.renderStartup()
+	for _, want := range []string{"--no-scanner", "Session-to-process correlation", "Kill switch"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("scanner-disabled startup should mention %q", want)
+		}
+	}
+	if strings.Contains(view, "[R] Rescan") || strings.Contains(view, "[F] Fix") {
+		t.Error("scanner-disabled startup should not offer rescan or fix actions")
+	}
+	if strings.Contains(view, "No Claude Code instances found") {
+		t.Error("scanner-disabled startup should not report an empty process scan")
+	}
+}
+
+func TestRenderStartup_GroupsByTerminalAndProject(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mockScanner := &mockScannerProvider{
+		processes: []scanner.ProcessInfo{
+			{PID: 300, Terminal: "iTerm2", CWD: "/Users/test/api"},
+			{PID: 100, Terminal: "iTerm2", CWD: "/Users/test/myapp"},
+			{PID: 200, Terminal: "iTerm2", CWD: "/Users/test/myapp"},
+			{PID: 400, Terminal: "tmux", CWD: "/Users/test/tools"},
+		},
+	}
+
+	m := NewModel(cfg, WithStartView(ViewStartup), WithScannerProvider(mockScanner))
+	m.width = 120
+	m.height = 40
+
+	keys := m.startupGroupKeys()
+	if len(keys) != 5 {
+		t.Fatalf("expected 2 terminal + 3 project headers, got %d: %q", len(keys), keys)
+	}
+
+	view := m.renderStartup()
+	for _, want := range []string{"iTerm2 (3)", "myapp (2)", "api (1)", "tmux (1)", "tools (1)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("startup should contain group header %q, got:\n%s", want, view)
+		}
+	}
+	// Terminals are sorted, and processes within a project are sorted by PID.
+	if strings.Index(view, "iTerm2") > strings.Index(view, "tmux") {
+		t.Error("terminal groups should be sorted by name")
+	}
+	if strings.Index(view, "100") > strings.Index(view, "200") {
+		t.Error("processes should be sorted by PID within a project")
+	}
+}
+
+func TestStartup_CollapseGroup(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mockScanner := &mockScannerProvider{
+		processes: []scanner.ProcessInfo{
+			{PID: 100, Terminal: "iTerm2", CWD: "/Users/test/myapp"},
+			{PID: 400, Terminal: "tmux", CWD: "/Users/test/tools"},
+		},
+	}
+
+	m := NewModel(cfg, WithStartView(ViewStartup), WithScannerProvider(mockScanner))
+	m.width = 120
+	m.height = 40
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	// Collapse the first terminal group.
+	result, _ := m.Update(space)
+	m = result.(Model)
+	view := m.renderStartup()
+	if strings.Contains(view, "myapp") || strings.Contains(view, "100") {
+		t.Errorf("collapsed terminal should hide its projects and processes, got:\n%s", view)
+	}
+	if !strings.Contains(view, "▸ iTerm2 (1)") {
+		t.Errorf("collapsed terminal should show a collapsed marker, got:\n%s", view)
+	}
+	if !strings.Contains(view, "400") {
+		t.Error("other terminal groups should stay expanded")
+	}
+
+	// Headers under a collapsed group are skipped by the cursor.
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = result.(Model)
+	if got := m.startupGroupKeys()[m.startupCursor]; got != "tmux" {
+		t.Errorf("cursor should move to the next visible header, got %q", got)
+	}
+
+	// Expand again.
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = result.(Model)
+	result, _ = m.Update(space)
+	m = result.(Model)
+	if !strings.Contains(m.renderStartup(), "100") {
+		t.Error("expanding the group should show its processes again")
+	}
+}
+
+func TestStartView(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.StartView = "dashboard"
+	if m := NewModel(cfg, WithStartView(ViewStartup)); m.view != ViewDashboard {
+		t.Errorf("start_view dashboard: got view %d", m.view)
+	}
+	m := NewModel(cfg, WithUIState(UIState{View: "history"}))
+	if m.view != ViewHistory {
+		t.Errorf("a remembered view should win over start_view, got %d", m.view)
+	}
+}
+
+func TestStartView_AutoAdvance(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.StartView = "auto"
+	mock := &mockStateProvider{}
+	m := NewModel(cfg, WithStateProvider(mock), WithScannerProvider(&mockScannerProvider{}))
+	m.width, m.height = 120, 40
+
+	if !strings.Contains(m.renderStartup(), "opens once a session sends telemetry") {
+		t.Error("auto start view should explain when the dashboard opens")
+	}
+	updated, _ := m.Update(tickMsg(time.Now()))
+	m = updated.(Model)
+	if m.view != ViewStartup {
+		t.Fatalf("should stay on the process list without telemetry, got %d", m.view)
+	}
+
+	mock.sessions = []state.SessionData{{SessionID: "sess-1", StartedAt: time.Now()}}
+	updated, _ = m.Update(tickMsg(time.Now()))
+	m = updated.(Model)
+	if m.view != ViewDashboard {
+		t.Fatalf("should open the dashboard once a session has telemetry, got %d", m.view)
+	}
+
+	// Going back to the process list later does not bounce to the dashboard.
+	m.view = ViewStartup
+	updated, _ = m.Update(tickMsg(time.Now()))
+	if updated.(Model).view != ViewStartup {
+		t.Error("auto-advance should only happen once")
+	}
+}
@@ -2,9 +2,12 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nixlim/cc-top/internal/records"
 	"github.com/nixlim/cc-top/internal/stats"
 )
 
@@ -18,7 +21,7 @@ func (m Model) renderStats() string {
 		viewLabel += " Global"
 	}
 	indicators := m.headerIndicators()
-	help := "Tab:History  q:Quit "
+	help := "Tab:Projects  q:Quit "
 	padding := m.width - lipgloss.Width(" cc-top") - lipgloss.Width(viewLabel) - lipgloss.Width(indicators) - lipgloss.Width(help)
 	if padding < 0 {
 		padding = 0
@@ -28,7 +31,9 @@ func (m Model) renderStats() string {
 	sb.WriteString(headerLine)
 	sb.WriteByte('\n')
 
+	start := time.Now()
 	ds := m.getStats()
+	m.watchdog.span("compute", start)
 
 	contentW := m.width - 4
 	if contentW < 20 {
@@ -36,13 +41,27 @@ func (m Model) renderStats() string {
 	}
 
 	sections := []string{
+		m.renderRecordsSection(),
 		m.renderCodeSection(ds),
 		m.renderToolsSection(ds),
+		m.renderToolFailures(ds),
 		m.renderAPISection(ds),
+		m.renderTurnSection(ds),
+		m.renderRateLimitSection(ds),
 		m.renderTokenBreakdownSection(ds),
 		m.renderModelBreakdown(ds),
+		m.renderTierSection(ds),
+		m.renderAccountBreakdown(ds),
+		m.renderTeamBreakdown(ds),
+		m.renderVersionBreakdown(ds),
 		m.renderTopTools(ds),
 	}
+	if m.plan != nil {
+		sections = slices.Insert(sections, 1, m.renderPlanSection(time.Now()))
+	}
+	if m.writerStats != nil {
+		sections = append(sections, m.renderWriterSection(time.Now()))
+	}
 
 	allLines := []string{}
 	for _, section := range sections {
@@ -84,6 +103,33 @@ func (m Model) getStats() stats.DashboardStats {
 	return m.stats.GetGlobal()
 }
 
+// renderRecordsSection shows the all-time records, in the session view too.
+func (m Model) renderRecordsSection() string {
+	lines := []string{panelTitleStyle.Render("Records (all time)")}
+	var recs []records.Record
+	if m.records != nil {
+		recs = m.records.Records(time.Now())
+	}
+	if len(recs) == 0 {
+		lines = append(lines, dimStyle.Render("  No records yet"))
+	}
+	for _, r := range recs {
+		var detail string
+		switch r.Name {
+		case records.CostliestDay:
+			detail = r.Detail
+		case records.HourlyRate:
+			detail = m.formatDateTime(r.AchievedAt)
+		case records.LongestSession:
+			detail = truncateID(r.SessionID, 8)
+		case records.BiggestRequest:
+			detail = strings.TrimSpace(r.Detail + " " + truncateID(r.SessionID, 8))
+		}
+		lines = append(lines, fmt.Sprintf("  %-20s %-11s %s", r.Label()+":", r.FormatValue(), dimStyle.Render(detail)))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderCodeSection(ds stats.DashboardStats) string {
 	title := panelTitleStyle.Render("Code Metrics")
 	lines := []string{
@@ -111,19 +157,85 @@ func (m Model) renderToolsSection(ds stats.DashboardStats) string {
 	return strings.Join(lines, "\n")
 }
 
+// renderToolFailures shows how often each tool's calls failed, most
+// failures first.
+func (m Model) renderToolFailures(ds stats.DashboardStats) string {
+	lines := []string{panelTitleStyle.Render("Tool Failure Rates")}
+	if len(ds.ToolFailures) == 0 {
+		lines = append(lines, dimStyle.Render("  No tool data"))
+	}
+	for _, t := range ds.ToolFailures {
+		bar := renderProgressBar(t.Rate, 20)
+		lines = append(lines, fmt.Sprintf("  %-15s %s %3.0f%%  %d of %d failed", t.ToolName, bar, t.Rate*100, t.Failures, t.Calls))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderAPISection(ds stats.DashboardStats) string {
 	title := panelTitleStyle.Render("API Performance")
+
+	avgLine := fmt.Sprintf("  Avg API latency:  %.1fs", ds.AvgAPILatency)
+	if ds.LatencyAvgMethod != "" && ds.LatencyAvgMethod != stats.LatencyAvgMean {
+		avgLine += dimStyle.Render(" (" + ds.LatencyAvgMethod + ")")
+	}
+	if ds.LatencyOutliers > 0 {
+		avgLine += dimStyle.Render(fmt.Sprintf(" [%d outlier(s)]", ds.LatencyOutliers))
+	}
+
 	lines := []string{
 		title,
 		fmt.Sprintf("  Cache efficiency: %s %.0f%%",
 			renderProgressBar(ds.CacheEfficiency, 20), ds.CacheEfficiency*100),
-		fmt.Sprintf("  Avg API latency:  %.1fs", ds.AvgAPILatency),
+		avgLine,
+		fmt.Sprintf("  Max API latency:  %.1fs", ds.LatencyMax),
 		fmt.Sprintf("  Error rate:       %s %.1f%%",
 			renderProgressBar(ds.ErrorRate, 20), ds.ErrorRate*100),
 	}
 	return strings.Join(lines, "\n")
 }
 
+// renderTurnSection shows where the wall time of conversation turns went.
+func (m Model) renderTurnSection(ds stats.DashboardStats) string {
+	title := panelTitleStyle.Render("Turn Time Split")
+	tb := ds.TurnBreakdown
+	wall := tb.Wall()
+	if tb.Turns == 0 || wall <= 0 {
+		return title + "\n" + dimStyle.Render("  No turns")
+	}
+	row := func(label string, d time.Duration) string {
+		ratio := float64(d) / float64(wall)
+		bar := strings.Repeat("█", int(ratio*20+0.5))
+		return fmt.Sprintf("  %-12s %-20s %3.0f%%  %s", label, bar, ratio*100, formatDuration(d))
+	}
+	lines := []string{
+		title,
+		fmt.Sprintf("  Turns:       %d", tb.Turns) +
+			dimStyle.Render(fmt.Sprintf(" (avg %s)", formatDuration(wall/time.Duration(tb.Turns)))),
+		row("API:", tb.API),
+		row("Tools:", tb.Tool),
+		row("Other agent:", tb.Other),
+		row("User wait:", tb.UserWait),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderRateLimitSection(ds stats.DashboardStats) string {
+	title := panelTitleStyle.Render("Rate Limits")
+	rl := ds.RateLimitPacing
+	if rl.RateLimited == 0 {
+		return title + "\n" + dimStyle.Render("  No 429 responses")
+	}
+	lines := []string{
+		title,
+		fmt.Sprintf("  429 responses:    %d (last %s ago)",
+			rl.RateLimited, formatDuration(time.Since(rl.LastAt))),
+		fmt.Sprintf("  Observed pace:    %.1f req/min", rl.ObservedRPM) +
+			dimStyle.Render(" (5m before last 429)"),
+		alertWarningStyle.Render(fmt.Sprintf("  Suggested pace:   <= %.1f req/min", rl.SuggestedRPM)),
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderTokenBreakdownSection(ds stats.DashboardStats) string {
 	title := panelTitleStyle.Render("Token Breakdown")
 	if len(ds.TokenBreakdown) == 0 {
@@ -141,21 +253,168 @@ func (m Model) renderTokenBreakdownSection(ds stats.DashboardStats) string {
 
 func (m Model) renderModelBreakdown(ds stats.DashboardStats) string {
 	title := panelTitleStyle.Render("Model Breakdown")
+	models, column := ds.ModelBreakdown, "Model"
+	if m.statsModelFamily {
+		models, column = stats.GroupByFamily(models), "Family"
+		title += dimStyle.Render(" (by family, g: by model ID)")
+	} else if len(models) > 0 {
+		title += dimStyle.Render(" (g: by family)")
+	}
 	lines := []string{title}
 
-	if len(ds.ModelBreakdown) == 0 {
+	if len(models) == 0 {
 		lines = append(lines, dimStyle.Render("  No model data"))
 	} else {
-		lines = append(lines, fmt.Sprintf("  %-25s %10s %12s", "Model", "Cost", "Tokens"))
+		lines = append(lines, fmt.Sprintf("  %-25s %10s %12s", column, "Cost", "Tokens"))
 		lines = append(lines, dimStyle.Render("  "+strings.Repeat("─", 50)))
-		for _, ms := range ds.ModelBreakdown {
+		for _, ms := range models {
+			name := ms.Model
+			if ms.Variants > 1 {
+				name = fmt.Sprintf("%s (%d IDs)", name, ms.Variants)
+			}
 			lines = append(lines, fmt.Sprintf("  %-25s $%9.2f %12s",
-				truncateStr(ms.Model, 25), ms.TotalCost, formatNumber(ms.TotalTokens)))
+				truncateStr(name, 25), ms.TotalCost, formatNumber(ms.TotalTokens)))
 		}
 	}
 	return strings.Join(lines, "\n")
 }
 
+func (m Model) renderTierSection(ds stats.DashboardStats) string {
+	title := panelTitleStyle.Render("Pricing Tiers")
+	if len(ds.TierBreakdown) == 0 {
+		return title + "\n" + dimStyle.Render("  No API requests")
+	}
+	if len(ds.TierBreakdown) == 1 && ds.TierBreakdown[0].Tier == "standard" {
+		return title + "\n" + dimStyle.Render("  All requests on the standard tier")
+	}
+
+	lines := []string{title}
+	lines = append(lines, fmt.Sprintf("  %-12s %10s %10s %12s", "Tier", "Requests", "Cost", "At standard"))
+	lines = append(lines, dimStyle.Render("  "+strings.Repeat("─", 47)))
+	for _, ts := range ds.TierBreakdown {
+		lines = append(lines, fmt.Sprintf("  %-12s %10s $%9.2f $%11.2f",
+			truncateStr(ts.Tier, 12), formatNumber(int64(ts.Requests)), ts.CostUSD, ts.StandardCostUSD))
+	}
+	switch {
+	case ds.TierSavingsUSD > 0:
+		lines = append(lines, costGreenStyle.Render(fmt.Sprintf("  Saved vs standard: $%.2f", ds.TierSavingsUSD)))
+	case ds.TierSavingsUSD < 0:
+		lines = append(lines, costYellowStyle.Render(fmt.Sprintf("  Premium vs standard: $%.2f", -ds.TierSavingsUSD)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderAccountBreakdown(ds stats.DashboardStats) string {
+	title := panelTitleStyle.Render("Account Breakdown")
+	lines := []string{title}
+
+	if len(ds.AccountBreakdown) == 0 {
+		lines = append(lines, dimStyle.Render("  No account data"))
+	} else {
+		lines = append(lines, fmt.Sprintf("  %-40s %10s %8s", "Account (org / user)", "Cost", "Sessions"))
+		lines = append(lines, dimStyle.Render("  "+strings.Repeat("─", 60)))
+		for _, as := range ds.AccountBreakdown {
+			lines = append(lines, fmt.Sprintf("  %-40s $%9.2f %8d",
+				truncateStr(formatAccount(as.OrgID, as.UserUUID), 40), as.TotalCost, as.SessionCount))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTeamBreakdown shows cost and sessions per machine and per user,
+// which `cc-top serve` collects from the instances pushing to it.
+func (m Model) renderTeamBreakdown(ds stats.DashboardStats) string {
+	title := panelTitleStyle.Render("Team (hosts / users)")
+	lines := []string{title}
+
+	hasName := func(groups []stats.TeamStats) bool {
+		return slices.ContainsFunc(groups, func(g stats.TeamStats) bool { return g.Name != "" })
+	}
+	if !hasName(ds.HostBreakdown) && !hasName(ds.UserBreakdown) {
+		lines = append(lines, dimStyle.Render("  No host or user data (see cc-top serve)"))
+		return strings.Join(lines, "\n")
+	}
+
+	table := func(label string, groups []stats.TeamStats) {
+		lines = append(lines, fmt.Sprintf("  %-30s %10s %8s %6s", label, "Cost", "Sessions", "Active"))
+		lines = append(lines, dimStyle.Render("  "+strings.Repeat("─", 60)))
+		for _, g := range groups {
+			name := g.Name
+			if name == "" {
+				name = "(unknown)"
+			}
+			lines = append(lines, fmt.Sprintf("  %-30s $%9.2f %8d %6d",
+				truncateStr(name, 30), g.TotalCost, g.SessionCount, g.Active))
+		}
+	}
+	table("Host", ds.HostBreakdown)
+	if hasName(ds.UserBreakdown) {
+		lines = append(lines, "")
+		table("User", ds.UserBreakdown)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderVersionBreakdown shows how sessions are spread across Claude Code
+// releases, with a warning naming the machines on releases older than the
+// configured minimum.
+func (m Model) renderVersionBreakdown(ds stats.DashboardStats) string {
+	title := panelTitleStyle.Render("Claude Code Versions")
+	lines := []string{title}
+
+	if len(ds.VersionBreakdown) == 0 {
+		lines = append(lines, dimStyle.Render("  No version data"))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, fmt.Sprintf("  %-14s %8s %6s %-30s", "Version", "Sessions", "Users", "Machines"))
+	lines = append(lines, dimStyle.Render("  "+strings.Repeat("─", 60)))
+	var outdatedSessions int
+	var outdatedHosts []string
+	for _, vs := range ds.VersionBreakdown {
+		version := vs.Version
+		if version == "" {
+			version = "(unknown)"
+		}
+		hosts := strings.Join(vs.Hosts, ", ")
+		if hosts == "" {
+			hosts = "-"
+		}
+		line := fmt.Sprintf("  %-14s %8d %6d %-30s",
+			truncateStr(version, 14), vs.SessionCount, vs.Users, truncateStr(hosts, 30))
+		if vs.Outdated {
+			line = costYellowStyle.Render(line)
+			outdatedSessions += vs.SessionCount
+			outdatedHosts = append(outdatedHosts, vs.Hosts...)
+		}
+		lines = append(lines, line)
+	}
+	if outdatedSessions > 0 {
+		warning := fmt.Sprintf("  ⚠ %d session(s) run a version older than %s", outdatedSessions, ds.MinVersion)
+		if len(outdatedHosts) > 0 {
+			slices.Sort(outdatedHosts)
+			warning += " on " + strings.Join(slices.Compact(outdatedHosts), ", ")
+		}
+		lines = append(lines, costRedStyle.Render(warning))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatAccount renders an org_id/user_uuid pair for display, using
+// "(unknown)" for sessions that did not report account attributes.
+func formatAccount(orgID, userUUID string) string {
+	if orgID == "" && userUUID == "" {
+		return "(unknown)"
+	}
+	if orgID == "" {
+		orgID = "-"
+	}
+	if userUUID == "" {
+		userUUID = "-"
+	}
+	return orgID + " / " + truncateID(userUUID, 8)
+}
+
 func (m Model) renderTopTools(ds stats.DashboardStats) string {
 	title := panelTitleStyle.Render("Top Tools")
 	lines := []string{title}
@@ -3,8 +3,10 @@ package tui
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/records"
 	"github.com/nixlim/cc-top/internal/stats"
 )
 
@@ -160,6 +162,27 @@ func TestRenderModelBreakdown_Empty(t *testing.T) {
 	}
 }
 
+func TestRenderModelBreakdown_FamilyToggle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg, WithStartView(ViewStats))
+	ds := stats.DashboardStats{ModelBreakdown: []stats.ModelStats{
+		{Model: "claude-sonnet-4-5-20251101", TotalCost: 2, TotalTokens: 2000},
+		{Model: "claude-sonnet-4-5-20250929", TotalCost: 1, TotalTokens: 1000},
+	}}
+	if section := m.renderModelBreakdown(ds); !strings.Contains(section, "202509") || !strings.Contains(section, "g: by family") {
+		t.Errorf("default breakdown should list model IDs:\n%s", section)
+	}
+
+	m = sendKey(m, "g")
+	section := m.renderModelBreakdown(ds)
+	if !strings.Contains(section, "sonnet (2 IDs)") || !strings.Contains(section, "$     3.00") || strings.Contains(section, "202509") {
+		t.Errorf("g should group models by family:\n%s", section)
+	}
+	if !m.UIState().StatsModelFamily {
+		t.Error("the family toggle should be remembered")
+	}
+}
+
 func TestRenderTokenBreakdown(t *testing.T) {
 	cfg := config.DefaultConfig()
 	m := NewModel(cfg)
@@ -206,3 +229,213 @@ func TestRenderTopTools_Empty(t *testing.T) {
 		t.Error("empty top tools should show 'No tool data'")
 	}
 }
+
+func TestRenderToolFailures(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg)
+
+	ds := stats.DashboardStats{
+		ToolFailures: []stats.ToolFailureRate{
+			{ToolName: "Bash", Calls: 8, Failures: 6, Rate: 0.75},
+			{ToolName: "Read", Calls: 3, Failures: 0, Rate: 0},
+		},
+	}
+	section := m.renderToolFailures(ds)
+	for _, want := range []string{"Tool Failure Rates", "Bash", "75%  6 of 8 failed", "0%  0 of 3 failed"} {
+		if !strings.Contains(section, want) {
+			t.Errorf("section should contain %q:\n%s", want, section)
+		}
+	}
+
+	if section := m.renderToolFailures(stats.DashboardStats{}); !strings.Contains(section, "No tool data") {
+		t.Error("empty tool failures should show 'No tool data'")
+	}
+}
+
+func TestRenderAccountBreakdown(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg)
+
+	out := m.renderAccountBreakdown(stats.DashboardStats{})
+	if !strings.Contains(out, "No account data") {
+		t.Error("empty account breakdown should show 'No account data'")
+	}
+
+	out = m.renderAccountBreakdown(stats.DashboardStats{
+		AccountBreakdown: []stats.AccountStats{
+			{OrgID: "org-work", UserUUID: "1234567890abcdef", TotalCost: 12.5, SessionCount: 3},
+			{TotalCost: 0.4, SessionCount: 1},
+		},
+	})
+	if !strings.Contains(out, "org-work / 12345678") {
+		t.Errorf("account row should show org and short user id, got:\n%s", out)
+	}
+	if !strings.Contains(out, "$    12.50") {
+		t.Errorf("account row should show cost, got:\n%s", out)
+	}
+	if !strings.Contains(out, "(unknown)") {
+		t.Errorf("unattributed sessions should render as (unknown), got:\n%s", out)
+	}
+}
+
+func TestRenderVersionBreakdown(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+
+	if out := m.renderVersionBreakdown(stats.DashboardStats{}); !strings.Contains(out, "No version data") {
+		t.Error("empty version breakdown should show 'No version data'")
+	}
+
+	out := stripAnsi(m.renderVersionBreakdown(stats.DashboardStats{
+		MinVersion: "2.0.10",
+		VersionBreakdown: []stats.VersionStats{
+			{Version: "2.0.14", SessionCount: 3, Hosts: []string{"laptop-a"}, Users: 2},
+			{Version: "2.0.9", SessionCount: 2, Hosts: []string{"laptop-b", "laptop-c"}, Users: 1, Outdated: true},
+			{SessionCount: 1, Users: 1},
+		},
+	}))
+	for _, want := range []string{"2.0.14", "laptop-b, laptop-c", "(unknown)",
+		"2 session(s) run a version older than 2.0.10 on laptop-b, laptop-c"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("version breakdown missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderAPISection_LatencyOutliers(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	out := m.renderAPISection(stats.DashboardStats{
+		AvgAPILatency:    1.0,
+		LatencyAvgMethod: stats.LatencyAvgTrimmed,
+		LatencyMax:       300,
+		LatencyOutliers:  2,
+	})
+	for _, want := range []string{"(trimmed)", "2 outlier(s)", "Max API latency:  300.0s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("API section should contain %q, got:\n%s", want, out)
+		}
+	}
+
+	out = m.renderAPISection(stats.DashboardStats{AvgAPILatency: 1.0, LatencyAvgMethod: stats.LatencyAvgMean})
+	if strings.Contains(out, "(mean)") || strings.Contains(out, "outlier") {
+		t.Errorf("plain mean without outliers should not be annotated, got:\n%s", out)
+	}
+}
+
+func TestRenderRateLimitSection(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+
+	out := m.renderRateLimitSection(stats.DashboardStats{})
+	if !strings.Contains(out, "No 429 responses") {
+		t.Errorf("expected empty state, got:\n%s", out)
+	}
+
+	out = m.renderRateLimitSection(stats.DashboardStats{RateLimitPacing: stats.RateLimitPacing{
+		RateLimited:  3,
+		LastAt:       time.Now().Add(-2 * time.Minute),
+		ObservedRPM:  12.5,
+		SuggestedRPM: 9,
+	}})
+	for _, want := range []string{"429 responses:    3 (last 2m", "12.5 req/min", "<= 9.0 req/min"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rate limit section should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderTierSection(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+
+	out := m.renderTierSection(stats.DashboardStats{TierBreakdown: []stats.TierStats{
+		{Tier: "standard", Requests: 4, CostUSD: 2, StandardCostUSD: 2},
+	}})
+	if !strings.Contains(out, "All requests on the standard tier") {
+		t.Errorf("expected standard-only note, got:\n%s", out)
+	}
+
+	out = stripAnsi(m.renderTierSection(stats.DashboardStats{
+		TierBreakdown: []stats.TierStats{
+			{Tier: "batch", Requests: 10, CostUSD: 3, StandardCostUSD: 6},
+			{Tier: "standard", Requests: 4, CostUSD: 2, StandardCostUSD: 2},
+		},
+		TierSavingsUSD: 3,
+	}))
+	for _, want := range []string{"batch", "$     3.00", "$       6.00", "Saved vs standard: $3.00"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("tier section should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderTurnSection(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	if got := m.renderTurnSection(stats.DashboardStats{}); !strings.Contains(got, "No turns") {
+		t.Errorf("empty breakdown should say so, got %q", got)
+	}
+
+	got := m.renderTurnSection(stats.DashboardStats{TurnBreakdown: stats.TurnBreakdown{
+		Turns:    4,
+		API:      30 * time.Minute,
+		Tool:     20 * time.Minute,
+		Other:    10 * time.Minute,
+		UserWait: 60 * time.Minute,
+	}})
+	for _, want := range []string{"Turns:       4", "(avg 30m0s)", " 25%  30m0s", " 50%  1h0m"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("turn section should contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderTeamBreakdown(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+
+	out := m.renderTeamBreakdown(stats.DashboardStats{HostBreakdown: []stats.TeamStats{{SessionCount: 2}}})
+	if !strings.Contains(out, "No host or user data") {
+		t.Errorf("sessions without host or user should show the empty state, got:\n%s", out)
+	}
+
+	out = stripAnsi(m.renderTeamBreakdown(stats.DashboardStats{
+		HostBreakdown: []stats.TeamStats{
+			{Name: "build-box", TotalCost: 5, SessionCount: 3, Active: 1},
+			{TotalCost: 0.5, SessionCount: 1},
+		},
+		UserBreakdown: []stats.TeamStats{{Name: "alice", TotalCost: 5.5, SessionCount: 4, Active: 1}},
+	}))
+	for _, want := range []string{"build-box", "$     5.00", "(unknown)", "User", "alice"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("team breakdown should contain %q, got:\n%s", want, out)
+		}
+	}
+
+	out = m.renderTeamBreakdown(stats.DashboardStats{HostBreakdown: []stats.TeamStats{{Name: "laptop", SessionCount: 1}}})
+	if strings.Contains(out, "User") {
+		t.Errorf("users table should be omitted without pushed users, got:\n%s", out)
+	}
+}
+
+type mockRecordsProvider struct {
+	records []records.Record
+}
+
+func (m *mockRecordsProvider) Records(time.Time) []records.Record { return m.records }
+
+func TestRenderRecordsSection(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg, WithStartView(ViewStats))
+	if out := m.renderRecordsSection(); !strings.Contains(out, "No records yet") {
+		t.Errorf("without records:\n%s", out)
+	}
+
+	m = NewModel(cfg, WithStartView(ViewStats), WithRecordsProvider(&mockRecordsProvider{records: []records.Record{
+		{Name: records.CostliestDay, Value: 42.1, Detail: "2026-03-12"},
+		{Name: records.LongestSession, Value: 5400, SessionID: "abcdef123456"},
+		{Name: records.BiggestRequest, Value: 1.25, SessionID: "abcdef123456", Detail: "claude-opus-4"},
+	}}))
+	out := m.renderRecordsSection()
+	for _, want := range []string{"Records (all time)", "Most expensive day:", "$42.10", "2026-03-12",
+		"Longest session:", "1h30m", "abcdef12", "Biggest API request:", "$1.25", "claude-opus-4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("records section should contain %q:\n%s", want, out)
+		}
+	}
+}
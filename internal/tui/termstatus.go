@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// progressClear removes the OSC 9;4 taskbar progress indicator.
+const progressClear = "\x1b]9;4;0\x07"
+
+// WithTerminalOutput lets the model write OSC 9;4 taskbar progress
+// sequences to w, the terminal the program renders to, when
+// display.taskbar_progress is on.
+func WithTerminalOutput(w io.Writer) ModelOption {
+	return func(m *Model) { m.termOut = w }
+}
+
+// terminalTitle is the window title: global spend and hourly rate.
+func (m Model) terminalTitle() string {
+	br := m.globalBurnRate()
+	return fmt.Sprintf("cc-top · $%.2f · $%.2f/hr", br.TotalCost, br.HourlyRate)
+}
+
+// taskbarProgress returns the OSC 9;4 sequence showing the most used
+// budget: normal below 80%, warning from 80% and error once exhausted. It
+// returns "" without budgets.
+func (m Model) taskbarProgress(now time.Time) string {
+	if m.budgets == nil {
+		return ""
+	}
+	statuses := m.budgets.Status(now)
+	if len(statuses) == 0 {
+		return ""
+	}
+	var pct float64
+	for _, s := range statuses {
+		if p := s.Percent(); p > pct {
+			pct = p
+		}
+	}
+	state := 1
+	switch {
+	case pct >= 100:
+		state, pct = 2, 100
+	case pct >= 80:
+		state = 4
+	}
+	return fmt.Sprintf("\x1b]9;4;%d;%d\x07", state, int(pct))
+}
+
+// updateTerminalStatus returns the commands that bring the window title and
+// taskbar progress up to date, or nil when neither changed.
+func (m *Model) updateTerminalStatus(now time.Time) tea.Cmd {
+	var cmds []tea.Cmd
+
+	title := ""
+	if m.cfg.Display.TerminalTitle {
+		title = m.terminalTitle()
+	}
+	if title != m.termTitle {
+		m.termTitle = title
+		cmds = append(cmds, tea.SetWindowTitle(title))
+	}
+
+	progress := ""
+	if m.cfg.Display.TaskbarProgress && m.termOut != nil {
+		progress = m.taskbarProgress(now)
+	}
+	if progress != m.termProgress {
+		seq := progress
+		if seq == "" {
+			seq = progressClear
+		}
+		m.termProgress = progress
+		w := m.termOut
+		cmds = append(cmds, func() tea.Msg {
+			_, _ = io.WriteString(w, seq)
+			return nil
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// ClearTerminalStatus resets the window title and removes the taskbar
+// progress the model set, for use once the program has exited.
+func (m Model) ClearTerminalStatus(w io.Writer) {
+	if m.termTitle != "" {
+		_, _ = io.WriteString(w, "\x1b]2;\x07")
+	}
+	if m.termProgress != "" {
+		_, _ = io.WriteString(w, progressClear)
+	}
+}
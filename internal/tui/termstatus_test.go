@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nixlim/cc-top/internal/budget"
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestTaskbarProgress(t *testing.T) {
+	budgets := &mockBudgetProvider{}
+	m := NewModel(config.DefaultConfig(), WithBudgetProvider(budgets))
+	if got := m.taskbarProgress(time.Now()); got != "" {
+		t.Errorf("no budgets should show no progress, got %q", got)
+	}
+
+	tests := []struct {
+		spent float64
+		want  string
+	}{
+		{40, "\x1b]9;4;1;40\x07"},
+		{85, "\x1b]9;4;4;85\x07"},
+		{130, "\x1b]9;4;2;100\x07"},
+	}
+	for _, tt := range tests {
+		budgets.statuses = []budget.Status{
+			{Period: budget.Weekly, Limit: 100, Spent: tt.spent},
+			{Period: budget.Monthly, Limit: 400, Spent: 100},
+		}
+		if got := m.taskbarProgress(time.Now()); got != tt.want {
+			t.Errorf("spent %v: progress %q, want %q", tt.spent, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateTerminalStatus(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.TaskbarProgress = true
+	var out bytes.Buffer
+	budgets := &mockBudgetProvider{statuses: []budget.Status{{Period: budget.Weekly, Limit: 100, Spent: 50}}}
+	m := NewModel(cfg, WithBudgetProvider(budgets), WithTerminalOutput(&out))
+	m.cachedBurnRate = burnrate.BurnRate{TotalCost: 12.5, HourlyRate: 3.2}
+
+	cmd := m.updateTerminalStatus(time.Now())
+	if cmd == nil {
+		t.Fatal("the first update should set the title and progress")
+	}
+	if m.termTitle != "cc-top · $12.50 · $3.20/hr" {
+		t.Errorf("title = %q", m.termTitle)
+	}
+	runCmd(cmd)
+	if got := out.String(); got != "\x1b]9;4;1;50\x07" {
+		t.Errorf("progress written = %q", got)
+	}
+
+	if cmd := m.updateTerminalStatus(time.Now()); cmd != nil {
+		t.Error("nothing changed, so nothing should be sent")
+	}
+
+	out.Reset()
+	m.cfg.Display.TerminalTitle, m.cfg.Display.TaskbarProgress = false, false
+	runCmd(m.updateTerminalStatus(time.Now()))
+	if m.termTitle != "" || out.String() != progressClear {
+		t.Errorf("turning both off should reset the title and clear progress, got title %q, wrote %q", m.termTitle, out.String())
+	}
+
+	out.Reset()
+	m.ClearTerminalStatus(&out)
+	if out.Len() != 0 {
+		t.Errorf("nothing is set, so nothing should be cleared, wrote %q", out.String())
+	}
+}
+
+// runCmd runs cmd and any commands it batches, discarding their messages.
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(c)
+		}
+	}
+}
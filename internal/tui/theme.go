@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// palette holds the colors of a theme, one per role in
+// config.ThemeColorRoles.
+type palette struct {
+	Accent     lipgloss.Color // panel titles and overlay borders
+	HeaderFg   lipgloss.Color // header bar and selected rows
+	HeaderBg   lipgloss.Color
+	Border     lipgloss.Color
+	Focus      lipgloss.Color // focused panel and menu borders
+	Dim        lipgloss.Color
+	Muted      lipgloss.Color // done sessions and the status bar
+	Good       lipgloss.Color
+	Warning    lipgloss.Color
+	Critical   lipgloss.Color
+	Prompt     lipgloss.Color // event stream types
+	Tool       lipgloss.Color
+	API        lipgloss.Color
+	Decision   lipgloss.Color
+	Annotation lipgloss.Color
+	AlertBadge lipgloss.Color
+}
+
+// roles maps the config role names to the palette fields.
+func (p *palette) roles() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"accent": &p.Accent, "header_fg": &p.HeaderFg, "header_bg": &p.HeaderBg,
+		"border": &p.Border, "focus": &p.Focus, "dim": &p.Dim, "muted": &p.Muted,
+		"good": &p.Good, "warning": &p.Warning, "critical": &p.Critical,
+		"prompt": &p.Prompt, "tool": &p.Tool, "api": &p.API, "decision": &p.Decision,
+		"annotation": &p.Annotation, "alert_badge": &p.AlertBadge,
+	}
+}
+
+// themes is the registry of built-in themes, keyed by config.ThemeNames.
+var themes = map[string]palette{
+	"dark": {
+		Accent: "69", HeaderFg: "15", HeaderBg: "62", Border: "240", Focus: "63",
+		Dim: "240", Muted: "245", Good: "82", Warning: "226", Critical: "196",
+		Prompt: "117", Tool: "222", API: "114", Decision: "183", Annotation: "81", AlertBadge: "214",
+	},
+	"light": {
+		Accent: "25", HeaderFg: "15", HeaderBg: "25", Border: "246", Focus: "33",
+		Dim: "243", Muted: "240", Good: "28", Warning: "130", Critical: "160",
+		Prompt: "25", Tool: "94", API: "28", Decision: "90", Annotation: "30", AlertBadge: "214",
+	},
+	"solarized": {
+		Accent: "#268bd2", HeaderFg: "#fdf6e3", HeaderBg: "#268bd2", Border: "#586e75", Focus: "#2aa198",
+		Dim: "#586e75", Muted: "#93a1a1", Good: "#859900", Warning: "#b58900", Critical: "#dc322f",
+		Prompt: "#268bd2", Tool: "#cb4b16", API: "#859900", Decision: "#6c71c4", Annotation: "#2aa198", AlertBadge: "#b58900",
+	},
+	"high-contrast": {
+		Accent: "14", HeaderFg: "0", HeaderBg: "11", Border: "15", Focus: "11",
+		Dim: "250", Muted: "252", Good: "10", Warning: "11", Critical: "9",
+		Prompt: "14", Tool: "11", API: "10", Decision: "13", Annotation: "14", AlertBadge: "11",
+	},
+}
+
+// currentPalette is the palette the package styles were last built from.
+var currentPalette palette
+
+func init() {
+	applyPalette(themes["dark"])
+}
+
+// resolveTheme returns the palette of the named theme with the config's
+// color overrides applied. Unknown names fall back to dark.
+func resolveTheme(cfg config.ThemeConfig) palette {
+	p, ok := themes[cfg.Name]
+	if !ok {
+		p = themes["dark"]
+	}
+	roles := p.roles()
+	for role, c := range cfg.Colors {
+		if field, ok := roles[role]; ok {
+			*field = lipgloss.Color(c)
+		}
+	}
+	return p
+}
+
+// applyTheme rebuilds the package styles from the configured theme.
+func applyTheme(cfg config.ThemeConfig) {
+	applyPalette(resolveTheme(cfg))
+}
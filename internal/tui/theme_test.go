@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestThemes_MatchConfig(t *testing.T) {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := slices.Clone(config.ThemeNames)
+	sort.Strings(want)
+	if !slices.Equal(names, want) {
+		t.Errorf("theme registry %v does not match config.ThemeNames %v", names, want)
+	}
+
+	for name, p := range themes {
+		roles := p.roles()
+		if len(roles) != len(config.ThemeColorRoles) {
+			t.Errorf("palette has %d roles, config lists %d", len(roles), len(config.ThemeColorRoles))
+		}
+		for _, role := range config.ThemeColorRoles {
+			field, ok := roles[role]
+			if !ok {
+				t.Errorf("role %q missing from palette", role)
+			} else if *field == "" {
+				t.Errorf("theme %s: role %q has no color", name, role)
+			}
+		}
+	}
+}
+
+func TestApplyTheme_Overrides(t *testing.T) {
+	defer applyTheme(config.ThemeConfig{Name: "dark"})
+
+	applyTheme(config.ThemeConfig{Name: "light", Colors: map[string]string{"accent": "#ff0000"}})
+	if currentPalette.Accent != lipgloss.Color("#ff0000") {
+		t.Errorf("accent override not applied: %q", currentPalette.Accent)
+	}
+	if currentPalette.Good != themes["light"].Good {
+		t.Errorf("other roles should come from the base theme, got %q", currentPalette.Good)
+	}
+	if panelTitleStyle.GetForeground() != lipgloss.Color("#ff0000") {
+		t.Error("styles should be rebuilt from the palette")
+	}
+	if themes["light"].Accent == "#ff0000" {
+		t.Error("overrides must not modify the registry")
+	}
+
+	applyTheme(config.ThemeConfig{Name: "unknown"})
+	if currentPalette != themes["dark"] {
+		t.Error("an unknown theme should fall back to dark")
+	}
+}
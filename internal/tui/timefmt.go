@@ -0,0 +1,45 @@
+package tui
+
+import "time"
+
+// clockLayout returns the time-of-day layout for the configured
+// display.time_format. When seconds is false the seconds field is dropped
+// regardless of the setting; it is used for minute-resolution rows.
+func (m Model) clockLayout(seconds bool) string {
+	switch m.cfg.Display.TimeFormat {
+	case "12h":
+		return "3:04 PM"
+	case "12h_seconds":
+		if seconds {
+			return "3:04:05 PM"
+		}
+		return "3:04 PM"
+	case "24h":
+		return "15:04"
+	}
+	if seconds {
+		return "15:04:05"
+	}
+	return "15:04"
+}
+
+// formatClock formats the local time of day of t, with seconds when the
+// configured format includes them.
+func (m Model) formatClock(t time.Time) string {
+	return t.Local().Format(m.clockLayout(true))
+}
+
+// formatClockMinutes formats the local time of day of t without seconds.
+func (m Model) formatClockMinutes(t time.Time) string {
+	return t.Local().Format(m.clockLayout(false))
+}
+
+// formatDateTime formats t as a local date and time of day.
+func (m Model) formatDateTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 " + m.clockLayout(true))
+}
+
+// dateTimeWidth is the column width of formatDateTime output.
+func (m Model) dateTimeWidth() int {
+	return len("2006-01-02 " + m.clockLayout(true))
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestTimeFormat_Layouts(t *testing.T) {
+	ts := time.Date(2026, 2, 20, 14, 5, 9, 0, time.Local)
+
+	tests := []struct {
+		format   string
+		clock    string
+		minutes  string
+		dateTime string
+	}{
+		{"24h_seconds", "14:05:09", "14:05", "2026-02-20 14:05:09"},
+		{"24h", "14:05", "14:05", "2026-02-20 14:05"},
+		{"12h_seconds", "2:05:09 PM", "2:05 PM", "2026-02-20 2:05:09 PM"},
+		{"12h", "2:05 PM", "2:05 PM", "2026-02-20 2:05 PM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Display.TimeFormat = tt.format
+			m := NewModel(cfg)
+
+			if got := m.formatClock(ts); got != tt.clock {
+				t.Errorf("formatClock = %q, want %q", got, tt.clock)
+			}
+			if got := m.formatClockMinutes(ts); got != tt.minutes {
+				t.Errorf("formatClockMinutes = %q, want %q", got, tt.minutes)
+			}
+			if got := m.formatDateTime(ts); got != tt.dateTime {
+				t.Errorf("formatDateTime = %q, want %q", got, tt.dateTime)
+			}
+			if m.dateTimeWidth() < len(tt.dateTime) {
+				t.Errorf("dateTimeWidth %d narrower than %q", m.dateTimeWidth(), tt.dateTime)
+			}
+		})
+	}
+}
+
+func TestTimeFormat_AlertDetail(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.TimeFormat = "12h"
+	m := NewModel(cfg)
+
+	out := m.formatAlertDetail(alerts.Alert{
+		Rule:     "CostSurge",
+		Severity: "warning",
+		FiredAt:  time.Date(2026, 2, 20, 9, 30, 0, 0, time.Local),
+	})
+	if !strings.Contains(out, "Fired at:  2026-02-20 9:30 AM") {
+		t.Errorf("alert detail should use the configured time format, got:\n%s", out)
+	}
+}
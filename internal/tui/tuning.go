@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// tuneHistory is how far back the threshold tuning overlay replays.
+const tuneHistory = 7 * 24 * time.Hour
+
+// ThresholdTuner replays alert history against candidate thresholds.
+// SQLiteStore implements this interface.
+type ThresholdTuner interface {
+	// ThresholdFirings returns how many times rule would have fired since
+	// since with cfg; ok is false when the rule cannot be replayed.
+	ThresholdFirings(rule string, cfg config.AlertsConfig, since time.Time) (n int, ok bool)
+}
+
+// WithThresholdTuner enables the threshold tuning overlay (t in the Alerts
+// panel).
+func WithThresholdTuner(t ThresholdTuner) ModelOption {
+	return func(m *Model) { m.tuner = t }
+}
+
+// tunableRule is a threshold of the tuning overlay.
+type tunableRule struct {
+	rule string
+	key  string // config key under [alerts]
+	step float64
+	// integer marks config keys that take whole numbers.
+	integer bool
+	format  func(float64) string
+	get     func(*config.AlertsConfig) float64
+	set     func(*config.AlertsConfig, float64)
+}
+
+var tunableRules = []tunableRule{
+	{
+		rule: alerts.RuleCostSurge, key: "cost_surge_threshold_per_hour", step: 0.5,
+		format: func(v float64) string { return fmt.Sprintf("$%.2f/hr", v) },
+		get:    func(c *config.AlertsConfig) float64 { return c.CostSurgeThresholdPerHour },
+		set:    func(c *config.AlertsConfig, v float64) { c.CostSurgeThresholdPerHour = v },
+	},
+	{
+		rule: alerts.RuleRunawayTokens, key: "runaway_token_velocity", step: 5000, integer: true,
+		format: func(v float64) string { return formatNumber(int64(v)) + " tok/min" },
+		get:    func(c *config.AlertsConfig) float64 { return float64(c.RunawayTokenVelocity) },
+		set:    func(c *config.AlertsConfig, v float64) { c.RunawayTokenVelocity = int(v) },
+	},
+	{
+		rule: alerts.RuleSessionCost, key: "session_cost_threshold", step: 1,
+		format: func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+		get:    func(c *config.AlertsConfig) float64 { return c.SessionCostThreshold },
+		set:    func(c *config.AlertsConfig, v float64) { c.SessionCostThreshold = v },
+	},
+}
+
+// tuneState is the open threshold tuning overlay. Candidate holds the
+// adjusted thresholds; fired and firedNow are the replayed firing counts at
+// the candidate and the configured values, -1 when unavailable.
+type tuneState struct {
+	cursor    int
+	candidate config.AlertsConfig
+	fired     []int
+	firedNow  []int
+}
+
+// openTuning opens the tuning overlay at the configured thresholds.
+func (m *Model) openTuning(now time.Time) {
+	t := &tuneState{
+		candidate: m.cfg.Alerts,
+		fired:     make([]int, len(tunableRules)),
+		firedNow:  make([]int, len(tunableRules)),
+	}
+	for i, r := range tunableRules {
+		t.firedNow[i] = m.replayFirings(r.rule, m.cfg.Alerts, now)
+		t.fired[i] = t.firedNow[i]
+	}
+	m.tune = t
+}
+
+func (m Model) replayFirings(rule string, cfg config.AlertsConfig, now time.Time) int {
+	n, ok := m.tuner.ThresholdFirings(rule, cfg, now.Add(-tuneHistory))
+	if !ok {
+		return -1
+	}
+	return n
+}
+
+func (m Model) handleTuningKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := *m.tune
+	t.fired = append([]int(nil), t.fired...)
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.TuneThresholds):
+		m.tune = nil
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		t.cursor = max(t.cursor-1, 0)
+
+	case key.Matches(msg, m.keys.Down):
+		t.cursor = min(t.cursor+1, len(tunableRules)-1)
+
+	case key.Matches(msg, m.keys.TuneLower), key.Matches(msg, m.keys.TuneRaise):
+		r := tunableRules[t.cursor]
+		step := r.step
+		if key.Matches(msg, m.keys.TuneLower) {
+			step = -step
+		}
+		r.set(&t.candidate, max(r.get(&t.candidate)+step, r.step))
+		t.fired[t.cursor] = m.replayFirings(r.rule, t.candidate, time.Now())
+	}
+	m.tune = &t
+	return m, nil
+}
+
+// overlayTuning renders the thresholds with how often each would have fired
+// over the last week at the candidate and the configured value.
+func (m Model) overlayTuning(base string) string {
+	t := m.tune
+	var sb strings.Builder
+	sb.WriteString(panelTitleStyle.Render("Tune Alert Thresholds"))
+	sb.WriteString(dimStyle.Render(" (replayed over the last 7 days)"))
+	sb.WriteString("\n\n")
+	for i, r := range tunableRules {
+		current, candidate := r.get(&m.cfg.Alerts), r.get(&t.candidate)
+		line := fmt.Sprintf("%-14s %16s  %s", r.rule, r.format(candidate), formatFirings(t.fired[i]))
+		if candidate != current {
+			line += dimStyle.Render(fmt.Sprintf("  (now %s: %s)", r.format(current), formatFirings(t.firedNow[i])))
+		}
+		if i == t.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	r := tunableRules[t.cursor]
+	if v := r.get(&t.candidate); v != r.get(&m.cfg.Alerts) {
+		sb.WriteString("\n" + dimStyle.Render(fmt.Sprintf("To keep it, set %s = %s under [alerts].", r.key, formatConfigValue(v, r.integer))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n" + dimStyle.Render("←/→: Adjust  ↑/↓: Select  Esc: Close"))
+
+	dialog := filterMenuStyle.Render(sb.String())
+	return placeOverlay(0, 0, dialog, base)
+}
+
+func formatFirings(n int) string {
+	switch n {
+	case -1:
+		return "no history"
+	case 1:
+		return "would fire 1 time"
+	}
+	return fmt.Sprintf("would fire %d times", n)
+}
+
+// formatConfigValue formats v as a TOML integer or float.
+func formatConfigValue(v float64, integer bool) string {
+	if integer {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+// mockTuner fires CostSurge once per dollar below $10/hr and cannot replay
+// any other rule.
+type mockTuner struct{ since time.Time }
+
+func (m *mockTuner) ThresholdFirings(rule string, cfg config.AlertsConfig, since time.Time) (int, bool) {
+	m.since = since
+	if rule != alerts.RuleCostSurge {
+		return 0, false
+	}
+	return max(int(10-cfg.CostSurgeThresholdPerHour), 0), true
+}
+
+func TestTuningOverlay(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Alerts.CostSurgeThresholdPerHour = 5
+	tuner := &mockTuner{}
+	m := NewModel(cfg, WithStateProvider(&mockStateProvider{}), WithStartView(ViewDashboard), WithThresholdTuner(tuner))
+	m.width, m.height = 120, 50
+
+	m = typeKeys(t, m, runes("a"), runes("t"))
+	if m.tune == nil {
+		t.Fatal("t in the Alerts panel should open threshold tuning")
+	}
+	if d := time.Since(tuner.since); d < tuneHistory || d > tuneHistory+time.Minute {
+		t.Errorf("replayed since %v ago, want %v", d, tuneHistory)
+	}
+	got := stripAnsi(m.View())
+	for _, want := range []string{"Tune Alert Thresholds", "$5.00/hr", "would fire 5 times", "no history"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("overlay should contain %q", want)
+		}
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyRight})
+	if m.tune.candidate.CostSurgeThresholdPerHour != 6 || m.cfg.Alerts.CostSurgeThresholdPerHour != 5 {
+		t.Fatalf("candidate = %v, config = %v; only the candidate should change",
+			m.tune.candidate.CostSurgeThresholdPerHour, m.cfg.Alerts.CostSurgeThresholdPerHour)
+	}
+	got = stripAnsi(m.View())
+	for _, want := range []string{"$6.00/hr", "would fire 4 times", "(now $5.00/hr: would fire 5 times)",
+		"set cost_surge_threshold_per_hour = 6.00 under [alerts]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("overlay should contain %q", want)
+		}
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyDown}, runes("-"))
+	if v := m.tune.candidate.RunawayTokenVelocity; v != cfg.Alerts.RunawayTokenVelocity-5000 {
+		t.Errorf("runaway velocity = %d, want one step lower", v)
+	}
+	if got := stripAnsi(m.View()); !strings.Contains(got, "runaway_token_velocity = ") {
+		t.Error("the hint should follow the selected rule")
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.tune != nil {
+		t.Error("Esc should close threshold tuning")
+	}
+}
+
+func TestTuningNeedsTuner(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStateProvider(&mockStateProvider{}), WithStartView(ViewDashboard))
+	m = typeKeys(t, m, runes("a"), runes("t"))
+	if m.tune != nil {
+		t.Error("threshold tuning needs a tuner")
+	}
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// UIState is the part of the TUI state remembered across restarts.
+type UIState struct {
+	View               string          `json:"view,omitempty"` // startup, dashboard, stats, history
+	SelectedSession    string          `json:"selected_session,omitempty"`
+	EventFilters       map[string]bool `json:"event_filters,omitempty"` // filter menu option key -> enabled
+	HistorySection     int             `json:"history_section"`
+	HistoryGranularity string          `json:"history_granularity,omitempty"`
+	HistoryAlertFilter string          `json:"history_alert_filter,omitempty"`
+	StatsModelFamily   bool            `json:"stats_model_family,omitempty"`
+	SessionSort        string          `json:"session_sort,omitempty"` // key of the session sort menu
+	SessionSortAsc     bool            `json:"session_sort_asc,omitempty"`
+}
+
+// LoadUIState reads a state file written by SaveUIState. A missing file
+// yields the zero state without error.
+func LoadUIState(path string) (UIState, error) {
+	var st UIState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return UIState{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return st, nil
+}
+
+// SaveUIState writes st to path, replacing the file atomically.
+func SaveUIState(path string, st UIState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// WithUIState restores a remembered UI state. The selected session is
+// re-selected once it shows up in the session list.
+func WithUIState(st UIState) ModelOption {
+	return func(m *Model) { m.restoreUIState(st) }
+}
+
+// UIState returns the state to remember for the next start.
+func (m Model) UIState() UIState {
+	st := UIState{
+		View:               viewName(m.view),
+		SelectedSession:    m.selectedSession,
+		EventFilters:       make(map[string]bool, len(m.filterMenu.Options)),
+		HistorySection:     m.historySection,
+		HistoryGranularity: m.historyGranularity,
+		HistoryAlertFilter: m.historyAlertFilter,
+		StatsModelFamily:   m.statsModelFamily,
+		SessionSort:        m.sessionSort,
+		SessionSortAsc:     m.sessionSortAsc,
+	}
+	if st.SelectedSession == "" {
+		st.SelectedSession = m.pendingSelection
+	}
+	for _, opt := range m.filterMenu.Options {
+		st.EventFilters[opt.Key] = opt.Enabled
+	}
+	return st
+}
+
+func (m *Model) restoreUIState(st UIState) {
+	for _, v := range []ViewState{ViewStartup, ViewDashboard, ViewStats, ViewHistory, ViewProjects} {
+		if st.View == viewName(v) {
+			m.view = v
+		}
+	}
+	for i, opt := range m.filterMenu.Options {
+		if enabled, ok := st.EventFilters[opt.Key]; ok {
+			m.filterMenu.Options[i].Enabled = enabled
+		}
+	}
+	m.applyFilter()
+	if st.HistorySection >= 0 && st.HistorySection <= 4 {
+		m.historySection = st.HistorySection
+	}
+	switch st.HistoryGranularity {
+	case "daily", "weekly", "monthly":
+		m.historyGranularity = st.HistoryGranularity
+	}
+	m.historyAlertFilter = st.HistoryAlertFilter
+	m.statsModelFamily = st.StatsModelFamily
+	for _, k := range sessionSortKeys {
+		if st.SessionSort == k.key {
+			m.sessionSort = k.key
+			m.sessionSortAsc = st.SessionSortAsc
+		}
+	}
+	m.pendingSelection = st.SelectedSession
+}
+
+// restoreSelection selects the remembered session once it is listed.
+func (m *Model) restoreSelection() {
+	if m.pendingSelection == "" {
+		return
+	}
+	for i, s := range m.getSessions() {
+		if s.SessionID == m.pendingSelection {
+			m.selectedSession = s.SessionID
+			m.eventFilter.SessionID = s.SessionID
+			m.sessionCursor = i
+			m.pendingSelection = ""
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+func TestUIState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+
+	st, err := LoadUIState(path)
+	if err != nil {
+		t.Fatalf("missing file should not be an error: %v", err)
+	}
+	if st.View != "" || st.SelectedSession != "" {
+		t.Errorf("missing file should give the zero state, got %+v", st)
+	}
+
+	m := NewModel(config.DefaultConfig())
+	m.view = ViewHistory
+	m.historySection = 2
+	m.historyGranularity = "weekly"
+	m.historyAlertFilter = "CostSurge"
+	m.selectedSession = "sess-42"
+	m.filterMenu.Options[0].Enabled = false
+	m.applyFilter()
+
+	if err := SaveUIState(path, m.UIState()); err != nil {
+		t.Fatalf("SaveUIState: %v", err)
+	}
+	st, err = LoadUIState(path)
+	if err != nil {
+		t.Fatalf("LoadUIState: %v", err)
+	}
+
+	sessions := &mockStateProvider{}
+	r := NewModel(config.DefaultConfig(), WithStartView(ViewStartup), WithStateProvider(sessions), WithUIState(st))
+	if r.view != ViewHistory || r.historySection != 2 || r.historyGranularity != "weekly" || r.historyAlertFilter != "CostSurge" {
+		t.Errorf("view state not restored: view=%v section=%d granularity=%q filter=%q",
+			r.view, r.historySection, r.historyGranularity, r.historyAlertFilter)
+	}
+	if r.eventFilter.EventTypes[r.filterMenu.Options[0].Key] {
+		t.Error("disabled event filter not restored")
+	}
+
+	// The session is selected once it is listed.
+	r.restoreSelection()
+	if r.selectedSession != "" {
+		t.Errorf("selection should wait for the session, got %q", r.selectedSession)
+	}
+	if got := r.UIState().SelectedSession; got != "sess-42" {
+		t.Errorf("pending selection should still be remembered, got %q", got)
+	}
+	sessions.sessions = []state.SessionData{
+		{SessionID: "sess-1", StartedAt: time.Now()},
+		{SessionID: "sess-42", StartedAt: time.Now()},
+	}
+	r.restoreSelection()
+	if r.selectedSession != "sess-42" || r.eventFilter.SessionID != "sess-42" || r.sessionCursor != 1 {
+		t.Errorf("selection not restored: selected=%q filter=%q cursor=%d", r.selectedSession, r.eventFilter.SessionID, r.sessionCursor)
+	}
+}
+
+func TestUIState_IgnoresInvalidValues(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard), WithUIState(UIState{
+		View:               "bogus",
+		HistorySection:     9,
+		HistoryGranularity: "hourly",
+	}))
+	if m.view != ViewDashboard || m.historySection != 0 || m.historyGranularity != "daily" {
+		t.Errorf("invalid values should keep defaults: view=%v section=%d granularity=%q", m.view, m.historySection, m.historyGranularity)
+	}
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// renderSpan is the time spent rendering one panel within a frame.
+type renderSpan struct {
+	name    string
+	elapsed time.Duration
+}
+
+// RenderWatchdog times View and Update calls and writes a line to its log
+// whenever one exceeds the threshold. Slow View lines include per-panel
+// timings so the expensive panel can be identified. A nil watchdog is a
+// no-op, so call sites need no guards.
+type RenderWatchdog struct {
+	threshold time.Duration
+	out       io.Writer
+
+	mu    sync.Mutex
+	spans []renderSpan // panels rendered in the current frame
+	slow  int
+}
+
+// NewRenderWatchdog creates a watchdog that logs calls slower than
+// threshold to out.
+func NewRenderWatchdog(threshold time.Duration, out io.Writer) *RenderWatchdog {
+	return &RenderWatchdog{threshold: threshold, out: out}
+}
+
+// span records the time spent rendering a panel since start.
+func (w *RenderWatchdog) span(name string, start time.Time) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.spans = append(w.spans, renderSpan{name: name, elapsed: time.Since(start)})
+	w.mu.Unlock()
+}
+
+// timed renders a panel and records its duration.
+func (w *RenderWatchdog) timed(name string, render func() string) string {
+	if w == nil {
+		return render()
+	}
+	start := time.Now()
+	out := render()
+	w.span(name, start)
+	return out
+}
+
+// done reports the call op (e.g. "View dashboard") that began at start,
+// logging it if it ran over the threshold. Panel spans collected since the
+// previous call are attached and then cleared.
+func (w *RenderWatchdog) done(op string, start time.Time) {
+	if w == nil {
+		return
+	}
+	elapsed := time.Since(start)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	spans := w.spans
+	w.spans = nil
+	if elapsed < w.threshold {
+		return
+	}
+	w.slow++
+
+	line := fmt.Sprintf("%s slow: %s took %s", start.Format(time.RFC3339), op, elapsed.Round(time.Microsecond))
+	if len(spans) > 0 {
+		parts := make([]string, len(spans))
+		for i, s := range spans {
+			parts[i] = fmt.Sprintf("%s=%s", s.name, s.elapsed.Round(time.Microsecond))
+		}
+		line += " (" + strings.Join(parts, " ") + ")"
+	}
+	_, _ = fmt.Fprintln(w.out, line)
+}
+
+// SlowCount returns the number of calls logged as slow.
+func (w *RenderWatchdog) SlowCount() int {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.slow
+}
+
+// viewName returns a short label for v used in watchdog log lines.
+func viewName(v ViewState) string {
+	switch v {
+	case ViewStartup:
+		return "startup"
+	case ViewDashboard:
+		return "dashboard"
+	case ViewStats:
+		return "stats"
+	case ViewHistory:
+		return "history"
+	case ViewProjects:
+		return "projects"
+	}
+	return "unknown"
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nixlim/cc-top/internal/config"
+)
+
+func TestRenderWatchdog_LogsSlowCallsWithSpans(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewRenderWatchdog(time.Millisecond, &buf)
+
+	w.timed("sessions", func() string { time.Sleep(2 * time.Millisecond); return "" })
+	w.timed("events", func() string { return "" })
+	w.done("View dashboard", time.Now().Add(-5*time.Millisecond))
+
+	out := buf.String()
+	for _, want := range []string{"View dashboard took", "sessions=", "events="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log should contain %q, got %q", want, out)
+		}
+	}
+	if w.SlowCount() != 1 {
+		t.Errorf("SlowCount() = %d, want 1", w.SlowCount())
+	}
+
+	// Fast calls are not logged, and spans don't leak into the next frame.
+	buf.Reset()
+	w.done("View dashboard", time.Now())
+	if buf.Len() != 0 {
+		t.Errorf("fast call should not be logged, got %q", buf.String())
+	}
+	w.done("Update tea.KeyMsg", time.Now().Add(-5*time.Millisecond))
+	if strings.Contains(buf.String(), "sessions=") {
+		t.Errorf("spans from a previous frame should be cleared, got %q", buf.String())
+	}
+}
+
+func TestRenderWatchdog_NilIsNoop(t *testing.T) {
+	var w *RenderWatchdog
+	if got := w.timed("x", func() string { return "ok" }); got != "ok" {
+		t.Errorf("timed() = %q, want ok", got)
+	}
+	w.done("View", time.Now())
+	if w.SlowCount() != 0 {
+		t.Error("nil watchdog should report zero slow calls")
+	}
+}
+
+func TestModel_WatchdogInstrumentsViewAndUpdate(t *testing.T) {
+	var buf bytes.Buffer
+	// A zero threshold logs every call.
+	m := NewModel(config.DefaultConfig(), WithStartView(ViewDashboard), WithRenderWatchdog(NewRenderWatchdog(0, &buf)))
+	m.width = 120
+	m.height = 40
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_ = result.(Model).View()
+
+	out := buf.String()
+	for _, want := range []string{"Update tea.KeyMsg", "View dashboard", "sessions=", "burnrate=", "alerts="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log should contain %q, got:\n%s", want, out)
+		}
+	}
+}
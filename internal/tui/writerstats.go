@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// WriterStatsProvider reports the persistent store's write queue and flushes.
+// SQLiteStore implements this interface.
+type WriterStatsProvider interface {
+	WriterStats(now time.Time) state.WriterStats
+}
+
+// WithWriterStats shows the write queue diagnostics in the Stats view and
+// warns in the header when the queue stays nearly full.
+func WithWriterStats(p WriterStatsProvider) ModelOption {
+	return func(m *Model) { m.writerStats = p }
+}
+
+// renderWriterSection renders the write queue depth, batch sizes and flush
+// latencies for the Stats view.
+func (m Model) renderWriterSection(now time.Time) string {
+	ws := m.writerStats.WriterStats(now)
+	pct := ws.QueuePercent()
+	queue := fmt.Sprintf("  Queue:          %s %d/%d (%.0f%%)",
+		renderProgressBar(pct/100, 20), ws.QueueDepth, ws.QueueCapacity, pct)
+	if ws.Backlogged(now) {
+		queue += alertWarningStyle.Render(fmt.Sprintf(" above %d%% for %s",
+			state.QueueHighPercent, formatDuration(now.Sub(ws.HighSince))))
+	}
+	lines := []string{panelTitleStyle.Render("Write Queue"), queue}
+	if ws.Flushes == 0 {
+		lines = append(lines, dimStyle.Render("  No flushes yet"))
+	} else {
+		lines = append(lines,
+			fmt.Sprintf("  Batch size:     %d last, %.1f avg, %d max", ws.LastBatch, ws.AvgBatch, ws.MaxBatch),
+			fmt.Sprintf("  Flush latency:  %s last, %s avg, %s max",
+				formatLatency(ws.LastFlush), formatLatency(ws.AvgFlush), formatLatency(ws.MaxFlush)),
+			fmt.Sprintf("  Flushes:        %s", formatNumber(ws.Flushes)))
+	}
+	switch ws.Role {
+	case state.StoreRoleStandby:
+		lines = append(lines, dimStyle.Render("  Shared:         another cc-top instance maintains this database"))
+	case state.StoreRoleReadOnly:
+		lines = append(lines, dimStyle.Render("  Read-only:      another cc-top instance writes this database; telemetry received here is not stored"))
+	}
+	if ws.Dropped > 0 {
+		lines = append(lines, alertWarningStyle.Render(fmt.Sprintf("  Dropped writes: %s", formatNumber(ws.Dropped))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatLatency formats a flush latency with millisecond precision.
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/config"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+type mockWriterStats struct{ ws state.WriterStats }
+
+func (m *mockWriterStats) WriterStats(time.Time) state.WriterStats { return m.ws }
+
+func TestRenderWriterSection(t *testing.T) {
+	now := time.Now()
+	ws := &mockWriterStats{state.WriterStats{QueueDepth: 9, QueueCapacity: 10, HighSince: now.Add(-30 * time.Second)}}
+	m := NewModel(config.DefaultConfig(),
+		WithStateProvider(&mockStateProvider{}),
+		WithPersistenceFlag(true),
+		WithWriterStats(ws))
+
+	got := stripAnsi(m.renderWriterSection(now))
+	for _, want := range []string{"Write Queue", "9/10 (90%)", "No flushes yet"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("section %q should contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "above 80%") || m.healthSummary(now, false) != "" {
+		t.Error("the queue is not backlogged within a minute")
+	}
+
+	ws.ws.HighSince = now.Add(-2 * time.Minute)
+	ws.ws.Flushes, ws.ws.LastBatch, ws.ws.AvgBatch, ws.ws.MaxBatch = 4, 100, 75, 100
+	ws.ws.LastFlush, ws.ws.AvgFlush, ws.ws.MaxFlush = 2*time.Millisecond, 1500*time.Microsecond, 3*time.Millisecond
+	got = stripAnsi(m.renderWriterSection(now))
+	for _, want := range []string{"above 80% for 2m", "100 last, 75.0 avg, 100 max", "2.0ms last, 1.5ms avg, 3.0ms max"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("section %q should contain %q", got, want)
+		}
+	}
+	if h := stripAnsi(m.healthSummary(now, false)); !strings.HasPrefix(h, "db:write queue backlogged") {
+		t.Errorf("health summary = %q", h)
+	}
+
+	ws.ws.Role = state.StoreRoleStandby
+	if got := stripAnsi(m.renderWriterSection(now)); !strings.Contains(got, "another cc-top instance maintains this database") {
+		t.Errorf("standby section %q should say who maintains the database", got)
+	}
+}
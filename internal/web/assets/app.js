@@ -0,0 +1,155 @@
+// cc-top web dashboard: polls the JSON API and renders it. Read-only.
+"use strict";
+
+const SUMMARY_INTERVAL = 2000;
+const HISTORY_INTERVAL = 60000;
+// The token the page was opened with (/?token=...), for `cc-top web --token`.
+// It is moved to sessionStorage and dropped from the address bar, so it
+// stays out of the browser history and of copied links.
+const TOKEN = (() => {
+  const params = new URLSearchParams(location.search);
+  const token = params.get("token");
+  if (token === null) return sessionStorage.getItem("cc-top-token");
+  sessionStorage.setItem("cc-top-token", token);
+  params.delete("token");
+  const query = params.toString();
+  history.replaceState(null, "", location.pathname + (query ? "?" + query : "") + location.hash);
+  return token;
+})();
+
+const $ = (id) => document.getElementById(id);
+const usd = (v) => "$" + v.toFixed(2);
+const trendArrow = { up: "↑ rising", down: "↓ falling", flat: "→ steady" };
+
+function el(tag, text, className) {
+  const e = document.createElement(tag);
+  if (text !== undefined) e.textContent = text;
+  if (className) e.className = className;
+  return e;
+}
+
+function ago(iso) {
+  const t = Date.parse(iso);
+  if (!t || t < 0) return "–";
+  const s = Math.max(0, Math.round((Date.now() - t) / 1000));
+  if (s < 60) return s + "s ago";
+  if (s < 3600) return Math.floor(s / 60) + "m ago";
+  if (s < 86400) return Math.floor(s / 3600) + "h ago";
+  return Math.floor(s / 86400) + "d ago";
+}
+
+function project(cwd) {
+  if (!cwd) return "–";
+  const parts = cwd.split(/[\\/]/).filter(Boolean);
+  return parts.length ? parts[parts.length - 1] : cwd;
+}
+
+// barChart draws values as an SVG bar chart with a label under every
+// labelEvery-th bar; highlight marks one bar.
+function barChart(container, values, labels, labelEvery, highlight) {
+  const ns = "http://www.w3.org/2000/svg";
+  const w = 600, h = 140, pad = 16;
+  const svg = document.createElementNS(ns, "svg");
+  svg.setAttribute("viewBox", `0 0 ${w} ${h}`);
+  svg.setAttribute("preserveAspectRatio", "none");
+  const top = Math.max(...values, 0.01);
+  const bw = w / Math.max(values.length, 1);
+  values.forEach((v, i) => {
+    const bh = (v / top) * (h - pad - 14);
+    const r = document.createElementNS(ns, "rect");
+    r.setAttribute("x", i * bw + 1);
+    r.setAttribute("y", h - pad - bh);
+    r.setAttribute("width", Math.max(bw - 2, 1));
+    r.setAttribute("height", bh);
+    if (i === highlight) r.setAttribute("class", "now");
+    const title = document.createElementNS(ns, "title");
+    title.textContent = `${labels[i]}: ${usd(v)}`;
+    r.appendChild(title);
+    svg.appendChild(r);
+    if (i % labelEvery === 0) {
+      const t = document.createElementNS(ns, "text");
+      t.setAttribute("x", i * bw + 2);
+      t.setAttribute("y", h - 3);
+      t.textContent = labels[i];
+      svg.appendChild(t);
+    }
+  });
+  const max = document.createElementNS(ns, "text");
+  max.setAttribute("x", 4);
+  max.setAttribute("y", 11);
+  max.textContent = "max " + usd(top);
+  svg.appendChild(max);
+  container.replaceChildren(svg);
+}
+
+function renderSummary(s) {
+  $("total-cost").textContent = usd(s.total_cost);
+  $("hourly-rate").textContent = usd(s.hourly_rate) + "/hr";
+  $("trend").textContent = trendArrow[s.trend] || "";
+  $("daily-projection").textContent = usd(s.daily_projection) + "/day";
+  $("monthly-projection").textContent = usd(s.monthly_projection) + "/month";
+  $("sessions-count").textContent = s.sessions;
+  $("active-count").textContent = s.active_sessions + " active";
+  $("updated").textContent = "updated " + new Date(s.updated_at).toLocaleTimeString();
+
+  const list = $("alerts");
+  list.replaceChildren(...s.alerts.map((a) => el("li", `${a.rule}: ${a.message}`, a.severity)));
+  $("alerts-section").hidden = s.alerts.length === 0;
+
+  const hours = [...Array(24).keys()].map((h) => String(h).padStart(2, "0"));
+  barChart($("today-chart"), s.today_by_hour, hours, 3, new Date().getHours());
+}
+
+function renderSessions(sessions) {
+  $("sessions").replaceChildren(...sessions.map((s) => {
+    const tr = el("tr");
+    tr.append(
+      el("td", s.session_id.slice(0, 8)),
+      el("td", project(s.cwd) + (s.host ? " @" + s.host : "")),
+      el("td", s.model || "–"),
+      el("td", s.status, "status-" + s.status),
+      el("td", usd(s.total_cost), "num"),
+      el("td", s.total_tokens.toLocaleString(), "num"),
+      el("td", ago(s.last_event_at)),
+    );
+    return tr;
+  }));
+}
+
+function renderHistory(h) {
+  const empty = h.days.length === 0;
+  $("history-empty").hidden = !empty;
+  $("history-chart").hidden = empty;
+  if (empty) return;
+  barChart($("history-chart"), h.days.map((d) => d.total_cost), h.days.map((d) => d.date.slice(5)), 5, -1);
+}
+
+async function fetchJSON(path) {
+  const headers = TOKEN ? { Authorization: "Bearer " + TOKEN } : {};
+  const resp = await fetch(path, { cache: "no-store", headers });
+  if (!resp.ok) throw new Error(`${path}: ${resp.status}`);
+  return resp.json();
+}
+
+async function poll() {
+  try {
+    const [summary, sessions] = await Promise.all([fetchJSON("api/summary"), fetchJSON("api/sessions")]);
+    renderSummary(summary);
+    renderSessions(sessions);
+  } catch (err) {
+    $("updated").textContent = "disconnected (" + err.message + ")";
+  }
+}
+
+async function pollHistory() {
+  try {
+    renderHistory(await fetchJSON("api/history?days=30"));
+  } catch (err) {
+    // The summary poll reports connection problems.
+  }
+}
+
+poll();
+pollHistory();
+setInterval(poll, SUMMARY_INTERVAL);
+setInterval(pollHistory, HISTORY_INTERVAL);
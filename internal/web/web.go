@@ -0,0 +1,316 @@
+// Package web serves a read-only dashboard for browsers on the LAN: the
+// sessions, burn rate and cost history cc-top collects, as a small page
+// from embedded assets and a JSON API.
+package web
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/receiver"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+//go:embed assets
+var assets embed.FS
+
+const (
+	// defaultHistoryDays is how many days /api/history returns without a
+	// days parameter; maxHistoryDays caps the parameter.
+	defaultHistoryDays = 30
+	maxHistoryDays     = 365
+)
+
+// StateProvider, BurnRateProvider, AlertProvider and HistoryProvider are
+// the subsets of the TUI's providers the dashboard reads, so the same
+// adapters back both.
+type StateProvider interface {
+	Snapshot() *state.Snapshot
+	QueryDailySummaries(days int) []state.DailySummary
+}
+
+type BurnRateProvider interface {
+	GetGlobal() burnrate.BurnRate
+}
+
+type AlertProvider interface {
+	Active() []alerts.Alert
+}
+
+type HistoryProvider interface {
+	QueryBurnRateHourly(days int) map[string][24]float64 // date -> average $/hr per UTC hour
+}
+
+// Handler serves the dashboard page and its API.
+type Handler struct {
+	state    StateProvider
+	burnRate BurnRateProvider
+	alerts   AlertProvider
+	history  HistoryProvider
+	token    string
+	mux      *http.ServeMux
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithBurnRate adds the global burn rate to /api/summary.
+func WithBurnRate(p BurnRateProvider) Option {
+	return func(h *Handler) { h.burnRate = p }
+}
+
+// WithAlerts adds the active alerts to /api/summary.
+func WithAlerts(p AlertProvider) Option {
+	return func(h *Handler) { h.alerts = p }
+}
+
+// WithHistory adds hourly burn rate averages to /api/history. It needs
+// persistence.
+func WithHistory(p HistoryProvider) Option {
+	return func(h *Handler) { h.history = p }
+}
+
+// WithToken makes the API require Authorization: Bearer <token>. The page
+// and its assets hold no data and stay public; the page sends the token it
+// was opened with (/?token=...) on its API requests.
+func WithToken(token string) Option {
+	return func(h *Handler) { h.token = token }
+}
+
+// New creates a Handler reading sessions and daily summaries from sp.
+func New(sp StateProvider, opts ...Option) *Handler {
+	h := &Handler{state: sp, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	static, _ := fs.Sub(assets, "assets")
+	h.mux.Handle("/", http.FileServerFS(static))
+	h.mux.HandleFunc("/api/summary", h.serveSummary)
+	h.mux.HandleFunc("/api/sessions", h.serveSessions)
+	h.mux.HandleFunc("/api/history", h.serveHistory)
+	return h
+}
+
+// ServeHTTP serves GET and HEAD requests; the dashboard is read-only. With
+// WithToken, API requests without the token get 401.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if strings.HasPrefix(req.URL.Path, "/api/") && !receiver.Authorized(req.Header.Get("Authorization"), h.token) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="cc-top"`)
+		http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+		return
+	}
+	h.mux.ServeHTTP(w, req)
+}
+
+// summary is the reply of /api/summary.
+type summary struct {
+	TotalCost         float64      `json:"total_cost"`
+	HourlyRate        float64      `json:"hourly_rate"`
+	Trend             string       `json:"trend"`
+	TokenVelocity     float64      `json:"token_velocity"`
+	DailyProjection   float64      `json:"daily_projection"`
+	MonthlyProjection float64      `json:"monthly_projection"`
+	TodayByHour       [24]float64  `json:"today_by_hour"`
+	PerModel          []modelRate  `json:"per_model"`
+	Sessions          int          `json:"sessions"`
+	ActiveSessions    int          `json:"active_sessions"`
+	Alerts            []alertEntry `json:"alerts"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+type modelRate struct {
+	Model      string  `json:"model"`
+	TotalCost  float64 `json:"total_cost"`
+	HourlyRate float64 `json:"hourly_rate"`
+}
+
+type alertEntry struct {
+	Rule      string    `json:"rule"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+	SessionID string    `json:"session_id,omitempty"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// session is one entry of /api/sessions.
+type session struct {
+	SessionID   string    `json:"session_id"`
+	Model       string    `json:"model,omitempty"`
+	CWD         string    `json:"cwd,omitempty"`
+	Host        string    `json:"host,omitempty"`
+	User        string    `json:"user,omitempty"`
+	Status      string    `json:"status"`
+	TotalCost   float64   `json:"total_cost"`
+	TotalTokens int64     `json:"total_tokens"`
+	StartedAt   time.Time `json:"started_at"`
+	LastEventAt time.Time `json:"last_event_at"`
+}
+
+// history is the reply of /api/history.
+type history struct {
+	Days   []day                  `json:"days"`
+	Hourly map[string][24]float64 `json:"hourly,omitempty"`
+}
+
+type day struct {
+	Date         string  `json:"date"`
+	TotalCost    float64 `json:"total_cost"`
+	TotalTokens  int64   `json:"total_tokens"`
+	SessionCount int     `json:"session_count"`
+}
+
+func (h *Handler) serveSummary(w http.ResponseWriter, _ *http.Request) {
+	snap := h.state.Snapshot()
+	out := summary{
+		TotalCost: snap.TotalCost,
+		Sessions:  len(snap.Sessions),
+		PerModel:  []modelRate{},
+		Alerts:    []alertEntry{},
+		UpdatedAt: time.Now(),
+	}
+	for i := range snap.Sessions {
+		if snap.Sessions[i].Status() == state.StatusActive {
+			out.ActiveSessions++
+		}
+	}
+	if h.burnRate != nil {
+		br := h.burnRate.GetGlobal()
+		out.HourlyRate = br.HourlyRate
+		out.Trend = br.Trend.String()
+		out.TokenVelocity = br.TokenVelocity
+		out.DailyProjection = br.DailyProjection
+		out.MonthlyProjection = br.MonthlyProjection
+		out.TodayByHour = br.TodayByHour
+		for _, m := range br.PerModel {
+			out.PerModel = append(out.PerModel, modelRate{Model: m.Model, TotalCost: m.TotalCost, HourlyRate: m.HourlyRate})
+		}
+	}
+	if h.alerts != nil {
+		for _, a := range h.alerts.Active() {
+			out.Alerts = append(out.Alerts, alertEntry{Rule: a.Rule, Severity: a.Severity, Message: a.Message, SessionID: a.SessionID, FiredAt: a.FiredAt})
+		}
+	}
+	writeJSON(w, out)
+}
+
+func (h *Handler) serveSessions(w http.ResponseWriter, _ *http.Request) {
+	snap := h.state.Snapshot()
+	out := make([]session, len(snap.Sessions))
+	for i := range snap.Sessions {
+		s := &snap.Sessions[i]
+		out[i] = session{
+			SessionID:   s.SessionID,
+			Model:       s.Model,
+			CWD:         s.CWD,
+			Host:        s.Metadata.HostName,
+			User:        s.Metadata.User,
+			Status:      string(s.Status()),
+			TotalCost:   s.TotalCost,
+			TotalTokens: s.TotalTokens,
+			StartedAt:   s.StartedAt,
+			LastEventAt: s.LastEventAt,
+		}
+	}
+	slices.SortFunc(out, func(a, b session) int {
+		return cmp.Or(b.LastEventAt.Compare(a.LastEventAt), cmp.Compare(a.SessionID, b.SessionID))
+	})
+	writeJSON(w, out)
+}
+
+func (h *Handler) serveHistory(w http.ResponseWriter, req *http.Request) {
+	days := defaultHistoryDays
+	if v := req.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryDays {
+			http.Error(w, fmt.Sprintf("days must be between 1 and %d", maxHistoryDays), http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	out := history{Days: []day{}}
+	for _, d := range h.state.QueryDailySummaries(days) {
+		out.Days = append(out.Days, day{Date: d.Date, TotalCost: d.TotalCost, TotalTokens: d.TotalTokens, SessionCount: d.SessionCount})
+	}
+	slices.SortFunc(out.Days, func(a, b day) int { return cmp.Compare(a.Date, b.Date) })
+	if h.history != nil {
+		out.Hourly = h.history.QueryBurnRateHourly(days)
+	}
+	writeJSON(w, out)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("web dashboard: writing reply: %v", err)
+	}
+}
+
+// Server serves a Handler on an address.
+type Server struct {
+	addr    string
+	handler http.Handler
+	server  *http.Server
+}
+
+// NewServer creates a server that will listen on addr (host:port).
+func NewServer(addr string, h *Handler) *Server {
+	return &Server{addr: addr, handler: h}
+}
+
+// Start listens on the configured address and serves the dashboard in the
+// background. It returns an error if the address is unavailable.
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("web dashboard %s: %w", s.addr, err)
+	}
+	s.server = &http.Server{
+		Handler:      s.handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	log.Printf("web dashboard listening on http://%s/", s.addr)
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Printf("web dashboard stopped: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		s.Stop()
+	}()
+	return nil
+}
+
+// Stop shuts the server down, waiting up to 5 seconds for in-flight requests.
+func (s *Server) Stop() {
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.server.Shutdown(ctx); err != nil {
+			log.Printf("web dashboard forced shutdown: %v", err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/cc-top/internal/alerts"
+	"github.com/nixlim/cc-top/internal/burnrate"
+	"github.com/nixlim/cc-top/internal/state"
+)
+
+// fakeState serves a MemoryStore plus canned daily summaries.
+type fakeState struct {
+	*state.MemoryStore
+	days []state.DailySummary
+}
+
+func (f fakeState) QueryDailySummaries(days int) []state.DailySummary { return f.days }
+
+type fakeBurnRate burnrate.BurnRate
+
+func (f fakeBurnRate) GetGlobal() burnrate.BurnRate { return burnrate.BurnRate(f) }
+
+type fakeAlerts []alerts.Alert
+
+func (f fakeAlerts) Active() []alerts.Alert { return f }
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	store := state.NewMemoryStore()
+	now := time.Now()
+	store.AddMetric("older", state.Metric{Name: "claude_code.cost.usage", Value: 1.5, Timestamp: now.Add(-time.Hour)})
+	store.AddMetric("newer", state.Metric{Name: "claude_code.cost.usage", Value: 0.25, Timestamp: now})
+
+	h := New(fakeState{MemoryStore: store, days: []state.DailySummary{
+		{Date: "2026-03-02", TotalCost: 4},
+		{Date: "2026-03-01", TotalCost: 2},
+	}},
+		WithBurnRate(fakeBurnRate{HourlyRate: 3, Trend: burnrate.TrendUp, DailyProjection: 72}),
+		WithAlerts(fakeAlerts{{Rule: alerts.RuleCostSurge, Severity: alerts.SeverityWarning, Message: "surge"}}),
+	)
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string, into any) *http.Response {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if into != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+			t.Fatalf("decoding %s: %v", url, err)
+		}
+	}
+	return resp
+}
+
+func TestHandler_ServesPage(t *testing.T) {
+	srv := newTestServer(t)
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || len(body) == 0 {
+			t.Errorf("GET %s = %d with %d bytes, want the embedded asset", path, resp.StatusCode, len(body))
+		}
+	}
+}
+
+func TestHandler_Summary(t *testing.T) {
+	srv := newTestServer(t)
+	var s summary
+	get(t, srv.URL+"/api/summary", &s)
+	if s.TotalCost != 1.75 || s.Sessions != 2 || s.ActiveSessions != 1 {
+		t.Errorf("summary = %+v, want $1.75 over 2 sessions, 1 active", s)
+	}
+	if s.HourlyRate != 3 || s.Trend != "up" || s.DailyProjection != 72 {
+		t.Errorf("burn rate not reported: %+v", s)
+	}
+	if len(s.Alerts) != 1 || s.Alerts[0].Message != "surge" {
+		t.Errorf("alerts = %+v, want the active alert", s.Alerts)
+	}
+}
+
+func TestHandler_SessionsNewestFirst(t *testing.T) {
+	srv := newTestServer(t)
+	var sessions []session
+	get(t, srv.URL+"/api/sessions", &sessions)
+	if len(sessions) != 2 || sessions[0].SessionID != "newer" || sessions[0].Status != "active" {
+		t.Errorf("sessions = %+v, want newer (active) first", sessions)
+	}
+}
+
+func TestHandler_History(t *testing.T) {
+	srv := newTestServer(t)
+	var h history
+	get(t, srv.URL+"/api/history?days=7", &h)
+	if len(h.Days) != 2 || h.Days[0].Date != "2026-03-01" {
+		t.Errorf("days = %+v, want oldest first", h.Days)
+	}
+	if resp := get(t, srv.URL+"/api/history?days=0", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("days=0 status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestHandler_ReadOnly(t *testing.T) {
+	srv := newTestServer(t)
+	resp, err := http.Post(srv.URL+"/api/summary", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestHandler_Token(t *testing.T) {
+	srv := httptest.NewServer(New(fakeState{MemoryStore: state.NewMemoryStore()}, WithToken("s3cret")))
+	t.Cleanup(srv.Close)
+
+	if resp := get(t, srv.URL+"/", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("page status = %d, want 200 without a token", resp.StatusCode)
+	}
+	if resp := get(t, srv.URL+"/api/summary", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("API status = %d, want 401 without a token", resp.StatusCode)
+	}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/summary", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("API status = %d, want 200 with the token", resp.StatusCode)
+	}
+}